@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/audit"
+	"github.com/abakermi/r53check/internal/aws"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+// failOnRisk is --fail-on-risk: exit with code 6 when a domain is at risk
+var failOnRisk bool
+
+// newAuditSource creates what audit-dns reads the account from; tests
+// replace it
+var newAuditSource = func(ctx context.Context) (audit.Source, error) {
+	awsConfig, err := loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return aws.NewZones(awsConfig), nil
+}
+
+// auditStatuses are the audit statuses in the order they are summarized,
+// with the symbol and summary label of each
+var auditStatuses = []struct {
+	status audit.Status
+	symbol string
+	label  string
+}{
+	{audit.StatusDanglingZone, "✗", "dangling"},
+	{audit.StatusThirdParty, "⚠", "third-party"},
+	{audit.StatusNoNameServers, "⚠", "not delegated"},
+	{audit.StatusError, "?", "unreadable"},
+	{audit.StatusOK, "✓", "OK"},
+}
+
+// auditDNSCmd represents the audit-dns command
+var auditDNSCmd = &cobra.Command{
+	Use:   "audit-dns",
+	Short: "Find registered domains delegated to deleted hosted zones or third-party name servers",
+	Long: `Audit the delegation of every domain registered to the account with Route 53
+Domains against the account's public hosted zones.
+
+A domain delegated to Route 53 name servers that serve none of the account's
+hosted zones for it is reported DANGLING_ZONE. This happens when a hosted zone
+is deleted, or recreated on other name servers, without updating the domain,
+and is a takeover risk: anyone who creates a hosted zone for the domain on
+those name servers answers for it. Domains delegated to other DNS providers are
+reported THIRD_PARTY_NS, since the provider must still host their zone, and
+domains without name servers NO_NAMESERVERS.
+
+Nothing is changed. --fail-on-risk exits with code 6 when a domain has a
+dangling zone, for scheduled audits.`,
+	Example: `  # Audit the account's domains
+  r53check audit-dns
+
+  # Fail a scheduled job when a domain has a dangling zone
+  r53check audit-dns --fail-on-risk --output json`,
+	Args: cobra.NoArgs,
+	RunE: runAuditDNSCommand,
+}
+
+func init() {
+	auditDNSCmd.Flags().BoolVar(&failOnRisk, "fail-on-risk", false, "Exit with code 6 when a domain has a dangling zone")
+
+	rootCmd.AddCommand(auditDNSCmd)
+}
+
+func runAuditDNSCommand(cmd *cobra.Command, args []string) error {
+	if mockMode {
+		return customErrors.NewValidationError("", "mock", "audit-dns reads the domains and hosted zones of an AWS account, which --mock has none of", nil)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	source, err := newAuditSource(ctx)
+	if err != nil {
+		return err
+	}
+	findings, err := audit.Audit(ctx, source)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[audit.Status]int)
+	atRisk := 0
+	for _, finding := range findings {
+		counts[finding.Status]++
+		if finding.Status.AtRisk() {
+			atRisk++
+		}
+	}
+
+	if outputFormat == output.FormatJSON {
+		data, _ := json.MarshalIndent(map[string]interface{}{"findings": findings, "summary": counts}, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+	} else {
+		fmt.Fprint(stdout, formatAudit(findings, counts))
+	}
+
+	if failOnRisk && atRisk > 0 {
+		if outputFormat != output.FormatJSON {
+			fmt.Fprintf(stderr, "Audit failed: %s with a dangling zone\n", pluralize(atRisk, "domain"))
+		}
+		return customErrors.NewExitError(customErrors.ExitPartialFailure, nil)
+	}
+	return nil
+}
+
+// formatAudit renders findings, one domain per line with its explanation,
+// followed by the count of each status
+func formatAudit(findings []audit.Finding, counts map[audit.Status]int) string {
+	if len(findings) == 0 {
+		return "No domains are registered to this account\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "DNS delegation audit (%s):\n", pluralize(len(findings), "domain"))
+	for _, finding := range findings {
+		symbol := "?"
+		for _, status := range auditStatuses {
+			if status.status == finding.Status {
+				symbol = status.symbol
+			}
+		}
+		fmt.Fprintf(&b, "  %s %s: %s\n", symbol, finding.Domain, finding.Status)
+		fmt.Fprintf(&b, "    %s\n", finding.Message)
+	}
+
+	var summary []string
+	for _, status := range auditStatuses {
+		if counts[status.status] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[status.status], status.label))
+		}
+	}
+	fmt.Fprintf(&b, "Summary: %s\n", strings.Join(summary, ", "))
+	return b.String()
+}
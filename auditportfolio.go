@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abakermi/r53check/internal/audit"
+	"github.com/abakermi/r53check/internal/aws"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// expiringWithin is --expiring-within: how many days before expiring a
+	// domain is flagged
+	expiringWithin int
+
+	// skipChecks is --skip: the portfolio checks left out
+	skipChecks []string
+)
+
+// newPortfolioSource creates what audit reads the account from; tests
+// replace it
+var newPortfolioSource = func(ctx context.Context) (audit.PortfolioSource, error) {
+	awsConfig, err := loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return aws.NewZones(awsConfig), nil
+}
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Flag risky settings across the domains registered to the account",
+	Long: `Audit every domain registered to the account with Route 53 Domains and flag
+the states that put it at risk:
+
+  expiry          expiring within --expiring-within days, 60 by default
+  auto_renew      auto-renewal is off
+  transfer_lock   the transfer lock is off
+  delegation      delegated to a dangling hosted zone, or to no name servers,
+                  as audit-dns reports
+  dnssec          no DNSSEC keys at the registry
+  contact_email   a contact email that is invalid, no-reply, at a reserved
+                  domain, or at the domain itself
+
+Domains are listed soonest to expire first. --skip leaves checks out, such as
+dnssec for a portfolio that does not use it. Nothing is changed. The run exits
+with code 6 when any domain is flagged, or its detail cannot be read, so it
+can gate scheduled jobs.`,
+	Example: `  # Audit the account's domains
+  r53check audit
+
+  # Flag domains expiring within 90 days, without the DNSSEC check
+  r53check audit --expiring-within 90 --skip dnssec --output json`,
+	Args: cobra.NoArgs,
+	RunE: runAuditCommand,
+}
+
+func init() {
+	auditCmd.Flags().IntVar(&expiringWithin, "expiring-within", 60, "Flag domains expiring within this many days")
+	auditCmd.Flags().StringSliceVar(&skipChecks, "skip", nil, "Checks to leave out: expiry, auto_renew, transfer_lock, delegation, dnssec, contact_email")
+
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAuditCommand(cmd *cobra.Command, args []string) error {
+	if mockMode {
+		return customErrors.NewValidationError("", "mock", "audit reads the domains registered to an AWS account, which --mock has none of", nil)
+	}
+	if expiringWithin < 0 {
+		return customErrors.NewValidationError("", "expiring-within", "--expiring-within cannot be negative", nil)
+	}
+	options := audit.PortfolioOptions{ExpiryWindow: time.Duration(expiringWithin) * 24 * time.Hour, Now: time.Now()}
+	for _, name := range skipChecks {
+		check := audit.PortfolioCheck(strings.ToLower(strings.TrimSpace(name)))
+		if !slices.Contains(audit.PortfolioChecks, check) {
+			return customErrors.NewValidationError("", "skip",
+				fmt.Sprintf("unknown check %q (checks: %v)", name, audit.PortfolioChecks), nil)
+		}
+		options.Skip = append(options.Skip, check)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	source, err := newPortfolioSource(ctx)
+	if err != nil {
+		return err
+	}
+	audits, err := audit.AuditPortfolio(ctx, source, options)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[audit.PortfolioCheck]int)
+	flagged := 0
+	for _, domainAudit := range audits {
+		for _, issue := range domainAudit.Issues {
+			counts[issue.Check]++
+		}
+		if !domainAudit.OK() {
+			flagged++
+		}
+	}
+
+	if outputFormat == output.FormatJSON {
+		data, _ := json.MarshalIndent(map[string]interface{}{"domains": audits, "summary": counts, "flagged": flagged}, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+	} else {
+		fmt.Fprint(stdout, formatPortfolioAudit(audits, counts, flagged))
+	}
+
+	if flagged > 0 {
+		if outputFormat != output.FormatJSON {
+			fmt.Fprintf(stderr, "Audit failed: %s flagged\n", pluralize(flagged, "domain"))
+		}
+		return customErrors.NewExitError(customErrors.ExitPartialFailure, nil)
+	}
+	return nil
+}
+
+// formatPortfolioAudit renders audits, one domain per line with its expiry
+// and an indented line per issue, followed by the count of each check
+func formatPortfolioAudit(audits []audit.DomainAudit, counts map[audit.PortfolioCheck]int, flagged int) string {
+	if len(audits) == 0 {
+		return "No domains are registered to this account\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Portfolio audit (%s):\n", pluralize(len(audits), "domain"))
+	for _, domainAudit := range audits {
+		expiry := "no expiry"
+		if !domainAudit.Expiry.IsZero() {
+			expiry = "expires " + domainAudit.Expiry.Format("2006-01-02")
+		}
+		switch {
+		case domainAudit.Error != "":
+			fmt.Fprintf(&b, "  ? %s (%s): %s\n", domainAudit.Domain, expiry, domainAudit.Error)
+		case domainAudit.OK():
+			fmt.Fprintf(&b, "  ✓ %s (%s)\n", domainAudit.Domain, expiry)
+		default:
+			fmt.Fprintf(&b, "  ✗ %s (%s)\n", domainAudit.Domain, expiry)
+		}
+		for _, issue := range domainAudit.Issues {
+			fmt.Fprintf(&b, "    %s: %s\n", issue.Check, issue.Message)
+		}
+	}
+
+	var summary []string
+	for _, check := range audit.PortfolioChecks {
+		if counts[check] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[check], check))
+		}
+	}
+	if len(summary) == 0 {
+		summary = append(summary, "no issues")
+	}
+	fmt.Fprintf(&b, "Summary: %d of %d domains flagged; %s\n", flagged, len(audits), strings.Join(summary, ", "))
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/abakermi/r53check/internal/budget"
+	"github.com/abakermi/r53check/internal/config"
+	"github.com/abakermi/r53check/internal/output"
+	"github.com/abakermi/r53check/internal/storage"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// budgetSettings are the spending limits, from the config file
+	budgetSettings config.BudgetSettings
+
+	// overrideBudget is --override-budget, which lets a purchase go over
+	// the budget
+	overrideBudget bool
+)
+
+// budgetCmd shows the spending limits and this month's purchases
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Show the spending budget and what has been spent this month",
+	Long: `Show the spending budget and what has been spent of it this month.
+
+The budget section of the config file limits what commands that purchase
+domains, such as registering or renewing them, may spend: per_run caps a single
+run and per_month every run in a calendar month together, in USD like Route 53
+prices. A purchase that would go over either limit is refused unless the
+command is given --override-budget. Purchases are recorded in
+$XDG_DATA_HOME/r53check/purchases.jsonl, which the monthly limit is counted
+from.`,
+	Example: `  # Show the budget
+  r53check budget
+
+  # Limit spending in ~/.config/r53check/config.yaml
+  budget:
+    per_run: 100
+    per_month: 500`,
+	Args: cobra.NoArgs,
+	RunE: runBudgetCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(budgetCmd)
+}
+
+// addOverrideBudgetFlag registers --override-budget on a command that
+// purchases domains
+func addOverrideBudgetFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&overrideBudget, "override-budget", false, "Purchase even when it goes over the budget in the config file")
+}
+
+// purchaseLedger opens the purchase ledger in the data directory; tests
+// replace it
+var purchaseLedger = func() *budget.Ledger {
+	return budget.NewLedger(storage.DefaultDirs().Purchases())
+}
+
+// newBudgetGuard creates the guard enforcing the configured budget, with
+// the purchase ledger
+func newBudgetGuard() (*budget.Guard, error) {
+	return budget.NewGuard(budgetSettings, purchaseLedger())
+}
+
+// checkBudget refuses purchases that would go over the budget, unless
+// --override-budget is given. Commands that purchase domains call it before
+// buying anything, then record what they bought with the guard it returns.
+func checkBudget(purchases []budget.Purchase) (*budget.Guard, error) {
+	guard, err := newBudgetGuard()
+	if err != nil {
+		return nil, err
+	}
+	if err := guard.Check(purchases, false); err != nil {
+		if !overrideBudget {
+			return nil, err
+		}
+		fmt.Fprintln(stderr, "Warning: going over the budget (--override-budget)")
+	}
+	return guard, nil
+}
+
+func runBudgetCommand(cmd *cobra.Command, args []string) error {
+	guard, err := newBudgetGuard()
+	if err != nil {
+		return err
+	}
+	status, err := guard.Status()
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == output.FormatJSON {
+		data, _ := json.MarshalIndent(status, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	if !guard.Enabled() {
+		fmt.Fprintln(stdout, "No budget is set; add budget.per_run or budget.per_month to the config file")
+	} else {
+		fmt.Fprintln(stdout, "Budget (USD):")
+		if status.PerRun > 0 {
+			fmt.Fprintf(stdout, "  Per run:   $%.2f\n", status.PerRun)
+		}
+		if status.PerMonth > 0 {
+			fmt.Fprintf(stdout, "  Per month: $%.2f, $%.2f left\n", status.PerMonth, *status.Remaining)
+		}
+	}
+	fmt.Fprintf(stdout, "Spent in %s: $%.2f\n", status.Month, status.Spent)
+	return nil
+}
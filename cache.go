@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/output"
+	"github.com/abakermi/r53check/internal/storage"
+
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd groups the commands that manage r53check's on-disk storage
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear cached data",
+	Long: `Inspect or clear the files r53check keeps on disk.
+
+Caches (the TLD catalog, price list and cached results) live under $XDG_CACHE_HOME/r53check
+and can be deleted at any time. Data (check history, bulk checkpoints and the purchase ledger) lives
+under $XDG_DATA_HOME/r53check and is never removed by "cache clear".`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show where r53check stores files and how much space they use",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheStatsCommand,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete all cached files",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheClearCommand,
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// storageEntry describes one item of the storage layout for cache stats
+type storageEntry struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	Path  string `json:"path"`
+	Files int    `json:"files"`
+	Bytes int64  `json:"bytes"`
+}
+
+func runCacheStatsCommand(cmd *cobra.Command, args []string) error {
+	dirs := storage.DefaultDirs()
+	entries := []storageEntry{
+		{Name: "TLD catalog", Kind: "cache", Path: dirs.TLDCatalog()},
+		{Name: "Price list", Kind: "cache", Path: dirs.PriceCatalog()},
+		{Name: "Result cache", Kind: "cache", Path: dirs.ResultCache()},
+		{Name: "Checkpoints", Kind: "data", Path: dirs.Checkpoints()},
+		{Name: "History database", Kind: "data", Path: dirs.HistoryDB()},
+		{Name: "Purchase ledger", Kind: "data", Path: dirs.Purchases()},
+	}
+
+	for i := range entries {
+		files, size, err := storage.Usage(entries[i].Path)
+		if err != nil {
+			return customErrors.NewSystemError("cache", "failed to read "+entries[i].Path, err)
+		}
+		entries[i].Files = files
+		entries[i].Bytes = size
+	}
+
+	if outputFormat == output.FormatJSON {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"config_dir": dirs.Config,
+			"cache_dir":  dirs.Cache,
+			"data_dir":   dirs.Data,
+			"entries":    entries,
+		}, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(stdout, "Config: %s\n", dirs.Config)
+	fmt.Fprintf(stdout, "Cache:  %s\n", dirs.Cache)
+	fmt.Fprintf(stdout, "Data:   %s\n\n", dirs.Data)
+	for _, entry := range entries {
+		usage := "empty"
+		if entry.Files > 0 {
+			usage = fmt.Sprintf("%s, %s", pluralize(entry.Files, "file"), formatBytes(entry.Bytes))
+		}
+		fmt.Fprintf(stdout, "  %-13s %-6s %s (%s)\n", entry.Name, entry.Kind, entry.Path, usage)
+	}
+	return nil
+}
+
+func runCacheClearCommand(cmd *cobra.Command, args []string) error {
+	dirs := storage.DefaultDirs()
+
+	files, size, _ := storage.Usage(dirs.Cache)
+	if err := dirs.ClearCache(); err != nil {
+		return customErrors.NewSystemError("cache", "failed to clear "+dirs.Cache, err)
+	}
+
+	fmt.Fprintf(stdout, "Cleared %s (%s) from %s\n", pluralize(files, "cached file"), formatBytes(size), dirs.Cache)
+	return nil
+}
+
+// formatBytes renders a size in bytes with a binary unit
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// pluralize renders a count with a singular or plural noun
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
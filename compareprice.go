@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/output"
+	"github.com/abakermi/r53check/internal/pricing"
+
+	"github.com/spf13/cobra"
+)
+
+// priceProviderChoices are the --provider values
+var priceProviderChoices = []string{"route53", "porkbun"}
+
+var (
+	// Compare-price command flags
+	priceProviders []string
+	priceTables    []string
+)
+
+// newPriceProvider creates the provider of a --provider value other than
+// route53; tests replace it
+var newPriceProvider = func(name string) pricing.Provider {
+	return pricing.NewPorkbun(nil)
+}
+
+// comparePriceCmd represents the compare-price command
+var comparePriceCmd = &cobra.Command{
+	Use:   "compare-price <domain>",
+	Short: "Compare what registrars charge for a domain's TLD",
+	Long: `Compare the registration, renewal and transfer prices of a domain's TLD at
+Route 53 and other registrars, cheapest registration first, to see where it is
+cheapest before registering it through AWS.
+
+--provider picks the registrars with a price API: route53, the default, which
+lists the prices of Route 53 Domains, and porkbun, which reads Porkbun's public
+price list. --prices adds registrars from a CSV price table with the columns
+registrar, tld, registration, renewal, transfer and currency; registrar, tld
+and registration are required, and prices without a currency are in USD.
+
+Prices in different currencies are not converted, so the cheapest registrar is
+shown per currency. Availability is not checked.`,
+	Example: `  # Compare Route 53 with Porkbun for .io
+  r53check compare-price example.io --provider route53,porkbun
+
+  # Add the prices of other registrars from a table
+  r53check compare-price example.com --prices registrars.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: runComparePriceCommand,
+}
+
+func init() {
+	comparePriceCmd.Flags().StringSliceVar(&priceProviders, "provider", nil, "Registrars to ask for prices: route53, porkbun (default route53)")
+	comparePriceCmd.Flags().StringArrayVar(&priceTables, "prices", nil, "CSV price table of other registrars (repeatable)")
+
+	rootCmd.AddCommand(comparePriceCmd)
+}
+
+func runComparePriceCommand(cmd *cobra.Command, args []string) error {
+	name := normalizeDomains(args)[0]
+	tld := domain.EffectiveTLD(name)
+	if tld == "" {
+		return customErrors.NewValidationError(name, "domain", "expected a domain such as example.com", nil)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	providers, err := newPriceProviders(ctx)
+	if err != nil {
+		return err
+	}
+
+	quotes := pricing.Compare(ctx, tld, providers)
+	if outputFormat == output.FormatJSON {
+		data, _ := json.MarshalIndent(newPriceComparison(name, tld, quotes), "", "  ")
+		fmt.Fprintln(stdout, string(data))
+	} else {
+		fmt.Fprint(stdout, formatPriceComparison(name, tld, quotes))
+	}
+
+	// Like bulk, fail only when no provider could answer
+	for _, quote := range quotes {
+		if quote.Err == nil {
+			return nil
+		}
+	}
+	return quotes[0].Err
+}
+
+// newPriceProviders creates the --provider providers followed by those of
+// the --prices tables
+func newPriceProviders(ctx context.Context) ([]pricing.Provider, error) {
+	names := priceProviders
+	if len(names) == 0 {
+		names = []string{"route53"}
+	}
+
+	var providers []pricing.Provider
+	seen := make(map[string]bool)
+	for _, value := range names {
+		name := strings.ToLower(strings.TrimSpace(value))
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		switch name {
+		case "route53":
+			awsClient, err := newTimedRoute53Client(ctx)
+			if err != nil {
+				return nil, err
+			}
+			checker := domain.NewDomainCheckerWithTimeout(domain.NewDomainValidator(), awsClient, timeout)
+			providers = append(providers, route53Prices{checker})
+		case "porkbun":
+			providers = append(providers, newPriceProvider(name))
+		default:
+			return nil, customErrors.NewValidationError("", "provider",
+				fmt.Sprintf("invalid --provider value %q: must be one of %s%s", value, strings.Join(priceProviderChoices, ", "),
+					customErrors.DidYouMean(value, priceProviderChoices)), nil)
+		}
+	}
+
+	for _, path := range priceTables {
+		tables, err := pricing.LoadTables(path)
+		if err != nil {
+			return nil, customErrors.NewValidationError("", "prices", "invalid price table "+path, err)
+		}
+		for _, table := range tables {
+			providers = append(providers, table)
+		}
+	}
+
+	return providers, nil
+}
+
+// route53Prices quotes the prices Route 53 Domains lists
+type route53Prices struct {
+	checker *domain.DomainChecker
+}
+
+func (p route53Prices) Name() string {
+	return "route53"
+}
+
+func (p route53Prices) Price(ctx context.Context, tld string) (*domain.PricingInfo, error) {
+	return p.checker.TLDPricing(ctx, tld)
+}
+
+// formatPriceComparison renders quotes, one registrar per line, followed by
+// the cheapest registration in each currency
+func formatPriceComparison(name, tld string, quotes []pricing.Quote) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Prices for .%s (%s):\n", tld, name)
+
+	width := 0
+	for _, quote := range quotes {
+		width = max(width, len(quote.Provider))
+	}
+	for _, quote := range quotes {
+		fmt.Fprintf(&b, "  %-*s  ", width, quote.Provider)
+		switch {
+		case quote.Err != nil:
+			fmt.Fprintf(&b, "error: %v\n", quote.Err)
+		case !quote.Offered():
+			fmt.Fprintf(&b, "does not offer .%s\n", tld)
+		default:
+			fields := []string{formatPrice(quote.Pricing.RegistrationPrice, quote.Pricing.Currency)}
+			for _, price := range []struct {
+				label string
+				value *float64
+			}{{"renewal", quote.Pricing.RenewalPrice}, {"transfer", quote.Pricing.TransferPrice}} {
+				if price.value != nil {
+					fields = append(fields, price.label+" "+formatPrice(price.value, quote.Pricing.Currency))
+				}
+			}
+			b.WriteString(strings.Join(fields, ", ") + "\n")
+		}
+	}
+
+	route53 := routeQuote(quotes)
+	for _, cheapest := range pricing.Cheapest(quotes) {
+		fmt.Fprintf(&b, "Cheapest: %s at %s", cheapest.Provider, formatPrice(cheapest.Pricing.RegistrationPrice, cheapest.Pricing.Currency))
+		if route53 != nil && route53.Provider != cheapest.Provider && route53.Pricing.Currency == cheapest.Pricing.Currency {
+			saving := *route53.Pricing.RegistrationPrice - *cheapest.Pricing.RegistrationPrice
+			fmt.Fprintf(&b, ", %s less than Route 53", formatPrice(&saving, cheapest.Pricing.Currency))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// routeQuote returns the offered Route 53 quote, or nil
+func routeQuote(quotes []pricing.Quote) *pricing.Quote {
+	for i := range quotes {
+		if quotes[i].Provider == "route53" && quotes[i].Offered() {
+			return &quotes[i]
+		}
+	}
+	return nil
+}
+
+// formatPrice formats a price as the console formatter does
+func formatPrice(price *float64, currency string) string {
+	return fmt.Sprintf("$%.2f %s", *price, currency)
+}
+
+// priceComparison is the JSON output of compare-price
+type priceComparison struct {
+	Domain string      `json:"domain"`
+	TLD    string      `json:"tld"`
+	Quotes []jsonQuote `json:"quotes"`
+	// Cheapest is the registrar with the cheapest registration, by currency
+	Cheapest map[string]string `json:"cheapest"`
+}
+
+// jsonQuote is a registrar's prices in the JSON output
+type jsonQuote struct {
+	Provider string              `json:"provider"`
+	Offered  bool                `json:"offered"`
+	Pricing  *output.JSONPricing `json:"pricing,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+// newPriceComparison converts quotes to their JSON output
+func newPriceComparison(name, tld string, quotes []pricing.Quote) priceComparison {
+	comparison := priceComparison{Domain: name, TLD: tld, Quotes: make([]jsonQuote, len(quotes)), Cheapest: make(map[string]string)}
+	for i, quote := range quotes {
+		comparison.Quotes[i] = jsonQuote{Provider: quote.Provider, Offered: quote.Offered()}
+		if quote.Err != nil {
+			comparison.Quotes[i].Error = quote.Err.Error()
+		} else if quote.Offered() {
+			comparison.Quotes[i].Pricing = output.NewJSONPricing(quote.Pricing)
+		}
+	}
+	for _, cheapest := range pricing.Cheapest(quotes) {
+		comparison.Cheapest[cheapest.Pricing.Currency] = cheapest.Provider
+	}
+	return comparison
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/spf13/cobra"
+)
+
+// assumeYes is --yes: approve every confirmation without prompting
+var assumeYes bool
+
+// addConfirmFlags registers --yes on cmd, for commands that delete data or
+// spend money and ask for confirmation first
+func addConfirmFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Do not ask for confirmation, for scripts")
+}
+
+// spend is what an action costs, shown in its confirmation prompt
+type spend struct {
+	Amount   float64
+	Currency string
+}
+
+func (s spend) String() string {
+	return fmt.Sprintf("$%.2f %s", s.Amount, s.Currency)
+}
+
+// confirm asks on stderr whether to go ahead with action, such as "Register
+// example.com for 1 year", and returns nil once the user answers yes. The
+// cost, when known, is shown with the action. Anything but "y" or "yes"
+// declines, as does a closed stdin. --yes approves without asking; without
+// it, an action cannot be confirmed when stdin is not a terminal, so scripts
+// fail instead of spending money or deleting data unattended.
+func confirm(action string, cost *spend) error {
+	if cost != nil {
+		action = fmt.Sprintf("%s for %s", action, cost)
+	}
+	if assumeYes {
+		if verbose {
+			fmt.Fprintf(stderr, "%s: confirmed by --yes\n", action)
+		}
+		return nil
+	}
+	if !stdinTerminal() {
+		return customErrors.NewValidationError("", "yes",
+			fmt.Sprintf("%s needs confirmation; use --yes to confirm without a terminal", action), nil)
+	}
+
+	fmt.Fprintf(stderr, "%s? [y/N] ", action)
+	answer := readAnswer(stdin)
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		if !strings.HasSuffix(answer, "\n") {
+			fmt.Fprintln(stderr)
+		}
+		return customErrors.NewValidationError("", "confirm", "cancelled: "+action+" was not confirmed", nil)
+	}
+}
+
+// readAnswer reads one line from r, a byte at a time so that input meant
+// for a later prompt is not consumed
+func readAnswer(r io.Reader) string {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		if n, err := r.Read(b); n == 1 {
+			line = append(line, b[0])
+			if b[0] == '\n' {
+				break
+			}
+		} else if err != nil {
+			break
+		}
+	}
+	return string(line)
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/aws"
+	"github.com/abakermi/r53check/internal/config"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/output"
+	"github.com/abakermi/r53check/internal/register"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// contactProfiles are the contact profiles, from the config file
+	contactProfiles map[string]config.ContactProfile
+
+	// contactProfile is --contact: the contact profile to register to
+	contactProfile string
+)
+
+// newParameterReader creates what contact profiles are read from SSM
+// Parameter Store with; tests replace it
+var newParameterReader = func(ctx context.Context) (register.ParameterReader, error) {
+	awsConfig, err := loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return aws.NewParameters(awsConfig), nil
+}
+
+// contactsCmd groups the commands about contact profiles
+var contactsCmd = &cobra.Command{
+	Use:   "contacts",
+	Short: "List and show the contact profiles domains are registered to",
+	Long: `List and show the contact profiles that register, and watch with
+--register-on-available, register domains to.
+
+Profiles are kept under contacts in the config file, by name, each with a
+registrant contact, optional admin and tech contacts that default to the
+registrant, and WHOIS privacy toggles for each contact. A profile can instead
+name an SSM Parameter Store parameter holding it as YAML or JSON, so that the
+contact details are shared and kept out of the config file. Registrations use
+the profile picked with --contact, or register.profile.`,
+	Example: `  # ~/.config/r53check/config.yaml
+  register:
+    profile: work
+  contacts:
+    work:
+      registrant:
+        first_name: Ada
+        last_name: Lovelace
+        organization: Example Ltd
+        type: COMPANY
+        email: ada@example.com
+        phone: "+44.2071234567"
+        address_line_1: 12 St James's Square
+        city: London
+        country_code: GB
+        zip_code: SW1Y 4JH
+      tech:
+        first_name: Grace
+        last_name: Hopper
+        email: ops@example.com
+        ...
+      privacy:
+        tech: false
+    shared:
+      ssm_parameter: /r53check/contacts/shared
+
+  # Check a profile before registering with it
+  r53check contacts show work`,
+}
+
+var contactsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the contact profiles",
+	Args:  cobra.NoArgs,
+	RunE:  runContactsListCommand,
+}
+
+var contactsShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a contact profile, reading it from SSM Parameter Store if it is kept there",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runContactsShowCommand,
+}
+
+func init() {
+	contactsShowCmd.Flags().BoolVar(&privacy, "privacy", true, "WHOIS privacy of the contacts the profile has no toggle for")
+	contactsCmd.AddCommand(contactsListCmd, contactsShowCmd)
+	rootCmd.AddCommand(contactsCmd)
+}
+
+// resolveContactProfile returns the contact profile name, reading it from
+// SSM Parameter Store when the config file says it is kept there
+func resolveContactProfile(ctx context.Context, name string) (register.Profile, error) {
+	// The config file's keys, and so the profile names, are lowercased
+	name = strings.ToLower(name)
+
+	var parameters register.ParameterReader
+	if profile, ok := contactProfiles[name]; ok && profile.SSMParameter != "" {
+		if mockMode {
+			return register.Profile{}, customErrors.NewValidationError("", "mock", "the contact profile "+name+" is kept in SSM Parameter Store, which --mock cannot read", nil)
+		}
+		var err error
+		if parameters, err = newParameterReader(ctx); err != nil {
+			return register.Profile{}, err
+		}
+	}
+	return register.ResolveProfile(ctx, name, contactProfiles, parameters, privacy)
+}
+
+func runContactsListCommand(cmd *cobra.Command, args []string) error {
+	names := register.ProfileNames(contactProfiles)
+
+	if outputFormat == output.FormatJSON {
+		type profileJSON struct {
+			Name         string `json:"name"`
+			Default      bool   `json:"default"`
+			SSMParameter string `json:"ssm_parameter,omitempty"`
+			Registrant   string `json:"registrant,omitempty"`
+		}
+		profiles := make([]profileJSON, 0, len(names))
+		for _, name := range names {
+			profile := contactProfiles[name]
+			profiles = append(profiles, profileJSON{
+				Name:         name,
+				Default:      name == strings.ToLower(registerSettings.Profile),
+				SSMParameter: profile.SSMParameter,
+				Registrant:   contactName(profile.Registrant),
+			})
+		}
+		data, _ := json.MarshalIndent(map[string]interface{}{"profiles": profiles}, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	if len(names) == 0 {
+		fmt.Fprintln(stdout, "No contact profiles; add them under contacts in the config file")
+		return nil
+	}
+	for _, name := range names {
+		profile := contactProfiles[name]
+		marker := " "
+		if name == strings.ToLower(registerSettings.Profile) {
+			marker = "*"
+		}
+		if profile.SSMParameter != "" {
+			fmt.Fprintf(stdout, "%s %s  (SSM parameter %s)\n", marker, name, profile.SSMParameter)
+		} else {
+			fmt.Fprintf(stdout, "%s %s  %s\n", marker, name, contactName(profile.Registrant))
+		}
+	}
+	return nil
+}
+
+func runContactsShowCommand(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	profile, err := resolveContactProfile(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == output.FormatJSON {
+		data, _ := json.MarshalIndent(profile, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+	fmt.Fprint(stdout, formatContactProfile(profile))
+	return nil
+}
+
+// formatContactProfile renders each contact of a profile with its WHOIS
+// privacy
+func formatContactProfile(profile register.Profile) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Contact profile %s (%s):\n", profile.Name, profile.Source)
+	for _, role := range []struct {
+		label   string
+		contact config.ContactSettings
+		private bool
+	}{
+		{"Registrant", profile.Registrant, profile.Privacy.Registrant},
+		{"Admin", profile.Admin, profile.Privacy.Admin},
+		{"Tech", profile.Tech, profile.Privacy.Tech},
+	} {
+		visibility := "public in WHOIS"
+		if role.private {
+			visibility = "private"
+		}
+		fmt.Fprintf(&b, "  %-10s  %s, %s\n", role.label, contactName(role.contact), visibility)
+		address := []string{role.contact.AddressLine1, role.contact.AddressLine2, role.contact.City, role.contact.State, role.contact.ZipCode, strings.ToUpper(role.contact.CountryCode)}
+		fmt.Fprintf(&b, "  %-10s  %s, %s\n", "", joinNonEmpty(address), role.contact.Phone)
+	}
+	return b.String()
+}
+
+// contactName describes a contact by name, organization and email, such as
+// "Ada Lovelace, Example Ltd <ada@example.com>"
+func contactName(contact config.ContactSettings) string {
+	name := joinNonEmpty([]string{strings.TrimSpace(contact.FirstName + " " + contact.LastName), contact.Organization})
+	if contact.Email != "" {
+		name += " <" + contact.Email + ">"
+	}
+	return strings.TrimSpace(name)
+}
+
+// joinNonEmpty joins the non-empty values with commas
+func joinNonEmpty(values []string) string {
+	var kept []string
+	for _, value := range values {
+		if value != "" {
+			kept = append(kept, value)
+		}
+	}
+	return strings.Join(kept, ", ")
+}
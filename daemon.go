@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/history"
+	"github.com/abakermi/r53check/internal/monitor"
+	"github.com/abakermi/r53check/internal/schedule"
+	"github.com/abakermi/r53check/internal/sink"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Daemon command flags
+	scheduleSpec string
+	runNow       bool
+	runOnce      bool
+)
+
+// daemonCmd represents the daemon command
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Check domains on a schedule and report status changes",
+	Long: `Run as a long-lived monitor that checks a list of domains on a cron schedule.
+
+Every run is recorded in the check history, and each domain whose status
+changed since its previous check is reported, for example when a registered
+domain becomes available. A domain's first check sets its baseline, and failed
+checks never count as changes. With --no-history, changes are only detected
+while the daemon keeps running. With --notify, changes are also sent to the
+selected notifiers.
+
+The schedule uses the standard five cron fields (minute hour day-of-month month
+day-of-week) in local time, or one of @hourly, @daily, @weekly, @monthly and
+@yearly. The domains files are read again before every run, and glob patterns
+expanded again, so lists can be edited or added without restarting the daemon.`,
+	Example: `  # Check every six hours
+  r53check daemon --schedule "0 */6 * * *" --file domains.txt
+
+  # Check every weekday at 09:00, starting with an immediate run
+  r53check daemon --schedule "0 9 * * 1-5" --file domains.txt --run-now
+
+  # Email status changes, using the email settings from the config file
+  r53check daemon --schedule @hourly --file domains.txt --notify email
+
+  # Post status changes to a webhook
+  r53check daemon --schedule @hourly --file domains.txt --webhook-url https://hooks.example.com/r53check
+
+  # Publish every result to an SQS queue for downstream processing
+  r53check daemon --schedule @hourly --file domains.txt --sqs-queue-url https://sqs.us-east-1.amazonaws.com/123456789012/domains
+
+  # Run once and exit, for use from an external scheduler
+  r53check daemon --once --file domains.txt`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonCommand,
+}
+
+func init() {
+	daemonCmd.Flags().StringVar(&scheduleSpec, "schedule", "", `Cron schedule for checks, e.g. "0 */6 * * *"`)
+	daemonCmd.Flags().StringArrayVarP(&domainsFiles, "file", "f", nil, "Read domains from file (one domain per line, or a CSV or Excel file); repeatable and accepts glob patterns")
+	daemonCmd.Flags().StringVar(&domainsColumn, "column", "", "Column of a CSV or Excel file holding the domains, by header name or number")
+	daemonCmd.Flags().BoolVar(&runNow, "run-now", false, "Run a check immediately instead of waiting for the first scheduled time")
+	daemonCmd.Flags().BoolVar(&runOnce, "once", false, "Run a single check and exit, ignoring --schedule")
+	daemonCmd.Flags().IntVar(&concurrency, "concurrency", 5, "Maximum number of concurrent requests")
+	addHistoryFlags(daemonCmd)
+	addNotifyFlags(daemonCmd)
+	addSinkFlags(daemonCmd)
+
+	rootCmd.AddCommand(daemonCmd)
+}
+
+func runDaemonCommand(cmd *cobra.Command, args []string) error {
+	if len(domainsFiles) == 0 {
+		return customErrors.NewValidationError("", "file", "a domains file is required; use --file", nil)
+	}
+	if slices.Contains(domainsFiles, "-") {
+		// The file is read again for every run, which stdin cannot be
+		return customErrors.NewValidationError("", "file", "the daemon cannot read domains from stdin; use a file", nil)
+	}
+
+	var sched *schedule.Schedule
+	if !runOnce {
+		if scheduleSpec == "" {
+			return customErrors.NewValidationError("", "schedule", "a schedule is required; use --schedule or --once", nil)
+		}
+		var err error
+		if sched, err = schedule.Parse(scheduleSpec); err != nil {
+			return customErrors.NewValidationError("", "schedule", "invalid schedule", err)
+		}
+	}
+
+	if err := parseRunTags(); err != nil {
+		return err
+	}
+
+	// Fail on an unreadable domains file before waiting for the first run
+	if _, err := readDaemonDomains(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	setup, err := newMonitorSetup(ctx)
+	if err != nil {
+		return err
+	}
+	defer setup.Close()
+
+	if runOnce {
+		return runMonitor(ctx, setup, readDaemonDomains)
+	}
+
+	if runNow {
+		reportRunError(runMonitor(ctx, setup, readDaemonDomains))
+	}
+
+	return monitorLoop(ctx, setup, readDaemonDomains, func(now time.Time) (time.Time, error) {
+		next := sched.Next(now)
+		if next.IsZero() {
+			return next, customErrors.NewValidationError("", "schedule", "schedule "+sched.String()+" never runs", nil)
+		}
+		return next, nil
+	})
+}
+
+// monitorSetup is the monitor of the daemon and watch commands, with the
+// history store and SQS sink it uses
+type monitorSetup struct {
+	mon     *monitor.Monitor
+	store   history.Store
+	sqsSink *sink.SQSSink
+
+	// afterRun, when set, is given the report of every run, such as to
+	// register a watched domain once it is available
+	afterRun func(ctx context.Context, report *monitor.Report) error
+}
+
+// newMonitorSetup creates a monitor that records runs in the check history,
+// prints status changes and sends them to the --notify notifiers
+func newMonitorSetup(ctx context.Context) (*monitorSetup, error) {
+	awsClient, err := newRoute53Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	validator, _, err := newValidator(ctx, awsClient)
+	if err != nil {
+		return nil, err
+	}
+	checker, err := newChecker(validator, awsClient)
+	if err != nil {
+		return nil, err
+	}
+
+	setup := &monitorSetup{}
+	if setup.store, err = openHistory(ctx); err != nil {
+		return nil, err
+	}
+	if setup.mon, err = monitor.NewMonitor(checker, setup.store); err != nil {
+		setup.Close()
+		return nil, err
+	}
+	setup.mon.SetPricing(price)
+	setup.mon.SetTags(runTags)
+	setup.mon.AddNotifier(monitor.NewLogNotifier(stdout))
+
+	notifiers, err := newNotifiers(ctx)
+	if err != nil {
+		setup.Close()
+		return nil, err
+	}
+	for _, n := range notifiers {
+		setup.mon.AddNotifier(n)
+	}
+
+	if setup.sqsSink, err = newSQSSink(ctx, checker); err != nil {
+		setup.Close()
+		return nil, err
+	}
+	return setup, nil
+}
+
+// Close publishes the results still queued for SQS and closes the history
+func (s *monitorSetup) Close() {
+	if s.sqsSink != nil {
+		s.sqsSink.Close()
+	}
+	if s.store != nil {
+		s.store.Close()
+	}
+}
+
+// monitorLoop runs the monitor at the times returned by next, until ctx is
+// cancelled. Failed runs are reported without stopping the loop.
+func monitorLoop(ctx context.Context, setup *monitorSetup, list func() ([]string, error), next func(time.Time) (time.Time, error)) error {
+	for {
+		at, err := next(time.Now())
+		if err != nil {
+			return err
+		}
+		if verbose {
+			fmt.Fprintf(stderr, "Next run at %s\n", at.Format(time.RFC3339))
+		}
+
+		timer := time.NewTimer(time.Until(at))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if verbose {
+				fmt.Fprintln(stderr, "Received interrupt signal, stopping")
+			}
+			return nil
+		case <-timer.C:
+		}
+
+		reportRunError(runMonitor(ctx, setup, list))
+	}
+}
+
+// runMonitor reads the domains from list and runs one monitoring check,
+// printing a summary line for the run. With an SQS sink, the run only ends
+// once its results have been published.
+func runMonitor(ctx context.Context, setup *monitorSetup, list func() ([]string, error)) error {
+	domains, err := list()
+	if err != nil {
+		return err
+	}
+
+	report, err := setup.mon.Run(ctx, domains)
+	if setup.sqsSink != nil {
+		err = errors.Join(err, setup.sqsSink.Flush())
+	}
+	if report != nil {
+		printRunSummary(report)
+		if setup.afterRun != nil {
+			err = errors.Join(err, setup.afterRun(ctx, report))
+		}
+	}
+	return err
+}
+
+// readDaemonDomains reads and normalizes the domains file
+func readDaemonDomains() ([]string, error) {
+	domains, err := readDomainFiles(domainsFiles)
+	if err != nil {
+		return nil, customErrors.NewValidationError("", "file", "unable to read domains file", err)
+	}
+	if len(domains) == 0 {
+		return nil, customErrors.NewValidationError("", "file", "no domains found in "+strings.Join(domainsFiles, ", "), nil)
+	}
+	return normalizeDomains(domains), nil
+}
+
+// printRunSummary writes a one-line summary of a monitoring run
+func printRunSummary(report *monitor.Report) {
+	available, errs := 0, 0
+	for _, result := range report.Results {
+		switch {
+		case result.Error != nil:
+			errs++
+		case result.Status == domain.StatusAvailable:
+			available++
+		}
+	}
+
+	duration := report.FinishedAt.Sub(report.StartedAt).Round(time.Millisecond).String()
+	if report.Incomplete {
+		duration += ", incomplete"
+	}
+	fmt.Fprintf(stdout, "%s run %s: %d checked, %d available, %d errors, %d changed (%s)\n",
+		report.FinishedAt.Format(time.RFC3339), report.RunID, len(report.Results), available, errs,
+		len(report.Changes), duration)
+}
+
+// reportRunError prints a failed scheduled run without stopping the daemon
+func reportRunError(err error) {
+	if err != nil {
+		fmt.Fprintln(stderr, createFormatter().FormatError(err))
+		reporter.ReportError(err)
+	}
+}
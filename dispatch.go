@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// defaultCommand lets domains be given without a command: when the first
+// argument that is not a flag looks like a domain rather than a command, it
+// prepends check for a single domain, or bulk for several, so
+// "r53check example.com" runs "r53check check example.com". The command goes
+// first so that flags of either command can come before the domains.
+// Anything else, including a mistyped command, is returned unchanged for
+// cobra to handle.
+func defaultCommand(args []string) []string {
+	positional := positionalArgs(args, rootCmd.PersistentFlags(), checkCmd.Flags(), bulkCmd.Flags())
+	if len(positional) == 0 {
+		return args
+	}
+
+	first := args[positional[0]]
+	if isCommand(rootCmd, first) || !looksLikeDomain(first) {
+		return args
+	}
+
+	command := checkCmd.Name()
+	if len(positional) > 1 {
+		command = bulkCmd.Name()
+	}
+	return append([]string{command}, args...)
+}
+
+// positionalArgs returns the indexes of the arguments that are neither flags
+// nor the values of flags defined in one of flagSets. Everything after "--"
+// is positional.
+func positionalArgs(args []string, flagSets ...*pflag.FlagSet) []int {
+	var positional []int
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			for j := i + 1; j < len(args); j++ {
+				positional = append(positional, j)
+			}
+			return positional
+		case arg == "-" || !strings.HasPrefix(arg, "-"):
+			positional = append(positional, i)
+		case !strings.Contains(arg, "=") && takesValue(arg, flagSets):
+			i++
+		}
+	}
+	return positional
+}
+
+// takesValue reports whether the flag arg, without an inline value, is one
+// of flagSets' flags that reads its value from the next argument
+func takesValue(arg string, flagSets []*pflag.FlagSet) bool {
+	for _, flags := range flagSets {
+		var flag *pflag.Flag
+		if name, ok := strings.CutPrefix(arg, "--"); ok {
+			flag = flags.Lookup(name)
+		} else if len(arg) == 2 {
+			flag = flags.ShorthandLookup(arg[1:])
+		}
+		if flag != nil {
+			return flag.NoOptDefVal == ""
+		}
+	}
+	return false
+}
+
+// isCommand reports whether name is a subcommand of cmd, or one of its
+// aliases, including the help and completion commands cobra adds
+func isCommand(cmd *cobra.Command, name string) bool {
+	if name == "help" || name == "completion" || name == cobra.ShellCompRequestCmd || name == cobra.ShellCompNoDescRequestCmd {
+		return true
+	}
+	for _, sub := range cmd.Commands() {
+		if sub.Name() == name || sub.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeDomain reports whether arg could be a domain or URL rather than a
+// command name, which never contains a dot
+func looksLikeDomain(arg string) bool {
+	return strings.Contains(arg, ".") && !strings.ContainsAny(arg, " \t")
+}
+
+// suggestFlag adds the closest flag of cmd to an unknown flag error, such
+// as "unknown flag: --outptu (did you mean --output?)". Cobra already
+// suggests commands for a mistyped command, but not flags.
+func suggestFlag(cmd *cobra.Command, err error) error {
+	name, ok := strings.CutPrefix(err.Error(), "unknown flag: --")
+	if !ok {
+		return err
+	}
+
+	var names []string
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if !flag.Hidden {
+			names = append(names, flag.Name)
+		}
+	})
+	if suggestion := customErrors.Suggest(name, names); suggestion != "" {
+		return fmt.Errorf("%w (did you mean --%s?)", err, suggestion)
+	}
+	return err
+}
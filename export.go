@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/spf13/cobra"
+)
+
+// exportFormats are the --format values of export
+var exportFormats = []string{"terraform"}
+
+var (
+	// Export command flags
+	exportFormat     string
+	exportHostedZone bool
+	exportAutoRenew  bool
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [domains...] --format terraform",
+	Short: "Export the available domains as infrastructure as code",
+	Long: `Check domains and print configuration for registering the available ones, so
+registration goes through the review of an infrastructure as code workflow
+instead of ad-hoc purchases.
+
+--format terraform prints an aws_route53domains_registered_domain resource per
+available domain, and with --hosted-zone an aws_route53_zone resource whose
+name servers the domain is delegated to. Each resource notes when the domain
+was found available and, with --price, its registration price. Domains that
+are not available or could not be checked are listed on stderr and left out.
+
+The AWS provider manages a domain with aws_route53domains_registered_domain once
+it is registered to the account, so the exported resources record the intended
+settings for review; availability can change before they are applied.`,
+	Example: `  # Write Terraform for the available domains of a shortlist
+  r53check export --format terraform example.com example.io > domains.tf
+
+  # Include a hosted zone per domain and the registration prices
+  r53check --price export --format terraform --hosted-zone --file shortlist.txt`,
+	RunE: runExportCommand,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "terraform", "Configuration to export: terraform")
+	exportCmd.Flags().StringArrayVarP(&domainsFiles, "file", "f", nil, "Read domains from file (one domain per line, or a CSV or Excel file); repeatable and accepts glob patterns")
+	exportCmd.Flags().BoolVar(&exportHostedZone, "hosted-zone", false, "Also create a Route 53 hosted zone per domain and delegate the domain to it")
+	exportCmd.Flags().BoolVar(&exportAutoRenew, "auto-renew", true, "Renew the exported domains automatically")
+	exportCmd.Flags().IntVar(&concurrency, "concurrency", 5, "Maximum number of concurrent requests")
+
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExportCommand(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(strings.TrimSpace(exportFormat))
+	if format != "terraform" {
+		return customErrors.NewValidationError("", "format",
+			fmt.Sprintf("invalid --format value %q: must be one of %s%s", exportFormat, strings.Join(exportFormats, ", "),
+				customErrors.DidYouMean(exportFormat, exportFormats)), nil)
+	}
+
+	domains := args
+	if len(domainsFiles) > 0 {
+		fileDomains, err := readDomainFiles(domainsFiles)
+		if err != nil {
+			return customErrors.NewValidationError("", "file", "unable to read domains file", err)
+		}
+		domains = fileDomains
+	}
+	if len(domains) == 0 {
+		return customErrors.NewValidationError("", "domains", "no domains provided; use arguments or --file", nil)
+	}
+
+	ctx := context.Background()
+	awsClient, err := newTimedRoute53Client(ctx)
+	if err != nil {
+		return err
+	}
+	validator, _, err := newValidator(ctx, awsClient)
+	if err != nil {
+		return err
+	}
+	checker, err := newChecker(validator, awsClient)
+	if err != nil {
+		return err
+	}
+
+	check := checker.CheckAvailabilityBulk
+	if price {
+		check = checker.CheckAvailabilityBulkWithPricing
+	}
+	results, err := check(ctx, normalizeDomains(domains))
+	if results == nil {
+		return err
+	}
+
+	var available []*domain.AvailabilityResult
+	var firstErr error
+	for _, result := range results {
+		switch {
+		case result == nil:
+			continue
+		case result.Error != nil:
+			fmt.Fprintf(stderr, "Not exported: %s could not be checked: %v\n", result.Domain, result.Error)
+			if firstErr == nil {
+				firstErr = result.Error
+			}
+		case !result.Available:
+			fmt.Fprintf(stderr, "Not exported: %s is %s\n", result.Domain, result.Status)
+		case result.Unverified:
+			// A fallback's answer is not enough to register a domain on
+			fmt.Fprintf(stderr, "Not exported: %s is only likely available, according to %s\n", result.Domain, result.Source)
+		default:
+			available = append(available, result)
+		}
+	}
+
+	if len(available) == 0 {
+		if firstErr != nil {
+			return firstErr
+		}
+		return customErrors.NewValidationError("", "domains", "none of the domains is available; nothing to export", nil)
+	}
+	fmt.Fprint(stdout, terraformConfig(available, exportHostedZone, exportAutoRenew))
+	return nil
+}
+
+// terraformConfig renders results as Terraform resources, with a hosted zone
+// per domain when hostedZone is set
+func terraformConfig(results []*domain.AvailabilityResult, hostedZone, autoRenew bool) string {
+	var b strings.Builder
+	b.WriteString("# Generated by r53check from availability checks; domains may be registered\n")
+	b.WriteString("# by someone else before this is applied.\n")
+
+	for _, result := range results {
+		name := terraformName(result.Domain)
+		b.WriteString("\n")
+		if hostedZone {
+			fmt.Fprintf(&b, "resource \"aws_route53_zone\" %q {\n", name)
+			fmt.Fprintf(&b, "  name = %q\n", result.Domain)
+			b.WriteString("}\n\n")
+		}
+
+		fmt.Fprintf(&b, "# %s was AVAILABLE at %s", result.Domain, result.CheckedAt.UTC().Format(time.RFC3339))
+		if result.Pricing != nil && result.Pricing.RegistrationPrice != nil {
+			fmt.Fprintf(&b, "; registration $%.2f %s", *result.Pricing.RegistrationPrice, result.Pricing.Currency)
+		}
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "resource \"aws_route53domains_registered_domain\" %q {\n", name)
+		fmt.Fprintf(&b, "  domain_name = %q\n", result.Domain)
+		fmt.Fprintf(&b, "  auto_renew  = %t\n", autoRenew)
+		if hostedZone {
+			b.WriteString("\n")
+			b.WriteString("  dynamic \"name_server\" {\n")
+			fmt.Fprintf(&b, "    for_each = aws_route53_zone.%s.name_servers\n", name)
+			b.WriteString("    content {\n")
+			b.WriteString("      name = name_server.value\n")
+			b.WriteString("    }\n")
+			b.WriteString("  }\n")
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// terraformName turns a domain into a Terraform resource name, which may only
+// hold letters, digits, underscores and hyphens and must not start with a digit
+func terraformName(name string) string {
+	label := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	if label == "" || (label[0] >= '0' && label[0] <= '9') || label[0] == '-' {
+		label = "_" + label
+	}
+	return label
+}
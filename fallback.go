@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/whois"
+)
+
+// fallbackChoices are the --fallback values
+var fallbackChoices = []string{"whois"}
+
+// fallbackFlag is --fallback: how to check domains under TLDs Route 53
+// Domains does not support
+var fallbackFlag string
+
+// newFallback creates the checker of a --fallback value; tests replace it
+var newFallback = func(name string) domain.Fallback {
+	return whois.NewClient()
+}
+
+// setupFallback gives checker the --fallback checker, if one was asked for
+func setupFallback(checker *domain.DomainChecker) error {
+	name := strings.ToLower(strings.TrimSpace(fallbackFlag))
+	switch name {
+	case "":
+		return nil
+	case "whois":
+		if verbose {
+			fmt.Fprintln(stderr, "Checking unsupported TLDs with WHOIS; those results are unverified")
+		}
+		checker.SetFallback(newFallback(name))
+		return nil
+	default:
+		return customErrors.NewValidationError("", "fallback",
+			fmt.Sprintf("invalid --fallback value %q: must be one of %s%s", fallbackFlag, strings.Join(fallbackChoices, ", "),
+				customErrors.DidYouMean(fallbackFlag, fallbackChoices)), nil)
+	}
+}
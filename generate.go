@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/config"
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/generate"
+	"github.com/abakermi/r53check/internal/input"
+	"github.com/abakermi/r53check/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Generate command flags
+	generatePrefixes []string
+	generateSuffixes []string
+	generateHyphens  bool
+	generateAll      bool
+	generateList     bool
+
+	// generateWordLists are combined into names instead of varying names;
+	// generateSeparators also join their words, under each of generateTLDs
+	generateWordLists  [2]string
+	generateSeparators []string
+	generateTLDs       []string
+
+	// nameConstraints leave unrealistic names out of the generated ones
+	nameConstraints generate.Constraints
+
+	// maxPrice leaves names costing more to register out of the checks and
+	// output of bulk and the name generating commands
+	maxPrice float64
+
+	// generateSettings holds the affix lists from the config file
+	generateSettings config.GenerateSettings
+)
+
+// generateCmd represents the generate command
+var generateCmd = &cobra.Command{
+	Use:   "generate <name>... | --list1 <file> --list2 <file>",
+	Short: "Check prefixed and suffixed variants of a name, or combinations of two word lists",
+	Long: `Generate variants of each name by adding prefixes and suffixes, such as
+getexample.com and examplehq.com for example.com, and check them all like the
+bulk command does. Only the available names are listed, after the summary.
+
+A name without TLD, such as "example", is generated under .com. The affixes
+come from --prefixes and --suffixes, the generate section of the config file,
+or a built-in list, in that order. --hyphens also joins them with a hyphen, as
+in example-app.io.
+
+With --list1 and --list2 instead of names, every word of the first file is
+combined with every word of the second, such as swiftfox.io from "swift" and
+"fox", under each --tld. The files hold one word per line. --separators also
+joins the words with each separator given, as in swift-fox.io.
+
+--max-price leaves out names whose registration costs more, or whose price is
+unknown. Names under TLDs that cost more are not checked at all.`,
+	Example: `  # Check variants of a taken name
+  r53check generate example.com
+
+  # Use your own affixes, with and without hyphens
+  r53check generate example.io --prefixes get,try --suffixes app,hq --hyphens
+
+  # Print the generated names without checking them
+  r53check generate example --list > candidates.txt
+
+  # Combine adjectives and nouns under .io, with and without a hyphen
+  r53check generate --list1 adjectives.txt --list2 nouns.txt --tld io --separators -
+
+  # Only list combinations costing up to $40 a year
+  r53check generate --list1 adjectives.txt --list2 nouns.txt --max-price 40`,
+	RunE: runGenerateCommand,
+}
+
+func init() {
+	generateCmd.Flags().StringSliceVar(&generatePrefixes, "prefixes", nil, fmt.Sprintf("Prefixes to add before the name (default %s)", strings.Join(generate.DefaultPrefixes, ",")))
+	generateCmd.Flags().StringSliceVar(&generateSuffixes, "suffixes", nil, fmt.Sprintf("Suffixes to add after the name (default %s)", strings.Join(generate.DefaultSuffixes, ",")))
+	generateCmd.Flags().BoolVar(&generateHyphens, "hyphens", false, "Also join each prefix and suffix with a hyphen, as in example-app")
+	generateCmd.Flags().StringVar(&generateWordLists[0], "list1", "", "Word list whose words start the combined names")
+	generateCmd.Flags().StringVar(&generateWordLists[1], "list2", "", "Word list whose words end the combined names")
+	generateCmd.Flags().StringSliceVar(&generateSeparators, "separators", nil, "Also join the words of --list1 and --list2 with each of these, e.g. -")
+	generateCmd.Flags().StringSliceVar(&generateTLDs, "tld", nil, "TLDs of the --list1 and --list2 combinations (default com)")
+	addMaxPriceFlag(generateCmd)
+	addConstraintFlags(generateCmd)
+	generateCmd.Flags().BoolVar(&generateAll, "all", false, "Print the result of every generated name, not only the available ones")
+	generateCmd.Flags().BoolVar(&generateList, "list", false, "Print the generated names, one per line, without checking them")
+	generateCmd.Flags().IntVar(&concurrency, "concurrency", 5, "Maximum number of concurrent requests")
+	addHistoryFlags(generateCmd)
+
+	rootCmd.AddCommand(generateCmd)
+}
+
+func runGenerateCommand(cmd *cobra.Command, args []string) error {
+	lists := generateWordLists[0] != "" || generateWordLists[1] != ""
+	switch {
+	case lists && len(args) > 0:
+		return customErrors.NewValidationError("", "list1", "give names or --list1 and --list2, not both", nil)
+	case lists && (generateWordLists[0] == "" || generateWordLists[1] == ""):
+		return customErrors.NewValidationError("", "list1", "--list1 and --list2 are used together", nil)
+	case !lists && len(args) == 0:
+		return customErrors.NewValidationError("", "name", "no names given; use arguments or --list1 and --list2", nil)
+	case !lists && (len(generateSeparators) > 0 || len(generateTLDs) > 0):
+		return customErrors.NewValidationError("", "separators", "--separators and --tld apply to --list1 and --list2", nil)
+	}
+	if lists {
+		return runWordListGenerate()
+	}
+
+	opts := generate.Options{
+		Prefixes: affixes(cmd, "prefixes", generatePrefixes, generateSettings.Prefixes, generate.DefaultPrefixes),
+		Suffixes: affixes(cmd, "suffixes", generateSuffixes, generateSettings.Suffixes, generate.DefaultSuffixes),
+		Hyphens:  generateHyphens,
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, base := range args {
+		for _, name := range generate.Names(base, opts) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return customErrors.NewValidationError("", "name", "no valid names could be generated", nil)
+	}
+
+	if verbose {
+		fmt.Fprintf(stderr, "Generated %s from %s\n", pluralize(len(names), "name"), strings.Join(args, ", "))
+	}
+	return checkGeneratedNames(domainEntries(names))
+}
+
+// runWordListGenerate checks the combinations of the --list1 and --list2 words
+func runWordListGenerate() error {
+	var groups [][]string
+	for i, path := range generateWordLists {
+		words, err := readWordList(path)
+		if err != nil {
+			return customErrors.NewValidationError("", fmt.Sprintf("list%d", i+1), "unable to read word list", err)
+		}
+		if len(words) == 0 {
+			return customErrors.NewValidationError("", fmt.Sprintf("list%d", i+1), "word list "+path+" is empty", nil)
+		}
+		groups = append(groups, words)
+	}
+
+	tlds := generateTLDs
+	if len(tlds) == 0 {
+		tlds = []string{generate.DefaultTLD}
+	}
+	separators := append([]string{""}, generateSeparators...)
+	names := generate.Combine(groups, separators, tlds)
+	if len(names) == 0 {
+		return customErrors.NewValidationError("", "list1", "no valid names could be made from the word lists", nil)
+	}
+
+	if verbose {
+		fmt.Fprintf(stderr, "Combined %d and %d words into %s\n", len(groups[0]), len(groups[1]), pluralize(len(names), "name"))
+	}
+	return checkGeneratedNames(domainEntries(names))
+}
+
+// readWordList reads one word per line from path, lowercased, skipping blank
+// lines and # comments
+func readWordList(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var words []string
+	for _, line := range strings.Split(string(data), "\n") {
+		word := strings.ToLower(strings.TrimSpace(line))
+		if word != "" && !strings.HasPrefix(word, "#") {
+			words = append(words, word)
+		}
+	}
+	return words, nil
+}
+
+// addConstraintFlags registers the flags narrowing the generated names
+func addConstraintFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&nameConstraints.MaxLength, "max-length", 0, "Leave out names longer than this, not counting the TLD")
+	cmd.Flags().BoolVar(&nameConstraints.LetterFirst, "letter-first", false, "Leave out names starting with a digit")
+	cmd.Flags().BoolVar(&nameConstraints.NoHyphens, "no-hyphens", false, "Leave out names with hyphens")
+	cmd.Flags().BoolVar(&nameConstraints.Pronounceable, "pronounceable", false, "Leave out names that are hard to pronounce, such as xkcd")
+}
+
+// addMaxPriceFlag registers --max-price on bulk or a name generating command
+func addMaxPriceFlag(cmd *cobra.Command) {
+	cmd.Flags().Float64Var(&maxPrice, "max-price", 0, "Leave out names whose registration costs more than this, in USD")
+}
+
+// affordableDomains leaves out the domains under TLDs whose registration
+// costs more than --max-price, or that have no listed price, before their
+// availability is checked. Each TLD's price is fetched once and reused for
+// the pricing of the results. TLDs whose price cannot be read are kept.
+func affordableDomains(ctx context.Context, checker *domain.DomainChecker, domains []string) []string {
+	if maxPrice <= 0 {
+		return domains
+	}
+
+	kept := make([]string, 0, len(domains))
+	for _, name := range domains {
+		pricing, err := checker.TLDPricing(ctx, domain.EffectiveTLD(name))
+		if err == nil && (pricing == nil || pricing.RegistrationPrice == nil || *pricing.RegistrationPrice > maxPrice) {
+			continue
+		}
+		kept = append(kept, name)
+	}
+	if verbose && len(kept) < len(domains) {
+		fmt.Fprintf(stderr, "Skipping %s under TLDs costing more than $%.2f\n", pluralize(len(domains)-len(kept), "domain"), maxPrice)
+	}
+	return kept
+}
+
+// withinMaxPrice leaves out the available results costing more than
+// --max-price to register, or whose price is unknown
+func withinMaxPrice(results []*domain.AvailabilityResult) []*domain.AvailabilityResult {
+	if maxPrice <= 0 {
+		return results
+	}
+
+	kept := make([]*domain.AvailabilityResult, 0, len(results))
+	for _, result := range results {
+		if result != nil && result.Available &&
+			(result.Pricing == nil || result.Pricing.RegistrationPrice == nil || *result.Pricing.RegistrationPrice > maxPrice) {
+			continue
+		}
+		kept = append(kept, result)
+	}
+	if verbose && len(kept) < len(results) {
+		fmt.Fprintf(stderr, "Left out %s costing more than $%.2f\n", pluralize(len(results)-len(kept), "available name"), maxPrice)
+	}
+	return kept
+}
+
+// checkGeneratedNames prints the entries with --list, in the domains file
+// format, and otherwise checks them like the bulk command, listing only the
+// available ones unless --all is set
+func checkGeneratedNames(entries []input.Entry) error {
+	if maxPrice < 0 {
+		return customErrors.NewValidationError("", "max-price", "--max-price must not be negative", nil)
+	}
+	if nameConstraints.MaxLength < 0 {
+		return customErrors.NewValidationError("", "max-length", "--max-length must not be negative", nil)
+	}
+
+	allowed := slices.DeleteFunc(slices.Clone(entries), func(entry input.Entry) bool {
+		return !nameConstraints.Allows(entry.Domain)
+	})
+	if len(allowed) == 0 {
+		return customErrors.NewValidationError("", "name", "no generated name meets the constraints", nil)
+	}
+	if verbose && len(allowed) < len(entries) {
+		fmt.Fprintf(stderr, "Left out %s not meeting the constraints\n", pluralize(len(entries)-len(allowed), "name"))
+	}
+	entries = allowed
+	if generateList {
+		for _, entry := range entries {
+			if entry.Note != "" {
+				fmt.Fprintf(stdout, "%s, %s\n", entry.Domain, entry.Note)
+			} else {
+				fmt.Fprintln(stdout, entry.Domain)
+			}
+		}
+		return nil
+	}
+
+	if err := parseRunTags(); err != nil {
+		return err
+	}
+	if !generateAll {
+		summaryOnly = true
+		listAvailable = true
+	}
+	if maxPrice > 0 {
+		price = true
+	}
+
+	return runBulkDomainCheck(context.Background(), entries, output.OrderInput)
+}
+
+// affixes returns the affix list set by flag, else the configured list, else
+// the defaults. An empty flag value turns the affixes off.
+func affixes(cmd *cobra.Command, flag string, values, configured, defaults []string) []string {
+	switch {
+	case cmd.Flags().Changed(flag):
+		return values
+	case len(configured) > 0:
+		return configured
+	default:
+		return defaults
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abakermi/r53check/internal/config"
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/history"
+	"github.com/abakermi/r53check/internal/monitor"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// History flags, shared by the check, bulk and daemon commands
+	historyFile string
+	noHistory   bool
+	tagValues   []string
+
+	// runTags are the parsed --tag flags, set by parseRunTags
+	runTags map[string]string
+
+	// historySettings selects the history backend, from the config file
+	historySettings config.HistorySettings
+)
+
+// addHistoryFlags registers the history flags on cmd
+func addHistoryFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&historyFile, "history", "", "History database (default $XDG_DATA_HOME/r53check/history.db)")
+	cmd.Flags().BoolVar(&noHistory, "no-history", false, "Do not record checks in the history database")
+	cmd.Flags().StringArrayVar(&tagValues, "tag", nil, "Label the run with a key=value tag, recorded in history and sent with results (repeatable)")
+}
+
+// parseRunTags parses the --tag flags into runTags
+func parseRunTags() error {
+	tags, err := history.ParseTags(tagValues)
+	if err != nil {
+		return err
+	}
+	runTags = tags
+	return nil
+}
+
+// newDynamoDBAPI creates the DynamoDB client used by the history.
+// Tests replace it with an in-memory table.
+var newDynamoDBAPI = func(ctx context.Context) (history.DynamoDBAPI, error) {
+	awsConfig, err := loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dynamodb.NewFromConfig(*awsConfig), nil
+}
+
+// openHistory opens the configured history store, or returns nil with
+// --no-history. --history always selects a SQLite database, and --mock
+// records in a mock history of its own unless --history is given, so that
+// fake results never reach the real one.
+func openHistory(ctx context.Context) (history.Store, error) {
+	if noHistory {
+		return nil, nil
+	}
+
+	backend := historySettings.Backend
+	if historyFile != "" || mockMode {
+		backend = "sqlite"
+	}
+
+	switch backend {
+	case "", "sqlite":
+		return openSQLiteHistory()
+	case "dynamodb":
+		table := historySettings.DynamoDB.Table
+		if table == "" {
+			return nil, customErrors.NewValidationError("", "history.dynamodb.table", "a DynamoDB table is required for the dynamodb history", nil)
+		}
+		api, err := newDynamoDBAPI(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if verbose {
+			fmt.Fprintf(stderr, "Recording history in DynamoDB table %s\n", table)
+		}
+		return history.NewDynamoDB(api, table), nil
+	case "postgres":
+		store, err := history.OpenPostgres(historySettings.Postgres.DSN)
+		if err != nil {
+			return nil, err
+		}
+		if verbose {
+			fmt.Fprintln(stderr, "Recording history in the Postgres database")
+		}
+		return store, nil
+	default:
+		return nil, customErrors.NewValidationError("", "history.backend",
+			fmt.Sprintf("unknown history backend %q; use sqlite, dynamodb or postgres%s", backend,
+				customErrors.DidYouMean(backend, []string{"sqlite", "dynamodb", "postgres"})), nil)
+	}
+}
+
+// openSQLiteHistory opens the SQLite history database, at --history or
+// history.path when given
+func openSQLiteHistory() (history.Store, error) {
+	path := historyFile
+	if path == "" && !mockMode {
+		path = historySettings.Path
+	}
+	if path == "" {
+		path = appDirs().HistoryDB()
+	}
+
+	db, err := history.OpenDB(path)
+	if err != nil {
+		return nil, err
+	}
+	if verbose {
+		fmt.Fprintf(stderr, "Recording history in %s\n", path)
+	}
+	return db, nil
+}
+
+// openCommandHistory opens the history for a one-off check. History is a
+// convenience there, so a failure only produces a warning.
+func openCommandHistory(ctx context.Context) history.Store {
+	store, err := openHistory(ctx)
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: checks will not be recorded: %v\n", err)
+		return nil
+	}
+	return store
+}
+
+// recordHistory records the results of report in store, and the prices of
+// the TLDs checked with pricing when store keeps prices, warning on failure
+func recordHistory(store history.Store, report *monitor.Report) {
+	if store == nil {
+		return
+	}
+
+	records := make([]history.Record, 0, len(report.Results))
+	for _, result := range report.Results {
+		// Reused results are already in the history, and skipped
+		// domains were never checked
+		if !result.FromHistory && !result.Skipped {
+			record := history.NewRecord(report.RunID, result)
+			record.Tags = report.Tags
+			records = append(records, record)
+		}
+	}
+	if err := store.Append(records); err != nil {
+		fmt.Fprintf(stderr, "Warning: checks were not recorded: %v\n", err)
+	}
+	if prices, ok := store.(history.PriceStore); ok {
+		if _, err := history.RecordPrices(prices, history.ResultPrices(report.Results)); err != nil {
+			fmt.Fprintf(stderr, "Warning: prices were not recorded: %v\n", err)
+		}
+	}
+}
+
+// historyKey returns the name a domain is recorded under in history, which
+// is the ASCII form of internationalized names
+func historyKey(name string) string {
+	if ascii, err := domain.ToASCII(name); err == nil {
+		return ascii
+	}
+	return name
+}
+
+// recentResults returns the latest results in store of the domains checked
+// within maxAge, keyed by historyKey, for bulk runs to reuse. An available
+// domain recorded without pricing is not reused when --price asks for it.
+// History is a convenience for one-off checks, so a failure to read it only
+// produces a warning.
+func recentResults(store history.Store, domains []string, maxAge time.Duration) map[string]*domain.AvailabilityResult {
+	if store == nil || maxAge <= 0 {
+		return nil
+	}
+
+	latest, err := store.Latest()
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: checking every domain, the history could not be read: %v\n", err)
+		return nil
+	}
+
+	recent := make(map[string]*domain.AvailabilityResult)
+	for _, name := range domains {
+		record, ok := latest[historyKey(name)]
+		if !ok || time.Since(record.CheckedAt) >= maxAge {
+			continue
+		}
+		if price && record.Available && record.Pricing == nil {
+			continue
+		}
+		recent[historyKey(name)] = record.Result()
+	}
+	return recent
+}
+
+// mergeRecentResults combines the reused results with the results of the
+// unchecked domains, which were checked in the same order, into the
+// results of every domain in input order
+func mergeRecentResults(domains []string, recent map[string]*domain.AvailabilityResult, checked []*domain.AvailabilityResult) []*domain.AvailabilityResult {
+	results := make([]*domain.AvailabilityResult, 0, len(domains))
+	for _, name := range domains {
+		if result := recent[historyKey(name)]; result != nil {
+			results = append(results, result)
+			continue
+		}
+		if len(checked) > 0 {
+			results = append(results, checked[0])
+			checked = checked[1:]
+		}
+	}
+	return results
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/aws"
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/input"
+)
+
+// fromHostedZones is --from-hosted-zones: check the domains of the account's
+// hosted zones instead of a list
+var fromHostedZones bool
+
+// zoneSource lists the account's hosted zones and registered domains
+type zoneSource interface {
+	HostedZones(ctx context.Context) ([]string, error)
+	RegisteredDomains(ctx context.Context) ([]string, error)
+}
+
+// newZoneSource creates the zone source of --from-hosted-zones; tests
+// replace it
+var newZoneSource = func(ctx context.Context) (zoneSource, error) {
+	awsConfig, err := loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return aws.NewZones(awsConfig), nil
+}
+
+// hostedZoneEntries returns the registrable domain of each public hosted zone
+// that is not registered to the account with Route 53 Domains, noting the
+// zones it was found in. Those are the zones that may be dangling: their
+// domain is registered elsewhere, or to no one.
+func hostedZoneEntries(ctx context.Context) ([]input.Entry, error) {
+	if mockMode {
+		return nil, customErrors.NewValidationError("", "from-hosted-zones", "--from-hosted-zones lists the hosted zones of an AWS account, which --mock has none of", nil)
+	}
+
+	source, err := newZoneSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	zones, err := source.HostedZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	registered, err := source.RegisteredDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inAccount := make(map[string]bool, len(registered))
+	for _, name := range registered {
+		inAccount[strings.ToLower(name)] = true
+	}
+
+	var entries []input.Entry
+	zonesOf := make(map[string][]string)
+	for _, zone := range zones {
+		name, _ := domain.NormalizeDomain(zone)
+		if name == "" || inAccount[name] {
+			continue
+		}
+		if _, seen := zonesOf[name]; !seen {
+			entries = append(entries, input.Entry{Domain: name})
+		}
+		zonesOf[name] = append(zonesOf[name], zone)
+	}
+	for i, entry := range entries {
+		entries[i].Note = "hosted zone " + strings.Join(zonesOf[entry.Domain], ", ")
+	}
+
+	if verbose {
+		fmt.Fprintf(stderr, "Found %s; checking the %s not registered in this account\n",
+			pluralize(len(zones), "public hosted zone"), pluralize(len(entries), "domain"))
+	}
+	return entries, nil
+}
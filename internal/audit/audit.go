@@ -0,0 +1,151 @@
+// Package audit cross-references the domains registered to an AWS account
+// with the name servers they are delegated to and the account's hosted
+// zones. A domain delegated to Route 53 name servers that serve none of the
+// account's zones for it, such as those of a deleted zone, is a takeover
+// risk: anyone who creates a hosted zone for the domain on those servers
+// answers for it. AuditPortfolio adds the renewal, transfer lock, expiry,
+// DNSSEC and contact settings of each domain to the delegation check.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/aws"
+)
+
+// Status is the outcome of auditing a domain's delegation
+type Status string
+
+const (
+	// StatusOK means the domain is delegated to a hosted zone of the account
+	StatusOK Status = "OK"
+	// StatusDanglingZone means the domain is delegated to Route 53 name
+	// servers that serve none of the account's hosted zones for it
+	StatusDanglingZone Status = "DANGLING_ZONE"
+	// StatusThirdParty means the domain is delegated to name servers outside
+	// Route 53, which must still host its zone
+	StatusThirdParty Status = "THIRD_PARTY_NS"
+	// StatusNoNameServers means the domain is not delegated, so it does not resolve
+	StatusNoNameServers Status = "NO_NAMESERVERS"
+	// StatusError means the domain's delegation could not be read
+	StatusError Status = "ERROR"
+)
+
+// AtRisk reports whether status is a takeover risk
+func (s Status) AtRisk() bool {
+	return s == StatusDanglingZone
+}
+
+// Source reads the account's domains, their delegation and hosted zones
+type Source interface {
+	RegisteredDomains(ctx context.Context) ([]string, error)
+	DomainNameServers(ctx context.Context, domain string) ([]string, error)
+	HostedZoneDelegations(ctx context.Context) ([]aws.HostedZone, error)
+}
+
+// Finding is the audit of a domain
+type Finding struct {
+	Domain      string   `json:"domain"`
+	Status      Status   `json:"status"`
+	NameServers []string `json:"name_servers,omitempty"`
+	// Zones are the IDs of the account's hosted zones for the domain
+	Zones   []string `json:"hosted_zones,omitempty"`
+	Message string   `json:"message"`
+}
+
+// Audit audits every domain registered to the account, in the order
+// Route 53 Domains lists them. A domain whose delegation cannot be read is
+// reported with StatusError rather than failing the audit.
+func Audit(ctx context.Context, source Source) ([]Finding, error) {
+	domains, err := source.RegisteredDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	zones, err := source.HostedZoneDelegations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	zonesByName := make(map[string][]aws.HostedZone)
+	for _, zone := range zones {
+		name := strings.ToLower(zone.Name)
+		zonesByName[name] = append(zonesByName[name], zone)
+	}
+
+	findings := make([]Finding, 0, len(domains))
+	for _, name := range domains {
+		name = strings.ToLower(name)
+		servers, err := source.DomainNameServers(ctx, name)
+		if err != nil {
+			findings = append(findings, Finding{Domain: name, Status: StatusError, Message: err.Error()})
+			continue
+		}
+		findings = append(findings, Check(name, servers, zonesByName[name]))
+	}
+	return findings, nil
+}
+
+// Check audits a domain delegated to servers against the account's hosted
+// zones for it
+func Check(name string, servers []string, zones []aws.HostedZone) Finding {
+	finding := Finding{Domain: name, NameServers: servers}
+	for _, zone := range zones {
+		finding.Zones = append(finding.Zones, zone.ID)
+	}
+
+	if len(servers) == 0 {
+		finding.Status = StatusNoNameServers
+		finding.Message = "not delegated to any name servers, so the domain does not resolve"
+		return finding
+	}
+
+	var route53, others, unserved []string
+	for _, server := range servers {
+		if !IsRoute53NameServer(server) {
+			others = append(others, server)
+			continue
+		}
+		route53 = append(route53, server)
+		if !slices.ContainsFunc(zones, func(zone aws.HostedZone) bool { return slices.Contains(zone.NameServers, server) }) {
+			unserved = append(unserved, server)
+		}
+	}
+
+	switch {
+	case len(unserved) > 0:
+		finding.Status = StatusDanglingZone
+		finding.Message = fmt.Sprintf("delegated to Route 53 name servers that serve none of this account's hosted zones for the domain (%s); a zone created on them by anyone answers for it",
+			strings.Join(unserved, ", "))
+		if len(zones) == 0 {
+			finding.Message += "; the account has no hosted zone for the domain"
+		}
+	case len(others) > 0:
+		finding.Status = StatusThirdParty
+		finding.Message = fmt.Sprintf("delegated outside Route 53 to %s; make sure the provider still hosts the zone", strings.Join(others, ", "))
+		if len(route53) == 0 && len(zones) > 0 {
+			finding.Message += fmt.Sprintf("; hosted zone %s is not used", strings.Join(finding.Zones, ", "))
+		}
+	default:
+		finding.Status = StatusOK
+		finding.Message = "delegated to hosted zone " + zoneServing(zones, route53)
+	}
+	return finding
+}
+
+// zoneServing returns the ID of the zone whose name servers include servers
+func zoneServing(zones []aws.HostedZone, servers []string) string {
+	for _, zone := range zones {
+		if slices.Contains(zone.NameServers, servers[0]) {
+			return zone.ID
+		}
+	}
+	return ""
+}
+
+// IsRoute53NameServer reports whether server is a Route 53 name server, such
+// as ns-123.awsdns-45.com
+func IsRoute53NameServer(server string) bool {
+	return strings.Contains(strings.ToLower(server), ".awsdns-")
+}
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/abakermi/r53check/internal/aws"
+)
+
+// fakeSource answers from fixed domains, delegations and zones
+type fakeSource struct {
+	domains     []string
+	nameServers map[string][]string
+	zones       []aws.HostedZone
+	err         error
+}
+
+func (f fakeSource) RegisteredDomains(ctx context.Context) ([]string, error) {
+	return f.domains, f.err
+}
+
+func (f fakeSource) DomainNameServers(ctx context.Context, domain string) ([]string, error) {
+	servers, ok := f.nameServers[domain]
+	if !ok {
+		return nil, errors.New("domain detail unavailable")
+	}
+	return servers, nil
+}
+
+func (f fakeSource) HostedZoneDelegations(ctx context.Context) ([]aws.HostedZone, error) {
+	return f.zones, nil
+}
+
+func TestAudit(t *testing.T) {
+	source := fakeSource{
+		domains: []string{"ok.com", "Deleted.com", "recreated.com", "external.io", "mixed.io", "parked.net", "broken.org"},
+		nameServers: map[string][]string{
+			"ok.com":        {"ns-1.awsdns-01.org", "ns-2.awsdns-02.com"},
+			"deleted.com":   {"ns-3.awsdns-03.net"},
+			"recreated.com": {"ns-4.awsdns-04.co.uk"},
+			"external.io":   {"ns1.cloudflare.com", "ns2.cloudflare.com"},
+			"mixed.io":      {"ns-5.awsdns-05.org", "ns1.other-dns.net"},
+			"parked.net":    nil,
+		},
+		zones: []aws.HostedZone{
+			{ID: "Z1", Name: "ok.com", NameServers: []string{"ns-1.awsdns-01.org", "ns-2.awsdns-02.com"}},
+			{ID: "Z2", Name: "recreated.com", NameServers: []string{"ns-9.awsdns-09.org"}},
+			{ID: "Z3", Name: "external.io", NameServers: []string{"ns-6.awsdns-06.org"}},
+			{ID: "Z4", Name: "mixed.io", NameServers: []string{"ns-5.awsdns-05.org"}},
+		},
+	}
+
+	findings, err := Audit(context.Background(), source)
+	if err != nil {
+		t.Fatalf("Audit() error: %v", err)
+	}
+
+	expected := []struct {
+		status  Status
+		message string
+	}{
+		{StatusOK, "delegated to hosted zone Z1"},
+		{StatusDanglingZone, "the account has no hosted zone for the domain"},
+		{StatusDanglingZone, "(ns-4.awsdns-04.co.uk)"},
+		{StatusThirdParty, "hosted zone Z3 is not used"},
+		{StatusThirdParty, "to ns1.other-dns.net;"},
+		{StatusNoNameServers, "does not resolve"},
+		{StatusError, "domain detail unavailable"},
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %+v", len(expected), findings)
+	}
+	for i, want := range expected {
+		finding := findings[i]
+		if finding.Status != want.status || !strings.Contains(finding.Message, want.message) {
+			t.Errorf("%s: expected %s containing %q, got %s: %s", finding.Domain, want.status, want.message, finding.Status, finding.Message)
+		}
+	}
+	if findings[1].Domain != "deleted.com" || !findings[1].Status.AtRisk() || findings[3].Status.AtRisk() {
+		t.Errorf("Expected only dangling zones to be at risk, got %+v", findings)
+	}
+
+	if _, err := Audit(context.Background(), fakeSource{err: errors.New("access denied")}); err == nil {
+		t.Error("Expected an error when the domains cannot be listed")
+	}
+}
@@ -0,0 +1,222 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abakermi/r53check/internal/aws"
+)
+
+// PortfolioCheck names a check of the portfolio audit
+type PortfolioCheck string
+
+const (
+	// CheckAutoRenew flags domains that will not renew automatically
+	CheckAutoRenew PortfolioCheck = "auto_renew"
+
+	// CheckTransferLock flags domains that can be transferred away
+	CheckTransferLock PortfolioCheck = "transfer_lock"
+
+	// CheckExpiry flags domains expiring within the expiry window
+	CheckExpiry PortfolioCheck = "expiry"
+
+	// CheckDNSSEC flags domains without DNSSEC keys at the registry
+	CheckDNSSEC PortfolioCheck = "dnssec"
+
+	// CheckContactEmail flags contact emails likely to bounce
+	CheckContactEmail PortfolioCheck = "contact_email"
+
+	// CheckDelegation flags dangling or missing delegations, as audit-dns does
+	CheckDelegation PortfolioCheck = "delegation"
+)
+
+// PortfolioChecks are the portfolio audit checks, in the order issues are listed
+var PortfolioChecks = []PortfolioCheck{CheckExpiry, CheckAutoRenew, CheckTransferLock, CheckDelegation, CheckDNSSEC, CheckContactEmail}
+
+// PortfolioSource reads the account's domains with their settings, and its
+// hosted zones. *aws.Zones implements it.
+type PortfolioSource interface {
+	DomainSummaries(ctx context.Context) ([]aws.DomainSummary, error)
+	DomainDetail(ctx context.Context, domain string) (aws.DomainDetail, error)
+	HostedZoneDelegations(ctx context.Context) ([]aws.HostedZone, error)
+}
+
+// PortfolioOptions configures a portfolio audit
+type PortfolioOptions struct {
+	// ExpiryWindow is how soon before expiring a domain is flagged
+	ExpiryWindow time.Duration
+
+	// Skip are the checks left out
+	Skip []PortfolioCheck
+
+	// Now is the time expiry is measured from
+	Now time.Time
+}
+
+// Issue is a risky state a portfolio audit found on a domain
+type Issue struct {
+	Check   PortfolioCheck `json:"check"`
+	Message string         `json:"message"`
+}
+
+// DomainAudit is the portfolio audit of a domain
+type DomainAudit struct {
+	Domain       string    `json:"domain"`
+	AutoRenew    bool      `json:"auto_renew"`
+	TransferLock bool      `json:"transfer_lock"`
+	Expiry       time.Time `json:"expiry"`
+	DNSSEC       bool      `json:"dnssec"`
+	Delegation   Status    `json:"delegation,omitempty"`
+	Issues       []Issue   `json:"issues,omitempty"`
+
+	// Error is why the detail of the domain could not be read; the checks
+	// needing it were not made
+	Error string `json:"error,omitempty"`
+}
+
+// OK reports whether the audit found nothing to flag
+func (a DomainAudit) OK() bool {
+	return a.Error == "" && len(a.Issues) == 0
+}
+
+// AuditPortfolio audits the renewal, transfer lock, expiry, delegation,
+// DNSSEC and contact emails of every domain registered to the account,
+// soonest to expire first. A domain whose detail cannot be read is reported
+// with the error rather than failing the audit.
+func AuditPortfolio(ctx context.Context, source PortfolioSource, options PortfolioOptions) ([]DomainAudit, error) {
+	summaries, err := source.DomainSummaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	zonesByName := make(map[string][]aws.HostedZone)
+	if !slices.Contains(options.Skip, CheckDelegation) {
+		zones, err := source.HostedZoneDelegations(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, zone := range zones {
+			name := strings.ToLower(zone.Name)
+			zonesByName[name] = append(zonesByName[name], zone)
+		}
+	}
+
+	audits := make([]DomainAudit, 0, len(summaries))
+	for _, summary := range summaries {
+		name := strings.ToLower(summary.Name)
+		result := DomainAudit{Domain: name, AutoRenew: summary.AutoRenew, TransferLock: summary.TransferLock, Expiry: summary.Expiry}
+		var issues []Issue
+
+		if !summary.Expiry.IsZero() && summary.Expiry.Sub(options.Now) < options.ExpiryWindow {
+			days := int(summary.Expiry.Sub(options.Now).Hours() / 24)
+			message := fmt.Sprintf("expires in %d days, on %s", days, summary.Expiry.Format("2006-01-02"))
+			if days < 0 {
+				message = "expired on " + summary.Expiry.Format("2006-01-02")
+			}
+			if !summary.AutoRenew {
+				message += ", and will not renew automatically"
+			}
+			issues = append(issues, Issue{CheckExpiry, message})
+		}
+		if !summary.AutoRenew {
+			issues = append(issues, Issue{CheckAutoRenew, "auto-renewal is off, so the domain is released when it expires"})
+		}
+		if !summary.TransferLock {
+			issues = append(issues, Issue{CheckTransferLock, "the transfer lock is off, so the domain can be transferred to another registrar"})
+		}
+
+		detail, err := source.DomainDetail(ctx, name)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.DNSSEC = detail.DNSSEC
+			delegation := Check(name, detail.NameServers, zonesByName[name])
+			result.Delegation = delegation.Status
+			if delegation.Status.AtRisk() || delegation.Status == StatusNoNameServers {
+				issues = append(issues, Issue{CheckDelegation, delegation.Message})
+			}
+			if !detail.DNSSEC {
+				issues = append(issues, Issue{CheckDNSSEC, "DNSSEC is not enabled, so answers for the domain can be spoofed"})
+			}
+			issues = append(issues, contactEmailIssues(name, detail.ContactEmails)...)
+		}
+
+		for _, issue := range issues {
+			if !slices.Contains(options.Skip, issue.Check) {
+				result.Issues = append(result.Issues, issue)
+			}
+		}
+		audits = append(audits, result)
+	}
+
+	sort.SliceStable(audits, func(i, j int) bool {
+		if audits[i].Expiry.IsZero() || audits[j].Expiry.IsZero() {
+			return !audits[i].Expiry.IsZero()
+		}
+		return audits[i].Expiry.Before(audits[j].Expiry)
+	})
+	return audits, nil
+}
+
+// contactEmailIssues flags the contact emails of a domain that are likely
+// to bounce, in role order
+func contactEmailIssues(domain string, emails map[string]string) []Issue {
+	var issues []Issue
+	for _, role := range []string{"registrant", "admin", "tech"} {
+		email, ok := emails[role]
+		if !ok {
+			continue
+		}
+		if reason := EmailRisk(email, domain); reason != "" {
+			issues = append(issues, Issue{CheckContactEmail, fmt.Sprintf("the %s email %s %s", role, email, reason)})
+		}
+	}
+	return issues
+}
+
+// reservedEmailDomains are domains reserved for documentation and testing,
+// which never receive mail
+var reservedEmailDomains = []string{"example.com", "example.net", "example.org"}
+
+// reservedEmailTLDs are the TLDs reserved by RFC 2606, which never receive
+// mail
+var reservedEmailTLDs = []string{"test", "example", "invalid", "localhost"}
+
+// noReplyMarkers are parts of local parts used by addresses no one reads
+var noReplyMarkers = []string{"noreply", "no-reply", "no_reply", "donotreply", "do-not-reply", "do_not_reply"}
+
+// EmailRisk explains why email, a contact of domain, is likely to bounce or
+// go unread, or returns "" when nothing is wrong with it. An address at the
+// domain itself is flagged, since the renewal and transfer notices sent to
+// it stop arriving once the domain lapses.
+func EmailRisk(email, domain string) string {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != strings.TrimSpace(email) {
+		return "is not a valid address"
+	}
+	at := strings.LastIndex(address.Address, "@")
+	local, host := strings.ToLower(address.Address[:at]), strings.ToLower(address.Address[at+1:])
+	if !strings.Contains(host, ".") {
+		return "has no valid mail domain"
+	}
+
+	tld := host[strings.LastIndex(host, ".")+1:]
+	if slices.Contains(reservedEmailTLDs, tld) || slices.ContainsFunc(reservedEmailDomains, func(reserved string) bool {
+		return host == reserved || strings.HasSuffix(host, "."+reserved)
+	}) {
+		return "is at a reserved domain that receives no mail"
+	}
+	for _, marker := range noReplyMarkers {
+		if strings.Contains(local, marker) {
+			return "is a no-reply address"
+		}
+	}
+	if host == domain || strings.HasSuffix(host, "."+domain) {
+		return "is at the domain itself, so notices stop arriving if it lapses"
+	}
+	return ""
+}
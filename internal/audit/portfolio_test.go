@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/aws"
+)
+
+// fakePortfolio answers from fixed summaries, details and zones
+type fakePortfolio struct {
+	summaries []aws.DomainSummary
+	details   map[string]aws.DomainDetail
+	zones     []aws.HostedZone
+	err       error
+}
+
+func (f fakePortfolio) DomainSummaries(ctx context.Context) ([]aws.DomainSummary, error) {
+	return f.summaries, f.err
+}
+
+func (f fakePortfolio) DomainDetail(ctx context.Context, domain string) (aws.DomainDetail, error) {
+	detail, ok := f.details[domain]
+	if !ok {
+		return aws.DomainDetail{}, errors.New("domain detail unavailable")
+	}
+	return detail, nil
+}
+
+func (f fakePortfolio) HostedZoneDelegations(ctx context.Context) ([]aws.HostedZone, error) {
+	return f.zones, nil
+}
+
+func TestAuditPortfolio(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	route53 := []string{"ns-1.awsdns-01.org"}
+	source := fakePortfolio{
+		summaries: []aws.DomainSummary{
+			{Name: "safe.com", AutoRenew: true, TransferLock: true, Expiry: now.AddDate(1, 0, 0)},
+			{Name: "Lapsing.com", TransferLock: true, Expiry: now.AddDate(0, 0, 20)},
+			{Name: "unlocked.io", AutoRenew: true, Expiry: now.AddDate(0, 6, 0)},
+			{Name: "broken.org", AutoRenew: true, TransferLock: true, Expiry: now.AddDate(2, 0, 0)},
+		},
+		details: map[string]aws.DomainDetail{
+			"safe.com":    {NameServers: route53, DNSSEC: true, ContactEmails: map[string]string{"registrant": "owner@corp.net"}},
+			"lapsing.com": {NameServers: []string{"ns-9.awsdns-09.net"}, DNSSEC: true},
+			"unlocked.io": {NameServers: []string{"ns1.other-dns.net"}, ContactEmails: map[string]string{"registrant": "admin@unlocked.io", "tech": "noreply@corp.net"}},
+		},
+		zones: []aws.HostedZone{{ID: "Z1", Name: "safe.com", NameServers: route53}},
+	}
+
+	audits, err := AuditPortfolio(context.Background(), source, PortfolioOptions{ExpiryWindow: 60 * 24 * time.Hour, Now: now})
+	if err != nil {
+		t.Fatalf("AuditPortfolio() error: %v", err)
+	}
+
+	expected := []struct {
+		domain string
+		checks []PortfolioCheck
+		err    bool
+	}{
+		{"lapsing.com", []PortfolioCheck{CheckExpiry, CheckAutoRenew, CheckDelegation}, false},
+		{"unlocked.io", []PortfolioCheck{CheckTransferLock, CheckDNSSEC, CheckContactEmail, CheckContactEmail}, false},
+		{"safe.com", nil, false},
+		{"broken.org", nil, true},
+	}
+	if len(audits) != len(expected) {
+		t.Fatalf("Expected %d audits, got %+v", len(expected), audits)
+	}
+	for i, want := range expected {
+		audit := audits[i]
+		var checks []PortfolioCheck
+		for _, issue := range audit.Issues {
+			checks = append(checks, issue.Check)
+		}
+		if audit.Domain != want.domain || !reflect.DeepEqual(checks, want.checks) || (audit.Error != "") != want.err {
+			t.Errorf("Expected %s with %v (error %v), got %+v", want.domain, want.checks, want.err, audit)
+		}
+	}
+	if message := audits[0].Issues[0].Message; message != "expires in 20 days, on 2026-10-21, and will not renew automatically" {
+		t.Errorf("Unexpected expiry message %q", message)
+	}
+	if !audits[2].OK() || audits[3].OK() {
+		t.Error("Expected only the domain without issues or errors to be OK")
+	}
+
+	skipped, err := AuditPortfolio(context.Background(), source, PortfolioOptions{ExpiryWindow: 60 * 24 * time.Hour, Now: now, Skip: []PortfolioCheck{CheckDNSSEC, CheckContactEmail, CheckTransferLock}})
+	if err != nil {
+		t.Fatalf("AuditPortfolio() error: %v", err)
+	}
+	if len(skipped[1].Issues) != 0 {
+		t.Errorf("Expected the skipped checks to be left out, got %+v", skipped[1].Issues)
+	}
+
+	if _, err := AuditPortfolio(context.Background(), fakePortfolio{err: errors.New("access denied")}, PortfolioOptions{}); err == nil {
+		t.Error("Expected an error when the domains cannot be listed")
+	}
+}
+
+func TestEmailRisk(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"owner@corp.net", ""},
+		{"Owner@Sub.Corp.net", ""},
+		{"not-an-address", "is not a valid address"},
+		{"Ada <ada@corp.net>", "is not a valid address"},
+		{"root@localhost", "has no valid mail domain"},
+		{"ada@example.com", "is at a reserved domain that receives no mail"},
+		{"ada@mail.example.org", "is at a reserved domain that receives no mail"},
+		{"ada@corp.test", "is at a reserved domain that receives no mail"},
+		{"no-reply@corp.net", "is a no-reply address"},
+		{"DoNotReply.billing@corp.net", "is a no-reply address"},
+		{"hostmaster@brand.com", "is at the domain itself, so notices stop arriving if it lapses"},
+		{"ops@mail.brand.com", "is at the domain itself, so notices stop arriving if it lapses"},
+		{"ops@otherbrand.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if got := EmailRisk(tt.email, "brand.com"); got != tt.want {
+				t.Errorf("EmailRisk(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+	"github.com/aws/smithy-go"
+)
+
+// fakeRoute53DomainsAPI implements Route53DomainsAPI, recording the inputs it receives
+type fakeRoute53DomainsAPI struct {
+	checkOutput *route53domains.CheckDomainAvailabilityOutput
+	checkErr    error
+	pricePages  []*route53domains.ListPricesOutput
+	priceErr    error
+
+	checkInputs []*route53domains.CheckDomainAvailabilityInput
+	priceInputs []*route53domains.ListPricesInput
+}
+
+func (f *fakeRoute53DomainsAPI) CheckDomainAvailability(ctx context.Context, params *route53domains.CheckDomainAvailabilityInput, optFns ...func(*route53domains.Options)) (*route53domains.CheckDomainAvailabilityOutput, error) {
+	f.checkInputs = append(f.checkInputs, params)
+	return f.checkOutput, f.checkErr
+}
+
+func (f *fakeRoute53DomainsAPI) ListPrices(ctx context.Context, params *route53domains.ListPricesInput, optFns ...func(*route53domains.Options)) (*route53domains.ListPricesOutput, error) {
+	f.priceInputs = append(f.priceInputs, params)
+	if f.priceErr != nil {
+		return nil, f.priceErr
+	}
+	page := f.pricePages[len(f.priceInputs)-1]
+	return page, nil
+}
+
+func TestNewClientWithAPI_CheckDomainAvailability(t *testing.T) {
+	api := &fakeRoute53DomainsAPI{
+		checkOutput: &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable},
+	}
+	client := NewClientWithAPI(api)
+
+	result, err := client.CheckDomainAvailability(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Availability != types.DomainAvailabilityAvailable {
+		t.Errorf("expected AVAILABLE, got %s", result.Availability)
+	}
+	if len(api.checkInputs) != 1 || aws.ToString(api.checkInputs[0].DomainName) != "example.com" {
+		t.Errorf("expected one request for example.com, got %v", api.checkInputs)
+	}
+
+	available, err := client.IsAvailable(context.Background(), "example.com")
+	if err != nil || !available {
+		t.Errorf("expected IsAvailable to report true, got %v, %v", available, err)
+	}
+}
+
+func TestNewClientWithAPI_CheckDomainAvailabilityErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		domain        string
+		apiErr        error
+		expectedCalls int
+		checkErr      func(error) bool
+	}{
+		{
+			name:          "empty domain is rejected before calling AWS",
+			domain:        "",
+			expectedCalls: 0,
+			checkErr: func(err error) bool {
+				var validationErr *customErrors.ValidationError
+				return errors.As(err, &validationErr)
+			},
+		},
+		{
+			name:          "access denied is classified",
+			domain:        "example.com",
+			apiErr:        &smithy.GenericAPIError{Code: "AccessDenied", Message: "not allowed"},
+			expectedCalls: 1,
+			checkErr: func(err error) bool {
+				var authzErr *customErrors.AuthorizationError
+				return errors.As(err, &authzErr)
+			},
+		},
+		{
+			name:          "throttling is retryable",
+			domain:        "example.com",
+			apiErr:        &smithy.GenericAPIError{Code: "TooManyRequests", Message: "slow down"},
+			expectedCalls: 1,
+			checkErr:      customErrors.IsRetryable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeRoute53DomainsAPI{checkErr: tt.apiErr}
+			_, err := NewClientWithAPI(api).CheckDomainAvailability(context.Background(), tt.domain)
+
+			if err == nil || !tt.checkErr(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if len(api.checkInputs) != tt.expectedCalls {
+				t.Errorf("expected %d API calls, got %d", tt.expectedCalls, len(api.checkInputs))
+			}
+		})
+	}
+}
+
+func TestNewClientWithAPI_ListPrices(t *testing.T) {
+	api := &fakeRoute53DomainsAPI{
+		pricePages: []*route53domains.ListPricesOutput{{
+			Prices: []types.DomainPrice{{Name: aws.String("com")}},
+		}},
+	}
+
+	result, err := NewClientWithAPI(api).ListPrices(context.Background(), "com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Prices) != 1 || aws.ToString(api.priceInputs[0].Tld) != "com" {
+		t.Errorf("unexpected result %v for inputs %v", result.Prices, api.priceInputs)
+	}
+
+	if _, err := NewClientWithAPI(api).ListPrices(context.Background(), ""); err == nil {
+		t.Error("expected an error for an empty TLD")
+	}
+}
+
+func TestNewClientWithAPI_ListTLDsPaginates(t *testing.T) {
+	api := &fakeRoute53DomainsAPI{
+		pricePages: []*route53domains.ListPricesOutput{
+			{
+				Prices:         []types.DomainPrice{{Name: aws.String("com")}, {Name: aws.String("net")}},
+				NextPageMarker: aws.String("page-2"),
+			},
+			{
+				Prices: []types.DomainPrice{{Name: aws.String("io")}, {Name: nil}},
+			},
+		},
+	}
+
+	tlds, err := NewClientWithAPI(api).ListTLDs(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tlds) != 3 || tlds[0] != "com" || tlds[2] != "io" {
+		t.Errorf("expected [com net io], got %v", tlds)
+	}
+	if len(api.priceInputs) != 2 || aws.ToString(api.priceInputs[1].Marker) != "page-2" {
+		t.Errorf("expected the second request to use the page marker, got %v", api.priceInputs)
+	}
+
+	failing := &fakeRoute53DomainsAPI{priceErr: errors.New("network down")}
+	if _, err := NewClientWithAPI(failing).ListTLDs(context.Background()); err == nil {
+		t.Error("expected an error when a page fails")
+	}
+}
+
+func TestClient_Ping(t *testing.T) {
+	api := &fakeRoute53DomainsAPI{pricePages: []*route53domains.ListPricesOutput{{}}}
+	if err := NewClientWithAPI(api).Ping(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := aws.ToInt32(api.priceInputs[0].MaxItems); got != 1 {
+		t.Errorf("expected a single-item ListPrices call, got MaxItems %d", got)
+	}
+
+	api = &fakeRoute53DomainsAPI{priceErr: &smithy.GenericAPIError{Code: "AccessDenied", Message: "denied"}}
+	var authzErr *customErrors.AuthorizationError
+	if err := NewClientWithAPI(api).Ping(context.Background()); !errors.As(err, &authzErr) {
+		t.Errorf("expected an authorization error, got %v", err)
+	}
+}
+
+func TestClient_CheckCredentials(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials aws.CredentialsProvider
+		expectError bool
+	}{
+		{"no provider", nil, false},
+		{"valid", aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+		}), false},
+		{"retrieve fails", aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{}, errors.New("no valid providers in chain")
+		}), true},
+		{"expired", aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKID", CanExpire: true, Expires: time.Now().Add(-time.Minute)}, nil
+		}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{credentials: tt.credentials}
+			err := client.CheckCredentials(context.Background())
+
+			var authErr *customErrors.AuthenticationError
+			if tt.expectError && !errors.As(err, &authErr) {
+				t.Errorf("expected an authentication error, got %v", err)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
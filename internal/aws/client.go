@@ -16,15 +16,31 @@ type Route53Client interface {
 	ListPrices(ctx context.Context, tld string) (*route53domains.ListPricesOutput, error)
 }
 
+// Route53DomainsAPI is the part of the AWS SDK Route 53 Domains client that
+// Client calls. *route53domains.Client implements it; tests and callers can
+// supply their own implementation, for example to add instrumentation.
+type Route53DomainsAPI interface {
+	CheckDomainAvailability(ctx context.Context, params *route53domains.CheckDomainAvailabilityInput, optFns ...func(*route53domains.Options)) (*route53domains.CheckDomainAvailabilityOutput, error)
+	ListPrices(ctx context.Context, params *route53domains.ListPricesInput, optFns ...func(*route53domains.Options)) (*route53domains.ListPricesOutput, error)
+}
+
 // Client wraps the AWS Route 53 Domains client
 type Client struct {
-	route53Client *route53domains.Client
+	route53Client Route53DomainsAPI
+	credentials   aws.CredentialsProvider
 }
 
 // NewClient creates a new Route 53 client wrapper
 func NewClient(cfg *aws.Config) *Client {
+	client := NewClientWithAPI(route53domains.NewFromConfig(*cfg))
+	client.credentials = cfg.Credentials
+	return client
+}
+
+// NewClientWithAPI creates a Route 53 client wrapper around an existing SDK client
+func NewClientWithAPI(api Route53DomainsAPI) *Client {
 	return &Client{
-		route53Client: route53domains.NewFromConfig(*cfg),
+		route53Client: api,
 	}
 }
 
@@ -64,6 +80,48 @@ func (c *Client) ListPrices(ctx context.Context, tld string) (*route53domains.Li
 	return result, nil
 }
 
+// ListTLDs returns the name of every TLD Route 53 Domains supports, following
+// ListPrices pagination until the full catalog has been read
+func (c *Client) ListTLDs(ctx context.Context) ([]string, error) {
+	paginator := route53domains.NewListPricesPaginator(c.route53Client, &route53domains.ListPricesInput{
+		MaxItems: aws.Int32(1000),
+	})
+
+	var tlds []string
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.WrapAWSError(err, "route53domains", "ListPrices")
+		}
+		for _, price := range page.Prices {
+			if price.Name != nil {
+				tlds = append(tlds, *price.Name)
+			}
+		}
+	}
+
+	return tlds, nil
+}
+
+// ListTLDPrices returns the prices of every TLD Route 53 Domains supports,
+// following ListPrices pagination until the full price list has been read
+func (c *Client) ListTLDPrices(ctx context.Context) ([]types.DomainPrice, error) {
+	paginator := route53domains.NewListPricesPaginator(c.route53Client, &route53domains.ListPricesInput{
+		MaxItems: aws.Int32(1000),
+	})
+
+	var prices []types.DomainPrice
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.WrapAWSError(err, "route53domains", "ListPrices")
+		}
+		prices = append(prices, page.Prices...)
+	}
+
+	return prices, nil
+}
+
 // IsAvailable is a convenience method that returns true if the domain is available
 func (c *Client) IsAvailable(ctx context.Context, domain string) (bool, error) {
 	result, err := c.CheckDomainAvailability(ctx, domain)
@@ -73,3 +131,33 @@ func (c *Client) IsAvailable(ctx context.Context, domain string) (bool, error) {
 
 	return result.Availability == types.DomainAvailabilityAvailable, nil
 }
+
+// CheckCredentials verifies that AWS credentials can be retrieved and have
+// not expired. Clients created with NewClientWithAPI leave credentials to the
+// injected API and always pass.
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	if c.credentials == nil {
+		return nil
+	}
+
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return errors.NewAuthenticationError("aws-sdk", "unable to retrieve AWS credentials", err)
+	}
+	if creds.Expired() {
+		return errors.NewAuthenticationError("aws-sdk", "AWS credentials have expired", nil)
+	}
+	return nil
+}
+
+// Ping verifies that Route 53 Domains is reachable with the configured
+// credentials by listing a single TLD price
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.route53Client.ListPrices(ctx, &route53domains.ListPricesInput{
+		MaxItems: aws.Int32(1),
+	})
+	if err != nil {
+		return errors.WrapAWSError(err, "route53domains", "ListPrices")
+	}
+	return nil
+}
@@ -2,13 +2,19 @@ package aws
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	"github.com/abakermi/r53check/internal/errors"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 )
 
+// defaultRegion is used when no region is given, as Route 53 Domains API is only available there
+const defaultRegion = "us-east-1"
+
 // Config holds AWS configuration settings
 type Config struct {
 	Region string
@@ -18,7 +24,7 @@ type Config struct {
 // This supports environment variables, shared credentials file, and IAM roles
 // Defaults to us-east-1 region as Route 53 Domains API is only available there
 func NewConfig(ctx context.Context) (*aws.Config, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(defaultRegion))
 	if err != nil {
 		return nil, errors.WrapAWSError(err, "config", "LoadDefaultConfig")
 	}
@@ -35,3 +41,67 @@ func NewConfigWithRegion(ctx context.Context, region string) (*aws.Config, error
 
 	return &cfg, nil
 }
+
+// HTTPOptions tunes the connection pool of the HTTP client shared by all AWS clients
+type HTTPOptions struct {
+	MaxIdleConns        int           // Idle connections kept open, in total and per host
+	IdleConnTimeout     time.Duration // How long an idle connection stays in the pool
+	TLSHandshakeTimeout time.Duration // Maximum time to wait for a TLS handshake
+}
+
+// DefaultHTTPOptions returns the SDK's pool defaults with the per-host limit
+// raised to match the total, since every request goes to the same endpoint
+func DefaultHTTPOptions() HTTPOptions {
+	return HTTPOptions{
+		MaxIdleConns:        awshttp.DefaultHTTPTransportMaxIdleConns,
+		IdleConnTimeout:     awshttp.DefaultHTTPTransportIdleConnTimeout,
+		TLSHandshakeTimeout: awshttp.DefaultHTTPTransportTLSHandleshakeTimeout,
+	}
+}
+
+// NewHTTPClient builds a tuned HTTP client to be shared by every AWS client.
+// Zero values in opts keep the SDK defaults.
+func NewHTTPClient(opts HTTPOptions) *awshttp.BuildableClient {
+	return awshttp.NewBuildableClient().WithTransportOptions(func(t *http.Transport) {
+		if opts.MaxIdleConns > 0 {
+			t.MaxIdleConns = opts.MaxIdleConns
+			t.MaxIdleConnsPerHost = opts.MaxIdleConns
+		}
+		if opts.IdleConnTimeout > 0 {
+			t.IdleConnTimeout = opts.IdleConnTimeout
+		}
+		if opts.TLSHandshakeTimeout > 0 {
+			t.TLSHandshakeTimeout = opts.TLSHandshakeTimeout
+		}
+	})
+}
+
+// NewConfigWithHTTPClient creates a new AWS configuration whose credential
+// providers and service clients all send requests through httpClient.
+// An empty region defaults to us-east-1.
+func NewConfigWithHTTPClient(ctx context.Context, region string, httpClient aws.HTTPClient) (*aws.Config, error) {
+	return NewConfigWithProfile(ctx, region, "", httpClient)
+}
+
+// NewConfigWithProfile is like NewConfigWithHTTPClient but loads credentials
+// from a named shared config profile. An empty profile uses the default chain.
+func NewConfigWithProfile(ctx context.Context, region, profile string, httpClient aws.HTTPClient) (*aws.Config, error) {
+	if region == "" {
+		region = defaultRegion
+	}
+
+	opts := []func(*config.LoadOptions) error{
+		config.WithRegion(region),
+		config.WithHTTPClient(httpClient),
+	}
+	if profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, errors.WrapAWSError(err, "config", "LoadDefaultConfig")
+	}
+
+	return &cfg, nil
+}
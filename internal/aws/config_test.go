@@ -3,7 +3,11 @@ package aws
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 )
 
 func TestNewConfig(t *testing.T) {
@@ -147,3 +151,113 @@ func TestNewConfigWithRegion(t *testing.T) {
 		})
 	}
 }
+
+func TestNewHTTPClient(t *testing.T) {
+	tests := []struct {
+		name                string
+		opts                HTTPOptions
+		expectedMaxIdle     int
+		expectedPerHost     int
+		expectedIdleTimeout time.Duration
+		expectedTLSTimeout  time.Duration
+	}{
+		{
+			name:                "defaults raise the per-host limit",
+			opts:                DefaultHTTPOptions(),
+			expectedMaxIdle:     100,
+			expectedPerHost:     100,
+			expectedIdleTimeout: 90 * time.Second,
+			expectedTLSTimeout:  10 * time.Second,
+		},
+		{
+			name: "custom settings",
+			opts: HTTPOptions{
+				MaxIdleConns:        32,
+				IdleConnTimeout:     30 * time.Second,
+				TLSHandshakeTimeout: 5 * time.Second,
+			},
+			expectedMaxIdle:     32,
+			expectedPerHost:     32,
+			expectedIdleTimeout: 30 * time.Second,
+			expectedTLSTimeout:  5 * time.Second,
+		},
+		{
+			name:                "zero values keep SDK defaults",
+			opts:                HTTPOptions{},
+			expectedMaxIdle:     100,
+			expectedPerHost:     10,
+			expectedIdleTimeout: 90 * time.Second,
+			expectedTLSTimeout:  10 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := NewHTTPClient(tt.opts).GetTransport()
+
+			if transport.MaxIdleConns != tt.expectedMaxIdle {
+				t.Errorf("expected MaxIdleConns %d, got %d", tt.expectedMaxIdle, transport.MaxIdleConns)
+			}
+			if transport.MaxIdleConnsPerHost != tt.expectedPerHost {
+				t.Errorf("expected MaxIdleConnsPerHost %d, got %d", tt.expectedPerHost, transport.MaxIdleConnsPerHost)
+			}
+			if transport.IdleConnTimeout != tt.expectedIdleTimeout {
+				t.Errorf("expected IdleConnTimeout %v, got %v", tt.expectedIdleTimeout, transport.IdleConnTimeout)
+			}
+			if transport.TLSHandshakeTimeout != tt.expectedTLSTimeout {
+				t.Errorf("expected TLSHandshakeTimeout %v, got %v", tt.expectedTLSTimeout, transport.TLSHandshakeTimeout)
+			}
+		})
+	}
+}
+
+func TestNewConfigWithHTTPClient(t *testing.T) {
+	httpClient := NewHTTPClient(DefaultHTTPOptions())
+
+	cfg, err := NewConfigWithHTTPClient(context.Background(), "", httpClient)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Region != "us-east-1" {
+		t.Errorf("expected default region us-east-1, got %s", cfg.Region)
+	}
+	buildable, ok := cfg.HTTPClient.(*awshttp.BuildableClient)
+	if !ok {
+		t.Fatalf("expected a buildable HTTP client, got %T", cfg.HTTPClient)
+	}
+	if buildable.GetTransport().MaxIdleConnsPerHost != 100 {
+		t.Error("expected the tuned transport settings to be kept")
+	}
+
+	cfg, err = NewConfigWithHTTPClient(context.Background(), "eu-west-1", httpClient)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Region != "eu-west-1" {
+		t.Errorf("expected region eu-west-1, got %s", cfg.Region)
+	}
+}
+
+func TestNewConfigWithProfile(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config")
+	if err := os.WriteFile(configFile, []byte("[profile domains]\nregion = eu-west-1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+
+	httpClient := NewHTTPClient(DefaultHTTPOptions())
+
+	cfg, err := NewConfigWithProfile(context.Background(), "", "domains", httpClient)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Region != "us-east-1" {
+		t.Errorf("expected the region argument to take precedence, got %s", cfg.Region)
+	}
+
+	if _, err := NewConfigWithProfile(context.Background(), "", "missing", httpClient); err == nil {
+		t.Error("expected an error for a profile missing from the shared config")
+	}
+}
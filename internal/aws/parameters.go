@@ -0,0 +1,114 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/smithy-go"
+)
+
+// Parameters reads parameters from SSM Parameter Store. Only GetParameter
+// is needed, so it calls the JSON API of SSM directly, signing requests
+// with the credentials of the AWS config.
+type Parameters struct {
+	cfg      aws.Config
+	endpoint string
+	signer   *v4.Signer
+}
+
+// NewParameters creates a Parameters for the account and region of cfg
+func NewParameters(cfg *aws.Config) *Parameters {
+	endpoint := fmt.Sprintf("https://ssm.%s.amazonaws.com/", cfg.Region)
+	if cfg.BaseEndpoint != nil {
+		endpoint = *cfg.BaseEndpoint
+	}
+	return &Parameters{cfg: *cfg, endpoint: endpoint, signer: v4.NewSigner()}
+}
+
+// getParameterOutput is the part of the GetParameter response that is read
+type getParameterOutput struct {
+	Parameter struct {
+		Value string `json:"Value"`
+	} `json:"Parameter"`
+}
+
+// GetParameter returns the value of the parameter name, decrypting a
+// SecureString parameter
+func (p *Parameters) GetParameter(ctx context.Context, name string) (string, error) {
+	body, _ := json.Marshal(map[string]interface{}{"Name": name, "WithDecryption": true})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", errors.NewSystemError("ssm", "unable to create request", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AmazonSSM.GetParameter")
+
+	if p.cfg.Credentials == nil {
+		return "", errors.WrapAWSError(&smithy.GenericAPIError{Code: "NoCredentialsErr", Message: "no AWS credentials"}, "ssm", "GetParameter")
+	}
+	credentials, err := p.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", errors.WrapAWSError(err, "ssm", "GetParameter")
+	}
+	hash := sha256.Sum256(body)
+	if err := p.signer.SignHTTP(ctx, credentials, req, hex.EncodeToString(hash[:]), "ssm", p.cfg.Region, time.Now()); err != nil {
+		return "", errors.NewSystemError("ssm", "unable to sign request", err)
+	}
+
+	client := p.cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", errors.WrapAWSError(err, "ssm", "GetParameter")
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.WrapAWSError(err, "ssm", "GetParameter")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.WrapAWSError(apiError(resp.StatusCode, data), "ssm", "GetParameter")
+	}
+	var out getParameterOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", errors.NewSystemError("ssm", "invalid GetParameter response", err)
+	}
+	return out.Parameter.Value, nil
+}
+
+// apiError converts an error response of the JSON API, such as
+// {"__type": "ParameterNotFound", "message": "..."}, to the API error the
+// SDK would return
+func apiError(status int, data []byte) error {
+	var body struct {
+		Type    string `json:"__type"`
+		Message string `json:"message"`
+	}
+	json.Unmarshal(data, &body)
+
+	// The type may be qualified, as in "com.amazonaws.ssm#ParameterNotFound"
+	code := body.Type[strings.LastIndex(body.Type, "#")+1:]
+	if code == "" {
+		code = http.StatusText(status)
+	}
+	fault := smithy.FaultClient
+	if status >= 500 {
+		fault = smithy.FaultServer
+	}
+	return &smithy.GenericAPIError{Code: code, Message: body.Message, Fault: fault}
+}
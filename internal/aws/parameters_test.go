@@ -0,0 +1,54 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+)
+
+func TestParameters_GetParameter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "AmazonSSM.GetParameter" || !strings.Contains(r.Header.Get("Authorization"), "/us-east-1/ssm/aws4_request") {
+			t.Errorf("Unexpected request headers %v", r.Header)
+		}
+		var input struct {
+			Name           string
+			WithDecryption bool
+		}
+		json.NewDecoder(r.Body).Decode(&input)
+		if input.Name != "/r53check/contacts/work" || !input.WithDecryption {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type": "com.amazonaws.ssm#ParameterNotFound", "message": "no such parameter"}`))
+			return
+		}
+		w.Write([]byte(`{"Parameter": {"Name": "/r53check/contacts/work", "Type": "SecureString", "Value": "registrant: {}"}}`))
+	}))
+	defer server.Close()
+
+	cfg := aws.Config{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		HTTPClient:   server.Client(),
+		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+		}),
+	}
+	parameters := NewParameters(&cfg)
+
+	value, err := parameters.GetParameter(context.Background(), "/r53check/contacts/work")
+	if err != nil || value != "registrant: {}" {
+		t.Errorf("GetParameter() = %q, %v", value, err)
+	}
+	_, err = parameters.GetParameter(context.Background(), "/missing")
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ParameterNotFound" || apiErr.ErrorMessage() != "no such parameter" {
+		t.Errorf("Expected ParameterNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// RegistrationAPI is the part of the AWS SDK Route 53 Domains client that
+// Registrar calls. *route53domains.Client implements it.
+type RegistrationAPI interface {
+	RegisterDomain(ctx context.Context, params *route53domains.RegisterDomainInput, optFns ...func(*route53domains.Options)) (*route53domains.RegisterDomainOutput, error)
+	GetOperationDetail(ctx context.Context, params *route53domains.GetOperationDetailInput, optFns ...func(*route53domains.Options)) (*route53domains.GetOperationDetailOutput, error)
+}
+
+// RegistrationOptions are the settings every domain is registered with
+type RegistrationOptions struct {
+	// Registrant, Admin and Tech are the registrant, administrative and
+	// technical contacts
+	Registrant types.ContactDetail
+	Admin      types.ContactDetail
+	Tech       types.ContactDetail
+
+	// AutoRenew renews the domains before they expire
+	AutoRenew bool
+
+	// Privacy hides each contact from WHOIS where the TLD allows it
+	Privacy ContactPrivacy
+}
+
+// ContactPrivacy turns WHOIS privacy protection on for each contact
+type ContactPrivacy struct {
+	Registrant bool `json:"registrant"`
+	Admin      bool `json:"admin"`
+	Tech       bool `json:"tech"`
+}
+
+// Registrar registers domains to the account with Route 53 Domains and
+// follows the operations that registration starts
+type Registrar struct {
+	api     RegistrationAPI
+	options RegistrationOptions
+}
+
+// NewRegistrar creates a Registrar for the account of cfg
+func NewRegistrar(cfg *aws.Config, options RegistrationOptions) *Registrar {
+	return NewRegistrarWithAPI(route53domains.NewFromConfig(*cfg), options)
+}
+
+// NewRegistrarWithAPI creates a Registrar around an existing SDK client
+func NewRegistrarWithAPI(api RegistrationAPI, options RegistrationOptions) *Registrar {
+	return &Registrar{api: api, options: options}
+}
+
+// Register starts registering domain for years and returns the ID of the
+// operation, which finishes asynchronously. Route 53 charges the account
+// once the registration succeeds.
+func (r *Registrar) Register(ctx context.Context, domain string, years int) (string, error) {
+	registrant, admin, tech := r.options.Registrant, r.options.Admin, r.options.Tech
+	out, err := r.api.RegisterDomain(ctx, &route53domains.RegisterDomainInput{
+		DomainName:                      aws.String(domain),
+		DurationInYears:                 aws.Int32(int32(years)),
+		AdminContact:                    &admin,
+		RegistrantContact:               &registrant,
+		TechContact:                     &tech,
+		AutoRenew:                       aws.Bool(r.options.AutoRenew),
+		PrivacyProtectAdminContact:      aws.Bool(r.options.Privacy.Admin),
+		PrivacyProtectRegistrantContact: aws.Bool(r.options.Privacy.Registrant),
+		PrivacyProtectTechContact:       aws.Bool(r.options.Privacy.Tech),
+	})
+	if err != nil {
+		return "", errors.WrapAWSError(err, "route53domains", "RegisterDomain")
+	}
+	return aws.ToString(out.OperationId), nil
+}
+
+// OperationStatus returns the status of an operation, such as SUCCESSFUL
+// or IN_PROGRESS, and the message Route 53 gives with it
+func (r *Registrar) OperationStatus(ctx context.Context, operationID string) (string, string, error) {
+	out, err := r.api.GetOperationDetail(ctx, &route53domains.GetOperationDetailInput{OperationId: aws.String(operationID)})
+	if err != nil {
+		return "", "", errors.WrapAWSError(err, "route53domains", "GetOperationDetail")
+	}
+	return string(out.Status), aws.ToString(out.Message), nil
+}
@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// fakeRegistrationAPI records registrations and reports every operation
+// with the same status
+type fakeRegistrationAPI struct {
+	inputs []*route53domains.RegisterDomainInput
+	status types.OperationStatus
+	err    error
+}
+
+func (f *fakeRegistrationAPI) RegisterDomain(ctx context.Context, params *route53domains.RegisterDomainInput, optFns ...func(*route53domains.Options)) (*route53domains.RegisterDomainOutput, error) {
+	f.inputs = append(f.inputs, params)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &route53domains.RegisterDomainOutput{OperationId: aws.String("op-" + aws.ToString(params.DomainName))}, nil
+}
+
+func (f *fakeRegistrationAPI) GetOperationDetail(ctx context.Context, params *route53domains.GetOperationDetailInput, optFns ...func(*route53domains.Options)) (*route53domains.GetOperationDetailOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &route53domains.GetOperationDetailOutput{OperationId: params.OperationId, Status: f.status, Message: aws.String("on its way")}, nil
+}
+
+func TestRegistrar_Register(t *testing.T) {
+	api := &fakeRegistrationAPI{status: types.OperationStatusInProgress}
+	contact := types.ContactDetail{FirstName: aws.String("Ada"), Email: aws.String("ada@example.com")}
+	tech := types.ContactDetail{FirstName: aws.String("Grace"), Email: aws.String("ops@example.com")}
+	registrar := NewRegistrarWithAPI(api, RegistrationOptions{
+		Registrant: contact, Admin: contact, Tech: tech,
+		AutoRenew: true,
+		Privacy:   ContactPrivacy{Registrant: true, Admin: true},
+	})
+
+	id, err := registrar.Register(context.Background(), "example.com", 2)
+	if err != nil || id != "op-example.com" {
+		t.Fatalf("Register() = %q, %v", id, err)
+	}
+	input := api.inputs[0]
+	if aws.ToInt32(input.DurationInYears) != 2 || !aws.ToBool(input.AutoRenew) ||
+		!aws.ToBool(input.PrivacyProtectRegistrantContact) || aws.ToBool(input.PrivacyProtectTechContact) {
+		t.Errorf("Unexpected registration %+v", input)
+	}
+	if aws.ToString(input.RegistrantContact.Email) != "ada@example.com" || aws.ToString(input.AdminContact.FirstName) != "Ada" ||
+		aws.ToString(input.TechContact.Email) != "ops@example.com" {
+		t.Errorf("Expected the contact of each role, got %+v", input)
+	}
+
+	status, message, err := registrar.OperationStatus(context.Background(), id)
+	if err != nil || status != "IN_PROGRESS" || message != "on its way" {
+		t.Errorf("OperationStatus() = %q, %q, %v", status, message, err)
+	}
+
+	api.err = errors.New("DomainLimitExceeded")
+	if _, err := registrar.Register(context.Background(), "more.com", 1); err == nil {
+		t.Error("Expected the API error")
+	}
+}
@@ -0,0 +1,75 @@
+package aws
+
+import (
+	"context"
+	"sort"
+
+	"github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// DomainTagsAPI is the part of the AWS SDK Route 53 Domains client that
+// DomainTags calls. *route53domains.Client implements it.
+type DomainTagsAPI interface {
+	ListDomains(ctx context.Context, params *route53domains.ListDomainsInput, optFns ...func(*route53domains.Options)) (*route53domains.ListDomainsOutput, error)
+	ListTagsForDomain(ctx context.Context, params *route53domains.ListTagsForDomainInput, optFns ...func(*route53domains.Options)) (*route53domains.ListTagsForDomainOutput, error)
+	UpdateTagsForDomain(ctx context.Context, params *route53domains.UpdateTagsForDomainInput, optFns ...func(*route53domains.Options)) (*route53domains.UpdateTagsForDomainOutput, error)
+}
+
+// DomainTags reads and updates the tags of the domains registered to the
+// account
+type DomainTags struct {
+	api DomainTagsAPI
+}
+
+// NewDomainTags creates a DomainTags for the account of cfg
+func NewDomainTags(cfg *aws.Config) *DomainTags {
+	return NewDomainTagsWithAPI(route53domains.NewFromConfig(*cfg))
+}
+
+// NewDomainTagsWithAPI creates a DomainTags around an existing SDK client
+func NewDomainTagsWithAPI(api DomainTagsAPI) *DomainTags {
+	return &DomainTags{api: api}
+}
+
+// RegisteredDomains returns the domains registered to the account with
+// Route 53 Domains
+func (d *DomainTags) RegisteredDomains(ctx context.Context) ([]string, error) {
+	return registeredDomains(ctx, d.api)
+}
+
+// Tags returns the tags of a registered domain
+func (d *DomainTags) Tags(ctx context.Context, domain string) (map[string]string, error) {
+	out, err := d.api.ListTagsForDomain(ctx, &route53domains.ListTagsForDomainInput{DomainName: aws.String(domain)})
+	if err != nil {
+		return nil, errors.WrapAWSError(err, "route53domains", "ListTagsForDomain")
+	}
+	tags := make(map[string]string, len(out.TagList))
+	for _, tag := range out.TagList {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
+// TagDomain adds tags to a registered domain, replacing the values of the
+// keys it already has. The domain must have finished registering.
+func (d *DomainTags) TagDomain(ctx context.Context, domain string, tags map[string]string) error {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	update := make([]types.Tag, 0, len(keys))
+	for _, key := range keys {
+		update = append(update, types.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+	_, err := d.api.UpdateTagsForDomain(ctx, &route53domains.UpdateTagsForDomainInput{DomainName: aws.String(domain), TagsToUpdate: update})
+	if err != nil {
+		return errors.WrapAWSError(err, "route53domains", "UpdateTagsForDomain")
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// fakeDomainTagsAPI keeps the tags of its domains in memory
+type fakeDomainTagsAPI struct {
+	tags map[string][]types.Tag
+}
+
+func (f *fakeDomainTagsAPI) ListDomains(ctx context.Context, params *route53domains.ListDomainsInput, optFns ...func(*route53domains.Options)) (*route53domains.ListDomainsOutput, error) {
+	out := &route53domains.ListDomainsOutput{}
+	for _, name := range []string{"a.com", "b.io"} {
+		out.Domains = append(out.Domains, types.DomainSummary{DomainName: aws.String(name)})
+	}
+	return out, nil
+}
+
+func (f *fakeDomainTagsAPI) ListTagsForDomain(ctx context.Context, params *route53domains.ListTagsForDomainInput, optFns ...func(*route53domains.Options)) (*route53domains.ListTagsForDomainOutput, error) {
+	tags, ok := f.tags[aws.ToString(params.DomainName)]
+	if !ok {
+		return nil, errors.New("InvalidInput: domain not found")
+	}
+	return &route53domains.ListTagsForDomainOutput{TagList: tags}, nil
+}
+
+func (f *fakeDomainTagsAPI) UpdateTagsForDomain(ctx context.Context, params *route53domains.UpdateTagsForDomainInput, optFns ...func(*route53domains.Options)) (*route53domains.UpdateTagsForDomainOutput, error) {
+	name := aws.ToString(params.DomainName)
+	if _, ok := f.tags[name]; !ok {
+		return nil, errors.New("InvalidInput: domain not found")
+	}
+	f.tags[name] = append(f.tags[name], params.TagsToUpdate...)
+	return &route53domains.UpdateTagsForDomainOutput{}, nil
+}
+
+func TestDomainTags(t *testing.T) {
+	api := &fakeDomainTagsAPI{tags: map[string][]types.Tag{
+		"a.com": {{Key: aws.String("team"), Value: aws.String("web")}},
+		"b.io":  nil,
+	}}
+	tags := NewDomainTagsWithAPI(api)
+
+	domains, err := tags.RegisteredDomains(context.Background())
+	if err != nil || !reflect.DeepEqual(domains, []string{"a.com", "b.io"}) {
+		t.Errorf("RegisteredDomains() = %v, %v", domains, err)
+	}
+
+	if err := tags.TagDomain(context.Background(), "b.io", map[string]string{"team": "platform", "env": "prod"}); err != nil {
+		t.Fatalf("TagDomain() error: %v", err)
+	}
+	got, err := tags.Tags(context.Background(), "b.io")
+	if err != nil || !reflect.DeepEqual(got, map[string]string{"env": "prod", "team": "platform"}) {
+		t.Errorf("Tags() = %v, %v", got, err)
+	}
+
+	if err := tags.TagDomain(context.Background(), "pending.com", map[string]string{"team": "platform"}); err == nil {
+		t.Error("Expected tagging a domain that is not registered to fail")
+	}
+}
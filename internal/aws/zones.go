@@ -0,0 +1,223 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// HostedZonesAPI is the part of the AWS SDK Route 53 client that Zones
+// calls. *route53.Client implements it.
+type HostedZonesAPI interface {
+	ListHostedZones(ctx context.Context, params *route53.ListHostedZonesInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error)
+	GetHostedZone(ctx context.Context, params *route53.GetHostedZoneInput, optFns ...func(*route53.Options)) (*route53.GetHostedZoneOutput, error)
+}
+
+// RegisteredDomainsAPI is the part of the AWS SDK Route 53 Domains client
+// that Zones calls. *route53domains.Client implements it.
+type RegisteredDomainsAPI interface {
+	ListDomains(ctx context.Context, params *route53domains.ListDomainsInput, optFns ...func(*route53domains.Options)) (*route53domains.ListDomainsOutput, error)
+	GetDomainDetail(ctx context.Context, params *route53domains.GetDomainDetailInput, optFns ...func(*route53domains.Options)) (*route53domains.GetDomainDetailOutput, error)
+}
+
+// HostedZone is a public hosted zone and the name servers it is served from
+type HostedZone struct {
+	ID          string
+	Name        string
+	NameServers []string
+}
+
+// DomainSummary is a domain registered to the account, as Route 53 Domains
+// lists it
+type DomainSummary struct {
+	Name         string
+	AutoRenew    bool
+	TransferLock bool
+	Expiry       time.Time
+}
+
+// DomainDetail is what the portfolio audit reads of a registered domain
+// besides its summary
+type DomainDetail struct {
+	NameServers []string
+
+	// DNSSEC reports whether the domain has DNSSEC keys at the registry
+	DNSSEC bool
+
+	// ContactEmails are the email addresses of the registrant, admin and
+	// tech contacts, by role
+	ContactEmails map[string]string
+}
+
+// Zones lists the account's Route 53 hosted zones and the domains registered
+// to it with Route 53 Domains
+type Zones struct {
+	hostedZones HostedZonesAPI
+	domains     RegisteredDomainsAPI
+}
+
+// NewZones creates a Zones reading the account of cfg
+func NewZones(cfg *aws.Config) *Zones {
+	return NewZonesWithAPI(route53.NewFromConfig(*cfg), route53domains.NewFromConfig(*cfg))
+}
+
+// NewZonesWithAPI creates a Zones around existing SDK clients
+func NewZonesWithAPI(hostedZones HostedZonesAPI, domains RegisteredDomainsAPI) *Zones {
+	return &Zones{hostedZones: hostedZones, domains: domains}
+}
+
+// HostedZones returns the names of the account's public hosted zones,
+// without their trailing dot. Private zones are left out, since their names
+// need not be registered.
+func (z *Zones) HostedZones(ctx context.Context) ([]string, error) {
+	zones, err := z.publicZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(zones))
+	for i, zone := range zones {
+		names[i] = zone.Name
+	}
+	return names, nil
+}
+
+// HostedZoneDelegations returns the account's public hosted zones with the
+// name servers of each, which takes a request per zone
+func (z *Zones) HostedZoneDelegations(ctx context.Context) ([]HostedZone, error) {
+	zones, err := z.publicZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range zones {
+		detail, err := z.hostedZones.GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: aws.String(zones[i].ID)})
+		if err != nil {
+			return nil, errors.WrapAWSError(err, "route53", "GetHostedZone")
+		}
+		if detail.DelegationSet != nil {
+			zones[i].NameServers = trimDots(detail.DelegationSet.NameServers)
+		}
+	}
+	return zones, nil
+}
+
+// publicZones lists the account's public hosted zones, without their name
+// servers
+func (z *Zones) publicZones(ctx context.Context) ([]HostedZone, error) {
+	paginator := route53.NewListHostedZonesPaginator(z.hostedZones, &route53.ListHostedZonesInput{})
+
+	var zones []HostedZone
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.WrapAWSError(err, "route53", "ListHostedZones")
+		}
+		for _, zone := range page.HostedZones {
+			if zone.Name == nil || (zone.Config != nil && zone.Config.PrivateZone) {
+				continue
+			}
+			zones = append(zones, HostedZone{
+				ID:   strings.TrimPrefix(aws.ToString(zone.Id), "/hostedzone/"),
+				Name: strings.TrimSuffix(*zone.Name, "."),
+			})
+		}
+	}
+	return zones, nil
+}
+
+// DomainNameServers returns the name servers a domain registered to the
+// account is delegated to
+func (z *Zones) DomainNameServers(ctx context.Context, domain string) ([]string, error) {
+	detail, err := z.DomainDetail(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	return detail.NameServers, nil
+}
+
+// DomainDetail returns the delegation, DNSSEC keys and contact emails of a
+// domain registered to the account
+func (z *Zones) DomainDetail(ctx context.Context, domain string) (DomainDetail, error) {
+	out, err := z.domains.GetDomainDetail(ctx, &route53domains.GetDomainDetailInput{DomainName: aws.String(domain)})
+	if err != nil {
+		return DomainDetail{}, errors.WrapAWSError(err, "route53domains", "GetDomainDetail")
+	}
+	var names []string
+	for _, server := range out.Nameservers {
+		if server.Name != nil {
+			names = append(names, *server.Name)
+		}
+	}
+	detail := DomainDetail{NameServers: trimDots(names), DNSSEC: len(out.DnssecKeys) > 0, ContactEmails: make(map[string]string)}
+	for role, contact := range map[string]*types.ContactDetail{"registrant": out.RegistrantContact, "admin": out.AdminContact, "tech": out.TechContact} {
+		if contact != nil && contact.Email != nil {
+			detail.ContactEmails[role] = *contact.Email
+		}
+	}
+	return detail, nil
+}
+
+// trimDots returns host names lowercased and without their trailing dot
+func trimDots(names []string) []string {
+	trimmed := make([]string, len(names))
+	for i, name := range names {
+		trimmed[i] = strings.ToLower(strings.TrimSuffix(name, "."))
+	}
+	return trimmed
+}
+
+// RegisteredDomains returns the domains registered to the account with
+// Route 53 Domains
+func (z *Zones) RegisteredDomains(ctx context.Context) ([]string, error) {
+	return registeredDomains(ctx, z.domains)
+}
+
+// DomainSummaries returns the domains registered to the account with their
+// renewal, transfer lock and expiry
+func (z *Zones) DomainSummaries(ctx context.Context) ([]DomainSummary, error) {
+	return domainSummaries(ctx, z.domains)
+}
+
+// registeredDomains lists the domains registered to the account
+func registeredDomains(ctx context.Context, api route53domains.ListDomainsAPIClient) ([]string, error) {
+	summaries, err := domainSummaries(ctx, api)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(summaries))
+	for i, summary := range summaries {
+		names[i] = summary.Name
+	}
+	return names, nil
+}
+
+// domainSummaries lists the domains registered to the account, a page at a
+// time
+func domainSummaries(ctx context.Context, api route53domains.ListDomainsAPIClient) ([]DomainSummary, error) {
+	paginator := route53domains.NewListDomainsPaginator(api, &route53domains.ListDomainsInput{})
+
+	var summaries []DomainSummary
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.WrapAWSError(err, "route53domains", "ListDomains")
+		}
+		for _, summary := range page.Domains {
+			if summary.DomainName != nil {
+				summaries = append(summaries, DomainSummary{
+					Name:         *summary.DomainName,
+					AutoRenew:    aws.ToBool(summary.AutoRenew),
+					TransferLock: aws.ToBool(summary.TransferLock),
+					Expiry:       aws.ToTime(summary.Expiry),
+				})
+			}
+		}
+	}
+	return summaries, nil
+}
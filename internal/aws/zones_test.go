@@ -0,0 +1,213 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// fakeZonesAPI implements HostedZonesAPI and RegisteredDomainsAPI, answering
+// each request with the next page
+type fakeZonesAPI struct {
+	zonePages   []*route53.ListHostedZonesOutput
+	domainPages []*route53domains.ListDomainsOutput
+	nameServers map[string][]string
+	details     map[string]*route53domains.GetDomainDetailOutput
+	err         error
+
+	zoneInputs   []*route53.ListHostedZonesInput
+	domainInputs []*route53domains.ListDomainsInput
+}
+
+func (f *fakeZonesAPI) ListHostedZones(ctx context.Context, params *route53.ListHostedZonesInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error) {
+	f.zoneInputs = append(f.zoneInputs, params)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.zonePages[len(f.zoneInputs)-1], nil
+}
+
+func (f *fakeZonesAPI) ListDomains(ctx context.Context, params *route53domains.ListDomainsInput, optFns ...func(*route53domains.Options)) (*route53domains.ListDomainsOutput, error) {
+	f.domainInputs = append(f.domainInputs, params)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.domainPages[len(f.domainInputs)-1], nil
+}
+
+func (f *fakeZonesAPI) GetHostedZone(ctx context.Context, params *route53.GetHostedZoneInput, optFns ...func(*route53.Options)) (*route53.GetHostedZoneOutput, error) {
+	servers, ok := f.nameServers[aws.ToString(params.Id)]
+	if !ok {
+		return nil, errors.New("no such hosted zone")
+	}
+	return &route53.GetHostedZoneOutput{DelegationSet: &route53types.DelegationSet{NameServers: servers}}, nil
+}
+
+func (f *fakeZonesAPI) GetDomainDetail(ctx context.Context, params *route53domains.GetDomainDetailInput, optFns ...func(*route53domains.Options)) (*route53domains.GetDomainDetailOutput, error) {
+	if detail, ok := f.details[aws.ToString(params.DomainName)]; ok {
+		return detail, nil
+	}
+	servers, ok := f.nameServers[aws.ToString(params.DomainName)]
+	if !ok {
+		return nil, errors.New("domain not found")
+	}
+	output := &route53domains.GetDomainDetailOutput{}
+	for _, server := range servers {
+		output.Nameservers = append(output.Nameservers, types.Nameserver{Name: aws.String(server)})
+	}
+	return output, nil
+}
+
+func TestZones_HostedZones(t *testing.T) {
+	api := &fakeZonesAPI{
+		zonePages: []*route53.ListHostedZonesOutput{
+			{
+				HostedZones: []route53types.HostedZone{
+					{Name: aws.String("example.com.")},
+					{Name: aws.String("internal.corp."), Config: &route53types.HostedZoneConfig{PrivateZone: true}},
+				},
+				IsTruncated: true,
+				NextMarker:  aws.String("page-2"),
+			},
+			{
+				HostedZones: []route53types.HostedZone{{Name: aws.String("dev.example.io.")}},
+			},
+		},
+	}
+
+	zones, err := NewZonesWithAPI(api, api).HostedZones(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(zones) != 2 || zones[0] != "example.com" || zones[1] != "dev.example.io" {
+		t.Errorf("expected the public zones without trailing dots, got %v", zones)
+	}
+	if len(api.zoneInputs) != 2 || aws.ToString(api.zoneInputs[1].Marker) != "page-2" {
+		t.Errorf("expected the second request to use the page marker, got %v", api.zoneInputs)
+	}
+
+	failing := &fakeZonesAPI{err: errors.New("access denied")}
+	if _, err := NewZonesWithAPI(failing, failing).HostedZones(context.Background()); err == nil {
+		t.Error("expected an error when a page fails")
+	}
+}
+
+func TestZones_RegisteredDomains(t *testing.T) {
+	api := &fakeZonesAPI{
+		domainPages: []*route53domains.ListDomainsOutput{
+			{
+				Domains:        []types.DomainSummary{{DomainName: aws.String("example.com")}},
+				NextPageMarker: aws.String("page-2"),
+			},
+			{
+				Domains: []types.DomainSummary{{DomainName: aws.String("example.io")}, {DomainName: nil}},
+			},
+		},
+	}
+
+	domains, err := NewZonesWithAPI(api, api).RegisteredDomains(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(domains) != 2 || domains[0] != "example.com" || domains[1] != "example.io" {
+		t.Errorf("expected [example.com example.io], got %v", domains)
+	}
+	if len(api.domainInputs) != 2 || aws.ToString(api.domainInputs[1].Marker) != "page-2" {
+		t.Errorf("expected the second request to use the page marker, got %v", api.domainInputs)
+	}
+}
+
+func TestZones_HostedZoneDelegations(t *testing.T) {
+	api := &fakeZonesAPI{
+		zonePages: []*route53.ListHostedZonesOutput{{
+			HostedZones: []route53types.HostedZone{
+				{Id: aws.String("/hostedzone/Z1"), Name: aws.String("example.com.")},
+				{Id: aws.String("/hostedzone/Z2"), Name: aws.String("internal.corp."), Config: &route53types.HostedZoneConfig{PrivateZone: true}},
+			},
+		}},
+		nameServers: map[string][]string{"Z1": {"NS-1.awsdns-01.org."}},
+	}
+
+	zones, err := NewZonesWithAPI(api, api).HostedZoneDelegations(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(zones) != 1 || zones[0].ID != "Z1" || zones[0].Name != "example.com" || len(zones[0].NameServers) != 1 || zones[0].NameServers[0] != "ns-1.awsdns-01.org" {
+		t.Errorf("expected the public zone with its name servers, got %+v", zones)
+	}
+}
+
+func TestZones_DomainNameServers(t *testing.T) {
+	api := &fakeZonesAPI{nameServers: map[string][]string{"example.com": {"ns1.example-dns.net.", "NS2.example-dns.net"}}}
+	zones := NewZonesWithAPI(api, api)
+
+	servers, err := zones.DomainNameServers(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(servers) != 2 || servers[0] != "ns1.example-dns.net" || servers[1] != "ns2.example-dns.net" {
+		t.Errorf("expected normalized name servers, got %v", servers)
+	}
+	if _, err := zones.DomainNameServers(context.Background(), "missing.com"); err == nil {
+		t.Error("expected an error for a domain the account does not own")
+	}
+}
+
+func TestZones_DomainSummaries(t *testing.T) {
+	expiry := time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC)
+	api := &fakeZonesAPI{
+		domainPages: []*route53domains.ListDomainsOutput{
+			{
+				Domains:        []types.DomainSummary{{DomainName: aws.String("example.com"), AutoRenew: aws.Bool(true), TransferLock: aws.Bool(true), Expiry: &expiry}},
+				NextPageMarker: aws.String("page-2"),
+			},
+			{
+				Domains: []types.DomainSummary{{DomainName: aws.String("example.io")}},
+			},
+		},
+	}
+
+	summaries, err := NewZonesWithAPI(api, api).DomainSummaries(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []DomainSummary{
+		{Name: "example.com", AutoRenew: true, TransferLock: true, Expiry: expiry},
+		{Name: "example.io"},
+	}
+	if !reflect.DeepEqual(summaries, expected) {
+		t.Errorf("expected %+v, got %+v", expected, summaries)
+	}
+}
+
+func TestZones_DomainDetail(t *testing.T) {
+	api := &fakeZonesAPI{details: map[string]*route53domains.GetDomainDetailOutput{
+		"example.com": {
+			Nameservers:       []types.Nameserver{{Name: aws.String("NS-1.awsdns-01.org.")}},
+			DnssecKeys:        []types.DnssecKey{{Id: aws.String("key-1")}},
+			RegistrantContact: &types.ContactDetail{Email: aws.String("owner@example.org")},
+			TechContact:       &types.ContactDetail{Email: aws.String("noreply@example.com")},
+		},
+	}}
+
+	detail, err := NewZonesWithAPI(api, api).DomainDetail(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := DomainDetail{
+		NameServers:   []string{"ns-1.awsdns-01.org"},
+		DNSSEC:        true,
+		ContactEmails: map[string]string{"registrant": "owner@example.org", "tech": "noreply@example.com"},
+	}
+	if !reflect.DeepEqual(detail, expected) {
+		t.Errorf("expected %+v, got %+v", expected, detail)
+	}
+}
@@ -0,0 +1,244 @@
+// Package budget guards commands that purchase domains, such as registering
+// and renewing them, against the spending limits in the config file. Every
+// purchase is recorded in a ledger, so the monthly limit holds across runs.
+package budget
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/abakermi/r53check/internal/config"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+// ErrExceeded is the cause of the error returned by Check when purchases
+// would go over a limit
+var ErrExceeded = errors.New("budget exceeded")
+
+// Purchase is a domain bought, or about to be bought, and what it costs
+type Purchase struct {
+	Domain string `json:"domain"`
+
+	// Kind is what was bought, such as "register" or "renew"
+	Kind     string    `json:"kind"`
+	Amount   float64   `json:"amount"`
+	Currency string    `json:"currency"`
+	At       time.Time `json:"at"`
+
+	// OperationID is the Route 53 Domains operation that made the purchase
+	OperationID string `json:"operation_id,omitempty"`
+
+	// Tags are the tags the domain was registered with
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Unconfirmed is set when the request failed without an answer, so the
+	// purchase may or may not have been made. It counts against the budget
+	// all the same.
+	Unconfirmed bool `json:"unconfirmed,omitempty"`
+}
+
+// Ledger is the record of purchases, stored as JSON Lines with one
+// purchase per line in the order they were made
+type Ledger struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLedger returns the ledger stored at path. The file is created on the
+// first purchase.
+func NewLedger(path string) *Ledger {
+	return &Ledger{path: path}
+}
+
+// Path returns the file the ledger is stored in
+func (l *Ledger) Path() string {
+	return l.path
+}
+
+// Record adds purchases to the end of the ledger
+func (l *Ledger) Record(purchases []Purchase) error {
+	if len(purchases) == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return customErrors.NewSystemError("budget", "unable to create ledger directory", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return customErrors.NewSystemError("budget", "unable to open ledger "+l.path, err)
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, purchase := range purchases {
+		if err := encoder.Encode(purchase); err != nil {
+			file.Close()
+			return customErrors.NewSystemError("budget", "unable to encode purchase", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return customErrors.NewSystemError("budget", "unable to write ledger "+l.path, err)
+	}
+	if err := file.Close(); err != nil {
+		return customErrors.NewSystemError("budget", "unable to write ledger "+l.path, err)
+	}
+	return nil
+}
+
+// Purchases returns every purchase in the ledger, oldest first. A ledger
+// that does not exist yet has no purchases.
+func (l *Ledger) Purchases() ([]Purchase, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, customErrors.NewSystemError("budget", "unable to open ledger "+l.path, err)
+	}
+	defer file.Close()
+
+	var purchases []Purchase
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var purchase Purchase
+		if err := json.Unmarshal(scanner.Bytes(), &purchase); err != nil {
+			return nil, customErrors.NewSystemError("budget", fmt.Sprintf("corrupt purchase on line %d of ledger %s", line, l.path), err)
+		}
+		purchases = append(purchases, purchase)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, customErrors.NewSystemError("budget", "unable to read ledger "+l.path, err)
+	}
+	return purchases, nil
+}
+
+// Spent returns what the purchases in the calendar month of at cost
+// together, in at's time zone
+func (l *Ledger) Spent(at time.Time) (float64, error) {
+	purchases, err := l.Purchases()
+	if err != nil {
+		return 0, err
+	}
+
+	year, month, _ := at.Date()
+	var spent float64
+	for _, purchase := range purchases {
+		if y, m, _ := purchase.At.In(at.Location()).Date(); y == year && m == month {
+			spent += purchase.Amount
+		}
+	}
+	return spent, nil
+}
+
+// Guard checks purchases against the per-run and monthly limits
+type Guard struct {
+	perRun   float64
+	perMonth float64
+	ledger   *Ledger
+
+	// now returns the current time; tests replace it
+	now func() time.Time
+}
+
+// NewGuard creates a guard enforcing the limits in settings, counting the
+// month's spending from ledger
+func NewGuard(settings config.BudgetSettings, ledger *Ledger) (*Guard, error) {
+	if settings.PerRun < 0 {
+		return nil, customErrors.NewValidationError("", "budget.per_run", "the per-run budget cannot be negative", nil)
+	}
+	if settings.PerMonth < 0 {
+		return nil, customErrors.NewValidationError("", "budget.per_month", "the monthly budget cannot be negative", nil)
+	}
+	return &Guard{perRun: settings.PerRun, perMonth: settings.PerMonth, ledger: ledger, now: time.Now}, nil
+}
+
+// Enabled reports whether any limit is set
+func (g *Guard) Enabled() bool {
+	return g.perRun > 0 || g.perMonth > 0
+}
+
+// Check returns an error when purchases, made together in one run, would go
+// over the per-run limit, or take the month's spending over the monthly
+// limit. The error names --override-budget; with override the purchases
+// are allowed regardless.
+func (g *Guard) Check(purchases []Purchase, override bool) error {
+	if override || !g.Enabled() {
+		return nil
+	}
+
+	var total float64
+	for _, purchase := range purchases {
+		total += purchase.Amount
+	}
+	if g.perRun > 0 && total > g.perRun {
+		return customErrors.NewValidationError("", "budget",
+			fmt.Sprintf("this run would spend $%.2f, over the per-run budget of $%.2f; use --override-budget to spend it anyway", total, g.perRun),
+			ErrExceeded)
+	}
+
+	if g.perMonth > 0 {
+		spent, err := g.ledger.Spent(g.now())
+		if err != nil {
+			return err
+		}
+		if spent+total > g.perMonth {
+			return customErrors.NewValidationError("", "budget",
+				fmt.Sprintf("this run would spend $%.2f, and $%.2f is already spent this month, over the monthly budget of $%.2f; use --override-budget to spend it anyway",
+					total, spent, g.perMonth),
+				ErrExceeded)
+		}
+	}
+	return nil
+}
+
+// Record adds purchases that were made to the ledger, so they count
+// against the monthly limit
+func (g *Guard) Record(purchases []Purchase) error {
+	return g.ledger.Record(purchases)
+}
+
+// Status is the budget and what has been spent of it this month
+type Status struct {
+	PerRun   float64 `json:"per_run"`
+	PerMonth float64 `json:"per_month"`
+
+	// Month is the current calendar month, such as "2025-01"
+	Month string  `json:"month"`
+	Spent float64 `json:"spent"`
+
+	// Remaining is what is left of the monthly budget, and nil without one
+	Remaining *float64 `json:"remaining"`
+}
+
+// Status returns the limits and this month's spending
+func (g *Guard) Status() (Status, error) {
+	now := g.now()
+	spent, err := g.ledger.Spent(now)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{PerRun: g.perRun, PerMonth: g.perMonth, Month: now.Format("2006-01"), Spent: spent}
+	if g.perMonth > 0 {
+		remaining := max(g.perMonth-spent, 0)
+		status.Remaining = &remaining
+	}
+	return status, nil
+}
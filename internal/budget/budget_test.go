@@ -0,0 +1,130 @@
+package budget
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/config"
+)
+
+// purchaseOn returns a purchase of name for amount made on day of
+// January 2025
+func purchaseOn(name string, amount float64, day int) Purchase {
+	return Purchase{Domain: name, Kind: "register", Amount: amount, Currency: "USD", At: time.Date(2025, 1, day, 12, 0, 0, 0, time.UTC)}
+}
+
+func newTestGuard(t *testing.T, settings config.BudgetSettings, spent ...Purchase) *Guard {
+	t.Helper()
+
+	ledger := NewLedger(filepath.Join(t.TempDir(), "data", "purchases.jsonl"))
+	if err := ledger.Record(spent); err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+	guard, err := NewGuard(settings, ledger)
+	if err != nil {
+		t.Fatalf("NewGuard() error: %v", err)
+	}
+	guard.now = func() time.Time { return time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC) }
+	return guard
+}
+
+func TestLedger_Spent(t *testing.T) {
+	ledger := NewLedger(filepath.Join(t.TempDir(), "purchases.jsonl"))
+	if purchases, err := ledger.Purchases(); err != nil || len(purchases) != 0 {
+		t.Fatalf("Expected a missing ledger to be empty, got %v, %v", purchases, err)
+	}
+
+	december := purchaseOn("old.com", 40, 1)
+	december.At = december.At.AddDate(0, -1, 0)
+	if err := ledger.Record([]Purchase{december, purchaseOn("a.com", 12, 2)}); err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+	if err := ledger.Record([]Purchase{purchaseOn("b.io", 39.5, 15)}); err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+
+	purchases, err := ledger.Purchases()
+	if err != nil || len(purchases) != 3 || purchases[2].Domain != "b.io" {
+		t.Errorf("Expected the 3 purchases in order, got %+v, %v", purchases, err)
+	}
+	if spent, err := ledger.Spent(time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)); err != nil || spent != 51.5 {
+		t.Errorf("Expected 51.5 spent in January, got %v, %v", spent, err)
+	}
+}
+
+func TestLedger_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "purchases.jsonl")
+	if err := os.WriteFile(path, []byte("{\"domain\":\"a.com\"}\nnot json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLedger(path).Purchases(); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected the corrupt line to be reported, got %v", err)
+	}
+}
+
+func TestGuard_Check(t *testing.T) {
+	spent := []Purchase{purchaseOn("a.com", 300, 2), purchaseOn("old.com", 400, 1)}
+	spent[1].At = spent[1].At.AddDate(0, -1, 0)
+
+	tests := []struct {
+		name      string
+		settings  config.BudgetSettings
+		purchases []Purchase
+		override  bool
+		err       string
+	}{
+		{"no budget", config.BudgetSettings{}, []Purchase{purchaseOn("b.com", 5000, 20)}, false, ""},
+		{"within budget", config.BudgetSettings{PerRun: 100, PerMonth: 500}, []Purchase{purchaseOn("b.com", 60, 20), purchaseOn("c.com", 40, 20)}, false, ""},
+		{"over the per-run budget", config.BudgetSettings{PerRun: 100}, []Purchase{purchaseOn("b.com", 60, 20), purchaseOn("c.com", 41, 20)}, false, "over the per-run budget of $100.00"},
+		{"over the monthly budget", config.BudgetSettings{PerMonth: 500}, []Purchase{purchaseOn("b.com", 201, 20)}, false, "$300.00 is already spent this month, over the monthly budget of $500.00"},
+		{"overridden", config.BudgetSettings{PerRun: 100, PerMonth: 500}, []Purchase{purchaseOn("b.com", 900, 20)}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTestGuard(t, tt.settings, spent...).Check(tt.purchases, tt.override)
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("Expected the purchases to be allowed, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) || !strings.Contains(err.Error(), "--override-budget") {
+				t.Errorf("Expected an error containing %q, got %v", tt.err, err)
+			}
+			if !errors.Is(err, ErrExceeded) {
+				t.Errorf("Expected the error to wrap ErrExceeded, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGuard_RecordAndStatus(t *testing.T) {
+	guard := newTestGuard(t, config.BudgetSettings{PerRun: 100, PerMonth: 500}, purchaseOn("a.com", 300, 2))
+	if err := guard.Record([]Purchase{purchaseOn("b.com", 150, 20)}); err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+
+	status, err := guard.Status()
+	if err != nil {
+		t.Fatalf("Status() error: %v", err)
+	}
+	if status.Month != "2025-01" || status.Spent != 450 || status.Remaining == nil || *status.Remaining != 50 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	if err := guard.Check([]Purchase{purchaseOn("c.com", 60, 20)}, false); !errors.Is(err, ErrExceeded) {
+		t.Errorf("Expected the recorded purchase to count against the month, got %v", err)
+	}
+}
+
+func TestNewGuard_Invalid(t *testing.T) {
+	for _, settings := range []config.BudgetSettings{{PerRun: -1}, {PerMonth: -5}} {
+		if _, err := NewGuard(settings, NewLedger("purchases.jsonl")); err == nil {
+			t.Errorf("Expected an error for %+v", settings)
+		}
+	}
+}
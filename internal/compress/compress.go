@@ -0,0 +1,98 @@
+// Package compress reads and writes gzip and zstd compressed files, so large
+// domain lists and result files can be kept compressed.
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec is a compression format
+type Codec string
+
+const (
+	None Codec = ""     // Uncompressed
+	Gzip Codec = "gzip" // gzip, with the .gz extension
+	Zstd Codec = "zstd" // Zstandard, with the .zst extension
+)
+
+// Magic numbers that start compressed data
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Detect returns the codec of filename from its extension
+func Detect(filename string) Codec {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".gz", ".gzip":
+		return Gzip
+	case ".zst", ".zstd":
+		return Zstd
+	default:
+		return None
+	}
+}
+
+// TrimExtension removes the compression extension from filename, so
+// "domains.csv.gz" yields "domains.csv"
+func TrimExtension(filename string) string {
+	if Detect(filename) == None {
+		return filename
+	}
+	return strings.TrimSuffix(filename, filepath.Ext(filename))
+}
+
+// NewReader returns a reader of the decompressed data of r. gzip and zstd
+// data are recognized by their first bytes, whatever the file is called;
+// other data is read as is.
+func NewReader(r io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(r)
+	head, _ := buffered.Peek(len(zstdMagic))
+
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		reader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip data: %w", err)
+		}
+		return reader, nil
+	case bytes.HasPrefix(head, zstdMagic):
+		decoder, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("invalid zstd data: %w", err)
+		}
+		return decoder.IOReadCloser(), nil
+	default:
+		return io.NopCloser(buffered), nil
+	}
+}
+
+// NewWriter returns a writer compressing to w with codec. Closing it
+// flushes the compressed data but leaves w open.
+func NewWriter(w io.Writer, codec Codec) (io.WriteCloser, error) {
+	switch codec {
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w)
+	case None:
+		return nopWriteCloser{w}, nil
+	default:
+		return nil, fmt.Errorf("unknown compression %q", codec)
+	}
+}
+
+// nopWriteCloser is a writer whose Close does nothing
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
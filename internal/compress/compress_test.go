@@ -0,0 +1,78 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		filename string
+		codec    Codec
+		trimmed  string
+	}{
+		{"domains.txt.gz", Gzip, "domains.txt"},
+		{"results.json.GZ", Gzip, "results.json"},
+		{"corpus.csv.zst", Zstd, "corpus.csv"},
+		{"corpus.zstd", Zstd, "corpus"},
+		{"domains.txt", None, "domains.txt"},
+		{"gz", None, "gz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if got := Detect(tt.filename); got != tt.codec {
+				t.Errorf("Detect(%q) = %q, want %q", tt.filename, got, tt.codec)
+			}
+			if got := TrimExtension(tt.filename); got != tt.trimmed {
+				t.Errorf("TrimExtension(%q) = %q, want %q", tt.filename, got, tt.trimmed)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	const data = "example.com\ntest.org\n"
+
+	for _, codec := range []Codec{None, Gzip, Zstd} {
+		t.Run(string(codec), func(t *testing.T) {
+			var compressed bytes.Buffer
+			w, err := NewWriter(&compressed, codec)
+			if err != nil {
+				t.Fatalf("NewWriter() error = %v", err)
+			}
+			if _, err := io.WriteString(w, data); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if codec != None && compressed.String() == data {
+				t.Fatal("expected the data to be compressed")
+			}
+
+			r, err := NewReader(&compressed)
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+			defer r.Close()
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != data {
+				t.Errorf("read %q, want %q", got, data)
+			}
+		})
+	}
+}
+
+func TestNewReader_Invalid(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader([]byte{0x1f, 0x8b, 0x00})); err == nil {
+		t.Error("expected an error for truncated gzip data")
+	}
+	if _, err := NewWriter(io.Discard, "lz4"); err == nil {
+		t.Error("expected an error for an unknown codec")
+	}
+}
@@ -0,0 +1,383 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abakermi/r53check/internal/storage"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix is prepended to setting names to form environment variables,
+// so "timeout" is read from R53CHECK_TIMEOUT
+const EnvPrefix = "R53CHECK"
+
+// Keys lists every setting that can come from the config file. Each key is
+// also the name of the flag that overrides it.
+var Keys = []string{"timeout", "region", "profile", "output", "concurrency", "tlds", "lang", "error-reporting", "mock", "price-cache-ttl"}
+
+// secretKeys lists nested settings that are read from the environment as
+// well as the config file, so secrets need not be written to disk
+var secretKeys = []string{"email.smtp.password", "webhook.secret", "history.postgres.dsn"}
+
+// Config holds settings resolved from flags, environment variables, the
+// config file and defaults, in that order of precedence
+type Config struct {
+	Timeout     time.Duration `mapstructure:"timeout"`
+	Region      string        `mapstructure:"region"`
+	Profile     string        `mapstructure:"profile"`
+	Output      string        `mapstructure:"output"`
+	Concurrency int           `mapstructure:"concurrency"`
+	TLDs        []string      `mapstructure:"tlds"`
+	Lang        string        `mapstructure:"lang"`
+
+	// ErrorReporting is where unexpected errors are reported, such as
+	// sentry://key@host/project; empty disables reporting
+	ErrorReporting string `mapstructure:"error-reporting"`
+
+	// Mock answers availability and pricing checks from a deterministic
+	// fake instead of Route 53, so no AWS credentials are needed
+	Mock bool `mapstructure:"mock"`
+
+	// PriceCacheTTL is how long the cached Route 53 price list is used
+	// before it is fetched again
+	PriceCacheTTL time.Duration `mapstructure:"price-cache-ttl"`
+
+	// Email configures email notifications; it is only read from the
+	// config file, apart from the SMTP password
+	Email EmailSettings `mapstructure:"email"`
+
+	// Webhook configures webhook notifications; it is only read from the
+	// config file, apart from the signing secret
+	Webhook WebhookSettings `mapstructure:"webhook"`
+
+	// Notify routes results to notifiers; it is only read from the config file
+	Notify NotifySettings `mapstructure:"notify"`
+
+	// History selects where checks are recorded; it is only read from the
+	// config file, apart from the Postgres connection string
+	History HistorySettings `mapstructure:"history"`
+
+	// Retry sets how often failed checks are retried; it is only read
+	// from the config file
+	Retry RetrySettings `mapstructure:"retry"`
+
+	// Generate sets the affixes of generated names; it is only read from
+	// the config file
+	Generate GenerateSettings `mapstructure:"generate"`
+
+	// AI enables name suggestions from Amazon Bedrock; it is only read from
+	// the config file
+	AI AISettings `mapstructure:"ai"`
+
+	// Metrics sends check metrics to StatsD or CloudWatch as they happen;
+	// it is only read from the config file
+	Metrics MetricsSettings `mapstructure:"metrics"`
+
+	// Budget limits what purchases may spend; it is only read from the
+	// config file
+	Budget BudgetSettings `mapstructure:"budget"`
+
+	// Register sets who domains are registered to; it is only read from the
+	// config file
+	Register RegisterSettings `mapstructure:"register"`
+
+	// Contacts are the contact profiles registrations use, by name; they
+	// are only read from the config file
+	Contacts map[string]ContactProfile `mapstructure:"contacts"`
+
+	// File is the config file that was read, empty when none was used
+	File string `mapstructure:"-"`
+
+	v *viper.Viper
+}
+
+// EmailSettings configures how notification emails are sent
+type EmailSettings struct {
+	// Transport is "smtp" or "ses"
+	Transport string   `mapstructure:"transport"`
+	From      string   `mapstructure:"from"`
+	To        []string `mapstructure:"to"`
+
+	// Subject is a text/template for the subject line, and Template the
+	// path of a text/template file for the body; empty uses the defaults
+	Subject  string `mapstructure:"subject"`
+	Template string `mapstructure:"template"`
+
+	SMTP SMTPSettings `mapstructure:"smtp"`
+}
+
+// SMTPSettings locates and authenticates with an SMTP server
+type SMTPSettings struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+
+	// TLS connects with implicit TLS, as on port 465, instead of STARTTLS
+	TLS bool `mapstructure:"tls"`
+}
+
+// WebhookSettings configures how results are posted to a webhook
+type WebhookSettings struct {
+	URL string `mapstructure:"url"`
+
+	// Template is the path of a text/template file for the request body;
+	// empty posts the results as JSON
+	Template string `mapstructure:"template"`
+
+	// Secret, when set, signs each body with HMAC-SHA256
+	Secret string `mapstructure:"secret"`
+
+	// Retries is how often a failed delivery is retried; 0 uses the
+	// default and a negative value disables retries
+	Retries int `mapstructure:"retries"`
+
+	// Headers are added to every request, e.g. for authentication
+	Headers map[string]string `mapstructure:"headers"`
+}
+
+// NotifySettings configures which results reach each notifier
+type NotifySettings struct {
+	Rules []NotifyRule `mapstructure:"rules"`
+}
+
+// NotifyRule sends the results matching all of its conditions to its
+// targets. Empty conditions match every result. A rule with PriceChange
+// sends the TLD price changes matching its TLDs instead of results.
+type NotifyRule struct {
+	Name        string   `mapstructure:"name"`
+	Status      []string `mapstructure:"status"`
+	TLDs        []string `mapstructure:"tlds"`
+	PriceBelow  float64  `mapstructure:"price_below"`
+	PriceChange bool     `mapstructure:"price_change"`
+	Targets     []string `mapstructure:"targets"`
+}
+
+// HistorySettings selects the check history backend
+type HistorySettings struct {
+	// Backend is "sqlite", the default, "dynamodb" or "postgres"
+	Backend string `mapstructure:"backend"`
+
+	// Path is the SQLite database; empty uses the default location
+	Path string `mapstructure:"path"`
+
+	DynamoDB DynamoDBSettings `mapstructure:"dynamodb"`
+	Postgres PostgresSettings `mapstructure:"postgres"`
+}
+
+// DynamoDBSettings locates the DynamoDB history table
+type DynamoDBSettings struct {
+	Table string `mapstructure:"table"`
+}
+
+// PostgresSettings locates the Postgres history database
+type PostgresSettings struct {
+	// DSN is a postgres:// URL or key=value connection string
+	DSN string `mapstructure:"dsn"`
+}
+
+// RetrySettings configures the retries of failed checks
+type RetrySettings struct {
+	// Budgets maps an HTTP status code such as "429", or "timeout", to the
+	// number of retries for checks failing with it
+	Budgets map[string]int `mapstructure:"budgets"`
+}
+
+// GenerateSettings configures the name variants of the generate command
+type GenerateSettings struct {
+	// Prefixes and Suffixes replace the built-in affix lists when set
+	Prefixes []string `mapstructure:"prefixes"`
+	Suffixes []string `mapstructure:"suffixes"`
+}
+
+// AISettings configures name suggestions from a model on Amazon Bedrock,
+// which are off unless Model is set
+type AISettings struct {
+	// Model is the Bedrock model ID, such as
+	// anthropic.claude-3-haiku-20240307-v1:0
+	Model string `mapstructure:"model"`
+
+	// Region is where the model is called; empty uses the AWS region
+	Region string `mapstructure:"region"`
+}
+
+// MetricsSettings configures where check counts, error counts and latency
+// are pushed; each destination is off while empty
+type MetricsSettings struct {
+	// StatsD is the host:port of a StatsD server, which receives DogStatsD tags
+	StatsD string `mapstructure:"statsd"`
+
+	// EMF writes CloudWatch embedded metric format documents to "stderr",
+	// the tcp:// or udp:// address of a CloudWatch agent, or a file
+	EMF string `mapstructure:"emf"`
+
+	// Namespace is the StatsD prefix and CloudWatch namespace; empty uses
+	// r53check
+	Namespace string `mapstructure:"namespace"`
+}
+
+// BudgetSettings limits the spending of commands that purchase domains, in
+// USD like Route 53 prices; each limit is off while zero
+type BudgetSettings struct {
+	// PerRun caps what a single run may spend
+	PerRun float64 `mapstructure:"per_run"`
+
+	// PerMonth caps what runs may spend together in a calendar month
+	PerMonth float64 `mapstructure:"per_month"`
+}
+
+// RegisterSettings configures the registration of domains
+type RegisterSettings struct {
+	// Profile names the contact profile in Contacts that registrations use
+	// unless --contact picks another
+	Profile string `mapstructure:"profile"`
+
+	// Contact is the registrant, administrative and technical contact of
+	// registered domains without a contact profile
+	Contact ContactSettings `mapstructure:"contact"`
+
+	// RequiredTags are the tag keys every registration must be given with
+	// --tags, and that tags audit checks registered domains for
+	RequiredTags []string `mapstructure:"required_tags"`
+}
+
+// ContactProfile is the contacts a domain is registered with. Admin and
+// Tech left empty are the same as Registrant.
+type ContactProfile struct {
+	Registrant ContactSettings `mapstructure:"registrant" json:"registrant"`
+	Admin      ContactSettings `mapstructure:"admin" json:"admin"`
+	Tech       ContactSettings `mapstructure:"tech" json:"tech"`
+	Privacy    PrivacySettings `mapstructure:"privacy" json:"privacy"`
+
+	// SSMParameter names an SSM Parameter Store parameter holding the
+	// profile as YAML or JSON, instead of the config file
+	SSMParameter string `mapstructure:"ssm_parameter" json:"ssm_parameter,omitempty"`
+}
+
+// PrivacySettings turn WHOIS privacy protection on or off for each contact;
+// unset uses --privacy
+type PrivacySettings struct {
+	Registrant *bool `mapstructure:"registrant" json:"registrant,omitempty"`
+	Admin      *bool `mapstructure:"admin" json:"admin,omitempty"`
+	Tech       *bool `mapstructure:"tech" json:"tech,omitempty"`
+}
+
+// ParseContactProfile reads a contact profile written as YAML or JSON, such
+// as one kept in SSM Parameter Store, with the keys of the config file
+func ParseContactProfile(data []byte) (ContactProfile, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return ContactProfile{}, fmt.Errorf("invalid contact profile: %w", err)
+	}
+	var profile ContactProfile
+	if err := v.Unmarshal(&profile); err != nil {
+		return ContactProfile{}, fmt.Errorf("invalid contact profile: %w", err)
+	}
+	return profile, nil
+}
+
+// ContactSettings is a domain contact, in the form Route 53 Domains takes it
+type ContactSettings struct {
+	// Type is PERSON, COMPANY, ASSOCIATION, PUBLIC_BODY or RESELLER; empty
+	// is PERSON
+	Type         string `mapstructure:"type" json:"type,omitempty"`
+	FirstName    string `mapstructure:"first_name" json:"first_name,omitempty"`
+	LastName     string `mapstructure:"last_name" json:"last_name,omitempty"`
+	Organization string `mapstructure:"organization" json:"organization,omitempty"`
+	Email        string `mapstructure:"email" json:"email,omitempty"`
+
+	// Phone is in the form +1.1234567890
+	Phone        string `mapstructure:"phone" json:"phone,omitempty"`
+	AddressLine1 string `mapstructure:"address_line_1" json:"address_line_1,omitempty"`
+	AddressLine2 string `mapstructure:"address_line_2" json:"address_line_2,omitempty"`
+	City         string `mapstructure:"city" json:"city,omitempty"`
+	State        string `mapstructure:"state" json:"state,omitempty"`
+
+	// CountryCode is the two-letter ISO country code, such as US
+	CountryCode string `mapstructure:"country_code" json:"country_code,omitempty"`
+	ZipCode     string `mapstructure:"zip_code" json:"zip_code,omitempty"`
+}
+
+// DefaultPath returns the default config file location, usually
+// ~/.config/r53check/config.yaml
+func DefaultPath() string {
+	return storage.DefaultDirs().ConfigFile()
+}
+
+// Load resolves the settings. Flags in flags named after a key override the
+// environment, which overrides the file at path, which overrides the flag
+// defaults. An empty path reads DefaultPath if it exists; an explicit path
+// must exist.
+func Load(path string, flags *pflag.FlagSet) (*Config, error) {
+	v := viper.New()
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_", ".", "_"))
+
+	for _, key := range Keys {
+		if err := v.BindEnv(key); err != nil {
+			return nil, err
+		}
+		if flag := flags.Lookup(key); flag != nil {
+			if err := v.BindPFlag(key, flag); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, key := range secretKeys {
+		if err := v.BindEnv(key); err != nil {
+			return nil, err
+		}
+	}
+
+	file := path
+	if file == "" {
+		if _, err := os.Stat(DefaultPath()); err == nil {
+			file = DefaultPath()
+		}
+	}
+	if file != "" {
+		v.SetConfigFile(file)
+		if err := v.ReadInConfig(); err != nil {
+			var notFound *os.PathError
+			if errors.As(err, &notFound) {
+				return nil, fmt.Errorf("config file not found: %s", file)
+			}
+			return nil, fmt.Errorf("failed to read config file %s: %w", file, err)
+		}
+	}
+
+	cfg := &Config{File: file, v: v}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", file, err)
+	}
+
+	// Environment variables hold lists as comma-separated values
+	if len(cfg.TLDs) == 1 && strings.Contains(cfg.TLDs[0], ",") {
+		cfg.TLDs = strings.Split(cfg.TLDs[0], ",")
+	}
+
+	return cfg, nil
+}
+
+// Source reports where the effective value of key came from: "flag", "env",
+// "config" or "default"
+func (c *Config) Source(key string, flags *pflag.FlagSet) string {
+	if flag := flags.Lookup(key); flag != nil && flag.Changed {
+		return "flag"
+	}
+	if _, ok := os.LookupEnv(EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))); ok {
+		return "env"
+	}
+	if c.v != nil && c.v.InConfig(key) {
+		return "config"
+	}
+	return "default"
+}
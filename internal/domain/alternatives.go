@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"strings"
+)
+
+// AlternativeNames returns the name of domain under each of tlds instead of
+// its own TLD, so "www.example.com" with "io" and "co.uk" yields
+// "example.io" and "example.co.uk". The domain's own TLD and repeated TLDs
+// are left out.
+func AlternativeNames(name string, tlds []string) []string {
+	registrable := RegistrableDomain(name)
+	if registrable == "" || isPublicSuffix(registrable) {
+		return nil
+	}
+	own := EffectiveTLD(registrable)
+	label := strings.TrimSuffix(registrable, "."+own)
+
+	var names []string
+	seen := map[string]bool{own: true}
+	for _, tld := range tlds {
+		tld = strings.ToLower(strings.Trim(strings.TrimSpace(tld), "."))
+		if tld == "" || seen[tld] {
+			continue
+		}
+		seen[tld] = true
+		names = append(names, label+"."+tld)
+	}
+	return names
+}
+
+// FindAlternatives checks the names under tlds of every unavailable domain
+// in results, with pricing, and sets the Alternatives of each result to the
+// ones that are available. All alternatives are checked in one bulk check;
+// those that fail to be checked are left out.
+func (c *DomainChecker) FindAlternatives(ctx context.Context, results []*AvailabilityResult, tlds []string) error {
+	var names []string
+	owners := make(map[string][]*AvailabilityResult)
+	for _, result := range results {
+		if result == nil || result.Error != nil || result.Status != StatusUnavailable {
+			continue
+		}
+		for _, name := range AlternativeNames(result.Domain, tlds) {
+			if owners[name] == nil {
+				names = append(names, name)
+			}
+			owners[name] = append(owners[name], result)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	checked, err := c.CheckAvailabilityBulkWithPricing(ctx, names)
+	if err != nil {
+		return err
+	}
+	for i, alternative := range checked {
+		if alternative == nil || alternative.Error != nil || !alternative.Available {
+			continue
+		}
+		for _, result := range owners[names[i]] {
+			result.Alternatives = append(result.Alternatives, alternative)
+		}
+	}
+	return nil
+}
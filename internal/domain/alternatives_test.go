@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+func TestAlternativeNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   string
+		tlds     []string
+		expected []string
+	}{
+		{"other TLDs", "example.com", []string{"dev", "io", "app"}, []string{"example.dev", "example.io", "example.app"}},
+		{"own TLD left out", "example.io", []string{"com", "io", ".IO", "dev"}, []string{"example.com", "example.dev"}},
+		{"subdomain", "www.example.com", []string{"io"}, []string{"example.io"}},
+		{"multi-label TLDs", "example.co.uk", []string{"com", "co.uk", "org.uk"}, []string{"example.com", "example.org.uk"}},
+		{"public suffix", "co.uk", []string{"com"}, nil},
+		{"no TLDs", "example.com", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AlternativeNames(tt.domain, tt.tlds); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("AlternativeNames(%q, %v) = %v, want %v", tt.domain, tt.tlds, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindAlternatives(t *testing.T) {
+	available := map[string]bool{"free.com": true, "example.io": true, "taken.io": true}
+	client := &scriptedRoute53Client{
+		fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+			if available[domain] {
+				return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable}, nil
+			}
+			return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityUnavailable}, nil
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+
+	results, err := checker.CheckAvailabilityBulk(context.Background(), []string{"example.com", "free.com", "taken.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := checker.FindAlternatives(context.Background(), results, []string{"io", "dev"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	names := func(result *AvailabilityResult) []string {
+		var names []string
+		for _, alternative := range result.Alternatives {
+			names = append(names, alternative.Domain)
+		}
+		return names
+	}
+	if got := names(results[0]); !reflect.DeepEqual(got, []string{"example.io"}) {
+		t.Errorf("Expected example.io as the alternative of example.com, got %v", got)
+	}
+	if got := names(results[1]); got != nil {
+		t.Errorf("Expected no alternatives for an available domain, got %v", got)
+	}
+	if got := names(results[2]); !reflect.DeepEqual(got, []string{"taken.io"}) {
+		t.Errorf("Expected taken.io as the alternative of taken.com, got %v", got)
+	}
+	if client.calls["free.io"] != 0 {
+		t.Error("Expected no alternatives to be checked for available domains")
+	}
+}
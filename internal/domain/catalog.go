@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/abakermi/r53check/internal/storage"
+)
+
+// DefaultCatalogTTL is how long a cached TLD catalog is used before it is refreshed
+const DefaultCatalogTTL = 24 * time.Hour
+
+// TLDSource lists every TLD that Route 53 Domains can register
+type TLDSource interface {
+	ListTLDs(ctx context.Context) ([]string, error)
+}
+
+// CatalogOrigin describes where a loaded TLD catalog came from
+type CatalogOrigin string
+
+const (
+	OriginCache   CatalogOrigin = "cache"    // Fresh on-disk copy
+	OriginAPI     CatalogOrigin = "api"      // Fetched from Route 53 Domains
+	OriginStale   CatalogOrigin = "stale"    // Expired on-disk copy, used because the API failed
+	OriginBuiltIn CatalogOrigin = "built-in" // Static fallback list
+)
+
+// catalogFile is the on-disk representation of a cached TLD catalog
+type catalogFile struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	TLDs      []string  `json:"tlds"`
+}
+
+// TLDCatalog loads the set of supported TLDs, preferring a fresh cached copy,
+// then the Route 53 API, then a stale cached copy, then the built-in list
+type TLDCatalog struct {
+	source    TLDSource
+	cachePath string
+	ttl       time.Duration
+}
+
+// NewTLDCatalog creates a catalog backed by source and cached at cachePath.
+// An empty cachePath disables the on-disk cache.
+func NewTLDCatalog(source TLDSource, cachePath string, ttl time.Duration) *TLDCatalog {
+	return &TLDCatalog{
+		source:    source,
+		cachePath: cachePath,
+		ttl:       ttl,
+	}
+}
+
+// DefaultCatalogPath returns the default location of the cached TLD catalog
+func DefaultCatalogPath() string {
+	return storage.DefaultDirs().TLDCatalog()
+}
+
+// Load returns the supported TLDs and where they came from. It never fails
+// outright: when neither the cache nor the API is usable the built-in list is
+// returned along with the error that prevented a fresh fetch.
+func (c *TLDCatalog) Load(ctx context.Context) ([]string, CatalogOrigin, error) {
+	cached, cacheErr := c.readCache()
+	if cacheErr == nil && time.Since(cached.FetchedAt) < c.ttl {
+		return cached.TLDs, OriginCache, nil
+	}
+
+	tlds, fetchErr := c.fetch(ctx)
+	if fetchErr == nil {
+		// A failed cache write only costs a refetch next time
+		_ = c.writeCache(tlds)
+		return tlds, OriginAPI, nil
+	}
+
+	if cacheErr == nil {
+		return cached.TLDs, OriginStale, fetchErr
+	}
+
+	return DefaultTLDs(), OriginBuiltIn, fetchErr
+}
+
+// Refresh fetches the catalog from the API and rewrites the cache regardless of its age
+func (c *TLDCatalog) Refresh(ctx context.Context) ([]string, error) {
+	tlds, err := c.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.writeCache(tlds); err != nil {
+		return tlds, err
+	}
+	return tlds, nil
+}
+
+// fetch asks the source for the current TLD list
+func (c *TLDCatalog) fetch(ctx context.Context) ([]string, error) {
+	if c.source == nil {
+		return nil, fmt.Errorf("no TLD source configured")
+	}
+
+	tlds, err := c.source.ListTLDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(tlds) == 0 {
+		return nil, fmt.Errorf("TLD source returned an empty catalog")
+	}
+	return tlds, nil
+}
+
+// readCache loads the cached catalog from disk
+func (c *TLDCatalog) readCache() (*catalogFile, error) {
+	if c.cachePath == "" {
+		return nil, fmt.Errorf("TLD catalog cache disabled")
+	}
+
+	data, err := os.ReadFile(c.cachePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var cached catalogFile
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, fmt.Errorf("corrupt TLD catalog cache: %w", err)
+	}
+	if len(cached.TLDs) == 0 {
+		return nil, fmt.Errorf("empty TLD catalog cache")
+	}
+	return &cached, nil
+}
+
+// writeCache stores the catalog on disk with the current time
+func (c *TLDCatalog) writeCache(tlds []string) error {
+	if c.cachePath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(catalogFile{FetchedAt: time.Now(), TLDs: tlds})
+	if err != nil {
+		return err
+	}
+
+	return writeCacheFile(c.cachePath, data)
+}
+
+// writeCacheFile writes data to path, creating its directory
+func writeCacheFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// mockTLDSource implements the TLDSource interface for testing
+type mockTLDSource struct {
+	tlds  []string
+	err   error
+	calls int
+}
+
+func (m *mockTLDSource) ListTLDs(ctx context.Context) ([]string, error) {
+	m.calls++
+	return m.tlds, m.err
+}
+
+func writeCatalogCache(t *testing.T, path string, fetchedAt time.Time, tlds []string) {
+	t.Helper()
+	data, err := json.Marshal(catalogFile{FetchedAt: fetchedAt, TLDs: tlds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTLDCatalog_Load(t *testing.T) {
+	apiErr := errors.New("access denied")
+
+	tests := []struct {
+		name           string
+		cacheAge       time.Duration // negative means no cache file
+		source         *mockTLDSource
+		expectedOrigin CatalogOrigin
+		expectedFirst  string
+		expectErr      bool
+		expectFetch    bool
+	}{
+		{
+			name:           "fresh cache is used without calling the API",
+			cacheAge:       time.Hour,
+			source:         &mockTLDSource{tlds: []string{"dev"}},
+			expectedOrigin: OriginCache,
+			expectedFirst:  "cached",
+		},
+		{
+			name:           "stale cache is refreshed from the API",
+			cacheAge:       48 * time.Hour,
+			source:         &mockTLDSource{tlds: []string{"dev", "app"}},
+			expectedOrigin: OriginAPI,
+			expectedFirst:  "dev",
+			expectFetch:    true,
+		},
+		{
+			name:           "no cache fetches from the API",
+			cacheAge:       -1,
+			source:         &mockTLDSource{tlds: []string{"xyz"}},
+			expectedOrigin: OriginAPI,
+			expectedFirst:  "xyz",
+			expectFetch:    true,
+		},
+		{
+			name:           "stale cache is used when the API fails",
+			cacheAge:       48 * time.Hour,
+			source:         &mockTLDSource{err: apiErr},
+			expectedOrigin: OriginStale,
+			expectedFirst:  "cached",
+			expectErr:      true,
+			expectFetch:    true,
+		},
+		{
+			name:           "built-in list is used when offline without a cache",
+			cacheAge:       -1,
+			source:         &mockTLDSource{err: apiErr},
+			expectedOrigin: OriginBuiltIn,
+			expectedFirst:  "com",
+			expectErr:      true,
+			expectFetch:    true,
+		},
+		{
+			name:           "empty API response falls back to built-in list",
+			cacheAge:       -1,
+			source:         &mockTLDSource{},
+			expectedOrigin: OriginBuiltIn,
+			expectedFirst:  "com",
+			expectErr:      true,
+			expectFetch:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tlds.json")
+			if tt.cacheAge >= 0 {
+				writeCatalogCache(t, path, time.Now().Add(-tt.cacheAge), []string{"cached"})
+			}
+
+			catalog := NewTLDCatalog(tt.source, path, 24*time.Hour)
+			tlds, origin, err := catalog.Load(context.Background())
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if origin != tt.expectedOrigin {
+				t.Errorf("expected origin %s, got %s", tt.expectedOrigin, origin)
+			}
+			if len(tlds) == 0 || tlds[0] != tt.expectedFirst {
+				t.Errorf("expected first TLD %s, got %v", tt.expectedFirst, tlds)
+			}
+			if (tt.source.calls > 0) != tt.expectFetch {
+				t.Errorf("expected fetch %v, got %d calls", tt.expectFetch, tt.source.calls)
+			}
+		})
+	}
+}
+
+func TestTLDCatalog_LoadWritesCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "tlds.json")
+	source := &mockTLDSource{tlds: []string{"dev", "app"}}
+
+	if _, _, err := NewTLDCatalog(source, path, time.Hour).Load(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A second catalog should now be served from disk
+	second := &mockTLDSource{err: errors.New("should not be called")}
+	tlds, origin, err := NewTLDCatalog(second, path, time.Hour).Load(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if origin != OriginCache || len(tlds) != 2 || second.calls != 0 {
+		t.Errorf("expected cached catalog, got origin %s, tlds %v, calls %d", origin, tlds, second.calls)
+	}
+}
+
+func TestTLDCatalog_Refresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tlds.json")
+	writeCatalogCache(t, path, time.Now(), []string{"cached"})
+
+	source := &mockTLDSource{tlds: []string{"dev"}}
+	tlds, err := NewTLDCatalog(source, path, time.Hour).Refresh(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if source.calls != 1 || len(tlds) != 1 || tlds[0] != "dev" {
+		t.Errorf("expected refresh to bypass a fresh cache, got %v after %d calls", tlds, source.calls)
+	}
+}
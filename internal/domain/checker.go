@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"sync"
 	"time"
 
@@ -24,6 +23,12 @@ const (
 	StatusUnknown     AvailabilityStatus = "UNKNOWN"
 )
 
+// defaultRetryDelay is the pause between attempts in the bulk retry pass
+const defaultRetryDelay = 1 * time.Second
+
+// defaultConcurrency limits concurrent bulk requests to stay under AWS rate limits
+const defaultConcurrency = 5
+
 // PricingInfo contains domain pricing information
 type PricingInfo struct {
 	RegistrationPrice *float64
@@ -34,13 +39,39 @@ type PricingInfo struct {
 
 // AvailabilityResult contains the result of a domain availability check
 type AvailabilityResult struct {
-	Domain    string
-	Available bool
-	Status    AvailabilityStatus
-	Message   string
-	CheckedAt time.Time
-	Error     error
-	Pricing   *PricingInfo // Optional pricing information
+	Domain        string
+	UnicodeDomain string // Unicode form of an internationalized domain, empty otherwise
+	Available     bool
+	Status        AvailabilityStatus
+	Message       string
+	CheckedAt     time.Time
+	CompletedAt   time.Time // Set when a bulk check finishes, used for completion ordering
+	Error         error
+	Pricing       *PricingInfo // Optional pricing information
+	Retried       bool         // Set when the result comes from the bulk retry pass
+	Retries       int          // Number of times the domain was checked again after failing
+	Skipped       bool         // Set when a bulk run was aborted or cancelled before checking the domain
+	FromHistory   bool         // Set when a bulk run reused a recent check from history instead of checking again
+	Warnings      []string     // Lookalike and screening warnings, which never affect availability
+	Note          string       // Note given next to the domain in a bulk input list
+	Source        string       // Service that answered when a fallback checked the domain instead of Route 53, such as "whois"
+	Unverified    bool         // Set when the status comes from a fallback and could not be confirmed by Route 53
+	Prescreened   bool         // Set when DNS showed the domain is registered, so Route 53 was not asked
+
+	// Alternatives are the available names of an unavailable domain under
+	// other TLDs, with pricing, when asked for with FindAlternatives
+	Alternatives []*AvailabilityResult
+
+	// Timings records how long each phase of the last attempt took
+	Timings CheckTimings
+}
+
+// CheckTimings splits the duration of a check into its phases
+type CheckTimings struct {
+	Validation time.Duration // Validating, converting and screening the name
+	API        time.Duration // Waiting for CheckDomainAvailability
+	Pricing    time.Duration // Looking up the TLD price; zero without pricing
+	Prescreen  time.Duration // Looking the domain up in DNS; zero without prescreening
 }
 
 // Route53Client interface defines the methods needed for domain availability checking
@@ -57,49 +88,105 @@ type Checker interface {
 
 // DomainChecker implements the Checker interface
 type DomainChecker struct {
-	validator Validator
-	awsClient Route53Client
-	timeout   time.Duration
+	validator       Validator
+	awsClient       Route53Client
+	timeout         time.Duration
+	retryDelay      time.Duration
+	retryPolicy     *customErrors.RetryPolicy
+	failFast        bool
+	batchSize       int
+	batchDelay      time.Duration
+	warnConfusables bool
+	screeners       []Screener
+	concurrency     int
+	hooks           []Hooks
+	prices          priceCache
+	priceList       map[string]*PricingInfo
+	fallback        Fallback
+	prescreener     Prescreener
 }
 
 // NewDomainChecker creates a new domain checker with the provided dependencies
 func NewDomainChecker(validator Validator, awsClient Route53Client) *DomainChecker {
 	return &DomainChecker{
-		validator: validator,
-		awsClient: awsClient,
-		timeout:   10 * time.Second, // Default 10-second timeout
+		validator:   validator,
+		awsClient:   awsClient,
+		timeout:     10 * time.Second, // Default 10-second timeout
+		retryDelay:  defaultRetryDelay,
+		retryPolicy: customErrors.DefaultRetryPolicy(),
+		concurrency: defaultConcurrency,
 	}
 }
 
 // NewDomainCheckerWithTimeout creates a new domain checker with a custom timeout
 func NewDomainCheckerWithTimeout(validator Validator, awsClient Route53Client, timeout time.Duration) *DomainChecker {
 	return &DomainChecker{
-		validator: validator,
-		awsClient: awsClient,
-		timeout:   timeout,
+		validator:   validator,
+		awsClient:   awsClient,
+		timeout:     timeout,
+		retryDelay:  defaultRetryDelay,
+		retryPolicy: customErrors.DefaultRetryPolicy(),
+		concurrency: defaultConcurrency,
 	}
 }
 
 // CheckAvailability checks if a domain is available for registration
 func (c *DomainChecker) CheckAvailability(ctx context.Context, domain string) (*AvailabilityResult, error) {
+	return c.observe(ctx, domain, c.checkAvailability)
+}
+
+// checkAvailability performs the check for CheckAvailability without notifying hooks
+func (c *DomainChecker) checkAvailability(ctx context.Context, domain string) (*AvailabilityResult, error) {
 	result := &AvailabilityResult{
 		Domain:    domain,
 		CheckedAt: time.Now(),
 	}
 
-	// Validate domain format first
+	// Validate domain format first; a domain under an unsupported TLD is
+	// left to the fallback, if there is one
+	useFallback := false
 	if err := c.validator.ValidateDomain(domain); err != nil {
-		result.Error = err
-		result.Status = StatusUnknown
-		return result, err
+		if c.fallback == nil || !unsupportedTLD(domain, err) {
+			result.Timings.Validation = time.Since(result.CheckedAt)
+			result.Error = err
+			result.Status = StatusUnknown
+			return result, err
+		}
+		useFallback = true
+	}
+
+	// Internationalized names are sent to AWS in their ASCII (punycode) form
+	if IsIDN(domain) {
+		if asciiDomain, err := ToASCII(domain); err == nil {
+			result.Domain = asciiDomain
+			result.UnicodeDomain = ToUnicode(asciiDomain)
+		}
+	}
+
+	if c.warnConfusables {
+		result.Warnings = ConfusableWarnings(result.Domain)
+	}
+	for _, screener := range c.screeners {
+		result.Warnings = append(result.Warnings, screener.Screen(result.Domain)...)
 	}
 
 	// Create context with timeout
 	timeoutCtx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
+	// Domains shown to be registered need neither Route 53 nor the fallback
+	result.Timings.Validation = time.Since(result.CheckedAt)
+	if c.prescreen(timeoutCtx, result) {
+		return result, nil
+	}
+	if useFallback {
+		return c.checkFallback(timeoutCtx, result)
+	}
+
 	// Call AWS API to check domain availability
-	awsResult, err := c.awsClient.CheckDomainAvailability(timeoutCtx, domain)
+	apiStart := time.Now()
+	awsResult, err := c.awsClient.CheckDomainAvailability(timeoutCtx, result.Domain)
+	result.Timings.API = time.Since(apiStart)
 	if err != nil {
 		// Wrap the error if it's not already a custom error
 		var customErr interface {
@@ -122,15 +209,25 @@ func (c *DomainChecker) CheckAvailability(ctx context.Context, domain string) (*
 
 // CheckAvailabilityWithPricing checks domain availability and includes pricing information
 func (c *DomainChecker) CheckAvailabilityWithPricing(ctx context.Context, domain string) (*AvailabilityResult, error) {
+	return c.observe(ctx, domain, c.checkAvailabilityWithPricing)
+}
+
+// checkAvailabilityWithPricing performs the check for CheckAvailabilityWithPricing
+// without notifying hooks, so they see a single check that includes pricing
+func (c *DomainChecker) checkAvailabilityWithPricing(ctx context.Context, domain string) (*AvailabilityResult, error) {
 	// First check availability
-	result, err := c.CheckAvailability(ctx, domain)
+	result, err := c.checkAvailability(ctx, domain)
 	if err != nil {
 		return result, err
 	}
 
-	// If domain is available, get pricing information
-	if result.Available {
-		if err := c.addPricingInfo(ctx, domain, result); err != nil {
+	// If domain is available, get pricing information; Route 53 has no
+	// prices for the TLDs a fallback checks
+	if result.Available && !result.Unverified {
+		pricingStart := time.Now()
+		err := c.addPricingInfo(ctx, result.Domain, result)
+		result.Timings.Pricing = time.Since(pricingStart)
+		if err != nil {
 			// Don't fail the entire request if pricing fails, just log it
 			// The availability check was successful
 			if c.timeout > 0 {
@@ -151,49 +248,23 @@ func (c *DomainChecker) addPricingInfo(ctx context.Context, domain string, resul
 		return fmt.Errorf("unable to extract TLD from domain: %s", domain)
 	}
 
-	// Create context with timeout
-	timeoutCtx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	// Get pricing information for the TLD
-	priceResult, err := c.awsClient.ListPrices(timeoutCtx, tld)
+	pricing, err := c.TLDPricing(ctx, tld)
 	if err != nil {
 		return err
 	}
-
-	if priceResult != nil && len(priceResult.Prices) > 0 {
-		pricing := &PricingInfo{
-			Currency: "USD", // Route 53 pricing is in USD
-		}
-
-		// Extract pricing information from the first price entry
-		price := priceResult.Prices[0]
-		if price.RegistrationPrice != nil {
-			regPrice := price.RegistrationPrice.Price
-			pricing.RegistrationPrice = &regPrice
-		}
-		if price.RenewalPrice != nil {
-			renewPrice := price.RenewalPrice.Price
-			pricing.RenewalPrice = &renewPrice
-		}
-		if price.TransferPrice != nil {
-			transferPrice := price.TransferPrice.Price
-			pricing.TransferPrice = &transferPrice
-		}
-
-		result.Pricing = pricing
+	if pricing != nil {
+		// Each result gets its own copy of the shared TLD price
+		copied := *pricing
+		result.Pricing = &copied
 	}
 
 	return nil
 }
 
-// extractTLD extracts the top-level domain from a full domain name
+// extractTLD extracts the effective top-level domain from a full domain name,
+// so second-level registrations like "example.co.uk" are priced as "co.uk"
 func (c *DomainChecker) extractTLD(domain string) string {
-	parts := strings.Split(domain, ".")
-	if len(parts) < 2 {
-		return ""
-	}
-	return parts[len(parts)-1]
+	return EffectiveTLD(domain)
 }
 
 // mapAWSResponse maps AWS API response to our business domain model
@@ -244,112 +315,259 @@ func (c *DomainChecker) GetTimeout() time.Duration {
 	return c.timeout
 }
 
-// CheckAvailabilityBulk checks availability for multiple domains concurrently
+// CheckAvailabilityBulk checks availability for multiple domains concurrently.
+// When ctx is cancelled or times out, the results that completed are still
+// returned with the error, and the other domains are marked Skipped.
 func (c *DomainChecker) CheckAvailabilityBulk(ctx context.Context, domains []string) ([]*AvailabilityResult, error) {
+	return c.checkBulk(ctx, domains, c.CheckAvailability)
+}
+
+// CheckAvailabilityBulkWithPricing checks availability for multiple domains concurrently with pricing
+func (c *DomainChecker) CheckAvailabilityBulkWithPricing(ctx context.Context, domains []string) ([]*AvailabilityResult, error) {
+	return c.checkBulk(ctx, domains, c.CheckAvailabilityWithPricing)
+}
+
+// checkBulk runs check for every domain with bounded concurrency, then
+// re-attempts domains that failed with a retryable error in a slower
+// sequential second pass
+func (c *DomainChecker) checkBulk(ctx context.Context, domains []string, check func(context.Context, string) (*AvailabilityResult, error)) ([]*AvailabilityResult, error) {
 	if len(domains) == 0 {
 		return nil, customErrors.NewValidationError("", "domains", "no domains provided for bulk check", nil)
 	}
 
-	// Create a channel to collect results
 	results := make([]*AvailabilityResult, len(domains))
-	errors := make([]error, len(domains))
+	errs := make([]error, len(domains))
+
+	// In fail-fast mode the first non-retryable failure cancels runCtx,
+	// which stops the remaining checks from reaching the API. An invalid
+	// domain only fails its own check, as it never reached the API.
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+	var abortOnce sync.Once
+	var abortErr error
 
 	// Use a semaphore to limit concurrent requests (AWS rate limiting)
-	semaphore := make(chan struct{}, 5) // Limit to 5 concurrent requests
+	semaphore := make(chan struct{}, c.concurrency)
+
+	batchSize := c.batchSize
+	if batchSize <= 0 {
+		batchSize = len(domains)
+	}
+
+	for start := 0; start < len(domains); start += batchSize {
+		// Pause between batches to stay under API quotas
+		if start > 0 && c.batchDelay > 0 {
+			select {
+			case <-runCtx.Done():
+			case <-time.After(c.batchDelay):
+			}
+		}
+		if runCtx.Err() != nil {
+			break
+		}
+
+		end := start + batchSize
+		if end > len(domains) {
+			end = len(domains)
+		}
 
-	// Use a wait group to wait for all goroutines
-	var wg sync.WaitGroup
+		// Use a wait group to wait for all goroutines in the batch
+		var wg sync.WaitGroup
+
+		for i := start; i < end; i++ {
+			wg.Add(1)
+			go func(index int, domainName string) {
+				defer wg.Done()
+
+				// Acquire semaphore
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+
+				if runCtx.Err() != nil {
+					return
+				}
+
+				result, err := check(runCtx, domainName)
+				if result != nil {
+					result.CompletedAt = time.Now()
+				}
+				results[index] = result
+				errs[index] = err
+
+				if err != nil && c.failFast && c.retryBudget(runCtx, err) == 0 &&
+					customErrors.GetCategory(err) != customErrors.CategoryValidation {
+					abortOnce.Do(func() {
+						abortErr = err
+						cancelRun()
+					})
+				}
+			}(i, domains[i])
+		}
 
-	for i, domain := range domains {
-		wg.Add(1)
-		go func(index int, domainName string) {
-			defer wg.Done()
+		// Wait for all goroutines to complete
+		wg.Wait()
+	}
 
-			// Acquire semaphore
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
+	// Check if context was cancelled or timed out, keeping the results
+	// that completed
+	if ctx.Err() != nil {
+		markSkipped(domains, results, errs, ctx.Err(), nil, skippedAfterCancel)
+		return results, customErrors.WrapSystemError("bulk-check", ctx.Err())
+	}
 
-			result, err := c.CheckAvailability(ctx, domainName)
-			results[index] = result
-			errors[index] = err
-		}(i, domain)
+	// Mark everything the abort prevented from completing as skipped
+	if abortErr != nil {
+		markSkipped(domains, results, errs, context.Canceled, abortErr, "check skipped after an earlier failure aborted the run")
+		return results, abortErr
 	}
 
-	// Wait for all goroutines to complete
-	wg.Wait()
+	// Give throttled and timed out domains a second chance, one at a time
+	c.retryFailed(ctx, domains, results, errs, check)
 
-	// Check if context was cancelled
 	if ctx.Err() != nil {
+		markSkipped(domains, results, errs, ctx.Err(), nil, skippedAfterCancel)
 		return results, customErrors.WrapSystemError("bulk-check", ctx.Err())
 	}
 
 	// Count successful results
 	successCount := 0
-	for _, err := range errors {
+	for _, err := range errs {
 		if err == nil {
 			successCount++
 		}
 	}
 
 	// If no results were successful, return the first error
-	if successCount == 0 && len(errors) > 0 {
-		return results, errors[0]
+	if successCount == 0 && len(errs) > 0 {
+		return results, errs[0]
 	}
 
 	return results, nil
 }
 
-// CheckAvailabilityBulkWithPricing checks availability for multiple domains concurrently with pricing
-func (c *DomainChecker) CheckAvailabilityBulkWithPricing(ctx context.Context, domains []string) ([]*AvailabilityResult, error) {
-	if len(domains) == 0 {
-		return nil, customErrors.NewValidationError("", "domains", "no domains provided for bulk check", nil)
+// retryFailed re-checks every domain whose check failed with a retryable
+// error, sequentially and with retryDelay between attempts, in passes until
+// no domain has retries left in the budget for its latest failure. Results
+// and errors are updated in place.
+func (c *DomainChecker) retryFailed(ctx context.Context, domains []string, results []*AvailabilityResult, errs []error, check func(context.Context, string) (*AvailabilityResult, error)) {
+	if c.retryDelay < 0 {
+		return
 	}
 
-	// Create a channel to collect results
-	results := make([]*AvailabilityResult, len(domains))
-	errors := make([]error, len(domains))
-
-	// Use a semaphore to limit concurrent requests (AWS rate limiting)
-	semaphore := make(chan struct{}, 5) // Limit to 5 concurrent requests
-
-	// Use a wait group to wait for all goroutines
-	var wg sync.WaitGroup
-
-	for i, domain := range domains {
-		wg.Add(1)
-		go func(index int, domainName string) {
-			defer wg.Done()
-
-			// Acquire semaphore
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
+	retries := make([]int, len(domains))
+	first := true
+	for retried := true; retried; {
+		retried = false
+		for i, err := range errs {
+			if err == nil || retries[i] >= c.retryBudget(ctx, err) {
+				continue
+			}
 
-			result, err := c.CheckAvailabilityWithPricing(ctx, domainName)
-			results[index] = result
-			errors[index] = err
-		}(i, domain)
+			if !first {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(c.retryDelay):
+				}
+			}
+			first = false
+			retried = true
+
+			retries[i]++
+			c.notifyRetry(ctx, domains[i], retries[i]+1, err)
+			result, retryErr := check(ctx, domains[i])
+			if result != nil {
+				result.Retried = true
+				result.Retries = retries[i]
+				result.CompletedAt = time.Now()
+			}
+			results[i] = result
+			errs[i] = retryErr
+		}
 	}
+}
 
-	// Wait for all goroutines to complete
-	wg.Wait()
-
-	// Check if context was cancelled
-	if ctx.Err() != nil {
-		return results, customErrors.WrapSystemError("bulk-check", ctx.Err())
+// retryBudget returns how many times a check that failed with err may be
+// retried under the retry policy. Per-request timeouts are only retried as
+// long as the overall run has not been cancelled.
+func (c *DomainChecker) retryBudget(ctx context.Context, err error) int {
+	if customErrors.RetryKey(err) == customErrors.RetryTimeout && ctx.Err() != nil {
+		return 0
 	}
+	return c.retryPolicy.Budget(err)
+}
 
-	// Count successful results
-	successCount := 0
-	for _, err := range errors {
-		if err == nil {
-			successCount++
+// skippedAfterCancel is the reason given for domains a cancelled or timed
+// out bulk run did not get to
+const skippedAfterCancel = "check skipped after the run was cancelled"
+
+// markSkipped replaces the result of every domain an aborted bulk run did
+// not complete with a skipped result. Checks cut short with interruption, the
+// context error that stopped the run, count as not completed, except cause,
+// the failure that aborted the run.
+func markSkipped(domains []string, results []*AvailabilityResult, errs []error, interruption, cause error, reason string) {
+	for i := range results {
+		if results[i] == nil || (errs[i] != cause && errors.Is(errs[i], interruption)) {
+			results[i] = SkippedResult(domains[i], reason)
+			errs[i] = results[i].Error
 		}
 	}
+}
 
-	// If no results were successful, return the first error
-	if successCount == 0 && len(errors) > 0 {
-		return results, errors[0]
+// SkippedResult builds the result for a domain that was never checked
+// because its bulk run was aborted, with reason as the error message
+func SkippedResult(domain, reason string) *AvailabilityResult {
+	return &AvailabilityResult{
+		Domain:    domain,
+		Status:    StatusUnknown,
+		Message:   fmt.Sprintf("Check for domain %s was skipped", domain),
+		CheckedAt: time.Now(),
+		Error:     customErrors.NewSystemError("bulk-check", reason, nil),
+		Skipped:   true,
 	}
+}
 
-	return results, nil
+// SetFailFast controls whether a bulk check aborts on the first
+// non-retryable failure instead of continuing with the remaining domains.
+// Invalid domains are reported without aborting.
+func (c *DomainChecker) SetFailFast(failFast bool) {
+	c.failFast = failFast
+}
+
+// SetBatching splits bulk checks into batches of size domains with a pause
+// of delay between batches. A size of zero or less disables batching.
+func (c *DomainChecker) SetBatching(size int, delay time.Duration) {
+	c.batchSize = size
+	c.batchDelay = delay
+}
+
+// SetRetryDelay sets the pause between attempts in the bulk retry pass.
+// A negative delay disables the retry pass.
+func (c *DomainChecker) SetRetryDelay(delay time.Duration) {
+	c.retryDelay = delay
+}
+
+// SetRetryPolicy sets how many times each kind of failure is retried
+func (c *DomainChecker) SetRetryPolicy(policy *customErrors.RetryPolicy) {
+	c.retryPolicy = policy
+}
+
+// SetWarnConfusables enables warnings for domains that look like popular
+// domains or mix scripts
+func (c *DomainChecker) SetWarnConfusables(warn bool) {
+	c.warnConfusables = warn
+}
+
+// AddScreener registers a screener whose warnings are added to every result
+func (c *DomainChecker) AddScreener(screener Screener) {
+	c.screeners = append(c.screeners, screener)
+}
+
+// SetConcurrency sets how many bulk requests run at once. Values below one
+// restore the default of five.
+func (c *DomainChecker) SetConcurrency(n int) {
+	if n < 1 {
+		n = defaultConcurrency
+	}
+	c.concurrency = n
 }
@@ -3,6 +3,8 @@ package domain
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -697,3 +699,523 @@ func TestCheckAvailability_CustomErrorPreservation(t *testing.T) {
 		t.Errorf("expected result.Error to be same instance, got: %v", result.Error)
 	}
 }
+
+// scriptedRoute53Client lets each test decide the response per call, safely across goroutines
+type scriptedRoute53Client struct {
+	mu    sync.Mutex
+	calls map[string]int
+	fn    func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error)
+}
+
+func (m *scriptedRoute53Client) CheckDomainAvailability(ctx context.Context, domain string) (*route53domains.CheckDomainAvailabilityOutput, error) {
+	m.mu.Lock()
+	if m.calls == nil {
+		m.calls = make(map[string]int)
+	}
+	m.calls[domain]++
+	attempt := m.calls[domain]
+	m.mu.Unlock()
+
+	return m.fn(ctx, domain, attempt)
+}
+
+func (m *scriptedRoute53Client) ListPrices(ctx context.Context, tld string) (*route53domains.ListPricesOutput, error) {
+	return nil, nil
+}
+
+func TestCheckAvailabilityBulk_RetryPass(t *testing.T) {
+	throttled := customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "rate limited", nil).WithStatusCode(429)
+	denied := customErrors.NewAuthorizationError("CheckDomainAvailability", "route53domains", "denied", nil)
+
+	client := &scriptedRoute53Client{
+		fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+			switch domain {
+			case "throttled.com":
+				if attempt == 1 {
+					return nil, throttled
+				}
+			case "always-throttled.com":
+				return nil, throttled
+			case "denied.com":
+				return nil, denied
+			}
+			return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable}, nil
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+	checker.SetRetryDelay(0)
+
+	domains := []string{"ok.com", "throttled.com", "always-throttled.com", "denied.com"}
+	results, err := checker.CheckAvailabilityBulk(context.Background(), domains)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if results[0].Retried || results[0].Error != nil {
+		t.Errorf("Expected ok.com to succeed on the first pass, got %+v", results[0])
+	}
+	if !results[1].Retried || results[1].Error != nil || !results[1].Available {
+		t.Errorf("Expected throttled.com to succeed on the retry pass, got %+v", results[1])
+	}
+	if !results[2].Retried || results[2].Error == nil {
+		t.Errorf("Expected always-throttled.com to fail after retry, got %+v", results[2])
+	}
+	if results[3].Retried {
+		t.Error("Expected non-retryable failure not to be retried")
+	}
+
+	if client.calls["denied.com"] != 1 {
+		t.Errorf("Expected 1 call for denied.com, got %d", client.calls["denied.com"])
+	}
+	if client.calls["always-throttled.com"] != 2 {
+		t.Errorf("Expected 2 calls for always-throttled.com, got %d", client.calls["always-throttled.com"])
+	}
+}
+
+func TestCheckAvailabilityBulk_RetryPassDisabled(t *testing.T) {
+	client := &scriptedRoute53Client{
+		fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+			return nil, customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "rate limited", nil).WithStatusCode(429)
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+	checker.SetRetryDelay(-1)
+
+	results, err := checker.CheckAvailabilityBulk(context.Background(), []string{"example.com"})
+	if err == nil {
+		t.Fatal("Expected error when every domain fails")
+	}
+	if results[0].Retried {
+		t.Error("Expected no retry pass when disabled")
+	}
+	if client.calls["example.com"] != 1 {
+		t.Errorf("Expected 1 call, got %d", client.calls["example.com"])
+	}
+}
+
+func TestCheckAvailabilityBulk_RetryPolicy(t *testing.T) {
+	throttled := customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "rate limited", nil).WithStatusCode(429)
+	unavailable := customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "unavailable", nil).WithStatusCode(503)
+
+	client := &scriptedRoute53Client{
+		fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+			switch domain {
+			case "flaky.com":
+				if attempt <= 2 {
+					return nil, throttled
+				}
+			case "throttled.com":
+				return nil, throttled
+			case "unavailable.com":
+				return nil, unavailable
+			}
+			return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable}, nil
+		},
+	}
+	policy, err := customErrors.NewRetryPolicy(map[string]int{"429": 3, "503": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+	checker.SetRetryDelay(0)
+	checker.SetRetryPolicy(policy)
+
+	results, err := checker.CheckAvailabilityBulk(context.Background(), []string{"flaky.com", "throttled.com", "unavailable.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if results[0].Error != nil || results[0].Retries != 2 {
+		t.Errorf("Expected flaky.com to succeed after 2 retries, got %+v", results[0])
+	}
+	if results[1].Error == nil || results[1].Retries != 3 || client.calls["throttled.com"] != 4 {
+		t.Errorf("Expected throttled.com to fail after 3 retries, got %+v in %d calls", results[1], client.calls["throttled.com"])
+	}
+	if results[2].Retried || client.calls["unavailable.com"] != 1 {
+		t.Errorf("Expected unavailable.com not to be retried, got %+v in %d calls", results[2], client.calls["unavailable.com"])
+	}
+}
+
+func TestCheckAvailabilityBulk_FailFast(t *testing.T) {
+	denied := customErrors.NewAuthorizationError("CheckDomainAvailability", "route53domains", "denied", nil)
+
+	client := &scriptedRoute53Client{
+		fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+			if domain == "denied.com" {
+				return nil, denied
+			}
+			// Hold the other checks open so the abort can overtake them
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(2 * time.Second):
+			}
+			return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable}, nil
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+	checker.SetFailFast(true)
+
+	domains := []string{"denied.com", "a.com", "b.com", "c.com", "d.com", "e.com", "f.com", "g.com"}
+	results, err := checker.CheckAvailabilityBulk(context.Background(), domains)
+
+	if err != denied {
+		t.Fatalf("Expected the authorization error to be returned, got %v", err)
+	}
+	if results[0].Skipped || results[0].Error != denied {
+		t.Errorf("Expected denied.com to carry the original error, got %+v", results[0])
+	}
+
+	skipped := 0
+	for i, result := range results[1:] {
+		if result == nil {
+			t.Fatalf("Expected a result for %s", domains[i+1])
+		}
+		if result.Skipped {
+			skipped++
+			if result.Error == nil {
+				t.Errorf("Expected skipped result for %s to carry an error", result.Domain)
+			}
+		}
+	}
+	if skipped == 0 {
+		t.Error("Expected remaining domains to be skipped after the abort")
+	}
+}
+
+func TestCheckAvailabilityBulk_FailFastSkipsInvalidDomains(t *testing.T) {
+	client := &scriptedRoute53Client{
+		fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+			return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable}, nil
+		},
+	}
+	checker := NewDomainChecker(NewDomainValidatorWithTLDs([]string{"com"}), client)
+	checker.SetFailFast(true)
+	checker.SetConcurrency(1)
+
+	domains := []string{"bad-.com", "a.com", "b.com", "c.com"}
+	results, err := checker.CheckAvailabilityBulk(context.Background(), domains)
+
+	if err != nil {
+		t.Fatalf("Expected an invalid domain not to abort the run, got %v", err)
+	}
+	if results[0] == nil || results[0].Skipped || customErrors.GetCategory(results[0].Error) != customErrors.CategoryValidation {
+		t.Errorf("Expected bad-.com to carry a validation error, got %+v", results[0])
+	}
+	for _, result := range results[1:] {
+		if result == nil || result.Skipped || !result.Available {
+			t.Errorf("Expected every valid domain to be checked, got %+v", result)
+		}
+	}
+}
+
+func TestCheckAvailabilityBulk_CancelKeepsCompletedResults(t *testing.T) {
+	tests := []struct {
+		name        string
+		interrupt   func(ctx context.Context) (context.Context, func())
+		expectedErr error
+	}{
+		{
+			name: "cancelled",
+			interrupt: func(ctx context.Context) (context.Context, func()) {
+				ctx, cancel := context.WithCancel(ctx)
+				return ctx, cancel
+			},
+			expectedErr: context.Canceled,
+		},
+		{
+			name: "timed out",
+			interrupt: func(ctx context.Context) (context.Context, func()) {
+				ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+				return ctx, func() { <-ctx.Done(); cancel() }
+			},
+			expectedErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, interrupt := tt.interrupt(context.Background())
+			defer interrupt()
+			fastDone := make(chan struct{})
+
+			client := &scriptedRoute53Client{
+				fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+					if domain == "fast.com" {
+						defer close(fastDone)
+						return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable}, nil
+					}
+					// Interrupt the run while the other checks are in flight
+					<-fastDone
+					interrupt()
+					<-ctx.Done()
+					return nil, ctx.Err()
+				},
+			}
+			checker := NewDomainChecker(&MockValidator{}, client)
+
+			domains := []string{"fast.com", "slow.com", "slower.com"}
+			results, err := checker.CheckAvailabilityBulk(ctx, domains)
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected %v to be returned, got %v", tt.expectedErr, err)
+			}
+			if results[0] == nil || results[0].Skipped || !results[0].Available {
+				t.Errorf("Expected fast.com to keep its result, got %+v", results[0])
+			}
+			for i, result := range results[1:] {
+				if result == nil || !result.Skipped || result.Domain != domains[i+1] || result.Error == nil {
+					t.Errorf("Expected %s to be reported as skipped, got %+v", domains[i+1], result)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckAvailabilityBulk_ContinueOnErrorByDefault(t *testing.T) {
+	denied := customErrors.NewAuthorizationError("CheckDomainAvailability", "route53domains", "denied", nil)
+
+	client := &scriptedRoute53Client{
+		fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+			if domain == "denied.com" {
+				return nil, denied
+			}
+			return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityUnavailable}, nil
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+
+	results, err := checker.CheckAvailabilityBulk(context.Background(), []string{"denied.com", "a.com", "b.com"})
+	if err != nil {
+		t.Fatalf("Expected no error when some domains succeed, got %v", err)
+	}
+	for _, result := range results[1:] {
+		if result.Skipped || result.Error != nil {
+			t.Errorf("Expected %s to be checked, got %+v", result.Domain, result)
+		}
+	}
+}
+
+func TestCheckAvailabilityBulk_Batching(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+
+	client := &scriptedRoute53Client{
+		fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable}, nil
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+	checker.SetBatching(2, 20*time.Millisecond)
+
+	domains := []string{"a.com", "b.com", "c.com", "d.com", "e.com"}
+	start := time.Now()
+	results, err := checker.CheckAvailabilityBulk(context.Background(), domains)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for i, result := range results {
+		if result == nil || result.Domain != domains[i] || !result.Available {
+			t.Errorf("Unexpected result at %d: %+v", i, result)
+		}
+	}
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 concurrent checks per batch, got %d", maxInFlight)
+	}
+	// Three batches means two pauses
+	if elapsed < 40*time.Millisecond {
+		t.Errorf("Expected batch delays to be applied, run took %v", elapsed)
+	}
+}
+
+func TestCheckAvailability_InternationalizedDomain(t *testing.T) {
+	client := &MockRoute53Client{
+		response: &route53domains.CheckDomainAvailabilityOutput{
+			Availability: types.DomainAvailabilityAvailable,
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+
+	result, err := checker.CheckAvailability(context.Background(), "bücher.de")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(client.callLog) != 1 || client.callLog[0] != "xn--bcher-kva.de" {
+		t.Errorf("Expected the punycode form to be sent to AWS, got %v", client.callLog)
+	}
+	if result.Domain != "xn--bcher-kva.de" {
+		t.Errorf("Expected ASCII domain in result, got %s", result.Domain)
+	}
+	if result.UnicodeDomain != "bücher.de" {
+		t.Errorf("Expected Unicode domain in result, got %s", result.UnicodeDomain)
+	}
+}
+
+func TestCheckAvailabilityWithPricing_MultiLabelTLD(t *testing.T) {
+	var requestedTLD string
+	client := &pricingRecorder{
+		MockRoute53Client: MockRoute53Client{
+			response: &route53domains.CheckDomainAvailabilityOutput{
+				Availability: types.DomainAvailabilityAvailable,
+			},
+		},
+		tld: &requestedTLD,
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+
+	if _, err := checker.CheckAvailabilityWithPricing(context.Background(), "example.co.uk"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if requestedTLD != "co.uk" {
+		t.Errorf("Expected prices for co.uk, got %q", requestedTLD)
+	}
+}
+
+// pricingRecorder records which TLD pricing was requested for
+type pricingRecorder struct {
+	MockRoute53Client
+	tld *string
+}
+
+func (p *pricingRecorder) ListPrices(ctx context.Context, tld string) (*route53domains.ListPricesOutput, error) {
+	*p.tld = tld
+	return p.MockRoute53Client.ListPrices(ctx, tld)
+}
+
+// slowClient delays each call, so the phases of a check take measurable time
+type slowClient struct {
+	MockRoute53Client
+	delay time.Duration
+}
+
+func (s *slowClient) CheckDomainAvailability(ctx context.Context, domain string) (*route53domains.CheckDomainAvailabilityOutput, error) {
+	time.Sleep(s.delay)
+	return s.MockRoute53Client.CheckDomainAvailability(ctx, domain)
+}
+
+func (s *slowClient) ListPrices(ctx context.Context, tld string) (*route53domains.ListPricesOutput, error) {
+	time.Sleep(s.delay)
+	return s.MockRoute53Client.ListPrices(ctx, tld)
+}
+
+func TestCheckAvailability_Timings(t *testing.T) {
+	client := &slowClient{
+		MockRoute53Client: MockRoute53Client{
+			response: &route53domains.CheckDomainAvailabilityOutput{
+				Availability: types.DomainAvailabilityAvailable,
+			},
+			pricesResponse: &route53domains.ListPricesOutput{},
+		},
+		delay: 20 * time.Millisecond,
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+
+	result, err := checker.CheckAvailability(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Timings.API < client.delay || result.Timings.Pricing != 0 || result.Timings.Validation >= client.delay {
+		t.Errorf("Expected only the API call to take the delay, got %+v", result.Timings)
+	}
+
+	result, err = checker.CheckAvailabilityWithPricing(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Timings.API < client.delay || result.Timings.Pricing < client.delay {
+		t.Errorf("Expected the API call and pricing to take the delay, got %+v", result.Timings)
+	}
+}
+
+func TestCheckAvailability_ConfusableWarnings(t *testing.T) {
+	client := &MockRoute53Client{
+		response: &route53domains.CheckDomainAvailabilityOutput{
+			Availability: types.DomainAvailabilityAvailable,
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+
+	result, err := checker.CheckAvailability(context.Background(), "paypa1.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("Expected no warnings when confusable checks are disabled, got %v", result.Warnings)
+	}
+
+	checker.SetWarnConfusables(true)
+	result, err = checker.CheckAvailability(context.Background(), "paypa1.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0] != "'paypa1' looks like 'paypal'" {
+		t.Errorf("Expected lookalike warning, got %v", result.Warnings)
+	}
+}
+
+func TestCheckAvailabilityBulk_Concurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		expectedMax int
+	}{
+		{"serial", 1, 1},
+		{"custom limit", 8, 8},
+		{"invalid limit restores default", 0, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			inFlight, maxInFlight := 0, 0
+
+			client := &scriptedRoute53Client{
+				fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+					mu.Lock()
+					inFlight++
+					if inFlight > maxInFlight {
+						maxInFlight = inFlight
+					}
+					mu.Unlock()
+
+					time.Sleep(10 * time.Millisecond)
+
+					mu.Lock()
+					inFlight--
+					mu.Unlock()
+					return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable}, nil
+				},
+			}
+
+			checker := NewDomainChecker(&MockValidator{}, client)
+			checker.SetConcurrency(tt.concurrency)
+
+			domains := make([]string, 16)
+			for i := range domains {
+				domains[i] = fmt.Sprintf("domain%d.com", i)
+			}
+			if _, err := checker.CheckAvailabilityBulk(context.Background(), domains); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if maxInFlight != tt.expectedMax {
+				t.Errorf("Expected at most %d concurrent requests, got %d", tt.expectedMax, maxInFlight)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// popularNames are well-known second-level labels that lookalike domains tend to imitate
+var popularNames = []string{
+	"adobe", "amazon", "apple", "dropbox", "ebay", "facebook", "github", "google",
+	"instagram", "linkedin", "microsoft", "netflix", "paypal", "spotify", "stripe",
+	"twitter", "whatsapp", "wikipedia", "yahoo", "youtube",
+}
+
+// confusableRunes maps characters to the Latin letter they are commonly mistaken for
+var confusableRunes = map[rune]rune{
+	// Digits
+	'0': 'o', '1': 'l', '3': 'e', '5': 's',
+	// Latin letters that are hard to tell apart
+	'i': 'l',
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'і': 'l', 'ј': 'j', 'к': 'k', 'м': 'm', 'н': 'h',
+	'о': 'o', 'р': 'p', 'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'ѕ': 's', 'ԁ': 'd',
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'ι': 'l', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p',
+	'τ': 't', 'υ': 'u', 'χ': 'x',
+}
+
+// confusablePairs are letter pairs that render like a single letter
+var confusablePairs = [][2]string{{"rn", "m"}, {"vv", "w"}, {"cl", "d"}}
+
+// confusableSequences replaces each confusable pair with its letter
+var confusableSequences = func() *strings.Replacer {
+	var oldnew []string
+	for _, pair := range confusablePairs {
+		oldnew = append(oldnew, pair[0], pair[1])
+	}
+	return strings.NewReplacer(oldnew...)
+}()
+
+// scriptTables are the scripts considered when looking for mixed-script labels.
+// Scripts that are routinely written together share a name.
+var scriptTables = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"Latin", unicode.Latin},
+	{"Cyrillic", unicode.Cyrillic},
+	{"Greek", unicode.Greek},
+	{"Armenian", unicode.Armenian},
+	{"Hebrew", unicode.Hebrew},
+	{"Arabic", unicode.Arabic},
+	{"Devanagari", unicode.Devanagari},
+	{"Thai", unicode.Thai},
+	{"CJK", unicode.Han},
+	{"CJK", unicode.Hiragana},
+	{"CJK", unicode.Katakana},
+	{"CJK", unicode.Hangul},
+}
+
+// ConfusableWarnings reports ways in which domain could be mistaken for a
+// different name: labels mixing scripts, and second-level labels that look
+// like a popular name through digit/letter swaps or homoglyphs. It returns
+// nil when nothing suspicious is found.
+func ConfusableWarnings(domain string) []string {
+	name := ToUnicode(strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), ".")))
+
+	var warnings []string
+	for _, label := range strings.Split(name, ".") {
+		if scripts := labelScripts(label); len(scripts) > 1 {
+			warnings = append(warnings, fmt.Sprintf("label '%s' mixes %s characters", label, strings.Join(scripts, " and ")))
+		}
+	}
+
+	label := secondLevelLabel(name)
+	if label == "" {
+		return warnings
+	}
+	labelSkeleton := skeleton(label)
+	for _, popular := range popularNames {
+		if label != popular && labelSkeleton == skeleton(popular) {
+			warnings = append(warnings, fmt.Sprintf("'%s' looks like '%s'", label, popular))
+			break
+		}
+	}
+
+	return warnings
+}
+
+// secondLevelLabel returns the label directly left of the effective TLD
+func secondLevelLabel(name string) string {
+	registrable := RegistrableDomain(name)
+	if registrable == "" {
+		return ""
+	}
+	label, _, _ := strings.Cut(registrable, ".")
+	return label
+}
+
+// labelScripts returns the sorted, distinct scripts used by letters in label
+func labelScripts(label string) []string {
+	seen := make(map[string]bool)
+	for _, r := range label {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		for _, script := range scriptTables {
+			if unicode.Is(script.table, r) {
+				seen[script.name] = true
+				break
+			}
+		}
+	}
+
+	scripts := make([]string, 0, len(seen))
+	for script := range seen {
+		scripts = append(scripts, script)
+	}
+	sort.Strings(scripts)
+	return scripts
+}
+
+// skeleton maps a label to a canonical form in which confusable characters compare equal
+func skeleton(label string) string {
+	mapped := strings.Map(func(r rune) rune {
+		if replacement, ok := confusableRunes[r]; ok {
+			return replacement
+		}
+		return r
+	}, label)
+	return confusableSequences.Replace(mapped)
+}
+
+// Homoglyphs returns the characters and letter pairs that can be mistaken
+// for letter, such as "0" and the Cyrillic "о" for 'o', or "rn" for 'm'
+func Homoglyphs(letter rune) []string {
+	var lookalikes []string
+	for r, target := range confusableRunes {
+		if target == letter {
+			lookalikes = append(lookalikes, string(r))
+		}
+	}
+	sort.Strings(lookalikes)
+
+	for _, pair := range confusablePairs {
+		if pair[1] == string(letter) {
+			lookalikes = append(lookalikes, pair[0])
+		}
+	}
+	return lookalikes
+}
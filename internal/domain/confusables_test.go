@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfusableWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   string
+		expected []string
+	}{
+		{
+			name:   "ordinary domain",
+			domain: "example.com",
+		},
+		{
+			name:   "popular domain itself",
+			domain: "paypal.com",
+		},
+		{
+			name:     "digit swap",
+			domain:   "paypa1.com",
+			expected: []string{"'paypa1' looks like 'paypal'"},
+		},
+		{
+			name:     "zero for o",
+			domain:   "g00gle.net",
+			expected: []string{"'g00gle' looks like 'google'"},
+		},
+		{
+			name:     "rn for m",
+			domain:   "rnicrosoft.io",
+			expected: []string{"'rnicrosoft' looks like 'microsoft'"},
+		},
+		{
+			name:     "subdomains are ignored",
+			domain:   "www.amaz0n.co.uk",
+			expected: []string{"'amaz0n' looks like 'amazon'"},
+		},
+		{
+			name:   "Cyrillic homoglyph mixed with Latin",
+			domain: "аpple.com", // Cyrillic а
+			expected: []string{
+				"label 'аpple' mixes Cyrillic and Latin characters",
+				"'аpple' looks like 'apple'",
+			},
+		},
+		{
+			name:     "punycode input is decoded",
+			domain:   "xn--pple-43d.com",
+			expected: []string{"label 'аpple' mixes Cyrillic and Latin characters", "'аpple' looks like 'apple'"},
+		},
+		{
+			name:   "single non-Latin script",
+			domain: "пример.рф",
+		},
+		{
+			name:   "Japanese scripts together are not mixed",
+			domain: "日本のドメイン.jp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := ConfusableWarnings(tt.domain)
+			if strings.Join(warnings, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("ConfusableWarnings(%q) = %q, want %q", tt.domain, warnings, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHomoglyphs(t *testing.T) {
+	tests := []struct {
+		letter   rune
+		expected []string
+	}{
+		{'o', []string{"0", "ο", "о"}},
+		{'m', []string{"м", "rn"}},
+		{'l', []string{"1", "i", "ι", "і"}},
+		{'z', nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.letter), func(t *testing.T) {
+			if got := Homoglyphs(tt.letter); strings.Join(got, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("Homoglyphs(%q) = %q, want %q", tt.letter, got, tt.expected)
+			}
+		})
+	}
+}
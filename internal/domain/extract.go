@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// hostPattern matches host names in free text: dot-separated labels of
+// letters, digits and inner hyphens, in any script
+var hostPattern = regexp.MustCompile(`(?:[\p{L}\p{N}](?:[\p{L}\p{N}-]{0,61}[\p{L}\p{N}])?\.)+[\p{L}\p{N}](?:[\p{L}\p{N}-]{0,61}[\p{L}\p{N}])?`)
+
+// ExtractDomains returns the registrable domains mentioned anywhere in text,
+// such as a log, an HTML page or a document, in order of first mention and
+// each once. Host names are reduced to their registrable domain, so
+// "https://www.example.co.uk/page" yields "example.co.uk". Names are only
+// kept when they end in a TLD on the ICANN section of the Public Suffix
+// List, which leaves out file names such as "index.html" and version numbers.
+func ExtractDomains(text string) []string {
+	var domains []string
+	seen := make(map[string]bool)
+	for _, host := range hostPattern.FindAllString(text, -1) {
+		host = strings.ToLower(host)
+		if !hasICANNTLD(host) || isPublicSuffix(host) {
+			continue
+		}
+		registrable := RegistrableDomain(host)
+		if registrable == "" || seen[registrable] {
+			continue
+		}
+		seen[registrable] = true
+		domains = append(domains, registrable)
+	}
+	return domains
+}
+
+// hasICANNTLD reports whether the last label of host is a top-level domain
+// on the ICANN section of the Public Suffix List
+func hasICANNTLD(host string) bool {
+	tld := host[strings.LastIndex(host, ".")+1:]
+	ascii, err := ToASCII(tld)
+	if err != nil {
+		return false
+	}
+	_, icann := publicsuffix.PublicSuffix(ascii)
+	return icann
+}
+
+// isPublicSuffix reports whether host is itself a registry suffix such as
+// "co.uk", which names no domain
+func isPublicSuffix(host string) bool {
+	suffix, icann := publicsuffix.PublicSuffix(host)
+	return icann && suffix == host
+}
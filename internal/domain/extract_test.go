@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractDomains(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{"plain list", "example.com test.org", []string{"example.com", "test.org"}},
+		{"log line", `10.0.0.1 - - [16/Oct/2026] "GET https://www.Example.com/index.html HTTP/1.1" 200 ref=news.bbc.co.uk`, []string{"example.com", "bbc.co.uk"}},
+		{"HTML", `<a href="https://shop.acme.io/?q=1">Acme</a><img src="logo.png"> mail <b>sales@widgets.net</b>.`, []string{"acme.io", "widgets.net"}},
+		{"sentence", "Consider brandname.dev, brandname.app or e.g. brandname.dev.", []string{"brandname.dev", "brandname.app"}},
+		{"internationalized", "Neue Shops: bücher.de und straße.de", []string{"bücher.de", "straße.de"}},
+		{"unknown TLDs and versions", "see main.go, config.json and v1.2.3 on 192.168.0.1", nil},
+		{"public suffix alone", "registered under co.uk", nil},
+		{"empty", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractDomains(tt.text); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ExtractDomains(%q) = %v, want %v", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+// Fallback checks domains under TLDs that Route 53 Domains does not
+// support, such as by querying WHOIS. Its answers cannot be confirmed by
+// Route 53, so their results are marked unverified.
+type Fallback interface {
+	// Name identifies the fallback in results, such as "whois"
+	Name() string
+	// Lookup returns the likely status of domain and a message explaining it
+	Lookup(ctx context.Context, domain string) (AvailabilityStatus, string, error)
+}
+
+// SetFallback checks domains whose TLD is not supported with fallback
+// instead of rejecting them. Other validation errors, including TLDs denied
+// by the TLD policy, still fail the check. A nil fallback removes it.
+func (c *DomainChecker) SetFallback(fallback Fallback) {
+	c.fallback = fallback
+}
+
+// ValidateDomain validates domain as a check does, so a domain under an
+// unsupported TLD passes when the fallback can check it
+func (c *DomainChecker) ValidateDomain(domain string) error {
+	err := c.validator.ValidateDomain(domain)
+	if err != nil && c.fallback != nil && unsupportedTLD(domain, err) {
+		return nil
+	}
+	return err
+}
+
+// unsupportedTLD reports whether err rejects domain only because its TLD is
+// missing from the supported catalog, which a fallback can still check
+func unsupportedTLD(domain string, err error) bool {
+	var validationErr *customErrors.ValidationError
+	return errors.As(err, &validationErr) && validationErr.Field == "tld" && EffectiveTLD(domain) != ""
+}
+
+// checkFallback completes result with the fallback's answer
+func (c *DomainChecker) checkFallback(ctx context.Context, result *AvailabilityResult) (*AvailabilityResult, error) {
+	result.Source = c.fallback.Name()
+	result.Unverified = true
+
+	lookupStart := time.Now()
+	status, message, err := c.fallback.Lookup(ctx, result.Domain)
+	result.Timings.API = time.Since(lookupStart)
+	if err != nil {
+		result.Error = err
+		result.Status = StatusUnknown
+		return result, err
+	}
+
+	result.Status = status
+	result.Available = status == StatusAvailable
+	result.Message = message
+	return result, nil
+}
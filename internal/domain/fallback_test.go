@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// fakeFallback answers every lookup with the same status, or err
+type fakeFallback struct {
+	status  AvailabilityStatus
+	err     error
+	lookups []string
+}
+
+func (f *fakeFallback) Name() string {
+	return "fake"
+}
+
+func (f *fakeFallback) Lookup(ctx context.Context, domain string) (AvailabilityStatus, string, error) {
+	f.lookups = append(f.lookups, domain)
+	return f.status, "answered by the fake", f.err
+}
+
+func TestCheckAvailability_Fallback(t *testing.T) {
+	policy := NewTLDPolicy("test")
+	policy.Deny["xyz"] = true
+	validator := NewDomainValidatorWithTLDs([]string{"com", "xyz"})
+	validator.SetTLDPolicy(policy)
+
+	tests := []struct {
+		name       string
+		domain     string
+		fallback   *fakeFallback
+		status     AvailabilityStatus
+		unverified bool
+		err        string
+	}{
+		{"unsupported TLD", "example.ly", &fakeFallback{status: StatusAvailable}, StatusAvailable, true, ""},
+		{"registered", "taken.ly", &fakeFallback{status: StatusUnavailable}, StatusUnavailable, true, ""},
+		{"supported TLD", "example.com", &fakeFallback{status: StatusAvailable}, StatusUnavailable, false, ""},
+		{"denied by policy", "example.xyz", &fakeFallback{status: StatusAvailable}, StatusUnknown, false, "denied by TLD policy"},
+		{"invalid name", "bad-.ly", &fakeFallback{status: StatusAvailable}, StatusUnknown, false, "hyphen"},
+		{"lookup fails", "example.ly", &fakeFallback{err: errors.New("connection refused")}, StatusUnknown, true, "connection refused"},
+		{"without fallback", "example.ly", nil, StatusUnknown, false, "unsupported TLD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &MockRoute53Client{response: &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityUnavailable}}
+			checker := NewDomainChecker(validator, client)
+			if tt.fallback != nil {
+				checker.SetFallback(tt.fallback)
+			}
+
+			result, err := checker.CheckAvailabilityWithPricing(context.Background(), tt.domain)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Expected an error containing %q, got %v", tt.err, err)
+				}
+			} else if err != nil {
+				t.Fatalf("CheckAvailability() error: %v", err)
+			}
+
+			if result.Status != tt.status || result.Unverified != tt.unverified {
+				t.Errorf("Expected %s, unverified %v, got %s, unverified %v", tt.status, tt.unverified, result.Status, result.Unverified)
+			}
+			if tt.unverified {
+				if result.Source != "fake" || len(client.callLog) != 0 {
+					t.Errorf("Expected only the fallback to be asked, got source %q and Route 53 calls %v", result.Source, client.callLog)
+				}
+				if result.Pricing != nil {
+					t.Errorf("Expected no Route 53 pricing for a fallback result, got %+v", result.Pricing)
+				}
+			} else if tt.fallback != nil && len(tt.fallback.lookups) != 0 {
+				t.Errorf("Expected the fallback not to be asked, got %v", tt.fallback.lookups)
+			}
+		})
+	}
+}
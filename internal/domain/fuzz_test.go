@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// fuzzSeeds covers the shapes of input the validator has to cope with
+var fuzzSeeds = []string{
+	"example.com",
+	"EXAMPLE.COM",
+	"sub.example.co.uk",
+	"example.com.",
+	"https://www.example.com/path?q=1",
+	"bücher.de",
+	"xn--bcher-kva.de",
+	"-bad--name.123.invalidtld",
+	"a..b.com",
+	"123.com",
+	"ab.io",
+	"nic.info",
+	"",
+	".",
+	"..",
+	"localhost",
+	"xn--.com",
+	strings.Repeat("a", 64) + ".com",
+	strings.Repeat("a.", 130) + "com",
+}
+
+// FuzzValidateDomain checks that validation never panics and that accepting a
+// domain is consistent with the forms the CLI reduces it to
+func FuzzValidateDomain(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+
+	validator := NewDomainValidator()
+	f.Fuzz(func(t *testing.T, input string) {
+		err := validator.ValidateDomain(input)
+		if err != nil {
+			if err.Error() == "" {
+				t.Errorf("ValidateDomain(%q) returned an error with an empty message", input)
+			}
+			return
+		}
+
+		// ASCII validation is case-insensitive
+		if isASCII(input) {
+			if upperErr := validator.ValidateDomain(strings.ToUpper(input)); upperErr != nil {
+				t.Errorf("ValidateDomain(%q) succeeded but upper case failed: %v", input, upperErr)
+			}
+		}
+
+		// Reducing a valid domain to its registrable form keeps it valid
+		normalized, _ := NormalizeDomain(input)
+		if normErr := validator.ValidateDomain(normalized); normErr != nil {
+			t.Errorf("ValidateDomain(%q) succeeded but normalized %q failed: %v", input, normalized, normErr)
+		}
+	})
+}
+
+// FuzzExtractTLD checks that the extracted TLD is always a suffix of the input
+func FuzzExtractTLD(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+
+	validator := NewDomainValidator()
+	f.Fuzz(func(t *testing.T, input string) {
+		tld := validator.extractTLD(input)
+		if tld == "" {
+			return
+		}
+
+		name := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(input), "."))
+		if !strings.HasSuffix(name, "."+tld) {
+			t.Errorf("extractTLD(%q) = %q, which is not a suffix of the domain", input, tld)
+		}
+		if strings.HasPrefix(tld, ".") {
+			t.Errorf("extractTLD(%q) = %q, which starts with a dot", input, tld)
+		}
+	})
+}
+
+// FuzzNormalizeDomain checks that normalization is idempotent
+func FuzzNormalizeDomain(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		if !utf8.ValidString(input) {
+			return
+		}
+
+		once, _ := NormalizeDomain(input)
+		twice, changed := NormalizeDomain(once)
+		if twice != once || changed {
+			t.Errorf("NormalizeDomain is not idempotent: %q -> %q -> %q (changed %v)", input, once, twice, changed)
+		}
+	})
+}
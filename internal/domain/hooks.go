@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+// Hooks observes the checks a DomainChecker performs, for logging, metrics
+// or persistence. Hooks are called synchronously from the goroutine running
+// the check, so implementations must be safe for concurrent use and fast.
+// Embed NopHooks to implement only the events of interest.
+type Hooks interface {
+	// OnCheckStart is called before a domain is validated and checked
+	OnCheckStart(ctx context.Context, domain string)
+	// OnCheckComplete is called with the outcome of every check, including failures
+	OnCheckComplete(ctx context.Context, result *AvailabilityResult, err error)
+	// OnRetry is called before a failed domain is checked again; attempt is
+	// the number of the upcoming attempt and err the failure that caused it
+	OnRetry(ctx context.Context, domain string, attempt int, err error)
+	// OnThrottle is called when AWS rejects a check for exceeding its rate limit
+	OnThrottle(ctx context.Context, domain string, err error)
+}
+
+// NopHooks implements Hooks with methods that do nothing
+type NopHooks struct{}
+
+func (NopHooks) OnCheckStart(ctx context.Context, domain string)                            {}
+func (NopHooks) OnCheckComplete(ctx context.Context, result *AvailabilityResult, err error) {}
+func (NopHooks) OnRetry(ctx context.Context, domain string, attempt int, err error)         {}
+func (NopHooks) OnThrottle(ctx context.Context, domain string, err error)                   {}
+
+// AddHooks registers hooks that are notified of every check, in the order added
+func (c *DomainChecker) AddHooks(hooks Hooks) {
+	c.hooks = append(c.hooks, hooks)
+}
+
+// observe runs check for domain, notifying the registered hooks around it
+func (c *DomainChecker) observe(ctx context.Context, domain string, check func(context.Context, string) (*AvailabilityResult, error)) (*AvailabilityResult, error) {
+	for _, h := range c.hooks {
+		h.OnCheckStart(ctx, domain)
+	}
+
+	result, err := check(ctx, domain)
+
+	if customErrors.IsThrottling(err) {
+		for _, h := range c.hooks {
+			h.OnThrottle(ctx, domain, err)
+		}
+	}
+	for _, h := range c.hooks {
+		h.OnCheckComplete(ctx, result, err)
+	}
+
+	return result, err
+}
+
+// notifyRetry tells the registered hooks that domain is about to be checked again
+func (c *DomainChecker) notifyRetry(ctx context.Context, domain string, attempt int, err error) {
+	for _, h := range c.hooks {
+		h.OnRetry(ctx, domain, attempt, err)
+	}
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// recordingHooks logs every hook event as "event:domain"
+type recordingHooks struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (h *recordingHooks) record(event, domain string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event+":"+domain)
+}
+
+func (h *recordingHooks) OnCheckStart(ctx context.Context, domain string) {
+	h.record("start", domain)
+}
+
+func (h *recordingHooks) OnCheckComplete(ctx context.Context, result *AvailabilityResult, err error) {
+	h.record("complete", result.Domain)
+}
+
+func (h *recordingHooks) OnRetry(ctx context.Context, domain string, attempt int, err error) {
+	h.record("retry", domain)
+}
+
+func (h *recordingHooks) OnThrottle(ctx context.Context, domain string, err error) {
+	h.record("throttle", domain)
+}
+
+// completionCounter only implements OnCheckComplete, relying on NopHooks for the rest
+type completionCounter struct {
+	NopHooks
+	count atomic.Int64
+}
+
+func (c *completionCounter) OnCheckComplete(ctx context.Context, result *AvailabilityResult, err error) {
+	c.count.Add(1)
+}
+
+func TestHooks_SingleCheck(t *testing.T) {
+	mockClient := &MockRoute53Client{
+		response: &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable},
+		pricesResponse: &route53domains.ListPricesOutput{
+			Prices: []types.DomainPrice{{RegistrationPrice: &types.PriceWithCurrency{Price: 12}}},
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, mockClient)
+	hooks := &recordingHooks{}
+	checker.AddHooks(hooks)
+
+	if _, err := checker.CheckAvailabilityWithPricing(context.Background(), "example.com"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The pricing check is reported as one check, not an availability check plus pricing
+	expected := []string{"start:example.com", "complete:example.com"}
+	if !slices.Equal(hooks.events, expected) {
+		t.Errorf("Expected events %v, got %v", expected, hooks.events)
+	}
+}
+
+func TestHooks_BulkRetryAndThrottle(t *testing.T) {
+	throttled := customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "rate limited", nil).WithStatusCode(429)
+
+	client := &scriptedRoute53Client{
+		fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+			if domain == "throttled.com" && attempt == 1 {
+				return nil, throttled
+			}
+			return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable}, nil
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+	checker.SetRetryDelay(0)
+	hooks := &recordingHooks{}
+	counter := &completionCounter{}
+	checker.AddHooks(hooks)
+	checker.AddHooks(counter)
+
+	if _, err := checker.CheckAvailabilityBulk(context.Background(), []string{"ok.com", "throttled.com"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	events := slices.Clone(hooks.events)
+	sort.Strings(events)
+	expected := []string{
+		"complete:ok.com", "complete:throttled.com", "complete:throttled.com",
+		"retry:throttled.com",
+		"start:ok.com", "start:throttled.com", "start:throttled.com",
+		"throttle:throttled.com",
+	}
+	if !slices.Equal(events, expected) {
+		t.Errorf("Expected events %v, got %v", expected, events)
+	}
+	if n := counter.count.Load(); n != 3 {
+		t.Errorf("Expected 3 completed checks, got %d", n)
+	}
+}
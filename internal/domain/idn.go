@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ToASCII converts a possibly internationalized domain name to its ASCII
+// (punycode) form. The name is NFC-normalized first so that visually identical
+// inputs map to the same registration.
+func ToASCII(name string) (string, error) {
+	return idna.Lookup.ToASCII(norm.NFC.String(strings.TrimSpace(name)))
+}
+
+// ToUnicode returns the Unicode form of a domain name, or the name unchanged
+// when it cannot be decoded
+func ToUnicode(name string) string {
+	unicode, err := idna.Lookup.ToUnicode(name)
+	if err != nil {
+		return name
+	}
+	return unicode
+}
+
+// IsIDN reports whether a domain name is internationalized, either written in
+// Unicode or containing punycode ("xn--") labels
+func IsIDN(name string) bool {
+	if !isASCII(name) {
+		return true
+	}
+	for _, label := range strings.Split(strings.ToLower(name), ".") {
+		if strings.HasPrefix(label, "xn--") {
+			return true
+		}
+	}
+	return false
+}
+
+// isASCII reports whether s contains only ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
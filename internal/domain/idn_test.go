@@ -0,0 +1,57 @@
+package domain
+
+import "testing"
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"ascii domain unchanged", "example.com", "example.com", false},
+		{"german umlaut", "bücher.de", "xn--bcher-kva.de", false},
+		{"uppercase unicode is mapped", "BÜCHER.de", "xn--bcher-kva.de", false},
+		{"decomposed form is NFC normalized", "bu\u0308cher.de", "xn--bcher-kva.de", false},
+		{"japanese", "例え.jp", "xn--r8jz45g.jp", false},
+		{"surrounding whitespace", "  bücher.de ", "xn--bcher-kva.de", false},
+		{"invalid bidi label", "aא.com", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToASCII(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToASCII(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.expected {
+				t.Errorf("ToASCII(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestToUnicode(t *testing.T) {
+	if got := ToUnicode("xn--bcher-kva.de"); got != "bücher.de" {
+		t.Errorf("ToUnicode = %q, want bücher.de", got)
+	}
+	if got := ToUnicode("example.com"); got != "example.com" {
+		t.Errorf("ToUnicode = %q, want example.com", got)
+	}
+}
+
+func TestIsIDN(t *testing.T) {
+	tests := map[string]bool{
+		"example.com":      false,
+		"bücher.de":        true,
+		"xn--bcher-kva.de": true,
+		"XN--BCHER-KVA.DE": true,
+		"my-xn--site.com":  false,
+	}
+
+	for input, expected := range tests {
+		if got := IsIDN(input); got != expected {
+			t.Errorf("IsIDN(%q) = %v, want %v", input, got, expected)
+		}
+	}
+}
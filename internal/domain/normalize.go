@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// NormalizeDomain reduces common non-domain inputs to the name that would be
+// registered: a trailing FQDN dot is dropped, a URL is reduced to its host,
+// and subdomains such as "www." are stripped down to the registrable domain.
+// It reports whether the input was changed beyond case and whitespace. Inputs that do not look like a
+// host name are returned trimmed so validation can explain what is wrong.
+func NormalizeDomain(input string) (string, bool) {
+	trimmed := strings.TrimSpace(input)
+	host := trimmed
+
+	// Reduce URLs to their host, with or without a scheme
+	if strings.Contains(host, "://") {
+		if u, err := url.Parse(host); err == nil && u.Hostname() != "" {
+			host = u.Hostname()
+		}
+	} else if i := strings.IndexAny(host, "/?#"); i > 0 {
+		host = host[:i]
+	}
+
+	// A fully qualified name ends with a dot that is not part of the domain
+	host = strings.TrimRightFunc(host, func(r rune) bool {
+		return r == '.' || unicode.IsSpace(r)
+	})
+
+	// Only strip subdomains from well-formed host names, otherwise a malformed
+	// label could be silently discarded instead of reported
+	if _, err := ToASCII(host); err == nil {
+		if registrable := RegistrableDomain(host); registrable != "" {
+			host = registrable
+		}
+	}
+
+	return host, !strings.EqualFold(host, trimmed)
+}
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// TLDPolicy restricts or extends the TLDs the validator accepts.
+//
+// Policy entries use a small syntax, one per line in a file or one per
+// comma-separated value on the command line:
+//
+//	io      allow: when any allow entries exist only those TLDs are accepted
+//	+dev    extend: accept the TLD even if it is missing from the catalog
+//	-xyz    deny: always reject the TLD
+type TLDPolicy struct {
+	Allow  map[string]bool
+	Extend map[string]bool
+	Deny   map[string]bool
+	Source string // Where the policy came from, used in error messages
+}
+
+// NewTLDPolicy creates an empty policy that accepts every catalog TLD
+func NewTLDPolicy(source string) *TLDPolicy {
+	return &TLDPolicy{
+		Allow:  make(map[string]bool),
+		Extend: make(map[string]bool),
+		Deny:   make(map[string]bool),
+		Source: source,
+	}
+}
+
+// ParseTLDPolicy reads policy entries from r, one per line. Empty lines and
+// lines starting with # are ignored.
+func ParseTLDPolicy(r io.Reader, source string) (*TLDPolicy, error) {
+	policy := NewTLDPolicy(source)
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := policy.Add(line); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", source, lineNumber, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", source, err)
+	}
+
+	return policy, nil
+}
+
+// LoadTLDPolicyFile reads a policy from the file at path
+func LoadTLDPolicyFile(path string) (*TLDPolicy, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TLD policy file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseTLDPolicy(file, path)
+}
+
+// Add records a single policy entry
+func (p *TLDPolicy) Add(entry string) error {
+	entry = strings.TrimSpace(entry)
+
+	target := p.Allow
+	switch {
+	case strings.HasPrefix(entry, "+"):
+		target = p.Extend
+		entry = entry[1:]
+	case strings.HasPrefix(entry, "-"):
+		target = p.Deny
+		entry = entry[1:]
+	}
+
+	tld := strings.ToLower(strings.Trim(strings.TrimSpace(entry), "."))
+	if tld == "" {
+		return fmt.Errorf("empty TLD in policy entry %q", entry)
+	}
+	if asciiTLD, err := ToASCII(tld); err == nil {
+		tld = asciiTLD
+	}
+
+	target[tld] = true
+	return nil
+}
+
+// Merge adds every entry of other to the policy
+func (p *TLDPolicy) Merge(other *TLDPolicy) {
+	for tld := range other.Allow {
+		p.Allow[tld] = true
+	}
+	for tld := range other.Extend {
+		p.Extend[tld] = true
+	}
+	for tld := range other.Deny {
+		p.Deny[tld] = true
+	}
+	if other.Source != "" {
+		if p.Source == "" {
+			p.Source = other.Source
+		} else {
+			p.Source += ", " + other.Source
+		}
+	}
+}
+
+// IsEmpty reports whether the policy has no entries
+func (p *TLDPolicy) IsEmpty() bool {
+	return len(p.Allow) == 0 && len(p.Extend) == 0 && len(p.Deny) == 0
+}
+
+// matchSuffix returns the longest suffix of domain found in set, or ""
+func matchSuffix(domain string, set map[string]bool) string {
+	labels := strings.Split(domain, ".")
+	for i := 1; i < len(labels); i++ {
+		if suffix := strings.Join(labels[i:], "."); set[suffix] {
+			return suffix
+		}
+	}
+	return ""
+}
+
+// sortedTLDs returns the keys of set as a sorted list of ".tld" strings
+func sortedTLDs(set map[string]bool) []string {
+	tlds := make([]string, 0, len(set))
+	for tld := range set {
+		tlds = append(tlds, "."+tld)
+	}
+	sort.Strings(tlds)
+	return tlds
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+func TestParseTLDPolicy(t *testing.T) {
+	input := `# corporate TLD policy
+com
+.IO
+
++dev
+-xyz
+`
+	policy, err := ParseTLDPolicy(strings.NewReader(input), "corp.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !policy.Allow["com"] || !policy.Allow["io"] || len(policy.Allow) != 2 {
+		t.Errorf("unexpected allow entries: %v", policy.Allow)
+	}
+	if !policy.Extend["dev"] || len(policy.Extend) != 1 {
+		t.Errorf("unexpected extend entries: %v", policy.Extend)
+	}
+	if !policy.Deny["xyz"] || len(policy.Deny) != 1 {
+		t.Errorf("unexpected deny entries: %v", policy.Deny)
+	}
+	if policy.Source != "corp.txt" {
+		t.Errorf("expected source corp.txt, got %s", policy.Source)
+	}
+}
+
+func TestParseTLDPolicy_InvalidEntry(t *testing.T) {
+	_, err := ParseTLDPolicy(strings.NewReader("com\n+\n"), "bad.txt")
+	if err == nil {
+		t.Fatal("expected error for empty entry")
+	}
+	if !strings.Contains(err.Error(), "bad.txt:2") {
+		t.Errorf("expected error to name the line, got %v", err)
+	}
+}
+
+func TestLoadTLDPolicyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tlds.txt")
+	if err := os.WriteFile(path, []byte("io\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := LoadTLDPolicyFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !policy.Allow["io"] {
+		t.Error("expected io to be allowed")
+	}
+
+	if _, err := LoadTLDPolicyFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestTLDPolicy_Merge(t *testing.T) {
+	policy := NewTLDPolicy("file.txt")
+	policy.Add("com")
+
+	flags := NewTLDPolicy("--tlds")
+	flags.Add("-xyz")
+	policy.Merge(flags)
+
+	if !policy.Allow["com"] || !policy.Deny["xyz"] {
+		t.Errorf("expected merged entries, got %+v", policy)
+	}
+	if policy.Source != "file.txt, --tlds" {
+		t.Errorf("expected combined source, got %s", policy.Source)
+	}
+}
+
+func TestValidateDomain_TLDPolicy(t *testing.T) {
+	policy := NewTLDPolicy("corp.txt")
+	for _, entry := range []string{"com", "io", "co.uk", "+internal", "-xyz"} {
+		if err := policy.Add(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		tlds        []string
+		policy      *TLDPolicy
+		domain      string
+		wantErr     bool
+		wantField   string
+		wantMessage string
+	}{
+		{"allowed TLD", []string{"com", "io"}, policy, "example.com", false, "", ""},
+		{"multi-label allowed TLD", []string{"uk", "co.uk"}, policy, "example.co.uk", false, "", ""},
+		{"catalog TLD outside allowlist", []string{"com", "net"}, policy, "example.net", true, "tld-policy", "not allowed by TLD policy (corp.txt)"},
+		{"denied TLD", []string{"xyz"}, policy, "example.xyz", true, "tld-policy", "denied by TLD policy (corp.txt)"},
+		{"extended TLD without allowlist", []string{"com"}, &TLDPolicy{Extend: map[string]bool{"internal": true}, Source: "x"}, "example.internal", false, "", ""},
+		{"allowlisted TLD missing from catalog", []string{"com"}, policy, "example.io", true, "tld", "unsupported TLD"},
+		{"no policy", []string{"net"}, nil, "example.net", false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewDomainValidatorWithTLDs(tt.tlds)
+			validator.SetTLDPolicy(tt.policy)
+
+			err := validator.ValidateDomain(tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateDomain(%q) error = %v, wantErr %v", tt.domain, err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				return
+			}
+
+			var validationErr *customErrors.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected ValidationError, got %T", err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("expected field %s, got %s", tt.wantField, validationErr.Field)
+			}
+			if !strings.Contains(err.Error(), tt.wantMessage) {
+				t.Errorf("expected message to contain %q, got %q", tt.wantMessage, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// Prescreener tells cheaply whether a domain is registered, so the Route 53
+// API is only called for domains that may be available
+type Prescreener interface {
+	// Registered reports whether domain is certainly registered. False means
+	// it may not be, and the domain is checked with Route 53.
+	Registered(ctx context.Context, domain string) (bool, error)
+}
+
+// DNSPrescreener treats domains with name servers as registered, since a
+// domain is only delegated in DNS once it has been registered. Domains
+// without name servers may still be registered, so they are left to Route 53.
+type DNSPrescreener struct {
+	// lookupNS resolves the name servers of a domain; tests replace it
+	lookupNS func(ctx context.Context, name string) ([]*net.NS, error)
+}
+
+// NewDNSPrescreener creates a prescreener that uses the system resolver
+func NewDNSPrescreener() *DNSPrescreener {
+	return &DNSPrescreener{lookupNS: net.DefaultResolver.LookupNS}
+}
+
+// Registered reports whether domain has name servers. A domain that does
+// not exist in DNS is not an error.
+func (p *DNSPrescreener) Registered(ctx context.Context, domain string) (bool, error) {
+	servers, err := p.lookupNS(ctx, domain)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return len(servers) > 0, nil
+}
+
+// SetPrescreener asks prescreener about each domain before Route 53, and
+// reports the domains it shows are registered as unavailable without
+// calling the API. A failed prescreen falls back to the API. A nil
+// prescreener removes it.
+func (c *DomainChecker) SetPrescreener(prescreener Prescreener) {
+	c.prescreener = prescreener
+}
+
+// prescreen completes result as unavailable and returns true when the
+// prescreener shows its domain is registered
+func (c *DomainChecker) prescreen(ctx context.Context, result *AvailabilityResult) bool {
+	if c.prescreener == nil {
+		return false
+	}
+
+	start := time.Now()
+	registered, err := c.prescreener.Registered(ctx, result.Domain)
+	result.Timings.Prescreen = time.Since(start)
+	if err != nil || !registered {
+		return false
+	}
+
+	result.Status = StatusUnavailable
+	result.Available = false
+	result.Message = "Domain has name servers in DNS, so it is registered (prescreened)"
+	result.Prescreened = true
+	return true
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+func TestDNSPrescreener_Registered(t *testing.T) {
+	notFound := &net.DNSError{Err: "no such host", Name: "free.com", IsNotFound: true}
+	timeout := &net.DNSError{Err: "i/o timeout", Name: "slow.com", IsTimeout: true}
+
+	tests := []struct {
+		name       string
+		servers    []*net.NS
+		err        error
+		registered bool
+		wantErr    bool
+	}{
+		{"delegated", []*net.NS{{Host: "ns1.example.net."}}, nil, true, false},
+		{"not in DNS", nil, notFound, false, false},
+		{"no name servers", nil, nil, false, false},
+		{"lookup fails", nil, timeout, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prescreener := &DNSPrescreener{lookupNS: func(ctx context.Context, name string) ([]*net.NS, error) {
+				return tt.servers, tt.err
+			}}
+			registered, err := prescreener.Registered(context.Background(), "example.com")
+			if registered != tt.registered || (err != nil) != tt.wantErr {
+				t.Errorf("Registered() = %v, %v, want %v, error %v", registered, err, tt.registered, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fixedPrescreener reports the domains in registered as registered, and
+// fails for every domain when err is set
+type fixedPrescreener struct {
+	registered map[string]bool
+	err        error
+}
+
+func (p fixedPrescreener) Registered(ctx context.Context, domain string) (bool, error) {
+	return p.registered[domain], p.err
+}
+
+func TestCheckAvailability_Prescreen(t *testing.T) {
+	tests := []struct {
+		name        string
+		domain      string
+		prescreener fixedPrescreener
+		prescreened bool
+	}{
+		{"registered", "taken.com", fixedPrescreener{registered: map[string]bool{"taken.com": true}}, true},
+		{"maybe available", "free.com", fixedPrescreener{registered: map[string]bool{"taken.com": true}}, false},
+		{"prescreen fails", "taken.com", fixedPrescreener{registered: map[string]bool{"taken.com": true}, err: errors.New("timeout")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &MockRoute53Client{response: &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable}}
+			checker := NewDomainChecker(NewDomainValidatorWithTLDs([]string{"com"}), client)
+			checker.SetPrescreener(tt.prescreener)
+
+			result, err := checker.CheckAvailability(context.Background(), tt.domain)
+			if err != nil {
+				t.Fatalf("CheckAvailability() error: %v", err)
+			}
+			if result.Prescreened != tt.prescreened {
+				t.Errorf("Expected prescreened %v, got %+v", tt.prescreened, result)
+			}
+			if tt.prescreened {
+				if result.Status != StatusUnavailable || len(client.callLog) != 0 {
+					t.Errorf("Expected UNAVAILABLE without calling Route 53, got %s after %v", result.Status, client.callLog)
+				}
+			} else if result.Status != StatusAvailable || len(client.callLog) != 1 {
+				t.Errorf("Expected Route 53 to answer, got %s after %v", result.Status, client.callLog)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abakermi/r53check/internal/storage"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// DefaultPriceCatalogTTL is how long a cached price list is used before it is refreshed
+const DefaultPriceCatalogTTL = 24 * time.Hour
+
+// PriceSource lists the prices of every TLD that Route 53 Domains can register
+type PriceSource interface {
+	ListTLDPrices(ctx context.Context) ([]types.DomainPrice, error)
+}
+
+// priceFile is the on-disk representation of a cached price list
+type priceFile struct {
+	FetchedAt time.Time              `json:"fetched_at"`
+	Prices    map[string]cachedPrice `json:"prices"`
+}
+
+// cachedPrice is the on-disk representation of the prices of a TLD
+type cachedPrice struct {
+	Registration *float64 `json:"registration,omitempty"`
+	Renewal      *float64 `json:"renewal,omitempty"`
+	Transfer     *float64 `json:"transfer,omitempty"`
+	Currency     string   `json:"currency"`
+}
+
+// PriceList is the prices of every TLD, keyed by lowercase TLD, and when
+// they were fetched
+type PriceList struct {
+	FetchedAt time.Time
+	Prices    map[string]*PricingInfo
+}
+
+// PriceCatalog loads the prices of every TLD, preferring a fresh cached
+// copy, then the Route 53 API, then a stale cached copy, so runs without
+// access to the API can still show approximate prices
+type PriceCatalog struct {
+	source    PriceSource
+	cachePath string
+	ttl       time.Duration
+}
+
+// NewPriceCatalog creates a price catalog backed by source and cached at
+// cachePath. An empty cachePath disables the on-disk cache.
+func NewPriceCatalog(source PriceSource, cachePath string, ttl time.Duration) *PriceCatalog {
+	return &PriceCatalog{
+		source:    source,
+		cachePath: cachePath,
+		ttl:       ttl,
+	}
+}
+
+// DefaultPriceCatalogPath returns the default location of the cached price list
+func DefaultPriceCatalogPath() string {
+	return storage.DefaultDirs().PriceCatalog()
+}
+
+// Load returns the price list and where it came from. A stale cached copy
+// is returned along with the error that prevented a fresh fetch; without
+// one the error is returned alone.
+func (c *PriceCatalog) Load(ctx context.Context) (*PriceList, CatalogOrigin, error) {
+	cached, cacheErr := c.readCache()
+	if cacheErr == nil && time.Since(cached.FetchedAt) < c.ttl {
+		return cached, OriginCache, nil
+	}
+
+	list, fetchErr := c.fetch(ctx)
+	if fetchErr == nil {
+		// A failed cache write only costs a refetch next time
+		_ = c.writeCache(list)
+		return list, OriginAPI, nil
+	}
+
+	if cacheErr == nil {
+		return cached, OriginStale, fetchErr
+	}
+	return nil, "", fetchErr
+}
+
+// Refresh fetches the price list from the API and rewrites the cache regardless of its age
+func (c *PriceCatalog) Refresh(ctx context.Context) (*PriceList, error) {
+	list, err := c.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.writeCache(list); err != nil {
+		return list, err
+	}
+	return list, nil
+}
+
+// fetch asks the source for the current price list
+func (c *PriceCatalog) fetch(ctx context.Context) (*PriceList, error) {
+	if c.source == nil {
+		return nil, fmt.Errorf("no price source configured")
+	}
+
+	prices, err := c.source.ListTLDPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &PriceList{FetchedAt: time.Now(), Prices: make(map[string]*PricingInfo, len(prices))}
+	for _, price := range prices {
+		if price.Name != nil {
+			list.Prices[strings.ToLower(*price.Name)] = newPricingInfo(price)
+		}
+	}
+	if len(list.Prices) == 0 {
+		return nil, fmt.Errorf("price source returned an empty price list")
+	}
+	return list, nil
+}
+
+// readCache loads the cached price list from disk
+func (c *PriceCatalog) readCache() (*PriceList, error) {
+	if c.cachePath == "" {
+		return nil, fmt.Errorf("price list cache disabled")
+	}
+
+	data, err := os.ReadFile(c.cachePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var cached priceFile
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, fmt.Errorf("corrupt price list cache: %w", err)
+	}
+	if len(cached.Prices) == 0 {
+		return nil, fmt.Errorf("empty price list cache")
+	}
+
+	list := &PriceList{FetchedAt: cached.FetchedAt, Prices: make(map[string]*PricingInfo, len(cached.Prices))}
+	for tld, price := range cached.Prices {
+		list.Prices[tld] = &PricingInfo{
+			RegistrationPrice: price.Registration,
+			RenewalPrice:      price.Renewal,
+			TransferPrice:     price.Transfer,
+			Currency:          price.Currency,
+		}
+	}
+	return list, nil
+}
+
+// writeCache stores the price list on disk
+func (c *PriceCatalog) writeCache(list *PriceList) error {
+	if c.cachePath == "" {
+		return nil
+	}
+
+	cached := priceFile{FetchedAt: list.FetchedAt, Prices: make(map[string]cachedPrice, len(list.Prices))}
+	for tld, pricing := range list.Prices {
+		cached.Prices[tld] = cachedPrice{
+			Registration: pricing.RegistrationPrice,
+			Renewal:      pricing.RenewalPrice,
+			Transfer:     pricing.TransferPrice,
+			Currency:     pricing.Currency,
+		}
+	}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	return writeCacheFile(c.cachePath, data)
+}
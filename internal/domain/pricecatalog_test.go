@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// mockPriceSource implements the PriceSource interface for testing
+type mockPriceSource struct {
+	prices map[string]float64
+	err    error
+	calls  int
+}
+
+func (m *mockPriceSource) ListTLDPrices(ctx context.Context) ([]types.DomainPrice, error) {
+	m.calls++
+	var prices []types.DomainPrice
+	for tld, price := range m.prices {
+		prices = append(prices, types.DomainPrice{
+			Name:              aws.String(tld),
+			RegistrationPrice: &types.PriceWithCurrency{Price: price, Currency: aws.String("USD")},
+			RenewalPrice:      &types.PriceWithCurrency{Price: price + 1, Currency: aws.String("USD")},
+		})
+	}
+	return prices, m.err
+}
+
+// writePriceCache caches registration prices at path as if fetched at fetchedAt
+func writePriceCache(t *testing.T, path string, fetchedAt time.Time, prices map[string]float64) {
+	t.Helper()
+	list := &PriceList{FetchedAt: fetchedAt, Prices: make(map[string]*PricingInfo)}
+	for tld, price := range prices {
+		list.Prices[tld] = &PricingInfo{RegistrationPrice: &price, Currency: "USD"}
+	}
+	if err := NewPriceCatalog(nil, path, time.Hour).writeCache(list); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPriceCatalog_Load(t *testing.T) {
+	apiErr := errors.New("access denied")
+
+	tests := []struct {
+		name           string
+		cacheAge       time.Duration // negative means no cache file
+		source         *mockPriceSource
+		expectedOrigin CatalogOrigin
+		expectedPrice  float64 // registration price of .com, 0 for no list
+		expectErr      bool
+		expectFetch    bool
+	}{
+		{"fresh cache is used without calling the API", time.Hour, &mockPriceSource{prices: map[string]float64{"com": 14}}, OriginCache, 10, false, false},
+		{"stale cache is refreshed from the API", 48 * time.Hour, &mockPriceSource{prices: map[string]float64{"COM": 14}}, OriginAPI, 14, false, true},
+		{"no cache fetches from the API", -1, &mockPriceSource{prices: map[string]float64{"com": 14}}, OriginAPI, 14, false, true},
+		{"stale cache is used when the API fails", 48 * time.Hour, &mockPriceSource{err: apiErr}, OriginStale, 10, true, true},
+		{"no prices when offline without a cache", -1, &mockPriceSource{err: apiErr}, "", 0, true, true},
+		{"empty API response is an error", -1, &mockPriceSource{}, "", 0, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "prices.json")
+			if tt.cacheAge >= 0 {
+				writePriceCache(t, path, time.Now().Add(-tt.cacheAge), map[string]float64{"com": 10})
+			}
+
+			list, origin, err := NewPriceCatalog(tt.source, path, 24*time.Hour).Load(context.Background())
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if origin != tt.expectedOrigin {
+				t.Errorf("expected origin %q, got %q", tt.expectedOrigin, origin)
+			}
+			if tt.expectedPrice == 0 {
+				if list != nil {
+					t.Errorf("expected no price list, got %+v", list)
+				}
+			} else if list == nil || list.Prices["com"] == nil || *list.Prices["com"].RegistrationPrice != tt.expectedPrice {
+				t.Errorf("expected .com at %v, got %+v", tt.expectedPrice, list)
+			}
+			if (tt.source.calls > 0) != tt.expectFetch {
+				t.Errorf("expected fetch %v, got %d calls", tt.expectFetch, tt.source.calls)
+			}
+		})
+	}
+}
+
+func TestPriceCatalog_LoadWritesCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "prices.json")
+	source := &mockPriceSource{prices: map[string]float64{"com": 14, "io": 40}}
+
+	if _, _, err := NewPriceCatalog(source, path, time.Hour).Load(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A second catalog should now be served from disk, renewal prices included
+	second := &mockPriceSource{err: errors.New("should not be called")}
+	list, origin, err := NewPriceCatalog(second, path, time.Hour).Load(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if origin != OriginCache || len(list.Prices) != 2 || second.calls != 0 {
+		t.Errorf("expected cached prices, got origin %s, prices %v, calls %d", origin, list.Prices, second.calls)
+	}
+	if io := list.Prices["io"]; io == nil || *io.RenewalPrice != 41 || io.Currency != "USD" || io.TransferPrice != nil {
+		t.Errorf("expected the .io prices to round trip, got %+v", io)
+	}
+}
+
+func TestPriceCatalog_Refresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json")
+	writePriceCache(t, path, time.Now(), map[string]float64{"com": 10})
+
+	source := &mockPriceSource{prices: map[string]float64{"com": 14}}
+	list, err := NewPriceCatalog(source, path, time.Hour).Refresh(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if source.calls != 1 || *list.Prices["com"].RegistrationPrice != 14 {
+		t.Errorf("expected refresh to bypass a fresh cache, got %+v after %d calls", list.Prices, source.calls)
+	}
+}
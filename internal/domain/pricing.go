@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// priceCache remembers the price of each TLD for the life of a checker, so
+// bulk checks with pricing call ListPrices once per TLD instead of once per
+// available domain. Failed lookups are not remembered.
+type priceCache struct {
+	mu      sync.Mutex
+	entries map[string]*priceEntry
+}
+
+// priceEntry is the price of a TLD, ready once done is closed
+type priceEntry struct {
+	done    chan struct{}
+	pricing *PricingInfo
+	err     error
+}
+
+// get returns the price of tld, calling fetch once when it is not cached.
+// Concurrent calls for the same TLD wait for a single fetch.
+func (p *priceCache) get(tld string, fetch func() (*PricingInfo, error)) (*PricingInfo, error) {
+	p.mu.Lock()
+	if p.entries == nil {
+		p.entries = make(map[string]*priceEntry)
+	}
+	if entry, ok := p.entries[tld]; ok {
+		p.mu.Unlock()
+		<-entry.done
+		return entry.pricing, entry.err
+	}
+	entry := &priceEntry{done: make(chan struct{})}
+	p.entries[tld] = entry
+	p.mu.Unlock()
+
+	entry.pricing, entry.err = fetch()
+	if entry.err != nil {
+		p.mu.Lock()
+		delete(p.entries, tld)
+		p.mu.Unlock()
+	}
+	close(entry.done)
+	return entry.pricing, entry.err
+}
+
+// TLDPricing returns the prices Route 53 lists for registering, renewing and
+// transferring a domain under tld, or nil when it lists none. Prices are
+// fetched once per checker and shared with pricing checks, and TLDs in the
+// price list set with SetPriceList are not fetched at all.
+func (c *DomainChecker) TLDPricing(ctx context.Context, tld string) (*PricingInfo, error) {
+	if pricing, ok := c.priceList[strings.ToLower(tld)]; ok {
+		return pricing, nil
+	}
+	return c.prices.get(tld, func() (*PricingInfo, error) {
+		timeoutCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+
+		priceResult, err := c.awsClient.ListPrices(timeoutCtx, tld)
+		if err != nil {
+			return nil, err
+		}
+		if priceResult == nil || len(priceResult.Prices) == 0 {
+			return nil, nil
+		}
+
+		return newPricingInfo(priceResult.Prices[0]), nil
+	})
+}
+
+// SetPriceList prices the TLDs in prices, keyed by lowercase TLD, without
+// asking Route 53. Other TLDs are still fetched. A nil list removes it.
+func (c *DomainChecker) SetPriceList(prices map[string]*PricingInfo) {
+	c.priceList = prices
+}
+
+// newPricingInfo converts a price Route 53 Domains lists for a TLD
+func newPricingInfo(price types.DomainPrice) *PricingInfo {
+	pricing := &PricingInfo{
+		Currency: "USD", // Route 53 pricing is in USD
+	}
+	if price.RegistrationPrice != nil {
+		regPrice := price.RegistrationPrice.Price
+		pricing.RegistrationPrice = &regPrice
+	}
+	if price.RenewalPrice != nil {
+		renewPrice := price.RenewalPrice.Price
+		pricing.RenewalPrice = &renewPrice
+	}
+	if price.TransferPrice != nil {
+		transferPrice := price.TransferPrice.Price
+		pricing.TransferPrice = &transferPrice
+	}
+	return pricing
+}
+
+// Cost returns the cost of holding a domain for years: its registration
+// followed by years-1 renewals. It is false when a price it needs is unknown.
+func (p *PricingInfo) Cost(years int) (float64, bool) {
+	if p == nil || p.RegistrationPrice == nil || years < 1 {
+		return 0, false
+	}
+	if years == 1 {
+		return *p.RegistrationPrice, true
+	}
+	if p.RenewalPrice == nil {
+		return 0, false
+	}
+	return *p.RegistrationPrice + float64(years-1)**p.RenewalPrice, true
+}
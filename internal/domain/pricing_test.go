@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// countingPrices counts ListPrices calls per TLD
+type countingPrices struct {
+	MockRoute53Client
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (c *countingPrices) ListPrices(ctx context.Context, tld string) (*route53domains.ListPricesOutput, error) {
+	c.mu.Lock()
+	c.calls[tld]++
+	c.mu.Unlock()
+	return c.MockRoute53Client.ListPrices(ctx, tld)
+}
+
+func TestTLDPricing_Cache(t *testing.T) {
+	client := &countingPrices{
+		MockRoute53Client: MockRoute53Client{
+			response: &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable},
+			pricesResponse: &route53domains.ListPricesOutput{Prices: []types.DomainPrice{{
+				RegistrationPrice: &types.PriceWithCurrency{Price: 12},
+			}}},
+		},
+		calls: make(map[string]int),
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+	checker.SetConcurrency(1)
+
+	results, err := checker.CheckAvailabilityBulkWithPricing(context.Background(), []string{"a.com", "b.com", "c.com", "d.io"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, result := range results {
+		if result.Pricing == nil || result.Pricing.RegistrationPrice == nil || *result.Pricing.RegistrationPrice != 12 {
+			t.Errorf("expected %s to be priced at 12, got %+v", result.Domain, result.Pricing)
+		}
+	}
+	if client.calls["com"] != 1 || client.calls["io"] != 1 {
+		t.Errorf("expected one ListPrices call per TLD, got %v", client.calls)
+	}
+
+	// Each result holds its own copy of the price
+	results[0].Pricing.Currency = "EUR"
+	if results[1].Pricing.Currency != "USD" {
+		t.Error("expected results not to share pricing")
+	}
+}
+
+func TestTLDPricing_PriceList(t *testing.T) {
+	price := 9.0
+	client := &countingPrices{
+		MockRoute53Client: MockRoute53Client{pricesResponse: &route53domains.ListPricesOutput{}},
+		calls:             make(map[string]int),
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+	checker.SetPriceList(map[string]*PricingInfo{"com": {RegistrationPrice: &price, Currency: "USD"}})
+
+	pricing, err := checker.TLDPricing(context.Background(), "COM")
+	if err != nil || pricing == nil || *pricing.RegistrationPrice != 9 {
+		t.Errorf("expected the listed price, got %+v, %v", pricing, err)
+	}
+	if _, err := checker.TLDPricing(context.Background(), "io"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if client.calls["com"] != 0 || client.calls["COM"] != 0 || client.calls["io"] != 1 {
+		t.Errorf("expected only the unlisted TLD to be fetched, got %v", client.calls)
+	}
+}
+
+func TestTLDPricing_ErrorsNotCached(t *testing.T) {
+	client := &countingPrices{
+		MockRoute53Client: MockRoute53Client{pricesErr: errors.New("throttled")},
+		calls:             make(map[string]int),
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+
+	if _, err := checker.TLDPricing(context.Background(), "com"); err == nil {
+		t.Fatal("expected an error")
+	}
+	client.pricesErr = nil
+	client.pricesResponse = &route53domains.ListPricesOutput{}
+	pricing, err := checker.TLDPricing(context.Background(), "com")
+	if err != nil || pricing != nil {
+		t.Errorf("expected no price after the retry, got %+v, %v", pricing, err)
+	}
+	if client.calls["com"] != 2 {
+		t.Errorf("expected the failed lookup to be retried, got %d calls", client.calls["com"])
+	}
+}
+
+func TestPricingInfo_Cost(t *testing.T) {
+	registration, renewal := 12.0, 15.0
+
+	tests := []struct {
+		name    string
+		pricing *PricingInfo
+		years   int
+		cost    float64
+		ok      bool
+	}{
+		{"one year", &PricingInfo{RegistrationPrice: &registration, RenewalPrice: &renewal}, 1, 12, true},
+		{"three years", &PricingInfo{RegistrationPrice: &registration, RenewalPrice: &renewal}, 3, 42, true},
+		{"one year without renewal price", &PricingInfo{RegistrationPrice: &registration}, 1, 12, true},
+		{"renewal price unknown", &PricingInfo{RegistrationPrice: &registration}, 2, 0, false},
+		{"registration price unknown", &PricingInfo{RenewalPrice: &renewal}, 2, 0, false},
+		{"no pricing", nil, 2, 0, false},
+		{"no years", &PricingInfo{RegistrationPrice: &registration, RenewalPrice: &renewal}, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, ok := tt.pricing.Cost(tt.years)
+			if cost != tt.cost || ok != tt.ok {
+				t.Errorf("Cost(%d) = %v, %v, want %v, %v", tt.years, cost, ok, tt.cost, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// EffectiveTLD returns the registry suffix of a domain name according to the
+// ICANN section of the Public Suffix List, so "example.co.uk" yields "co.uk"
+// rather than "uk". Private suffixes such as "github.io" are skipped because
+// they are not registries. Names without a dot return "".
+func EffectiveTLD(name string) string {
+	name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return ""
+	}
+
+	// Try the longest proper suffix first
+	for i := 1; i < len(labels); i++ {
+		candidate := strings.Join(labels[i:], ".")
+		if suffix, icann := publicsuffix.PublicSuffix(candidate); icann && suffix == candidate {
+			return candidate
+		}
+	}
+
+	// Unknown TLDs are not on the list; fall back to the last label
+	return labels[len(labels)-1]
+}
+
+// RegistrableDomain returns the part of a domain name that can be registered:
+// the effective TLD plus one label, so "www.example.co.uk" yields
+// "example.co.uk". It returns "" when name is itself a public suffix.
+func RegistrableDomain(name string) string {
+	name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+	tld := EffectiveTLD(name)
+	if tld == "" || name == tld {
+		return ""
+	}
+
+	rest := strings.TrimSuffix(name, "."+tld)
+	if i := strings.LastIndex(rest, "."); i >= 0 {
+		rest = rest[i+1:]
+	}
+	return rest + "." + tld
+}
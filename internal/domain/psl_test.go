@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestEffectiveTLD(t *testing.T) {
+	tests := []struct {
+		domain   string
+		expected string
+	}{
+		{"example.com", "com"},
+		{"example.co.uk", "co.uk"},
+		{"www.example.co.uk", "co.uk"},
+		{"example.com.au", "com.au"},
+		{"Example.CO.UK.", "co.uk"},
+		{"example.uk", "uk"},
+		{"co.uk", "uk"},
+		{"site.github.io", "io"},
+		{"foo.blogspot.com", "com"},
+		{"example.unknowntld", "unknowntld"},
+		{"single", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			if got := EffectiveTLD(tt.domain); got != tt.expected {
+				t.Errorf("EffectiveTLD(%q) = %q, want %q", tt.domain, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		domain   string
+		expected string
+	}{
+		{"example.com", "example.com"},
+		{"www.example.com", "example.com"},
+		{"a.b.example.co.uk", "example.co.uk"},
+		{"example.com.au.", "example.com.au"},
+		{"site.github.io", "github.io"},
+		{"co.uk", "co.uk"},
+		{"com", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			if got := RegistrableDomain(tt.domain); got != tt.expected {
+				t.Errorf("RegistrableDomain(%q) = %q, want %q", tt.domain, got, tt.expected)
+			}
+		})
+	}
+}
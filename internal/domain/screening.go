@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Screener inspects a domain before it is checked and returns warnings to
+// show alongside the result. Screeners never block a check.
+type Screener interface {
+	Screen(domain string) []string
+}
+
+// ReservedWords screens domains against a list of protected words, such as
+// brand names a company does not want registered by accident
+type ReservedWords struct {
+	words  []string
+	Source string // Where the list came from, used in warnings
+}
+
+// ParseReservedWords reads reserved words from r, one per line. Empty lines
+// and lines starting with # are ignored.
+func ParseReservedWords(r io.Reader, source string) (*ReservedWords, error) {
+	reserved := &ReservedWords{Source: source}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		reserved.words = append(reserved.words, strings.ToLower(line))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", source, err)
+	}
+
+	return reserved, nil
+}
+
+// LoadReservedWordsFile reads a reserved words list from the file at path
+func LoadReservedWordsFile(path string) (*ReservedWords, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reserved words file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseReservedWords(file, path)
+}
+
+// Len returns the number of reserved words
+func (w *ReservedWords) Len() int {
+	return len(w.words)
+}
+
+// Screen warns when the registrable label of domain contains a reserved word.
+// Hyphens are ignored and confusable characters are folded, so "my-acme",
+// "acme-shop" and "acrne" all match "acme".
+func (w *ReservedWords) Screen(domain string) []string {
+	name := ToUnicode(strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), ".")))
+	label := secondLevelLabel(name)
+	if label == "" {
+		return nil
+	}
+	folded := skeleton(strings.ReplaceAll(label, "-", ""))
+
+	var warnings []string
+	for _, word := range w.words {
+		if strings.Contains(folded, skeleton(strings.ReplaceAll(word, "-", ""))) {
+			warnings = append(warnings, fmt.Sprintf("'%s' matches reserved word '%s' (%s)", label, word, w.Source))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+func TestParseReservedWords(t *testing.T) {
+	input := `# brand names
+Acme
+
+roadrunner
+`
+	reserved, err := ParseReservedWords(strings.NewReader(input), "brands.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reserved.Len() != 2 {
+		t.Errorf("expected 2 words, got %d", reserved.Len())
+	}
+	if reserved.Source != "brands.txt" {
+		t.Errorf("expected source brands.txt, got %s", reserved.Source)
+	}
+}
+
+func TestLoadReservedWordsFile_Missing(t *testing.T) {
+	if _, err := LoadReservedWordsFile("/nonexistent/brands.txt"); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestReservedWords_Screen(t *testing.T) {
+	reserved, _ := ParseReservedWords(strings.NewReader("acme\nroad-runner\n"), "brands.txt")
+
+	tests := []struct {
+		domain   string
+		expected []string
+	}{
+		{"example.com", nil},
+		{"acme.com", []string{"'acme' matches reserved word 'acme' (brands.txt)"}},
+		{"buy-acme-online.net", []string{"'buy-acme-online' matches reserved word 'acme' (brands.txt)"}},
+		{"ACME.io", []string{"'acme' matches reserved word 'acme' (brands.txt)"}},
+		{"acrne.com", []string{"'acrne' matches reserved word 'acme' (brands.txt)"}},
+		{"roadrunner.co.uk", []string{"'roadrunner' matches reserved word 'road-runner' (brands.txt)"}},
+		{"acme.example.com", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			warnings := reserved.Screen(tt.domain)
+			if strings.Join(warnings, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("Screen(%q) = %q, want %q", tt.domain, warnings, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckAvailability_Screeners(t *testing.T) {
+	client := &MockRoute53Client{
+		response: &route53domains.CheckDomainAvailabilityOutput{
+			Availability: types.DomainAvailabilityAvailable,
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+	reserved, _ := ParseReservedWords(strings.NewReader("paypal\n"), "brands.txt")
+	checker.AddScreener(reserved)
+	checker.SetWarnConfusables(true)
+
+	result, err := checker.CheckAvailability(context.Background(), "paypa1.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"'paypa1' looks like 'paypal'",
+		"'paypa1' matches reserved word 'paypal' (brands.txt)",
+	}
+	if strings.Join(result.Warnings, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected warnings %q, got %q", expected, result.Warnings)
+	}
+	if result.Status != StatusAvailable {
+		t.Errorf("Expected screening not to affect the status, got %s", result.Status)
+	}
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+// streamItem carries one finished check from a worker to the consumer
+type streamItem struct {
+	result *AvailabilityResult
+	err    error
+}
+
+// Stream checks every domain produced by domains with bounded concurrency and
+// yields each result as soon as it completes, so callers can feed unbounded
+// inputs without holding them in memory. Results arrive in completion order.
+// Breaking out of the loop cancels the checks still in flight, and waits for
+// domains to yield its next domain or end, so none of its code runs after
+// Stream returns; an input that blocks, such as one reading stdin, delays the
+// return until it produces a line. If ctx is cancelled, the final pair
+// yielded is a nil result with the cancellation error.
+func (c *DomainChecker) Stream(ctx context.Context, domains iter.Seq[string]) iter.Seq2[*AvailabilityResult, error] {
+	return c.stream(ctx, domains, c.CheckAvailability)
+}
+
+// StreamWithPricing is Stream with pricing information for available domains
+func (c *DomainChecker) StreamWithPricing(ctx context.Context, domains iter.Seq[string]) iter.Seq2[*AvailabilityResult, error] {
+	return c.stream(ctx, domains, c.CheckAvailabilityWithPricing)
+}
+
+// stream fans domains out to c.concurrency workers and fans their results back in.
+// Unlike checkBulk there is no separate retry pass; a failed check is
+// re-attempted by the same worker, retryDelay after each failure, for as long
+// as the retry policy leaves a budget for its latest error.
+func (c *DomainChecker) stream(ctx context.Context, domains iter.Seq[string], check func(context.Context, string) (*AvailabilityResult, error)) iter.Seq2[*AvailabilityResult, error] {
+	return func(yield func(*AvailabilityResult, error) bool) {
+		runCtx, cancelRun := context.WithCancel(ctx)
+		defer cancelRun()
+
+		jobs := make(chan string)
+		items := make(chan streamItem)
+
+		// Feed domains to the workers until the input ends or the run stops.
+		// The feeder is in wg, so the stream does not return while it is
+		// still inside the caller's sequence.
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(jobs)
+			for domain := range domains {
+				select {
+				case jobs <- domain:
+				case <-runCtx.Done():
+					return
+				}
+			}
+		}()
+
+		for i := 0; i < c.concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					var domain string
+					var ok bool
+					select {
+					case domain, ok = <-jobs:
+						if !ok {
+							return
+						}
+					case <-runCtx.Done():
+						return
+					}
+
+					result, err := c.checkWithRetry(runCtx, domain, check)
+					select {
+					case items <- streamItem{result: result, err: err}:
+					case <-runCtx.Done():
+						return
+					}
+				}
+			}()
+		}
+
+		go func() {
+			wg.Wait()
+			close(items)
+		}()
+
+		for item := range items {
+			if !yield(item.result, item.err) {
+				cancelRun()
+				// Wait for the workers so none outlive the loop
+				for range items {
+				}
+				return
+			}
+		}
+
+		if ctx.Err() != nil {
+			yield(nil, customErrors.WrapSystemError("stream", ctx.Err()))
+		}
+	}
+}
+
+// checkWithRetry runs check again after retryDelay while it fails with an
+// error that has retries left in the retry policy. A negative retryDelay
+// disables the retries.
+func (c *DomainChecker) checkWithRetry(ctx context.Context, domain string, check func(context.Context, string) (*AvailabilityResult, error)) (*AvailabilityResult, error) {
+	result, err := check(ctx, domain)
+retry:
+	for retries := 0; err != nil && c.retryDelay >= 0 && retries < c.retryBudget(ctx, err); {
+		select {
+		case <-ctx.Done():
+			break retry
+		case <-time.After(c.retryDelay):
+		}
+		retries++
+		c.notifyRetry(ctx, domain, retries+1, err)
+		result, err = check(ctx, domain)
+		if result != nil {
+			result.Retried = true
+			result.Retries = retries
+		}
+	}
+	if result != nil {
+		result.CompletedAt = time.Now()
+	}
+	return result, err
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+func TestStream_YieldsEveryResult(t *testing.T) {
+	throttled := customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "rate limited", nil).WithStatusCode(429)
+	denied := customErrors.NewAuthorizationError("CheckDomainAvailability", "route53domains", "denied", nil)
+
+	client := &scriptedRoute53Client{
+		fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+			switch domain {
+			case "throttled.com":
+				if attempt == 1 {
+					return nil, throttled
+				}
+			case "denied.com":
+				return nil, denied
+			}
+			return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable}, nil
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+	checker.SetRetryDelay(0)
+
+	domains := []string{"a.com", "b.com", "throttled.com", "denied.com"}
+
+	var got []string
+	for result, err := range checker.Stream(context.Background(), slices.Values(domains)) {
+		if result == nil {
+			t.Fatalf("Unexpected nil result with error %v", err)
+		}
+		got = append(got, result.Domain)
+
+		switch result.Domain {
+		case "throttled.com":
+			if err != nil || !result.Retried || !result.Available {
+				t.Errorf("Expected throttled.com to succeed on retry, got %+v, %v", result, err)
+			}
+		case "denied.com":
+			if err == nil || result.Retried {
+				t.Errorf("Expected denied.com to fail without retry, got %+v, %v", result, err)
+			}
+		default:
+			if err != nil || result.CompletedAt.IsZero() {
+				t.Errorf("Expected %s to succeed, got %+v, %v", result.Domain, result, err)
+			}
+		}
+	}
+
+	sort.Strings(got)
+	want := slices.Clone(domains)
+	sort.Strings(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected results for %v, got %v", want, got)
+	}
+}
+
+func TestStream_BreakStopsInput(t *testing.T) {
+	var produced atomic.Int64
+	domains := func(yield func(string) bool) {
+		for i := 0; ; i++ {
+			produced.Add(1)
+			if !yield(fmt.Sprintf("domain%d.com", i)) {
+				return
+			}
+		}
+	}
+
+	client := &scriptedRoute53Client{
+		fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+			return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityUnavailable}, nil
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+	checker.SetConcurrency(2)
+
+	count := 0
+	for range checker.Stream(context.Background(), domains) {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+
+	if count != 10 {
+		t.Fatalf("Expected 10 results before break, got %d", count)
+	}
+	// The producer may be a few domains ahead of the consumer, never unbounded
+	if n := produced.Load(); n > 20 {
+		t.Errorf("Expected input to stop shortly after break, produced %d domains", n)
+	}
+}
+
+func TestStream_BreakWaitsForSlowInput(t *testing.T) {
+	var finished atomic.Bool
+	domains := func(yield func(string) bool) {
+		defer finished.Store(true)
+		for i := 0; ; i++ {
+			// A slow input, such as a scanner waiting on stdin
+			time.Sleep(20 * time.Millisecond)
+			if !yield(fmt.Sprintf("domain%d.com", i)) {
+				return
+			}
+		}
+	}
+
+	client := &scriptedRoute53Client{
+		fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+			return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable}, nil
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+
+	for range checker.Stream(context.Background(), domains) {
+		break
+	}
+
+	if !finished.Load() {
+		t.Error("Expected the input sequence to have returned before Stream did")
+	}
+}
+
+func TestStream_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := &scriptedRoute53Client{
+		fn: func(ctx context.Context, domain string, attempt int) (*route53domains.CheckDomainAvailabilityOutput, error) {
+			return &route53domains.CheckDomainAvailabilityOutput{Availability: types.DomainAvailabilityAvailable}, nil
+		},
+	}
+	checker := NewDomainChecker(&MockValidator{}, client)
+
+	var lastErr error
+	for _, err := range checker.Stream(ctx, slices.Values([]string{"a.com", "b.com"})) {
+		lastErr = err
+	}
+
+	var sysErr *customErrors.SystemError
+	if !errors.As(lastErr, &sysErr) {
+		t.Errorf("Expected final SystemError for cancelled context, got %v", lastErr)
+	}
+}
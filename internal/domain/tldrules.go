@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TLDRule describes registry constraints on the label registered directly
+// under a TLD, beyond the general DNS rules checked for every domain
+type TLDRule struct {
+	MinLength  int      // Shortest accepted label in characters, 0 for no minimum
+	MaxLength  int      // Longest accepted label in characters, 0 for the DNS limit
+	NoHyphenAt []int    // 1-based label positions that may not hold a hyphen
+	NoIDN      bool     // Registry does not accept internationalized labels
+	Restricted []string // Labels the registry withholds from registration
+}
+
+// gTLDReserved are the second-level labels generic TLD registries reserve for their own use
+var gTLDReserved = []string{"nic", "whois", "www"}
+
+// DefaultTLDRules returns the built-in per-TLD rules. TLDs without an entry
+// are only subject to the general DNS rules.
+func DefaultTLDRules() map[string]TLDRule {
+	return map[string]TLDRule{
+		"info":   {Restricted: gTLDReserved},
+		"biz":    {Restricted: gTLDReserved},
+		"name":   {Restricted: gTLDReserved},
+		"asia":   {Restricted: gTLDReserved},
+		"tel":    {Restricted: gTLDReserved},
+		"mobi":   {MinLength: 3, Restricted: gTLDReserved},
+		"io":     {MinLength: 3},
+		"me":     {MinLength: 3},
+		"ai":     {MinLength: 3},
+		"eu":     {MinLength: 2},
+		"ca":     {MinLength: 2},
+		"be":     {MinLength: 2},
+		"nl":     {MinLength: 2},
+		"com.au": {MinLength: 2},
+		"net.au": {MinLength: 2},
+		"org.au": {MinLength: 2},
+	}
+}
+
+// ruleErrors checks the label registered under the domain's TLD against the
+// most specific matching rule
+func (v *DomainValidator) ruleErrors(domain string) []string {
+	if len(v.rules) == 0 {
+		return nil
+	}
+
+	tld := matchRule(domain, v.rules)
+	if tld == "" {
+		return nil
+	}
+	rule := v.rules[tld]
+
+	rest := strings.TrimSuffix(domain, "."+tld)
+	label := rest[strings.LastIndex(rest, ".")+1:]
+	unicodeLabel := ToUnicode(label)
+	runes := []rune(unicodeLabel)
+	length := len(runes)
+
+	var problems []string
+	if rule.MinLength > 0 && length < rule.MinLength {
+		problems = append(problems, fmt.Sprintf("label '%s' is too short for .%s: minimum %d characters", unicodeLabel, tld, rule.MinLength))
+	}
+	if rule.MaxLength > 0 && length > rule.MaxLength {
+		problems = append(problems, fmt.Sprintf("label '%s' is too long for .%s: maximum %d characters", unicodeLabel, tld, rule.MaxLength))
+	}
+	for _, position := range rule.NoHyphenAt {
+		if position >= 1 && position <= len(runes) && runes[position-1] == '-' {
+			problems = append(problems, fmt.Sprintf("hyphen not allowed at position %d for .%s", position, tld))
+		}
+	}
+	if rule.NoIDN && strings.HasPrefix(label, "xn--") {
+		problems = append(problems, fmt.Sprintf("internationalized labels are not accepted for .%s", tld))
+	}
+	for _, restricted := range rule.Restricted {
+		if label == restricted {
+			problems = append(problems, fmt.Sprintf("'%s' is reserved by the .%s registry", label, tld))
+			break
+		}
+	}
+
+	return problems
+}
+
+// matchRule returns the longest suffix of domain that has a rule, or ""
+func matchRule(domain string, rules map[string]TLDRule) string {
+	labels := strings.Split(domain, ".")
+	for i := 1; i < len(labels); i++ {
+		suffix := strings.Join(labels[i:], ".")
+		if _, ok := rules[suffix]; ok {
+			return suffix
+		}
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+func TestValidateDomain_DefaultTLDRules(t *testing.T) {
+	validator := NewDomainValidatorWithTLDs([]string{"com", "io", "info", "com.au", "au"})
+
+	tests := []struct {
+		domain          string
+		expectedMessage string // empty means valid
+	}{
+		{"ab.com", ""},
+		{"abc.io", ""},
+		{"ab.io", "label 'ab' is too short for .io: minimum 3 characters"},
+		{"www.ab.io", "label 'ab' is too short for .io: minimum 3 characters"},
+		{"nic.info", "'nic' is reserved by the .info registry"},
+		{"nic.com", ""},
+		{"ab.com.au", ""},
+		{"a.com.au", "label 'a' is too short for .com.au: minimum 2 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			err := validator.ValidateDomain(tt.domain)
+			if tt.expectedMessage == "" {
+				if err != nil {
+					t.Errorf("Expected %s to be valid, got %v", tt.domain, err)
+				}
+				return
+			}
+
+			var validationErr *customErrors.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected ValidationError, got %T: %v", err, err)
+			}
+			if validationErr.Field != "tld-rule" || validationErr.Message != tt.expectedMessage {
+				t.Errorf("Expected tld-rule error %q, got %s error %q", tt.expectedMessage, validationErr.Field, validationErr.Message)
+			}
+		})
+	}
+}
+
+func TestValidateDomain_CustomTLDRules(t *testing.T) {
+	validator := NewDomainValidatorWithTLDs([]string{"test"})
+	validator.SetTLDRules(map[string]TLDRule{
+		"test": {MaxLength: 5, NoHyphenAt: []int{3}, NoIDN: true},
+	})
+
+	tests := []struct {
+		domain          string
+		expectedMessage string
+	}{
+		{"abcde.test", ""},
+		{"abcdef.test", "label 'abcdef' is too long for .test: maximum 5 characters"},
+		{"ab-c.test", "hyphen not allowed at position 3 for .test"},
+		{"a-bc.test", ""},
+		{"büch.test", "internationalized labels are not accepted for .test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			err := validator.ValidateDomain(tt.domain)
+			if tt.expectedMessage == "" {
+				if err != nil {
+					t.Errorf("Expected %s to be valid, got %v", tt.domain, err)
+				}
+				return
+			}
+			var validationErr *customErrors.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Message != tt.expectedMessage {
+				t.Errorf("Expected %q, got %v", tt.expectedMessage, err)
+			}
+		})
+	}
+
+	// Rules can be switched off entirely
+	validator.SetTLDRules(nil)
+	if err := validator.ValidateDomain("abcdef.test"); err != nil {
+		t.Errorf("Expected no rule errors with rules disabled, got %v", err)
+	}
+}
+
+func TestValidateDomain_TLDRulesSkippedForUnsupportedTLD(t *testing.T) {
+	validator := NewDomainValidatorWithTLDs([]string{"com"})
+
+	err := validator.ValidateDomain("ab.io")
+	var validationErr *customErrors.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "tld" {
+		t.Errorf("Expected only the unsupported TLD error, got %v", err)
+	}
+}
@@ -16,51 +16,35 @@ type Validator interface {
 type DomainValidator struct {
 	supportedTLDs map[string]bool
 	domainRegex   *regexp.Regexp
+	policy        *TLDPolicy
+	rules         map[string]TLDRule
 }
 
-// NewDomainValidator creates a new domain validator with supported TLDs
+// DefaultTLDs returns the built-in list of supported TLDs, used when the
+// Route 53 TLD catalog cannot be loaded
+func DefaultTLDs() []string {
+	return []string{
+		"com", "net", "org", "io", "co", "info", "biz", "name", "me", "tv",
+		"cc", "ws", "mobi", "tel", "asia", "us", "uk", "ca", "au", "de",
+		"fr", "it", "es", "nl", "be", "ch", "at", "se", "no", "dk",
+		"fi", "pl", "cz", "ru", "jp", "cn", "in", "br", "mx",
+	}
+}
+
+// NewDomainValidator creates a new domain validator with the built-in supported TLDs
 func NewDomainValidator() *DomainValidator {
-	// Supported TLDs as specified in requirements
-	supportedTLDs := map[string]bool{
-		"com":  true,
-		"net":  true,
-		"org":  true,
-		"io":   true,
-		"co":   true,
-		"info": true,
-		"biz":  true,
-		"name": true,
-		"me":   true,
-		"tv":   true,
-		"cc":   true,
-		"ws":   true,
-		"mobi": true,
-		"tel":  true,
-		"asia": true,
-		"us":   true,
-		"uk":   true,
-		"ca":   true,
-		"au":   true,
-		"de":   true,
-		"fr":   true,
-		"it":   true,
-		"es":   true,
-		"nl":   true,
-		"be":   true,
-		"ch":   true,
-		"at":   true,
-		"se":   true,
-		"no":   true,
-		"dk":   true,
-		"fi":   true,
-		"pl":   true,
-		"cz":   true,
-		"ru":   true,
-		"jp":   true,
-		"cn":   true,
-		"in":   true,
-		"br":   true,
-		"mx":   true,
+	return NewDomainValidatorWithTLDs(DefaultTLDs())
+}
+
+// NewDomainValidatorWithTLDs creates a new domain validator that accepts the given TLDs.
+// Multi-label TLDs such as "co.uk" are matched against the end of the domain.
+func NewDomainValidatorWithTLDs(tlds []string) *DomainValidator {
+	supportedTLDs := make(map[string]bool, len(tlds))
+	for _, tld := range tlds {
+		tld = strings.ToLower(strings.Trim(strings.TrimSpace(tld), "."))
+		if tld != "" {
+			supportedTLDs[tld] = true
+		}
 	}
 
 	// Domain regex pattern:
@@ -70,11 +54,13 @@ func NewDomainValidator() *DomainValidator {
 	// - Total length must be <= 253 characters
 	// - Must have at least one dot separating domain and TLD
 	// - No consecutive hyphens allowed
-	domainRegex := regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])?)*\.[a-zA-Z]{2,}$`)
+	// - TLD is alphabetic or a punycode ("xn--") label
+	domainRegex := regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])?)*\.([a-zA-Z]{2,}|xn--[a-zA-Z0-9\-]{1,59})$`)
 
 	return &DomainValidator{
 		supportedTLDs: supportedTLDs,
 		domainRegex:   domainRegex,
+		rules:         DefaultTLDRules(),
 	}
 }
 
@@ -93,78 +79,170 @@ func (v *DomainValidator) ValidateDomain(domain string) error {
 		return errors.NewValidationError(originalDomain, "domain", "domain cannot be empty", nil)
 	}
 
+	// Internationalized names are validated in their ASCII (punycode) form
+	if !isASCII(domain) {
+		asciiDomain, err := ToASCII(domain)
+		if err != nil {
+			return errors.NewValidationError(originalDomain, "idn", "invalid internationalized domain name", err)
+		}
+		domain = asciiDomain
+	}
+
+	// Collect every rule the domain violates rather than stopping at the first
+	var problems []*errors.ValidationError
+	report := func(field, message string) {
+		problems = append(problems, errors.NewValidationError(domain, field, message, nil))
+	}
+
 	// Check overall length (RFC 1035)
 	if len(domain) > 253 {
-		return errors.NewValidationError(domain, "length", "domain name too long: maximum 253 characters allowed", nil)
+		report("length", "domain name too long: maximum 253 characters allowed")
 	}
 
 	// Check minimum length
 	if len(domain) < 3 {
-		return errors.NewValidationError(domain, "length", "domain name too short: minimum 3 characters required", nil)
+		report("length", "domain name too short: minimum 3 characters required")
 	}
 
 	// Additional validation checks first (more specific errors)
-	if err := v.validateLabels(domain); err != nil {
-		return errors.WrapValidationError(domain, err)
+	for _, err := range v.labelErrors(domain) {
+		problems = append(problems, errors.NewValidationError(domain, "format", err.Error(), err))
 	}
 
-	// Check for consecutive hyphens
-	if strings.Contains(domain, "--") {
-		return errors.NewValidationError(domain, "format", "consecutive hyphens not allowed in domain", nil)
+	// Check for consecutive hyphens, except in the "xn--" prefix of punycode labels
+	for _, label := range strings.Split(domain, ".") {
+		if strings.Contains(strings.TrimPrefix(label, "xn--"), "--") {
+			report("format", "consecutive hyphens not allowed in domain")
+			break
+		}
 	}
 
-	// Validate domain format using regex
-	if !v.domainRegex.MatchString(domain) {
-		return errors.NewValidationError(domain, "format", "invalid domain format", nil)
+	// Validate domain format using regex, which only adds information when
+	// none of the more specific checks above explain the mismatch
+	if len(problems) == 0 && !v.domainRegex.MatchString(domain) {
+		report("format", "invalid domain format")
 	}
 
 	// Extract and validate TLD
 	tld := v.extractTLD(domain)
 	if tld == "" {
-		return errors.NewValidationError(domain, "tld", "unable to extract TLD from domain", nil)
+		report("tld", "unable to extract TLD from domain")
+	} else if err := v.validateTLD(domain, tld); err != nil {
+		problems = append(problems, err)
+	} else {
+		// Registry-specific rules only matter once the TLD itself is acceptable
+		for _, message := range v.ruleErrors(domain) {
+			report("tld-rule", message)
+		}
 	}
 
-	if !v.supportedTLDs[tld] {
-		return errors.NewValidationError(domain, "tld", fmt.Sprintf("unsupported TLD: .%s", tld), nil)
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return problems[0]
+	default:
+		return errors.NewValidationErrors(domain, problems)
+	}
+}
+
+// validateTLD checks the TLD against the user's policy and the supported catalog
+func (v *DomainValidator) validateTLD(domain, tld string) *errors.ValidationError {
+	// Apply the user's TLD policy before the catalog so its errors name the policy
+	if v.policy != nil {
+		if denied := matchSuffix(domain, v.policy.Deny); denied != "" {
+			return errors.NewValidationError(domain, "tld-policy",
+				fmt.Sprintf("TLD .%s is denied by TLD policy (%s)", denied, v.policy.Source), nil)
+		}
+		if len(v.policy.Allow) > 0 && matchSuffix(domain, v.policy.Allow) == "" {
+			return errors.NewValidationError(domain, "tld-policy",
+				fmt.Sprintf("TLD .%s is not allowed by TLD policy (%s); allowed TLDs: %s",
+					tld, v.policy.Source, strings.Join(sortedTLDs(v.policy.Allow), ", ")), nil)
+		}
+	}
+
+	if !v.isSupported(domain) {
+		message := fmt.Sprintf("unsupported TLD: .%s", tld)
+		if suggestion := v.suggestTLD(tld); suggestion != "" {
+			message += fmt.Sprintf(" (did you mean %s?)", suggestion)
+		}
+		return errors.NewValidationError(domain, "tld", message, nil)
 	}
 
 	return nil
 }
 
-// extractTLD extracts the top-level domain from a domain name
+// extractTLD extracts the effective top-level domain from a domain name
 func (v *DomainValidator) extractTLD(domain string) string {
-	parts := strings.Split(domain, ".")
-	if len(parts) < 2 {
-		return ""
+	return EffectiveTLD(domain)
+}
+
+// isSupported reports whether any suffix of the domain is a supported TLD,
+// so both "example.uk" and "example.co.uk" match a catalog containing "co.uk".
+// TLDs added by the policy's extend entries count as supported.
+func (v *DomainValidator) isSupported(domain string) bool {
+	if matchSuffix(domain, v.supportedTLDs) != "" {
+		return true
 	}
-	return parts[len(parts)-1]
+	return v.policy != nil && matchSuffix(domain, v.policy.Extend) != ""
 }
 
-// validateLabels performs additional validation on domain labels
+// suggestTLD returns the supported ".tld" closest to a mistyped tld, such as
+// ".com" for "con", or "" when none is close
+func (v *DomainValidator) suggestTLD(tld string) string {
+	candidates := sortedTLDs(v.supportedTLDs)
+	if v.policy != nil {
+		candidates = append(candidates, sortedTLDs(v.policy.Extend)...)
+	}
+	return errors.Suggest("."+tld, candidates)
+}
+
+// SetTLDPolicy restricts or extends the accepted TLDs. A nil policy removes any restriction.
+func (v *DomainValidator) SetTLDPolicy(policy *TLDPolicy) {
+	v.policy = policy
+}
+
+// SetTLDRules replaces the per-TLD registry rules. A nil map disables them.
+func (v *DomainValidator) SetTLDRules(rules map[string]TLDRule) {
+	v.rules = rules
+}
+
+// validateLabels performs additional validation on domain labels,
+// returning the first problem found
 func (v *DomainValidator) validateLabels(domain string) error {
+	if problems := v.labelErrors(domain); len(problems) > 0 {
+		return problems[0]
+	}
+	return nil
+}
+
+// labelErrors returns every problem found in the domain's labels
+func (v *DomainValidator) labelErrors(domain string) []error {
 	labels := strings.Split(domain, ".")
 
+	var problems []error
 	for i, label := range labels {
 		if label == "" {
-			return fmt.Errorf("empty label in domain")
+			problems = append(problems, fmt.Errorf("empty label in domain"))
+			continue
 		}
 
 		if len(label) > 63 {
-			return fmt.Errorf("label too long: %s (maximum 63 characters per label)", label)
+			problems = append(problems, fmt.Errorf("label too long: %s (maximum 63 characters per label)", label))
 		}
 
 		// Labels cannot start or end with hyphen
 		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
-			return fmt.Errorf("label cannot start or end with hyphen: %s", label)
+			problems = append(problems, fmt.Errorf("label cannot start or end with hyphen: %s", label))
 		}
 
 		// Domain name (not TLD) cannot be all numeric
 		if i < len(labels)-1 && v.isAllNumeric(label) {
-			return fmt.Errorf("domain labels cannot be all numeric: %s", label)
+			problems = append(problems, fmt.Errorf("domain labels cannot be all numeric: %s", label))
 		}
 	}
 
-	return nil
+	return problems
 }
 
 // isAllNumeric checks if a string contains only numeric characters
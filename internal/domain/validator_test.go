@@ -108,6 +108,7 @@ func TestValidateDomain_InvalidDomains(t *testing.T) {
 		{"ex--ample.com", "consecutive hyphens not allowed"},
 		{"example.invalidtld", "unsupported TLD"},
 		{"example.xyz", "unsupported TLD"},
+		{"example.con", "unsupported TLD: .con (did you mean .com?)"},
 		{"123.com", "domain labels cannot be all numeric"},
 		{"456.789.com", "domain labels cannot be all numeric"},
 		{"example..com", "empty label in domain"},
@@ -516,3 +517,111 @@ func TestValidateDomain_ErrorUnwrapping(t *testing.T) {
 		t.Errorf("expected category %v, got %v", customErrors.CategoryValidation, baseErr.Category)
 	}
 }
+
+func TestNewDomainValidatorWithTLDs(t *testing.T) {
+	validator := NewDomainValidatorWithTLDs([]string{"dev", ".APP", " co.uk ", ""})
+
+	tests := []struct {
+		domain  string
+		wantErr bool
+	}{
+		{"example.dev", false},
+		{"example.app", false},
+		{"example.co.uk", false},
+		{"example.com", true},
+		{"example.uk", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			err := validator.ValidateDomain(tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDomain(%q) error = %v, wantErr %v", tt.domain, err, tt.wantErr)
+			}
+		})
+	}
+
+	if len(validator.GetSupportedTLDs()) != 3 {
+		t.Errorf("expected 3 normalized TLDs, got %v", validator.GetSupportedTLDs())
+	}
+}
+
+func TestValidateDomain_InternationalizedNames(t *testing.T) {
+	validator := NewDomainValidatorWithTLDs([]string{"de", "jp", "com", "xn--p1ai"})
+
+	tests := []struct {
+		domain  string
+		wantErr bool
+	}{
+		{"bücher.de", false},
+		{"xn--bcher-kva.de", false},
+		{"例え.jp", false},
+		{"пример.рф", false},
+		{"münchen.com", false},
+		{"bücher.fr", true},
+		{"bad--bücher.de", true},
+		{"aא.com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			err := validator.ValidateDomain(tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDomain(%q) error = %v, wantErr %v", tt.domain, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDomain_ReportsAllErrors(t *testing.T) {
+	validator := NewDomainValidator()
+
+	err := validator.ValidateDomain("-bad--name.123.invalidtld")
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+
+	var validationErrs *customErrors.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		t.Fatalf("expected ValidationErrors, got %T: %v", err, err)
+	}
+
+	expected := []string{
+		"label cannot start or end with hyphen: -bad--name",
+		"domain labels cannot be all numeric: 123",
+		"consecutive hyphens not allowed in domain",
+		"unsupported TLD: .invalidtld",
+	}
+	if len(validationErrs.Errors) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %v", len(expected), len(validationErrs.Errors), err)
+	}
+	for i, want := range expected {
+		if validationErrs.Errors[i].Message != want {
+			t.Errorf("error %d: expected %q, got %q", i, want, validationErrs.Errors[i].Message)
+		}
+	}
+
+	// The first individual error stays reachable and keeps the validation exit code
+	var first *customErrors.ValidationError
+	if !errors.As(err, &first) || first.Field != "format" {
+		t.Errorf("expected first error with field 'format', got %v", first)
+	}
+	if customErrors.GetExitCode(err) != customErrors.ExitValidation {
+		t.Errorf("expected validation exit code, got %d", customErrors.GetExitCode(err))
+	}
+}
+
+func TestValidateDomain_SingleErrorNotWrapped(t *testing.T) {
+	validator := NewDomainValidator()
+
+	err := validator.ValidateDomain("example.invalidtld")
+
+	var validationErr *customErrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %T", err)
+	}
+	var validationErrs *customErrors.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		t.Error("expected a single error not to be wrapped in ValidationErrors")
+	}
+}
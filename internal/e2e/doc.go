@@ -0,0 +1,4 @@
+// Package e2e holds end-to-end tests that build the r53check binary and run
+// it as a separate process with --mock, asserting on its stdout, stderr and
+// exit code. Run them with go test ./internal/e2e; -short skips them.
+package e2e
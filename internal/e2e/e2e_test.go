@@ -0,0 +1,233 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/pkg/r53checktest"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// binary is the r53check executable built by TestMain
+var binary string
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if testing.Short() {
+		fmt.Println("skipping end-to-end tests in short mode")
+		os.Exit(0)
+	}
+
+	dir, err := os.MkdirTemp("", "r53check-e2e")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create build directory: %v\n", err)
+		os.Exit(1)
+	}
+	binary = filepath.Join(dir, "r53check")
+
+	build := exec.Command("go", "build", "-o", binary, "github.com/abakermi/r53check")
+	if out, err := build.CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to build r53check: %v\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// result is the outcome of one run of the binary
+type result struct {
+	code   int
+	stdout string
+	stderr string
+}
+
+// run executes the binary with --mock and args, piping input to stdin, with
+// isolated storage directories and no AWS credentials
+func run(t *testing.T, input string, args ...string) result {
+	t.Helper()
+
+	cmd := exec.Command(binary, append([]string{"--mock"}, args...)...)
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + t.TempDir(),
+		"XDG_CONFIG_HOME=" + t.TempDir(),
+		"XDG_CACHE_HOME=" + t.TempDir(),
+		"XDG_DATA_HOME=" + t.TempDir(),
+		"LC_ALL=C",
+	}
+	cmd.Stdin = strings.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatalf("unable to run r53check: %v", err)
+	}
+	return result{code: cmd.ProcessState.ExitCode(), stdout: stdout.String(), stderr: stderr.String()}
+}
+
+func TestMockDomains(t *testing.T) {
+	// The scenarios below rely on the mock's answers for these names
+	expected := map[string]types.DomainAvailability{
+		"free.com":  types.DomainAvailabilityAvailable,
+		"taken.com": types.DomainAvailabilityUnavailable,
+	}
+	for domain, availability := range expected {
+		if got := r53checktest.HashedAvailability(domain); got != availability {
+			t.Errorf("expected the mock to report %s %s, got %s", domain, availability, got)
+		}
+	}
+}
+
+func TestCLI(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		args   []string
+		code   customErrors.ExitCode
+		stdout []string
+		stderr []string
+	}{
+		{
+			name:   "check available",
+			args:   []string{"check", "free.com"},
+			code:   customErrors.ExitSuccess,
+			stdout: []string{"free.com is AVAILABLE"},
+		},
+		{
+			name:   "check unavailable",
+			args:   []string{"check", "taken.com"},
+			code:   customErrors.ExitSuccess,
+			stdout: []string{"taken.com is UNAVAILABLE"},
+		},
+		{
+			name:   "check with fail-on",
+			args:   []string{"check", "taken.com", "--fail-on", "unavailable"},
+			code:   customErrors.ExitPartialFailure,
+			stdout: []string{"taken.com is UNAVAILABLE"},
+			stderr: []string{"--fail-on unavailable"},
+		},
+		{
+			name:   "check with pricing",
+			args:   []string{"--price", "check", "free.com"},
+			code:   customErrors.ExitSuccess,
+			stdout: []string{"free.com is AVAILABLE", "15.00"},
+		},
+		{
+			name:   "bulk arguments",
+			args:   []string{"bulk", "free.com", "taken.com"},
+			code:   customErrors.ExitSuccess,
+			stdout: []string{"✓ free.com: AVAILABLE", "✗ taken.com: UNAVAILABLE", "Available: 1", "Unavailable: 1"},
+		},
+		{
+			name:   "bulk from stdin",
+			input:  "free.com\n# comment\ntaken.com\n",
+			args:   []string{"bulk", "-"},
+			code:   customErrors.ExitSuccess,
+			stdout: []string{"Bulk Domain Check Results (2 domains)"},
+		},
+		{
+			name:   "bulk fail on unavailable",
+			args:   []string{"bulk", "free.com", "taken.com", "--fail-on-unavailable"},
+			code:   customErrors.ExitPartialFailure,
+			stdout: []string{"Summary:"},
+			stderr: []string{"1 of 2 domains are not available"},
+		},
+		{
+			name:   "invalid domain",
+			args:   []string{"check", "bad-.com"},
+			code:   customErrors.ExitValidation,
+			stderr: []string{"Domain Validation Error", "cannot start or end with hyphen"},
+		},
+		{
+			name:   "unsupported TLD",
+			args:   []string{"check", "example.notatld"},
+			code:   customErrors.ExitValidation,
+			stderr: []string{"Domain Validation Error"},
+		},
+		{
+			name:   "missing argument",
+			args:   []string{"check"},
+			code:   customErrors.ExitSystemError,
+			stderr: []string{"accepts 1 arg(s)"},
+		},
+		{
+			name:   "unknown command",
+			args:   []string{"bogus"},
+			code:   customErrors.ExitSystemError,
+			stderr: []string{`unknown command "bogus"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, tt.input, tt.args...)
+
+			if got.code != int(tt.code) {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.code, got.code, got.stderr)
+			}
+			for _, want := range tt.stdout {
+				if !strings.Contains(got.stdout, want) {
+					t.Errorf("expected stdout to contain %q, got %q", want, got.stdout)
+				}
+			}
+			for _, want := range tt.stderr {
+				if !strings.Contains(got.stderr, want) {
+					t.Errorf("expected stderr to contain %q, got %q", want, got.stderr)
+				}
+			}
+			if len(tt.stderr) == 0 && got.stderr != "" {
+				t.Errorf("expected nothing on stderr, got %q", got.stderr)
+			}
+		})
+	}
+}
+
+func TestCLI_JSON(t *testing.T) {
+	got := run(t, "", "-o", "json", "bulk", "free.com", "taken.com")
+	if got.code != int(customErrors.ExitSuccess) {
+		t.Fatalf("expected success, got exit code %d (stderr: %s)", got.code, got.stderr)
+	}
+
+	var output struct {
+		Results []struct {
+			Domain    string `json:"domain"`
+			Available bool   `json:"available"`
+			Status    string `json:"status"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(got.stdout), &output); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, got.stdout)
+	}
+	if len(output.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(output.Results))
+	}
+	for _, r := range output.Results {
+		if r.Available != (r.Domain == "free.com") {
+			t.Errorf("unexpected result for %s: %+v", r.Domain, r)
+		}
+	}
+
+	got = run(t, "", "-o", "json", "check", "bad-.com")
+	if got.code != int(customErrors.ExitValidation) {
+		t.Errorf("expected exit code %d, got %d", customErrors.ExitValidation, got.code)
+	}
+	if !json.Valid([]byte(strings.TrimSpace(got.stdout + got.stderr))) {
+		t.Errorf("expected the error as JSON, got stdout %q and stderr %q", got.stdout, got.stderr)
+	}
+}
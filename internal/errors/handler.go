@@ -19,6 +19,8 @@ const (
 	ExitAuthorization  ExitCode = 3 // Authorization error (insufficient permissions)
 	ExitAPIError       ExitCode = 4 // API error (AWS service error)
 	ExitSystemError    ExitCode = 5 // System error (unexpected error)
+	ExitPartialFailure ExitCode = 6 // Partial failure (checked domains matched a --fail-on condition)
+	ExitTimeout        ExitCode = 7 // Timeout (gave up waiting for AWS to respond)
 )
 
 // GetExitCode returns the appropriate exit code for an error
@@ -27,8 +29,19 @@ func GetExitCode(err error) ExitCode {
 		return ExitSuccess
 	}
 
-	// Check for context errors first
-	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+	// An explicit exit code always wins
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	// Check for timeouts and context errors first, so an API error that
+	// wraps a deadline still reports giving up rather than AWS failing
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) || errors.Is(err, context.DeadlineExceeded) {
+		return ExitTimeout
+	}
+	if errors.Is(err, context.Canceled) {
 		return ExitSystemError
 	}
 
@@ -180,6 +193,11 @@ func WrapAWSError(err error, service, operation string) error {
 		return nil
 	}
 
+	// A deadline means the request was abandoned, not answered
+	if errors.Is(err, context.DeadlineExceeded) {
+		return NewTimeoutError(operation, 0, err)
+	}
+
 	// Check for AWS API errors first
 	var apiErr smithy.APIError
 	if errors.As(err, &apiErr) {
@@ -261,32 +279,87 @@ func WrapSystemError(component string, err error) error {
 	return NewSystemError(component, err.Error(), err)
 }
 
-// IsRetryable determines if an error is retryable
+// IsRetryable determines if an error is retryable under the default retry
+// policy. Timeouts are not: a context deadline may be the user's own.
 func IsRetryable(err error) bool {
+	key := RetryKey(err)
+	return key != "" && key != RetryTimeout && defaultRetryBudgets[key] > 0
+}
+
+// IsThrottling reports whether an error means AWS rejected the request for
+// exceeding its rate limit
+func IsThrottling(err error) bool {
 	if err == nil {
 		return false
 	}
 
-	// Check for retryable custom error types
 	var apiErr *APIError
 	if errors.As(err, &apiErr) {
-		// Rate limiting and temporary service errors are retryable
-		return apiErr.StatusCode == 429 || apiErr.StatusCode == 503 || apiErr.StatusCode == 408
+		return apiErr.StatusCode == 429
 	}
 
-	// Check for AWS SDK retryable errors
 	var apiError smithy.APIError
 	if errors.As(err, &apiError) {
 		switch apiError.ErrorCode() {
-		case "TooManyRequests", "Throttling", "RequestLimitExceeded", "ServiceUnavailable", "InternalFailure", "RequestTimeout":
+		case "TooManyRequests", "Throttling", "RequestLimitExceeded":
 			return true
 		}
 	}
 
-	// Context timeout is not retryable (user-defined timeout)
-	if errors.Is(err, context.DeadlineExceeded) {
-		return false
+	return false
+}
+
+// GetCategory returns the category of the first categorized error in the
+// chain of err, or else the category matching its exit code. Like exit
+// codes, timeouts take precedence over the error they were wrapped in.
+func GetCategory(err error) ErrorCategory {
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) || errors.Is(err, context.DeadlineExceeded) {
+		return CategoryTimeout
 	}
 
-	return false
+	var categorized interface{ GetCategory() ErrorCategory }
+	if errors.As(err, &categorized) {
+		return categorized.GetCategory()
+	}
+
+	switch GetExitCode(err) {
+	case ExitValidation:
+		return CategoryValidation
+	case ExitAuthentication:
+		return CategoryAuthentication
+	case ExitAuthorization:
+		return CategoryAuthorization
+	case ExitAPIError:
+		return CategoryAPI
+	case ExitTimeout:
+		return CategoryTimeout
+	default:
+		return CategorySystem
+	}
+}
+
+// GetErrorCode returns the AWS error code in the chain of err, such as
+// "AccessDeniedException", or empty when err did not come from AWS
+func GetErrorCode(err error) string {
+	var apiError smithy.APIError
+	if errors.As(err, &apiError) {
+		return apiError.ErrorCode()
+	}
+	return ""
+}
+
+// GetRequestID returns the AWS request ID in the chain of err, or empty
+// when AWS did not return one
+func GetRequestID(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RequestID != "" {
+		return apiErr.RequestID
+	}
+
+	var requestErr interface{ ServiceRequestID() string }
+	if errors.As(err, &requestErr) {
+		return requestErr.ServiceRequestID()
+	}
+	return ""
 }
@@ -3,7 +3,9 @@ package errors
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
 	"github.com/aws/smithy-go"
@@ -53,7 +55,27 @@ func TestGetExitCode(t *testing.T) {
 		{
 			name:     "context deadline exceeded",
 			err:      context.DeadlineExceeded,
-			expected: ExitSystemError,
+			expected: ExitTimeout,
+		},
+		{
+			name:     "timeout error",
+			err:      NewTimeoutError("CheckDomainAvailability", 10*time.Second, nil),
+			expected: ExitTimeout,
+		},
+		{
+			name:     "API error wrapping a deadline",
+			err:      NewAPIError("route53domains", "CheckDomainAvailability", "AWS API call failed", context.DeadlineExceeded),
+			expected: ExitTimeout,
+		},
+		{
+			name:     "exit error overrides the wrapped error",
+			err:      NewExitError(ExitAPIError, NewValidationError("test.com", "format", "invalid format", nil)),
+			expected: ExitAPIError,
+		},
+		{
+			name:     "exit error without cause",
+			err:      NewExitError(ExitValidation, nil),
+			expected: ExitValidation,
 		},
 	}
 
@@ -258,6 +280,13 @@ func TestWrapAWSError(t *testing.T) {
 			operation: "CheckDomainAvailability",
 			expected:  CategoryAPI,
 		},
+		{
+			name:      "request deadline",
+			err:       fmt.Errorf("operation error: %w", context.DeadlineExceeded),
+			service:   "route53domains",
+			operation: "CheckDomainAvailability",
+			expected:  CategoryTimeout,
+		},
 	}
 
 	for _, tt := range tests {
@@ -457,6 +486,71 @@ func TestIsRetryable(t *testing.T) {
 	}
 }
 
+func TestIsThrottling(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil error", nil, false},
+		{"API error with 429 status", NewAPIError("route53domains", "CheckDomainAvailability", "rate limited", nil).WithStatusCode(429), true},
+		{"API error with 503 status", NewAPIError("route53domains", "CheckDomainAvailability", "service unavailable", nil).WithStatusCode(503), false},
+		{"AWS throttling error", &smithy.GenericAPIError{Code: "Throttling", Message: "rate exceeded"}, true},
+		{"AWS service unavailable", &smithy.GenericAPIError{Code: "ServiceUnavailable", Message: "unavailable"}, false},
+		{"plain error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := IsThrottling(tt.err); result != tt.expected {
+				t.Errorf("IsThrottling(%v) = %v, want %v", tt.err, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected ErrorCategory
+	}{
+		{"validation error", NewValidationError("test.com", "format", "invalid", nil), CategoryValidation},
+		{"authentication error", NewAuthenticationError("aws-sdk", "no credentials", nil), CategoryAuthentication},
+		{"API error behind an exit code", NewExitError(ExitAPIError, NewAPIError("route53domains", "CheckDomainAvailability", "timed out", nil)), CategoryAPI},
+		{"AWS access denied", &smithy.GenericAPIError{Code: "AccessDenied"}, CategoryAuthorization},
+		{"plain error", errors.New("boom"), CategorySystem},
+		{"API error wrapping a deadline", NewAPIError("route53domains", "CheckDomainAvailability", "failed", context.DeadlineExceeded), CategoryTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetCategory(tt.err); got != tt.expected {
+				t.Errorf("GetCategory(%v) = %s, want %s", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetErrorCodeAndRequestID(t *testing.T) {
+	awsErr := &smithy.GenericAPIError{Code: "NoCredentialsErr", Message: "no credentials"}
+	wrapped := NewAuthenticationError("aws-sdk", "no credentials", awsErr)
+	if code := GetErrorCode(wrapped); code != "NoCredentialsErr" {
+		t.Errorf("Expected the AWS error code, got %q", code)
+	}
+	if code := GetErrorCode(errors.New("boom")); code != "" {
+		t.Errorf("Expected no code for a plain error, got %q", code)
+	}
+
+	apiErr := NewAPIError("route53domains", "CheckDomainAvailability", "failed", awsErr).WithRequestID("req-123")
+	if id := GetRequestID(apiErr); id != "req-123" {
+		t.Errorf("Expected the request ID, got %q", id)
+	}
+	if id := GetRequestID(wrapped); id != "" {
+		t.Errorf("Expected no request ID, got %q", id)
+	}
+}
+
 // Helper function for creating string pointers
 func stringPtr(s string) *string {
 	return &s
@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/smithy-go"
+)
+
+// RetryTimeout is the retry policy key for checks that exceeded their
+// per-request timeout
+const RetryTimeout = "timeout"
+
+// defaultRetryBudgets retries throttling, temporary service errors and
+// timeouts once each
+var defaultRetryBudgets = map[string]int{
+	"429":        1,
+	"503":        1,
+	"408":        1,
+	RetryTimeout: 1,
+}
+
+// RetryPolicy sets how many times each kind of failure may be retried.
+// Failures are keyed by RetryKey: the HTTP status code of the error, or
+// "timeout".
+type RetryPolicy struct {
+	budgets map[string]int
+}
+
+// DefaultRetryPolicy returns the policy that retries throttling (429),
+// unavailable service (503), request timeout (408) and per-request timeout
+// failures once
+func DefaultRetryPolicy() *RetryPolicy {
+	policy := &RetryPolicy{budgets: make(map[string]int, len(defaultRetryBudgets))}
+	for key, budget := range defaultRetryBudgets {
+		policy.budgets[key] = budget
+	}
+	return policy
+}
+
+// NewRetryPolicy returns the default policy with budgets overriding the
+// retries of the failures they name. Keys are HTTP status codes such as
+// "429" or "timeout"; a budget of 0 disables retries for that failure.
+func NewRetryPolicy(budgets map[string]int) (*RetryPolicy, error) {
+	policy := DefaultRetryPolicy()
+	for key, budget := range budgets {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key != RetryTimeout {
+			code, err := strconv.Atoi(key)
+			if err != nil || code < 400 || code > 599 {
+				return nil, NewValidationError("", "retry", fmt.Sprintf("invalid retry budget key %q; use an HTTP status code or %q", key, RetryTimeout), nil)
+			}
+		}
+		if budget < 0 {
+			return nil, NewValidationError("", "retry", fmt.Sprintf("retry budget for %s must not be negative", key), nil)
+		}
+		policy.budgets[key] = budget
+	}
+	return policy, nil
+}
+
+// Budget returns how many times a check that failed with err may be
+// retried, 0 for failures that are not retryable
+func (p *RetryPolicy) Budget(err error) int {
+	key := RetryKey(err)
+	if key == "" {
+		return 0
+	}
+	return p.budgets[key]
+}
+
+// RetryKey classifies err for a retry policy: the HTTP status code of an
+// API error as a string, RetryTimeout for an exceeded deadline, or empty
+// when err carries neither
+func RetryKey(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode != 0 {
+		return strconv.Itoa(apiErr.StatusCode)
+	}
+
+	// AWS SDK errors that were not wrapped, keyed like WrapAWSError would
+	var apiError smithy.APIError
+	if errors.As(err, &apiError) {
+		switch apiError.ErrorCode() {
+		case "TooManyRequests", "Throttling", "RequestLimitExceeded":
+			return "429"
+		case "ServiceUnavailable", "InternalFailure":
+			return "503"
+		case "RequestTimeout":
+			return "408"
+		}
+	}
+
+	var statusErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &statusErr) && statusErr.HTTPStatusCode() != 0 {
+		return strconv.Itoa(statusErr.HTTPStatusCode())
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return RetryTimeout
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestRetryKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"nil error", nil, ""},
+		{"API error with status", NewAPIError("route53domains", "CheckDomainAvailability", "rate limited", nil).WithStatusCode(429), "429"},
+		{"API error without status", NewAPIError("route53domains", "CheckDomainAvailability", "failed", nil), ""},
+		{"AWS throttling error", &smithy.GenericAPIError{Code: "Throttling"}, "429"},
+		{"AWS internal failure", &smithy.GenericAPIError{Code: "InternalFailure"}, "503"},
+		{"AWS request timeout", &smithy.GenericAPIError{Code: "RequestTimeout"}, "408"},
+		{"wrapped deadline", fmt.Errorf("check: %w", context.DeadlineExceeded), RetryTimeout},
+		{"validation error", NewValidationError("test.com", "format", "invalid", nil), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RetryKey(tt.err); got != tt.expected {
+				t.Errorf("RetryKey(%v) = %q, want %q", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewRetryPolicy(t *testing.T) {
+	throttled := NewAPIError("route53domains", "CheckDomainAvailability", "rate limited", nil).WithStatusCode(429)
+	unavailable := NewAPIError("route53domains", "CheckDomainAvailability", "unavailable", nil).WithStatusCode(503)
+	internal := NewAPIError("route53domains", "CheckDomainAvailability", "internal", nil).WithStatusCode(500)
+	denied := NewAuthorizationError("CheckDomainAvailability", "route53domains", "denied", nil)
+
+	tests := []struct {
+		name     string
+		budgets  map[string]int
+		err      error
+		expected int
+		wantErr  bool
+	}{
+		{"default", nil, throttled, 1, false},
+		{"override", map[string]int{"429": 5}, throttled, 5, false},
+		{"other keys keep their default", map[string]int{"429": 5}, unavailable, 1, false},
+		{"disabled", map[string]int{"503": 0}, unavailable, 0, false},
+		{"new status code", map[string]int{"500": 2}, internal, 2, false},
+		{"timeout", map[string]int{"Timeout": 3}, context.DeadlineExceeded, 3, false},
+		{"not retryable", map[string]int{"429": 5}, denied, 0, false},
+		{"unknown key", map[string]int{"throttling": 5}, nil, 0, true},
+		{"not an error status", map[string]int{"200": 1}, nil, 0, true},
+		{"negative budget", map[string]int{"429": -1}, nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewRetryPolicy(tt.budgets)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewRetryPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := policy.Budget(tt.err); got != tt.expected {
+				t.Errorf("Budget(%v) = %d, want %d", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxSuggestionDistance is the largest edit distance at which a choice is
+// still suggested for a mistyped value
+const maxSuggestionDistance = 2
+
+// Suggest returns the choice closest to value by Levenshtein distance,
+// ignoring case, or "" when none is close enough to be a likely typo.
+// Short values only match choices one edit away, so "io" does not suggest
+// every other two-letter choice. Of equally close choices the one nearest
+// in length wins, so "con" suggests "com" rather than "co", and then the
+// one listed first.
+func Suggest(value string, choices []string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return ""
+	}
+
+	length := len([]rune(value))
+	limit := min(maxSuggestionDistance, max(length/2, 1))
+	best, bestDistance, bestLengthDiff := "", limit+1, 0
+	for _, choice := range choices {
+		distance := levenshtein(value, strings.ToLower(choice))
+		lengthDiff := abs(len([]rune(choice)) - length)
+		if distance == 0 || distance > bestDistance || (distance == bestDistance && lengthDiff >= bestLengthDiff) {
+			continue
+		}
+		best, bestDistance, bestLengthDiff = choice, distance, lengthDiff
+	}
+	return best
+}
+
+// DidYouMean returns a hint such as ` (did you mean "json"?)` to append to
+// the error message for a mistyped value, or "" when no choice is close
+func DidYouMean(value string, choices []string) string {
+	if suggestion := Suggest(value, choices); suggestion != "" {
+		return fmt.Sprintf(" (did you mean %q?)", suggestion)
+	}
+	return ""
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// levenshtein returns the number of single-character insertions, deletions
+// and substitutions needed to turn a into b
+func levenshtein(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
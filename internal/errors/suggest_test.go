@@ -0,0 +1,61 @@
+package errors
+
+import "testing"
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		choices  []string
+		expected string
+	}{
+		{"missing letter", "jsn", []string{"text", "json"}, "json"},
+		{"ignores case", "JSNO", []string{"text", "json"}, "json"},
+		{"transposed letters", "compelted", []string{"input", "completed", "alpha"}, "completed"},
+		{"closest wins", "comm", []string{"co", "com"}, "com"},
+		{"nearest length wins", "con", []string{"cn", "co", "com"}, "com"},
+		{"first of equals wins", "cx", []string{"cn", "co"}, "cn"},
+		{"exact match", "json", []string{"text", "json"}, ""},
+		{"too far", "yaml", []string{"text", "json"}, ""},
+		{"short values allow one edit", "xy", []string{"io", "xyz"}, "xyz"},
+		{"short values reject two edits", "io", []string{"ai"}, ""},
+		{"empty value", "", []string{"text"}, ""},
+		{"no choices", "jsn", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Suggest(tt.value, tt.choices); got != tt.expected {
+				t.Errorf("Suggest(%q, %v) = %q, want %q", tt.value, tt.choices, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	if got := DidYouMean("jsn", []string{"text", "json"}); got != ` (did you mean "json"?)` {
+		t.Errorf("Unexpected hint %q", got)
+	}
+	if got := DidYouMean("yaml", []string{"text", "json"}); got != "" {
+		t.Errorf("Expected no hint, got %q", got)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"café", "cafe", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.expected {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
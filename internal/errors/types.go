@@ -2,6 +2,8 @@ package errors
 
 import (
 	"fmt"
+	"strings"
+	"time"
 )
 
 // ErrorCategory represents different categories of errors
@@ -13,6 +15,7 @@ const (
 	CategoryAuthorization  ErrorCategory = "AUTHORIZATION"
 	CategoryAPI            ErrorCategory = "API"
 	CategorySystem         ErrorCategory = "SYSTEM"
+	CategoryTimeout        ErrorCategory = "TIMEOUT"
 )
 
 // BaseError provides common functionality for all custom errors
@@ -79,6 +82,45 @@ func (e *ValidationError) Error() string {
 	return baseMsg
 }
 
+// ValidationErrors collects every validation rule a domain violated
+type ValidationErrors struct {
+	*BaseError
+	Domain string
+	Errors []*ValidationError
+}
+
+func NewValidationErrors(domain string, errs []*ValidationError) *ValidationErrors {
+	return &ValidationErrors{
+		BaseError: &BaseError{
+			Category: CategoryValidation,
+			Message:  fmt.Sprintf("%d validation errors", len(errs)),
+			Context: map[string]interface{}{
+				"domain": domain,
+				"count":  len(errs),
+			},
+		},
+		Domain: domain,
+		Errors: errs,
+	}
+}
+
+func (e *ValidationErrors) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Message
+	}
+	return fmt.Sprintf("domain validation failed for '%s': %s", e.Domain, strings.Join(messages, "; "))
+}
+
+// Unwrap exposes the individual errors so errors.As finds the first ValidationError
+func (e *ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
 // AuthenticationError represents AWS credential issues
 type AuthenticationError struct {
 	*BaseError
@@ -180,6 +222,41 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("AWS %s API error in %s: %s", e.Service, e.Operation, e.Message)
 }
 
+// TimeoutError represents an operation that was abandoned because it took
+// longer than allowed, as opposed to one AWS rejected
+type TimeoutError struct {
+	*BaseError
+	Operation string
+	Timeout   time.Duration
+}
+
+func NewTimeoutError(operation string, timeout time.Duration, cause error) *TimeoutError {
+	return &TimeoutError{
+		BaseError: &BaseError{
+			Category: CategoryTimeout,
+			Message:  "gave up waiting for a response",
+			Cause:    cause,
+			Context: map[string]interface{}{
+				"operation": operation,
+				"timeout":   timeout.String(),
+			},
+		},
+		Operation: operation,
+		Timeout:   timeout,
+	}
+}
+
+func (e *TimeoutError) Error() string {
+	msg := fmt.Sprintf("%s timed out", e.Operation)
+	if e.Timeout > 0 {
+		msg = fmt.Sprintf("%s after %v", msg, e.Timeout)
+	}
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %s", msg, e.Cause.Error())
+	}
+	return msg
+}
+
 // SystemError represents unexpected system errors
 type SystemError struct {
 	*BaseError
@@ -206,3 +283,28 @@ func (e *SystemError) Error() string {
 	}
 	return fmt.Sprintf("system error: %s", e.Message)
 }
+
+// ExitError carries the exit code a command wants the process to end with.
+// A nil Err means the command has already reported the failure itself.
+type ExitError struct {
+	Code ExitCode
+	Err  error
+}
+
+func NewExitError(code ExitCode, err error) *ExitError {
+	return &ExitError{
+		Code: code,
+		Err:  err,
+	}
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
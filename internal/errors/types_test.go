@@ -1,8 +1,10 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 )
 
 func TestValidationError(t *testing.T) {
@@ -276,6 +278,44 @@ func TestSystemError(t *testing.T) {
 	}
 }
 
+func TestTimeoutError(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation string
+		timeout   time.Duration
+		cause     error
+		expected  string
+	}{
+		{
+			name:      "timeout with duration",
+			operation: "domain check",
+			timeout:   10 * time.Second,
+			cause:     nil,
+			expected:  "domain check timed out after 10s",
+		},
+		{
+			name:      "timeout with cause",
+			operation: "CheckDomainAvailability",
+			cause:     context.DeadlineExceeded,
+			expected:  "CheckDomainAvailability timed out: context deadline exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewTimeoutError(tt.operation, tt.timeout, tt.cause)
+
+			if err.Error() != tt.expected {
+				t.Errorf("TimeoutError.Error() = %v, want %v", err.Error(), tt.expected)
+			}
+
+			if err.GetCategory() != CategoryTimeout {
+				t.Errorf("TimeoutError.GetCategory() = %v, want %v", err.GetCategory(), CategoryTimeout)
+			}
+		})
+	}
+}
+
 func TestBaseErrorUnwrap(t *testing.T) {
 	cause := errors.New("underlying error")
 	err := NewValidationError("test.com", "format", "invalid", cause)
@@ -289,3 +329,28 @@ func TestBaseErrorUnwrap(t *testing.T) {
 		t.Errorf("BaseError.Unwrap() should return the cause error")
 	}
 }
+
+func TestValidationErrors(t *testing.T) {
+	first := NewValidationError("bad.x", "format", "first problem", nil)
+	second := NewValidationError("bad.x", "tld", "second problem", nil)
+	err := NewValidationErrors("bad.x", []*ValidationError{first, second})
+
+	expected := "domain validation failed for 'bad.x': first problem; second problem"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+	if err.GetCategory() != CategoryValidation {
+		t.Errorf("expected category %v, got %v", CategoryValidation, err.GetCategory())
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr != first {
+		t.Error("expected errors.As to find the first ValidationError")
+	}
+	if !errors.Is(err, second) {
+		t.Error("expected errors.Is to find the second ValidationError")
+	}
+	if GetExitCode(err) != ExitValidation {
+		t.Errorf("expected exit code %d, got %d", ExitValidation, GetExitCode(err))
+	}
+}
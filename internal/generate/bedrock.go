@@ -0,0 +1,104 @@
+package generate
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+// maxTokensPerName bounds the response length the model is allowed per
+// requested name
+const maxTokensPerName = 16
+
+// systemPrompt keeps the model's reply to a plain list that parseNames reads
+const systemPrompt = "You name companies and products. Reply with one name per line and nothing " +
+	"else: no numbering, explanations or TLDs. Names are short, brandable, easy to spell, and " +
+	"use only letters, digits and hyphens."
+
+// listMarker matches the numbering or bullet at the start of a list item
+var listMarker = regexp.MustCompile(`^\s*(?:\d+[.)]|[-*•])?\s*`)
+
+// BedrockAPI is the part of the Bedrock runtime client used to suggest names
+type BedrockAPI interface {
+	Converse(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error)
+}
+
+// BedrockSuggester asks a model on Amazon Bedrock for names matching a
+// description
+type BedrockSuggester struct {
+	api     BedrockAPI
+	modelID string
+}
+
+// NewBedrockSuggester creates a suggester calling modelID, such as
+// "anthropic.claude-3-haiku-20240307-v1:0", with api, usually
+// bedrockruntime.NewFromConfig(cfg)
+func NewBedrockSuggester(api BedrockAPI, modelID string) *BedrockSuggester {
+	return &BedrockSuggester{api: api, modelID: modelID}
+}
+
+// Suggest returns up to count names for what description describes, as
+// labels without TLD. Replies that are not valid labels are left out.
+func (s *BedrockSuggester) Suggest(ctx context.Context, description string, count int) ([]string, error) {
+	prompt := fmt.Sprintf("Suggest %d names for: %s", count, description)
+	out, err := s.api.Converse(ctx, &bedrockruntime.ConverseInput{
+		ModelId: aws.String(s.modelID),
+		System:  []types.SystemContentBlock{&types.SystemContentBlockMemberText{Value: systemPrompt}},
+		Messages: []types.Message{{
+			Role:    types.ConversationRoleUser,
+			Content: []types.ContentBlock{&types.ContentBlockMemberText{Value: prompt}},
+		}},
+		InferenceConfig: &types.InferenceConfiguration{MaxTokens: aws.Int32(int32(count * maxTokensPerName))},
+	})
+	if err != nil {
+		return nil, customErrors.WrapAWSError(err, "bedrock", "Converse")
+	}
+
+	message, ok := out.Output.(*types.ConverseOutputMemberMessage)
+	if !ok {
+		return nil, customErrors.NewSystemError("bedrock", "the model replied without a message", nil)
+	}
+	var reply strings.Builder
+	for _, block := range message.Value.Content {
+		if text, ok := block.(*types.ContentBlockMemberText); ok {
+			reply.WriteString(text.Value)
+			reply.WriteString("\n")
+		}
+	}
+
+	names := parseNames(reply.String())
+	if len(names) > count {
+		names = names[:count]
+	}
+	return names, nil
+}
+
+// parseNames reads one name per line of a model's reply, tolerating the
+// numbering, bullets, TLDs and explanations models add anyway
+func parseNames(reply string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(reply, "\n") {
+		line = listMarker.ReplaceAllString(line, "")
+		if strings.HasSuffix(strings.TrimSpace(line), ":") {
+			// An introduction such as "Here are some names:"
+			continue
+		}
+		name, _, _ := strings.Cut(strings.ToLower(line), " ")
+		name, _, _ = strings.Cut(name, ".")
+		name = strings.Trim(name, "*_`\"'():,")
+		if name == "" || seen[name] || len(name) > maxLabelLength || !labelPattern.MatchString(name) {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
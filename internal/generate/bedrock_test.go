@@ -0,0 +1,95 @@
+package generate
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/aws/smithy-go"
+)
+
+// fakeBedrock replies to every Converse call with reply, or fails with err
+type fakeBedrock struct {
+	reply string
+	err   error
+	input *bedrockruntime.ConverseInput
+}
+
+func (f *fakeBedrock) Converse(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+	f.input = params
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &bedrockruntime.ConverseOutput{
+		Output: &types.ConverseOutputMemberMessage{Value: types.Message{
+			Role:    types.ConversationRoleAssistant,
+			Content: []types.ContentBlock{&types.ContentBlockMemberText{Value: f.reply}},
+		}},
+	}, nil
+}
+
+func TestBedrockSuggester_Suggest(t *testing.T) {
+	api := &fakeBedrock{reply: "Pipewise\nShipLens\nPipewise\n"}
+	suggester := NewBedrockSuggester(api, "test-model")
+
+	names, err := suggester.Suggest(context.Background(), "a CI/CD analytics startup", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"pipewise", "shiplens"}) {
+		t.Errorf("expected [pipewise shiplens], got %v", names)
+	}
+	if *api.input.ModelId != "test-model" {
+		t.Errorf("expected model test-model, got %s", *api.input.ModelId)
+	}
+	prompt := api.input.Messages[0].Content[0].(*types.ContentBlockMemberText).Value
+	if !strings.Contains(prompt, "5 names") || !strings.Contains(prompt, "a CI/CD analytics startup") {
+		t.Errorf("expected the count and description in the prompt, got %q", prompt)
+	}
+
+	names, err = suggester.Suggest(context.Background(), "anything", 1)
+	if err != nil || len(names) != 1 {
+		t.Errorf("expected the reply cut to 1 name, got %v, %v", names, err)
+	}
+}
+
+func TestBedrockSuggester_Error(t *testing.T) {
+	api := &fakeBedrock{err: &smithy.GenericAPIError{Code: "AccessDenied", Message: "not allowed"}}
+	suggester := NewBedrockSuggester(api, "test-model")
+
+	_, err := suggester.Suggest(context.Background(), "anything", 5)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if category := customErrors.GetCategory(err); category != customErrors.CategoryAuthorization {
+		t.Errorf("expected an authorization error, got %s: %v", category, err)
+	}
+}
+
+func TestParseNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    string
+		expected []string
+	}{
+		{"plain lines", "Pipewise\nbuildlens\n", []string{"pipewise", "buildlens"}},
+		{"numbering and bullets", "1. Pipewise\n2) BuildLens\n- ShipIQ\n* **Deployr**\n", []string{"pipewise", "buildlens", "shipiq", "deployr"}},
+		{"explanations and TLDs", "Pipewise.io - pipelines made wise\nBuildLens: insight into builds\n", []string{"pipewise", "buildlens"}},
+		{"leading digits kept", "99builds\n", []string{"99builds"}},
+		{"chatter left out", "Here are some names:\n\nPipewise\n", []string{"pipewise"}},
+		{"invalid labels", "pipe_wise\n!pipe\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNames(tt.reply); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseNames() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package generate
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Limits of the pronounceability heuristic
+const (
+	maxConsonantRun = 3 // As in "str"
+	maxVowelRun     = 3 // As in "eau"
+)
+
+// Constraints narrow generated names to ones that make realistic brand
+// names. They apply to the label under the TLD; the zero value allows every
+// name.
+type Constraints struct {
+	MaxLength     int  // Longest label in characters, 0 for no limit
+	LetterFirst   bool // The label starts with a letter, not a digit
+	NoHyphens     bool // The label has no hyphens
+	Pronounceable bool // The label passes Pronounceable
+}
+
+// Allows reports whether name meets the constraints
+func (c Constraints) Allows(name string) bool {
+	label, _ := split(name)
+	runes := []rune(label)
+	switch {
+	case len(runes) == 0:
+		return false
+	case c.MaxLength > 0 && len(runes) > c.MaxLength:
+		return false
+	case c.LetterFirst && !unicode.IsLetter(runes[0]):
+		return false
+	case c.NoHyphens && strings.Contains(label, "-"):
+		return false
+	case c.Pronounceable && !Pronounceable(label):
+		return false
+	}
+	return true
+}
+
+// Filter returns the names that meet the constraints, in order
+func (c Constraints) Filter(names []string) []string {
+	var allowed []string
+	for _, name := range names {
+		if c.Allows(name) {
+			allowed = append(allowed, name)
+		}
+	}
+	return allowed
+}
+
+// Pronounceable reports whether a Latin label reads as a word: it has a
+// vowel, at most three consonants or vowels in a row and no letter three
+// times in a row. Digits and hyphens separate words. Labels in other
+// scripts are not judged and always pass.
+func Pronounceable(label string) bool {
+	for _, word := range strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		if !pronounceableWord(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// pronounceableWord applies Pronounceable to a run of letters
+func pronounceableWord(word string) bool {
+	var vowels, consonants, repeats int
+	var previous rune
+	hasVowel := false
+	for _, r := range word {
+		if r > unicode.MaxASCII {
+			return true
+		}
+
+		if r == previous {
+			repeats++
+		} else {
+			repeats = 1
+		}
+		previous = r
+
+		// y sounds as a vowel in most brand names, as in "lyft" or "fly"
+		if strings.ContainsRune("aeiouy", r) {
+			hasVowel = true
+			vowels++
+			consonants = 0
+		} else {
+			consonants++
+			vowels = 0
+		}
+		if repeats >= 3 || vowels > maxVowelRun || consonants > maxConsonantRun {
+			return false
+		}
+	}
+	return hasVowel
+}
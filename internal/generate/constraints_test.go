@@ -0,0 +1,62 @@
+package generate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPronounceable(t *testing.T) {
+	tests := []struct {
+		label    string
+		expected bool
+	}{
+		{"example", true},
+		{"strive", true},
+		{"lyft", true},
+		{"beautiful", true},
+		{"get-example", true},
+		{"example24", true},
+		{"bücher", true},
+		{"пример", true},
+		{"xkcd", false},
+		{"bcdfg", false},
+		{"rhythms", false},
+		{"aaargh", false},
+		{"queueing", false},
+		{"get-xkcd", false},
+		{"42", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			if got := Pronounceable(tt.label); got != tt.expected {
+				t.Errorf("Pronounceable(%q) = %v, want %v", tt.label, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConstraints_Filter(t *testing.T) {
+	names := []string{"example.com", "getexample.co.uk", "example-app.io", "4example.com", "xkcd.com"}
+
+	tests := []struct {
+		name        string
+		constraints Constraints
+		expected    []string
+	}{
+		{"none", Constraints{}, names},
+		{"max length", Constraints{MaxLength: 7}, []string{"example.com", "xkcd.com"}},
+		{"letter first", Constraints{LetterFirst: true}, []string{"example.com", "getexample.co.uk", "example-app.io", "xkcd.com"}},
+		{"no hyphens", Constraints{NoHyphens: true}, []string{"example.com", "getexample.co.uk", "4example.com", "xkcd.com"}},
+		{"pronounceable", Constraints{Pronounceable: true}, []string{"example.com", "getexample.co.uk", "example-app.io", "4example.com"}},
+		{"all", Constraints{MaxLength: 10, LetterFirst: true, NoHyphens: true, Pronounceable: true}, []string{"example.com", "getexample.co.uk"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.constraints.Filter(names); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Filter() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package generate produces candidate domain names from a base name by
+// adding prefixes and suffixes, such as getexample.com or example-app.io, for
+// finding an available variant of a name that is taken.
+package generate
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+// DefaultTLD is the TLD of base names given without one
+const DefaultTLD = "com"
+
+// maxLabelLength is the longest label DNS allows
+const maxLabelLength = 63
+
+// DefaultPrefixes and DefaultSuffixes are the affixes used when none are
+// configured
+var (
+	DefaultPrefixes = []string{"get", "try", "use", "my", "go", "the"}
+	DefaultSuffixes = []string{"app", "hq", "hub", "labs", "now", "online"}
+)
+
+// labelPattern matches a label of letters, digits and inner hyphens
+var labelPattern = regexp.MustCompile(`^[\p{L}\p{N}](?:[\p{L}\p{N}-]*[\p{L}\p{N}])?$`)
+
+// Options sets the variants Names produces
+type Options struct {
+	Prefixes []string // Added before the name, as in getexample
+	Suffixes []string // Added after the name, as in examplehq
+
+	// Hyphens also joins each affix with a hyphen, as in example-app
+	Hyphens bool
+}
+
+// Names returns the base name followed by its variants with each prefix and
+// suffix, under the same TLD. base is a domain such as "example.io", or a
+// name without TLD, which gets DefaultTLD. Repeated names and names that are
+// not valid labels, such as ones over 63 characters, are left out.
+func Names(base string, opts Options) []string {
+	label, tld := split(base)
+	if label == "" {
+		return nil
+	}
+
+	candidates := []string{label}
+	for _, prefix := range clean(opts.Prefixes) {
+		candidates = append(candidates, prefix+label)
+		if opts.Hyphens {
+			candidates = append(candidates, prefix+"-"+label)
+		}
+	}
+	for _, suffix := range clean(opts.Suffixes) {
+		candidates = append(candidates, label+suffix)
+		if opts.Hyphens {
+			candidates = append(candidates, label+"-"+suffix)
+		}
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if seen[candidate] || len(candidate) > maxLabelLength || !labelPattern.MatchString(candidate) {
+			continue
+		}
+		seen[candidate] = true
+		names = append(names, candidate+"."+tld)
+	}
+	return names
+}
+
+// split returns the registrable label and TLD of base, reducing URLs and
+// subdomains like the bulk checker does
+func split(base string) (label, tld string) {
+	name, _ := domain.NormalizeDomain(base)
+	name = strings.ToLower(name)
+	if !strings.Contains(name, ".") {
+		return name, DefaultTLD
+	}
+	tld = domain.EffectiveTLD(name)
+	return strings.TrimSuffix(name, "."+tld), tld
+}
+
+// clean lowercases affixes, dropping empty ones and surrounding hyphens
+func clean(affixes []string) []string {
+	var cleaned []string
+	for _, affix := range affixes {
+		if affix = strings.Trim(strings.ToLower(strings.TrimSpace(affix)), "-"); affix != "" {
+			cleaned = append(cleaned, affix)
+		}
+	}
+	return cleaned
+}
@@ -0,0 +1,67 @@
+package generate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		opts     Options
+		expected []string
+	}{
+		{
+			name:     "prefixes and suffixes",
+			base:     "example.com",
+			opts:     Options{Prefixes: []string{"get"}, Suffixes: []string{"hq", "app"}},
+			expected: []string{"example.com", "getexample.com", "examplehq.com", "exampleapp.com"},
+		},
+		{
+			name:     "hyphens",
+			base:     "example.io",
+			opts:     Options{Prefixes: []string{"try"}, Suffixes: []string{"app"}, Hyphens: true},
+			expected: []string{"example.io", "tryexample.io", "try-example.io", "exampleapp.io", "example-app.io"},
+		},
+		{
+			name:     "name without TLD",
+			base:     "Example",
+			opts:     Options{Suffixes: []string{"HQ"}},
+			expected: []string{"example.com", "examplehq.com"},
+		},
+		{
+			name:     "subdomain and multi-label TLD",
+			base:     "https://www.example.co.uk/",
+			opts:     Options{Prefixes: []string{"my"}},
+			expected: []string{"example.co.uk", "myexample.co.uk"},
+		},
+		{
+			name:     "empty, hyphenated and repeated affixes",
+			base:     "example.com",
+			opts:     Options{Prefixes: []string{"", "-get-"}, Suffixes: []string{"hq", "hq", "bad_affix"}},
+			expected: []string{"example.com", "getexample.com", "examplehq.com"},
+		},
+		{
+			name:     "too long",
+			base:     strings.Repeat("a", 62) + ".com",
+			opts:     Options{Prefixes: []string{"get"}, Suffixes: []string{"s"}},
+			expected: []string{strings.Repeat("a", 62) + ".com", strings.Repeat("a", 62) + "s.com"},
+		},
+		{
+			name:     "empty base",
+			base:     "",
+			opts:     Options{Prefixes: DefaultPrefixes},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Names(tt.base, tt.opts); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Names(%q) = %v, want %v", tt.base, got, tt.expected)
+			}
+		})
+	}
+}
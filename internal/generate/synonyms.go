@@ -0,0 +1,115 @@
+package generate
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"strings"
+)
+
+//go:embed thesaurus.txt
+var defaultThesaurus string
+
+// Thesaurus looks up synonyms of words, from groups of words that mean the
+// same
+type Thesaurus struct {
+	groups map[string][]string
+}
+
+// DefaultThesaurus returns the thesaurus built into the binary
+func DefaultThesaurus() *Thesaurus {
+	thesaurus, _ := LoadThesaurus(strings.NewReader(defaultThesaurus))
+	return thesaurus
+}
+
+// LoadThesaurus reads a thesaurus of one synonym group per line, with the
+// words separated by commas. Blank lines and lines starting with # are
+// ignored.
+func LoadThesaurus(r io.Reader) (*Thesaurus, error) {
+	t := &Thesaurus{groups: make(map[string][]string)}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var group []string
+		for _, word := range strings.Split(line, ",") {
+			if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+				group = append(group, word)
+			}
+		}
+		for _, word := range group {
+			t.groups[word] = append(t.groups[word], group...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Synonyms returns word followed by its synonyms, without repeats. A word
+// the thesaurus does not know is returned alone.
+func (t *Thesaurus) Synonyms(word string) []string {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if word == "" {
+		return nil
+	}
+
+	synonyms := []string{word}
+	seen := map[string]bool{word: true}
+	for _, synonym := range t.groups[word] {
+		if !seen[synonym] {
+			seen[synonym] = true
+			synonyms = append(synonyms, synonym)
+		}
+	}
+	return synonyms
+}
+
+// Combine returns every name made of one word from each group, in group
+// order, under each TLD, such as quickcar.com for [[fast quick] [car]]. The
+// words are joined with each of separators, such as "" and "-" for both
+// quickcar and quick-car; no separators joins them directly. Names that are
+// not valid labels are left out.
+func Combine(groups [][]string, separators, tlds []string) []string {
+	if len(groups) == 0 {
+		return nil
+	}
+	if len(separators) == 0 {
+		separators = []string{""}
+	}
+
+	combinations := [][]string{nil}
+	for _, group := range groups {
+		var next [][]string
+		for _, combination := range combinations {
+			for _, word := range group {
+				next = append(next, append(combination[:len(combination):len(combination)], word))
+			}
+		}
+		combinations = next
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, combination := range combinations {
+		for _, separator := range separators {
+			label := strings.Join(combination, separator)
+			if len(label) > maxLabelLength || !labelPattern.MatchString(label) {
+				continue
+			}
+			for _, tld := range clean(tlds) {
+				name := label + "." + strings.TrimPrefix(tld, ".")
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,101 @@
+package generate
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestThesaurus_Synonyms(t *testing.T) {
+	thesaurus, err := LoadThesaurus(strings.NewReader(`# comment
+fast, quick, rapid
+
+Quick, Speedy
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		word     string
+		expected []string
+	}{
+		{"fast", []string{"fast", "quick", "rapid"}},
+		{"QUICK", []string{"quick", "fast", "rapid", "speedy"}},
+		{"unknown", []string{"unknown"}},
+		{" ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := thesaurus.Synonyms(tt.word); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Synonyms(%q) = %v, want %v", tt.word, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDefaultThesaurus(t *testing.T) {
+	synonyms := DefaultThesaurus().Synonyms("fast")
+	if len(synonyms) < 2 || synonyms[0] != "fast" || !slices.Contains(synonyms, "quick") {
+		t.Errorf("expected fast and its synonyms from the built-in thesaurus, got %v", synonyms)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	tests := []struct {
+		name       string
+		groups     [][]string
+		separators []string
+		tlds       []string
+		expected   []string
+	}{
+		{
+			name:     "single group",
+			groups:   [][]string{{"fast", "quick"}},
+			tlds:     []string{"com"},
+			expected: []string{"fast.com", "quick.com"},
+		},
+		{
+			name:     "groups in order under each TLD",
+			groups:   [][]string{{"fast", "quick"}, {"car"}},
+			tlds:     []string{"com", ".IO"},
+			expected: []string{"fastcar.com", "fastcar.io", "quickcar.com", "quickcar.io"},
+		},
+		{
+			name:       "separators",
+			groups:     [][]string{{"fast"}, {"car", "bike"}},
+			separators: []string{"", "-"},
+			tlds:       []string{"io"},
+			expected:   []string{"fastcar.io", "fast-car.io", "fastbike.io", "fast-bike.io"},
+		},
+		{
+			name:       "separators that make repeats or invalid labels",
+			groups:     [][]string{{"fast"}, {"", "car"}},
+			separators: []string{"", "_", "-"},
+			tlds:       []string{"io"},
+			expected:   []string{"fast.io", "fastcar.io", "fast-car.io"},
+		},
+		{
+			name:     "invalid labels",
+			groups:   [][]string{{"fast", "fast!", strings.Repeat("a", 64)}},
+			tlds:     []string{"com"},
+			expected: []string{"fast.com"},
+		},
+		{
+			name:     "no groups",
+			groups:   nil,
+			tlds:     []string{"com"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Combine(tt.groups, tt.separators, tt.tlds); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Combine() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
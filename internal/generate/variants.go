@@ -0,0 +1,152 @@
+package generate
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+// Kinds of lookalike variants
+const (
+	KindOmission      = "omission"      // A character left out: exmple
+	KindRepetition    = "repetition"    // A character typed twice: exaample
+	KindTransposition = "transposition" // Two characters swapped: exmaple
+	KindReplacement   = "replacement"   // A neighbouring key typed instead: exsmple
+	KindHomoglyph     = "homoglyph"     // A lookalike character: exampie, exаmple
+	KindHyphenation   = "hyphenation"   // A hyphen added or removed: ex-ample
+	KindPlural        = "plural"        // A plural or singular form: examples
+	KindLeet          = "leet"          // Letters written as digits: 3xampl3, ex4mple
+)
+
+// Kinds lists every kind of variant, in the order Variants produces them
+var Kinds = []string{
+	KindOmission, KindRepetition, KindTransposition, KindReplacement,
+	KindHomoglyph, KindHyphenation, KindPlural, KindLeet,
+}
+
+// keyboardNeighbours maps each key of a QWERTY keyboard to the keys around it
+var keyboardNeighbours = map[rune]string{
+	'1': "2q", '2': "13qw", '3': "24we", '4': "35er", '5': "46rt",
+	'6': "57ty", '7': "68yu", '8': "79ui", '9': "80io", '0': "9op",
+	'q': "12wa", 'w': "23qeas", 'e': "34wrsd", 'r': "45etdf", 't': "56ryfg",
+	'y': "67tugh", 'u': "78yihj", 'i': "89uojk", 'o': "90ipkl", 'p': "0ol",
+	'a': "qwsz", 's': "weadzx", 'd': "ersfxc", 'f': "rtdgcv", 'g': "tyfhvb",
+	'h': "yugjbn", 'j': "uihknm", 'k': "iojlm", 'l': "opk",
+	'z': "asx", 'x': "sdzc", 'c': "dfxv", 'v': "fgcb", 'b': "ghvn",
+	'n': "hjbm", 'm': "jkn",
+}
+
+// leetDigits maps letters to the digits they are written as in leetspeak
+var leetDigits = map[rune]rune{
+	'a': '4', 'b': '8', 'e': '3', 'g': '9', 'i': '1',
+	'l': '1', 'o': '0', 's': '5', 't': '7', 'z': '2',
+}
+
+// Variant is a lookalike of a domain and how it differs
+type Variant struct {
+	Name string
+	Kind string
+}
+
+// Variants returns lookalikes of domain that a typo or a misreading could
+// lead to, under the same TLD, such as exmple.com, exaample.com or
+// exampie.com for example.com. A name without TLD gets DefaultTLD. Only the
+// given kinds are produced, or all of them when none are given. Each name
+// appears once, with the first kind that produced it; domain itself and
+// invalid labels are left out.
+func Variants(base string, kinds ...string) []Variant {
+	label, tld := split(base)
+	if label == "" {
+		return nil
+	}
+
+	var variants []Variant
+	seen := map[string]bool{label: true}
+	add := func(kind, candidate string) {
+		if seen[candidate] || !labelPattern.MatchString(candidate) {
+			return
+		}
+		if len(kinds) > 0 && !slices.Contains(kinds, kind) {
+			return
+		}
+		if ascii, err := domain.ToASCII(candidate); err != nil || len(ascii) > maxLabelLength {
+			return
+		}
+		seen[candidate] = true
+		variants = append(variants, Variant{Name: candidate + "." + tld, Kind: kind})
+	}
+
+	runes := []rune(label)
+	splice := func(i, j int, middle string) string {
+		return string(runes[:i]) + middle + string(runes[j:])
+	}
+
+	for i := range runes {
+		add(KindOmission, splice(i, i+1, ""))
+	}
+	for i, r := range runes {
+		add(KindRepetition, splice(i, i+1, string(r)+string(r)))
+	}
+	for i := 0; i+1 < len(runes); i++ {
+		add(KindTransposition, splice(i, i+2, string(runes[i+1])+string(runes[i])))
+	}
+	for i, r := range runes {
+		for _, neighbour := range keyboardNeighbours[r] {
+			add(KindReplacement, splice(i, i+1, string(neighbour)))
+		}
+	}
+	for i, r := range runes {
+		for _, lookalike := range domain.Homoglyphs(r) {
+			add(KindHomoglyph, splice(i, i+1, lookalike))
+		}
+	}
+	if strings.Contains(label, "-") {
+		add(KindHyphenation, strings.ReplaceAll(label, "-", ""))
+	}
+	for i := 1; i < len(runes); i++ {
+		if runes[i-1] != '-' && runes[i] != '-' {
+			add(KindHyphenation, splice(i, i, "-"))
+		}
+	}
+	if trimmed, ok := strings.CutSuffix(label, "s"); ok {
+		add(KindPlural, trimmed)
+	} else {
+		add(KindPlural, label+"s")
+	}
+	for _, candidate := range leetVariants(label) {
+		add(KindLeet, candidate)
+	}
+
+	return variants
+}
+
+// leetVariants writes label with digits for letters: one letter at a time,
+// every occurrence of one letter, as in f00d for food, and every letter
+func leetVariants(label string) []string {
+	runes := []rune(label)
+	var candidates []string
+	for i, r := range runes {
+		if digit, ok := leetDigits[r]; ok {
+			replaced := slices.Clone(runes)
+			replaced[i] = digit
+			candidates = append(candidates, string(replaced))
+		}
+	}
+
+	for _, letter := range runes {
+		digit, ok := leetDigits[letter]
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, strings.ReplaceAll(label, string(letter), string(digit)))
+	}
+
+	candidates = append(candidates, strings.Map(func(r rune) rune {
+		if digit, ok := leetDigits[r]; ok {
+			return digit
+		}
+		return r
+	}, label))
+	return candidates
+}
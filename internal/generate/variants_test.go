@@ -0,0 +1,93 @@
+package generate
+
+import (
+	"testing"
+)
+
+func TestVariants(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		kinds    []string
+		expected []Variant // Must all be among the variants
+		excluded []string  // Must not be among the variants
+	}{
+		{
+			name: "typos and lookalikes",
+			base: "example.com",
+			expected: []Variant{
+				{"xample.com", KindOmission},
+				{"exmple.com", KindOmission},
+				{"exaample.com", KindRepetition},
+				{"exmaple.com", KindTransposition},
+				{"exsmple.com", KindReplacement},
+				{"exampie.com", KindHomoglyph},
+				{"exаmple.com", KindHomoglyph}, // Cyrillic а
+				{"exarnple.com", KindHomoglyph},
+				{"ex-ample.com", KindHyphenation},
+				{"examples.com", KindPlural},
+			},
+			excluded: []string{"example.com", "-example.com", "example-.com"},
+		},
+		{
+			name: "hyphens and plurals",
+			base: "my-shop.io",
+			expected: []Variant{
+				{"myshop.io", KindOmission},
+				{"my-shops.io", KindPlural},
+			},
+			excluded: []string{"my--shop.io"},
+		},
+		{
+			name: "leet",
+			base: "food.com",
+			expected: []Variant{
+				{"f00d.com", KindLeet},
+			},
+		},
+		{
+			name:  "only the given kinds",
+			base:  "example.com",
+			kinds: []string{KindLeet},
+			expected: []Variant{
+				{"ex4mple.com", KindLeet},
+				{"3xampl3.com", KindLeet},
+				{"3x4mp13.com", KindLeet},
+				{"examp1e.com", KindLeet},
+			},
+			excluded: []string{"exmple.com", "exаmple.com"},
+		},
+		{
+			name:     "name without TLD",
+			base:     "Example",
+			expected: []Variant{{"exmple.com", KindOmission}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variants := Variants(tt.base, tt.kinds...)
+			kinds := make(map[string]string)
+			for _, variant := range variants {
+				if _, ok := kinds[variant.Name]; ok {
+					t.Errorf("%s listed more than once", variant.Name)
+				}
+				kinds[variant.Name] = variant.Kind
+			}
+			for _, want := range tt.expected {
+				if kind, ok := kinds[want.Name]; !ok || kind != want.Kind {
+					t.Errorf("expected %s as %s, got %q", want.Name, want.Kind, kind)
+				}
+			}
+			for _, name := range tt.excluded {
+				if _, ok := kinds[name]; ok {
+					t.Errorf("expected %s to be left out", name)
+				}
+			}
+		})
+	}
+
+	if variants := Variants(""); variants != nil {
+		t.Errorf("expected no variants of an empty name, got %v", variants)
+	}
+}
@@ -0,0 +1,423 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// maxBatchWrite is the most items BatchWriteItem accepts at once
+	maxBatchWrite = 25
+
+	// maxUnprocessedRetries bounds how often items DynamoDB did not
+	// process, usually because of throttling, are sent again
+	maxUnprocessedRetries = 5
+
+	// dynamoDBTimeout bounds each DynamoDB request
+	dynamoDBTimeout = 30 * time.Second
+
+	// latestKey is the partition holding the latest definitive check of
+	// every domain, keyed by domain
+	latestKey = "LATEST"
+
+	// latestPricesKey is the partition holding the latest price of every
+	// TLD, keyed by TLD
+	latestPricesKey = "LATEST_PRICES"
+)
+
+// DynamoDBAPI is the part of the DynamoDB client used by the store
+type DynamoDBAPI interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+// DynamoDB is a check history stored in a DynamoDB table, which lets
+// several machines share one history. The table needs a string partition
+// key "pk" and a string sort key "sk". Checks of a domain are stored under
+// "DOMAIN#<domain>", sorted by time, and the latest definitive check of
+// every domain under "LATEST", so neither query needs a scan. TLD prices
+// are kept the same way, under "PRICE#<tld>" and "LATEST_PRICES".
+type DynamoDB struct {
+	api        DynamoDBAPI
+	table      string
+	retryDelay time.Duration
+}
+
+// NewDynamoDB returns the history stored in table
+func NewDynamoDB(api DynamoDBAPI, table string) *DynamoDB {
+	return &DynamoDB{api: api, table: table, retryDelay: 100 * time.Millisecond}
+}
+
+// SetRetryDelay sets the delay before unprocessed items are first sent
+// again; it doubles with every retry
+func (d *DynamoDB) SetRetryDelay(delay time.Duration) {
+	d.retryDelay = delay
+}
+
+// Close releases the store; the client holds no connections to close
+func (d *DynamoDB) Close() error {
+	return nil
+}
+
+// Append records checks, then moves the latest definitive check of each
+// domain forward. Updates of the latest check are conditional, so a slower
+// machine never replaces a newer check with an older one.
+func (d *DynamoDB) Append(records []Record) error {
+	requests := make([]types.WriteRequest, 0, len(records))
+	latest := make(map[string]Record)
+	for _, record := range records {
+		item, err := checkItem(record)
+		if err != nil {
+			return err
+		}
+		item["pk"] = &types.AttributeValueMemberS{Value: "DOMAIN#" + record.Domain}
+		item["sk"] = &types.AttributeValueMemberS{Value: checkSortKey(record)}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+
+		if record.Definitive() {
+			latest[record.Domain] = record
+		}
+	}
+
+	for start := 0; start < len(requests); start += maxBatchWrite {
+		end := min(start+maxBatchWrite, len(requests))
+		if err := d.batchWrite(requests[start:end]); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(latest))
+	for name := range latest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := d.putLatest(latest[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchWrite writes requests, sending unprocessed items again with
+// exponential backoff
+func (d *DynamoDB) batchWrite(requests []types.WriteRequest) error {
+	delay := d.retryDelay
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), dynamoDBTimeout)
+		out, err := d.api.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{d.table: requests},
+		})
+		cancel()
+		if err != nil {
+			return customErrors.WrapAWSError(err, "dynamodb", "BatchWriteItem")
+		}
+
+		requests = out.UnprocessedItems[d.table]
+		if len(requests) == 0 {
+			return nil
+		}
+		if attempt == maxUnprocessedRetries {
+			return customErrors.NewSystemError("history",
+				fmt.Sprintf("%d history records could not be written to DynamoDB table %s", len(requests), d.table), nil)
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// putLatest stores record as the latest check of its domain, unless a
+// newer check is already stored
+func (d *DynamoDB) putLatest(record Record) error {
+	item, err := checkItem(record)
+	if err != nil {
+		return err
+	}
+	item["pk"] = &types.AttributeValueMemberS{Value: latestKey}
+	item["sk"] = &types.AttributeValueMemberS{Value: record.Domain}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dynamoDBTimeout)
+	defer cancel()
+	_, err = d.api.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(d.table),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(pk) OR checked_at <= :checked_at"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":checked_at": item["checked_at"],
+		},
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return nil
+	}
+	if err != nil {
+		return customErrors.WrapAWSError(err, "dynamodb", "PutItem")
+	}
+	return nil
+}
+
+// Latest returns the most recent definitive record of every domain
+func (d *DynamoDB) Latest() (map[string]Record, error) {
+	records, err := d.query(&dynamodb.QueryInput{
+		KeyConditionExpression: aws.String("pk = :pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: latestKey},
+		},
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]Record, len(records))
+	for _, record := range records {
+		latest[record.Domain] = record
+	}
+	return latest, nil
+}
+
+// Records returns every check of name, oldest first
+func (d *DynamoDB) Records(name string) ([]Record, error) {
+	return d.query(domainQuery(name), false)
+}
+
+// LastSeen returns the most recent check of name with status, and false
+// when the domain was never seen with it
+func (d *DynamoDB) LastSeen(name string, status domain.AvailabilityStatus) (Record, bool, error) {
+	input := domainQuery(name)
+	input.ScanIndexForward = aws.Bool(false)
+	input.FilterExpression = aws.String("#status = :status")
+	// status is a DynamoDB reserved word
+	input.ExpressionAttributeNames = map[string]string{"#status": "status"}
+	input.ExpressionAttributeValues[":status"] = &types.AttributeValueMemberS{Value: string(status)}
+
+	records, err := d.query(input, true)
+	if err != nil || len(records) == 0 {
+		return Record{}, false, err
+	}
+	return records[0], true, nil
+}
+
+// domainQuery selects the checks of name, oldest first
+func domainQuery(name string) *dynamodb.QueryInput {
+	return &dynamodb.QueryInput{
+		KeyConditionExpression: aws.String("pk = :pk AND begins_with(sk, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     &types.AttributeValueMemberS{Value: "DOMAIN#" + name},
+			":prefix": &types.AttributeValueMemberS{Value: "CHECK#"},
+		},
+	}
+}
+
+// query runs input across every page of results, or until the first
+// record when first is set
+func (d *DynamoDB) query(input *dynamodb.QueryInput, first bool) ([]Record, error) {
+	var records []Record
+	err := d.queryRecords(input, first, func(data []byte) error {
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			return err
+		}
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+// queryRecords runs input across every page of results, or until the first
+// item when first is set, passing the JSON record of each item to decode
+func (d *DynamoDB) queryRecords(input *dynamodb.QueryInput, first bool, decode func(data []byte) error) error {
+	input.TableName = aws.String(d.table)
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), dynamoDBTimeout)
+		out, err := d.api.Query(ctx, input)
+		cancel()
+		if err != nil {
+			return customErrors.WrapAWSError(err, "dynamodb", "Query")
+		}
+
+		for _, item := range out.Items {
+			data, ok := item["record"].(*types.AttributeValueMemberS)
+			if !ok {
+				return customErrors.NewSystemError("history", "corrupt record in DynamoDB table "+d.table, nil)
+			}
+			if err := decode([]byte(data.Value)); err != nil {
+				return customErrors.NewSystemError("history", "corrupt record in DynamoDB table "+d.table, err)
+			}
+		}
+
+		if len(out.LastEvaluatedKey) == 0 || (first && len(out.Items) > 0) {
+			return nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+// checkSortKey orders the checks of a domain by time. The zero-padded
+// nanoseconds sort as strings, and the run ID keeps checks made at the
+// same instant by different machines apart.
+func checkSortKey(record Record) string {
+	return fmt.Sprintf("CHECK#%020d#%s", record.CheckedAt.UnixNano(), record.RunID)
+}
+
+// checkItem converts record to its DynamoDB attributes, without the key
+func checkItem(record Record) (map[string]types.AttributeValue, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, customErrors.NewSystemError("history", "unable to encode history record", err)
+	}
+
+	item := map[string]types.AttributeValue{
+		"run_id":     &types.AttributeValueMemberS{Value: record.RunID},
+		"domain":     &types.AttributeValueMemberS{Value: record.Domain},
+		"status":     &types.AttributeValueMemberS{Value: record.Status},
+		"definitive": &types.AttributeValueMemberBOOL{Value: record.Definitive()},
+		"checked_at": &types.AttributeValueMemberN{Value: strconv.FormatInt(record.CheckedAt.UnixNano(), 10)},
+		"record":     &types.AttributeValueMemberS{Value: string(data)},
+	}
+	if record.Pricing != nil {
+		item["currency"] = &types.AttributeValueMemberS{Value: record.Pricing.Currency}
+		if record.Pricing.Registration != nil {
+			item["price"] = &types.AttributeValueMemberN{Value: strconv.FormatFloat(*record.Pricing.Registration, 'f', -1, 64)}
+		}
+	}
+	return item, nil
+}
+
+// AppendPrices records TLD prices, then moves the latest price of each TLD
+// forward, never replacing a price with one observed earlier
+func (d *DynamoDB) AppendPrices(records []PriceRecord) error {
+	requests := make([]types.WriteRequest, 0, len(records))
+	latest := make(map[string]PriceRecord)
+	for _, record := range records {
+		item, err := priceItem(record)
+		if err != nil {
+			return err
+		}
+		item["pk"] = &types.AttributeValueMemberS{Value: "PRICE#" + record.TLD}
+		item["sk"] = &types.AttributeValueMemberS{Value: fmt.Sprintf("PRICE#%020d", record.ObservedAt.UnixNano())}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+		latest[record.TLD] = record
+	}
+
+	for start := 0; start < len(requests); start += maxBatchWrite {
+		end := min(start+maxBatchWrite, len(requests))
+		if err := d.batchWrite(requests[start:end]); err != nil {
+			return err
+		}
+	}
+
+	tlds := make([]string, 0, len(latest))
+	for tld := range latest {
+		tlds = append(tlds, tld)
+	}
+	sort.Strings(tlds)
+	for _, tld := range tlds {
+		if err := d.putLatestPrice(latest[tld]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putLatestPrice stores record as the latest price of its TLD, unless a
+// price observed later is already stored
+func (d *DynamoDB) putLatestPrice(record PriceRecord) error {
+	item, err := priceItem(record)
+	if err != nil {
+		return err
+	}
+	item["pk"] = &types.AttributeValueMemberS{Value: latestPricesKey}
+	item["sk"] = &types.AttributeValueMemberS{Value: record.TLD}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dynamoDBTimeout)
+	defer cancel()
+	_, err = d.api.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(d.table),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(pk) OR observed_at <= :observed_at"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":observed_at": item["observed_at"],
+		},
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return nil
+	}
+	if err != nil {
+		return customErrors.WrapAWSError(err, "dynamodb", "PutItem")
+	}
+	return nil
+}
+
+// LatestPrices returns the most recent price of every TLD
+func (d *DynamoDB) LatestPrices() (map[string]PriceRecord, error) {
+	records, err := d.queryPrices(&dynamodb.QueryInput{
+		KeyConditionExpression: aws.String("pk = :pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: latestPricesKey},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]PriceRecord, len(records))
+	for _, record := range records {
+		latest[record.TLD] = record
+	}
+	return latest, nil
+}
+
+// Prices returns every recorded price of tld, oldest first
+func (d *DynamoDB) Prices(tld string) ([]PriceRecord, error) {
+	return d.queryPrices(&dynamodb.QueryInput{
+		KeyConditionExpression: aws.String("pk = :pk AND begins_with(sk, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     &types.AttributeValueMemberS{Value: "PRICE#" + tld},
+			":prefix": &types.AttributeValueMemberS{Value: "PRICE#"},
+		},
+	})
+}
+
+// queryPrices runs input across every page of results
+func (d *DynamoDB) queryPrices(input *dynamodb.QueryInput) ([]PriceRecord, error) {
+	var records []PriceRecord
+	err := d.queryRecords(input, false, func(data []byte) error {
+		var record PriceRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return err
+		}
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+// priceItem converts record to its DynamoDB attributes, without the key
+func priceItem(record PriceRecord) (map[string]types.AttributeValue, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, customErrors.NewSystemError("history", "unable to encode price record", err)
+	}
+
+	return map[string]types.AttributeValue{
+		"tld":         &types.AttributeValueMemberS{Value: record.TLD},
+		"currency":    &types.AttributeValueMemberS{Value: record.Currency},
+		"observed_at": &types.AttributeValueMemberN{Value: strconv.FormatInt(record.ObservedAt.UnixNano(), 10)},
+		"record":      &types.AttributeValueMemberS{Value: string(data)},
+	}, nil
+}
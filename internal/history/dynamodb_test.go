@@ -0,0 +1,250 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+)
+
+// fakeDynamoDB is an in-memory table keyed by pk and sk. Queries return
+// pages of two items to exercise pagination.
+type fakeDynamoDB struct {
+	items map[string]map[string]map[string]types.AttributeValue
+
+	// unprocessed is how many batch writes leave their last item unprocessed
+	unprocessed int
+	batches     int
+	err         error
+}
+
+func newFakeDynamoDB() *fakeDynamoDB {
+	return &fakeDynamoDB{items: make(map[string]map[string]map[string]types.AttributeValue)}
+}
+
+func attr(item map[string]types.AttributeValue, name string) string {
+	switch v := item[name].(type) {
+	case *types.AttributeValueMemberS:
+		return v.Value
+	case *types.AttributeValueMemberN:
+		return v.Value
+	}
+	return ""
+}
+
+func (f *fakeDynamoDB) put(item map[string]types.AttributeValue) {
+	pk, sk := attr(item, "pk"), attr(item, "sk")
+	if f.items[pk] == nil {
+		f.items[pk] = make(map[string]map[string]types.AttributeValue)
+	}
+	f.items[pk][sk] = item
+}
+
+func (f *fakeDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if existing, ok := f.items[attr(params.Item, "pk")][attr(params.Item, "sk")]; ok && params.ConditionExpression != nil {
+		current, _ := strconv.ParseInt(attr(existing, "checked_at"), 10, 64)
+		next, _ := strconv.ParseInt(attr(params.ExpressionAttributeValues, ":checked_at"), 10, 64)
+		if current > next {
+			return nil, &types.ConditionalCheckFailedException{Message: new(string)}
+		}
+	}
+	f.put(params.Item)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeDynamoDB) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.batches++
+
+	out := &dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]types.WriteRequest{}}
+	for table, requests := range params.RequestItems {
+		if len(requests) > maxBatchWrite {
+			return nil, errors.New("too many items in batch")
+		}
+		if f.unprocessed > 0 {
+			f.unprocessed--
+			out.UnprocessedItems[table] = requests[len(requests)-1:]
+			requests = requests[:len(requests)-1]
+		}
+		for _, request := range requests {
+			f.put(request.PutRequest.Item)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	values := params.ExpressionAttributeValues
+	partition := f.items[attr(values, ":pk")]
+
+	var keys []string
+	for sk, item := range partition {
+		if !strings.HasPrefix(sk, attr(values, ":prefix")) {
+			continue
+		}
+		if status := attr(values, ":status"); status != "" && attr(item, "status") != status {
+			continue
+		}
+		keys = append(keys, sk)
+	}
+	sort.Strings(keys)
+	if params.ScanIndexForward != nil && !*params.ScanIndexForward {
+		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	}
+
+	if start := attr(params.ExclusiveStartKey, "sk"); start != "" {
+		for i, sk := range keys {
+			if sk == start {
+				keys = keys[i+1:]
+				break
+			}
+		}
+	}
+
+	out := &dynamodb.QueryOutput{}
+	for i, sk := range keys {
+		if i == 2 {
+			out.LastEvaluatedKey = map[string]types.AttributeValue{"sk": &types.AttributeValueMemberS{Value: keys[1]}}
+			break
+		}
+		out.Items = append(out.Items, partition[sk])
+	}
+	return out, nil
+}
+
+// checkAt returns a record of name checked minute minutes into the test day
+func checkAt(runID, name string, status domain.AvailabilityStatus, minute int) Record {
+	r := result(name, status, nil)
+	r.CheckedAt = r.CheckedAt.Add(time.Duration(minute) * time.Minute)
+	return NewRecord(runID, r)
+}
+
+func TestDynamoDB_AppendAndQueries(t *testing.T) {
+	api := newFakeDynamoDB()
+	store := NewDynamoDB(api, "history")
+
+	latest, err := store.Latest()
+	if err != nil || len(latest) != 0 {
+		t.Fatalf("Expected an empty history, got %v, %v", latest, err)
+	}
+
+	price := 12.5
+	priced := result("b.com", domain.StatusAvailable, nil)
+	priced.Pricing = &domain.PricingInfo{RegistrationPrice: &price, Currency: "USD"}
+
+	if err := store.Append([]Record{
+		checkAt("run-1", "a.com", domain.StatusAvailable, 1),
+		NewRecord("run-1", priced),
+		checkAt("run-2", "a.com", domain.StatusUnavailable, 2),
+		checkAt("run-3", "a.com", domain.StatusAvailable, 3),
+		checkAt("run-4", "a.com", domain.StatusUnavailable, 4),
+		NewRecord("run-4", result("b.com", domain.StatusUnknown, errors.New("throttled"))),
+	}); err != nil {
+		t.Fatalf("Append() error: %v", err)
+	}
+
+	latest, err = store.Latest()
+	if err != nil {
+		t.Fatalf("Latest() error: %v", err)
+	}
+	if got := latest["a.com"]; got.Status != "UNAVAILABLE" || got.RunID != "run-4" {
+		t.Errorf("Expected a.com UNAVAILABLE from run-4, got %+v", got)
+	}
+	if got := latest["b.com"]; got.RunID != "run-1" || got.Pricing == nil || *got.Pricing.Registration != 12.5 {
+		t.Errorf("Expected the priced b.com check from run-1, got %+v", got)
+	}
+
+	records, err := store.Records("a.com")
+	if err != nil {
+		t.Fatalf("Records() error: %v", err)
+	}
+	if len(records) != 4 || records[0].RunID != "run-1" || records[3].RunID != "run-4" {
+		t.Errorf("Expected the four checks of a.com oldest first across pages, got %+v", records)
+	}
+
+	record, ok, err := store.LastSeen("a.com", domain.StatusAvailable)
+	if err != nil || !ok || record.RunID != "run-3" {
+		t.Errorf("Expected a.com last seen available in run-3, got %+v, %v, %v", record, ok, err)
+	}
+	if _, ok, err := store.LastSeen("b.com", domain.StatusUnavailable); ok || err != nil {
+		t.Errorf("Expected b.com never seen unavailable, got %v, %v", ok, err)
+	}
+}
+
+func TestDynamoDB_LatestIsNotReplacedByOlderChecks(t *testing.T) {
+	api := newFakeDynamoDB()
+	store := NewDynamoDB(api, "history")
+
+	// Another machine already recorded a newer check
+	if err := store.Append([]Record{checkAt("run-2", "a.com", domain.StatusAvailable, 10)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Append([]Record{checkAt("run-1", "a.com", domain.StatusUnavailable, 5)}); err != nil {
+		t.Fatalf("Append() of an older check error: %v", err)
+	}
+
+	latest, _ := store.Latest()
+	if got := latest["a.com"]; got.RunID != "run-2" {
+		t.Errorf("Expected the newer check to stay latest, got %+v", got)
+	}
+	if records, _ := store.Records("a.com"); len(records) != 2 {
+		t.Errorf("Expected both checks to be recorded, got %d", len(records))
+	}
+}
+
+func TestDynamoDB_Batches(t *testing.T) {
+	api := newFakeDynamoDB()
+	api.unprocessed = 2
+	store := NewDynamoDB(api, "history")
+	store.SetRetryDelay(time.Millisecond)
+
+	var records []Record
+	for i := 0; i < 30; i++ {
+		records = append(records, checkAt("run-1", "d"+strconv.Itoa(i)+".com", domain.StatusAvailable, i))
+	}
+	if err := store.Append(records); err != nil {
+		t.Fatalf("Append() error: %v", err)
+	}
+	// 30 records take two batches, and each unprocessed item one more
+	if api.batches != 4 {
+		t.Errorf("Expected 4 batch writes, got %d", api.batches)
+	}
+	if latest, _ := store.Latest(); len(latest) != 30 {
+		t.Errorf("Expected every domain to be recorded, got %d", len(latest))
+	}
+
+	api.unprocessed = maxUnprocessedRetries + 1
+	if err := store.Append(records[:1]); err == nil {
+		t.Error("Expected an error when items stay unprocessed")
+	}
+}
+
+func TestDynamoDB_Errors(t *testing.T) {
+	api := newFakeDynamoDB()
+	api.err = &smithy.GenericAPIError{Code: "ResourceNotFoundException", Message: "Requested resource not found"}
+	store := NewDynamoDB(api, "history")
+
+	if err := store.Append([]Record{checkAt("run-1", "a.com", domain.StatusAvailable, 0)}); err == nil {
+		t.Error("Expected Append() to return the DynamoDB error")
+	}
+	if _, err := store.Latest(); err == nil {
+		t.Error("Expected Latest() to return the DynamoDB error")
+	}
+}
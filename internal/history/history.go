@@ -0,0 +1,251 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/output"
+)
+
+// maxRecordBytes bounds the length of one history line
+const maxRecordBytes = 1 << 20
+
+// Record is one domain check as stored in history: the JSON result shape
+// used by --output json, tagged with the run that produced it
+type Record struct {
+	RunID string `json:"run_id"`
+
+	// Tags are the key=value labels of the run, such as client=acme
+	Tags map[string]string `json:"tags,omitempty"`
+
+	output.JSONResult
+}
+
+// NewRecord creates the history record of result from run runID
+func NewRecord(runID string, result *domain.AvailabilityResult) Record {
+	return Record{RunID: runID, JSONResult: output.NewJSONResult(result)}
+}
+
+// Definitive reports whether the record settles the domain's status, as
+// opposed to a failed, skipped or inconclusive check
+func (r Record) Definitive() bool {
+	return r.Error == "" && !r.Skipped && r.Status != string(domain.StatusUnknown)
+}
+
+// HasTags reports whether the record carries every tag in tags
+func (r Record) HasTags(tags map[string]string) bool {
+	for key, value := range tags {
+		if got, ok := r.Tags[key]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseTags parses key=value run tags. Keys may contain letters, digits,
+// "-", "_" and ".", and each key may only be given once.
+func ParseTags(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	tags := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !validTagKey(key) {
+			return nil, customErrors.NewValidationError("", "tag",
+				fmt.Sprintf("invalid tag %q: use key=value with a key of letters, digits, '-', '_' or '.'", value), nil)
+		}
+		if _, seen := tags[key]; seen {
+			return nil, customErrors.NewValidationError("", "tag", fmt.Sprintf("tag %q is given more than once", key), nil)
+		}
+		tags[key] = strings.TrimSpace(val)
+	}
+	return tags, nil
+}
+
+// validTagKey reports whether key can name a tag
+func validTagKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// Result converts the record back to the result it was made from, marked
+// as coming from history. Errors are not restored, since only definitive
+// records are reused.
+func (r Record) Result() *domain.AvailabilityResult {
+	result := &domain.AvailabilityResult{
+		Domain:        r.Domain,
+		UnicodeDomain: r.UnicodeDomain,
+		Available:     r.Available,
+		Status:        domain.AvailabilityStatus(r.Status),
+		Message:       r.Message,
+		CheckedAt:     r.CheckedAt,
+		Warnings:      r.Warnings,
+		FromHistory:   true,
+	}
+	if r.Pricing != nil {
+		result.Pricing = &domain.PricingInfo{
+			RegistrationPrice: r.Pricing.Registration,
+			RenewalPrice:      r.Pricing.Renewal,
+			TransferPrice:     r.Pricing.Transfer,
+			Currency:          r.Pricing.Currency,
+		}
+	}
+	return result
+}
+
+// Log is an append-only history of checks stored as JSON Lines, one
+// record per line in the order the checks were recorded
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLog returns the history log stored at path. The file is created on
+// the first append.
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Path returns the file the log is stored in
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Close releases the log; it holds no resources between calls
+func (l *Log) Close() error {
+	return nil
+}
+
+// Append adds records to the end of the log
+func (l *Log) Append(records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return customErrors.NewSystemError("history", "unable to create history directory", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return customErrors.NewSystemError("history", "unable to open history "+l.path, err)
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			file.Close()
+			return customErrors.NewSystemError("history", "unable to encode history record", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return customErrors.NewSystemError("history", "unable to write history "+l.path, err)
+	}
+	if err := file.Close(); err != nil {
+		return customErrors.NewSystemError("history", "unable to write history "+l.path, err)
+	}
+	return nil
+}
+
+// Latest returns the most recent definitive record of every domain in the
+// log. A log that does not exist yet has no records.
+func (l *Log) Latest() (map[string]Record, error) {
+	records, err := l.All()
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]Record)
+	for _, record := range records {
+		if record.Definitive() {
+			latest[record.Domain] = record
+		}
+	}
+	return latest, nil
+}
+
+// Records returns every check of name in the log, oldest first
+func (l *Log) Records(name string) ([]Record, error) {
+	records, err := l.All()
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []Record
+	for _, record := range records {
+		if record.Domain == name {
+			matching = append(matching, record)
+		}
+	}
+	return matching, nil
+}
+
+// LastSeen returns the most recent check of name with status, and false
+// when the domain was never seen with it
+func (l *Log) LastSeen(name string, status domain.AvailabilityStatus) (Record, bool, error) {
+	records, err := l.Records(name)
+	if err != nil {
+		return Record{}, false, err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].Status == string(status) {
+			return records[i], true, nil
+		}
+	}
+	return Record{}, false, nil
+}
+
+// All returns every record in the log, oldest first. A log that does not
+// exist yet has no records.
+func (l *Log) All() ([]Record, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, customErrors.NewSystemError("history", "unable to open history "+l.path, err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordBytes)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// A line cut short by a crash should not hide the rest of the history
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, customErrors.NewSystemError("history", "unable to read history "+l.path, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,183 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+func result(name string, status domain.AvailabilityStatus, err error) *domain.AvailabilityResult {
+	return &domain.AvailabilityResult{
+		Domain:    name,
+		Available: status == domain.StatusAvailable,
+		Status:    status,
+		CheckedAt: time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+		Error:     err,
+	}
+}
+
+func TestLog_AppendAndLatest(t *testing.T) {
+	log := NewLog(filepath.Join(t.TempDir(), "data", "history.jsonl"))
+
+	// A log that was never written has no records
+	latest, err := log.Latest()
+	if err != nil || len(latest) != 0 {
+		t.Fatalf("Expected an empty history, got %v, %v", latest, err)
+	}
+
+	first := []Record{
+		NewRecord("run-1", result("a.com", domain.StatusUnavailable, nil)),
+		NewRecord("run-1", result("b.com", domain.StatusAvailable, nil)),
+	}
+	second := []Record{
+		NewRecord("run-2", result("a.com", domain.StatusAvailable, nil)),
+		// Failed checks do not replace the last known status
+		NewRecord("run-2", result("b.com", domain.StatusUnknown, errors.New("throttled"))),
+	}
+	if err := log.Append(first); err != nil {
+		t.Fatalf("Append() error: %v", err)
+	}
+	if err := log.Append(second); err != nil {
+		t.Fatalf("Append() error: %v", err)
+	}
+
+	latest, err = log.Latest()
+	if err != nil {
+		t.Fatalf("Latest() error: %v", err)
+	}
+	if got := latest["a.com"]; got.Status != "AVAILABLE" || got.RunID != "run-2" {
+		t.Errorf("Expected a.com AVAILABLE from run-2, got %+v", got)
+	}
+	if got := latest["b.com"]; got.Status != "AVAILABLE" || got.RunID != "run-1" {
+		t.Errorf("Expected b.com AVAILABLE from run-1, got %+v", got)
+	}
+}
+
+func TestLog_SkipsCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	log := NewLog(path)
+	if err := log.Append([]Record{NewRecord("run-1", result("a.com", domain.StatusReserved, nil))}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a write cut short by a crash
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"run_id":"run-2","domain":"a.c`)
+	file.Close()
+
+	latest, err := log.Latest()
+	if err != nil {
+		t.Fatalf("Latest() error: %v", err)
+	}
+	if latest["a.com"].Status != "RESERVED" {
+		t.Errorf("Expected the complete record to survive, got %+v", latest)
+	}
+}
+
+func TestRecord_Definitive(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *domain.AvailabilityResult
+		expected bool
+	}{
+		{"available", result("a.com", domain.StatusAvailable, nil), true},
+		{"unavailable", result("a.com", domain.StatusUnavailable, nil), true},
+		{"unknown", result("a.com", domain.StatusUnknown, nil), false},
+		{"failed", result("a.com", domain.StatusUnavailable, errors.New("denied")), false},
+		{"skipped", &domain.AvailabilityResult{Domain: "a.com", Status: domain.StatusUnavailable, Skipped: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewRecord("run", tt.result).Definitive(); got != tt.expected {
+				t.Errorf("Definitive() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRecord_Result(t *testing.T) {
+	price := 12.5
+	original := result("xn--bcher-kva.de", domain.StatusAvailable, nil)
+	original.UnicodeDomain = "bücher.de"
+	original.Pricing = &domain.PricingInfo{RegistrationPrice: &price, Currency: "USD"}
+
+	restored := NewRecord("run", original).Result()
+	if restored.Domain != original.Domain || restored.UnicodeDomain != "bücher.de" || !restored.Available ||
+		restored.Status != domain.StatusAvailable || !restored.CheckedAt.Equal(original.CheckedAt) {
+		t.Errorf("Expected the result to round-trip, got %+v", restored)
+	}
+	if restored.Pricing == nil || *restored.Pricing.RegistrationPrice != 12.5 || restored.Pricing.Currency != "USD" {
+		t.Errorf("Expected the pricing to round-trip, got %+v", restored.Pricing)
+	}
+	if !restored.FromHistory {
+		t.Error("Expected the result to be marked as coming from history")
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{"none", nil, nil, false},
+		{"several", []string{"client=acme", "project = rename"}, map[string]string{"client": "acme", "project": "rename"}, false},
+		{"empty value", []string{"urgent="}, map[string]string{"urgent": ""}, false},
+		{"value with equals", []string{"query=a=b"}, map[string]string{"query": "a=b"}, false},
+		{"no value", []string{"client"}, nil, true},
+		{"no key", []string{"=acme"}, nil, true},
+		{"invalid key", []string{"client name=acme"}, nil, true},
+		{"duplicate key", []string{"client=acme", "client=globex"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := ParseTags(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(tags) != len(tt.expected) {
+				t.Fatalf("ParseTags() = %v, expected %v", tags, tt.expected)
+			}
+			for key, value := range tt.expected {
+				if tags[key] != value {
+					t.Errorf("Expected %s=%q, got %q", key, value, tags[key])
+				}
+			}
+		})
+	}
+}
+
+func TestRecord_HasTags(t *testing.T) {
+	record := NewRecord("run", result("a.com", domain.StatusAvailable, nil))
+	record.Tags = map[string]string{"client": "acme", "project": "rename"}
+
+	tests := []struct {
+		name     string
+		filter   map[string]string
+		expected bool
+	}{
+		{"no filter", nil, true},
+		{"one tag", map[string]string{"client": "acme"}, true},
+		{"all tags", map[string]string{"client": "acme", "project": "rename"}, true},
+		{"other value", map[string]string{"client": "globex"}, false},
+		{"missing tag", map[string]string{"team": "ops"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := record.HasTags(tt.filter); got != tt.expected {
+				t.Errorf("HasTags(%v) = %v, want %v", tt.filter, got, tt.expected)
+			}
+		})
+	}
+}
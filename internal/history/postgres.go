@@ -0,0 +1,75 @@
+package history
+
+import (
+	"database/sql"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	// Registers the "postgres" database/sql driver
+	_ "github.com/lib/pq"
+)
+
+// postgresTable and postgresPriceTable are the checks and TLD prices
+// tables, prefixed because the database is usually shared with other
+// applications
+const (
+	postgresTable      = "r53check_checks"
+	postgresPriceTable = "r53check_prices"
+)
+
+// postgresSchema creates the history tables when they do not exist
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS r53check_checks (
+	id         BIGSERIAL        PRIMARY KEY,
+	run_id     TEXT             NOT NULL,
+	domain     TEXT             NOT NULL,
+	status     TEXT             NOT NULL,
+	definitive BOOLEAN          NOT NULL,
+	price      DOUBLE PRECISION,
+	currency   TEXT,
+	checked_at BIGINT           NOT NULL,
+	record     TEXT             NOT NULL
+);
+CREATE INDEX IF NOT EXISTS r53check_checks_domain ON r53check_checks (domain, id);
+CREATE INDEX IF NOT EXISTS r53check_checks_run ON r53check_checks (run_id);
+
+CREATE TABLE IF NOT EXISTS r53check_prices (
+	id          BIGSERIAL PRIMARY KEY,
+	tld         TEXT      NOT NULL,
+	observed_at BIGINT    NOT NULL,
+	record      TEXT      NOT NULL
+);
+CREATE INDEX IF NOT EXISTS r53check_prices_tld ON r53check_prices (tld, id);
+`
+
+// Postgres is a check history stored in a PostgreSQL database, which lets
+// several machines share one history
+type Postgres struct {
+	sqlStore
+}
+
+// OpenPostgres connects to the database at dsn, a postgres:// URL or
+// key=value connection string, and creates the history table when it does
+// not exist
+func OpenPostgres(dsn string) (*Postgres, error) {
+	if dsn == "" {
+		return nil, customErrors.NewValidationError("", "history.postgres.dsn", "a Postgres connection string is required", nil)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, customErrors.NewValidationError("", "history.postgres.dsn", "invalid Postgres connection string", err)
+	}
+	// The connection string may hold a password, so errors never show it
+	p := &Postgres{sqlStore{db: db, name: "Postgres database", table: postgresTable, priceTable: postgresPriceTable, numbered: true}}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, customErrors.NewSystemError("history", "unable to connect to the Postgres database", err)
+	}
+	if _, err := db.Exec(postgresSchema); err != nil {
+		db.Close()
+		return nil, customErrors.NewSystemError("history", "unable to create history tables", err)
+	}
+	return p, nil
+}
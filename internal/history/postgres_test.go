@@ -0,0 +1,65 @@
+package history
+
+import (
+	"os"
+	"testing"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+func TestSQLStore_Rebind(t *testing.T) {
+	tests := []struct {
+		name     string
+		store    sqlStore
+		expected string
+	}{
+		{"sqlite", sqlStore{table: "checks"}, "SELECT record FROM checks WHERE domain = ? AND status = ?"},
+		{"postgres", sqlStore{table: postgresTable, numbered: true}, "SELECT record FROM r53check_checks WHERE domain = $1 AND status = $2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.store.rebind("SELECT record FROM {table} WHERE domain = ? AND status = ?"); got != tt.expected {
+				t.Errorf("rebind() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOpenPostgres_NoDSN(t *testing.T) {
+	if _, err := OpenPostgres(""); err == nil {
+		t.Error("Expected an error without a connection string")
+	}
+}
+
+// TestPostgres runs against the database in R53CHECK_TEST_POSTGRES_DSN,
+// and is skipped without one. It leaves its records in the table.
+func TestPostgres(t *testing.T) {
+	dsn := os.Getenv("R53CHECK_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("R53CHECK_TEST_POSTGRES_DSN is not set")
+	}
+
+	store, err := OpenPostgres(dsn)
+	if err != nil {
+		t.Fatalf("OpenPostgres() error: %v", err)
+	}
+	defer store.Close()
+
+	name := "r53check-test-" + t.Name() + ".com"
+	if err := store.Append([]Record{
+		checkAt("run-1", name, domain.StatusAvailable, 1),
+		checkAt("run-2", name, domain.StatusUnavailable, 2),
+	}); err != nil {
+		t.Fatalf("Append() error: %v", err)
+	}
+
+	latest, err := store.Latest()
+	if err != nil || latest[name].RunID != "run-2" {
+		t.Errorf("Expected %s from run-2, got %+v, %v", name, latest[name], err)
+	}
+	record, ok, err := store.LastSeen(name, domain.StatusAvailable)
+	if err != nil || !ok || record.RunID != "run-1" {
+		t.Errorf("Expected %s last seen available in run-1, got %+v, %v, %v", name, record, ok, err)
+	}
+}
@@ -0,0 +1,133 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+// PriceRecord is the price of a TLD from the time it was first observed
+// until the next record of the TLD. Prices are only recorded when they
+// change, so the records of a TLD are its price history.
+type PriceRecord struct {
+	TLD          string    `json:"tld"`
+	Currency     string    `json:"currency"`
+	Registration *float64  `json:"registration,omitempty"`
+	Renewal      *float64  `json:"renewal,omitempty"`
+	Transfer     *float64  `json:"transfer,omitempty"`
+	ObservedAt   time.Time `json:"observed_at"`
+}
+
+// NewPriceRecord creates the record of the price of tld observed at at
+func NewPriceRecord(tld string, pricing *domain.PricingInfo, at time.Time) PriceRecord {
+	return PriceRecord{
+		TLD:          strings.ToLower(strings.Trim(tld, ".")),
+		Currency:     pricing.Currency,
+		Registration: pricing.RegistrationPrice,
+		Renewal:      pricing.RenewalPrice,
+		Transfer:     pricing.TransferPrice,
+		ObservedAt:   at,
+	}
+}
+
+// SamePrice reports whether r and other list the same prices
+func (r PriceRecord) SamePrice(other PriceRecord) bool {
+	return r.Currency == other.Currency && samePrice(r.Registration, other.Registration) &&
+		samePrice(r.Renewal, other.Renewal) && samePrice(r.Transfer, other.Transfer)
+}
+
+// Price describes the registration and renewal prices of the record, such
+// as "12.00 USD (renewal 14.00 USD)", with "-" for an unknown price
+func (r PriceRecord) Price() string {
+	price := "-"
+	if r.Registration != nil {
+		price = fmt.Sprintf("%.2f %s", *r.Registration, r.Currency)
+	}
+	if r.Renewal != nil {
+		price += fmt.Sprintf(" (renewal %.2f %s)", *r.Renewal, r.Currency)
+	}
+	return price
+}
+
+// samePrice reports whether two optional prices are equal
+func samePrice(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// PriceChange is a TLD whose price differs from the one last recorded
+type PriceChange struct {
+	TLD      string      `json:"tld"`
+	Previous PriceRecord `json:"previous"`
+	Current  PriceRecord `json:"current"`
+}
+
+// PriceStore is implemented by the stores that also keep the price history
+// of TLDs. A Log does not.
+type PriceStore interface {
+	// AppendPrices records prices, in the order they were observed
+	AppendPrices(records []PriceRecord) error
+
+	// LatestPrices returns the most recent price of every TLD
+	LatestPrices() (map[string]PriceRecord, error)
+
+	// Prices returns every recorded price of tld, oldest first
+	Prices(tld string) ([]PriceRecord, error)
+}
+
+var (
+	_ PriceStore = (*DB)(nil)
+	_ PriceStore = (*Postgres)(nil)
+	_ PriceStore = (*DynamoDB)(nil)
+)
+
+// RecordPrices records the prices that are new or differ from the latest
+// recorded price of their TLD, and returns the changes. A TLD's first
+// recorded price is a baseline, not a change.
+func RecordPrices(store PriceStore, prices []PriceRecord) ([]PriceChange, error) {
+	if len(prices) == 0 {
+		return nil, nil
+	}
+	latest, err := store.LatestPrices()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []PriceRecord
+	var changes []PriceChange
+	for _, record := range prices {
+		previous, seen := latest[record.TLD]
+		if seen && previous.SamePrice(record) {
+			continue
+		}
+		changed = append(changed, record)
+		latest[record.TLD] = record
+		if seen {
+			changes = append(changes, PriceChange{TLD: record.TLD, Previous: previous, Current: record})
+		}
+	}
+	return changes, store.AppendPrices(changed)
+}
+
+// ResultPrices returns the price of the TLD of each priced result, once
+// per TLD, observed when the result was checked
+func ResultPrices(results []*domain.AvailabilityResult) []PriceRecord {
+	seen := make(map[string]bool)
+	var prices []PriceRecord
+	for _, result := range results {
+		if result == nil || result.Pricing == nil || result.FromHistory {
+			continue
+		}
+		tld := domain.EffectiveTLD(result.Domain)
+		if tld == "" || seen[tld] {
+			continue
+		}
+		seen[tld] = true
+		prices = append(prices, NewPriceRecord(tld, result.Pricing, result.CheckedAt))
+	}
+	return prices
+}
@@ -0,0 +1,96 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+// priceAt returns the price of tld observed day days after the test day
+func priceAt(tld string, registration float64, day int) PriceRecord {
+	return PriceRecord{
+		TLD:          tld,
+		Currency:     "USD",
+		Registration: &registration,
+		ObservedAt:   time.Date(2025, 1, 15+day, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestPriceRecord_SamePrice(t *testing.T) {
+	renewal := 14.0
+	withRenewal := priceAt("com", 12, 0)
+	withRenewal.Renewal = &renewal
+
+	tests := []struct {
+		name  string
+		a, b  PriceRecord
+		equal bool
+	}{
+		{"same price on different days", priceAt("com", 12, 0), priceAt("com", 12, 3), true},
+		{"registration changed", priceAt("com", 12, 0), priceAt("com", 13, 1), false},
+		{"renewal listed", priceAt("com", 12, 0), withRenewal, false},
+		{"currency changed", priceAt("com", 12, 0), PriceRecord{TLD: "com", Currency: "EUR", Registration: priceAt("com", 12, 0).Registration}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.SamePrice(tt.b); got != tt.equal {
+				t.Errorf("SamePrice() = %v, expected %v", got, tt.equal)
+			}
+		})
+	}
+}
+
+func TestRecordPrices(t *testing.T) {
+	store := NewDynamoDB(newFakeDynamoDB(), "history")
+
+	// The first prices are a baseline
+	changes, err := RecordPrices(store, []PriceRecord{priceAt("com", 12, 0), priceAt("io", 39, 0)})
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("Expected a baseline without changes, got %+v, %v", changes, err)
+	}
+
+	// Only the changed price is recorded and reported
+	changes, err = RecordPrices(store, []PriceRecord{priceAt("com", 12, 1), priceAt("io", 45, 1), priceAt("dev", 15, 1)})
+	if err != nil {
+		t.Fatalf("RecordPrices() error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].TLD != "io" || *changes[0].Previous.Registration != 39 || *changes[0].Current.Registration != 45 {
+		t.Errorf("Expected the .io change from 39 to 45, got %+v", changes)
+	}
+
+	prices, err := store.Prices("com")
+	if err != nil || len(prices) != 1 || !prices[0].ObservedAt.Equal(priceAt("com", 12, 0).ObservedAt) {
+		t.Errorf("Expected the unchanged .com price to be recorded once, got %+v, %v", prices, err)
+	}
+	prices, err = store.Prices("io")
+	if err != nil || len(prices) != 2 || *prices[0].Registration != 39 || *prices[1].Registration != 45 {
+		t.Errorf("Expected both .io prices oldest first, got %+v, %v", prices, err)
+	}
+
+	latest, err := store.LatestPrices()
+	if err != nil || len(latest) != 3 || *latest["io"].Registration != 45 {
+		t.Errorf("Expected the latest price of 3 TLDs, got %+v, %v", latest, err)
+	}
+}
+
+func TestResultPrices(t *testing.T) {
+	price := 12.0
+	pricing := &domain.PricingInfo{RegistrationPrice: &price, Currency: "USD"}
+
+	first := result("a.com", domain.StatusAvailable, nil)
+	first.Pricing = pricing
+	second := result("b.com", domain.StatusAvailable, nil)
+	second.Pricing = pricing
+	reused := result("c.co.uk", domain.StatusAvailable, nil)
+	reused.Pricing = pricing
+	reused.FromHistory = true
+
+	prices := ResultPrices([]*domain.AvailabilityResult{
+		first, second, reused, nil, result("taken.io", domain.StatusUnavailable, nil),
+	})
+	if len(prices) != 1 || prices[0].TLD != "com" || *prices[0].Registration != 12 || !prices[0].ObservedAt.Equal(first.CheckedAt) {
+		t.Errorf("Expected one .com price, got %+v", prices)
+	}
+}
@@ -0,0 +1,100 @@
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	// Registers the "sqlite" database/sql driver, a pure Go SQLite that
+	// builds without cgo
+	_ "modernc.org/sqlite"
+)
+
+// schemaVersion is stored in the database's user_version and bumped with
+// every schema change
+const schemaVersion = 2
+
+// schema creates the history tables. Every statement can run again on an
+// older database, so migrating runs the whole schema.
+const schema = `
+CREATE TABLE IF NOT EXISTS checks (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id     TEXT    NOT NULL,
+	domain     TEXT    NOT NULL,
+	status     TEXT    NOT NULL,
+	definitive INTEGER NOT NULL,
+	price      REAL,
+	currency   TEXT,
+	checked_at INTEGER NOT NULL,
+	record     TEXT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS checks_domain ON checks (domain, id);
+CREATE INDEX IF NOT EXISTS checks_run ON checks (run_id);
+
+CREATE TABLE IF NOT EXISTS prices (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	tld         TEXT    NOT NULL,
+	observed_at INTEGER NOT NULL,
+	record      TEXT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS prices_tld ON prices (tld, id);
+`
+
+// DB is a check history stored in a SQLite database
+type DB struct {
+	sqlStore
+	path string
+}
+
+// OpenDB opens the history database at path, creating it and its
+// directory when they do not exist
+func OpenDB(path string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, customErrors.NewSystemError("history", "unable to create history directory", err)
+	}
+
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate")
+	if err != nil {
+		return nil, customErrors.NewSystemError("history", "unable to open history "+path, err)
+	}
+	// SQLite allows one writer at a time, so queue writes in the pool
+	db.SetMaxOpenConns(1)
+
+	h := &DB{sqlStore: sqlStore{db: db, name: path, table: "checks", priceTable: "prices"}, path: path}
+	if err := h.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return h, nil
+}
+
+// migrate brings the schema up to date
+func (h *DB) migrate() error {
+	var version int
+	if err := h.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return customErrors.NewSystemError("history", "unable to read history "+h.path, err)
+	}
+	if version > schemaVersion {
+		return customErrors.NewSystemError("history",
+			"history "+h.path+" was created by a newer version of r53check", nil)
+	}
+	if version == schemaVersion {
+		return nil
+	}
+
+	if _, err := h.db.Exec(schema); err != nil {
+		return customErrors.NewSystemError("history", "unable to create history tables", err)
+	}
+	if _, err := h.db.Exec(fmt.Sprintf("PRAGMA user_version = %d", schemaVersion)); err != nil {
+		return customErrors.NewSystemError("history", "unable to create history tables", err)
+	}
+	return nil
+}
+
+// Path returns the file the database is stored in
+func (h *DB) Path() string {
+	return h.path
+}
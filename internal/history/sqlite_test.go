@@ -0,0 +1,163 @@
+//go:build cgo
+
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	db, err := OpenDB(filepath.Join(t.TempDir(), "data", "history.db"))
+	if err != nil {
+		t.Fatalf("OpenDB() error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestDB_AppendAndLatest(t *testing.T) {
+	db := openTestDB(t)
+
+	latest, err := db.Latest()
+	if err != nil || len(latest) != 0 {
+		t.Fatalf("Expected an empty history, got %v, %v", latest, err)
+	}
+
+	price := 12.5
+	priced := result("b.com", domain.StatusAvailable, nil)
+	priced.Pricing = &domain.PricingInfo{RegistrationPrice: &price, Currency: "USD"}
+
+	if err := db.Append([]Record{
+		NewRecord("run-1", result("a.com", domain.StatusUnavailable, nil)),
+		NewRecord("run-1", priced),
+	}); err != nil {
+		t.Fatalf("Append() error: %v", err)
+	}
+	if err := db.Append([]Record{
+		NewRecord("run-2", result("a.com", domain.StatusAvailable, nil)),
+		NewRecord("run-2", result("b.com", domain.StatusUnknown, errors.New("throttled"))),
+	}); err != nil {
+		t.Fatalf("Append() error: %v", err)
+	}
+
+	latest, err = db.Latest()
+	if err != nil {
+		t.Fatalf("Latest() error: %v", err)
+	}
+	if got := latest["a.com"]; got.Status != "AVAILABLE" || got.RunID != "run-2" {
+		t.Errorf("Expected a.com AVAILABLE from run-2, got %+v", got)
+	}
+	got := latest["b.com"]
+	if got.Status != "AVAILABLE" || got.RunID != "run-1" {
+		t.Errorf("Expected b.com AVAILABLE from run-1, got %+v", got)
+	}
+	if got.Pricing == nil || *got.Pricing.Registration != 12.5 || !got.CheckedAt.Equal(priced.CheckedAt) {
+		t.Errorf("Expected the full record to round-trip, got %+v", got)
+	}
+}
+
+func TestDB_Queries(t *testing.T) {
+	db := openTestDB(t)
+	db.Append([]Record{
+		NewRecord("run-1", result("a.com", domain.StatusAvailable, nil)),
+		NewRecord("run-2", result("a.com", domain.StatusUnavailable, nil)),
+		NewRecord("run-3", result("a.com", domain.StatusAvailable, nil)),
+		NewRecord("run-4", result("a.com", domain.StatusUnavailable, nil)),
+		NewRecord("run-4", result("b.com", domain.StatusUnavailable, nil)),
+	})
+
+	records, err := db.Records("a.com")
+	if err != nil {
+		t.Fatalf("Records() error: %v", err)
+	}
+	if len(records) != 4 || records[0].RunID != "run-1" || records[3].RunID != "run-4" {
+		t.Errorf("Expected the four checks of a.com oldest first, got %+v", records)
+	}
+
+	record, ok, err := db.LastSeen("a.com", domain.StatusAvailable)
+	if err != nil || !ok || record.RunID != "run-3" {
+		t.Errorf("Expected a.com last seen available in run-3, got %+v, %v, %v", record, ok, err)
+	}
+	if _, ok, err := db.LastSeen("b.com", domain.StatusAvailable); ok || err != nil {
+		t.Errorf("Expected b.com never seen available, got %v, %v", ok, err)
+	}
+}
+
+func TestDB_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	db, err := OpenDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Append([]Record{NewRecord("run-1", result("a.com", domain.StatusAvailable, nil))})
+	db.Close()
+
+	if db, err = OpenDB(path); err != nil {
+		t.Fatalf("Reopening the database failed: %v", err)
+	}
+	defer db.Close()
+	if latest, _ := db.Latest(); latest["a.com"].RunID != "run-1" {
+		t.Errorf("Expected records to persist, got %v", latest)
+	}
+}
+
+func TestOpenDB_NewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	db, err := OpenDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.db.Exec("PRAGMA user_version = 99")
+	db.Close()
+
+	if _, err := OpenDB(path); err == nil {
+		t.Error("Expected an error for a database from a newer version")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected the database to be left in place: %v", err)
+	}
+}
+
+func TestDB_Prices(t *testing.T) {
+	db := openTestDB(t)
+
+	if err := db.AppendPrices([]PriceRecord{priceAt("com", 12, 0), priceAt("io", 39, 0), priceAt("io", 45, 1)}); err != nil {
+		t.Fatalf("AppendPrices() error: %v", err)
+	}
+
+	latest, err := db.LatestPrices()
+	if err != nil || len(latest) != 2 || *latest["io"].Registration != 45 {
+		t.Errorf("Expected the latest price of 2 TLDs, got %+v, %v", latest, err)
+	}
+	prices, err := db.Prices("io")
+	if err != nil || len(prices) != 2 || *prices[0].Registration != 39 {
+		t.Errorf("Expected both .io prices oldest first, got %+v, %v", prices, err)
+	}
+}
+
+func TestOpenDB_MigratesPrices(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	db, err := OpenDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A database from before prices were recorded
+	db.db.Exec("DROP TABLE prices")
+	db.db.Exec("PRAGMA user_version = 1")
+	db.Close()
+
+	if db, err = OpenDB(path); err != nil {
+		t.Fatalf("Migrating the database failed: %v", err)
+	}
+	defer db.Close()
+	if err := db.AppendPrices([]PriceRecord{priceAt("com", 12, 0)}); err != nil {
+		t.Errorf("Expected the prices table after migrating, got %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+// sqlStore implements Store on a database/sql database holding a checks
+// table, sharing the queries between SQLite and Postgres. Each check keeps
+// its full JSON record alongside the columns used for querying.
+type sqlStore struct {
+	db *sql.DB
+
+	// name identifies the database in errors, without any credentials
+	name string
+
+	// table is the name of the checks table
+	table string
+
+	// priceTable is the name of the TLD prices table
+	priceTable string
+
+	// numbered uses $1, $2, ... placeholders instead of ?
+	numbered bool
+}
+
+// rebind expands {table} and {prices} in query and converts its ? placeholders to the
+// database's style
+func (s *sqlStore) rebind(query string) string {
+	query = strings.ReplaceAll(query, "{table}", s.table)
+	query = strings.ReplaceAll(query, "{prices}", s.priceTable)
+	if !s.numbered {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Close closes the database
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+// Append records checks in a single transaction
+func (s *sqlStore) Append(records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return customErrors.NewSystemError("history", "unable to write history "+s.name, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(s.rebind(`INSERT INTO {table} (run_id, domain, status, definitive, price, currency, checked_at, record)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`))
+	if err != nil {
+		return customErrors.NewSystemError("history", "unable to write history "+s.name, err)
+	}
+	defer stmt.Close()
+
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return customErrors.NewSystemError("history", "unable to encode history record", err)
+		}
+
+		var price sql.NullFloat64
+		var currency sql.NullString
+		if record.Pricing != nil {
+			currency = sql.NullString{String: record.Pricing.Currency, Valid: true}
+			if record.Pricing.Registration != nil {
+				price = sql.NullFloat64{Float64: *record.Pricing.Registration, Valid: true}
+			}
+		}
+
+		if _, err := stmt.Exec(record.RunID, record.Domain, record.Status, record.Definitive(),
+			price, currency, record.CheckedAt.UnixNano(), string(data)); err != nil {
+			return customErrors.NewSystemError("history", "unable to write history "+s.name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return customErrors.NewSystemError("history", "unable to write history "+s.name, err)
+	}
+	return nil
+}
+
+// Latest returns the most recent definitive record of every domain
+func (s *sqlStore) Latest() (map[string]Record, error) {
+	records, err := s.query(`SELECT record FROM {table} WHERE id IN
+		(SELECT MAX(id) FROM {table} WHERE definitive GROUP BY domain)`)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]Record, len(records))
+	for _, record := range records {
+		latest[record.Domain] = record
+	}
+	return latest, nil
+}
+
+// Records returns every check of name, oldest first
+func (s *sqlStore) Records(name string) ([]Record, error) {
+	return s.query(`SELECT record FROM {table} WHERE domain = ? ORDER BY id`, name)
+}
+
+// LastSeen returns the most recent check of name with status, and false
+// when the domain was never seen with it
+func (s *sqlStore) LastSeen(name string, status domain.AvailabilityStatus) (Record, bool, error) {
+	records, err := s.query(`SELECT record FROM {table} WHERE domain = ? AND status = ? ORDER BY id DESC LIMIT 1`,
+		name, string(status))
+	if err != nil || len(records) == 0 {
+		return Record{}, false, err
+	}
+	return records[0], true, nil
+}
+
+// query runs a query selecting the record column
+func (s *sqlStore) query(query string, args ...any) ([]Record, error) {
+	rows, err := s.db.Query(s.rebind(query), args...)
+	if err != nil {
+		return nil, customErrors.NewSystemError("history", "unable to read history "+s.name, err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, customErrors.NewSystemError("history", "unable to read history "+s.name, err)
+		}
+		var record Record
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, customErrors.NewSystemError("history", "corrupt record in history "+s.name, err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, customErrors.NewSystemError("history", "unable to read history "+s.name, err)
+	}
+	return records, nil
+}
+
+// AppendPrices records TLD prices in a single transaction
+func (s *sqlStore) AppendPrices(records []PriceRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return customErrors.NewSystemError("history", "unable to write history "+s.name, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(s.rebind(`INSERT INTO {prices} (tld, observed_at, record) VALUES (?, ?, ?)`))
+	if err != nil {
+		return customErrors.NewSystemError("history", "unable to write history "+s.name, err)
+	}
+	defer stmt.Close()
+
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return customErrors.NewSystemError("history", "unable to encode price record", err)
+		}
+		if _, err := stmt.Exec(record.TLD, record.ObservedAt.UnixNano(), string(data)); err != nil {
+			return customErrors.NewSystemError("history", "unable to write history "+s.name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return customErrors.NewSystemError("history", "unable to write history "+s.name, err)
+	}
+	return nil
+}
+
+// LatestPrices returns the most recent price of every TLD
+func (s *sqlStore) LatestPrices() (map[string]PriceRecord, error) {
+	records, err := s.queryPrices(`SELECT record FROM {prices} WHERE id IN
+		(SELECT MAX(id) FROM {prices} GROUP BY tld)`)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]PriceRecord, len(records))
+	for _, record := range records {
+		latest[record.TLD] = record
+	}
+	return latest, nil
+}
+
+// Prices returns every recorded price of tld, oldest first
+func (s *sqlStore) Prices(tld string) ([]PriceRecord, error) {
+	return s.queryPrices(`SELECT record FROM {prices} WHERE tld = ? ORDER BY id`, tld)
+}
+
+// queryPrices runs a query selecting the record column of the prices table
+func (s *sqlStore) queryPrices(query string, args ...any) ([]PriceRecord, error) {
+	rows, err := s.db.Query(s.rebind(query), args...)
+	if err != nil {
+		return nil, customErrors.NewSystemError("history", "unable to read history "+s.name, err)
+	}
+	defer rows.Close()
+
+	var records []PriceRecord
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, customErrors.NewSystemError("history", "unable to read history "+s.name, err)
+		}
+		var record PriceRecord
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, customErrors.NewSystemError("history", "corrupt price record in history "+s.name, err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, customErrors.NewSystemError("history", "unable to read history "+s.name, err)
+	}
+	return records, nil
+}
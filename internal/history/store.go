@@ -0,0 +1,30 @@
+package history
+
+import "github.com/abakermi/r53check/internal/domain"
+
+// Store is where check history is kept. The SQLite database is the
+// default; DynamoDB and Postgres let several machines share one history.
+type Store interface {
+	// Append records checks, in the order they were made
+	Append(records []Record) error
+
+	// Latest returns the most recent definitive record of every domain
+	Latest() (map[string]Record, error)
+
+	// Records returns every check of a domain, oldest first
+	Records(name string) ([]Record, error)
+
+	// LastSeen returns the most recent check of a domain with status, and
+	// false when the domain was never seen with it
+	LastSeen(name string, status domain.AvailabilityStatus) (Record, bool, error)
+
+	// Close releases the store's connections
+	Close() error
+}
+
+var (
+	_ Store = (*Log)(nil)
+	_ Store = (*DB)(nil)
+	_ Store = (*Postgres)(nil)
+	_ Store = (*DynamoDB)(nil)
+)
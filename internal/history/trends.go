@@ -0,0 +1,67 @@
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+// Period is a stretch of consecutive checks that found a domain with the
+// same status
+type Period struct {
+	Status string `json:"status"`
+
+	// From and To are the first and last check with the status. The
+	// domain changed status at some point between the last check of the
+	// previous period and From.
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Checks int       `json:"checks"`
+}
+
+// Trend is the status timeline of a domain
+type Trend struct {
+	Domain  string   `json:"domain"`
+	Periods []Period `json:"periods"`
+
+	// Checks counts every check, and Failed those that did not settle the
+	// domain's status and so are left out of the periods
+	Checks int `json:"checks"`
+	Failed int `json:"failed"`
+
+	// Changes counts the status changes between periods
+	Changes int `json:"changes"`
+}
+
+// NewTrend builds the timeline of name from its records, in the order
+// they were checked
+func NewTrend(name string, records []Record) Trend {
+	sorted := make([]Record, len(records))
+	copy(sorted, records)
+	// Machines sharing a history may record their checks out of order
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CheckedAt.Before(sorted[j].CheckedAt)
+	})
+
+	trend := Trend{Domain: name, Periods: []Period{}, Checks: len(sorted)}
+	for _, record := range sorted {
+		if !record.Definitive() {
+			trend.Failed++
+			continue
+		}
+
+		last := len(trend.Periods) - 1
+		if last >= 0 && trend.Periods[last].Status == record.Status {
+			trend.Periods[last].To = record.CheckedAt
+			trend.Periods[last].Checks++
+			continue
+		}
+		trend.Periods = append(trend.Periods, Period{
+			Status: record.Status,
+			From:   record.CheckedAt,
+			To:     record.CheckedAt,
+			Checks: 1,
+		})
+	}
+	trend.Changes = max(len(trend.Periods)-1, 0)
+	return trend
+}
@@ -0,0 +1,66 @@
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+func TestNewTrend(t *testing.T) {
+	failed := NewRecord("run-3", result("drop.com", domain.StatusUnknown, errors.New("throttled")))
+	failed.CheckedAt = failed.CheckedAt.Add(3 * time.Minute)
+
+	tests := []struct {
+		name     string
+		records  []Record
+		statuses []string
+		checks   []int
+		failed   int
+	}{
+		{"no history", nil, nil, nil, 0},
+		{
+			name: "steady",
+			records: []Record{
+				checkAt("run-1", "drop.com", domain.StatusUnavailable, 1),
+				checkAt("run-2", "drop.com", domain.StatusUnavailable, 2),
+			},
+			statuses: []string{"UNAVAILABLE"},
+			checks:   []int{2},
+		},
+		{
+			name: "flips, out of order and with a failure",
+			records: []Record{
+				checkAt("run-5", "drop.com", domain.StatusUnavailable, 5),
+				checkAt("run-1", "drop.com", domain.StatusUnavailable, 1),
+				checkAt("run-2", "drop.com", domain.StatusUnavailable, 2),
+				failed,
+				checkAt("run-4", "drop.com", domain.StatusAvailable, 4),
+			},
+			statuses: []string{"UNAVAILABLE", "AVAILABLE", "UNAVAILABLE"},
+			checks:   []int{2, 1, 1},
+			failed:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trend := NewTrend("drop.com", tt.records)
+			if trend.Checks != len(tt.records) || trend.Failed != tt.failed {
+				t.Errorf("Expected %d checks with %d failed, got %d and %d", len(tt.records), tt.failed, trend.Checks, trend.Failed)
+			}
+			if len(trend.Periods) != len(tt.statuses) || trend.Changes != max(len(tt.statuses)-1, 0) {
+				t.Fatalf("Expected periods %v, got %+v", tt.statuses, trend.Periods)
+			}
+			for i, period := range trend.Periods {
+				if period.Status != tt.statuses[i] || period.Checks != tt.checks[i] {
+					t.Errorf("Period %d: expected %s with %d checks, got %+v", i, tt.statuses[i], tt.checks[i], period)
+				}
+				if period.To.Before(period.From) {
+					t.Errorf("Period %d ends before it starts: %+v", i, period)
+				}
+			}
+		})
+	}
+}
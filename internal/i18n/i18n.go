@@ -0,0 +1,155 @@
+// Package i18n translates the messages r53check shows people: check results
+// and the guidance given for errors. English messages are defined in code
+// next to where they are used; translations live in the message catalogs
+// under locales, named active.<language>.json and keyed by message ID.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// Message is a translatable message. Other holds the English text, a
+// text/template; One is its singular form for messages with a plural count.
+type Message = goi18n.Message
+
+// DefaultLanguage is used when no supported language is asked for
+const DefaultLanguage = "en"
+
+//go:embed locales/*.json
+var catalogs embed.FS
+
+// bundle holds the translations of every message catalog
+var bundle = loadBundle()
+
+// languages lists the supported languages, DefaultLanguage first
+var languages = []string{DefaultLanguage, "es", "fr", "ja"}
+
+// english renders messages for a nil Localizer
+var english = &Localizer{lang: DefaultLanguage, localizer: goi18n.NewLocalizer(bundle, DefaultLanguage)}
+
+func loadBundle() *goi18n.Bundle {
+	b := goi18n.NewBundle(language.English)
+	b.RegisterUnmarshalFunc("json", json.Unmarshal)
+
+	files, err := catalogs.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	for _, file := range files {
+		name := path.Join("locales", file.Name())
+		data, err := catalogs.ReadFile(name)
+		if err != nil {
+			panic(err)
+		}
+		// The catalogs are embedded, so a broken one is a build mistake
+		b.MustParseMessageFileBytes(data, name)
+	}
+	return b
+}
+
+// Languages returns the supported languages, such as "es"
+func Languages() []string {
+	return append([]string(nil), languages...)
+}
+
+// Localizer renders messages in one language. A nil Localizer renders
+// them in English.
+type Localizer struct {
+	lang      string
+	localizer *goi18n.Localizer
+}
+
+// New creates a Localizer for lang, a language such as "ja", "fr-CA" or a
+// locale such as "es_ES.UTF-8". An empty lang uses DefaultLanguage.
+func New(lang string) (*Localizer, error) {
+	if lang == "" {
+		return english, nil
+	}
+	base, ok := parseLanguage(lang)
+	if !ok {
+		return nil, customErrors.NewValidationError("", "lang",
+			fmt.Sprintf("unsupported language %q; use one of %s%s", lang, strings.Join(languages, ", "),
+				customErrors.DidYouMean(lang, languages)), nil)
+	}
+	return &Localizer{lang: base, localizer: goi18n.NewLocalizer(bundle, base)}, nil
+}
+
+// Detect returns the supported language of the user's locale, read from
+// LC_ALL, LC_MESSAGES and LANG in that order of precedence, or
+// DefaultLanguage when the locale is unset or not supported
+func Detect(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := getenv(name)
+		if locale == "" {
+			continue
+		}
+		if base, ok := parseLanguage(locale); ok {
+			return base
+		}
+		return DefaultLanguage
+	}
+	return DefaultLanguage
+}
+
+// parseLanguage returns the supported base language of a language tag or
+// POSIX locale, such as "fr" for "fr_FR.UTF-8@euro"
+func parseLanguage(lang string) (string, bool) {
+	lang, _, _ = strings.Cut(lang, ".")
+	lang, _, _ = strings.Cut(lang, "@")
+	tag, err := language.Parse(strings.ReplaceAll(lang, "_", "-"))
+	if err != nil {
+		return "", false
+	}
+	base, _ := tag.Base()
+	for _, supported := range languages {
+		if base.String() == supported {
+			return supported, true
+		}
+	}
+	return "", false
+}
+
+// Language returns the language messages are rendered in
+func (l *Localizer) Language() string {
+	if l == nil {
+		return DefaultLanguage
+	}
+	return l.lang
+}
+
+// Localize renders msg with data. Messages without a translation are
+// rendered in English.
+func (l *Localizer) Localize(msg *Message, data map[string]interface{}) string {
+	return l.localize(&goi18n.LocalizeConfig{DefaultMessage: msg, TemplateData: data})
+}
+
+// Plural renders the form of msg for count with data, which also gets
+// count as Count
+func (l *Localizer) Plural(msg *Message, count int, data map[string]interface{}) string {
+	templateData := map[string]interface{}{"Count": count}
+	for key, value := range data {
+		templateData[key] = value
+	}
+	return l.localize(&goi18n.LocalizeConfig{DefaultMessage: msg, TemplateData: templateData, PluralCount: count})
+}
+
+func (l *Localizer) localize(config *goi18n.LocalizeConfig) string {
+	if l == nil {
+		l = english
+	}
+	text, err := l.localizer.Localize(config)
+	if text == "" && err != nil {
+		// Only a broken template gets here; its source beats no message
+		return config.DefaultMessage.Other
+	}
+	return text
+}
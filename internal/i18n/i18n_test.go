@@ -0,0 +1,152 @@
+package i18n
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		lang     string
+		expected string
+		wantErr  bool
+	}{
+		{"", "en", false},
+		{"es", "es", false},
+		{"fr-CA", "fr", false},
+		{"ja_JP.UTF-8", "ja", false},
+		{"en_US.UTF-8@posix", "en", false},
+		{"de", "", true},
+		{"not a language", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			localizer, err := New(tt.lang)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New(%q) error = %v, wantErr %v", tt.lang, err, tt.wantErr)
+			}
+			if err == nil && localizer.Language() != tt.expected {
+				t.Errorf("New(%q).Language() = %q, want %q", tt.lang, localizer.Language(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{"unset", nil, "en"},
+		{"LANG", map[string]string{"LANG": "fr_FR.UTF-8"}, "fr"},
+		{"LC_MESSAGES over LANG", map[string]string{"LC_MESSAGES": "ja_JP.UTF-8", "LANG": "fr_FR.UTF-8"}, "ja"},
+		{"LC_ALL over all", map[string]string{"LC_ALL": "es_MX.UTF-8", "LC_MESSAGES": "ja_JP.UTF-8", "LANG": "fr_FR.UTF-8"}, "es"},
+		{"C locale", map[string]string{"LANG": "C.UTF-8"}, "en"},
+		{"unsupported locale", map[string]string{"LC_ALL": "de_DE.UTF-8", "LANG": "fr_FR.UTF-8"}, "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(name string) string { return tt.env[name] }
+			if got := Detect(getenv); got != tt.expected {
+				t.Errorf("Detect() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLocalizer_Localize(t *testing.T) {
+	available := &Message{ID: "ResultAvailable", Other: "{{.Domain}} is AVAILABLE for registration"}
+	untranslated := &Message{ID: "NotInAnyCatalog", Other: "only in English: {{.Domain}}"}
+	data := map[string]interface{}{"Domain": "example.com"}
+
+	tests := []struct {
+		name     string
+		lang     string
+		msg      *Message
+		expected string
+	}{
+		{"English", "en", available, "example.com is AVAILABLE for registration"},
+		{"Spanish", "es", available, "example.com está DISPONIBLE para registrar"},
+		{"French", "fr", available, "example.com est DISPONIBLE à l'enregistrement"},
+		{"Japanese", "ja", available, "example.com は登録可能です (AVAILABLE)"},
+		{"falls back to English", "fr", untranslated, "only in English: example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			localizer, err := New(tt.lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := localizer.Localize(tt.msg, data); got != tt.expected {
+				t.Errorf("Localize() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	var nilLocalizer *Localizer
+	if got := nilLocalizer.Localize(available, data); got != "example.com is AVAILABLE for registration" {
+		t.Errorf("nil Localizer rendered %q, want English", got)
+	}
+}
+
+func TestLocalizer_Plural(t *testing.T) {
+	msg := &Message{ID: "ErrorGroup", One: "{{.Count}} domain {{.Label}}: {{.Domains}}", Other: "{{.Count}} domains {{.Label}}: {{.Domains}}"}
+	data := map[string]interface{}{"Label": "timed out", "Domains": "a.com"}
+
+	tests := []struct {
+		lang     string
+		count    int
+		expected string
+	}{
+		{"en", 1, "1 domain timed out: a.com"},
+		{"en", 3, "3 domains timed out: a.com"},
+		{"es", 1, "1 dominio timed out: a.com"},
+		{"es", 3, "3 dominios timed out: a.com"},
+		{"ja", 3, "timed outドメイン 3 件: a.com"},
+	}
+
+	for _, tt := range tests {
+		localizer, err := New(tt.lang)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := localizer.Plural(msg, tt.count, data); got != tt.expected {
+			t.Errorf("Plural(%s, %d) = %q, want %q", tt.lang, tt.count, got, tt.expected)
+		}
+	}
+}
+
+// TestCatalogs checks that every catalog translates the same messages, so a
+// message added to one language is not forgotten in the others
+func TestCatalogs(t *testing.T) {
+	var reference []string
+	for _, lang := range Languages()[1:] {
+		name := path.Join("locales", "active."+lang+".json")
+		data, err := catalogs.ReadFile(name)
+		if err != nil {
+			t.Fatalf("missing catalog for %s: %v", lang, err)
+		}
+		var messages map[string]interface{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			t.Fatalf("invalid catalog %s: %v", name, err)
+		}
+
+		ids := make([]string, 0, len(messages))
+		for id := range messages {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		if reference == nil {
+			reference = ids
+		} else if !reflect.DeepEqual(ids, reference) {
+			t.Errorf("catalog %s translates different messages than the others", name)
+		}
+	}
+}
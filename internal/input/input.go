@@ -0,0 +1,309 @@
+// Package input reads lists of domains: plain text with one domain per
+// line, a column of a CSV file or Excel workbook, or the names of a BIND zone
+// file. Each domain can carry a note, such as "client preference #1", that is
+// kept with its result.
+package input
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/errors"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Format is the layout of a domains file
+type Format string
+
+const (
+	FormatText Format = "text"     // One domain per line, optionally followed by a comma and a note, with # comments
+	FormatCSV  Format = "csv"      // Comma-separated values
+	FormatXLSX Format = "xlsx"     // Excel workbook
+	FormatZone Format = "zonefile" // BIND zone file, read for the registrable domains of its names
+)
+
+// Formats are the formats a domains file can be read as
+var Formats = []Format{FormatText, FormatCSV, FormatXLSX, FormatZone}
+
+// defaultColumns are the header names of the column read when none is
+// chosen, matched without regard to case
+var defaultColumns = []string{"domain", "domains", "domain name"}
+
+// noteColumns are the header names of the column holding notes
+var noteColumns = []string{"note", "notes"}
+
+// Entry is a domain of a list and the note given next to it, if any
+type Entry struct {
+	Domain string
+	Note   string
+}
+
+// Domains returns the domains of entries
+func Domains(entries []Entry) []string {
+	if entries == nil {
+		return nil
+	}
+	domains := make([]string, len(entries))
+	for i, entry := range entries {
+		domains[i] = entry.Domain
+	}
+	return domains
+}
+
+// ParseFormat converts an --input-format value into a Format
+func ParseFormat(value string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(value)))
+	if slices.Contains(Formats, format) {
+		return format, nil
+	}
+	choices := make([]string, len(Formats))
+	for i, f := range Formats {
+		choices[i] = string(f)
+	}
+	return "", errors.NewValidationError("", "input-format",
+		fmt.Sprintf("invalid input format %q: must be one of %s%s", value, strings.Join(choices, ", "),
+			errors.DidYouMean(value, choices)), nil)
+}
+
+// DetectFormat returns the format of filename from its extension; files
+// that are neither CSV, Excel nor zone files are read as text
+func DetectFormat(filename string) Format {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV
+	case ".xlsx":
+		return FormatXLSX
+	case ".zone":
+		return FormatZone
+	default:
+		return FormatText
+	}
+}
+
+// Read returns the domains in r. For CSV and Excel input, column picks the
+// field holding the domains; see Column.
+func Read(r io.Reader, format Format, column string) ([]string, error) {
+	entries, err := ReadEntries(r, format, column)
+	return Domains(entries), err
+}
+
+// ReadEntries returns the domains in r with their notes. In text, a note
+// follows its domain after a comma; CSV and Excel input take notes from the
+// column described by Entries.
+func ReadEntries(r io.Reader, format Format, column string) ([]Entry, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r, column)
+	case FormatXLSX:
+		return readXLSX(r, column)
+	case FormatZone:
+		if column != "" {
+			return nil, fmt.Errorf("a column can only be chosen in CSV and Excel files")
+		}
+		return readZone(r)
+	default:
+		if column != "" {
+			return nil, fmt.Errorf("a column can only be chosen in CSV and Excel files")
+		}
+		return readText(r)
+	}
+}
+
+// ReadText reads one domain per line, skipping blank lines and # comments.
+// Text after the first comma of a line is a note and left out.
+func ReadText(r io.Reader) ([]string, error) {
+	entries, err := readText(r)
+	return Domains(entries), err
+}
+
+// ReadCSV reads the domains in column of CSV data
+func ReadCSV(r io.Reader, column string) ([]string, error) {
+	entries, err := readCSV(r, column)
+	return Domains(entries), err
+}
+
+// ReadXLSX reads the domains in column of the sheet an Excel workbook opens
+// on
+func ReadXLSX(r io.Reader, column string) ([]string, error) {
+	entries, err := readXLSX(r, column)
+	return Domains(entries), err
+}
+
+// readText reads the entries of ReadText with their notes
+func readText(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// Skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, note, _ := strings.Cut(line, ",")
+		if name = strings.TrimSpace(name); name != "" {
+			entries = append(entries, Entry{Domain: name, Note: strings.TrimSpace(note)})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading domains: %w", err)
+	}
+
+	return entries, nil
+}
+
+// readCSV reads the entries of ReadCSV with their notes
+func readCSV(r io.Reader, column string) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	// Spreadsheet programs start UTF-8 CSV exports with a byte order mark
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+	}
+	return Entries(rows, column)
+}
+
+// readXLSX reads the entries of ReadXLSX with their notes
+func readXLSX(r io.Reader, column string) ([]Entry, error) {
+	workbook, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Excel workbook: %w", err)
+	}
+	defer workbook.Close()
+
+	sheet := workbook.GetSheetName(workbook.GetActiveSheetIndex())
+	rows, err := workbook.GetRows(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read sheet %q: %w", sheet, err)
+	}
+	return Entries(rows, column)
+}
+
+// Column returns the non-empty cells of one column of rows. column is a
+// header name, matched without regard to case, or a 1-based column number.
+// A named column is looked up in the first row, which is then skipped. By
+// number, and when column is empty, the first row is only skipped when it
+// looks like a header rather than a domain. An empty column reads a column
+// with a header such as "domain", else the first column.
+func Column(rows [][]string, column string) ([]string, error) {
+	entries, err := Entries(rows, column)
+	return Domains(entries), err
+}
+
+// Entries returns the domains in one column of rows, picked as by Column,
+// with their notes. Notes are read from a column with a "note" header, or
+// from the second column of rows without a header whose domains are in the
+// first.
+func Entries(rows [][]string, column string) ([]Entry, error) {
+	rows = dropEmptyRows(rows)
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	header := rows[0]
+
+	index, skipHeader := 0, !looksLikeDomain(cell(header, 0))
+	if n, err := strconv.Atoi(column); err == nil {
+		if n < 1 {
+			return nil, fmt.Errorf("invalid column %d; columns are numbered from 1", n)
+		}
+		index, skipHeader = n-1, !looksLikeDomain(cell(header, n-1))
+	} else if column != "" {
+		if index = findColumn(header, column); index < 0 {
+			return nil, fmt.Errorf("column %q not found; the header has %s", column, strings.Join(nonEmpty(header), ", "))
+		}
+		skipHeader = true
+	} else {
+		for _, name := range defaultColumns {
+			if i := findColumn(header, name); i >= 0 {
+				index, skipHeader = i, true
+				break
+			}
+		}
+	}
+
+	noteIndex := -1
+	if skipHeader {
+		for _, name := range noteColumns {
+			if i := findColumn(header, name); i >= 0 && i != index {
+				noteIndex = i
+				break
+			}
+		}
+		rows = rows[1:]
+	} else if index == 0 {
+		noteIndex = 1
+	}
+
+	var entries []Entry
+	for _, row := range rows {
+		if value := cell(row, index); value != "" {
+			entry := Entry{Domain: value}
+			if noteIndex >= 0 {
+				entry.Note = cell(row, noteIndex)
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// findColumn returns the index of the header cell named name, or -1
+func findColumn(header []string, name string) int {
+	for i, value := range header {
+		if strings.EqualFold(strings.TrimSpace(value), strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// looksLikeDomain reports whether a cell holds a domain rather than a
+// header such as "Domain" or "Candidate name"
+func looksLikeDomain(value string) bool {
+	return strings.Contains(value, ".") && !strings.ContainsAny(value, " \t")
+}
+
+// cell returns the trimmed cell at index of row, empty when the row is short
+func cell(row []string, index int) string {
+	if index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+// dropEmptyRows removes rows without any content
+func dropEmptyRows(rows [][]string) [][]string {
+	kept := rows[:0:0]
+	for _, row := range rows {
+		if len(nonEmpty(row)) > 0 {
+			kept = append(kept, row)
+		}
+	}
+	return kept
+}
+
+// nonEmpty returns the trimmed, non-empty values
+func nonEmpty(values []string) []string {
+	var kept []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,167 @@
+package input
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected Format
+	}{
+		{"domains.txt", FormatText},
+		{"domains", FormatText},
+		{"candidates.csv", FormatCSV},
+		{"Candidates.XLSX", FormatXLSX},
+		{"zones/example.zone", FormatZone},
+	}
+
+	for _, tt := range tests {
+		if got := DetectFormat(tt.filename); got != tt.expected {
+			t.Errorf("DetectFormat(%q) = %q, want %q", tt.filename, got, tt.expected)
+		}
+	}
+}
+
+func TestReadText(t *testing.T) {
+	domains, err := ReadText(strings.NewReader("example.com\n# comment\n\n  test.org  \n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"example.com", "test.org"}; !reflect.DeepEqual(domains, expected) {
+		t.Errorf("ReadText() = %v, want %v", domains, expected)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	const sheet = "\ufeffName,Domain,Owner\nAcme,acme.com,marketing\n,,\nWidgets, widgets.io ,sales\nBlank,,sales\n"
+
+	tests := []struct {
+		name     string
+		data     string
+		column   string
+		expected []string
+		wantErr  bool
+	}{
+		{"by header name", sheet, "domain", []string{"acme.com", "widgets.io"}, false},
+		{"by number", sheet, "2", []string{"acme.com", "widgets.io"}, false},
+		{"default domain column", sheet, "", []string{"acme.com", "widgets.io"}, false},
+		{"first column without header", "acme.com,1\nwidgets.io,2\n", "", []string{"acme.com", "widgets.io"}, false},
+		{"number without header", "1,acme.com\n2,widgets.io\n", "2", []string{"acme.com", "widgets.io"}, false},
+		{"first column with other header", "Candidate name\nacme.com\n", "", []string{"acme.com"}, false},
+		{"empty", "", "domain", nil, false},
+		{"missing column", sheet, "hostname", nil, true},
+		{"column zero", sheet, "0", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			domains, err := ReadCSV(strings.NewReader(tt.data), tt.column)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(domains, tt.expected) {
+				t.Errorf("ReadCSV() = %v, want %v", domains, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadXLSX(t *testing.T) {
+	workbook := excelize.NewFile()
+	defer workbook.Close()
+
+	// The workbook opens on the second sheet, which is the one read
+	if err := workbook.SetSheetRow("Sheet1", "A1", &[]string{"ignored.com"}); err != nil {
+		t.Fatal(err)
+	}
+	index, err := workbook.NewSheet("Candidates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	workbook.SetActiveSheet(index)
+	for i, row := range [][]string{{"Brand", "Domain"}, {"Acme", "acme.com"}, {"Widgets", "widgets.io"}} {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := workbook.SetSheetRow("Candidates", cell, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := workbook.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	domains, err := ReadXLSX(bytes.NewReader(data.Bytes()), "Domain")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"acme.com", "widgets.io"}; !reflect.DeepEqual(domains, expected) {
+		t.Errorf("ReadXLSX() = %v, want %v", domains, expected)
+	}
+
+	if _, err := ReadXLSX(strings.NewReader("not a workbook"), ""); err == nil {
+		t.Error("expected an error for data that is not a workbook")
+	}
+}
+
+func TestRead_ColumnOfText(t *testing.T) {
+	if _, err := Read(strings.NewReader("example.com\n"), FormatText, "domain"); err == nil {
+		t.Error("expected an error when choosing a column of a text file")
+	}
+}
+
+func TestReadEntries(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		format   Format
+		column   string
+		expected []Entry
+	}{
+		{
+			name:   "text notes",
+			data:   "acme.com, client preference #1\nwidgets.io\n# comment, not a note\nbrand.dev,backup, if cheap\n",
+			format: FormatText,
+			expected: []Entry{
+				{Domain: "acme.com", Note: "client preference #1"},
+				{Domain: "widgets.io"},
+				{Domain: "brand.dev", Note: "backup, if cheap"},
+			},
+		},
+		{
+			name:     "CSV note column",
+			data:     "Notes,Domain\nfirst choice,acme.com\n,widgets.io\n",
+			format:   FormatCSV,
+			expected: []Entry{{Domain: "acme.com", Note: "first choice"}, {Domain: "widgets.io"}},
+		},
+		{
+			name:     "CSV without header",
+			data:     "acme.com,first choice\nwidgets.io\n",
+			format:   FormatCSV,
+			expected: []Entry{{Domain: "acme.com", Note: "first choice"}, {Domain: "widgets.io"}},
+		},
+		{
+			name:     "CSV header without notes",
+			data:     "domain,owner\nacme.com,sales\n",
+			format:   FormatCSV,
+			expected: []Entry{{Domain: "acme.com"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ReadEntries(strings.NewReader(tt.data), tt.format, tt.column)
+			if err != nil {
+				t.Fatalf("ReadEntries() error = %v", err)
+			}
+			if !reflect.DeepEqual(entries, tt.expected) {
+				t.Errorf("ReadEntries() = %v, want %v", entries, tt.expected)
+			}
+		})
+	}
+}
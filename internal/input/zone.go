@@ -0,0 +1,243 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+// classes are the DNS classes that may precede a record's type
+var classes = map[string]bool{"IN": true, "CH": true, "HS": true, "CS": true}
+
+// targetFields gives, for the record types whose data holds domain names,
+// the position of each name among the data fields
+var targetFields = map[string][]int{
+	"NS":    {0},
+	"CNAME": {0},
+	"DNAME": {0},
+	"PTR":   {0},
+	"MX":    {1},
+	"KX":    {1},
+	"AFSDB": {1},
+	"SRV":   {3},
+	"SVCB":  {1},
+	"HTTPS": {1},
+	"SOA":   {0},
+}
+
+// zoneReader follows the origin and owner of the records of a zone file
+type zoneReader struct {
+	origin  string
+	owner   string
+	entries []Entry
+	index   map[string]int
+}
+
+// readZone reads the registrable domains of the names in a BIND zone file:
+// the owners of its records and the names their data points to, such as
+// CNAME, NS and MX targets. Each domain is returned once, noted with the
+// first record pointing to it from another name, since a target that is not
+// registered is what an audit looks for. $INCLUDE files are not read, and
+// reverse zones under .arpa are left out.
+func readZone(r io.Reader) ([]Entry, error) {
+	z := &zoneReader{index: make(map[string]int)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var pending []string
+	depth, lineNumber, startLine := 0, 0, 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if depth == 0 {
+			startLine = lineNumber
+			// A record on an indented line belongs to the previous owner
+			if line != "" && (line[0] == ' ' || line[0] == '\t') {
+				pending = append(pending, "")
+			}
+		}
+
+		fields, opened := zoneFields(line)
+		pending = append(pending, fields...)
+		depth += opened
+		if depth > 0 {
+			continue
+		}
+		if depth < 0 {
+			return nil, fmt.Errorf("line %d: unbalanced parentheses", lineNumber)
+		}
+
+		record := pending
+		pending = nil
+		if len(record) == 0 || (len(record) == 1 && record[0] == "") {
+			continue
+		}
+		if err := z.record(record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", startLine, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading zone file: %w", err)
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parentheses", startLine)
+	}
+	return z.entries, nil
+}
+
+// zoneFields splits a zone file line into fields, leaving out comments and
+// parentheses, and returns how many more parentheses it opens than closes.
+// Quoted strings, such as TXT data, are one field.
+func zoneFields(line string) ([]string, int) {
+	var fields []string
+	var field strings.Builder
+	opened := 0
+	inQuotes, escaped, quoted := false, false, false
+
+	flush := func() {
+		if field.Len() > 0 || quoted {
+			fields = append(fields, field.String())
+		}
+		field.Reset()
+		quoted = false
+	}
+	for _, r := range line {
+		switch {
+		case escaped:
+			field.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			field.WriteRune(r)
+			escaped = true
+		case inQuotes:
+			if r == '"' {
+				inQuotes = false
+			} else {
+				field.WriteRune(r)
+			}
+		case r == '"':
+			inQuotes, quoted = true, true
+		case r == ';':
+			flush()
+			return fields, opened
+		case r == '(' || r == ')':
+			flush()
+			if r == '(' {
+				opened++
+			} else {
+				opened--
+			}
+		case r == ' ' || r == '\t':
+			flush()
+		default:
+			field.WriteRune(r)
+		}
+	}
+	flush()
+	return fields, opened
+}
+
+// record reads the fields of a directive or resource record. The first
+// field is the owner, empty when the record continues the previous owner.
+func (z *zoneReader) record(fields []string) error {
+	switch strings.ToUpper(fields[0]) {
+	case "$ORIGIN":
+		if len(fields) < 2 {
+			return fmt.Errorf("$ORIGIN without a name")
+		}
+		z.origin = z.absolute(fields[1])
+		return nil
+	case "$TTL", "$INCLUDE", "$GENERATE":
+		return nil
+	}
+
+	if fields[0] != "" {
+		z.owner = z.absolute(fields[0])
+	}
+	if z.owner == "" {
+		return fmt.Errorf("record without an owner name")
+	}
+	z.add(z.owner, "")
+
+	// Skip the TTL and class, which come in either order, to the type
+	data := fields[1:]
+	for len(data) > 0 && (classes[strings.ToUpper(data[0])] || isTTL(data[0])) {
+		data = data[1:]
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("record of %s without a type", z.owner)
+	}
+
+	recordType, data := strings.ToUpper(data[0]), data[1:]
+	for _, i := range targetFields[recordType] {
+		if i < len(data) && data[i] != "." {
+			z.add(z.absolute(data[i]), recordType+" target of "+z.owner)
+		}
+	}
+	return nil
+}
+
+// absolute returns name relative to the origin as a fully qualified name
+// without its trailing dot
+func (z *zoneReader) absolute(name string) string {
+	switch {
+	case name == "@":
+		return z.origin
+	case strings.HasSuffix(name, "."):
+		return strings.ToLower(strings.TrimSuffix(name, "."))
+	case z.origin == "":
+		return strings.ToLower(name)
+	default:
+		return strings.ToLower(name) + "." + z.origin
+	}
+}
+
+// add records the registrable domain of name, noting why it was found the
+// first time another name points to it
+func (z *zoneReader) add(name, note string) {
+	// Service labels such as _dmarc and wildcards are not part of the domain
+	labels := strings.Split(name, ".")
+	for len(labels) > 0 && (strings.HasPrefix(labels[0], "_") || labels[0] == "*") {
+		labels = labels[1:]
+	}
+	name = strings.Join(labels, ".")
+	if name == "" || name == "arpa" || strings.HasSuffix(name, ".arpa") {
+		return
+	}
+
+	registrable := domain.RegistrableDomain(name)
+	if registrable == "" {
+		return
+	}
+	if i, ok := z.index[registrable]; ok {
+		if z.entries[i].Note == "" && note != "" && domain.RegistrableDomain(z.owner) != registrable {
+			z.entries[i].Note = note
+		}
+		return
+	}
+	if domain.RegistrableDomain(z.owner) == registrable {
+		note = ""
+	}
+	z.index[registrable] = len(z.entries)
+	z.entries = append(z.entries, Entry{Domain: registrable, Note: note})
+}
+
+// isTTL reports whether field is a TTL, in seconds or with units such as 1h30m
+func isTTL(field string) bool {
+	if _, err := strconv.ParseUint(field, 10, 32); err == nil {
+		return true
+	}
+	if field == "" {
+		return false
+	}
+	for _, r := range strings.ToLower(field) {
+		if !strings.ContainsRune("0123456789smhdw", r) {
+			return false
+		}
+	}
+	return field[0] >= '0' && field[0] <= '9'
+}
@@ -0,0 +1,72 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadZone(t *testing.T) {
+	zone := `$ORIGIN example.com.
+$TTL 3600
+@	IN	SOA	ns1.example.com. hostmaster.example.com. (
+		2024010101 ; serial
+		7200       ; refresh
+		3600 1209600 3600 )
+	IN	NS	ns1.dns-host.net.
+	IN	MX	10 mail.mailhost.io.
+www	300	IN	CNAME	shop.vendor-cdn.com.
+_dmarc	IN	TXT	"v=DMARC1; p=reject; rua=mailto:reports@dmarc-tool.org"
+*.apps	IN	A	192.0.2.1
+_sip._tcp	IN	SRV	10 60 5060 sip.voip-provider.co.uk.
+blog		CNAME	blog.example.com.
+legacy	IN	CNAME	old-brand.com.
+$ORIGIN example.org.
+@	IN	A	192.0.2.2
+www	IN	CNAME	old-brand.com.
+`
+
+	entries, err := ReadEntries(strings.NewReader(zone), FormatZone, "")
+	if err != nil {
+		t.Fatalf("ReadEntries() error: %v", err)
+	}
+	expected := []Entry{
+		{Domain: "example.com"},
+		{Domain: "dns-host.net", Note: "NS target of example.com"},
+		{Domain: "mailhost.io", Note: "MX target of example.com"},
+		{Domain: "vendor-cdn.com", Note: "CNAME target of www.example.com"},
+		{Domain: "voip-provider.co.uk", Note: "SRV target of _sip._tcp.example.com"},
+		{Domain: "old-brand.com", Note: "CNAME target of legacy.example.com"},
+		{Domain: "example.org"},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("ReadEntries() =\n%v\nwant\n%v", entries, expected)
+	}
+}
+
+func TestReadZone_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		zone string
+		err  string
+	}{
+		{"unclosed parenthesis", "example.com. IN SOA ns1 host (\n 1 2 3\n", "line 1: unbalanced parentheses"},
+		{"extra parenthesis", "example.com. IN A 192.0.2.1 )\n", "line 1: unbalanced parentheses"},
+		{"no owner", "\tIN A 192.0.2.1\n", "line 1: record without an owner name"},
+		{"no type", "example.com. 300 IN\n", "line 1: record of example.com without a type"},
+		{"column", "", "a column can only be chosen"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column := ""
+			if tt.name == "column" {
+				column = "domain"
+			}
+			_, err := ReadEntries(strings.NewReader(tt.zone), FormatZone, column)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
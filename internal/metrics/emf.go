@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EMF emits metrics in the CloudWatch embedded metric format: one JSON
+// document per sample and line, which CloudWatch turns into metrics when it
+// reads them from a log group or receives them through the CloudWatch agent.
+// Each tag becomes a dimension.
+type EMF struct {
+	mu        sync.Mutex
+	w         io.Writer
+	closer    io.Closer
+	namespace string
+	now       func() time.Time
+}
+
+// NewEMF creates an emitter writing to w under the CloudWatch namespace ns.
+// An empty ns uses r53check.
+func NewEMF(w io.Writer, ns string) *EMF {
+	if ns == "" {
+		ns = namespace
+	}
+	return &EMF{w: w, namespace: ns, now: time.Now}
+}
+
+// OpenEMF creates an emitter for target: "stderr", the tcp:// or udp://
+// address of a CloudWatch agent, such as tcp://127.0.0.1:25888, or a file
+// that documents are appended to, under the CloudWatch namespace ns
+func OpenEMF(target, ns string) (*EMF, error) {
+	switch {
+	case target == "stderr":
+		return NewEMF(os.Stderr, ns), nil
+	case strings.HasPrefix(target, "tcp://"), strings.HasPrefix(target, "udp://"):
+		network, addr, _ := strings.Cut(target, "://")
+		conn, err := net.Dial(network, addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to the CloudWatch agent at %s: %w", target, err)
+		}
+		emf := NewEMF(conn, ns)
+		emf.closer = conn
+		return emf, nil
+	default:
+		file, err := os.OpenFile(target, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open EMF file: %w", err)
+		}
+		emf := NewEMF(file, ns)
+		emf.closer = file
+		return emf, nil
+	}
+}
+
+// Count writes a document for a counter sample
+func (e *EMF) Count(name string, value int64, tags map[string]string) {
+	e.write(name, "Count", value, tags)
+}
+
+// Timing writes a document for a duration, in milliseconds
+func (e *EMF) Timing(name string, d time.Duration, tags map[string]string) {
+	e.write(name, "Milliseconds", float64(d)/float64(time.Millisecond), tags)
+}
+
+// Close closes the connection or file opened by OpenEMF
+func (e *EMF) Close() error {
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
+
+// emfMetric describes one metric of a document
+type emfMetric struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit"`
+}
+
+// emfDirective tells CloudWatch which members of a document are metrics
+type emfDirective struct {
+	Namespace  string      `json:"Namespace"`
+	Dimensions [][]string  `json:"Dimensions"`
+	Metrics    []emfMetric `json:"Metrics"`
+}
+
+// emfMetadata is the _aws member of a document
+type emfMetadata struct {
+	Timestamp         int64          `json:"Timestamp"`
+	CloudWatchMetrics []emfDirective `json:"CloudWatchMetrics"`
+}
+
+// write encodes one sample as a document, dropping it if the write fails
+func (e *EMF) write(name, unit string, value any, tags map[string]string) {
+	dimensions := make([]string, 0, len(tags))
+	document := make(map[string]any, len(tags)+2)
+	for key, tag := range tags {
+		dimensions = append(dimensions, key)
+		document[key] = tag
+	}
+	sort.Strings(dimensions)
+	document[name] = value
+	document["_aws"] = emfMetadata{
+		Timestamp: e.now().UnixMilli(),
+		CloudWatchMetrics: []emfDirective{{
+			Namespace:  e.namespace,
+			Dimensions: [][]string{dimensions},
+			Metrics:    []emfMetric{{Name: name, Unit: unit}},
+		}},
+	}
+
+	line, err := json.Marshal(document)
+	if err != nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.w.Write(append(line, '\n'))
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+// Emitter pushes metrics to a monitoring backend as they are recorded,
+// unlike Metrics, which waits to be scraped. Implementations must be safe
+// for concurrent use and must not block the check; samples that cannot be
+// sent are dropped.
+type Emitter interface {
+	// Count adds value to the counter name
+	Count(name string, value int64, tags map[string]string)
+	// Timing records one duration of name
+	Timing(name string, d time.Duration, tags map[string]string)
+	// Close sends anything buffered and releases the connection or file
+	Close() error
+}
+
+// EmitHooks sends check counts by status, error counts by category and
+// check latency to an Emitter. It implements domain.Hooks, so attaching it
+// to a checker emits a sample for every check.
+type EmitHooks struct {
+	domain.NopHooks
+
+	emitter Emitter
+}
+
+// NewEmitHooks creates hooks emitting to emitter
+func NewEmitHooks(emitter Emitter) *EmitHooks {
+	return &EmitHooks{emitter: emitter}
+}
+
+// OnCheckComplete counts the check by status, counts a failure by error
+// category and records the check's duration
+func (h *EmitHooks) OnCheckComplete(ctx context.Context, result *domain.AvailabilityResult, err error) {
+	status := domain.StatusUnknown
+	if result != nil {
+		status = result.Status
+		if !result.CheckedAt.IsZero() {
+			h.emitter.Timing("check_duration", time.Since(result.CheckedAt), nil)
+		}
+	}
+	h.emitter.Count("checks", 1, map[string]string{"status": string(status)})
+
+	if err != nil {
+		h.emitter.Count("errors", 1, map[string]string{"category": string(errorCategory(err))})
+	}
+}
+
+// OnRetry counts retried checks
+func (h *EmitHooks) OnRetry(ctx context.Context, domain string, attempt int, err error) {
+	h.emitter.Count("retries", 1, nil)
+}
+
+// OnThrottle counts throttled checks
+func (h *EmitHooks) OnThrottle(ctx context.Context, domain string, err error) {
+	h.emitter.Count("throttles", 1, nil)
+}
+
+// errorCategory returns the category of a failed check, treating errors
+// without one as system errors
+func errorCategory(err error) customErrors.ErrorCategory {
+	var categorized interface {
+		GetCategory() customErrors.ErrorCategory
+	}
+	switch {
+	case errors.As(err, &categorized):
+		return categorized.GetCategory()
+	case errors.Is(err, context.DeadlineExceeded):
+		return customErrors.CategoryTimeout
+	default:
+		return customErrors.CategorySystem
+	}
+}
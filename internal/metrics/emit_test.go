@@ -0,0 +1,203 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	"github.com/abakermi/r53check/pkg/r53checktest"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// recordingEmitter records the samples it is given as name{tags} strings
+type recordingEmitter struct {
+	mu      sync.Mutex
+	counts  map[string]int64
+	timings int
+}
+
+func (r *recordingEmitter) Count(name string, value int64, tags map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counts[name+statsdTags(tags)] += value
+}
+
+func (r *recordingEmitter) Timing(name string, d time.Duration, tags map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timings++
+}
+
+func (r *recordingEmitter) Close() error { return nil }
+
+func TestEmitHooks(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		ThrottledThen("busy.com", 1, types.DomainAvailabilityAvailable).
+		Failing("denied.com", r53checktest.AccessDenied()).
+		Client()
+
+	checker := domain.NewDomainChecker(domain.NewDomainValidatorWithTLDs([]string{"com"}), client)
+	checker.SetRetryDelay(0)
+	emitter := &recordingEmitter{counts: make(map[string]int64)}
+	checker.AddHooks(NewEmitHooks(emitter))
+
+	domains := []string{"free.com", "taken.com", "busy.com", "denied.com", "bad-.com"}
+	if _, err := checker.CheckAvailabilityBulk(context.Background(), domains); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]int64{
+		"checks|#status:AVAILABLE":       2,
+		"checks|#status:UNAVAILABLE":     1,
+		"checks|#status:UNKNOWN":         3,
+		"errors|#category:API":           1,
+		"errors|#category:AUTHORIZATION": 1,
+		"errors|#category:VALIDATION":    1,
+		"throttles":                      1,
+		"retries":                        1,
+	}
+	if !reflect.DeepEqual(emitter.counts, expected) {
+		t.Errorf("Expected counts %v, got %v", expected, emitter.counts)
+	}
+	if emitter.timings != 6 {
+		t.Errorf("Expected a timing per check, got %d", emitter.timings)
+	}
+}
+
+func TestStatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	statsd, err := NewStatsD(conn.LocalAddr().String(), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	statsd.Count("checks", 1, map[string]string{"status": "AVAILABLE", "env": "prod"})
+	statsd.Timing("check_duration", 1500*time.Microsecond, nil)
+	if err := statsd.Close(); err != nil {
+		t.Errorf("Unexpected error closing: %v", err)
+	}
+
+	expected := []string{
+		"r53check.checks:1|c|#env:prod,status:AVAILABLE",
+		"r53check.check_duration:1.5|ms",
+	}
+	buf := make([]byte, 512)
+	for _, want := range expected {
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("Failed to read packet: %v", err)
+		}
+		if got := string(buf[:n]); got != want {
+			t.Errorf("Expected packet %q, got %q", want, got)
+		}
+	}
+}
+
+func TestEMF(t *testing.T) {
+	var buf bytes.Buffer
+	emf := NewEMF(&buf, "Domains")
+	emf.now = func() time.Time { return time.UnixMilli(1700000000000) }
+
+	emf.Count("checks", 1, map[string]string{"status": "AVAILABLE"})
+	emf.Timing("check_duration", 250*time.Millisecond, nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected a document per sample, got %q", buf.String())
+	}
+
+	var count struct {
+		AWS struct {
+			Timestamp         int64
+			CloudWatchMetrics []struct {
+				Namespace  string
+				Dimensions [][]string
+				Metrics    []struct{ Name, Unit string }
+			}
+		} `json:"_aws"`
+		Status string  `json:"status"`
+		Checks float64 `json:"checks"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &count); err != nil {
+		t.Fatalf("Invalid document %q: %v", lines[0], err)
+	}
+	directive := count.AWS.CloudWatchMetrics[0]
+	if count.AWS.Timestamp != 1700000000000 || directive.Namespace != "Domains" ||
+		!reflect.DeepEqual(directive.Dimensions, [][]string{{"status"}}) ||
+		directive.Metrics[0].Name != "checks" || directive.Metrics[0].Unit != "Count" ||
+		count.Status != "AVAILABLE" || count.Checks != 1 {
+		t.Errorf("Unexpected count document %s", lines[0])
+	}
+
+	var timing map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &timing); err != nil {
+		t.Fatalf("Invalid document %q: %v", lines[1], err)
+	}
+	if timing["check_duration"] != 250.0 || !strings.Contains(lines[1], `"Unit":"Milliseconds"`) {
+		t.Errorf("Unexpected timing document %s", lines[1])
+	}
+}
+
+func TestOpenEMF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.log")
+	for i := 0; i < 2; i++ {
+		emf, err := OpenEMF(path, "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		emf.Count("checks", 1, nil)
+		if err := emf.Close(); err != nil {
+			t.Fatalf("Unexpected error closing: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"Namespace":"r53check"`) {
+		t.Errorf("Expected both runs appended under the default namespace, got %q", data)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+	emf, err := OpenEMF("tcp://"+listener.Addr().String(), "")
+	if err != nil {
+		t.Fatalf("Unexpected error connecting to the agent: %v", err)
+	}
+	emf.Close()
+
+	if _, err := OpenEMF(filepath.Join(t.TempDir(), "missing", "metrics.log"), ""); err == nil {
+		t.Error("Expected an error for a file in a missing directory")
+	}
+}
+
+func TestStatsdTags(t *testing.T) {
+	if got := statsdTags(map[string]string{"b": "2", "a": "1"}); got != "|#a:1,b:2" {
+		t.Errorf("Expected sorted tags, got %q", got)
+	}
+	if statsdTags(nil) != "" {
+		t.Error("Expected no suffix without tags")
+	}
+}
@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/smithy-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric name
+const namespace = "r53check"
+
+// Metrics records domain checking activity for Prometheus. It implements
+// domain.Hooks, so attaching it to a checker records every check.
+type Metrics struct {
+	domain.NopHooks
+
+	registry     *prometheus.Registry
+	checks       *prometheus.CounterVec
+	awsErrors    *prometheus.CounterVec
+	throttles    prometheus.Counter
+	retries      prometheus.Counter
+	duration     prometheus.Histogram
+	cacheLookups *prometheus.CounterVec
+}
+
+// New creates a Metrics with its own registry, which also exposes the Go
+// runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		checks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "checks_total",
+			Help:      "Domain checks completed, by availability status.",
+		}, []string{"status"}),
+		awsErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "aws_errors_total",
+			Help:      "Failed Route 53 Domains calls, by AWS error code.",
+		}, []string{"code"}),
+		throttles: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "throttle_events_total",
+			Help:      "Checks rejected by Route 53 Domains rate limiting.",
+		}),
+		retries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Checks attempted again after a retryable failure.",
+		}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "check_duration_seconds",
+			Help:      "Time taken by each domain check, including validation and pricing.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Cache lookups, by cache and result (hit or miss).",
+		}, []string{"cache", "result"}),
+	}
+
+	m.registry.MustRegister(
+		m.checks, m.awsErrors, m.throttles, m.retries, m.duration, m.cacheLookups,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Registry returns the registry the metrics are registered with, so callers
+// can add their own collectors
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// OnCheckComplete counts the check by status, records its duration and
+// counts AWS failures by error code
+func (m *Metrics) OnCheckComplete(ctx context.Context, result *domain.AvailabilityResult, err error) {
+	status := domain.StatusUnknown
+	if result != nil {
+		status = result.Status
+		if !result.CheckedAt.IsZero() {
+			m.duration.Observe(time.Since(result.CheckedAt).Seconds())
+		}
+	}
+	m.checks.WithLabelValues(string(status)).Inc()
+
+	if code := awsErrorCode(err); code != "" {
+		m.awsErrors.WithLabelValues(code).Inc()
+	}
+}
+
+// OnRetry counts retried checks
+func (m *Metrics) OnRetry(ctx context.Context, domain string, attempt int, err error) {
+	m.retries.Inc()
+}
+
+// OnThrottle counts throttled checks
+func (m *Metrics) OnThrottle(ctx context.Context, domain string, err error) {
+	m.throttles.Inc()
+}
+
+// RecordCacheLookup counts a lookup in the named cache as a hit or a miss
+func (m *Metrics) RecordCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(cache, result).Inc()
+}
+
+// awsErrorCode returns the AWS error code of a failed call, or a code
+// derived from the error category when AWS returned none. Validation
+// failures never reach AWS and return an empty code.
+func awsErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "Timeout"
+	case errors.Is(err, context.Canceled):
+		return "Canceled"
+	case customErrors.IsThrottling(err):
+		return "Throttling"
+	}
+
+	var categorized interface {
+		GetCategory() customErrors.ErrorCategory
+	}
+	if errors.As(err, &categorized) {
+		switch categorized.GetCategory() {
+		case customErrors.CategoryValidation:
+			return ""
+		case customErrors.CategoryAuthentication:
+			return "AuthenticationFailed"
+		case customErrors.CategoryAuthorization:
+			return "AccessDenied"
+		}
+	}
+	return "Unknown"
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/pkg/r53checktest"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+	"github.com/aws/smithy-go"
+)
+
+// scrape returns the metrics exposition served by m
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatalf("Failed to read metrics: %v", err)
+	}
+	return string(body)
+}
+
+func TestMetrics_RecordsChecks(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		ThrottledThen("busy.com", 1, types.DomainAvailabilityAvailable).
+		Failing("denied.com", &smithy.GenericAPIError{Code: "AccessDenied", Message: "denied"}).
+		Client()
+
+	checker := domain.NewDomainChecker(domain.NewDomainValidatorWithTLDs([]string{"com"}), client)
+	checker.SetRetryDelay(0)
+	m := New()
+	checker.AddHooks(m)
+
+	domains := []string{"free.com", "taken.com", "busy.com", "denied.com", "bad-.com"}
+	if _, err := checker.CheckAvailabilityBulk(context.Background(), domains); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m.RecordCacheLookup("tld-catalog", true)
+	m.RecordCacheLookup("tld-catalog", false)
+
+	body := scrape(t, m)
+	expected := []string{
+		`r53check_checks_total{status="AVAILABLE"} 2`,
+		`r53check_checks_total{status="UNAVAILABLE"} 1`,
+		`r53check_checks_total{status="UNKNOWN"} 3`,
+		`r53check_aws_errors_total{code="AccessDenied"} 1`,
+		`r53check_aws_errors_total{code="Throttling"} 1`,
+		`r53check_throttle_events_total 1`,
+		`r53check_retries_total 1`,
+		`r53check_check_duration_seconds_count 6`,
+		`r53check_cache_lookups_total{cache="tld-catalog",result="hit"} 1`,
+		`r53check_cache_lookups_total{cache="tld-catalog",result="miss"} 1`,
+		`go_goroutines`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("Expected metrics to contain %q", line)
+		}
+	}
+
+	// Validation failures never reach AWS
+	if strings.Contains(body, `code="Unknown"`) {
+		t.Errorf("Expected no AWS error for the invalid domain, got:\n%s", body)
+	}
+}
+
+func TestAWSErrorCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"nil", nil, ""},
+		{"validation", customErrors.NewValidationError("x.com", "format", "bad", nil), ""},
+		{"smithy code", &smithy.GenericAPIError{Code: "InvalidInput"}, "InvalidInput"},
+		{"wrapped smithy code", customErrors.WrapAWSError(&smithy.GenericAPIError{Code: "UnsupportedTLD"}, "route53domains", "CheckDomainAvailability"), "UnsupportedTLD"},
+		{"throttled", r53checktest.Throttled(), "Throttling"},
+		{"timeout", context.DeadlineExceeded, "Timeout"},
+		{"authorization", r53checktest.AccessDenied(), "AccessDenied"},
+		{"system", customErrors.NewSystemError("bulk-check", "failed", nil), "Unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := awsErrorCode(tt.err); code != tt.expected {
+				t.Errorf("awsErrorCode(%v) = %q, want %q", tt.err, code, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StatsD emits metrics to a StatsD server over UDP, one packet per sample,
+// with tags in the DogStatsD format understood by Datadog and Telegraf
+type StatsD struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	prefix string
+}
+
+// NewStatsD creates an emitter sending to the StatsD server at addr, a
+// host:port, naming each metric prefix.name. An empty prefix uses r53check.
+func NewStatsD(addr, prefix string) (*StatsD, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to StatsD at %s: %w", addr, err)
+	}
+	if prefix == "" {
+		prefix = namespace
+	}
+	return &StatsD{conn: conn, prefix: prefix}, nil
+}
+
+// Count sends a counter sample
+func (s *StatsD) Count(name string, value int64, tags map[string]string) {
+	s.send(fmt.Sprintf("%s.%s:%d|c%s", s.prefix, name, value, statsdTags(tags)))
+}
+
+// Timing sends a timer sample in milliseconds
+func (s *StatsD) Timing(name string, d time.Duration, tags map[string]string) {
+	s.send(fmt.Sprintf("%s.%s:%g|ms%s", s.prefix, name, float64(d)/float64(time.Millisecond), statsdTags(tags)))
+}
+
+// Close closes the UDP socket
+func (s *StatsD) Close() error {
+	return s.conn.Close()
+}
+
+// send writes one packet, dropping it if the write fails
+func (s *StatsD) send(packet string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conn.Write([]byte(packet))
+}
+
+// statsdTags formats tags as a DogStatsD suffix, such as |#status:AVAILABLE,
+// sorted by key so identical tags always give identical packets
+func statsdTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		pairs = append(pairs, key+":"+value)
+	}
+	sort.Strings(pairs)
+	return "|#" + strings.Join(pairs, ",")
+}
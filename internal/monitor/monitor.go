@@ -0,0 +1,244 @@
+package monitor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/history"
+)
+
+// Change is a domain whose status differs from the previous definitive check
+type Change struct {
+	Domain   string
+	Previous domain.AvailabilityStatus
+	Current  domain.AvailabilityStatus
+	Result   *domain.AvailabilityResult
+}
+
+// Report describes one monitoring run
+type Report struct {
+	RunID      string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Results    []*domain.AvailabilityResult
+	Changes    []Change
+
+	// PriceChanges are the TLDs whose price differs from the price last
+	// recorded in history, found by runs with pricing
+	PriceChanges []history.PriceChange
+
+	// Tags are the key=value labels of the run, recorded in history
+	Tags map[string]string
+
+	// Incomplete is set when the run was cancelled or timed out, or
+	// aborted, before every domain was checked
+	Incomplete bool
+}
+
+// NewReport creates the report of a run that started at startedAt and just
+// finished with results, for notifying about runs made outside a Monitor
+func NewReport(startedAt time.Time, results []*domain.AvailabilityResult) *Report {
+	report := &Report{RunID: newRunID(), StartedAt: startedAt, FinishedAt: time.Now()}
+	for _, result := range results {
+		if result != nil {
+			report.Results = append(report.Results, result)
+			report.Incomplete = report.Incomplete || result.Skipped
+		}
+	}
+	return report
+}
+
+// Notifier is told about runs in which at least one domain changed status
+// or a TLD changed price, and about one-off bulk runs when notifications
+// are requested
+type Notifier interface {
+	Notify(ctx context.Context, report *Report) error
+}
+
+// History records the checks of each run and remembers the latest
+// definitive status of every domain, as every history.Store does
+type History interface {
+	Append(records []history.Record) error
+	Latest() (map[string]history.Record, error)
+}
+
+// Monitor checks a list of domains repeatedly, recording every run in
+// history and notifying when a domain's status changes
+type Monitor struct {
+	checker     *domain.DomainChecker
+	history     History
+	notifiers   []Notifier
+	withPricing bool
+	tags        map[string]string
+
+	// last holds the latest definitive status of each domain
+	last map[string]domain.AvailabilityStatus
+}
+
+// NewMonitor creates a monitor that checks domains with checker and records
+// runs in hist. Previous statuses are loaded from hist, so changes are
+// detected across restarts. A nil hist keeps no history.
+func NewMonitor(checker *domain.DomainChecker, hist History) (*Monitor, error) {
+	m := &Monitor{
+		checker: checker,
+		history: hist,
+		last:    make(map[string]domain.AvailabilityStatus),
+	}
+
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// load updates the latest statuses from the history. The history may be
+// shared with monitors on other machines, so it is loaded before every run.
+func (m *Monitor) load() error {
+	if m.history == nil {
+		return nil
+	}
+	latest, err := m.history.Latest()
+	if err != nil {
+		return err
+	}
+	for name, record := range latest {
+		m.last[name] = domain.AvailabilityStatus(record.Status)
+	}
+	return nil
+}
+
+// SetPricing makes each run include pricing for available domains
+func (m *Monitor) SetPricing(enabled bool) {
+	m.withPricing = enabled
+}
+
+// SetTags labels every run with tags
+func (m *Monitor) SetTags(tags map[string]string) {
+	m.tags = tags
+}
+
+// AddNotifier registers a notifier for runs with status changes
+func (m *Monitor) AddNotifier(n Notifier) {
+	m.notifiers = append(m.notifiers, n)
+}
+
+// Run checks domains once, records the results and notifies about status
+// changes. A domain's first definitive status is a baseline, not a change,
+// and failed checks never count as changes. With pricing and a history
+// that keeps prices, the prices of the TLDs checked are recorded too, and
+// changed prices are notified as well. The report is returned even when
+// recording or notifying fails. A run cancelled or timed out by ctx still
+// records the checks that completed and notifies about their changes, in a
+// report marked incomplete, so that no change goes unannounced.
+func (m *Monitor) Run(ctx context.Context, domains []string) (*Report, error) {
+	report := &Report{RunID: newRunID(), StartedAt: time.Now(), Tags: m.tags}
+
+	// Without the history, changes are still detected from the statuses
+	// this monitor saw itself
+	loadErr := m.load()
+
+	var results []*domain.AvailabilityResult
+	var err error
+	if m.withPricing {
+		results, err = m.checker.CheckAvailabilityBulkWithPricing(ctx, domains)
+	} else {
+		results, err = m.checker.CheckAvailabilityBulk(ctx, domains)
+	}
+	report.FinishedAt = time.Now()
+	if ctx.Err() != nil {
+		report.Incomplete = true
+		err = customErrors.WrapSystemError("monitor", ctx.Err())
+	}
+
+	records := make([]history.Record, 0, len(results))
+	for _, result := range results {
+		if result == nil || result.Skipped {
+			continue
+		}
+		report.Results = append(report.Results, result)
+
+		record := history.NewRecord(report.RunID, result)
+		record.Tags = report.Tags
+		records = append(records, record)
+		if !record.Definitive() {
+			continue
+		}
+
+		previous, seen := m.last[result.Domain]
+		if seen && previous != result.Status {
+			report.Changes = append(report.Changes, Change{
+				Domain:   result.Domain,
+				Previous: previous,
+				Current:  result.Status,
+				Result:   result,
+			})
+		}
+		m.last[result.Domain] = result.Status
+	}
+
+	// Every domain failing is reported, but the failures are still recorded
+	errs := []error{err, loadErr}
+	if m.history != nil {
+		errs = append(errs, m.history.Append(records))
+	}
+	if prices, ok := m.history.(history.PriceStore); ok && m.withPricing {
+		changes, priceErr := history.RecordPrices(prices, history.ResultPrices(report.Results))
+		report.PriceChanges = changes
+		errs = append(errs, priceErr)
+	}
+	if len(report.Changes) > 0 || len(report.PriceChanges) > 0 {
+		notifyCtx := ctx
+		if report.Incomplete {
+			notifyCtx = context.WithoutCancel(ctx)
+		}
+		for _, n := range m.notifiers {
+			if notifyErr := n.Notify(notifyCtx, report); notifyErr != nil {
+				errs = append(errs, customErrors.NewSystemError("notify", "notification failed", notifyErr))
+			}
+		}
+	}
+	return report, errors.Join(errs...)
+}
+
+// newRunID returns a random identifier for a run
+func newRunID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// LogNotifier writes one line per status or price change to a writer
+type LogNotifier struct {
+	w io.Writer
+}
+
+// NewLogNotifier creates a notifier writing status and price changes to w
+func NewLogNotifier(w io.Writer) *LogNotifier {
+	return &LogNotifier{w: w}
+}
+
+// Notify writes the status and price changes of report
+func (n *LogNotifier) Notify(ctx context.Context, report *Report) error {
+	for _, change := range report.Changes {
+		if _, err := fmt.Fprintf(n.w, "%s %s: %s -> %s\n",
+			report.FinishedAt.Format(time.RFC3339), change.Domain, change.Previous, change.Current); err != nil {
+			return err
+		}
+	}
+	for _, change := range report.PriceChanges {
+		if _, err := fmt.Fprintf(n.w, "%s .%s price: %s -> %s\n",
+			report.FinishedAt.Format(time.RFC3339), change.TLD, change.Previous.Price(), change.Current.Price()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
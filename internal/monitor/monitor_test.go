@@ -0,0 +1,297 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	"github.com/abakermi/r53check/internal/history"
+	"github.com/abakermi/r53check/pkg/r53checktest"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// recordingNotifier keeps every report it is notified about
+type recordingNotifier struct {
+	reports []*Report
+	err     error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, report *Report) error {
+	n.reports = append(n.reports, report)
+	return n.err
+}
+
+func newChecker(client *r53checktest.Client) *domain.DomainChecker {
+	checker := domain.NewDomainChecker(domain.NewDomainValidatorWithTLDs([]string{"com"}), client)
+	checker.SetRetryDelay(-1)
+	return checker
+}
+
+func TestMonitor_DetectsChanges(t *testing.T) {
+	client := r53checktest.NewClient()
+	client.Script("drop.com",
+		r53checktest.Respond(types.DomainAvailabilityUnavailable),
+		r53checktest.Respond(types.DomainAvailabilityAvailable))
+	client.Script("flaky.com",
+		r53checktest.Respond(types.DomainAvailabilityUnavailable),
+		r53checktest.Fail(r53checktest.AccessDenied()),
+		r53checktest.Respond(types.DomainAvailabilityUnavailable))
+	client.Script("steady.com", r53checktest.Respond(types.DomainAvailabilityUnavailable))
+
+	log := history.NewLog(filepath.Join(t.TempDir(), "history.jsonl"))
+	m, err := NewMonitor(newChecker(client), log)
+	if err != nil {
+		t.Fatalf("NewMonitor() error: %v", err)
+	}
+	notifier := &recordingNotifier{}
+	m.AddNotifier(notifier)
+
+	domains := []string{"drop.com", "flaky.com", "steady.com"}
+
+	// The first run sets the baseline
+	report, err := m.Run(context.Background(), domains)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if len(report.Results) != 3 || len(report.Changes) != 0 || report.RunID == "" {
+		t.Fatalf("Expected a baseline run without changes, got %+v", report)
+	}
+
+	// drop.com becomes available; flaky.com fails, which is not a change
+	report, _ = m.Run(context.Background(), domains)
+	if len(report.Changes) != 1 {
+		t.Fatalf("Expected one change, got %+v", report.Changes)
+	}
+	change := report.Changes[0]
+	if change.Domain != "drop.com" || change.Previous != domain.StatusUnavailable || change.Current != domain.StatusAvailable {
+		t.Errorf("Unexpected change %+v", change)
+	}
+	if len(notifier.reports) != 1 || notifier.reports[0] != report {
+		t.Errorf("Expected the notifier to receive the report, got %d reports", len(notifier.reports))
+	}
+
+	// flaky.com recovers with its previous status
+	report, _ = m.Run(context.Background(), domains)
+	if len(report.Changes) != 0 || len(notifier.reports) != 1 {
+		t.Errorf("Expected no further changes, got %+v", report.Changes)
+	}
+
+	// A new monitor picks up where the history left off
+	latest, err := log.Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest["drop.com"].Status != string(domain.StatusAvailable) {
+		t.Errorf("Expected drop.com AVAILABLE in history, got %+v", latest["drop.com"])
+	}
+	restarted, err := NewMonitor(newChecker(r53checktest.NewScenario().Unavailable("drop.com").Client()), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, _ = restarted.Run(context.Background(), []string{"drop.com"})
+	if len(report.Changes) != 1 || report.Changes[0].Previous != domain.StatusAvailable {
+		t.Errorf("Expected the change to be detected across restarts, got %+v", report.Changes)
+	}
+}
+
+func TestMonitor_Tags(t *testing.T) {
+	log := history.NewLog(filepath.Join(t.TempDir(), "history.jsonl"))
+	m, err := NewMonitor(newChecker(r53checktest.NewScenario().Available("free.com").Client()), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetTags(map[string]string{"client": "acme"})
+
+	report, err := m.Run(context.Background(), []string{"free.com"})
+	if err != nil || report.Tags["client"] != "acme" {
+		t.Fatalf("Expected a tagged report, got %+v, %v", report.Tags, err)
+	}
+	if latest, _ := log.Latest(); latest["free.com"].Tags["client"] != "acme" {
+		t.Errorf("Expected the tags to be recorded, got %+v", latest["free.com"])
+	}
+}
+
+func TestMonitor_SharedHistory(t *testing.T) {
+	log := history.NewLog(filepath.Join(t.TempDir(), "history.jsonl"))
+	available := r53checktest.NewScenario().Available("drop.com").Client()
+
+	first, err := NewMonitor(newChecker(r53checktest.NewScenario().Unavailable("drop.com").Client()), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewMonitor(newChecker(available), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Run(context.Background(), []string{"drop.com"})
+
+	// The second monitor starts from the first one's check
+	report, _ := second.Run(context.Background(), []string{"drop.com"})
+	if len(report.Changes) != 1 || report.Changes[0].Previous != domain.StatusUnavailable {
+		t.Fatalf("Expected the change to be detected from the shared history, got %+v", report.Changes)
+	}
+
+	// Once one monitor reported the change, the others do not report it again
+	first.checker = newChecker(available)
+	if report, _ := first.Run(context.Background(), []string{"drop.com"}); len(report.Changes) != 0 {
+		t.Errorf("Expected the change to be reported once, got %+v", report.Changes)
+	}
+}
+
+// priceHistory is a history log that also keeps prices in memory
+type priceHistory struct {
+	*history.Log
+	prices []history.PriceRecord
+}
+
+func (h *priceHistory) AppendPrices(records []history.PriceRecord) error {
+	h.prices = append(h.prices, records...)
+	return nil
+}
+
+func (h *priceHistory) LatestPrices() (map[string]history.PriceRecord, error) {
+	latest := make(map[string]history.PriceRecord)
+	for _, record := range h.prices {
+		latest[record.TLD] = record
+	}
+	return latest, nil
+}
+
+func (h *priceHistory) Prices(tld string) ([]history.PriceRecord, error) {
+	return nil, nil
+}
+
+func TestMonitor_PriceChanges(t *testing.T) {
+	hist := &priceHistory{Log: history.NewLog(filepath.Join(t.TempDir(), "history.jsonl"))}
+	priced := func(registration float64) *domain.DomainChecker {
+		return newChecker(r53checktest.NewScenario().Available("free.com").
+			Price("com", r53checktest.Price{Registration: registration}).Client())
+	}
+
+	m, err := NewMonitor(priced(12), hist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetPricing(true)
+	notifier := &recordingNotifier{}
+	m.AddNotifier(notifier)
+
+	// The first price is a baseline, and an unchanged price is not recorded again
+	for range 2 {
+		if report, err := m.Run(context.Background(), []string{"free.com"}); err != nil || len(report.PriceChanges) != 0 {
+			t.Fatalf("Expected no price change, got %+v, %v", report.PriceChanges, err)
+		}
+	}
+	if len(hist.prices) != 1 || len(notifier.reports) != 0 {
+		t.Fatalf("Expected one recorded price and no notification, got %+v and %d reports", hist.prices, len(notifier.reports))
+	}
+
+	m.checker = priced(14)
+	report, err := m.Run(context.Background(), []string{"free.com"})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if len(report.PriceChanges) != 1 || report.PriceChanges[0].TLD != "com" || *report.PriceChanges[0].Current.Registration != 14 {
+		t.Fatalf("Expected the .com price change, got %+v", report.PriceChanges)
+	}
+	if len(notifier.reports) != 1 || len(hist.prices) != 2 {
+		t.Errorf("Expected the price change to be notified and recorded, got %d reports and %+v", len(notifier.reports), hist.prices)
+	}
+}
+
+func TestMonitor_NotifierFailure(t *testing.T) {
+	client := r53checktest.NewClient()
+	client.Script("drop.com",
+		r53checktest.Respond(types.DomainAvailabilityUnavailable),
+		r53checktest.Respond(types.DomainAvailabilityAvailable))
+
+	log := history.NewLog(filepath.Join(t.TempDir(), "history.jsonl"))
+	m, err := NewMonitor(newChecker(client), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing := &recordingNotifier{err: errors.New("smtp unavailable")}
+	working := &recordingNotifier{}
+	m.AddNotifier(failing)
+	m.AddNotifier(working)
+
+	m.Run(context.Background(), []string{"drop.com"})
+	report, err := m.Run(context.Background(), []string{"drop.com"})
+	if err == nil || !strings.Contains(err.Error(), "notification failed") {
+		t.Fatalf("Expected a notification error, got %v", err)
+	}
+	if len(report.Changes) != 1 || len(working.reports) != 1 {
+		t.Errorf("Expected other notifiers to still run, got %d reports", len(working.reports))
+	}
+
+	// The run is recorded even though notifying failed
+	if latest, _ := log.Latest(); latest["drop.com"].Status != string(domain.StatusAvailable) {
+		t.Errorf("Expected the run to be recorded, got %+v", latest)
+	}
+}
+
+func TestMonitor_IncompleteRun(t *testing.T) {
+	client := r53checktest.NewScenario().Slow("slow.com", time.Minute, types.DomainAvailabilityAvailable).Client()
+	client.Script("drop.com",
+		r53checktest.Respond(types.DomainAvailabilityUnavailable),
+		r53checktest.Respond(types.DomainAvailabilityAvailable))
+
+	log := history.NewLog(filepath.Join(t.TempDir(), "history.jsonl"))
+	m, err := NewMonitor(newChecker(client), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier := &recordingNotifier{}
+	m.AddNotifier(notifier)
+	m.Run(context.Background(), []string{"drop.com"})
+
+	// The run times out while slow.com is still being checked
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	report, err := m.Run(ctx, []string{"drop.com", "slow.com"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the timeout to be returned, got %v", err)
+	}
+	if !report.Incomplete || len(report.Results) != 1 || report.Results[0].Domain != "drop.com" {
+		t.Errorf("Expected an incomplete report of drop.com, got %+v", report)
+	}
+	if len(report.Changes) != 1 || len(notifier.reports) != 1 {
+		t.Errorf("Expected the change to drop.com to be notified, got %+v", report.Changes)
+	}
+
+	latest, _ := log.Latest()
+	if latest["drop.com"].Status != string(domain.StatusAvailable) {
+		t.Errorf("Expected the completed check to be recorded, got %+v", latest["drop.com"])
+	}
+	if _, ok := latest["slow.com"]; ok {
+		t.Errorf("Expected the skipped slow.com not to be recorded, got %+v", latest["slow.com"])
+	}
+}
+
+func TestLogNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	oldPrice, newPrice := 12.0, 14.0
+	report := &Report{
+		Changes: []Change{{Domain: "drop.com", Previous: domain.StatusUnavailable, Current: domain.StatusAvailable}},
+		PriceChanges: []history.PriceChange{{
+			TLD:      "com",
+			Previous: history.PriceRecord{TLD: "com", Currency: "USD", Registration: &oldPrice},
+			Current:  history.PriceRecord{TLD: "com", Currency: "USD", Registration: &newPrice},
+		}},
+	}
+
+	if err := NewLogNotifier(&buf).Notify(context.Background(), report); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"drop.com: UNAVAILABLE -> AVAILABLE", ".com price: 12.00 USD -> 14.00 USD"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %q in notification %q", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/monitor"
+)
+
+// maxDesktopChanges is how many status or price changes a desktop
+// notification lists
+const maxDesktopChanges = 3
+
+// windowsToastScript shows a toast through the Windows Runtime API, reading
+// the text from the environment so it needs no quoting
+const windowsToastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode($env:R53CHECK_TITLE)) > $null
+$text.Item(1).AppendChild($template.CreateTextNode($env:R53CHECK_MESSAGE)) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe').Show($toast)`
+
+// DesktopNotifier shows a native desktop notification for each run it is
+// notified about, using osascript on macOS, notify-send on Linux and
+// PowerShell on Windows
+type DesktopNotifier struct {
+	goos string
+	path string
+
+	// run executes the notification command; tests replace it
+	run func(cmd *exec.Cmd) error
+}
+
+// NewDesktopNotifier creates a notifier for the current platform, failing
+// when the platform's notification command is not installed
+func NewDesktopNotifier() (*DesktopNotifier, error) {
+	return newDesktopNotifier(runtime.GOOS, exec.LookPath)
+}
+
+func newDesktopNotifier(goos string, lookPath func(string) (string, error)) (*DesktopNotifier, error) {
+	var program string
+	switch goos {
+	case "darwin":
+		program = "osascript"
+	case "windows":
+		program = "powershell"
+	case "linux", "freebsd", "openbsd", "netbsd":
+		program = "notify-send"
+	default:
+		return nil, customErrors.NewValidationError("", "notify",
+			"desktop notifications are not supported on "+goos, nil)
+	}
+
+	path, err := lookPath(program)
+	if err != nil {
+		return nil, customErrors.NewValidationError("", "notify",
+			fmt.Sprintf("desktop notifications need %s, which was not found", program), err)
+	}
+	return &DesktopNotifier{goos: goos, path: path, run: (*exec.Cmd).Run}, nil
+}
+
+// Notify shows a notification summarizing report
+func (n *DesktopNotifier) Notify(ctx context.Context, report *monitor.Report) error {
+	title, message := desktopText(report)
+	if err := n.run(n.command(ctx, title, message)); err != nil {
+		return customErrors.NewSystemError("desktop", "unable to show notification", err)
+	}
+	return nil
+}
+
+// command builds the platform's command for showing a notification
+func (n *DesktopNotifier) command(ctx context.Context, title, message string) *exec.Cmd {
+	switch n.goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return exec.CommandContext(ctx, n.path, "-e", script)
+	case "windows":
+		cmd := exec.CommandContext(ctx, n.path, "-NoProfile", "-NonInteractive", "-Command", windowsToastScript)
+		cmd.Env = append(os.Environ(), "R53CHECK_TITLE="+title, "R53CHECK_MESSAGE="+message)
+		return cmd
+	default:
+		return exec.CommandContext(ctx, n.path, "--app-name=r53check", "--", title, message)
+	}
+}
+
+// desktopText returns the title and message for report: the status
+// changes when there are any, then the price changes, and the outcome
+// counts otherwise
+func desktopText(report *monitor.Report) (string, string) {
+	if len(report.Changes) > 0 {
+		var lines []string
+		for i, change := range report.Changes {
+			if i == maxDesktopChanges {
+				lines = append(lines, fmt.Sprintf("and %d more", len(report.Changes)-maxDesktopChanges))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("%s is now %s", change.Domain, change.Current))
+		}
+		return fmt.Sprintf("r53check: %d domain(s) changed status", len(report.Changes)), strings.Join(lines, "\n")
+	}
+	if len(report.PriceChanges) > 0 {
+		var lines []string
+		for i, change := range report.PriceChanges {
+			if i == maxDesktopChanges {
+				lines = append(lines, fmt.Sprintf("and %d more", len(report.PriceChanges)-maxDesktopChanges))
+				break
+			}
+			lines = append(lines, fmt.Sprintf(".%s now costs %s", change.TLD, change.Current.Price()))
+		}
+		return fmt.Sprintf("r53check: %d TLD price(s) changed", len(report.PriceChanges)), strings.Join(lines, "\n")
+	}
+
+	summary := summarize(report.Results)
+	if summary.Total == 1 {
+		result := report.Results[0]
+		if result.Error != nil {
+			return "r53check: check failed", result.Domain + ": " + result.Error.Error()
+		}
+		return "r53check: check finished", fmt.Sprintf("%s is %s", result.Domain, result.Status)
+	}
+	return "r53check: check finished", fmt.Sprintf("%d checked: %d available, %d unavailable, %d errors",
+		summary.Total, summary.Available, summary.Unavailable, summary.Errors)
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/abakermi/r53check/internal/domain"
+	"github.com/abakermi/r53check/internal/history"
+	"github.com/abakermi/r53check/internal/monitor"
+)
+
+// fakeLookPath finds every program in /usr/bin
+func fakeLookPath(program string) (string, error) {
+	return "/usr/bin/" + program, nil
+}
+
+func TestDesktopNotifier_Commands(t *testing.T) {
+	tests := []struct {
+		goos     string
+		program  string
+		wantArgs []string
+		wantEnv  string
+	}{
+		{"darwin", "osascript", []string{"-e", `display notification "drop.com is \"now\" free" with title "Title"`}, ""},
+		{"linux", "notify-send", []string{"--app-name=r53check", "--", "Title", `drop.com is "now" free`}, ""},
+		{"windows", "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", windowsToastScript}, `R53CHECK_MESSAGE=drop.com is "now" free`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			notifier, err := newDesktopNotifier(tt.goos, fakeLookPath)
+			if err != nil {
+				t.Fatalf("newDesktopNotifier() error: %v", err)
+			}
+
+			cmd := notifier.command(context.Background(), "Title", `drop.com is "now" free`)
+			if cmd.Path != "/usr/bin/"+tt.program {
+				t.Errorf("Expected %s, got %s", tt.program, cmd.Path)
+			}
+			if !slices.Equal(cmd.Args[1:], tt.wantArgs) {
+				t.Errorf("Unexpected arguments %q", cmd.Args[1:])
+			}
+			if tt.wantEnv != "" && !slices.Contains(cmd.Env, tt.wantEnv) {
+				t.Errorf("Expected %q in the environment", tt.wantEnv)
+			}
+		})
+	}
+}
+
+func TestNewDesktopNotifier_Unavailable(t *testing.T) {
+	if _, err := newDesktopNotifier("plan9", fakeLookPath); err == nil {
+		t.Error("Expected an error on an unsupported platform")
+	}
+
+	missing := func(string) (string, error) { return "", exec.ErrNotFound }
+	if _, err := newDesktopNotifier("linux", missing); err == nil || !strings.Contains(err.Error(), "notify-send") {
+		t.Errorf("Expected an error naming notify-send, got %v", err)
+	}
+}
+
+func TestDesktopNotifier_Notify(t *testing.T) {
+	notifier, _ := newDesktopNotifier("linux", fakeLookPath)
+	var args []string
+	notifier.run = func(cmd *exec.Cmd) error {
+		args = cmd.Args
+		return nil
+	}
+
+	if err := notifier.Notify(context.Background(), testReport()); err != nil {
+		t.Fatalf("Notify() error: %v", err)
+	}
+	if len(args) != 5 || args[3] != "r53check: 1 domain(s) changed status" || args[4] != "drop.com is now AVAILABLE" {
+		t.Errorf("Unexpected notification %q", args)
+	}
+
+	notifier.run = func(cmd *exec.Cmd) error { return errors.New("no notification daemon") }
+	if err := notifier.Notify(context.Background(), testReport()); err == nil {
+		t.Error("Expected the command failure to be returned")
+	}
+}
+
+func TestDesktopText(t *testing.T) {
+	changes := make([]monitor.Change, 5)
+	for i := range changes {
+		changes[i] = monitor.Change{Domain: string(rune('a'+i)) + ".com", Current: domain.StatusAvailable}
+	}
+
+	tests := []struct {
+		name        string
+		report      *monitor.Report
+		wantTitle   string
+		wantMessage string
+	}{
+		{
+			"many changes",
+			&monitor.Report{Changes: changes},
+			"r53check: 5 domain(s) changed status",
+			"a.com is now AVAILABLE\nb.com is now AVAILABLE\nc.com is now AVAILABLE\nand 2 more",
+		},
+		{
+			"price changes",
+			&monitor.Report{Results: testReport().Results, PriceChanges: []history.PriceChange{ioPriceChange()}},
+			"r53check: 1 TLD price(s) changed",
+			".io now costs 45.00 USD (renewal 50.00 USD)",
+		},
+		{
+			"bulk run",
+			&monitor.Report{Results: testReport().Results},
+			"r53check: check finished",
+			"3 checked: 1 available, 1 unavailable, 1 errors",
+		},
+		{
+			"single check",
+			&monitor.Report{Results: []*domain.AvailabilityResult{{Domain: "taken.com", Status: domain.StatusUnavailable}}},
+			"r53check: check finished",
+			"taken.com is UNAVAILABLE",
+		},
+		{
+			"single failure",
+			&monitor.Report{Results: []*domain.AvailabilityResult{{Domain: "denied.com", Error: errors.New("access denied")}}},
+			"r53check: check failed",
+			"denied.com: access denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, message := desktopText(tt.report)
+			if title != tt.wantTitle || message != tt.wantMessage {
+				t.Errorf("desktopText() = %q, %q; want %q, %q", title, message, tt.wantTitle, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/abakermi/r53check/internal/config"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/monitor"
+
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// DefaultSubject is the subject template used when none is configured
+const DefaultSubject = `r53check: {{if .Changes}}{{len .Changes}} domain(s) changed status{{else if .PriceChanges}}{{len .PriceChanges}} TLD price(s) changed{{else}}{{len .Results}} domain(s) checked{{end}}`
+
+// DefaultBody is the body template used when none is configured
+const DefaultBody = `{{if .Changes}}Status changes:
+
+{{range .Changes}}  {{.Domain}}: {{.Previous}} -> {{.Current}}
+{{end}}
+{{end}}{{if .PriceChanges}}Price changes:
+
+{{range .PriceChanges}}  .{{.TLD}}: {{.Previous.Price}} -> {{.Current.Price}}
+{{end}}
+{{end}}Results of run {{.RunID}} at {{.FinishedAt.Format "2006-01-02 15:04 MST"}}:
+
+{{table .Results}}
+{{.Summary.Available}} available, {{.Summary.Unavailable}} unavailable, {{.Summary.Errors}} errors.
+`
+
+// smtpTimeout bounds connecting to and talking with an SMTP server
+const smtpTimeout = 30 * time.Second
+
+// MailSender delivers a complete RFC 5322 message
+type MailSender interface {
+	SendMail(ctx context.Context, from string, to []string, msg []byte) error
+}
+
+// EmailNotifier emails a report of each run it is notified about
+type EmailNotifier struct {
+	sender  MailSender
+	from    string
+	to      []string
+	subject *template.Template
+	body    *template.Template
+}
+
+// NewEmailNotifier creates a notifier that sends reports through sender
+// using the addresses and templates in settings
+func NewEmailNotifier(settings config.EmailSettings, sender MailSender) (*EmailNotifier, error) {
+	if settings.From == "" {
+		return nil, customErrors.NewValidationError("", "email.from", "a sender address is required for email notifications", nil)
+	}
+	if len(settings.To) == 0 {
+		return nil, customErrors.NewValidationError("", "email.to", "at least one recipient is required for email notifications", nil)
+	}
+
+	subjectText := settings.Subject
+	if subjectText == "" {
+		subjectText = DefaultSubject
+	}
+	subject, err := template.New("subject").Funcs(templateFuncs).Parse(subjectText)
+	if err != nil {
+		return nil, customErrors.NewValidationError("", "email.subject", "invalid subject template", err)
+	}
+
+	bodyText := DefaultBody
+	if settings.Template != "" {
+		data, err := os.ReadFile(settings.Template)
+		if err != nil {
+			return nil, customErrors.NewValidationError("", "email.template", "unable to read body template", err)
+		}
+		bodyText = string(data)
+	}
+	body, err := template.New("body").Funcs(templateFuncs).Parse(bodyText)
+	if err != nil {
+		return nil, customErrors.NewValidationError("", "email.template", "invalid body template", err)
+	}
+
+	return &EmailNotifier{sender: sender, from: settings.From, to: settings.To, subject: subject, body: body}, nil
+}
+
+// Notify renders and sends the email for report
+func (n *EmailNotifier) Notify(ctx context.Context, report *monitor.Report) error {
+	msg, err := n.Message(report)
+	if err != nil {
+		return err
+	}
+	return n.sender.SendMail(ctx, n.from, n.to, msg)
+}
+
+// Message renders the complete email for report
+func (n *EmailNotifier) Message(report *monitor.Report) ([]byte, error) {
+	data := newReportData(report)
+
+	var subject, body bytes.Buffer
+	if err := n.subject.Execute(&subject, data); err != nil {
+		return nil, customErrors.NewSystemError("email", "unable to render subject", err)
+	}
+	if err := n.body.Execute(&body, data); err != nil {
+		return nil, customErrors.NewSystemError("email", "unable to render body", err)
+	}
+
+	var msg bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&msg, "%s: %s\r\n", name, value)
+	}
+	header("From", n.from)
+	header("To", strings.Join(n.to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", strings.TrimSpace(subject.String())))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(n.from))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	msg.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&msg)
+	qp.Write(body.Bytes())
+	qp.Close()
+
+	return msg.Bytes(), nil
+}
+
+// messageID returns a unique Message-ID in the sender's domain
+func messageID(from string) string {
+	host := "r53check.localhost"
+	if _, domain, ok := strings.Cut(from, "@"); ok {
+		host = strings.Trim(domain, "<> ")
+	}
+	b := make([]byte, 12)
+	rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + host + ">"
+}
+
+// SMTPSender sends mail through an SMTP server, using STARTTLS when the
+// server offers it, or implicit TLS when configured
+type SMTPSender struct {
+	settings config.SMTPSettings
+}
+
+// NewSMTPSender creates a sender for the server in settings. The port
+// defaults to 465 with implicit TLS and 587 otherwise.
+func NewSMTPSender(settings config.SMTPSettings) (*SMTPSender, error) {
+	if settings.Host == "" {
+		return nil, customErrors.NewValidationError("", "email.smtp.host", "an SMTP host is required for SMTP email", nil)
+	}
+	if settings.Port == 0 {
+		settings.Port = 587
+		if settings.TLS {
+			settings.Port = 465
+		}
+	}
+	return &SMTPSender{settings: settings}, nil
+}
+
+// SendMail delivers msg from from to every address in to
+func (s *SMTPSender) SendMail(ctx context.Context, from string, to []string, msg []byte) error {
+	addr := net.JoinHostPort(s.settings.Host, strconv.Itoa(s.settings.Port))
+
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{}
+	if s.settings.TLS {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.settings.Host}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return customErrors.NewSystemError("smtp", "unable to connect to "+addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.settings.Host)
+	if err != nil {
+		conn.Close()
+		return customErrors.NewSystemError("smtp", "unable to talk to "+addr, err)
+	}
+	defer client.Close()
+
+	if err := s.send(client, from, to, msg); err != nil {
+		return customErrors.NewSystemError("smtp", "unable to send email through "+addr, err)
+	}
+	return nil
+}
+
+// send runs the SMTP transaction on an open connection
+func (s *SMTPSender) send(client *smtp.Client, from string, to []string, msg []byte) error {
+	if ok, _ := client.Extension("STARTTLS"); ok && !s.settings.TLS {
+		if err := client.StartTLS(&tls.Config{ServerName: s.settings.Host}); err != nil {
+			return err
+		}
+	}
+	if s.settings.Username != "" {
+		auth := smtp.PlainAuth("", s.settings.Username, s.settings.Password, s.settings.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// SESAPI is the part of the SES v2 client used to send email
+type SESAPI interface {
+	SendEmail(ctx context.Context, params *sesv2.SendEmailInput, optFns ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error)
+}
+
+// SESSender sends mail through the Amazon SES API
+type SESSender struct {
+	api SESAPI
+}
+
+// NewSESSender creates a sender using api, usually sesv2.NewFromConfig(cfg)
+func NewSESSender(api SESAPI) *SESSender {
+	return &SESSender{api: api}
+}
+
+// SendMail delivers msg as a raw SES message
+func (s *SESSender) SendMail(ctx context.Context, from string, to []string, msg []byte) error {
+	_, err := s.api.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: &from,
+		Destination:      &types.Destination{ToAddresses: to},
+		Content:          &types.EmailContent{Raw: &types.RawMessage{Data: msg}},
+	})
+	if err != nil {
+		return customErrors.WrapAWSError(err, "ses", "SendEmail")
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/config"
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/history"
+	"github.com/abakermi/r53check/internal/monitor"
+
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/smithy-go"
+)
+
+// recordingSender keeps the last message it was asked to send
+type recordingSender struct {
+	from string
+	to   []string
+	msg  []byte
+}
+
+func (s *recordingSender) SendMail(ctx context.Context, from string, to []string, msg []byte) error {
+	s.from, s.to, s.msg = from, to, msg
+	return nil
+}
+
+func testReport() *monitor.Report {
+	price := 12.0
+	drop := &domain.AvailabilityResult{
+		Domain: "drop.com", Available: true, Status: domain.StatusAvailable,
+		Pricing: &domain.PricingInfo{RegistrationPrice: &price, Currency: "USD"},
+	}
+	return &monitor.Report{
+		RunID:      "abc123",
+		FinishedAt: time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC),
+		Tags:       map[string]string{"client": "acme"},
+		Results: []*domain.AvailabilityResult{
+			drop,
+			{Domain: "taken.com", Status: domain.StatusUnavailable, Message: "registered"},
+			{Domain: "denied.com", Status: domain.StatusUnknown, Error: errors.New("access denied")},
+		},
+		Changes: []monitor.Change{{Domain: "drop.com", Previous: domain.StatusUnavailable, Current: domain.StatusAvailable, Result: drop}},
+	}
+}
+
+// parseMessage parses msg and returns its subject and decoded body
+func parseMessage(t *testing.T, msg []byte) (*mail.Message, string, string) {
+	t.Helper()
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(msg)))
+	if err != nil {
+		t.Fatalf("Invalid message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("Invalid subject: %v", err)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if err != nil {
+		t.Fatalf("Invalid body: %v", err)
+	}
+	return parsed, subject, string(body)
+}
+
+func TestEmailNotifier_DefaultTemplates(t *testing.T) {
+	sender := &recordingSender{}
+	notifier, err := NewEmailNotifier(config.EmailSettings{From: "r53check@example.com", To: []string{"ops@example.com"}}, sender)
+	if err != nil {
+		t.Fatalf("NewEmailNotifier() error: %v", err)
+	}
+
+	if err := notifier.Notify(context.Background(), testReport()); err != nil {
+		t.Fatalf("Notify() error: %v", err)
+	}
+	if sender.from != "r53check@example.com" || len(sender.to) != 1 || sender.to[0] != "ops@example.com" {
+		t.Errorf("Unexpected envelope from %q to %v", sender.from, sender.to)
+	}
+
+	parsed, subject, body := parseMessage(t, sender.msg)
+	if subject != "r53check: 1 domain(s) changed status" {
+		t.Errorf("Unexpected subject %q", subject)
+	}
+	if parsed.Header.Get("To") != "ops@example.com" || !strings.HasSuffix(parsed.Header.Get("Message-ID"), "@example.com>") {
+		t.Errorf("Unexpected headers %v", parsed.Header)
+	}
+
+	expected := []string{
+		"drop.com: UNAVAILABLE -> AVAILABLE",
+		"Results of run abc123",
+		"DOMAIN",
+		"12.00 USD",
+		"taken.com",
+		"access denied",
+		"1 available, 1 unavailable, 1 errors.",
+	}
+	for _, want := range expected {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected body to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+// ioPriceChange returns a change of the price of .io
+func ioPriceChange() history.PriceChange {
+	oldPrice, newPrice, renewal := 39.0, 45.0, 50.0
+	return history.PriceChange{
+		TLD:      "io",
+		Previous: history.PriceRecord{TLD: "io", Currency: "USD", Registration: &oldPrice},
+		Current:  history.PriceRecord{TLD: "io", Currency: "USD", Registration: &newPrice, Renewal: &renewal},
+	}
+}
+
+func TestEmailNotifier_PriceChanges(t *testing.T) {
+	sender := &recordingSender{}
+	notifier, err := NewEmailNotifier(config.EmailSettings{From: "r53check@example.com", To: []string{"ops@example.com"}}, sender)
+	if err != nil {
+		t.Fatalf("NewEmailNotifier() error: %v", err)
+	}
+
+	report := testReport()
+	report.Changes = nil
+	report.PriceChanges = []history.PriceChange{ioPriceChange()}
+	if err := notifier.Notify(context.Background(), report); err != nil {
+		t.Fatalf("Notify() error: %v", err)
+	}
+
+	_, subject, body := parseMessage(t, sender.msg)
+	if subject != "r53check: 1 TLD price(s) changed" {
+		t.Errorf("Unexpected subject %q", subject)
+	}
+	if want := ".io: 39.00 USD -> 45.00 USD (renewal 50.00 USD)"; !strings.Contains(body, want) {
+		t.Errorf("Expected body to contain %q, got:\n%s", want, body)
+	}
+}
+
+func TestEmailNotifier_CustomTemplates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "body.tmpl")
+	if err := os.WriteFile(path, []byte(`{{range .Results}}{{.Domain}}={{price .Pricing}};{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &recordingSender{}
+	notifier, err := NewEmailNotifier(config.EmailSettings{
+		From:     "r53check@example.com",
+		To:       []string{"ops@example.com"},
+		Subject:  "Domains: {{.Summary.Available}} of {{.Summary.Total}} available",
+		Template: path,
+	}, sender)
+	if err != nil {
+		t.Fatalf("NewEmailNotifier() error: %v", err)
+	}
+	if err := notifier.Notify(context.Background(), testReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, subject, body := parseMessage(t, sender.msg)
+	if subject != "Domains: 1 of 3 available" {
+		t.Errorf("Unexpected subject %q", subject)
+	}
+	if body != "drop.com=12.00 USD;taken.com=-;denied.com=-;" {
+		t.Errorf("Unexpected body %q", body)
+	}
+}
+
+func TestNewEmailNotifier_Invalid(t *testing.T) {
+	valid := config.EmailSettings{From: "a@example.com", To: []string{"b@example.com"}}
+
+	tests := []struct {
+		name   string
+		modify func(*config.EmailSettings)
+		field  string
+	}{
+		{"no sender", func(s *config.EmailSettings) { s.From = "" }, "email.from"},
+		{"no recipients", func(s *config.EmailSettings) { s.To = nil }, "email.to"},
+		{"bad subject", func(s *config.EmailSettings) { s.Subject = "{{.Nope" }, "email.subject"},
+		{"missing template", func(s *config.EmailSettings) { s.Template = "/does/not/exist" }, "email.template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := valid
+			tt.modify(&settings)
+			_, err := NewEmailNotifier(settings, &recordingSender{})
+			var validationErr *customErrors.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("Expected a validation error for %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+// fakeSES records SendEmail calls
+type fakeSES struct {
+	input *sesv2.SendEmailInput
+	err   error
+}
+
+func (f *fakeSES) SendEmail(ctx context.Context, params *sesv2.SendEmailInput, optFns ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error) {
+	f.input = params
+	return &sesv2.SendEmailOutput{}, f.err
+}
+
+func TestSESSender(t *testing.T) {
+	api := &fakeSES{}
+	sender := NewSESSender(api)
+
+	if err := sender.SendMail(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("raw")); err != nil {
+		t.Fatalf("SendMail() error: %v", err)
+	}
+	if *api.input.FromEmailAddress != "a@example.com" || api.input.Destination.ToAddresses[0] != "b@example.com" {
+		t.Errorf("Unexpected addresses in %+v", api.input)
+	}
+	if string(api.input.Content.Raw.Data) != "raw" {
+		t.Errorf("Expected the raw message to be sent, got %q", api.input.Content.Raw.Data)
+	}
+
+	api.err = &smithy.GenericAPIError{Code: "MessageRejected", Message: "Email address is not verified"}
+	if err := sender.SendMail(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("raw")); err == nil {
+		t.Error("Expected the SES error to be returned")
+	}
+}
+
+// fakeSMTPServer accepts one SMTP session and records the message
+type fakeSMTPServer struct {
+	listener net.Listener
+	auth     chan string
+	data     chan string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	s := &fakeSMTPServer{listener: listener, auth: make(chan string, 1), data: make(chan string, 1)}
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTPServer) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(command, "AUTH PLAIN"):
+			s.auth <- strings.TrimPrefix(command, "AUTH PLAIN ")
+			reply("235 Authenticated")
+		case strings.HasPrefix(command, "MAIL FROM"), strings.HasPrefix(command, "RCPT TO"):
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil || line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.data <- data.String()
+			reply("250 Queued")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Unknown command")
+		}
+	}
+}
+
+func TestSMTPSender(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	host, port, _ := net.SplitHostPort(server.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	sender, err := NewSMTPSender(config.SMTPSettings{Host: host, Port: portNumber, Username: "user", Password: "secret"})
+	if err != nil {
+		t.Fatalf("NewSMTPSender() error: %v", err)
+	}
+
+	msg := "Subject: test\r\n\r\nhello\r\n"
+	if err := sender.SendMail(context.Background(), "a@example.com", []string{"b@example.com"}, []byte(msg)); err != nil {
+		t.Fatalf("SendMail() error: %v", err)
+	}
+
+	if auth := <-server.auth; auth == "" {
+		t.Error("Expected the client to authenticate")
+	}
+	if data := <-server.data; data != msg {
+		t.Errorf("Expected the message to be delivered unchanged, got %q", data)
+	}
+}
+
+func TestSMTPSender_Defaults(t *testing.T) {
+	if _, err := NewSMTPSender(config.SMTPSettings{}); err == nil {
+		t.Error("Expected an error without a host")
+	}
+
+	sender, _ := NewSMTPSender(config.SMTPSettings{Host: "smtp.example.com"})
+	if sender.settings.Port != 587 {
+		t.Errorf("Expected port 587 by default, got %d", sender.settings.Port)
+	}
+	sender, _ = NewSMTPSender(config.SMTPSettings{Host: "smtp.example.com", TLS: true})
+	if sender.settings.Port != 465 {
+		t.Errorf("Expected port 465 with implicit TLS, got %d", sender.settings.Port)
+	}
+}
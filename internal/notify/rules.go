@@ -0,0 +1,180 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/config"
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/history"
+	"github.com/abakermi/r53check/internal/monitor"
+)
+
+// statuses are the values accepted in a rule's status condition
+var statuses = []domain.AvailabilityStatus{
+	domain.StatusAvailable, domain.StatusUnavailable, domain.StatusReserved, domain.StatusUnknown,
+}
+
+// Rule routes the results matching all of its conditions to its targets,
+// or with its price change condition, the TLD price changes matching it
+type Rule struct {
+	name        string
+	statuses    []domain.AvailabilityStatus
+	tlds        []string
+	priceBelow  float64
+	priceChange bool
+	targets     []string
+}
+
+// ParseRules validates and normalizes the routing rules in settings
+func ParseRules(settings []config.NotifyRule) ([]Rule, error) {
+	rules := make([]Rule, 0, len(settings))
+	for i, s := range settings {
+		rule := Rule{name: s.Name, priceBelow: s.PriceBelow, priceChange: s.PriceChange}
+		if rule.name == "" {
+			rule.name = fmt.Sprintf("rule %d", i+1)
+		}
+		field := fmt.Sprintf("notify.rules[%d]", i)
+
+		if len(s.Targets) == 0 {
+			return nil, customErrors.NewValidationError("", field+".targets", rule.name+" has no targets", nil)
+		}
+		for _, target := range s.Targets {
+			rule.targets = append(rule.targets, strings.ToLower(strings.TrimSpace(target)))
+		}
+
+		for _, value := range s.Status {
+			status := domain.AvailabilityStatus(strings.ToUpper(strings.TrimSpace(value)))
+			if !slices.Contains(statuses, status) {
+				return nil, customErrors.NewValidationError("", field+".status",
+					fmt.Sprintf("%s has unknown status %q", rule.name, value), nil)
+			}
+			rule.statuses = append(rule.statuses, status)
+		}
+		for _, tld := range s.TLDs {
+			rule.tlds = append(rule.tlds, strings.Trim(strings.ToLower(strings.TrimSpace(tld)), "."))
+		}
+		if rule.priceBelow < 0 {
+			return nil, customErrors.NewValidationError("", field+".price_below", rule.name+" has a negative price", nil)
+		}
+		if rule.priceChange && (len(rule.statuses) > 0 || rule.priceBelow > 0) {
+			return nil, customErrors.NewValidationError("", field+".price_change",
+				rule.name+" matches price changes, which have no status or price_below condition", nil)
+		}
+
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Name returns the rule's configured name, or its position when unnamed
+func (r Rule) Name() string {
+	return r.name
+}
+
+// Targets returns the notifier targets the rule routes to
+func (r Rule) Targets() []string {
+	return r.targets
+}
+
+// Match reports whether result meets every condition of the rule. A price
+// condition only matches results checked with pricing, and a rule for
+// price changes matches no result.
+func (r Rule) Match(result *domain.AvailabilityResult) bool {
+	if r.priceChange {
+		return false
+	}
+	if len(r.statuses) > 0 && !slices.Contains(r.statuses, result.Status) {
+		return false
+	}
+	if !r.matchTLD(strings.ToLower(result.Domain)) {
+		return false
+	}
+	if r.priceBelow > 0 {
+		if result.Pricing == nil || result.Pricing.RegistrationPrice == nil || *result.Pricing.RegistrationPrice >= r.priceBelow {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchPriceChange reports whether the rule routes price changes and change
+// is under one of its TLDs
+func (r Rule) MatchPriceChange(change history.PriceChange) bool {
+	return r.priceChange && r.matchTLD("."+change.TLD)
+}
+
+// matchTLD reports whether name, in lowercase, is under one of the rule's
+// TLDs, or the rule has no TLD condition
+func (r Rule) matchTLD(name string) bool {
+	return len(r.tlds) == 0 || slices.ContainsFunc(r.tlds, func(tld string) bool {
+		return strings.HasSuffix(name, "."+tld)
+	})
+}
+
+// RulesFor returns the rules routing to target
+func RulesFor(rules []Rule, target string) []Rule {
+	var matching []Rule
+	for _, rule := range rules {
+		if slices.Contains(rule.targets, target) {
+			matching = append(matching, rule)
+		}
+	}
+	return matching
+}
+
+// RoutedNotifier passes on only the results and price changes matching at
+// least one of its rules, and skips runs in which nothing matches
+type RoutedNotifier struct {
+	notifier monitor.Notifier
+	rules    []Rule
+}
+
+// Route wraps notifier so that it is only told about results matching rules
+func Route(notifier monitor.Notifier, rules []Rule) *RoutedNotifier {
+	return &RoutedNotifier{notifier: notifier, rules: rules}
+}
+
+// Notify filters report and notifies the wrapped notifier when anything
+// matched. A report of status changes is only passed on when a matching
+// result changed status, and a report of price changes alone only when a
+// matching TLD changed price.
+func (n *RoutedNotifier) Notify(ctx context.Context, report *monitor.Report) error {
+	filtered := &monitor.Report{RunID: report.RunID, StartedAt: report.StartedAt, FinishedAt: report.FinishedAt}
+	for _, result := range report.Results {
+		if n.match(result) {
+			filtered.Results = append(filtered.Results, result)
+		}
+	}
+	for _, change := range report.Changes {
+		if n.match(change.Result) {
+			filtered.Changes = append(filtered.Changes, change)
+		}
+	}
+
+	for _, change := range report.PriceChanges {
+		if slices.ContainsFunc(n.rules, func(rule Rule) bool { return rule.MatchPriceChange(change) }) {
+			filtered.PriceChanges = append(filtered.PriceChanges, change)
+		}
+	}
+
+	matched := len(filtered.Results) > 0
+	switch {
+	case len(report.Changes) > 0:
+		matched = len(filtered.Changes) > 0
+	case len(report.PriceChanges) > 0:
+		matched = false
+	}
+	if !matched && len(filtered.PriceChanges) == 0 {
+		return nil
+	}
+	return n.notifier.Notify(ctx, filtered)
+}
+
+// match reports whether any rule matches result
+func (n *RoutedNotifier) match(result *domain.AvailabilityResult) bool {
+	return slices.ContainsFunc(n.rules, func(rule Rule) bool { return rule.Match(result) })
+}
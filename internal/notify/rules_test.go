@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/abakermi/r53check/internal/config"
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/history"
+	"github.com/abakermi/r53check/internal/monitor"
+)
+
+// recordingNotifier keeps the reports it is notified about
+type recordingNotifier struct {
+	reports []*monitor.Report
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, report *monitor.Report) error {
+	n.reports = append(n.reports, report)
+	return nil
+}
+
+func priced(name string, status domain.AvailabilityStatus, price float64) *domain.AvailabilityResult {
+	return &domain.AvailabilityResult{
+		Domain: name, Status: status, Available: status == domain.StatusAvailable,
+		Pricing: &domain.PricingInfo{RegistrationPrice: &price, Currency: "USD"},
+	}
+}
+
+func TestRule_Match(t *testing.T) {
+	rules, err := ParseRules([]config.NotifyRule{{
+		Status:     []string{"available"},
+		TLDs:       []string{".com", "co.uk"},
+		PriceBelow: 20,
+		Targets:    []string{"Email"},
+	}})
+	if err != nil {
+		t.Fatalf("ParseRules() error: %v", err)
+	}
+	rule := rules[0]
+
+	tests := []struct {
+		name   string
+		result *domain.AvailabilityResult
+		want   bool
+	}{
+		{"all conditions met", priced("cheap.com", domain.StatusAvailable, 12), true},
+		{"multi-level TLD", priced("cheap.co.uk", domain.StatusAvailable, 9), true},
+		{"unavailable", priced("taken.com", domain.StatusUnavailable, 12), false},
+		{"other TLD", priced("cheap.io", domain.StatusAvailable, 12), false},
+		{"price at limit", priced("pricey.com", domain.StatusAvailable, 20), false},
+		{"no pricing", &domain.AvailabilityResult{Domain: "free.com", Status: domain.StatusAvailable}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rule.Match(tt.result); got != tt.want {
+				t.Errorf("Match(%s) = %v, want %v", tt.result.Domain, got, tt.want)
+			}
+		})
+	}
+
+	if targets := rule.Targets(); len(targets) != 1 || targets[0] != "email" {
+		t.Errorf("Expected normalized targets, got %v", targets)
+	}
+	if rule.Name() != "rule 1" {
+		t.Errorf("Expected an unnamed rule to be named by position, got %q", rule.Name())
+	}
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  config.NotifyRule
+		field string
+	}{
+		{"no targets", config.NotifyRule{Status: []string{"AVAILABLE"}}, "notify.rules[0].targets"},
+		{"unknown status", config.NotifyRule{Status: []string{"FREE"}, Targets: []string{"email"}}, "notify.rules[0].status"},
+		{"negative price", config.NotifyRule{PriceBelow: -1, Targets: []string{"email"}}, "notify.rules[0].price_below"},
+		{"price change with status", config.NotifyRule{PriceChange: true, Status: []string{"AVAILABLE"}, Targets: []string{"email"}}, "notify.rules[0].price_change"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRules([]config.NotifyRule{tt.rule})
+			var validationErr *customErrors.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("Expected a validation error for %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestRoutedNotifier(t *testing.T) {
+	rules, _ := ParseRules([]config.NotifyRule{
+		{Status: []string{"AVAILABLE"}, TLDs: []string{"io"}, Targets: []string{"webhook"}},
+		{PriceBelow: 10, Targets: []string{"webhook", "email"}},
+	})
+	if got := len(RulesFor(rules, "webhook")); got != 2 {
+		t.Fatalf("Expected 2 rules for webhook, got %d", got)
+	}
+
+	inner := &recordingNotifier{}
+	routed := Route(inner, RulesFor(rules, "webhook"))
+
+	app := &domain.AvailabilityResult{Domain: "app.io", Status: domain.StatusAvailable}
+	bargain := priced("bargain.com", domain.StatusAvailable, 8)
+	taken := &domain.AvailabilityResult{Domain: "taken.com", Status: domain.StatusUnavailable}
+
+	// A bulk run passes on only the matching results
+	routed.Notify(context.Background(), &monitor.Report{RunID: "bulk", Results: []*domain.AvailabilityResult{app, bargain, taken}})
+	if len(inner.reports) != 1 || len(inner.reports[0].Results) != 2 || inner.reports[0].RunID != "bulk" {
+		t.Fatalf("Expected one report with the two matching results, got %+v", inner.reports)
+	}
+
+	// Nothing matching means no notification
+	routed.Notify(context.Background(), &monitor.Report{Results: []*domain.AvailabilityResult{taken}})
+	if len(inner.reports) != 1 {
+		t.Errorf("Expected no notification without matching results, got %d reports", len(inner.reports))
+	}
+
+	// Status changes are only passed on when a matching domain changed
+	changedTaken := monitor.Change{Domain: "taken.com", Previous: domain.StatusAvailable, Current: domain.StatusUnavailable, Result: taken}
+	routed.Notify(context.Background(), &monitor.Report{Results: []*domain.AvailabilityResult{app, taken}, Changes: []monitor.Change{changedTaken}})
+	if len(inner.reports) != 1 {
+		t.Errorf("Expected no notification when only a non-matching domain changed, got %d reports", len(inner.reports))
+	}
+
+	changedApp := monitor.Change{Domain: "app.io", Previous: domain.StatusUnavailable, Current: domain.StatusAvailable, Result: app}
+	routed.Notify(context.Background(), &monitor.Report{Results: []*domain.AvailabilityResult{app, taken}, Changes: []monitor.Change{changedApp, changedTaken}})
+	if len(inner.reports) != 2 || len(inner.reports[1].Changes) != 1 || inner.reports[1].Changes[0].Domain != "app.io" {
+		t.Errorf("Expected the matching change to be passed on, got %+v", inner.reports)
+	}
+}
+
+func TestRoutedNotifier_PriceChanges(t *testing.T) {
+	rules, _ := ParseRules([]config.NotifyRule{
+		{Status: []string{"AVAILABLE"}, Targets: []string{"email"}},
+		{PriceChange: true, TLDs: []string{".io"}, Targets: []string{"email"}},
+	})
+	inner := &recordingNotifier{}
+	routed := Route(inner, rules)
+
+	app := &domain.AvailabilityResult{Domain: "app.io", Status: domain.StatusAvailable}
+	ioChange := history.PriceChange{TLD: "io", Previous: history.PriceRecord{TLD: "io"}, Current: history.PriceRecord{TLD: "io"}}
+	comChange := history.PriceChange{TLD: "com", Previous: history.PriceRecord{TLD: "com"}, Current: history.PriceRecord{TLD: "com"}}
+
+	if rules[1].Match(app) {
+		t.Error("Expected a price change rule to match no result")
+	}
+
+	// A monitoring run in which only prices changed is passed on when a
+	// matching TLD changed, with only its change
+	routed.Notify(context.Background(), &monitor.Report{Results: []*domain.AvailabilityResult{app}, PriceChanges: []history.PriceChange{comChange, ioChange}})
+	if len(inner.reports) != 1 || len(inner.reports[0].PriceChanges) != 1 || inner.reports[0].PriceChanges[0].TLD != "io" {
+		t.Fatalf("Expected the .io price change to be passed on, got %+v", inner.reports)
+	}
+
+	// Results matching a status rule are not news when only prices changed
+	routed.Notify(context.Background(), &monitor.Report{Results: []*domain.AvailabilityResult{app}, PriceChanges: []history.PriceChange{comChange}})
+	if len(inner.reports) != 1 {
+		t.Errorf("Expected no notification when only a non-matching TLD changed price, got %d reports", len(inner.reports))
+	}
+}
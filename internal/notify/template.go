@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/abakermi/r53check/internal/domain"
+	"github.com/abakermi/r53check/internal/monitor"
+)
+
+// ReportData is what notification templates are rendered with
+type ReportData struct {
+	*monitor.Report
+	Summary Summary
+}
+
+// Summary counts the results of a report by outcome
+type Summary struct {
+	Total, Available, Unavailable, Errors int
+}
+
+// newReportData prepares report for rendering
+func newReportData(report *monitor.Report) ReportData {
+	return ReportData{Report: report, Summary: summarize(report.Results)}
+}
+
+// templateFuncs are available in every notification template
+var templateFuncs = template.FuncMap{
+	"table": resultsTable,
+	"price": formatPrice,
+	"json":  toJSON,
+}
+
+// resultsTable renders results as an aligned plain text table
+func resultsTable(results []*domain.AvailabilityResult) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DOMAIN\tSTATUS\tPRICE\tNOTE")
+	for _, result := range results {
+		note := result.Message
+		if result.Error != nil {
+			note = result.Error.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Domain, result.Status, formatPrice(result.Pricing), note)
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// formatPrice renders the registration price of pricing, or "-" without one
+func formatPrice(pricing *domain.PricingInfo) string {
+	if pricing == nil || pricing.RegistrationPrice == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f %s", *pricing.RegistrationPrice, pricing.Currency)
+}
+
+// summarize counts results by outcome
+func summarize(results []*domain.AvailabilityResult) Summary {
+	summary := Summary{Total: len(results)}
+	for _, result := range results {
+		switch {
+		case result.Error != nil:
+			summary.Errors++
+		case result.Available:
+			summary.Available++
+		default:
+			summary.Unavailable++
+		}
+	}
+	return summary
+}
+
+// toJSON encodes v as JSON, for building JSON payloads in templates
+func toJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,235 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/abakermi/r53check/internal/config"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/history"
+	"github.com/abakermi/r53check/internal/monitor"
+	"github.com/abakermi/r53check/internal/output"
+)
+
+// SignatureHeader carries the HMAC-SHA256 signature of a webhook body, as
+// "sha256=" followed by the hex digest
+const SignatureHeader = "X-R53check-Signature"
+
+const (
+	// defaultWebhookRetries is how often a failed delivery is retried
+	defaultWebhookRetries = 3
+
+	// defaultWebhookRetryDelay is the wait before the first retry; it
+	// doubles with every further retry
+	defaultWebhookRetryDelay = time.Second
+
+	// maxWebhookRetryAfter caps how long a server can ask us to wait
+	maxWebhookRetryAfter = time.Minute
+)
+
+// WebhookPayload is the JSON body posted when no template is configured
+type WebhookPayload struct {
+	RunID      string              `json:"run_id"`
+	StartedAt  time.Time           `json:"started_at"`
+	FinishedAt time.Time           `json:"finished_at"`
+	Tags       map[string]string   `json:"tags,omitempty"`
+	Results    []output.JSONResult `json:"results"`
+	Summary    output.JSONSummary  `json:"summary"`
+	Changes    []WebhookChange     `json:"changes,omitempty"`
+
+	PriceChanges []WebhookPriceChange `json:"price_changes,omitempty"`
+}
+
+// WebhookChange is the JSON representation of a status change
+type WebhookChange struct {
+	Domain   string `json:"domain"`
+	Previous string `json:"previous"`
+	Current  string `json:"current"`
+}
+
+// WebhookPriceChange is the JSON representation of a TLD price change
+type WebhookPriceChange struct {
+	TLD      string              `json:"tld"`
+	Previous history.PriceRecord `json:"previous"`
+	Current  history.PriceRecord `json:"current"`
+}
+
+// WebhookNotifier posts a report of each run it is notified about to a URL
+type WebhookNotifier struct {
+	client     *http.Client
+	url        *url.URL
+	body       *template.Template
+	secret     []byte
+	headers    map[string]string
+	retries    int
+	retryDelay time.Duration
+}
+
+// NewWebhookNotifier creates a notifier posting to the URL in settings with
+// client. Without a template the body is a WebhookPayload.
+func NewWebhookNotifier(settings config.WebhookSettings, client *http.Client) (*WebhookNotifier, error) {
+	target, err := url.Parse(settings.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, customErrors.NewValidationError("", "webhook.url", "an http or https webhook URL is required", err)
+	}
+
+	n := &WebhookNotifier{
+		client:     client,
+		url:        target,
+		secret:     []byte(settings.Secret),
+		headers:    settings.Headers,
+		retries:    settings.Retries,
+		retryDelay: defaultWebhookRetryDelay,
+	}
+	if n.retries == 0 {
+		n.retries = defaultWebhookRetries
+	}
+
+	if settings.Template != "" {
+		data, err := os.ReadFile(settings.Template)
+		if err != nil {
+			return nil, customErrors.NewValidationError("", "webhook.template", "unable to read payload template", err)
+		}
+		if n.body, err = template.New("payload").Funcs(templateFuncs).Parse(string(data)); err != nil {
+			return nil, customErrors.NewValidationError("", "webhook.template", "invalid payload template", err)
+		}
+	}
+	return n, nil
+}
+
+// SetRetryDelay sets the wait before the first retry
+func (n *WebhookNotifier) SetRetryDelay(delay time.Duration) {
+	n.retryDelay = delay
+}
+
+// Notify posts the payload for report, retrying throttled requests, server
+// errors and network failures with exponential backoff
+func (n *WebhookNotifier) Notify(ctx context.Context, report *monitor.Report) error {
+	body, err := n.Payload(report)
+	if err != nil {
+		return err
+	}
+
+	attempts := 1 + max(n.retries, 0)
+	delay := n.retryDelay
+	for attempt := 1; ; attempt++ {
+		wait, err := n.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if wait < 0 || attempt == attempts {
+			return customErrors.NewSystemError("webhook",
+				fmt.Sprintf("delivery to %s failed after %d attempt(s)", n.url.Host, attempt), err)
+		}
+
+		if wait == 0 {
+			wait = delay
+			delay *= 2
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return customErrors.WrapSystemError("webhook", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// Payload renders the request body for report
+func (n *WebhookNotifier) Payload(report *monitor.Report) ([]byte, error) {
+	if n.body != nil {
+		var buf bytes.Buffer
+		if err := n.body.Execute(&buf, newReportData(report)); err != nil {
+			return nil, customErrors.NewSystemError("webhook", "unable to render payload", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	bulk := output.NewJSONBulkResults(report.Results)
+	payload := WebhookPayload{
+		RunID:      report.RunID,
+		StartedAt:  report.StartedAt,
+		FinishedAt: report.FinishedAt,
+		Tags:       report.Tags,
+		Results:    bulk.Results,
+		Summary:    bulk.Summary,
+	}
+	for _, change := range report.Changes {
+		payload.Changes = append(payload.Changes, WebhookChange{
+			Domain:   change.Domain,
+			Previous: string(change.Previous),
+			Current:  string(change.Current),
+		})
+	}
+	for _, change := range report.PriceChanges {
+		payload.PriceChanges = append(payload.PriceChanges, WebhookPriceChange(change))
+	}
+	return json.Marshal(payload)
+}
+
+// Sign returns the signature header value for body under secret
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// post sends body once. On failure it also returns how long to wait before
+// retrying: the server's Retry-After, 0 for the default backoff, or a
+// negative duration when retrying cannot help.
+func (n *WebhookNotifier) post(ctx context.Context, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	for name, value := range n.headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "r53check")
+	if len(n.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, ctx.Err()
+		}
+		// The URL may embed a token, as Discord and Teams URLs do, so
+		// report the underlying error without it
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, nil
+	}
+	err = fmt.Errorf("webhook returned %s", resp.Status)
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return -1, err
+	}
+	if seconds, parseErr := strconv.Atoi(resp.Header.Get("Retry-After")); parseErr == nil && seconds > 0 {
+		return min(time.Duration(seconds)*time.Second, maxWebhookRetryAfter), err
+	}
+	return 0, err
+}
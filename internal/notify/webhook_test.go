@@ -0,0 +1,186 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/config"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+// webhookServer records the requests it receives and answers each with the
+// next status in statuses, then with 204
+type webhookServer struct {
+	*httptest.Server
+	statuses []int
+	calls    atomic.Int32
+	requests chan *http.Request
+	bodies   chan string
+}
+
+func newWebhookServer(t *testing.T, statuses ...int) *webhookServer {
+	t.Helper()
+
+	s := &webhookServer{statuses: statuses, requests: make(chan *http.Request, 10), bodies: make(chan string, 10)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.requests <- r
+		s.bodies <- string(body)
+
+		call := int(s.calls.Add(1))
+		if call <= len(s.statuses) {
+			w.WriteHeader(s.statuses[call-1])
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestWebhook(t *testing.T, settings config.WebhookSettings) *WebhookNotifier {
+	t.Helper()
+
+	notifier, err := NewWebhookNotifier(settings, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier() error: %v", err)
+	}
+	notifier.SetRetryDelay(time.Millisecond)
+	return notifier
+}
+
+func TestWebhookNotifier_DefaultPayload(t *testing.T) {
+	server := newWebhookServer(t)
+	notifier := newTestWebhook(t, config.WebhookSettings{
+		URL:     server.URL,
+		Secret:  "s3cret",
+		Headers: map[string]string{"authorization": "Bearer token"},
+	})
+
+	if err := notifier.Notify(context.Background(), testReport()); err != nil {
+		t.Fatalf("Notify() error: %v", err)
+	}
+
+	req, body := <-server.requests, <-server.bodies
+	if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected request %s with Content-Type %q", req.Method, req.Header.Get("Content-Type"))
+	}
+	if req.Header.Get("Authorization") != "Bearer token" {
+		t.Errorf("Expected the configured headers, got %v", req.Header)
+	}
+	if got, want := req.Header.Get(SignatureHeader), Sign([]byte("s3cret"), []byte(body)); got != want {
+		t.Errorf("Expected signature %q, got %q", want, got)
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		t.Fatalf("Invalid payload %q: %v", body, err)
+	}
+	if payload.RunID != "abc123" || payload.Tags["client"] != "acme" || len(payload.Results) != 3 || payload.Summary.Available != 1 || payload.Summary.Errors != 1 {
+		t.Errorf("Unexpected payload %+v", payload)
+	}
+	if len(payload.Changes) != 1 || payload.Changes[0] != (WebhookChange{Domain: "drop.com", Previous: "UNAVAILABLE", Current: "AVAILABLE"}) {
+		t.Errorf("Unexpected changes %+v", payload.Changes)
+	}
+}
+
+func TestWebhookNotifier_Template(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "discord.tmpl")
+	template := `{"content": {{json (printf "%d of %d available for %s" .Summary.Available .Summary.Total .Tags.client)}}}`
+	if err := os.WriteFile(path, []byte(template), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := newWebhookServer(t)
+	notifier := newTestWebhook(t, config.WebhookSettings{URL: server.URL, Template: path})
+	if err := notifier.Notify(context.Background(), testReport()); err != nil {
+		t.Fatalf("Notify() error: %v", err)
+	}
+
+	req, body := <-server.requests, <-server.bodies
+	if body != `{"content": "1 of 3 available for acme"}` {
+		t.Errorf("Unexpected body %q", body)
+	}
+	if req.Header.Get(SignatureHeader) != "" {
+		t.Error("Expected no signature without a secret")
+	}
+}
+
+func TestWebhookNotifier_Retries(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		retries   int
+		wantCalls int32
+		wantErr   bool
+	}{
+		{"success", nil, 0, 1, false},
+		{"server errors then success", []int{502, 503}, 0, 3, false},
+		{"throttled then success", []int{429}, 0, 2, false},
+		{"client error is not retried", []int{400}, 0, 1, true},
+		{"retries exhausted", []int{500, 500, 500, 500}, 0, 4, true},
+		{"custom retries", []int{500, 500}, 1, 2, true},
+		{"retries disabled", []int{500}, -1, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newWebhookServer(t, tt.statuses...)
+			notifier := newTestWebhook(t, config.WebhookSettings{URL: server.URL, Retries: tt.retries})
+
+			err := notifier.Notify(context.Background(), testReport())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls := server.calls.Load(); calls != tt.wantCalls {
+				t.Errorf("Expected %d requests, got %d", tt.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier_ErrorHidesURL(t *testing.T) {
+	server := newWebhookServer(t)
+	server.Close()
+
+	notifier := newTestWebhook(t, config.WebhookSettings{URL: server.URL + "/api/webhooks/token", Retries: -1})
+	err := notifier.Notify(context.Background(), testReport())
+	if err == nil {
+		t.Fatal("Expected an error from a closed server")
+	}
+	if strings.Contains(err.Error(), "token") {
+		t.Errorf("Expected the error not to contain the URL path, got %v", err)
+	}
+}
+
+func TestNewWebhookNotifier_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings config.WebhookSettings
+		field    string
+	}{
+		{"no URL", config.WebhookSettings{}, "webhook.url"},
+		{"unsupported scheme", config.WebhookSettings{URL: "ftp://example.com"}, "webhook.url"},
+		{"missing template", config.WebhookSettings{URL: "https://example.com", Template: "/does/not/exist"}, "webhook.template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWebhookNotifier(tt.settings, http.DefaultClient)
+			var validationErr *customErrors.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("Expected a validation error for %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
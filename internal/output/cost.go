@@ -0,0 +1,40 @@
+package output
+
+import "github.com/abakermi/r53check/internal/domain"
+
+// CostTotal is the cost of holding every available domain priced in a
+// currency for a number of years
+type CostTotal struct {
+	Currency string  `json:"currency"`
+	Total    float64 `json:"total"`
+	Domains  int     `json:"domains"`
+}
+
+// CostTotals adds up the cost over years of the available domains of
+// results, one total per currency in the order the currencies first appear,
+// and counts the available domains left out because a price is unknown
+func CostTotals(results []*domain.AvailabilityResult, years int) ([]CostTotal, int) {
+	var totals []CostTotal
+	unpriced := 0
+	for _, result := range results {
+		if result == nil || result.Skipped || result.Error != nil || !result.Available {
+			continue
+		}
+		cost, ok := result.Pricing.Cost(years)
+		if !ok {
+			unpriced++
+			continue
+		}
+
+		i := 0
+		for i < len(totals) && totals[i].Currency != result.Pricing.Currency {
+			i++
+		}
+		if i == len(totals) {
+			totals = append(totals, CostTotal{Currency: result.Pricing.Currency})
+		}
+		totals[i].Total += cost
+		totals[i].Domains++
+	}
+	return totals, unpriced
+}
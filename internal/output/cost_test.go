@@ -0,0 +1,45 @@
+package output
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+// priced returns pricing with the given registration and renewal prices
+func priced(registration, renewal float64, currency string) *domain.PricingInfo {
+	return &domain.PricingInfo{RegistrationPrice: &registration, RenewalPrice: &renewal, Currency: currency}
+}
+
+func TestCostTotals(t *testing.T) {
+	registration := 10.0
+	results := []*domain.AvailabilityResult{
+		{Domain: "free.com", Available: true, Status: domain.StatusAvailable, Pricing: priced(12, 15, "USD")},
+		{Domain: "free.io", Available: true, Status: domain.StatusAvailable, Pricing: priced(40, 50, "USD")},
+		{Domain: "free.de", Available: true, Status: domain.StatusAvailable, Pricing: priced(8, 9, "EUR")},
+		{Domain: "partial.net", Available: true, Status: domain.StatusAvailable, Pricing: &domain.PricingInfo{RegistrationPrice: &registration, Currency: "USD"}},
+		{Domain: "unpriced.org", Available: true, Status: domain.StatusAvailable},
+		{Domain: "taken.com", Status: domain.StatusUnavailable, Pricing: priced(12, 15, "USD")},
+		nil,
+	}
+
+	tests := []struct {
+		name     string
+		years    int
+		totals   []CostTotal
+		unpriced int
+	}{
+		{"one year", 1, []CostTotal{{"USD", 62, 3}, {"EUR", 8, 1}}, 1},
+		{"three years", 3, []CostTotal{{"USD", 182, 2}, {"EUR", 26, 1}}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			totals, unpriced := CostTotals(results, tt.years)
+			if !reflect.DeepEqual(totals, tt.totals) || unpriced != tt.unpriced {
+				t.Errorf("CostTotals() = %v, %d, want %v, %d", totals, unpriced, tt.totals, tt.unpriced)
+			}
+		})
+	}
+}
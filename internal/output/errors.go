@@ -0,0 +1,65 @@
+package output
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+// Error kinds group bulk failures by what went wrong
+const (
+	ErrorKindThrottled      = "throttled"
+	ErrorKindTimeout        = "timeout"
+	ErrorKindAuthentication = "authentication"
+	ErrorKindAuthorization  = "authorization"
+	ErrorKindValidation     = "validation"
+	ErrorKindAPI            = "api"
+	ErrorKindSystem         = "system"
+)
+
+// ErrorGroup collects the domains of a bulk run that failed the same way
+type ErrorGroup struct {
+	Kind    string   `json:"kind"`
+	Count   int      `json:"count"`
+	Domains []string `json:"domains"`
+
+	// Message is the error of the first domain in the group, as an example
+	Message string `json:"message"`
+}
+
+// ErrorKind classifies err into one of the ErrorKind constants
+func ErrorKind(err error) string {
+	if customErrors.IsThrottling(err) {
+		return ErrorKindThrottled
+	}
+	return strings.ToLower(string(customErrors.GetCategory(err)))
+}
+
+// GroupErrors groups the failed results by kind of failure, largest group
+// first. Domains skipped by an aborted run are left out.
+func GroupErrors(results []*domain.AvailabilityResult) []ErrorGroup {
+	var groups []ErrorGroup
+	index := make(map[string]int)
+	for _, result := range results {
+		if result == nil || result.Error == nil || result.Skipped {
+			continue
+		}
+
+		kind := ErrorKind(result.Error)
+		i, ok := index[kind]
+		if !ok {
+			i = len(groups)
+			index[kind] = i
+			groups = append(groups, ErrorGroup{Kind: kind, Message: result.Error.Error()})
+		}
+		groups[i].Count++
+		groups[i].Domains = append(groups[i].Domains, result.Domain)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+	return groups
+}
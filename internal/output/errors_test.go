@@ -0,0 +1,71 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+func failed(name string, err error) *domain.AvailabilityResult {
+	return &domain.AvailabilityResult{Domain: name, Status: domain.StatusUnknown, Error: err}
+}
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"throttled", customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "rate limited", nil).WithStatusCode(429), ErrorKindThrottled},
+		{"unavailable", customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "unavailable", nil).WithStatusCode(503), ErrorKindAPI},
+		{"timeout", customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "failed", context.DeadlineExceeded), ErrorKindTimeout},
+		{"authorization", customErrors.NewAuthorizationError("CheckDomainAvailability", "route53domains", "denied", nil), ErrorKindAuthorization},
+		{"validation", customErrors.NewValidationError("bad-.com", "format", "invalid", nil), ErrorKindValidation},
+		{"plain", errors.New("boom"), ErrorKindSystem},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorKind(tt.err); got != tt.expected {
+				t.Errorf("ErrorKind(%v) = %q, want %q", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGroupErrors(t *testing.T) {
+	throttled := customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "rate limited", nil).WithStatusCode(429)
+	denied := customErrors.NewAuthorizationError("CheckDomainAvailability", "route53domains", "denied", nil)
+
+	results := []*domain.AvailabilityResult{
+		{Domain: "ok.com", Status: domain.StatusAvailable, Available: true},
+		failed("denied.com", denied),
+		failed("a.com", throttled),
+		failed("b.com", throttled),
+		{Domain: "skipped.com", Status: domain.StatusUnknown, Error: errors.New("skipped"), Skipped: true},
+		nil,
+	}
+
+	groups := GroupErrors(results)
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %+v", groups)
+	}
+	if groups[0].Kind != ErrorKindThrottled || groups[0].Count != 2 || !slices.Equal(groups[0].Domains, []string{"a.com", "b.com"}) {
+		t.Errorf("Expected the throttled domains first, got %+v", groups[0])
+	}
+	if !strings.Contains(groups[0].Message, "rate limited") {
+		t.Errorf("Expected an example message, got %q", groups[0].Message)
+	}
+	if groups[1].Kind != ErrorKindAuthorization || groups[1].Count != 1 {
+		t.Errorf("Expected the authorization failure second, got %+v", groups[1])
+	}
+
+	if groups := GroupErrors(results[:1]); groups != nil {
+		t.Errorf("Expected no groups without errors, got %+v", groups)
+	}
+}
@@ -1,10 +1,16 @@
 package output
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"strings"
+	"time"
 
 	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/i18n"
 )
 
 // Formatter interface defines methods for formatting output
@@ -20,6 +26,15 @@ type ConsoleFormatter struct {
 	ShowTimestamp bool
 	// Verbose controls the level of detail in output
 	Verbose bool
+	// Localizer translates results and error guidance; nil renders English
+	Localizer *i18n.Localizer
+	// SummaryOnly leaves the per-domain results out of bulk output
+	SummaryOnly bool
+	// ListAvailable lists the available domains after a bulk summary
+	ListAvailable bool
+	// Years adds the cost of holding a domain for this many years to its
+	// prices, and the total for the available domains to a bulk summary
+	Years int
 }
 
 // NewConsoleFormatter creates a new console formatter with default settings
@@ -41,7 +56,7 @@ func NewVerboseConsoleFormatter() *ConsoleFormatter {
 // FormatResult formats a domain availability result for console output
 func (f *ConsoleFormatter) FormatResult(result *domain.AvailabilityResult) string {
 	if result == nil {
-		return "Error: No result to format"
+		return f.t(msgNoResult, nil)
 	}
 
 	var output strings.Builder
@@ -52,53 +67,138 @@ func (f *ConsoleFormatter) FormatResult(result *domain.AvailabilityResult) strin
 	}
 
 	// Format the main result based on availability
+	data := map[string]interface{}{"Domain": displayName(result), "Status": result.Status}
 	switch result.Status {
 	case domain.StatusAvailable:
-		output.WriteString(fmt.Sprintf("✓ %s is AVAILABLE for registration", result.Domain))
+		output.WriteString("✓ " + f.t(msgResultAvailable, data))
 	case domain.StatusUnavailable:
-		output.WriteString(fmt.Sprintf("✗ %s is UNAVAILABLE (already registered)", result.Domain))
+		output.WriteString("✗ " + f.t(msgResultUnavailable, data))
 	case domain.StatusReserved:
-		output.WriteString(fmt.Sprintf("⚠ %s is RESERVED and cannot be registered", result.Domain))
+		output.WriteString("⚠ " + f.t(msgResultReserved, data))
 	case domain.StatusUnknown:
-		output.WriteString(fmt.Sprintf("? %s availability is UNKNOWN", result.Domain))
+		output.WriteString("? " + f.t(msgResultUnknown, data))
 	default:
-		output.WriteString(fmt.Sprintf("? %s has unknown status: %s", result.Domain, result.Status))
+		output.WriteString("? " + f.t(msgResultUnknownStatus, data))
+	}
+
+	if result.Unverified {
+		output.WriteString("\n⚠ " + f.t(msgUnverified, map[string]interface{}{"Source": result.Source}))
+	}
+
+	// Show screening warnings before anything else about the domain
+	for _, warning := range result.Warnings {
+		output.WriteString("\n⚠ " + f.t(msgWarning, map[string]interface{}{"Warning": warning}))
 	}
 
 	// Add pricing information if available
 	if result.Pricing != nil {
-		output.WriteString("\nPricing:")
-		if result.Pricing.RegistrationPrice != nil {
-			output.WriteString(fmt.Sprintf("\n  Registration: $%.2f %s", *result.Pricing.RegistrationPrice, result.Pricing.Currency))
-		}
-		if result.Pricing.RenewalPrice != nil {
-			output.WriteString(fmt.Sprintf("\n  Renewal: $%.2f %s", *result.Pricing.RenewalPrice, result.Pricing.Currency))
-		}
-		if result.Pricing.TransferPrice != nil {
-			output.WriteString(fmt.Sprintf("\n  Transfer: $%.2f %s", *result.Pricing.TransferPrice, result.Pricing.Currency))
+		output.WriteString("\n" + f.t(msgPricing, nil))
+		for _, line := range f.priceLines(result.Pricing) {
+			output.WriteString("\n  " + line)
 		}
 	}
 
+	if len(result.Alternatives) > 0 {
+		output.WriteString("\n↪ " + f.t(msgAlternatives, map[string]interface{}{"Domains": alternativeList(result.Alternatives)}))
+	}
+
 	// Add verbose information if requested
 	if f.Verbose {
-		output.WriteString(fmt.Sprintf("\nStatus: %s", result.Status))
+		output.WriteString(fmt.Sprintf("\n%s: %s", f.t(msgStatus, nil), result.Status))
 		if result.Message != "" {
-			output.WriteString(fmt.Sprintf("\nMessage: %s", result.Message))
+			output.WriteString(fmt.Sprintf("\n%s: %s", f.t(msgMessage, nil), result.Message))
 		}
 		if f.ShowTimestamp {
-			output.WriteString(fmt.Sprintf("\nChecked at: %s", result.CheckedAt.Format("2006-01-02 15:04:05 MST")))
+			output.WriteString(fmt.Sprintf("\n%s: %s", f.t(msgCheckedAt, nil), result.CheckedAt.Format("2006-01-02 15:04:05 MST")))
 		}
 	}
 
 	return output.String()
 }
 
-// FormatError formats various error types with clear, actionable messages
+// displayName shows an internationalized domain in both its Unicode and ASCII forms
+func displayName(result *domain.AvailabilityResult) string {
+	if result.UnicodeDomain != "" && result.UnicodeDomain != result.Domain {
+		return fmt.Sprintf("%s (%s)", result.UnicodeDomain, result.Domain)
+	}
+	return result.Domain
+}
+
+// t renders msg in the formatter's language
+func (f *ConsoleFormatter) t(msg *i18n.Message, data map[string]interface{}) string {
+	return f.Localizer.Localize(msg, data)
+}
+
+// FormatError formats various error types with clear, actionable messages.
+// Errors are classified by their type, wrapped or not; the message is only
+// inspected for errors that carry no type information.
 func (f *ConsoleFormatter) FormatError(err error) string {
 	if err == nil {
 		return ""
 	}
 
+	// List every problem when validation found more than one
+	var validationErrs *customErrors.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return f.formatValidationErrors(validationErrs)
+	}
+
+	if formatted, ok := f.formatTypedError(err); ok {
+		return formatted
+	}
+	return f.formatErrorByMessage(err)
+}
+
+// formatTypedError formats errors recognized by their type or AWS error
+// code, reporting false for errors it cannot classify
+func (f *ConsoleFormatter) formatTypedError(err error) (string, bool) {
+	// Giving up waiting wins over the error a timed out call was wrapped in
+	var timeoutErr *customErrors.TimeoutError
+	if errors.As(err, &timeoutErr) || errors.Is(err, context.DeadlineExceeded) {
+		return f.formatTimeoutError(), true
+	}
+	if customErrors.IsThrottling(err) {
+		return f.formatRateLimitError(), true
+	}
+
+	var authenticationErr *customErrors.AuthenticationError
+	if errors.As(err, &authenticationErr) {
+		return f.formatAuthenticationError(), true
+	}
+	var authorizationErr *customErrors.AuthorizationError
+	if errors.As(err, &authorizationErr) {
+		return f.formatAuthorizationError(), true
+	}
+	var validationErr *customErrors.ValidationError
+	if errors.As(err, &validationErr) {
+		if validationErr.Domain == "" {
+			// Invalid flags and settings, rather than a malformed domain
+			return f.t(msgError, map[string]interface{}{"Message": err.Error()}), true
+		}
+		return f.formatDomainValidationError(err.Error()), true
+	}
+
+	// AWS SDK errors that were not wrapped into the types above
+	switch customErrors.GetErrorCode(err) {
+	case "NoCredentialsErr", "CredentialsNotFound", "InvalidAccessKeyId", "SignatureDoesNotMatch":
+		return f.formatAuthenticationError(), true
+	case "UnauthorizedOperation", "AccessDenied", "AccessDeniedException", "Forbidden":
+		return f.formatAuthorizationError(), true
+	case "InvalidDomainName":
+		return f.formatDomainValidationError(err.Error()), true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return f.formatNetworkError(err.Error()), true
+	}
+
+	return "", false
+}
+
+// formatErrorByMessage classifies errors by their message, the last resort
+// for plain errors
+func (f *ConsoleFormatter) formatErrorByMessage(err error) string {
 	errorMsg := err.Error()
 
 	// Handle specific AWS error types with helpful messages
@@ -131,83 +231,68 @@ func (f *ConsoleFormatter) FormatError(err error) string {
 	}
 
 	// Generic error formatting
-	return fmt.Sprintf("Error: %s", errorMsg)
+	return f.t(msgError, map[string]interface{}{"Message": errorMsg})
 }
 
 // formatAuthenticationError provides helpful guidance for credential issues
 func (f *ConsoleFormatter) formatAuthenticationError() string {
-	var output strings.Builder
-	output.WriteString("✗ Authentication Error: AWS credentials not found\n")
-	output.WriteString("\nTo fix this issue, try one of the following:\n")
-	output.WriteString("  1. Set environment variables:\n")
-	output.WriteString("     export AWS_ACCESS_KEY_ID=your-access-key\n")
-	output.WriteString("     export AWS_SECRET_ACCESS_KEY=your-secret-key\n")
-	output.WriteString("  2. Configure AWS CLI: aws configure\n")
-	output.WriteString("  3. Use IAM roles if running on EC2/ECS/Lambda\n")
-	output.WriteString("  4. Set AWS_PROFILE environment variable to use a specific profile")
-	return output.String()
+	return f.formatGuidance(msgAuthenticationError, msgAuthenticationHelp)
 }
 
 // formatAuthorizationError provides guidance for permission issues
 func (f *ConsoleFormatter) formatAuthorizationError() string {
-	var output strings.Builder
-	output.WriteString("✗ Authorization Error: Insufficient permissions\n")
-	output.WriteString("\nTo fix this issue:\n")
-	output.WriteString("  1. Ensure your AWS user/role has the 'route53domains:CheckDomainAvailability' permission\n")
-	output.WriteString("  2. Check if your account has access to Route 53 Domains service\n")
-	output.WriteString("  3. Verify you're using the correct AWS region (Route 53 Domains is global)")
-	return output.String()
+	return f.formatGuidance(msgAuthorizationError, msgAuthorizationHelp)
 }
 
 // formatDomainValidationError provides specific guidance for domain format issues
 func (f *ConsoleFormatter) formatDomainValidationError(errorMsg string) string {
 	var output strings.Builder
-	output.WriteString("✗ Domain Validation Error\n")
-	output.WriteString(fmt.Sprintf("Details: %s\n", errorMsg))
-	output.WriteString("\nDomain format requirements:\n")
-	output.WriteString("  • Must be a valid domain name (e.g., example.com)\n")
-	output.WriteString("  • Must include a supported TLD (.com, .net, .org, .io, etc.)\n")
-	output.WriteString("  • Cannot contain spaces or special characters\n")
-	output.WriteString("  • Must be between 1-63 characters per label")
+	output.WriteString("✗ " + f.t(msgValidationError, nil) + "\n")
+	output.WriteString(f.t(msgDetails, map[string]interface{}{"Details": errorMsg}) + "\n")
+	f.writeDomainRequirements(&output)
 	return output.String()
 }
 
-// formatRateLimitError provides guidance for API rate limiting
-func (f *ConsoleFormatter) formatRateLimitError() string {
+// formatValidationErrors lists every validation problem found for a domain
+func (f *ConsoleFormatter) formatValidationErrors(errs *customErrors.ValidationErrors) string {
 	var output strings.Builder
-	output.WriteString("✗ Rate Limit Error: Too many requests to AWS API\n")
-	output.WriteString("\nTo fix this issue:\n")
-	output.WriteString("  • Wait a few seconds and try again\n")
-	output.WriteString("  • AWS Route 53 Domains has rate limits to prevent abuse\n")
-	output.WriteString("  • Consider implementing delays between requests if checking multiple domains")
+	output.WriteString("✗ " + f.Localizer.Plural(msgValidationProblems, len(errs.Errors), map[string]interface{}{"Domain": errs.Domain}) + "\n")
+	for _, err := range errs.Errors {
+		output.WriteString(fmt.Sprintf("  • %s\n", err.Message))
+	}
+	f.writeDomainRequirements(&output)
 	return output.String()
 }
 
+// writeDomainRequirements appends the summary of domain format rules
+func (f *ConsoleFormatter) writeDomainRequirements(output *strings.Builder) {
+	output.WriteString("\n" + f.t(msgDomainRequirements, nil))
+}
+
+// formatRateLimitError provides guidance for API rate limiting
+func (f *ConsoleFormatter) formatRateLimitError() string {
+	return f.formatGuidance(msgRateLimitError, msgRateLimitHelp)
+}
+
 // formatTimeoutError provides guidance for timeout issues
 func (f *ConsoleFormatter) formatTimeoutError() string {
-	var output strings.Builder
-	output.WriteString("✗ Timeout Error: Request took too long to complete\n")
-	output.WriteString("\nPossible causes:\n")
-	output.WriteString("  • Slow network connection\n")
-	output.WriteString("  • AWS service temporarily unavailable\n")
-	output.WriteString("  • Request timeout set too low\n")
-	output.WriteString("\nTry running the command again or check your network connection.")
-	return output.String()
+	return f.formatGuidance(msgTimeoutError, msgTimeoutHelp)
 }
 
 // formatNetworkError provides guidance for network-related issues
 func (f *ConsoleFormatter) formatNetworkError(errorMsg string) string {
 	var output strings.Builder
-	output.WriteString("✗ Network Error: Unable to connect to AWS services\n")
-	output.WriteString(fmt.Sprintf("Details: %s\n", errorMsg))
-	output.WriteString("\nPossible solutions:\n")
-	output.WriteString("  • Check your internet connection\n")
-	output.WriteString("  • Verify firewall settings allow HTTPS traffic\n")
-	output.WriteString("  • Check if you're behind a corporate proxy\n")
-	output.WriteString("  • Try again in a few minutes")
+	output.WriteString("✗ " + f.t(msgNetworkError, nil) + "\n")
+	output.WriteString(f.t(msgDetails, map[string]interface{}{"Details": errorMsg}) + "\n")
+	output.WriteString("\n" + f.t(msgNetworkHelp, nil))
 	return output.String()
 }
 
+// formatGuidance renders an error heading followed by advice on fixing it
+func (f *ConsoleFormatter) formatGuidance(heading, help *i18n.Message) string {
+	return "✗ " + f.t(heading, nil) + "\n\n" + f.t(help, nil)
+}
+
 // SetVerbose enables or disables verbose output
 func (f *ConsoleFormatter) SetVerbose(verbose bool) {
 	f.Verbose = verbose
@@ -218,6 +303,29 @@ func (f *ConsoleFormatter) SetShowTimestamp(show bool) {
 	f.ShowTimestamp = show
 }
 
+// SetSummaryOnly enables or disables leaving per-domain results out of bulk
+// output
+func (f *ConsoleFormatter) SetSummaryOnly(summaryOnly bool) {
+	f.SummaryOnly = summaryOnly
+}
+
+// SetListAvailable enables or disables listing the available domains after
+// a bulk summary
+func (f *ConsoleFormatter) SetListAvailable(list bool) {
+	f.ListAvailable = list
+}
+
+// SetYears sets the number of years costs are projected over; 0 leaves
+// them out
+func (f *ConsoleFormatter) SetYears(years int) {
+	f.Years = years
+}
+
+// SetLocalizer sets the language of results and error guidance
+func (f *ConsoleFormatter) SetLocalizer(localizer *i18n.Localizer) {
+	f.Localizer = localizer
+}
+
 // IsVerbose returns whether verbose mode is enabled
 func (f *ConsoleFormatter) IsVerbose() bool {
 	return f.Verbose
@@ -231,7 +339,7 @@ func (f *ConsoleFormatter) ShowsTimestamp() bool {
 // FormatBulkResults formats multiple domain availability results
 func (f *ConsoleFormatter) FormatBulkResults(results []*domain.AvailabilityResult) string {
 	if len(results) == 0 {
-		return "No domains to check"
+		return f.t(msgNoDomains, nil)
 	}
 
 	var output strings.Builder
@@ -240,14 +348,29 @@ func (f *ConsoleFormatter) FormatBulkResults(results []*domain.AvailabilityResul
 	availableCount := 0
 	unavailableCount := 0
 	errorCount := 0
+	skippedCount := 0
+	fromHistoryCount := 0
+	prescreenedCount := 0
+	var retryFailures []string
 
 	for _, result := range results {
 		if result == nil {
 			errorCount++
 			continue
 		}
-		if result.Error != nil {
+		if result.FromHistory {
+			fromHistoryCount++
+		}
+		if result.Prescreened {
+			prescreenedCount++
+		}
+		if result.Skipped {
+			skippedCount++
+		} else if result.Error != nil {
 			errorCount++
+			if result.Retried {
+				retryFailures = append(retryFailures, result.Domain)
+			}
 		} else if result.Available {
 			availableCount++
 		} else {
@@ -255,58 +378,219 @@ func (f *ConsoleFormatter) FormatBulkResults(results []*domain.AvailabilityResul
 		}
 	}
 
-	output.WriteString(fmt.Sprintf("Bulk Domain Check Results (%d domains)\n", len(results)))
-	output.WriteString(strings.Repeat("=", 50) + "\n\n")
+	output.WriteString(f.Localizer.Plural(msgBulkTitle, len(results), nil) + "\n")
+	output.WriteString(strings.Repeat("=", 50) + "\n")
+	if skippedCount > 0 {
+		output.WriteString("⚠ " + f.t(msgBulkIncomplete, map[string]interface{}{"Count": skippedCount}) + "\n")
+	}
+	if !f.SummaryOnly {
+		output.WriteString("\n")
+	}
 
-	// Individual results
-	for _, result := range results {
+	// Individual results, left out of summaries
+	listed := results
+	if f.SummaryOnly {
+		listed = nil
+	}
+	for _, result := range listed {
 		if result == nil {
-			output.WriteString("? UNKNOWN: Invalid result\n")
+			output.WriteString("? " + f.t(msgInvalidResult, nil) + "\n")
 			continue
 		}
 
+		data := map[string]interface{}{"Domain": displayName(result)}
+		if result.Skipped {
+			output.WriteString("- " + f.t(msgBulkSkipped, data) + "\n")
+			continue
+		}
+
+		// Error messages are grouped after the summary; verbose output
+		// also repeats each one next to its domain
 		if result.Error != nil {
-			output.WriteString(fmt.Sprintf("✗ %s: ERROR - %s\n", result.Domain, result.Error.Error()))
+			detail := ErrorKind(result.Error)
+			if result.Retries > 1 {
+				detail += ", " + f.t(msgAfterRetries, map[string]interface{}{"Count": result.Retries})
+			} else if result.Retried {
+				detail += ", " + f.t(msgAfterRetry, nil)
+			}
+			data["Detail"] = detail
+			output.WriteString("✗ " + f.t(msgBulkError, data))
+			if f.Verbose {
+				output.WriteString(" - " + result.Error.Error())
+			}
+			output.WriteString("\n")
 			continue
 		}
 
 		switch result.Status {
 		case domain.StatusAvailable:
-			output.WriteString(fmt.Sprintf("✓ %s: AVAILABLE\n", result.Domain))
+			output.WriteString("✓ " + f.t(msgBulkAvailable, data) + "\n")
 		case domain.StatusUnavailable:
-			output.WriteString(fmt.Sprintf("✗ %s: UNAVAILABLE (already registered)\n", result.Domain))
+			output.WriteString("✗ " + f.t(msgBulkUnavailable, data) + "\n")
 		case domain.StatusReserved:
-			output.WriteString(fmt.Sprintf("⚠ %s: RESERVED (cannot be registered)\n", result.Domain))
+			output.WriteString("⚠ " + f.t(msgBulkReserved, data) + "\n")
 		case domain.StatusUnknown:
-			output.WriteString(fmt.Sprintf("? %s: UNKNOWN (unable to determine)\n", result.Domain))
+			output.WriteString("? " + f.t(msgBulkUnknown, data) + "\n")
 		default:
-			output.WriteString(fmt.Sprintf("? %s: UNKNOWN STATUS\n", result.Domain))
+			output.WriteString("? " + f.t(msgBulkUnknownStatus, data) + "\n")
+		}
+
+		if result.Unverified && result.Error == nil {
+			output.WriteString("  ⚠ " + f.t(msgUnverified, map[string]interface{}{"Source": result.Source}) + "\n")
+		}
+
+		for _, warning := range result.Warnings {
+			output.WriteString("  ⚠ " + f.t(msgWarning, map[string]interface{}{"Warning": warning}) + "\n")
+		}
+
+		if result.Note != "" {
+			output.WriteString("  ✎ " + result.Note + "\n")
+		}
+
+		if len(result.Alternatives) > 0 {
+			output.WriteString("  ↪ " + f.t(msgAlternatives, map[string]interface{}{"Domains": alternativeList(result.Alternatives)}) + "\n")
+		}
+
+		if result.FromHistory {
+			output.WriteString("  ↺ " + f.t(msgFromHistory, map[string]interface{}{"Age": formatAge(time.Since(result.CheckedAt))}) + "\n")
+		}
+
+		if result.Prescreened {
+			output.WriteString("  ≈ " + f.t(msgPrescreened, nil) + "\n")
 		}
 
 		// Add pricing information if available
 		if result.Pricing != nil && result.Error == nil {
-			if result.Pricing.RegistrationPrice != nil {
-				output.WriteString(fmt.Sprintf("  Registration: $%.2f %s\n", *result.Pricing.RegistrationPrice, result.Pricing.Currency))
+			for _, line := range f.priceLines(result.Pricing) {
+				output.WriteString("  " + line + "\n")
 			}
 		}
 
 		// Add verbose details if enabled
 		if f.Verbose && result.Error == nil {
-			output.WriteString(fmt.Sprintf("  Message: %s\n", result.Message))
+			output.WriteString(fmt.Sprintf("  %s: %s\n", f.t(msgMessage, nil), result.Message))
 			if f.ShowTimestamp {
-				output.WriteString(fmt.Sprintf("  Checked: %s\n", result.CheckedAt.Format("2006-01-02 15:04:05 MST")))
+				output.WriteString(fmt.Sprintf("  %s: %s\n", f.t(msgChecked, nil), result.CheckedAt.Format("2006-01-02 15:04:05 MST")))
 			}
 		}
 	}
 
 	// Summary footer
-	output.WriteString("\n" + strings.Repeat("=", 50) + "\n")
-	output.WriteString("Summary:\n")
-	output.WriteString(fmt.Sprintf("  ✓ Available: %d\n", availableCount))
-	output.WriteString(fmt.Sprintf("  ✗ Unavailable: %d\n", unavailableCount))
+	if !f.SummaryOnly {
+		output.WriteString("\n" + strings.Repeat("=", 50) + "\n")
+	}
+	output.WriteString(f.t(msgSummary, nil) + "\n")
+	output.WriteString("  ✓ " + f.t(msgSummaryAvailable, map[string]interface{}{"Count": availableCount}) + "\n")
+	output.WriteString("  ✗ " + f.t(msgSummaryUnavailable, map[string]interface{}{"Count": unavailableCount}) + "\n")
 	if errorCount > 0 {
-		output.WriteString(fmt.Sprintf("  ⚠ Errors: %d\n", errorCount))
+		output.WriteString("  ⚠ " + f.t(msgSummaryErrors, map[string]interface{}{"Count": errorCount}) + "\n")
+	}
+	if skippedCount > 0 {
+		output.WriteString("  - " + f.t(msgSummarySkipped, map[string]interface{}{"Count": skippedCount}) + "\n")
+	}
+	if fromHistoryCount > 0 {
+		output.WriteString("  ↺ " + f.t(msgSummaryFromHistory, map[string]interface{}{"Count": fromHistoryCount}) + "\n")
+	}
+	if prescreenedCount > 0 {
+		output.WriteString("  ≈ " + f.t(msgSummaryPrescreened, map[string]interface{}{"Count": prescreenedCount}) + "\n")
+	}
+	if f.Years > 0 {
+		totals, unpriced := CostTotals(results, f.Years)
+		for _, total := range totals {
+			data := map[string]interface{}{"Total": fmt.Sprintf("$%.2f %s", total.Total, total.Currency), "Domains": total.Domains}
+			output.WriteString("  $ " + f.Localizer.Plural(msgSummaryCost, f.Years, data) + "\n")
+		}
+		if unpriced > 0 {
+			output.WriteString("  $ " + f.t(msgSummaryUnpriced, map[string]interface{}{"Count": unpriced}) + "\n")
+		}
+	}
+	if len(retryFailures) > 0 {
+		output.WriteString("  ↻ " + f.t(msgSummaryRetryFailure, map[string]interface{}{"Domains": strings.Join(retryFailures, ", ")}) + "\n")
+	}
+
+	if f.ListAvailable {
+		if available := AvailableDomains(results); len(available) > 0 {
+			output.WriteString("\n" + f.t(msgAvailableDomains, nil) + "\n")
+			for _, name := range available {
+				output.WriteString("  ✓ " + name + "\n")
+			}
+		}
+	}
+
+	if groups := GroupErrors(results); len(groups) > 0 {
+		output.WriteString("\n" + f.t(msgErrors, nil) + "\n")
+		for _, group := range groups {
+			f.writeErrorGroup(&output, group)
+		}
 	}
 
 	return output.String()
 }
+
+// maxGroupDomains caps the domains listed per error group in console output
+const maxGroupDomains = 10
+
+// writeErrorGroup appends an error group: how many domains failed that way,
+// which ones, and an example of the error
+func (f *ConsoleFormatter) writeErrorGroup(output *strings.Builder, group ErrorGroup) {
+	domains := strings.Join(group.Domains, ", ")
+	if len(group.Domains) > maxGroupDomains {
+		domains = strings.Join(group.Domains[:maxGroupDomains], ", ") + " " +
+			f.t(msgMore, map[string]interface{}{"Count": len(group.Domains) - maxGroupDomains})
+	}
+
+	data := map[string]interface{}{"Label": f.t(errorKindLabels[group.Kind], nil), "Domains": domains}
+	output.WriteString("  ✗ " + f.Localizer.Plural(msgErrorGroup, group.Count, data) + "\n")
+	output.WriteString(fmt.Sprintf("    %s\n", group.Message))
+}
+
+// formatAge renders how long ago something happened in its largest whole unit
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "less than a minute"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
+// priceLines returns one line per known price: registration, renewal and
+// transfer, followed by the cost over the projected years when it is known
+func (f *ConsoleFormatter) priceLines(pricing *domain.PricingInfo) []string {
+	prices := []struct {
+		label *i18n.Message
+		price *float64
+	}{
+		{msgRegistration, pricing.RegistrationPrice},
+		{msgRenewal, pricing.RenewalPrice},
+		{msgTransfer, pricing.TransferPrice},
+	}
+
+	var lines []string
+	for _, p := range prices {
+		if p.price != nil {
+			lines = append(lines, fmt.Sprintf("%s: $%.2f %s", f.t(p.label, nil), *p.price, pricing.Currency))
+		}
+	}
+	if cost, ok := pricing.Cost(f.Years); ok {
+		lines = append(lines, fmt.Sprintf("%s: $%.2f %s", f.Localizer.Plural(msgCost, f.Years, nil), cost, pricing.Currency))
+	}
+	return lines
+}
+
+// alternativeList lists alternative domains with their registration price,
+// e.g. "example.io ($39.00 USD), example.dev ($12.00 USD)"
+func alternativeList(alternatives []*domain.AvailabilityResult) string {
+	names := make([]string, len(alternatives))
+	for i, alternative := range alternatives {
+		names[i] = displayName(alternative)
+		if alternative.Pricing != nil && alternative.Pricing.RegistrationPrice != nil {
+			names[i] += fmt.Sprintf(" ($%.2f %s)", *alternative.Pricing.RegistrationPrice, alternative.Pricing.Currency)
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -1,12 +1,19 @@
 package output
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/i18n"
+
+	"github.com/aws/smithy-go"
 )
 
 func TestNewConsoleFormatter(t *testing.T) {
@@ -279,6 +286,171 @@ func TestConsoleFormatter_FormatError(t *testing.T) {
 	}
 }
 
+func TestConsoleFormatter_FormatError_Typed(t *testing.T) {
+	formatter := NewConsoleFormatter()
+
+	tests := []struct {
+		name     string
+		err      error
+		contains string
+	}{
+		{
+			name:     "wrapped authentication error",
+			err:      fmt.Errorf("check failed: %w", customErrors.NewAuthenticationError("aws-sdk", "AWS credentials have expired", nil)),
+			contains: "Authentication Error",
+		},
+		{
+			name:     "authorization error without the AWS code in its message",
+			err:      customErrors.NewAuthorizationError("CheckDomainAvailability", "route53domains", "denied", nil),
+			contains: "Authorization Error",
+		},
+		{
+			name:     "throttled API error",
+			err:      customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "slow down", nil).WithStatusCode(429),
+			contains: "Rate Limit Error",
+		},
+		{
+			name:     "timeout behind an API error",
+			err:      customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "AWS API call failed", context.DeadlineExceeded),
+			contains: "Timeout Error",
+		},
+		{
+			name:     "timeout error",
+			err:      customErrors.NewTimeoutError("domain check", 10*time.Second, nil),
+			contains: "Timeout Error",
+		},
+		{
+			name:     "unwrapped AWS error",
+			err:      fmt.Errorf("operation error: %w", &smithy.GenericAPIError{Code: "AccessDeniedException"}),
+			contains: "Authorization Error",
+		},
+		{
+			name:     "domain validation error",
+			err:      customErrors.NewValidationError("bad-.com", "format", "label cannot end with hyphen", nil),
+			contains: "Domain Validation Error",
+		},
+		{
+			name:     "settings validation error mentioning a timeout",
+			err:      customErrors.NewValidationError("", "timeout", "timeout must be positive", nil),
+			contains: "Error: validation error: timeout must be positive",
+		},
+		{
+			name:     "network error",
+			err:      &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("refused")},
+			contains: "Network Error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := formatter.FormatError(tt.err); !strings.Contains(result, tt.contains) {
+				t.Errorf("FormatError() result should contain %q, got: %s", tt.contains, result)
+			}
+		})
+	}
+}
+
+func TestConsoleFormatter_FormatBulkResults_ErrorGroups(t *testing.T) {
+	throttled := customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "rate limited", nil).WithStatusCode(429)
+	results := []*domain.AvailabilityResult{
+		{Domain: "ok.com", Status: domain.StatusAvailable, Available: true},
+		{Domain: "a.com", Status: domain.StatusUnknown, Error: throttled, Retried: true, Retries: 1},
+		{Domain: "b.com", Status: domain.StatusUnknown, Error: throttled},
+	}
+
+	output := NewConsoleFormatter().FormatBulkResults(results)
+	for _, expected := range []string{
+		"✗ a.com: ERROR (throttled, after retry)\n",
+		"✗ b.com: ERROR (throttled)\n",
+		"Errors:\n  ✗ 2 domains throttled by AWS: a.com, b.com\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Count(output, "rate limited") != 1 {
+		t.Errorf("Expected the error message once, in the error report, got:\n%s", output)
+	}
+
+	// Verbose output keeps each message next to its domain
+	verbose := NewVerboseConsoleFormatter().FormatBulkResults(results)
+	if !strings.Contains(verbose, "✗ b.com: ERROR (throttled) - ") {
+		t.Errorf("Expected the message next to the domain in verbose output, got:\n%s", verbose)
+	}
+}
+
+func TestConsoleFormatter_FormatBulkResults_Incomplete(t *testing.T) {
+	skipped := customErrors.NewSystemError("bulk-check", "check skipped after the run was cancelled", nil)
+	results := []*domain.AvailabilityResult{
+		{Domain: "ok.com", Status: domain.StatusAvailable, Available: true},
+		{Domain: "later.com", Status: domain.StatusUnknown, Error: skipped, Skipped: true},
+	}
+
+	output := NewConsoleFormatter().FormatBulkResults(results)
+	for _, expected := range []string{
+		"⚠ INCOMPLETE: the run stopped before every domain was checked (1 skipped)\n",
+		"- later.com: SKIPPED (run aborted before it was checked)\n",
+		"  - Skipped: 1\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	if output := NewConsoleFormatter().FormatBulkResults(results[:1]); strings.Contains(output, "INCOMPLETE") {
+		t.Errorf("Expected a complete run not to be marked incomplete, got:\n%s", output)
+	}
+}
+
+func TestConsoleFormatter_Localized(t *testing.T) {
+	throttled := customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "rate limited", nil).WithStatusCode(429)
+	results := []*domain.AvailabilityResult{
+		{Domain: "ok.com", Status: domain.StatusAvailable, Available: true},
+		{Domain: "taken.com", Status: domain.StatusUnavailable},
+		{Domain: "busy.com", Status: domain.StatusUnknown, Error: throttled},
+	}
+
+	tests := []struct {
+		lang     string
+		result   []string
+		bulk     []string
+		guidance string
+	}{
+		{"en", []string{"✓ ok.com is AVAILABLE for registration"}, []string{"Bulk Domain Check Results (3 domains)", "✓ ok.com: AVAILABLE", "  ✗ Unavailable: 1", "  ✗ 1 domain throttled by AWS: busy.com"}, "✗ Authentication Error"},
+		{"es", []string{"✓ ok.com está DISPONIBLE para registrar"}, []string{"(3 dominios)", "✗ taken.com: NO DISPONIBLE (ya registrado)", "Resumen:", "  ✗ 1 dominio limitado por AWS: busy.com"}, "✗ Error de autenticación"},
+		{"fr", []string{"✓ ok.com est DISPONIBLE à l'enregistrement"}, []string{"(3 domaines)", "✓ ok.com : DISPONIBLE", "  ⚠ Erreurs : 1", "  ✗ 1 domaine limité par AWS : busy.com"}, "✗ Erreur d'authentification"},
+		{"ja", []string{"✓ ok.com は登録可能です"}, []string{"(3 件)", "✗ busy.com: エラー (throttled)", "概要:", "  ✗ AWS にスロットリングされたドメイン 1 件: busy.com"}, "✗ 認証エラー"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			localizer, err := i18n.New(tt.lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			formatter := NewConsoleFormatter()
+			formatter.SetLocalizer(localizer)
+
+			result := formatter.FormatResult(results[0])
+			for _, expected := range tt.result {
+				if !strings.Contains(result, expected) {
+					t.Errorf("Expected result to contain %q, got: %s", expected, result)
+				}
+			}
+			bulk := formatter.FormatBulkResults(results)
+			for _, expected := range tt.bulk {
+				if !strings.Contains(bulk, expected) {
+					t.Errorf("Expected bulk results to contain %q, got:\n%s", expected, bulk)
+				}
+			}
+			guidance := formatter.FormatError(customErrors.NewAuthenticationError("aws", "no credentials", nil))
+			if !strings.HasPrefix(guidance, tt.guidance) {
+				t.Errorf("Expected guidance to start with %q, got: %s", tt.guidance, guidance)
+			}
+		})
+	}
+}
+
 func TestConsoleFormatter_SettersAndGetters(t *testing.T) {
 	formatter := NewConsoleFormatter()
 
@@ -419,3 +591,205 @@ func BenchmarkConsoleFormatter_FormatError(b *testing.B) {
 		formatter.FormatError(err)
 	}
 }
+
+func TestConsoleFormatter_InternationalizedDomain(t *testing.T) {
+	formatter := NewConsoleFormatter()
+	result := &domain.AvailabilityResult{
+		Domain:        "xn--bcher-kva.de",
+		UnicodeDomain: "bücher.de",
+		Available:     true,
+		Status:        domain.StatusAvailable,
+	}
+
+	expected := "✓ bücher.de (xn--bcher-kva.de) is AVAILABLE for registration"
+	if got := formatter.FormatResult(result); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	bulk := formatter.FormatBulkResults([]*domain.AvailabilityResult{result})
+	if !strings.Contains(bulk, "✓ bücher.de (xn--bcher-kva.de): AVAILABLE") {
+		t.Errorf("expected both forms in bulk output, got %q", bulk)
+	}
+}
+
+func TestConsoleFormatter_FormatError_MultipleValidationErrors(t *testing.T) {
+	formatter := NewConsoleFormatter()
+	err := customErrors.NewValidationErrors("-bad--name.invalidtld", []*customErrors.ValidationError{
+		customErrors.NewValidationError("-bad--name.invalidtld", "format", "label cannot start or end with hyphen: -bad--name", nil),
+		customErrors.NewValidationError("-bad--name.invalidtld", "format", "consecutive hyphens not allowed in domain", nil),
+		customErrors.NewValidationError("-bad--name.invalidtld", "tld", "unsupported TLD: .invalidtld", nil),
+	})
+
+	output := formatter.FormatError(err)
+
+	expected := []string{
+		"3 problems with '-bad--name.invalidtld'",
+		"  • label cannot start or end with hyphen: -bad--name\n",
+		"  • consecutive hyphens not allowed in domain\n",
+		"  • unsupported TLD: .invalidtld\n",
+		"Domain format requirements:",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestConsoleFormatter_ConfusableWarnings(t *testing.T) {
+	formatter := NewConsoleFormatter()
+	result := &domain.AvailabilityResult{
+		Domain:    "paypa1.com",
+		Available: true,
+		Status:    domain.StatusAvailable,
+		Warnings:  []string{"'paypa1' looks like 'paypal'"},
+	}
+
+	expected := "✓ paypa1.com is AVAILABLE for registration\n⚠ Warning: 'paypa1' looks like 'paypal'"
+	if got := formatter.FormatResult(result); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	bulk := formatter.FormatBulkResults([]*domain.AvailabilityResult{result})
+	if !strings.Contains(bulk, "✓ paypa1.com: AVAILABLE\n  ⚠ Warning: 'paypa1' looks like 'paypal'\n") {
+		t.Errorf("expected warning under the bulk result, got %q", bulk)
+	}
+}
+
+func TestConsoleFormatter_Unverified(t *testing.T) {
+	formatter := NewConsoleFormatter()
+	result := &domain.AvailabilityResult{
+		Domain:     "example.ly",
+		Available:  true,
+		Status:     domain.StatusAvailable,
+		Source:     "whois",
+		Unverified: true,
+	}
+
+	expected := "✓ example.ly is AVAILABLE for registration\n⚠ Unverified: answered by whois, not Route 53"
+	if got := formatter.FormatResult(result); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	bulk := formatter.FormatBulkResults([]*domain.AvailabilityResult{result})
+	if !strings.Contains(bulk, "✓ example.ly: AVAILABLE\n  ⚠ Unverified: answered by whois, not Route 53\n") {
+		t.Errorf("expected the result to be marked unverified, got %q", bulk)
+	}
+}
+
+func TestConsoleFormatter_FromHistory(t *testing.T) {
+	formatter := NewConsoleFormatter()
+	results := []*domain.AvailabilityResult{
+		{Domain: "taken.com", Status: domain.StatusUnavailable, CheckedAt: time.Now().Add(-3*time.Hour - time.Minute), FromHistory: true},
+		{Domain: "free.com", Available: true, Status: domain.StatusAvailable, CheckedAt: time.Now()},
+	}
+
+	bulk := formatter.FormatBulkResults(results)
+	if !strings.Contains(bulk, "✗ taken.com: UNAVAILABLE (already registered)\n  ↺ From history: checked 3h ago\n") {
+		t.Errorf("expected the reused result to be marked, got %q", bulk)
+	}
+	if !strings.Contains(bulk, "↺ From history: 1\n") || !strings.Contains(bulk, "✗ Unavailable: 1\n") {
+		t.Errorf("expected reused results to be counted, got %q", bulk)
+	}
+}
+
+func TestConsoleFormatter_Prescreened(t *testing.T) {
+	formatter := NewConsoleFormatter()
+	results := []*domain.AvailabilityResult{
+		{Domain: "taken.com", Status: domain.StatusUnavailable, CheckedAt: time.Now(), Prescreened: true},
+		{Domain: "free.com", Available: true, Status: domain.StatusAvailable, CheckedAt: time.Now()},
+	}
+
+	bulk := formatter.FormatBulkResults(results)
+	if !strings.Contains(bulk, "✗ taken.com: UNAVAILABLE (already registered)\n  ≈ Prescreened: registered according to DNS, Route 53 was not asked\n") {
+		t.Errorf("expected the prescreened result to be marked, got %q", bulk)
+	}
+	if !strings.Contains(bulk, "≈ Prescreened: 1\n") || !strings.Contains(bulk, "✗ Unavailable: 1\n") {
+		t.Errorf("expected prescreened results to be counted, got %q", bulk)
+	}
+}
+
+func TestConsoleFormatter_Notes(t *testing.T) {
+	formatter := NewConsoleFormatter()
+	results := []*domain.AvailabilityResult{
+		{Domain: "free.com", Available: true, Status: domain.StatusAvailable, CheckedAt: time.Now(), Note: "client preference #1"},
+		{Domain: "spare.com", Available: true, Status: domain.StatusAvailable, CheckedAt: time.Now()},
+	}
+
+	bulk := formatter.FormatBulkResults(results)
+	if !strings.Contains(bulk, "✓ free.com: AVAILABLE\n  ✎ client preference #1\n") {
+		t.Errorf("expected the note under its domain, got %q", bulk)
+	}
+	if strings.Count(bulk, "✎") != 1 {
+		t.Errorf("expected only results with a note to show one, got %q", bulk)
+	}
+}
+
+func TestConsoleFormatter_SummaryOnly(t *testing.T) {
+	results := []*domain.AvailabilityResult{
+		{Domain: "free.com", Available: true, Status: domain.StatusAvailable, CheckedAt: time.Now()},
+		{Domain: "taken.com", Status: domain.StatusUnavailable, CheckedAt: time.Now()},
+	}
+
+	formatter := NewConsoleFormatter()
+	formatter.SetSummaryOnly(true)
+	bulk := formatter.FormatBulkResults(results)
+	if strings.Contains(bulk, "free.com") || strings.Contains(bulk, "taken.com") {
+		t.Errorf("expected no per-domain lines, got %q", bulk)
+	}
+	if !strings.Contains(bulk, "✓ Available: 1\n") || !strings.Contains(bulk, "✗ Unavailable: 1\n") {
+		t.Errorf("expected the summary counts, got %q", bulk)
+	}
+
+	formatter.SetListAvailable(true)
+	bulk = formatter.FormatBulkResults(results)
+	if !strings.HasSuffix(bulk, "Available domains:\n  ✓ free.com\n") || strings.Contains(bulk, "taken.com") {
+		t.Errorf("expected only the available domain listed, got %q", bulk)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age      time.Duration
+		expected string
+	}{
+		{30 * time.Second, "less than a minute"},
+		{45 * time.Minute, "45m"},
+		{26 * time.Hour, "26h"},
+		{72 * time.Hour, "3d"},
+	}
+
+	for _, tt := range tests {
+		if got := formatAge(tt.age); got != tt.expected {
+			t.Errorf("formatAge(%v) = %q, expected %q", tt.age, got, tt.expected)
+		}
+	}
+}
+
+func TestConsoleFormatter_Years(t *testing.T) {
+	formatter := NewConsoleFormatter()
+	formatter.SetYears(3)
+	results := []*domain.AvailabilityResult{
+		{Domain: "free.com", Available: true, Status: domain.StatusAvailable, CheckedAt: time.Now(), Pricing: priced(12, 15, "USD")},
+		{Domain: "free.io", Available: true, Status: domain.StatusAvailable, CheckedAt: time.Now(), Pricing: priced(40, 50, "USD")},
+		{Domain: "free.org", Available: true, Status: domain.StatusAvailable, CheckedAt: time.Now()},
+	}
+
+	single := formatter.FormatResult(results[0])
+	if !strings.Contains(single, "Renewal: $15.00 USD\n  Cost over 3 years: $42.00 USD") {
+		t.Errorf("expected the cost after the prices, got %q", single)
+	}
+
+	bulk := formatter.FormatBulkResults(results)
+	if !strings.Contains(bulk, "  Cost over 3 years: $140.00 USD\n") {
+		t.Errorf("expected the cost of each domain, got %q", bulk)
+	}
+	if !strings.Contains(bulk, "$ Cost over 3 years: $182.00 USD for 2 available\n  $ Available without a known cost: 1\n") {
+		t.Errorf("expected the total cost in the summary, got %q", bulk)
+	}
+
+	formatter.SetYears(0)
+	if output := formatter.FormatBulkResults(results); strings.Contains(output, "Cost over") {
+		t.Errorf("expected no costs without years, got %q", output)
+	}
+}
@@ -0,0 +1,327 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	"github.com/abakermi/r53check/internal/errors"
+)
+
+// OutputFormat selects how results are rendered
+type OutputFormat string
+
+const (
+	FormatText OutputFormat = "text" // Human-readable console output
+	FormatJSON OutputFormat = "json" // One JSON document per command
+)
+
+// ParseOutputFormat converts a flag or config value into an OutputFormat
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	switch format := OutputFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case FormatText, FormatJSON:
+		return format, nil
+	default:
+		return "", errors.NewValidationError("", "output",
+			fmt.Sprintf("invalid output format %q: must be one of text, json%s", value,
+				errors.DidYouMean(value, []string{string(FormatText), string(FormatJSON)})), nil)
+	}
+}
+
+// JSONFormatter implements machine-readable JSON output
+type JSONFormatter struct {
+	// SummaryOnly leaves the per-domain results out of bulk output
+	SummaryOnly bool
+	// ListAvailable adds the available domains to a bulk summary
+	ListAvailable bool
+	// Years adds the cost of holding a domain for this many years to its
+	// pricing, and the total for the available domains to a bulk summary
+	Years int
+}
+
+// NewJSONFormatter creates a new JSON formatter
+func NewJSONFormatter() *JSONFormatter {
+	return &JSONFormatter{}
+}
+
+// JSONPricing is the JSON representation of domain.PricingInfo
+type JSONPricing struct {
+	Registration *float64 `json:"registration,omitempty"`
+	Renewal      *float64 `json:"renewal,omitempty"`
+	Transfer     *float64 `json:"transfer,omitempty"`
+	Currency     string   `json:"currency"`
+
+	// Years and Cost are the cost of holding the domain for a number of
+	// years, when asked for and known
+	Years int      `json:"years,omitempty"`
+	Cost  *float64 `json:"cost,omitempty"`
+}
+
+// JSONResult is the JSON representation of domain.AvailabilityResult
+type JSONResult struct {
+	Domain        string       `json:"domain"`
+	UnicodeDomain string       `json:"unicode_domain,omitempty"`
+	Available     bool         `json:"available"`
+	Status        string       `json:"status"`
+	Message       string       `json:"message,omitempty"`
+	CheckedAt     time.Time    `json:"checked_at"`
+	Error         string       `json:"error,omitempty"`
+	Pricing       *JSONPricing `json:"pricing,omitempty"`
+	Warnings      []string     `json:"warnings,omitempty"`
+	Retried       bool         `json:"retried,omitempty"`
+	Retries       int          `json:"retries,omitempty"`
+	Skipped       bool         `json:"skipped,omitempty"`
+	FromHistory   bool         `json:"from_history,omitempty"`
+	Note          string       `json:"note,omitempty"`
+	Source        string       `json:"source,omitempty"`
+	Unverified    bool         `json:"unverified,omitempty"`
+	Prescreened   bool         `json:"prescreened,omitempty"`
+	Alternatives  []JSONResult `json:"alternatives,omitempty"`
+}
+
+// JSONSummary counts bulk results by outcome
+type JSONSummary struct {
+	Total       int `json:"total"`
+	Available   int `json:"available"`
+	Unavailable int `json:"unavailable"`
+	Errors      int `json:"errors"`
+	Skipped     int `json:"skipped"`
+	FromHistory int `json:"from_history,omitempty"`
+	Prescreened int `json:"prescreened,omitempty"`
+
+	// Years, Costs and Unpriced total the cost of holding the available
+	// domains for a number of years, when asked for: Costs per currency,
+	// and Unpriced counts the domains whose cost is unknown
+	Years    int         `json:"years,omitempty"`
+	Costs    []CostTotal `json:"costs,omitempty"`
+	Unpriced int         `json:"unpriced,omitempty"`
+
+	// Incomplete is set when the run stopped, by cancellation, a timeout
+	// or --fail-fast, before every domain was checked
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+// FormatResult formats a domain availability result as a JSON object
+func (f *JSONFormatter) FormatResult(result *domain.AvailabilityResult) string {
+	if result == nil {
+		return f.FormatError(fmt.Errorf("no result to format"))
+	}
+	out := NewJSONResult(result)
+	out.Pricing.project(f.Years)
+	return marshal(out)
+}
+
+// FormatBulkResults formats multiple results and their summary as a JSON object
+func (f *JSONFormatter) FormatBulkResults(results []*domain.AvailabilityResult) string {
+	if f.SummaryOnly {
+		out := NewJSONBulkSummary(results, f.ListAvailable)
+		out.Summary.project(results, f.Years)
+		return marshal(out)
+	}
+	out := NewJSONBulkResults(results)
+	for i := range out.Results {
+		out.Results[i].Pricing.project(f.Years)
+	}
+	out.Summary.project(results, f.Years)
+	return marshal(out)
+}
+
+// SetYears sets the number of years costs are projected over; 0 leaves
+// them out
+func (f *JSONFormatter) SetYears(years int) {
+	f.Years = years
+}
+
+// SetSummaryOnly enables or disables leaving per-domain results out of bulk
+// output
+func (f *JSONFormatter) SetSummaryOnly(summaryOnly bool) {
+	f.SummaryOnly = summaryOnly
+}
+
+// SetListAvailable enables or disables listing the available domains in a
+// bulk summary
+func (f *JSONFormatter) SetListAvailable(list bool) {
+	f.ListAvailable = list
+}
+
+// JSONError is the JSON representation of a failed command
+type JSONError struct {
+	Category  string `json:"category"`
+	Code      string `json:"code,omitempty"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+	ExitCode  int    `json:"exit_code"`
+}
+
+// NewJSONError converts an error to its JSON representation
+func NewJSONError(err error) JSONError {
+	return JSONError{
+		Category:  string(errors.GetCategory(err)),
+		Code:      errors.GetErrorCode(err),
+		Message:   err.Error(),
+		RequestID: errors.GetRequestID(err),
+		ExitCode:  int(errors.GetExitCode(err)),
+	}
+}
+
+// FormatError formats an error as a JSON object under an "error" key
+func (f *JSONFormatter) FormatError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return marshal(map[string]JSONError{"error": NewJSONError(err)})
+}
+
+// JSONBulkResults is the JSON representation of a bulk check
+type JSONBulkResults struct {
+	Results []JSONResult `json:"results"`
+	Summary JSONSummary  `json:"summary"`
+
+	// ErrorGroups groups the failed domains by kind of failure
+	ErrorGroups []ErrorGroup `json:"error_groups,omitempty"`
+}
+
+// NewJSONBulkResults converts bulk results to their JSON representation,
+// counting each outcome in the summary
+func NewJSONBulkResults(results []*domain.AvailabilityResult) JSONBulkResults {
+	out := JSONBulkResults{
+		Results: make([]JSONResult, 0, len(results)),
+	}
+
+	for _, result := range results {
+		out.Summary.Total++
+		if result == nil {
+			out.Summary.Errors++
+			continue
+		}
+		if result.FromHistory {
+			out.Summary.FromHistory++
+		}
+		if result.Prescreened {
+			out.Summary.Prescreened++
+		}
+		switch {
+		case result.Skipped:
+			out.Summary.Skipped++
+		case result.Error != nil:
+			out.Summary.Errors++
+		case result.Available:
+			out.Summary.Available++
+		default:
+			out.Summary.Unavailable++
+		}
+		out.Results = append(out.Results, NewJSONResult(result))
+	}
+	out.Summary.Incomplete = out.Summary.Skipped > 0
+	out.ErrorGroups = GroupErrors(results)
+
+	return out
+}
+
+// JSONBulkSummary is the JSON representation of a bulk check without its
+// per-domain results
+type JSONBulkSummary struct {
+	Summary JSONSummary `json:"summary"`
+
+	// Available lists the available domains when asked for
+	Available []string `json:"available,omitempty"`
+
+	// ErrorGroups groups the failed domains by kind of failure
+	ErrorGroups []ErrorGroup `json:"error_groups,omitempty"`
+}
+
+// NewJSONBulkSummary summarizes bulk results, listing the available domains
+// when listAvailable is set
+func NewJSONBulkSummary(results []*domain.AvailabilityResult, listAvailable bool) JSONBulkSummary {
+	full := NewJSONBulkResults(results)
+	out := JSONBulkSummary{Summary: full.Summary, ErrorGroups: full.ErrorGroups}
+	if listAvailable {
+		out.Available = AvailableDomains(results)
+	}
+	return out
+}
+
+// AvailableDomains returns the domains of results that are available, in
+// order
+func AvailableDomains(results []*domain.AvailabilityResult) []string {
+	var available []string
+	for _, result := range results {
+		if result != nil && !result.Skipped && result.Error == nil && result.Available {
+			available = append(available, result.Domain)
+		}
+	}
+	return available
+}
+
+// NewJSONResult converts a result to its JSON representation
+func NewJSONResult(result *domain.AvailabilityResult) JSONResult {
+	out := JSONResult{
+		Domain:        result.Domain,
+		UnicodeDomain: result.UnicodeDomain,
+		Available:     result.Available,
+		Status:        string(result.Status),
+		Message:       result.Message,
+		CheckedAt:     result.CheckedAt,
+		Warnings:      result.Warnings,
+		Retried:       result.Retried,
+		Retries:       result.Retries,
+		Skipped:       result.Skipped,
+		FromHistory:   result.FromHistory,
+		Note:          result.Note,
+		Source:        result.Source,
+		Unverified:    result.Unverified,
+		Prescreened:   result.Prescreened,
+	}
+	if result.Error != nil {
+		out.Error = result.Error.Error()
+	}
+	for _, alternative := range result.Alternatives {
+		out.Alternatives = append(out.Alternatives, NewJSONResult(alternative))
+	}
+	if result.Pricing != nil {
+		out.Pricing = NewJSONPricing(result.Pricing)
+	}
+	return out
+}
+
+// NewJSONPricing converts pricing to its JSON representation
+func NewJSONPricing(pricing *domain.PricingInfo) *JSONPricing {
+	return &JSONPricing{
+		Registration: pricing.RegistrationPrice,
+		Renewal:      pricing.RenewalPrice,
+		Transfer:     pricing.TransferPrice,
+		Currency:     pricing.Currency,
+	}
+}
+
+// project adds the cost over years to pricing when it is known
+func (p *JSONPricing) project(years int) {
+	if p == nil || years < 1 {
+		return
+	}
+	pricing := domain.PricingInfo{RegistrationPrice: p.Registration, RenewalPrice: p.Renewal}
+	if cost, ok := pricing.Cost(years); ok {
+		p.Years = years
+		p.Cost = &cost
+	}
+}
+
+// project adds the cost over years of the available domains of results
+func (s *JSONSummary) project(results []*domain.AvailabilityResult, years int) {
+	if years < 1 {
+		return
+	}
+	s.Years = years
+	s.Costs, s.Unpriced = CostTotals(results, years)
+}
+
+// marshal encodes v as indented JSON
+func marshal(v interface{}) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprintf(`{"error":{"message":%q}}`, err.Error())
+	}
+	return string(data)
+}
@@ -0,0 +1,221 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected OutputFormat
+		wantErr  bool
+	}{
+		{"text", FormatText, false},
+		{"JSON", FormatJSON, false},
+		{" json ", FormatJSON, false},
+		{"yaml", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			format, err := ParseOutputFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOutputFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if format != tt.expected {
+				t.Errorf("ParseOutputFormat(%q) = %q, want %q", tt.input, format, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJSONFormatter_FormatResult(t *testing.T) {
+	price := 12.0
+	result := &domain.AvailabilityResult{
+		Domain:    "example.com",
+		Available: true,
+		Status:    domain.StatusAvailable,
+		Message:   "Domain is available for registration",
+		CheckedAt: time.Date(2023, 12, 25, 10, 30, 0, 0, time.UTC),
+		Pricing:   &domain.PricingInfo{RegistrationPrice: &price, Currency: "USD"},
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(NewJSONFormatter().FormatResult(result)), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	if decoded["domain"] != "example.com" || decoded["status"] != "AVAILABLE" || decoded["available"] != true {
+		t.Errorf("unexpected result fields: %v", decoded)
+	}
+	if decoded["checked_at"] != "2023-12-25T10:30:00Z" {
+		t.Errorf("expected RFC 3339 timestamp, got %v", decoded["checked_at"])
+	}
+	pricing, ok := decoded["pricing"].(map[string]interface{})
+	if !ok || pricing["registration"] != 12.0 || pricing["currency"] != "USD" {
+		t.Errorf("unexpected pricing: %v", decoded["pricing"])
+	}
+	if _, ok := decoded["error"]; ok {
+		t.Error("expected no error field for a successful result")
+	}
+	if _, ok := decoded["unverified"]; ok {
+		t.Error("expected no unverified field for a Route 53 result")
+	}
+
+	result.Source, result.Unverified, result.Pricing = "whois", true, nil
+	decoded = nil
+	if err := json.Unmarshal([]byte(NewJSONFormatter().FormatResult(result)), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if decoded["unverified"] != true || decoded["source"] != "whois" {
+		t.Errorf("expected the fallback result to be marked unverified, got %v", decoded)
+	}
+}
+
+func TestJSONFormatter_FormatBulkResults(t *testing.T) {
+	results := []*domain.AvailabilityResult{
+		{Domain: "free.com", Available: true, Status: domain.StatusAvailable},
+		{Domain: "taken.com", Status: domain.StatusUnavailable},
+		{Domain: "broken.com", Status: domain.StatusUnknown, Error: errors.New("throttled")},
+		{Domain: "later.com", Skipped: true, Error: errors.New("skipped")},
+	}
+
+	var decoded struct {
+		Results []struct {
+			Domain string `json:"domain"`
+			Error  string `json:"error"`
+		} `json:"results"`
+		Summary     JSONSummary  `json:"summary"`
+		ErrorGroups []ErrorGroup `json:"error_groups"`
+	}
+	if err := json.Unmarshal([]byte(NewJSONFormatter().FormatBulkResults(results)), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	expected := JSONSummary{Total: 4, Available: 1, Unavailable: 1, Errors: 1, Skipped: 1, Incomplete: true}
+	if !reflect.DeepEqual(decoded.Summary, expected) {
+		t.Errorf("expected summary %+v, got %+v", expected, decoded.Summary)
+	}
+	if len(decoded.Results) != 4 || decoded.Results[2].Error != "throttled" {
+		t.Errorf("unexpected results: %+v", decoded.Results)
+	}
+	if len(decoded.ErrorGroups) != 1 || decoded.ErrorGroups[0].Kind != ErrorKindSystem || decoded.ErrorGroups[0].Domains[0] != "broken.com" {
+		t.Errorf("expected the failed domain grouped without the skipped one, got %+v", decoded.ErrorGroups)
+	}
+}
+
+func TestJSONFormatter_FormatBulkResults_SummaryOnly(t *testing.T) {
+	results := []*domain.AvailabilityResult{
+		{Domain: "free.com", Available: true, Status: domain.StatusAvailable},
+		{Domain: "taken.com", Status: domain.StatusUnavailable},
+		{Domain: "spare.io", Available: true, Status: domain.StatusAvailable},
+	}
+
+	formatter := NewJSONFormatter()
+	formatter.SetSummaryOnly(true)
+	formatter.SetListAvailable(true)
+
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(formatter.FormatBulkResults(results)), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if _, ok := decoded["results"]; ok {
+		t.Error("expected the per-domain results to be left out")
+	}
+	var available []string
+	if err := json.Unmarshal(decoded["available"], &available); err != nil || len(available) != 2 || available[0] != "free.com" || available[1] != "spare.io" {
+		t.Errorf("expected the available domains in order, got %s", decoded["available"])
+	}
+	var summary JSONSummary
+	if err := json.Unmarshal(decoded["summary"], &summary); err != nil || summary.Total != 3 || summary.Available != 2 {
+		t.Errorf("unexpected summary %s", decoded["summary"])
+	}
+}
+
+func TestJSONFormatter_FormatError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected JSONError
+	}{
+		{
+			name:     "plain error",
+			err:      errors.New("boom"),
+			expected: JSONError{Category: "SYSTEM", Message: "boom", ExitCode: 5},
+		},
+		{
+			name: "authentication error",
+			err: customErrors.NewAuthenticationError("aws-sdk", "unable to retrieve AWS credentials",
+				&smithy.GenericAPIError{Code: "NoCredentialsErr", Message: "no credentials"}),
+			expected: JSONError{
+				Category: "AUTHENTICATION",
+				Code:     "NoCredentialsErr",
+				Message:  "authentication failed with provider 'aws-sdk': unable to retrieve AWS credentials",
+				ExitCode: 2,
+			},
+		},
+		{
+			name:     "API error",
+			err:      customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "failed", nil).WithRequestID("req-123"),
+			expected: JSONError{Category: "API", Message: "AWS route53domains API error in CheckDomainAvailability (RequestID: req-123): failed", RequestID: "req-123", ExitCode: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded map[string]JSONError
+			if err := json.Unmarshal([]byte(NewJSONFormatter().FormatError(tt.err)), &decoded); err != nil {
+				t.Fatalf("output is not valid JSON: %v", err)
+			}
+			if decoded["error"] != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, decoded["error"])
+			}
+		})
+	}
+	if NewJSONFormatter().FormatError(nil) != "" {
+		t.Error("expected empty output for nil error")
+	}
+}
+
+func TestJSONFormatter_Years(t *testing.T) {
+	results := []*domain.AvailabilityResult{
+		{Domain: "free.com", Available: true, Status: domain.StatusAvailable, Pricing: priced(12, 15, "USD")},
+		{Domain: "free.org", Available: true, Status: domain.StatusAvailable},
+	}
+
+	formatter := NewJSONFormatter()
+	formatter.SetYears(2)
+
+	var bulk JSONBulkResults
+	if err := json.Unmarshal([]byte(formatter.FormatBulkResults(results)), &bulk); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	pricing := bulk.Results[0].Pricing
+	if pricing == nil || pricing.Years != 2 || pricing.Cost == nil || *pricing.Cost != 27 {
+		t.Errorf("expected a cost of 27 over 2 years, got %+v", pricing)
+	}
+	if bulk.Summary.Years != 2 || len(bulk.Summary.Costs) != 1 || bulk.Summary.Costs[0] != (CostTotal{"USD", 27, 1}) || bulk.Summary.Unpriced != 1 {
+		t.Errorf("unexpected cost summary %+v", bulk.Summary)
+	}
+
+	formatter.SetSummaryOnly(true)
+	var summary JSONBulkSummary
+	if err := json.Unmarshal([]byte(formatter.FormatBulkResults(results)), &summary); err != nil || len(summary.Summary.Costs) != 1 {
+		t.Errorf("expected the summary only output to total costs, got %+v", summary.Summary)
+	}
+
+	var single JSONResult
+	if err := json.Unmarshal([]byte(NewJSONFormatter().FormatResult(results[0])), &single); err != nil || single.Pricing.Cost != nil {
+		t.Errorf("expected no cost without years, got %+v", single.Pricing)
+	}
+}
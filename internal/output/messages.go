@@ -0,0 +1,128 @@
+package output
+
+import "github.com/abakermi/r53check/internal/i18n"
+
+// Messages of the console formatter, in English. Translations are in the
+// message catalogs of the i18n package, keyed by ID. Symbols such as ✓ and
+// indentation are added by the formatter, so they are not translated.
+
+// Results of a single check
+var (
+	msgNoResult = &i18n.Message{ID: "NoResult", Other: "Error: No result to format"}
+
+	msgResultAvailable     = &i18n.Message{ID: "ResultAvailable", Other: "{{.Domain}} is AVAILABLE for registration"}
+	msgResultUnavailable   = &i18n.Message{ID: "ResultUnavailable", Other: "{{.Domain}} is UNAVAILABLE (already registered)"}
+	msgResultReserved      = &i18n.Message{ID: "ResultReserved", Other: "{{.Domain}} is RESERVED and cannot be registered"}
+	msgResultUnknown       = &i18n.Message{ID: "ResultUnknown", Other: "{{.Domain}} availability is UNKNOWN"}
+	msgResultUnknownStatus = &i18n.Message{ID: "ResultUnknownStatus", Other: "{{.Domain}} has unknown status: {{.Status}}"}
+
+	msgWarning      = &i18n.Message{ID: "Warning", Other: "Warning: {{.Warning}}"}
+	msgUnverified   = &i18n.Message{ID: "Unverified", Other: "Unverified: answered by {{.Source}}, not Route 53"}
+	msgAlternatives = &i18n.Message{ID: "Alternatives", Other: "Available instead: {{.Domains}}"}
+	msgPricing      = &i18n.Message{ID: "Pricing", Other: "Pricing:"}
+	msgRegistration = &i18n.Message{ID: "PriceRegistration", Other: "Registration"}
+	msgRenewal      = &i18n.Message{ID: "PriceRenewal", Other: "Renewal"}
+	msgTransfer     = &i18n.Message{ID: "PriceTransfer", Other: "Transfer"}
+	msgCost         = &i18n.Message{ID: "PriceCost", One: "Cost over {{.Count}} year", Other: "Cost over {{.Count}} years"}
+	msgStatus       = &i18n.Message{ID: "LabelStatus", Other: "Status"}
+	msgMessage      = &i18n.Message{ID: "LabelMessage", Other: "Message"}
+	msgCheckedAt    = &i18n.Message{ID: "LabelCheckedAt", Other: "Checked at"}
+	msgChecked      = &i18n.Message{ID: "LabelChecked", Other: "Checked"}
+)
+
+// Results of a bulk check
+var (
+	msgNoDomains     = &i18n.Message{ID: "NoDomains", Other: "No domains to check"}
+	msgInvalidResult = &i18n.Message{ID: "InvalidResult", Other: "UNKNOWN: Invalid result"}
+	msgBulkTitle     = &i18n.Message{ID: "BulkTitle", One: "Bulk Domain Check Results ({{.Count}} domain)", Other: "Bulk Domain Check Results ({{.Count}} domains)"}
+
+	msgBulkIncomplete    = &i18n.Message{ID: "BulkIncomplete", Other: "INCOMPLETE: the run stopped before every domain was checked ({{.Count}} skipped)"}
+	msgBulkSkipped       = &i18n.Message{ID: "BulkSkipped", Other: "{{.Domain}}: SKIPPED (run aborted before it was checked)"}
+	msgBulkError         = &i18n.Message{ID: "BulkError", Other: "{{.Domain}}: ERROR ({{.Detail}})"}
+	msgAfterRetry        = &i18n.Message{ID: "AfterRetry", Other: "after retry"}
+	msgAfterRetries      = &i18n.Message{ID: "AfterRetries", Other: "after {{.Count}} retries"}
+	msgBulkAvailable     = &i18n.Message{ID: "BulkAvailable", Other: "{{.Domain}}: AVAILABLE"}
+	msgBulkUnavailable   = &i18n.Message{ID: "BulkUnavailable", Other: "{{.Domain}}: UNAVAILABLE (already registered)"}
+	msgBulkReserved      = &i18n.Message{ID: "BulkReserved", Other: "{{.Domain}}: RESERVED (cannot be registered)"}
+	msgBulkUnknown       = &i18n.Message{ID: "BulkUnknown", Other: "{{.Domain}}: UNKNOWN (unable to determine)"}
+	msgBulkUnknownStatus = &i18n.Message{ID: "BulkUnknownStatus", Other: "{{.Domain}}: UNKNOWN STATUS"}
+	msgFromHistory       = &i18n.Message{ID: "FromHistory", Other: "From history: checked {{.Age}} ago"}
+	msgPrescreened       = &i18n.Message{ID: "Prescreened", Other: "Prescreened: registered according to DNS, Route 53 was not asked"}
+
+	msgSummary             = &i18n.Message{ID: "Summary", Other: "Summary:"}
+	msgSummaryAvailable    = &i18n.Message{ID: "SummaryAvailable", Other: "Available: {{.Count}}"}
+	msgSummaryUnavailable  = &i18n.Message{ID: "SummaryUnavailable", Other: "Unavailable: {{.Count}}"}
+	msgSummaryErrors       = &i18n.Message{ID: "SummaryErrors", Other: "Errors: {{.Count}}"}
+	msgSummarySkipped      = &i18n.Message{ID: "SummarySkipped", Other: "Skipped: {{.Count}}"}
+	msgSummaryFromHistory  = &i18n.Message{ID: "SummaryFromHistory", Other: "From history: {{.Count}}"}
+	msgSummaryPrescreened  = &i18n.Message{ID: "SummaryPrescreened", Other: "Prescreened: {{.Count}}"}
+	msgSummaryCost         = &i18n.Message{ID: "SummaryCost", One: "Cost over {{.Count}} year: {{.Total}} for {{.Domains}} available", Other: "Cost over {{.Count}} years: {{.Total}} for {{.Domains}} available"}
+	msgSummaryUnpriced     = &i18n.Message{ID: "SummaryUnpriced", Other: "Available without a known cost: {{.Count}}"}
+	msgSummaryRetryFailure = &i18n.Message{ID: "SummaryRetryFailures", Other: "Still failing after retry: {{.Domains}}"}
+	msgAvailableDomains    = &i18n.Message{ID: "AvailableDomains", Other: "Available domains:"}
+
+	msgErrors     = &i18n.Message{ID: "Errors", Other: "Errors:"}
+	msgErrorGroup = &i18n.Message{ID: "ErrorGroup", One: "{{.Count}} domain {{.Label}}: {{.Domains}}", Other: "{{.Count}} domains {{.Label}}: {{.Domains}}"}
+	msgMore       = &i18n.Message{ID: "ErrorGroupMore", Other: "and {{.Count}} more"}
+)
+
+// errorKindLabels describe each kind of error after a domain count
+var errorKindLabels = map[string]*i18n.Message{
+	ErrorKindThrottled:      {ID: "ErrorKindThrottled", Other: "throttled by AWS"},
+	ErrorKindTimeout:        {ID: "ErrorKindTimeout", Other: "timed out"},
+	ErrorKindAuthentication: {ID: "ErrorKindAuthentication", Other: "failed authentication"},
+	ErrorKindAuthorization:  {ID: "ErrorKindAuthorization", Other: "denied by AWS permissions"},
+	ErrorKindValidation:     {ID: "ErrorKindValidation", Other: "invalid"},
+	ErrorKindAPI:            {ID: "ErrorKindAPI", Other: "failed with an AWS API error"},
+	ErrorKindSystem:         {ID: "ErrorKindSystem", Other: "failed with an unexpected error"},
+}
+
+// Guidance for errors
+var (
+	msgError   = &i18n.Message{ID: "Error", Other: "Error: {{.Message}}"}
+	msgDetails = &i18n.Message{ID: "Details", Other: "Details: {{.Details}}"}
+
+	msgAuthenticationError = &i18n.Message{ID: "AuthenticationError", Other: "Authentication Error: AWS credentials not found"}
+	msgAuthenticationHelp  = &i18n.Message{ID: "AuthenticationHelp", Other: `To fix this issue, try one of the following:
+  1. Set environment variables:
+     export AWS_ACCESS_KEY_ID=your-access-key
+     export AWS_SECRET_ACCESS_KEY=your-secret-key
+  2. Configure AWS CLI: aws configure
+  3. Use IAM roles if running on EC2/ECS/Lambda
+  4. Set AWS_PROFILE environment variable to use a specific profile`}
+
+	msgAuthorizationError = &i18n.Message{ID: "AuthorizationError", Other: "Authorization Error: Insufficient permissions"}
+	msgAuthorizationHelp  = &i18n.Message{ID: "AuthorizationHelp", Other: `To fix this issue:
+  1. Ensure your AWS user/role has the 'route53domains:CheckDomainAvailability' permission
+  2. Check if your account has access to Route 53 Domains service
+  3. Verify you're using the correct AWS region (Route 53 Domains is global)`}
+
+	msgValidationError    = &i18n.Message{ID: "ValidationError", Other: "Domain Validation Error"}
+	msgValidationProblems = &i18n.Message{ID: "ValidationProblems", One: "Domain Validation Error: {{.Count}} problem with '{{.Domain}}'", Other: "Domain Validation Error: {{.Count}} problems with '{{.Domain}}'"}
+	msgDomainRequirements = &i18n.Message{ID: "DomainRequirements", Other: `Domain format requirements:
+  • Must be a valid domain name (e.g., example.com)
+  • Must include a supported TLD (.com, .net, .org, .io, etc.)
+  • Cannot contain spaces or special characters
+  • Must be between 1-63 characters per label`}
+
+	msgRateLimitError = &i18n.Message{ID: "RateLimitError", Other: "Rate Limit Error: Too many requests to AWS API"}
+	msgRateLimitHelp  = &i18n.Message{ID: "RateLimitHelp", Other: `To fix this issue:
+  • Wait a few seconds and try again
+  • AWS Route 53 Domains has rate limits to prevent abuse
+  • Consider implementing delays between requests if checking multiple domains`}
+
+	msgTimeoutError = &i18n.Message{ID: "TimeoutError", Other: "Timeout Error: Request took too long to complete"}
+	msgTimeoutHelp  = &i18n.Message{ID: "TimeoutHelp", Other: `Possible causes:
+  • Slow network connection
+  • AWS service temporarily unavailable
+  • Request timeout set too low
+
+Try running the command again or check your network connection.`}
+
+	msgNetworkError = &i18n.Message{ID: "NetworkError", Other: "Network Error: Unable to connect to AWS services"}
+	msgNetworkHelp  = &i18n.Message{ID: "NetworkHelp", Other: `Possible solutions:
+  • Check your internet connection
+  • Verify firewall settings allow HTTPS traffic
+  • Check if you're behind a corporate proxy
+  • Try again in a few minutes`}
+)
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/domain"
+	"github.com/abakermi/r53check/internal/errors"
+)
+
+// ResultOrder controls the order in which bulk results are listed
+type ResultOrder string
+
+const (
+	OrderInput     ResultOrder = "input"     // Same order as the domains were given
+	OrderCompleted ResultOrder = "completed" // Order in which the checks finished
+	OrderAlpha     ResultOrder = "alpha"     // Alphabetical by domain name
+	OrderPrice     ResultOrder = "price"     // Cheapest available domains first
+)
+
+// ParseResultOrder converts a flag value into a ResultOrder
+func ParseResultOrder(value string) (ResultOrder, error) {
+	switch order := ResultOrder(strings.ToLower(strings.TrimSpace(value))); order {
+	case OrderInput, OrderCompleted, OrderAlpha, OrderPrice:
+		return order, nil
+	default:
+		return "", errors.NewValidationError("", "order",
+			fmt.Sprintf("invalid order %q: must be one of input, completed, alpha, price%s", value,
+				errors.DidYouMean(value, []string{string(OrderInput), string(OrderCompleted), string(OrderAlpha), string(OrderPrice)})), nil)
+	}
+}
+
+// SortResults returns a copy of results arranged according to order.
+// The input order is returned unchanged.
+func SortResults(results []*domain.AvailabilityResult, order ResultOrder) []*domain.AvailabilityResult {
+	sorted := make([]*domain.AvailabilityResult, len(results))
+	copy(sorted, results)
+
+	switch order {
+	case OrderCompleted:
+		// Results that never completed (nil or skipped) go last
+		sort.SliceStable(sorted, func(i, j int) bool {
+			a, b := sorted[i], sorted[j]
+			if a == nil || a.CompletedAt.IsZero() {
+				return false
+			}
+			if b == nil || b.CompletedAt.IsZero() {
+				return true
+			}
+			return a.CompletedAt.Before(b.CompletedAt)
+		})
+	case OrderAlpha:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			a, b := sorted[i], sorted[j]
+			if a == nil {
+				return false
+			}
+			if b == nil {
+				return true
+			}
+			return a.Domain < b.Domain
+		})
+	case OrderPrice:
+		// Available domains by registration price, then available domains
+		// without a known price, then the rest in input order
+		sort.SliceStable(sorted, func(i, j int) bool {
+			a, b := priceRank(sorted[i]), priceRank(sorted[j])
+			if a.group != b.group {
+				return a.group < b.group
+			}
+			return a.price < b.price
+		})
+	}
+
+	return sorted
+}
+
+// rank places a result in the price order: its group, and its registration
+// price within the first group
+type rank struct {
+	group int
+	price float64
+}
+
+// priceRank ranks priced available results first, by price, then the other
+// available results, then the rest, with nil results last
+func priceRank(result *domain.AvailabilityResult) rank {
+	switch {
+	case result == nil:
+		return rank{group: 3}
+	case result.Skipped || result.Error != nil || !result.Available:
+		return rank{group: 2}
+	case result.Pricing == nil || result.Pricing.RegistrationPrice == nil:
+		return rank{group: 1}
+	default:
+		return rank{price: *result.Pricing.RegistrationPrice}
+	}
+}
@@ -0,0 +1,95 @@
+package output
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+func TestParseResultOrder(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ResultOrder
+		wantErr  bool
+	}{
+		{"input", OrderInput, false},
+		{"completed", OrderCompleted, false},
+		{"ALPHA", OrderAlpha, false},
+		{" alpha ", OrderAlpha, false},
+		{"price", OrderPrice, false},
+		{"random", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			order, err := ParseResultOrder(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseResultOrder(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if order != tt.expected {
+				t.Errorf("ParseResultOrder(%q) = %q, want %q", tt.input, order, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSortResults(t *testing.T) {
+	base := time.Date(2023, 12, 25, 10, 30, 0, 0, time.UTC)
+	results := []*domain.AvailabilityResult{
+		{Domain: "charlie.com", CompletedAt: base.Add(2 * time.Second)},
+		{Domain: "alpha.com", CompletedAt: base.Add(3 * time.Second)},
+		{Domain: "bravo.com"},
+		{Domain: "delta.com", CompletedAt: base.Add(1 * time.Second)},
+	}
+
+	tests := []struct {
+		order    ResultOrder
+		expected []string
+	}{
+		{OrderInput, []string{"charlie.com", "alpha.com", "bravo.com", "delta.com"}},
+		{OrderCompleted, []string{"delta.com", "charlie.com", "alpha.com", "bravo.com"}},
+		{OrderAlpha, []string{"alpha.com", "bravo.com", "charlie.com", "delta.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			sorted := SortResults(results, tt.order)
+			for i, want := range tt.expected {
+				if sorted[i].Domain != want {
+					t.Errorf("position %d: got %s, want %s", i, sorted[i].Domain, want)
+				}
+			}
+		})
+	}
+
+	// The input slice must not be reordered
+	if results[0].Domain != "charlie.com" {
+		t.Error("SortResults modified its input")
+	}
+}
+
+func TestSortResults_Price(t *testing.T) {
+	results := []*domain.AvailabilityResult{
+		{Domain: "taken.com", Status: domain.StatusUnavailable, Pricing: priced(1, 1, "USD")},
+		{Domain: "pricey.io", Available: true, Pricing: priced(40, 50, "USD")},
+		nil,
+		{Domain: "unpriced.org", Available: true},
+		{Domain: "cheap.com", Available: true, Pricing: priced(12, 15, "USD")},
+		{Domain: "failed.com", Available: true, Error: errors.New("throttled")},
+		{Domain: "cheap.net", Available: true, Pricing: priced(12, 14, "USD")},
+	}
+
+	expected := []string{"cheap.com", "cheap.net", "pricey.io", "unpriced.org", "taken.com", "failed.com"}
+	sorted := SortResults(results, OrderPrice)
+	for i, want := range expected {
+		if sorted[i] == nil || sorted[i].Domain != want {
+			t.Errorf("position %d: got %v, want %s", i, sorted[i], want)
+		}
+	}
+	if sorted[len(sorted)-1] != nil {
+		t.Errorf("expected the nil result last, got %v", sorted[len(sorted)-1])
+	}
+}
@@ -0,0 +1,129 @@
+package pricing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+const (
+	// PorkbunURL is Porkbun's price list endpoint, which needs no API key
+	PorkbunURL = "https://api.porkbun.com/api/json/v3/pricing/get"
+
+	// maxPriceListSize bounds a price list download
+	maxPriceListSize = 10 << 20
+)
+
+// Porkbun quotes Porkbun's prices from its public price list, which covers
+// every TLD it sells in USD. The list is downloaded once.
+type Porkbun struct {
+	client *http.Client
+	url    string
+
+	mu     sync.Mutex
+	prices map[string]*domain.PricingInfo
+}
+
+// NewPorkbun creates a Porkbun provider using client, or http.DefaultClient
+// when client is nil
+func NewPorkbun(client *http.Client) *Porkbun {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Porkbun{client: client, url: PorkbunURL}
+}
+
+// SetURL sets the price list endpoint, for tests
+func (p *Porkbun) SetURL(url string) {
+	p.url = url
+}
+
+// Name returns "porkbun"
+func (p *Porkbun) Name() string {
+	return "porkbun"
+}
+
+// Price returns Porkbun's prices for tld, or nil when it does not sell it
+func (p *Porkbun) Price(ctx context.Context, tld string) (*domain.PricingInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.prices == nil {
+		prices, err := p.fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.prices = prices
+	}
+	return p.prices[normalizeTLD(tld)], nil
+}
+
+// porkbunResponse is the price list, by TLD
+type porkbunResponse struct {
+	Status  string                   `json:"status"`
+	Message string                   `json:"message"`
+	Pricing map[string]porkbunPrices `json:"pricing"`
+}
+
+// porkbunPrices are the prices of a TLD, as decimal strings
+type porkbunPrices struct {
+	Registration string `json:"registration"`
+	Renewal      string `json:"renewal"`
+	Transfer     string `json:"transfer"`
+}
+
+// fetch downloads the price list
+func (p *Porkbun) fetch(ctx context.Context) (map[string]*domain.PricingInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader([]byte("{}")))
+	if err != nil {
+		return nil, customErrors.NewSystemError("porkbun", "invalid price list URL", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, customErrors.NewAPIError("porkbun", "pricing/get", "unable to reach Porkbun", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, customErrors.NewAPIError("porkbun", "pricing/get",
+			fmt.Sprintf("price list returned HTTP %d", resp.StatusCode), nil).WithStatusCode(resp.StatusCode)
+	}
+
+	var list porkbunResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxPriceListSize)).Decode(&list); err != nil {
+		return nil, customErrors.NewAPIError("porkbun", "pricing/get", "invalid price list", err)
+	}
+	if !strings.EqualFold(list.Status, "SUCCESS") {
+		return nil, customErrors.NewAPIError("porkbun", "pricing/get", "price list request failed: "+list.Message, nil)
+	}
+
+	prices := make(map[string]*domain.PricingInfo, len(list.Pricing))
+	for tld, fields := range list.Pricing {
+		prices[normalizeTLD(tld)] = &domain.PricingInfo{
+			RegistrationPrice: porkbunPrice(fields.Registration),
+			RenewalPrice:      porkbunPrice(fields.Renewal),
+			TransferPrice:     porkbunPrice(fields.Transfer),
+			Currency:          "USD",
+		}
+	}
+	return prices, nil
+}
+
+// porkbunPrice parses a price of the list, returning nil when it is missing
+// or malformed
+func porkbunPrice(value string) *float64 {
+	price, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return nil
+	}
+	return &price
+}
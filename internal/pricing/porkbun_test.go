@@ -0,0 +1,71 @@
+package pricing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPorkbun_Price(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected a POST, got %s", r.Method)
+		}
+		w.Write([]byte(`{"status":"SUCCESS","pricing":{"com":{"registration":"11.08","renewal":"11.08","transfer":"11.08","coupons":[]},"io":{"registration":"28.12","renewal":"","transfer":"28.12"}}}`))
+	}))
+	defer server.Close()
+
+	provider := NewPorkbun(server.Client())
+	provider.SetURL(server.URL)
+
+	pricing, err := provider.Price(context.Background(), "com")
+	if err != nil {
+		t.Fatalf("Price() error: %v", err)
+	}
+	if pricing == nil || *pricing.RegistrationPrice != 11.08 || pricing.Currency != "USD" {
+		t.Errorf("Unexpected .com prices: %+v", pricing)
+	}
+	if pricing, _ := provider.Price(context.Background(), ".io"); pricing == nil || pricing.RenewalPrice != nil {
+		t.Errorf("Expected .io prices without renewal, got %+v", pricing)
+	}
+	if pricing, _ := provider.Price(context.Background(), "zz"); pricing != nil {
+		t.Errorf("Expected no price for a TLD Porkbun does not sell, got %+v", pricing)
+	}
+	if requests != 1 {
+		t.Errorf("Expected the price list to be downloaded once, got %d requests", requests)
+	}
+}
+
+func TestPorkbun_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		err    string
+	}{
+		{"HTTP error", http.StatusServiceUnavailable, "", "HTTP 503"},
+		{"failed", http.StatusOK, `{"status":"ERROR","message":"rate limited"}`, "rate limited"},
+		{"invalid JSON", http.StatusOK, `<html>`, "invalid price list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider := NewPorkbun(server.Client())
+			provider.SetURL(server.URL)
+			_, err := provider.Price(context.Background(), "com")
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
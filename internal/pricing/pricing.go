@@ -0,0 +1,85 @@
+// Package pricing compares what registrars charge for a TLD. Each registrar
+// is a Provider: Route 53 through ListPrices, a registrar with a public price
+// API, or a static price table read from a file. Prices in different
+// currencies are not converted, so only quotes in the same currency are
+// compared.
+package pricing
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+// Provider quotes a registrar's prices
+type Provider interface {
+	// Name identifies the registrar, such as "route53"
+	Name() string
+	// Price returns the prices for registering, renewing and transferring a
+	// domain under tld, or nil when the registrar does not offer tld
+	Price(ctx context.Context, tld string) (*domain.PricingInfo, error)
+}
+
+// Quote is a provider's answer for a TLD
+type Quote struct {
+	Provider string
+	Pricing  *domain.PricingInfo
+	Err      error
+}
+
+// Offered reports whether the provider quoted a registration price
+func (q Quote) Offered() bool {
+	return q.Err == nil && q.Pricing != nil && q.Pricing.RegistrationPrice != nil
+}
+
+// Compare asks every provider for the prices of tld at once and returns
+// their quotes, the offered ones first from the cheapest registration, then
+// the rest in provider order
+func Compare(ctx context.Context, tld string, providers []Provider) []Quote {
+	quotes := make([]Quote, len(providers))
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pricing, err := provider.Price(ctx, tld)
+			quotes[i] = Quote{Provider: provider.Name(), Pricing: pricing, Err: err}
+		}()
+	}
+	wg.Wait()
+
+	slices.SortStableFunc(quotes, func(a, b Quote) int {
+		if a.Offered() != b.Offered() {
+			if a.Offered() {
+				return -1
+			}
+			return 1
+		}
+		if !a.Offered() {
+			return 0
+		}
+		if c := cmp.Compare(a.Pricing.Currency, b.Pricing.Currency); c != 0 {
+			return c
+		}
+		return cmp.Compare(*a.Pricing.RegistrationPrice, *b.Pricing.RegistrationPrice)
+	})
+	return quotes
+}
+
+// Cheapest returns the cheapest registration among quotes sorted by Compare,
+// one per currency, in currency order
+func Cheapest(quotes []Quote) []Quote {
+	var cheapest []Quote
+	for _, quote := range quotes {
+		if !quote.Offered() {
+			continue
+		}
+		if len(cheapest) == 0 || cheapest[len(cheapest)-1].Pricing.Currency != quote.Pricing.Currency {
+			cheapest = append(cheapest, quote)
+		}
+	}
+	return cheapest
+}
@@ -0,0 +1,66 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+// fixedProvider quotes registration prices from a map, in currency, or fails
+type fixedProvider struct {
+	name     string
+	prices   map[string]float64
+	currency string
+	err      error
+}
+
+func (p fixedProvider) Name() string {
+	return p.name
+}
+
+func (p fixedProvider) Price(ctx context.Context, tld string) (*domain.PricingInfo, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	price, ok := p.prices[tld]
+	if !ok {
+		return nil, nil
+	}
+	return &domain.PricingInfo{RegistrationPrice: &price, Currency: p.currency}, nil
+}
+
+func TestCompare(t *testing.T) {
+	providers := []Provider{
+		fixedProvider{name: "route53", prices: map[string]float64{"com": 15}, currency: "USD"},
+		fixedProvider{name: "down", err: errors.New("connection refused")},
+		fixedProvider{name: "cheap", prices: map[string]float64{"com": 9.5}, currency: "USD"},
+		fixedProvider{name: "none", prices: map[string]float64{"io": 30}, currency: "USD"},
+		fixedProvider{name: "euro", prices: map[string]float64{"com": 8}, currency: "EUR"},
+		fixedProvider{name: "pricey", prices: map[string]float64{"com": 20}, currency: "USD"},
+	}
+
+	quotes := Compare(context.Background(), "com", providers)
+	var order []string
+	for _, quote := range quotes {
+		order = append(order, quote.Provider)
+	}
+	expected := []string{"euro", "cheap", "route53", "pricey", "down", "none"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected quotes %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected quotes %v, got %v", expected, order)
+		}
+	}
+	if quotes[4].Err == nil || quotes[5].Offered() {
+		t.Errorf("Expected the failed and unoffered quotes last, got %+v", quotes[4:])
+	}
+
+	cheapest := Cheapest(quotes)
+	if len(cheapest) != 2 || cheapest[0].Provider != "euro" || cheapest[1].Provider != "cheap" {
+		t.Errorf("Expected the cheapest quote per currency, got %+v", cheapest)
+	}
+}
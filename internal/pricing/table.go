@@ -0,0 +1,152 @@
+package pricing
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+// tableColumns are the columns of a price table; registrar, tld and
+// registration are required
+var tableColumns = []string{"registrar", "tld", "registration", "renewal", "transfer", "currency"}
+
+// Table is a registrar's prices read from a price table
+type Table struct {
+	name   string
+	prices map[string]*domain.PricingInfo
+}
+
+// Name returns the registrar the table prices
+func (t *Table) Name() string {
+	return t.name
+}
+
+// Price returns the table's prices for tld, or nil when it has none
+func (t *Table) Price(ctx context.Context, tld string) (*domain.PricingInfo, error) {
+	return t.prices[normalizeTLD(tld)], nil
+}
+
+// LoadTables reads a CSV price table with a header row naming its columns:
+// registrar, tld and registration are required, and renewal, transfer and
+// currency optional. Prices without a currency are in USD. A file may price
+// several registrars, which are returned in the order they first appear.
+func LoadTables(path string) ([]*Table, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseTables(file)
+}
+
+// ParseTables reads a CSV price table, as LoadTables does
+func ParseTables(r io.Reader) ([]*Table, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("price table is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range tableColumns[:3] {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("price table has no %q column; the columns are %s", name, strings.Join(tableColumns, ", "))
+		}
+	}
+
+	var tables []*Table
+	byName := make(map[string]*Table)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		name, tld := field("registrar"), normalizeTLD(field("tld"))
+		if name == "" && tld == "" {
+			continue
+		}
+		if name == "" || tld == "" {
+			return nil, fmt.Errorf("line %d: registrar and tld are required", line)
+		}
+
+		pricing := &domain.PricingInfo{Currency: strings.ToUpper(field("currency"))}
+		if pricing.Currency == "" {
+			pricing.Currency = "USD"
+		}
+		for _, price := range []struct {
+			column string
+			value  **float64
+		}{
+			{"registration", &pricing.RegistrationPrice},
+			{"renewal", &pricing.RenewalPrice},
+			{"transfer", &pricing.TransferPrice},
+		} {
+			*price.value, err = parsePrice(field(price.column))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s price: %w", line, price.column, err)
+			}
+		}
+		if pricing.RegistrationPrice == nil {
+			return nil, fmt.Errorf("line %d: registration price is required", line)
+		}
+
+		table, ok := byName[name]
+		if !ok {
+			table = &Table{name: name, prices: make(map[string]*domain.PricingInfo)}
+			byName[name] = table
+			tables = append(tables, table)
+		}
+		table.prices[tld] = pricing
+	}
+	if len(tables) == 0 {
+		return nil, errors.New("price table has no prices")
+	}
+	return tables, nil
+}
+
+// parsePrice parses a price such as 9.99 or $9.99, returning nil when it is empty
+func parsePrice(value string) (*float64, error) {
+	value = strings.TrimPrefix(value, "$")
+	if value == "" {
+		return nil, nil
+	}
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	if price < 0 {
+		return nil, fmt.Errorf("%s is negative", value)
+	}
+	return &price, nil
+}
+
+// normalizeTLD lowercases tld and removes its leading dot
+func normalizeTLD(tld string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(tld)), ".")
+}
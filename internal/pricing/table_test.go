@@ -0,0 +1,57 @@
+package pricing
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseTables(t *testing.T) {
+	tables, err := ParseTables(strings.NewReader(`registrar,tld,registration,renewal,transfer,currency
+namecheap,.com,$9.58,15.88,,
+namecheap,io,32.98,,,
+gandi,COM,12.50,17.00,12.50,eur
+`))
+	if err != nil {
+		t.Fatalf("ParseTables() error: %v", err)
+	}
+	if len(tables) != 2 || tables[0].Name() != "namecheap" || tables[1].Name() != "gandi" {
+		t.Fatalf("Expected tables for namecheap and gandi in order, got %+v", tables)
+	}
+
+	pricing, _ := tables[0].Price(context.Background(), ".COM")
+	if pricing == nil || *pricing.RegistrationPrice != 9.58 || *pricing.RenewalPrice != 15.88 || pricing.TransferPrice != nil || pricing.Currency != "USD" {
+		t.Errorf("Unexpected namecheap .com prices: %+v", pricing)
+	}
+	if pricing, _ := tables[1].Price(context.Background(), "com"); pricing == nil || pricing.Currency != "EUR" {
+		t.Errorf("Expected gandi prices in EUR, got %+v", pricing)
+	}
+	if pricing, _ := tables[1].Price(context.Background(), "io"); pricing != nil {
+		t.Errorf("Expected no gandi price for .io, got %+v", pricing)
+	}
+}
+
+func TestParseTables_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{"empty", "", "empty"},
+		{"missing column", "registrar,tld\nnamecheap,com\n", `no "registration" column`},
+		{"no prices", "registrar,tld,registration\n", "no prices"},
+		{"missing tld", "registrar,tld,registration\nnamecheap,,9.58\n", "line 2: registrar and tld are required"},
+		{"missing registration", "registrar,tld,registration,renewal\nnamecheap,com,,15\n", "line 2: registration price is required"},
+		{"invalid price", "registrar,tld,registration\nnamecheap,com,cheap\n", "line 2: invalid registration price"},
+		{"negative price", "registrar,tld,registration\nnamecheap,com,-1\n", "negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTables(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
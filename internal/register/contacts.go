@@ -0,0 +1,140 @@
+package register
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/abakermi/r53check/internal/aws"
+	"github.com/abakermi/r53check/internal/config"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+// ParameterReader reads a parameter from SSM Parameter Store.
+// *aws.Parameters implements it.
+type ParameterReader interface {
+	GetParameter(ctx context.Context, name string) (string, error)
+}
+
+// Profile is a contact profile, resolved and ready to register domains with
+type Profile struct {
+	Name string `json:"name"`
+
+	// Source is "config", or the SSM parameter the profile was read from
+	Source string `json:"source"`
+
+	Registrant config.ContactSettings `json:"registrant"`
+	Admin      config.ContactSettings `json:"admin"`
+	Tech       config.ContactSettings `json:"tech"`
+
+	// Privacy is the WHOIS privacy protection of each contact
+	Privacy aws.ContactPrivacy `json:"privacy"`
+}
+
+// ProfileNames returns the names of the contact profiles, sorted
+func ProfileNames(profiles map[string]config.ContactProfile) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveProfile returns the contact profile name from profiles, reading it
+// from SSM Parameter Store through parameters when it names a parameter.
+// Privacy toggles set in the config file apply over those of the parameter;
+// contacts with neither use privacy. The contacts are checked like
+// NewContact checks them.
+func ResolveProfile(ctx context.Context, name string, profiles map[string]config.ContactProfile, parameters ParameterReader, privacy bool) (Profile, error) {
+	local, ok := profiles[name]
+	if !ok {
+		message := fmt.Sprintf("no contact profile %q in the config file", name)
+		if names := ProfileNames(profiles); len(names) > 0 {
+			message += fmt.Sprintf(" (profiles: %v)", names)
+		}
+		return Profile{}, customErrors.NewValidationError("", "contact", message, nil)
+	}
+
+	field := "contacts." + name
+	profile, source := local, "config"
+	if local.SSMParameter != "" {
+		if local.Registrant != (config.ContactSettings{}) || local.Admin != (config.ContactSettings{}) || local.Tech != (config.ContactSettings{}) {
+			return Profile{}, customErrors.NewValidationError("", field+".ssm_parameter",
+				"a contact profile read from SSM Parameter Store cannot also list contacts in the config file", nil)
+		}
+		if parameters == nil {
+			return Profile{}, customErrors.NewValidationError("", field+".ssm_parameter", "SSM Parameter Store cannot be read", nil)
+		}
+		value, err := parameters.GetParameter(ctx, local.SSMParameter)
+		if err != nil {
+			return Profile{}, err
+		}
+		if profile, err = config.ParseContactProfile([]byte(value)); err != nil {
+			return Profile{}, customErrors.NewValidationError("", field+".ssm_parameter",
+				"the contact profile in "+local.SSMParameter+" is invalid", err)
+		}
+		profile.Privacy = mergePrivacy(local.Privacy, profile.Privacy)
+		source = "ssm:" + local.SSMParameter
+	}
+
+	resolved := Profile{Name: name, Source: source, Registrant: profile.Registrant, Admin: profile.Admin, Tech: profile.Tech}
+	if resolved.Admin == (config.ContactSettings{}) {
+		resolved.Admin = resolved.Registrant
+	}
+	if resolved.Tech == (config.ContactSettings{}) {
+		resolved.Tech = resolved.Registrant
+	}
+	resolved.Privacy = aws.ContactPrivacy{
+		Registrant: toggle(profile.Privacy.Registrant, privacy),
+		Admin:      toggle(profile.Privacy.Admin, privacy),
+		Tech:       toggle(profile.Privacy.Tech, privacy),
+	}
+
+	for _, role := range []struct {
+		key     string
+		contact config.ContactSettings
+	}{{"registrant", resolved.Registrant}, {"admin", resolved.Admin}, {"tech", resolved.Tech}} {
+		if _, err := newContact(role.contact, field+"."+role.key); err != nil {
+			return Profile{}, err
+		}
+	}
+	return resolved, nil
+}
+
+// Options returns the registration options of the profile
+func (p Profile) Options(autoRenew bool) (aws.RegistrationOptions, error) {
+	options := aws.RegistrationOptions{AutoRenew: autoRenew, Privacy: p.Privacy}
+	var err error
+	if options.Registrant, err = newContact(p.Registrant, "contacts."+p.Name+".registrant"); err != nil {
+		return options, err
+	}
+	if options.Admin, err = newContact(p.Admin, "contacts."+p.Name+".admin"); err != nil {
+		return options, err
+	}
+	options.Tech, err = newContact(p.Tech, "contacts."+p.Name+".tech")
+	return options, err
+}
+
+// mergePrivacy returns the toggles of local, with those it leaves unset
+// taken from fallback
+func mergePrivacy(local, fallback config.PrivacySettings) config.PrivacySettings {
+	if local.Registrant == nil {
+		local.Registrant = fallback.Registrant
+	}
+	if local.Admin == nil {
+		local.Admin = fallback.Admin
+	}
+	if local.Tech == nil {
+		local.Tech = fallback.Tech
+	}
+	return local
+}
+
+// toggle returns the value of a privacy toggle, or fallback when unset
+func toggle(value *bool, fallback bool) bool {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
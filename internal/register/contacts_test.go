@@ -0,0 +1,94 @@
+package register
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/abakermi/r53check/internal/aws"
+	"github.com/abakermi/r53check/internal/config"
+)
+
+// fakeParameters serves parameters from a map
+type fakeParameters map[string]string
+
+func (f fakeParameters) GetParameter(ctx context.Context, name string) (string, error) {
+	value, ok := f[name]
+	if !ok {
+		return "", errors.New("ParameterNotFound")
+	}
+	return value, nil
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestResolveProfile(t *testing.T) {
+	tech := validContact()
+	tech.FirstName, tech.Email = "Grace", "ops@example.com"
+	profiles := map[string]config.ContactProfile{
+		"work": {
+			Registrant: validContact(),
+			Tech:       tech,
+			Privacy:    config.PrivacySettings{Tech: boolPtr(false)},
+		},
+		"shared": {
+			SSMParameter: "/r53check/contacts/shared",
+			Privacy:      config.PrivacySettings{Registrant: boolPtr(false)},
+		},
+		"missing": {SSMParameter: "/r53check/contacts/missing"},
+		"both":    {SSMParameter: "/r53check/contacts/shared", Registrant: validContact()},
+		"invalid": {Registrant: config.ContactSettings{FirstName: "Ada"}},
+	}
+	parameters := fakeParameters{"/r53check/contacts/shared": `{
+  "registrant": {"first_name": "Ada", "last_name": "Lovelace", "email": "ada@example.com", "phone": "+44.2071234567",
+    "address_line_1": "12 St James's Square", "city": "London", "country_code": "GB", "zip_code": "SW1Y 4JH"},
+  "privacy": {"registrant": true, "admin": false}
+}`}
+
+	profile, err := ResolveProfile(context.Background(), "work", profiles, parameters, true)
+	if err != nil {
+		t.Fatalf("ResolveProfile() error: %v", err)
+	}
+	if profile.Source != "config" || profile.Admin != profile.Registrant || profile.Tech.FirstName != "Grace" {
+		t.Errorf("Expected the admin to default to the registrant, got %+v", profile)
+	}
+	if !reflect.DeepEqual(profile.Privacy, aws.ContactPrivacy{Registrant: true, Admin: true, Tech: false}) {
+		t.Errorf("Unexpected privacy %+v", profile.Privacy)
+	}
+	options, err := profile.Options(true)
+	if err != nil || *options.Tech.Email != "ops@example.com" || *options.Admin.Email != "ada@example.com" || !options.AutoRenew {
+		t.Errorf("Unexpected options %+v, %v", options, err)
+	}
+
+	profile, err = ResolveProfile(context.Background(), "shared", profiles, parameters, true)
+	if err != nil {
+		t.Fatalf("ResolveProfile() error: %v", err)
+	}
+	if profile.Source != "ssm:/r53check/contacts/shared" || profile.Tech.FirstName != "Ada" {
+		t.Errorf("Expected the profile from the parameter, got %+v", profile)
+	}
+	if !reflect.DeepEqual(profile.Privacy, aws.ContactPrivacy{Registrant: false, Admin: false, Tech: true}) {
+		t.Errorf("Expected the config file's toggles over the parameter's, got %+v", profile.Privacy)
+	}
+
+	invalid := []struct {
+		name string
+		err  string
+	}{
+		{"unknown", `no contact profile "unknown" in the config file (profiles: [both invalid missing shared work])`},
+		{"missing", "ParameterNotFound"},
+		{"both", "cannot also list contacts"},
+		{"invalid", "the contact contacts.invalid.registrant is missing last_name"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ResolveProfile(context.Background(), tt.name, profiles, parameters, true); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
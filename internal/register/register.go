@@ -0,0 +1,305 @@
+// Package register registers domains picked from the results of an earlier
+// check. Registrations are submitted one at a time, and each starts an
+// operation that Route 53 Domains finishes asynchronously; the outcome of
+// every domain is kept, so one failed registration does not stop the rest.
+package register
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abakermi/r53check/internal/config"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/output"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// Operation statuses, as Route 53 Domains reports them. StatusSubmitted is
+// also the status of a registration that was just started.
+const (
+	StatusSubmitted  = "SUBMITTED"
+	StatusInProgress = "IN_PROGRESS"
+	StatusSuccessful = "SUCCESSFUL"
+	StatusFailed     = "FAILED"
+	StatusError      = "ERROR"
+)
+
+// Registrar starts registrations and reports on their operations.
+// *aws.Registrar implements it.
+type Registrar interface {
+	Register(ctx context.Context, domain string, years int) (string, error)
+	OperationStatus(ctx context.Context, operationID string) (string, string, error)
+}
+
+// NewContact converts the contact from the config file to the form Route 53
+// Domains takes, checking that the fields every registration needs are set
+func NewContact(settings config.ContactSettings) (types.ContactDetail, error) {
+	return newContact(settings, "register.contact")
+}
+
+// newContact is NewContact for the contact at field of the config file
+func newContact(settings config.ContactSettings, field string) (types.ContactDetail, error) {
+	required := []struct {
+		key   string
+		value string
+	}{
+		{"first_name", settings.FirstName},
+		{"last_name", settings.LastName},
+		{"email", settings.Email},
+		{"phone", settings.Phone},
+		{"address_line_1", settings.AddressLine1},
+		{"city", settings.City},
+		{"country_code", settings.CountryCode},
+		{"zip_code", settings.ZipCode},
+	}
+	var missing []string
+	for _, field := range required {
+		if strings.TrimSpace(field.value) == "" {
+			missing = append(missing, field.key)
+		}
+	}
+	if len(missing) > 0 {
+		return types.ContactDetail{}, customErrors.NewValidationError("", field+"."+missing[0],
+			fmt.Sprintf("the contact %s is missing %s", field, strings.Join(missing, ", ")), nil)
+	}
+
+	contactType := types.ContactTypePerson
+	if settings.Type != "" {
+		contactType = types.ContactType(strings.ToUpper(settings.Type))
+		if !slices.Contains(contactType.Values(), contactType) {
+			return types.ContactDetail{}, customErrors.NewValidationError("", field+".type",
+				fmt.Sprintf("unknown contact type %q", settings.Type), nil)
+		}
+	}
+	if contactType != types.ContactTypePerson && settings.Organization == "" {
+		return types.ContactDetail{}, customErrors.NewValidationError("", field+".organization",
+			fmt.Sprintf("a %s contact needs an organization", contactType), nil)
+	}
+
+	return types.ContactDetail{
+		ContactType:      contactType,
+		FirstName:        aws.String(settings.FirstName),
+		LastName:         aws.String(settings.LastName),
+		OrganizationName: optional(settings.Organization),
+		Email:            aws.String(settings.Email),
+		PhoneNumber:      aws.String(settings.Phone),
+		AddressLine1:     aws.String(settings.AddressLine1),
+		AddressLine2:     optional(settings.AddressLine2),
+		City:             aws.String(settings.City),
+		State:            optional(settings.State),
+		CountryCode:      types.CountryCode(strings.ToUpper(settings.CountryCode)),
+		ZipCode:          aws.String(settings.ZipCode),
+	}, nil
+}
+
+// optional returns nil for an empty setting, which Route 53 leaves unset
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return aws.String(value)
+}
+
+// ReadCandidates returns the domains of the results in the file at path,
+// written by bulk or generate with --output json, in their order there.
+// With onlyAvailable the domains that are not available are left out;
+// without it they are an error, so nothing is registered from a file that
+// was not meant for it. Results that failed are always left out.
+func ReadCandidates(path string, onlyAvailable bool) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, customErrors.NewValidationError("", "from", "unable to read results "+path, err)
+	}
+	var results output.JSONBulkResults
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, customErrors.NewValidationError("", "from",
+			path+" is not the JSON output of bulk; write it with --output json", err)
+	}
+
+	seen := make(map[string]bool)
+	var candidates, unavailable []string
+	for _, result := range results.Results {
+		name := strings.ToLower(strings.TrimSuffix(result.Domain, "."))
+		if name == "" || seen[name] || result.Error != "" {
+			continue
+		}
+		seen[name] = true
+		if !result.Available {
+			unavailable = append(unavailable, name)
+			continue
+		}
+		candidates = append(candidates, name)
+	}
+	if len(unavailable) > 0 && !onlyAvailable {
+		return nil, customErrors.NewValidationError("", "only-available",
+			fmt.Sprintf("%s in %s %s not available; use --only-available to skip them",
+				strings.Join(unavailable, ", "), path, pluralVerb(len(unavailable))), nil)
+	}
+	return candidates, nil
+}
+
+func pluralVerb(count int) string {
+	if count == 1 {
+		return "is"
+	}
+	return "are"
+}
+
+// Order is a domain to register and what registering it costs
+type Order struct {
+	Domain   string  `json:"domain"`
+	Cost     float64 `json:"cost"`
+	Currency string  `json:"currency"`
+}
+
+// Outcome is what became of an order: the operation registering the domain
+// and its last known status, or why the registration could not be started
+type Outcome struct {
+	Order
+	OperationID string `json:"operation_id,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Message     string `json:"message,omitempty"`
+
+	// Tagged is set once the domain carries the tags of --tags
+	Tagged bool `json:"tagged,omitempty"`
+
+	// Err is why Route 53 refused the registration, when it did
+	Err error `json:"-"`
+}
+
+// Failed reports whether the registration failed
+func (o Outcome) Failed() bool {
+	return o.Status == StatusFailed || o.Status == StatusError
+}
+
+// Pending reports whether the registration is still being processed
+func (o Outcome) Pending() bool {
+	return o.Status == StatusSubmitted || o.Status == StatusInProgress
+}
+
+// Transient reports whether Route 53 refused the registration for a reason
+// that may pass: throttling, a network error or a server error. Submitting
+// the registration again only makes sense then; a refusal of the order
+// itself, such as of its contact or TLD, is given again every time.
+func (o Outcome) Transient() bool {
+	if o.Err == nil {
+		return false
+	}
+	if customErrors.DefaultRetryPolicy().Budget(o.Err) > 0 {
+		return true
+	}
+	if code, err := strconv.Atoi(customErrors.RetryKey(o.Err)); err == nil && code >= 500 {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(o.Err, &netErr)
+}
+
+// Ambiguous reports whether the registration failed without an answer from
+// Route 53: the request timed out, was cancelled, or was lost to a network
+// error after it was sent. Route 53 may still have accepted it, so the domain
+// may be registered and charged for. An error dialling Route 53 is not
+// ambiguous, as the request never left.
+func (o Outcome) Ambiguous() bool {
+	if o.Err == nil {
+		return false
+	}
+	var timeoutErr *customErrors.TimeoutError
+	if errors.As(o.Err, &timeoutErr) || errors.Is(o.Err, context.DeadlineExceeded) || errors.Is(o.Err, context.Canceled) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(o.Err, &opErr) && opErr.Op == "dial" {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(o.Err, &netErr)
+}
+
+// registerTimeout bounds a Register call once it no longer stops with the
+// context of the run
+const registerTimeout = time.Minute
+
+// Submit starts registering each order for years, one at a time, and
+// returns the outcome of each in order. A registration Route 53 refuses is
+// recorded as failed with the reason, and the next one is still submitted.
+// Once ctx is done the orders left are failed without being submitted; the
+// one being submitted is not abandoned, as Route 53 may already be
+// processing it, and its Register call runs on until it returns or
+// registerTimeout passes.
+// When done is not nil it is called with each outcome as soon as its
+// Register call returns, before the next order is submitted, so callers can
+// record what Route 53 accepted even if the run is stopped part way.
+func Submit(ctx context.Context, registrar Registrar, orders []Order, years int, done func(Outcome)) []Outcome {
+	outcomes := make([]Outcome, 0, len(orders))
+	for _, order := range orders {
+		outcome := Outcome{Order: order}
+		if err := ctx.Err(); err != nil {
+			outcome.Status = StatusFailed
+			outcome.Message = "not submitted: " + err.Error()
+			outcomes = append(outcomes, outcome)
+			continue
+		}
+
+		registerCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), registerTimeout)
+		operationID, err := registrar.Register(registerCtx, order.Domain, years)
+		cancel()
+		if err != nil {
+			outcome.Status = StatusFailed
+			outcome.Message = err.Error()
+			outcome.Err = err
+		} else {
+			outcome.OperationID = operationID
+			outcome.Status = StatusSubmitted
+		}
+		if done != nil {
+			done(outcome)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes
+}
+
+// Wait polls the operations of the pending outcomes every interval, updating
+// their status and message, until none is pending or ctx is done. An
+// operation whose status cannot be read keeps its last known status.
+func Wait(ctx context.Context, registrar Registrar, outcomes []Outcome, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pending := 0
+		for i := range outcomes {
+			if !outcomes[i].Pending() {
+				continue
+			}
+			status, message, err := registrar.OperationStatus(ctx, outcomes[i].OperationID)
+			if err == nil {
+				outcomes[i].Status = status
+				outcomes[i].Message = message
+			}
+			if outcomes[i].Pending() {
+				pending++
+			}
+		}
+		if pending == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
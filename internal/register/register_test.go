@@ -0,0 +1,269 @@
+package register
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/config"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// fakeRegistrar refuses the domains in refused and moves every operation
+// through the statuses in progress, one per poll
+type fakeRegistrar struct {
+	refused   map[string]bool
+	progress  map[string][]string
+	submitted []string
+}
+
+func (f *fakeRegistrar) Register(ctx context.Context, domain string, years int) (string, error) {
+	if f.refused[domain] {
+		return "", errors.New("DomainLimitExceeded")
+	}
+	f.submitted = append(f.submitted, domain)
+	return "op-" + domain, nil
+}
+
+func (f *fakeRegistrar) OperationStatus(ctx context.Context, operationID string) (string, string, error) {
+	statuses := f.progress[operationID]
+	if len(statuses) == 0 {
+		return "", "", errors.New("OperationNotFound")
+	}
+	status := statuses[0]
+	if len(statuses) > 1 {
+		f.progress[operationID] = statuses[1:]
+	}
+	return status, strings.ToLower(status), nil
+}
+
+func validContact() config.ContactSettings {
+	return config.ContactSettings{
+		FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Phone: "+44.2071234567",
+		AddressLine1: "12 St James's Square", City: "London", CountryCode: "gb", ZipCode: "SW1Y 4JH",
+	}
+}
+
+func TestNewContact(t *testing.T) {
+	contact, err := NewContact(validContact())
+	if err != nil {
+		t.Fatalf("NewContact() error: %v", err)
+	}
+	if contact.ContactType != types.ContactTypePerson || contact.CountryCode != "GB" || aws.ToString(contact.Email) != "ada@example.com" {
+		t.Errorf("Unexpected contact %+v", contact)
+	}
+	if contact.OrganizationName != nil || contact.State != nil {
+		t.Errorf("Expected the optional fields to be unset, got %+v", contact)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*config.ContactSettings)
+		err    string
+	}{
+		{"missing fields", func(c *config.ContactSettings) { c.Email, c.ZipCode = "", " " }, "missing email, zip_code"},
+		{"unknown type", func(c *config.ContactSettings) { c.Type = "robot" }, `unknown contact type "robot"`},
+		{"company without organization", func(c *config.ContactSettings) { c.Type = "company" }, "a COMPANY contact needs an organization"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := validContact()
+			tt.modify(&settings)
+			if _, err := NewContact(settings); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestReadCandidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	results := `{"results": [
+  {"domain": "a.com", "available": true, "status": "AVAILABLE"},
+  {"domain": "taken.com", "available": false, "status": "UNAVAILABLE"},
+  {"domain": "broken.com", "available": false, "status": "", "error": "throttled"},
+  {"domain": "B.io", "available": true, "status": "AVAILABLE"},
+  {"domain": "a.com", "available": true, "status": "AVAILABLE"}
+], "summary": {"total": 5}}`
+	if err := os.WriteFile(path, []byte(results), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	candidates, err := ReadCandidates(path, true)
+	if err != nil || !reflect.DeepEqual(candidates, []string{"a.com", "b.io"}) {
+		t.Errorf("Expected the available domains once each, got %v, %v", candidates, err)
+	}
+	if _, err := ReadCandidates(path, false); err == nil || !strings.Contains(err.Error(), "taken.com") {
+		t.Errorf("Expected the unavailable domain to be an error, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("a.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadCandidates(path, true); err == nil || !strings.Contains(err.Error(), "--output json") {
+		t.Errorf("Expected a file that is not JSON to be rejected, got %v", err)
+	}
+}
+
+func TestSubmitAndWait(t *testing.T) {
+	registrar := &fakeRegistrar{
+		refused: map[string]bool{"b.com": true},
+		progress: map[string][]string{
+			"op-a.com": {StatusInProgress, StatusSuccessful},
+			"op-c.io":  {StatusInProgress, StatusInProgress, StatusFailed},
+		},
+	}
+	orders := []Order{{"a.com", 15, "USD"}, {"b.com", 15, "USD"}, {"c.io", 39, "USD"}}
+
+	outcomes := Submit(context.Background(), registrar, orders, 1, nil)
+	if !reflect.DeepEqual(registrar.submitted, []string{"a.com", "c.io"}) {
+		t.Errorf("Expected every order but the refused one to be submitted, got %v", registrar.submitted)
+	}
+	if outcomes[0].Status != StatusSubmitted || outcomes[0].OperationID != "op-a.com" {
+		t.Errorf("Unexpected outcome %+v", outcomes[0])
+	}
+	if !outcomes[1].Failed() || !strings.Contains(outcomes[1].Message, "DomainLimitExceeded") {
+		t.Errorf("Expected the refused order to fail with the reason, got %+v", outcomes[1])
+	}
+
+	if err := Wait(context.Background(), registrar, outcomes, time.Millisecond); err != nil {
+		t.Fatalf("Wait() error: %v", err)
+	}
+	statuses := []string{outcomes[0].Status, outcomes[1].Status, outcomes[2].Status}
+	if !reflect.DeepEqual(statuses, []string{StatusSuccessful, StatusFailed, StatusFailed}) {
+		t.Errorf("Unexpected statuses after waiting %v", statuses)
+	}
+}
+
+func TestWait_Timeout(t *testing.T) {
+	registrar := &fakeRegistrar{progress: map[string][]string{"op-a.com": {StatusInProgress}}}
+	outcomes := Submit(context.Background(), registrar, []Order{{"a.com", 15, "USD"}}, 1, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := Wait(ctx, registrar, outcomes, time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to stop waiting, got %v", err)
+	}
+	if outcomes[0].Status != StatusInProgress {
+		t.Errorf("Expected the last known status, got %+v", outcomes[0])
+	}
+}
+
+func TestSubmit_Done(t *testing.T) {
+	registrar := &fakeRegistrar{refused: map[string]bool{"b.com": true}}
+	orders := []Order{{"a.com", 15, "USD"}, {"b.com", 15, "USD"}, {"c.io", 39, "USD"}}
+
+	var done []string
+	Submit(context.Background(), registrar, orders, 1, func(outcome Outcome) {
+		// Each outcome is reported before the next order is submitted
+		if len(registrar.submitted) > len(done)+1 {
+			t.Errorf("%s reported after later orders were submitted: %v", outcome.Domain, registrar.submitted)
+		}
+		done = append(done, outcome.Domain+" "+outcome.Status)
+	})
+	want := []string{"a.com " + StatusSubmitted, "b.com " + StatusFailed, "c.io " + StatusSubmitted}
+	if !reflect.DeepEqual(done, want) {
+		t.Errorf("Expected each outcome in order, got %v", done)
+	}
+}
+
+func TestSubmit_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	registrar := &fakeRegistrar{}
+	outcomes := Submit(ctx, registrar, []Order{{"a.com", 15, "USD"}}, 1, nil)
+	if len(registrar.submitted) != 0 || !outcomes[0].Failed() {
+		t.Errorf("Expected nothing to be submitted after cancellation, got %+v", outcomes)
+	}
+}
+
+// blockingRegistrar cancels the run while a registration is in flight, and
+// accepts it unless its own context was cancelled
+type blockingRegistrar struct {
+	fakeRegistrar
+	cancel context.CancelFunc
+}
+
+func (b *blockingRegistrar) Register(ctx context.Context, domain string, years int) (string, error) {
+	b.cancel()
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-time.After(20 * time.Millisecond):
+	}
+	return b.fakeRegistrar.Register(ctx, domain, years)
+}
+
+func TestSubmit_CancelledInFlight(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	registrar := &blockingRegistrar{cancel: cancel}
+	outcomes := Submit(ctx, registrar, []Order{{"a.com", 15, "USD"}, {"b.com", 15, "USD"}}, 1, nil)
+
+	if outcomes[0].Status != StatusSubmitted || outcomes[0].OperationID != "op-a.com" {
+		t.Errorf("Expected the registration in flight to finish, got %+v", outcomes[0])
+	}
+	if !outcomes[1].Failed() || !reflect.DeepEqual(registrar.submitted, []string{"a.com"}) {
+		t.Errorf("Expected the next order not to be submitted, got %+v", outcomes[1])
+	}
+}
+
+func TestOutcome_Ambiguous(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		ambiguous bool
+	}{
+		{"accepted", nil, false},
+		{"timed out", customErrors.NewTimeoutError("RegisterDomain", 0, context.DeadlineExceeded), true},
+		{"deadline", fmt.Errorf("register: %w", context.DeadlineExceeded), true},
+		{"cancelled", context.Canceled, true},
+		{"connection reset", &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}, true},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, false},
+		{"throttled", customErrors.NewAPIError("route53domains", "RegisterDomain", "rate exceeded", nil).WithStatusCode(429), false},
+		{"refused", errors.New("DomainLimitExceeded"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome := Outcome{Status: StatusFailed, Err: tt.err}
+			if got := outcome.Ambiguous(); got != tt.ambiguous {
+				t.Errorf("Ambiguous() = %v, want %v", got, tt.ambiguous)
+			}
+		})
+	}
+}
+
+func TestOutcome_Transient(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{"throttled", customErrors.NewAPIError("route53domains", "RegisterDomain", "rate exceeded", nil).WithStatusCode(429), true},
+		{"unavailable", customErrors.NewAPIError("route53domains", "RegisterDomain", "unavailable", nil).WithStatusCode(503), true},
+		{"server error", customErrors.NewAPIError("route53domains", "RegisterDomain", "internal", nil).WithStatusCode(502), true},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"unsupported TLD", customErrors.NewValidationError("a.example", "tld", "unsupported", nil), false},
+		{"duplicate request", customErrors.NewAPIError("route53domains", "RegisterDomain", "DuplicateRequest", nil).WithStatusCode(400), false},
+		{"refused", errors.New("DomainLimitExceeded"), false},
+		{"not refused", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Outcome{Status: StatusFailed, Err: tt.err}).Transient(); got != tt.transient {
+				t.Errorf("Transient() = %v, want %v", got, tt.transient)
+			}
+		})
+	}
+}
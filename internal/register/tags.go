@@ -0,0 +1,159 @@
+package register
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/history"
+)
+
+// Tagger reads and updates the tags of the domains registered to the
+// account. *aws.DomainTags implements it.
+type Tagger interface {
+	RegisteredDomains(ctx context.Context) ([]string, error)
+	Tags(ctx context.Context, domain string) (map[string]string, error)
+	TagDomain(ctx context.Context, domain string, tags map[string]string) error
+}
+
+// ParseTags parses the key=value tags of --tags, checking that every key
+// in required is given
+func ParseTags(values []string, required []string) (map[string]string, error) {
+	tags, err := history.ParseTags(values)
+	if err != nil {
+		return nil, err
+	}
+	if missing := missingKeys(tags, required); len(missing) > 0 {
+		return nil, customErrors.NewValidationError("", "tags",
+			fmt.Sprintf("registered domains must be tagged with %s; add them to --tags", strings.Join(missing, ", ")), nil)
+	}
+	return tags, nil
+}
+
+// ApplyTags tags the domains whose registration succeeded, marking each
+// outcome tagged. Route 53 only tags registered domains, so the others are
+// left for AuditTags to find. The domains that could not be tagged are
+// reported together.
+func ApplyTags(ctx context.Context, tagger Tagger, outcomes []Outcome, tags map[string]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	var errs []error
+	for i := range outcomes {
+		if outcomes[i].Status != StatusSuccessful {
+			continue
+		}
+		if err := tagger.TagDomain(ctx, outcomes[i].Domain, tags); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", outcomes[i].Domain, err))
+			continue
+		}
+		outcomes[i].Tagged = true
+	}
+	return errors.Join(errs...)
+}
+
+// TagFinding is the audit of the tags of a registered domain
+type TagFinding struct {
+	Domain string            `json:"domain"`
+	Tags   map[string]string `json:"tags"`
+
+	// Missing are the required keys the domain has no tag for
+	Missing []string `json:"missing,omitempty"`
+
+	// Pending are the tags the domain was registered with whose keys it
+	// does not carry, such as when it was still registering. A key that was
+	// given another value since is left alone.
+	Pending map[string]string `json:"pending,omitempty"`
+
+	// Error is why the tags of the domain could not be read
+	Error string `json:"error,omitempty"`
+}
+
+// OK reports whether the domain carries every required and pending tag
+func (f TagFinding) OK() bool {
+	return f.Error == "" && len(f.Missing) == 0 && len(f.Pending) == 0
+}
+
+// AuditTags reads the tags of every domain registered to the account and
+// compares them with the required keys and the tags each domain was
+// registered with, from registered. A domain whose tags cannot be read is
+// reported with the error rather than failing the audit.
+func AuditTags(ctx context.Context, tagger Tagger, required []string, registered map[string]map[string]string) ([]TagFinding, error) {
+	domains, err := tagger.RegisteredDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]TagFinding, 0, len(domains))
+	for _, name := range domains {
+		name = strings.ToLower(name)
+		finding := TagFinding{Domain: name}
+		tags, err := tagger.Tags(ctx, name)
+		if err != nil {
+			finding.Error = err.Error()
+			findings = append(findings, finding)
+			continue
+		}
+		finding.Tags = tags
+		finding.check(required, registered[name])
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
+
+// ApplyPending adds the pending tags of the findings to their domains and
+// checks the domains again. The domains that could not be tagged keep their
+// pending tags and are reported together.
+func ApplyPending(ctx context.Context, tagger Tagger, findings []TagFinding, required []string) error {
+	var errs []error
+	for i := range findings {
+		finding := &findings[i]
+		if len(finding.Pending) == 0 {
+			continue
+		}
+		if err := tagger.TagDomain(ctx, finding.Domain, finding.Pending); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", finding.Domain, err))
+			continue
+		}
+		tags := maps.Clone(finding.Tags)
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		maps.Copy(tags, finding.Pending)
+		finding.Tags = tags
+		finding.check(required, nil)
+	}
+	return errors.Join(errs...)
+}
+
+// check sets the required keys the domain is missing and the registration
+// tags whose keys it does not carry
+func (f *TagFinding) check(required []string, registered map[string]string) {
+	f.Pending = nil
+	for key, value := range registered {
+		if _, ok := f.Tags[key]; !ok {
+			if f.Pending == nil {
+				f.Pending = make(map[string]string)
+			}
+			f.Pending[key] = value
+		}
+	}
+	f.Missing = missingKeys(f.Tags, required)
+}
+
+// missingKeys returns the keys of required that tags has no value for,
+// sorted
+func missingKeys(tags map[string]string, required []string) []string {
+	var missing []string
+	for _, key := range required {
+		if tags[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,110 @@
+package register
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeTagger keeps the tags of the registered domains in memory; domains
+// it has no tags for are not registered yet
+type fakeTagger struct {
+	domains []string
+	tags    map[string]map[string]string
+}
+
+func (f *fakeTagger) RegisteredDomains(ctx context.Context) ([]string, error) {
+	return f.domains, nil
+}
+
+func (f *fakeTagger) Tags(ctx context.Context, domain string) (map[string]string, error) {
+	tags, ok := f.tags[domain]
+	if !ok {
+		return nil, errors.New("AccessDenied")
+	}
+	return tags, nil
+}
+
+func (f *fakeTagger) TagDomain(ctx context.Context, domain string, tags map[string]string) error {
+	current, ok := f.tags[domain]
+	if !ok {
+		return errors.New("InvalidInput: domain not found")
+	}
+	for key, value := range tags {
+		current[key] = value
+	}
+	return nil
+}
+
+func TestParseTags(t *testing.T) {
+	tags, err := ParseTags([]string{"team=platform", "env=prod"}, []string{"team"})
+	if err != nil || !reflect.DeepEqual(tags, map[string]string{"team": "platform", "env": "prod"}) {
+		t.Errorf("ParseTags() = %v, %v", tags, err)
+	}
+	if _, err := ParseTags([]string{"env=prod"}, []string{"team", "cost-center"}); err == nil || !strings.Contains(err.Error(), "cost-center, team") {
+		t.Errorf("Expected the missing required keys, got %v", err)
+	}
+	if _, err := ParseTags([]string{"team"}, nil); err == nil {
+		t.Error("Expected a tag without a value to be rejected")
+	}
+}
+
+func TestApplyTags(t *testing.T) {
+	tagger := &fakeTagger{tags: map[string]map[string]string{"a.com": {}, "c.io": {}}}
+	outcomes := []Outcome{
+		{Order: Order{Domain: "a.com"}, Status: StatusSuccessful},
+		{Order: Order{Domain: "b.com"}, Status: StatusInProgress},
+		{Order: Order{Domain: "c.io"}, Status: StatusFailed},
+	}
+
+	if err := ApplyTags(context.Background(), tagger, outcomes, map[string]string{"team": "platform"}); err != nil {
+		t.Fatalf("ApplyTags() error: %v", err)
+	}
+	if !outcomes[0].Tagged || outcomes[1].Tagged || outcomes[2].Tagged || tagger.tags["a.com"]["team"] != "platform" || len(tagger.tags["c.io"]) != 0 {
+		t.Errorf("Expected only the registered domain to be tagged, got %+v", outcomes)
+	}
+}
+
+func TestAuditTags(t *testing.T) {
+	tagger := &fakeTagger{
+		domains: []string{"a.com", "B.io", "new.dev", "denied.net"},
+		tags: map[string]map[string]string{
+			"a.com":   {"team": "web", "env": "prod"},
+			"b.io":    {"env": "prod"},
+			"new.dev": {},
+		},
+	}
+	registered := map[string]map[string]string{
+		"b.io":    {"env": "staging"},
+		"new.dev": {"team": "platform", "env": "prod"},
+	}
+
+	findings, err := AuditTags(context.Background(), tagger, []string{"team"}, registered)
+	if err != nil {
+		t.Fatalf("AuditTags() error: %v", err)
+	}
+	if !findings[0].OK() {
+		t.Errorf("Expected a.com to carry its tags, got %+v", findings[0])
+	}
+	if !reflect.DeepEqual(findings[1].Missing, []string{"team"}) || findings[1].Pending != nil {
+		t.Errorf("Expected b.io to miss team but keep its own env, got %+v", findings[1])
+	}
+	if !reflect.DeepEqual(findings[2].Pending, registered["new.dev"]) || !reflect.DeepEqual(findings[2].Missing, []string{"team"}) {
+		t.Errorf("Expected new.dev to have pending tags, got %+v", findings[2])
+	}
+	if findings[3].Error == "" || findings[3].OK() {
+		t.Errorf("Expected the unreadable domain to be reported, got %+v", findings[3])
+	}
+
+	if err := ApplyPending(context.Background(), tagger, findings, []string{"team"}); err != nil {
+		t.Fatalf("ApplyPending() error: %v", err)
+	}
+	if !findings[2].OK() || tagger.tags["new.dev"]["team"] != "platform" {
+		t.Errorf("Expected the pending tags to be applied, got %+v", findings[2])
+	}
+	if findings[1].OK() {
+		t.Errorf("Expected b.io to still miss team, got %+v", findings[1])
+	}
+}
@@ -0,0 +1,165 @@
+// Package reporting sends unexpected errors and panics to Sentry, so teams
+// running the server or daemon learn about failures nobody was watching for.
+// Expected failures, such as invalid domains, missing credentials or AWS
+// rejecting a request, are never reported.
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// flushTimeout bounds how long reports are waited for before exiting
+const flushTimeout = 2 * time.Second
+
+// Reporter reports unexpected errors and panics to Sentry. It implements
+// domain.Hooks to report checks that failed unexpectedly. A nil Reporter
+// reports nothing.
+type Reporter struct {
+	domain.NopHooks
+
+	hub *sentry.Hub
+}
+
+// ParseTarget returns the Sentry DSN of an --error-reporting target of the
+// form sentry://key@host/project, which reports over HTTPS
+func ParseTarget(target string) (string, error) {
+	rest, ok := strings.CutPrefix(target, "sentry://")
+	if !ok || rest == "" {
+		return "", customErrors.NewValidationError("", "error-reporting",
+			"error reporting target must have the form sentry://key@host/project", nil)
+	}
+	return "https://" + rest, nil
+}
+
+// New creates a Reporter for target that tags every event with release
+func New(target, release string) (*Reporter, error) {
+	return NewWithTransport(target, release, nil)
+}
+
+// NewWithTransport creates a Reporter that sends events through transport
+// instead of over HTTP, for tests. A nil transport uses the default.
+func NewWithTransport(target, release string, transport sentry.Transport) (*Reporter, error) {
+	dsn, err := ParseTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:       dsn,
+		Release:   release,
+		Transport: transport,
+	})
+	if err != nil {
+		// The DSN holds the project key, so it is left out of the error
+		return nil, customErrors.NewValidationError("", "error-reporting", "invalid Sentry DSN in error reporting target", nil)
+	}
+	return &Reporter{hub: sentry.NewHub(client, sentry.NewScope())}, nil
+}
+
+// SetTag adds metadata, such as the command being run, to every event
+func (r *Reporter) SetTag(key, value string) {
+	if r == nil {
+		return
+	}
+	r.hub.Scope().SetTag(key, value)
+}
+
+// ShouldReport reports whether err is unexpected: a system error, as
+// opposed to invalid input, credentials, permissions, AWS failures and
+// timeouts, which users can act on themselves. Cancellation and exit codes
+// chosen by a command, such as a partial bulk failure, are expected.
+func ShouldReport(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var exitErr *customErrors.ExitError
+	if errors.As(err, &exitErr) {
+		return ShouldReport(exitErr.Err)
+	}
+	return customErrors.GetCategory(err) == customErrors.CategorySystem
+}
+
+// ReportError sends err, with the context it carries, if it is unexpected
+func (r *Reporter) ReportError(err error) {
+	if r == nil || !ShouldReport(err) {
+		return
+	}
+	r.capture(err, nil)
+}
+
+// ReportPanic sends a recovered panic value and waits for it to be
+// delivered, since the process is usually about to crash
+func (r *Reporter) ReportPanic(value interface{}) {
+	if r == nil {
+		return
+	}
+	r.hub.Recover(value)
+	r.hub.Flush(flushTimeout)
+}
+
+// OnCheckComplete reports checks that failed unexpectedly
+func (r *Reporter) OnCheckComplete(ctx context.Context, result *domain.AvailabilityResult, err error) {
+	if r == nil || !ShouldReport(err) {
+		return
+	}
+	var tags map[string]string
+	if result != nil {
+		tags = map[string]string{"domain": result.Domain}
+	}
+	r.capture(err, tags)
+}
+
+// capture sends err with tags on a copy of the reporter's scope, since
+// checks complete concurrently
+func (r *Reporter) capture(err error, tags map[string]string) {
+	scope := r.hub.Scope().Clone()
+	scope.SetTags(tags)
+
+	var contextual interface {
+		GetContext() map[string]interface{}
+	}
+	if errors.As(err, &contextual) && len(contextual.GetContext()) > 0 {
+		scope.SetContext("error", contextual.GetContext())
+	}
+	r.hub.Client().CaptureException(err, &sentry.EventHint{OriginalException: err}, scope)
+}
+
+// Middleware reports panics in handler, then answers the request with a
+// 500 instead of dropping the connection
+func (r *Reporter) Middleware(handler http.Handler) http.Handler {
+	if r == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			if value == http.ErrAbortHandler {
+				panic(value)
+			}
+			r.capture(fmt.Errorf("panic serving %s %s: %v", req.Method, req.URL.Path, value), nil)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// Close waits for pending reports to be delivered
+func (r *Reporter) Close() {
+	if r == nil {
+		return
+	}
+	r.hub.Flush(flushTimeout)
+}
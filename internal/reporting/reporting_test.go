@@ -0,0 +1,181 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/getsentry/sentry-go"
+)
+
+const testTarget = "sentry://key@sentry.example.com/42"
+
+// fakeTransport records events instead of sending them
+type fakeTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *fakeTransport) Configure(sentry.ClientOptions)        {}
+func (t *fakeTransport) Flush(time.Duration) bool              { return true }
+func (t *fakeTransport) FlushWithContext(context.Context) bool { return true }
+func (t *fakeTransport) Close()                                {}
+func (t *fakeTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *fakeTransport) Events() []*sentry.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*sentry.Event(nil), t.events...)
+}
+
+func newTestReporter(t *testing.T) (*Reporter, *fakeTransport) {
+	t.Helper()
+	transport := &fakeTransport{}
+	reporter, err := NewWithTransport(testTarget, "1.2.3", transport)
+	if err != nil {
+		t.Fatalf("NewWithTransport() error = %v", err)
+	}
+	return reporter, transport
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+		wantErr  bool
+	}{
+		{"sentry target", testTarget, "https://key@sentry.example.com/42", false},
+		{"missing scheme", "key@sentry.example.com/42", "", true},
+		{"other scheme", "https://key@sentry.example.com/42", "", true},
+		{"empty DSN", "sentry://", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTarget(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTarget(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseTarget(%q) = %q, want %q", tt.target, got, tt.expected)
+			}
+			if err != nil && customErrors.GetExitCode(err) != customErrors.ExitValidation {
+				t.Errorf("ParseTarget(%q) exit code = %d, want %d", tt.target, customErrors.GetExitCode(err), customErrors.ExitValidation)
+			}
+		})
+	}
+}
+
+func TestShouldReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"system error", customErrors.NewSystemError("history", "unable to write", nil), true},
+		{"unclassified error", errors.New("boom"), true},
+		{"validation error", customErrors.NewValidationError("bad", "format", "invalid", nil), false},
+		{"authentication error", customErrors.NewAuthenticationError("aws", "no credentials", nil), false},
+		{"API error", customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "failed", nil), false},
+		{"timeout", customErrors.NewTimeoutError("domain check", time.Second, context.DeadlineExceeded), false},
+		{"canceled", fmt.Errorf("check: %w", context.Canceled), false},
+		{"reported exit", customErrors.NewExitError(customErrors.ExitPartialFailure, nil), false},
+		{"exit wrapping a system error", customErrors.NewExitError(customErrors.ExitSystemError, customErrors.NewSystemError("cache", "corrupt", nil)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShouldReport(tt.err); got != tt.expected {
+				t.Errorf("ShouldReport(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReporter_ReportError(t *testing.T) {
+	reporter, transport := newTestReporter(t)
+	reporter.SetTag("command", "r53check serve")
+
+	reporter.ReportError(customErrors.NewValidationError("bad", "format", "invalid", nil))
+	reporter.ReportError(customErrors.NewSystemError("history", "unable to write", nil))
+
+	events := transport.Events()
+	if len(events) != 1 {
+		t.Fatalf("reported %d events, want 1", len(events))
+	}
+	event := events[0]
+	if event.Release != "1.2.3" {
+		t.Errorf("event release = %q, want %q", event.Release, "1.2.3")
+	}
+	if event.Tags["command"] != "r53check serve" {
+		t.Errorf("event command tag = %q, want %q", event.Tags["command"], "r53check serve")
+	}
+	if got := event.Contexts["error"]["component"]; got != "history" {
+		t.Errorf("event error context component = %v, want %q", got, "history")
+	}
+}
+
+func TestReporter_OnCheckComplete(t *testing.T) {
+	reporter, transport := newTestReporter(t)
+	var hooks domain.Hooks = reporter
+
+	ctx := context.Background()
+	hooks.OnCheckComplete(ctx, &domain.AvailabilityResult{Domain: "ok.com"}, nil)
+	hooks.OnCheckComplete(ctx, &domain.AvailabilityResult{Domain: "denied.com"}, customErrors.NewAuthorizationError("CheckDomainAvailability", "route53domains", "denied", nil))
+	hooks.OnCheckComplete(ctx, &domain.AvailabilityResult{Domain: "broken.com"}, errors.New("unexpected response"))
+
+	events := transport.Events()
+	if len(events) != 1 {
+		t.Fatalf("reported %d events, want 1", len(events))
+	}
+	if events[0].Tags["domain"] != "broken.com" {
+		t.Errorf("event domain tag = %q, want %q", events[0].Tags["domain"], "broken.com")
+	}
+}
+
+func TestReporter_Middleware(t *testing.T) {
+	reporter, transport := newTestReporter(t)
+	handler := reporter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("nil map")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/check/example.com", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if len(transport.Events()) != 1 {
+		t.Errorf("reported %d events, want 1", len(transport.Events()))
+	}
+}
+
+func TestReporter_Nil(t *testing.T) {
+	var reporter *Reporter
+
+	reporter.SetTag("command", "r53check")
+	reporter.ReportError(errors.New("boom"))
+	reporter.ReportPanic("boom")
+	reporter.Close()
+
+	handler := http.NotFoundHandler()
+	rec := httptest.NewRecorder()
+	reporter.Middleware(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
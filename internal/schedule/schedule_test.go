@@ -0,0 +1,94 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		spec    string
+		message string
+	}{
+		{"", "expected 5 fields"},
+		{"* * * *", "expected 5 fields"},
+		{"60 * * * *", "minute field"},
+		{"* 24 * * *", "hour field"},
+		{"* * 0 * *", "day of month field"},
+		{"* * * 13 *", "month field"},
+		{"* * * * 8", "day of week field"},
+		{"*/0 * * * *", "invalid step"},
+		{"5-1 * * * *", "invalid range"},
+		{"a * * * *", "minute field"},
+		{"@sometimes", "expected 5 fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := Parse(tt.spec)
+			if err == nil {
+				t.Fatalf("Expected an error for %q", tt.spec)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Expected error to mention %q, got %v", tt.message, err)
+			}
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// A Wednesday
+	start := time.Date(2025, time.January, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec     string
+		from     time.Time
+		expected time.Time
+	}{
+		{"* * * * *", start, time.Date(2025, 1, 15, 10, 8, 0, 0, time.UTC)},
+		{"0 */6 * * *", start, time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)},
+		{"0 */6 * * *", time.Date(2025, 1, 15, 18, 0, 0, 0, time.UTC), time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", start, time.Date(2025, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"30 9 * * 1-5", start, time.Date(2025, 1, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 0", start, time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", start, time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 */3 *", start, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 * *", start, time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)},
+		{"5/20 * * * *", start, time.Date(2025, 1, 15, 10, 25, 0, 0, time.UTC)},
+		{"0 0 29 2 *", start, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@daily", start, time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", start, time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"@yearly", start, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 20th or any Friday
+		{"0 0 20 * 5", start, time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)},
+		// Never matches
+		{"0 0 30 2 *", start, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.spec, err)
+			}
+			if got := s.Next(tt.from); !got.Equal(tt.expected) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSchedule_NextKeepsLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	s, err := Parse("0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := s.Next(time.Date(2025, 1, 15, 10, 0, 0, 0, loc))
+	expected := time.Date(2025, 1, 16, 9, 0, 0, 0, loc)
+	if !next.Equal(expected) || next.Location() != loc {
+		t.Errorf("Expected %v in the caller's location, got %v", expected, next)
+	}
+}
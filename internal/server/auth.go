@@ -0,0 +1,224 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abakermi/r53check/internal/errors"
+)
+
+// APIKey is a credential accepted by the server. Name identifies the
+// consumer in rate limiting and is never compared against requests.
+type APIKey struct {
+	Name string
+	Key  string
+}
+
+// LoadAPIKeys reads API keys from a file with one "name:key" entry per line.
+// A line holding only a key is named after its line number. Blank lines and
+// lines starting with # are ignored.
+func LoadAPIKeys(path string) ([]APIKey, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.NewSystemError("api-keys", "unable to read API keys file "+path, err)
+	}
+	defer file.Close()
+
+	var keys []APIKey
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key := APIKey{Name: fmt.Sprintf("key-%d", line), Key: text}
+		if name, secret, ok := strings.Cut(text, ":"); ok {
+			key = APIKey{Name: strings.TrimSpace(name), Key: strings.TrimSpace(secret)}
+		}
+		if key.Name == "" || key.Key == "" {
+			return nil, errors.NewValidationError("", "api-keys-file",
+				fmt.Sprintf("%s:%d: expected name:key", path, line), nil)
+		}
+		keys = append(keys, key)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.NewSystemError("api-keys", "unable to read API keys file "+path, err)
+	}
+	if len(keys) == 0 {
+		return nil, errors.NewValidationError("", "api-keys-file", path+" contains no API keys", nil)
+	}
+	return keys, nil
+}
+
+// SetAPIKeys requires requests to the /v1 routes to present one of keys,
+// either as "Authorization: Bearer <key>" or in the X-API-Key header. An
+// empty list disables authentication.
+func (s *Server) SetAPIKeys(keys []APIKey) {
+	s.apiKeys = keys
+}
+
+// SetRateLimit limits each consumer to perMinute domain checks per minute,
+// with bursts of up to burst checks. Consumers are identified by API key
+// name, or by client IP when authentication is disabled. A perMinute of
+// zero disables rate limiting; a burst below one defaults to perMinute.
+func (s *Server) SetRateLimit(perMinute, burst int) {
+	if perMinute <= 0 {
+		s.limiter = nil
+		return
+	}
+	if burst < 1 {
+		burst = perMinute
+	}
+	s.limiter = newRateLimiter(float64(perMinute)/60, burst)
+}
+
+// consumerKey is the context key holding the consumer a request came from
+type consumerKey struct{}
+
+// protect authenticates requests to handler and records the consumer they
+// came from for rate limiting
+func (s *Server) protect(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		consumer := clientIP(r)
+		if len(s.apiKeys) > 0 {
+			name, ok := s.authenticate(r)
+			if !ok {
+				writeError(w, errUnauthorized)
+				return
+			}
+			consumer = name
+		}
+
+		handler(w, r.WithContext(context.WithValue(r.Context(), consumerKey{}, consumer)))
+	}
+}
+
+// authenticate returns the name of the API key presented by r
+func (s *Server) authenticate(r *http.Request) (string, bool) {
+	presented := r.Header.Get("X-API-Key")
+	if auth := r.Header.Get("Authorization"); presented == "" && auth != "" {
+		scheme, token, ok := strings.Cut(auth, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return "", false
+		}
+		presented = strings.TrimSpace(token)
+	}
+	if presented == "" {
+		return "", false
+	}
+
+	// Compare against every key so timing does not reveal which one matched
+	name := ""
+	for _, key := range s.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(key.Key)) == 1 {
+			name = key.Name
+		}
+	}
+	return name, name != ""
+}
+
+// allow takes n domain checks from the rate limit of the request's consumer
+func (s *Server) allow(r *http.Request, n int) error {
+	if s.limiter == nil {
+		return nil
+	}
+	consumer, _ := r.Context().Value(consumerKey{}).(string)
+	return s.limiter.take(consumer, n)
+}
+
+// clientIP returns the address of the client that sent r
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rateLimitError reports that a consumer exceeded its rate limit
+type rateLimitError struct {
+	consumer   string
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded; retry in %ds", e.retryAfterSeconds())
+}
+
+// GetCategory reports rate limiting as an API error, like throttling by
+// Route 53 itself
+func (e *rateLimitError) GetCategory() errors.ErrorCategory {
+	return errors.CategoryAPI
+}
+
+// retryAfterSeconds rounds the retry delay up to whole seconds
+func (e *rateLimitError) retryAfterSeconds() int {
+	return int(math.Ceil(e.retryAfter.Seconds()))
+}
+
+// rateLimiter is a token bucket per consumer. Each domain check costs one
+// token, so a bulk request costs one per domain; a request for more checks
+// than the burst is rejected, since no bucket ever holds enough.
+type rateLimiter struct {
+	mu      sync.Mutex
+	rate    float64 // tokens added per second
+	burst   float64
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+// bucket is the token balance of one consumer
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter creates a limiter refilling rate tokens per second up to burst
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// take removes n tokens from consumer's bucket, or returns a rateLimitError
+// saying how long until they are available. n over the burst is a 400 naming
+// the burst.
+func (l *rateLimiter) take(consumer string, n int) error {
+	if float64(n) > l.burst {
+		return &statusError{http.StatusBadRequest, errors.CategoryValidation,
+			fmt.Sprintf("request checks %d domains, more than the rate limit burst of %d; split it into smaller requests", n, int(l.burst))}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[consumer]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[consumer] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	cost := float64(n)
+	if b.tokens < cost {
+		wait := time.Duration((cost - b.tokens) / l.rate * float64(time.Second))
+		return &rateLimitError{consumer: consumer, retryAfter: wait}
+	}
+	b.tokens -= cost
+	return nil
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveAs sends a request carrying the given headers
+func serveAs(s *Server, method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_APIKeyAuthentication(t *testing.T) {
+	s := newTestServer(t)
+	s.SetAPIKeys([]APIKey{{Name: "billing", Key: "secret-1"}, {Name: "growth", Key: "secret-2"}})
+
+	tests := []struct {
+		name           string
+		target         string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{"no key", "/v1/domains/free.com", nil, http.StatusUnauthorized},
+		{"wrong key", "/v1/domains/free.com", map[string]string{"Authorization": "Bearer nope"}, http.StatusUnauthorized},
+		{"wrong scheme", "/v1/domains/free.com", map[string]string{"Authorization": "Basic secret-1"}, http.StatusUnauthorized},
+		{"bearer token", "/v1/domains/free.com", map[string]string{"Authorization": "Bearer secret-1"}, http.StatusOK},
+		{"X-API-Key header", "/v1/domains/free.com", map[string]string{"X-API-Key": "secret-2"}, http.StatusOK},
+		{"health is public", "/healthz", nil, http.StatusOK},
+		{"OpenAPI document is public", "/openapi.json", nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveAs(s, http.MethodGet, tt.target, "", tt.headers)
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body)
+			}
+			if rec.Code != http.StatusUnauthorized {
+				return
+			}
+
+			if rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected a WWW-Authenticate header")
+			}
+			var body errorBody
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Invalid error body: %v", err)
+			}
+			if body.Error.Category != "AUTHENTICATION" {
+				t.Errorf("Expected AUTHENTICATION error, got %+v", body.Error)
+			}
+		})
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	s := newTestServer(t)
+	s.SetAPIKeys([]APIKey{{Name: "billing", Key: "secret-1"}, {Name: "growth", Key: "secret-2"}})
+	s.SetRateLimit(60, 3)
+
+	now := time.Now()
+	s.limiter.now = func() time.Time { return now }
+
+	billing := map[string]string{"Authorization": "Bearer secret-1", "Content-Type": "application/json"}
+	growth := map[string]string{"Authorization": "Bearer secret-2"}
+
+	// A bulk request costs one token per domain
+	rec := serveAs(s, http.MethodPost, "/v1/bulk", `{"domains": ["free.com", "taken.com"]}`, billing)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec = serveAs(s, http.MethodGet, "/v1/domains/free.com", "", billing); rec.Code != http.StatusOK {
+		t.Fatalf("Expected the third check to be allowed, got %d", rec.Code)
+	}
+
+	rec = serveAs(s, http.MethodGet, "/v1/domains/free.com", "", billing)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429, got %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Expected Retry-After 1, got %q", got)
+	}
+
+	// Other consumers have their own budget
+	if rec = serveAs(s, http.MethodGet, "/v1/domains/free.com", "", growth); rec.Code != http.StatusOK {
+		t.Errorf("Expected another key to be unaffected, got %d", rec.Code)
+	}
+
+	// Tokens refill over time
+	now = now.Add(time.Second)
+	if rec = serveAs(s, http.MethodGet, "/v1/domains/free.com", "", billing); rec.Code != http.StatusOK {
+		t.Errorf("Expected the refilled bucket to allow a check, got %d", rec.Code)
+	}
+}
+
+func TestRateLimiter_LargeRequests(t *testing.T) {
+	limiter := newRateLimiter(1, 5)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	// Bulk requests cost one token per domain
+	if err := limiter.take("a", 4); err != nil {
+		t.Fatalf("Expected a full bucket to allow 4 checks, got %v", err)
+	}
+	err := limiter.take("a", 3)
+	limited, ok := err.(*rateLimitError)
+	if !ok {
+		t.Fatalf("Expected a rateLimitError, got %v", err)
+	}
+	if limited.retryAfter != 2*time.Second {
+		t.Errorf("Expected to wait 2s for 3 tokens, got %v", limited.retryAfter)
+	}
+
+	// Requests larger than the burst can never be served
+	err = limiter.take("b", 50)
+	if status := statusForError(err); status != http.StatusBadRequest {
+		t.Fatalf("Expected status 400 for a request over the burst, got %d (%v)", status, err)
+	}
+	if !strings.Contains(err.Error(), "burst of 5") {
+		t.Errorf("Expected the error to name the burst, got %q", err)
+	}
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []APIKey
+		wantErr  bool
+	}{
+		{
+			name:     "named and unnamed keys",
+			content:  "# team keys\nbilling: secret-1\n\nsecret-2\n",
+			expected: []APIKey{{Name: "billing", Key: "secret-1"}, {Name: "key-4", Key: "secret-2"}},
+		},
+		{name: "missing key", content: "billing:\n", wantErr: true},
+		{name: "empty file", content: "# nothing here\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			keys, err := LoadAPIKeys(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadAPIKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(keys) != len(tt.expected) {
+				t.Fatalf("Expected %d keys, got %+v", len(tt.expected), keys)
+			}
+			for i := range keys {
+				if keys[i] != tt.expected[i] {
+					t.Errorf("Key %d = %+v, want %+v", i, keys[i], tt.expected[i])
+				}
+			}
+		})
+	}
+
+	if _, err := LoadAPIKeys(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
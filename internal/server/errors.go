@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/abakermi/r53check/internal/errors"
+)
+
+// statusError is a request error with a fixed HTTP status
+type statusError struct {
+	status   int
+	category errors.ErrorCategory
+	message  string
+}
+
+func (e *statusError) Error() string {
+	return e.message
+}
+
+var (
+	errUnsupportedMediaType = &statusError{http.StatusUnsupportedMediaType, errors.CategoryValidation, "request body must be application/json"}
+	errRequestTooLarge      = &statusError{http.StatusRequestEntityTooLarge, errors.CategoryValidation, "request body too large"}
+	errUnauthorized         = &statusError{http.StatusUnauthorized, errors.CategoryAuthentication, "missing or invalid API key"}
+)
+
+// errorBody is the JSON body of every error response
+type errorBody struct {
+	Error errorDetail `json:"error"`
+}
+
+// errorDetail describes what went wrong with a request
+type errorDetail struct {
+	Category string   `json:"category"`
+	Message  string   `json:"message"`
+	Domain   string   `json:"domain,omitempty"`
+	Field    string   `json:"field,omitempty"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// writeError writes err as a JSON error response with the matching status
+func writeError(w http.ResponseWriter, err error) {
+	var limited *rateLimitError
+	if stderrors.As(err, &limited) {
+		w.Header().Set("Retry-After", strconv.Itoa(limited.retryAfterSeconds()))
+	}
+	if stderrors.Is(err, errUnauthorized) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="r53check"`)
+	}
+	writeJSON(w, statusForError(err), errorBody{Error: newErrorDetail(err)})
+}
+
+// statusForError maps an error to an HTTP status. Failures of the server's
+// own AWS credentials or of Route 53 itself are reported as gateway errors,
+// since the client cannot fix them.
+func statusForError(err error) int {
+	var statusErr *statusError
+	if stderrors.As(err, &statusErr) {
+		return statusErr.status
+	}
+	var limited *rateLimitError
+	if stderrors.As(err, &limited) {
+		return http.StatusTooManyRequests
+	}
+
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case stderrors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	case errors.IsThrottling(err):
+		return http.StatusTooManyRequests
+	}
+
+	var categorized interface {
+		GetCategory() errors.ErrorCategory
+	}
+	if !stderrors.As(err, &categorized) {
+		return http.StatusInternalServerError
+	}
+
+	switch categorized.GetCategory() {
+	case errors.CategoryValidation:
+		return http.StatusBadRequest
+	case errors.CategoryTimeout:
+		return http.StatusGatewayTimeout
+	case errors.CategoryAuthentication, errors.CategoryAuthorization, errors.CategoryAPI:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// newErrorDetail describes err for an error response
+func newErrorDetail(err error) errorDetail {
+	detail := errorDetail{
+		Category: string(errors.CategorySystem),
+		Message:  err.Error(),
+	}
+
+	var statusErr *statusError
+	if stderrors.As(err, &statusErr) {
+		detail.Category = string(statusErr.category)
+		return detail
+	}
+
+	var validationErrs *errors.ValidationErrors
+	if stderrors.As(err, &validationErrs) {
+		detail.Category = string(errors.CategoryValidation)
+		detail.Domain = validationErrs.Domain
+		for _, problem := range validationErrs.Errors {
+			detail.Problems = append(detail.Problems, problem.Message)
+		}
+		return detail
+	}
+
+	var validationErr *errors.ValidationError
+	if stderrors.As(err, &validationErr) {
+		detail.Domain = validationErr.Domain
+		detail.Field = validationErr.Field
+	}
+
+	var categorized interface {
+		GetCategory() errors.ErrorCategory
+	}
+	if stderrors.As(err, &categorized) {
+		detail.Category = string(categorized.GetCategory())
+	}
+	return detail
+}
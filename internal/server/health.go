@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultReadinessTTL is how long a readiness report is reused, so frequent
+// probes do not spend Route 53 API quota
+const defaultReadinessTTL = 30 * time.Second
+
+// readinessCheckTimeout bounds each readiness check
+const readinessCheckTimeout = 5 * time.Second
+
+// readinessCheck is one named dependency verified by /readyz
+type readinessCheck struct {
+	name  string
+	check func(context.Context) error
+}
+
+// healthReport is the body of /healthz and /readyz responses
+type healthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks,omitempty"`
+}
+
+// checkResult is the outcome of one readiness check
+type checkResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// AddReadinessCheck registers a dependency that must be healthy for /readyz
+// to report the server ready
+func (s *Server) AddReadinessCheck(name string, check func(context.Context) error) {
+	s.readinessChecks = append(s.readinessChecks, readinessCheck{name: name, check: check})
+}
+
+// handleHealth reports that the process is alive
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, healthReport{Status: "ok"})
+}
+
+// handleReady reports whether every readiness check passes
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	report := s.readiness(r.Context())
+
+	status := http.StatusOK
+	if report.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+// readiness runs the readiness checks concurrently, reusing the previous
+// report while it is younger than the readiness TTL
+func (s *Server) readiness(ctx context.Context) healthReport {
+	s.readyMu.Lock()
+	defer s.readyMu.Unlock()
+
+	if !s.readyAt.IsZero() && time.Since(s.readyAt) < s.readinessTTL {
+		return s.readyReport
+	}
+
+	report := healthReport{Status: "ok", Checks: make(map[string]checkResult, len(s.readinessChecks))}
+	results := make([]checkResult, len(s.readinessChecks))
+
+	var wg sync.WaitGroup
+	for i, rc := range s.readinessChecks {
+		wg.Add(1)
+		go func(i int, rc readinessCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+			defer cancel()
+
+			results[i] = checkResult{Status: "ok"}
+			if err := rc.check(checkCtx); err != nil {
+				results[i] = checkResult{Status: "fail", Error: err.Error()}
+			}
+		}(i, rc)
+	}
+	wg.Wait()
+
+	for i, rc := range s.readinessChecks {
+		report.Checks[rc.name] = results[i]
+		if results[i].Status != "ok" {
+			report.Status = "unavailable"
+		}
+	}
+
+	s.readyAt = time.Now()
+	s.readyReport = report
+	return report
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestServer_Healthz(t *testing.T) {
+	s := newTestServer(t)
+	s.AddReadinessCheck("failing", func(ctx context.Context) error { return errors.New("down") })
+
+	// Liveness does not depend on readiness checks
+	rec := serve(s, http.MethodGet, "/healthz", "", "")
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+}
+
+func TestServer_Readyz(t *testing.T) {
+	s := newTestServer(t)
+
+	var calls atomic.Int64
+	healthy := true
+	s.AddReadinessCheck("aws-credentials", func(ctx context.Context) error { return nil })
+	s.AddReadinessCheck("route53domains", func(ctx context.Context) error {
+		calls.Add(1)
+		if !healthy {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	rec := serve(s, http.MethodGet, "/readyz", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	// A second probe within the TTL reuses the report
+	healthy = false
+	if rec = serve(s, http.MethodGet, "/readyz", "", ""); rec.Code != http.StatusOK || calls.Load() != 1 {
+		t.Errorf("Expected the cached report to be reused, got status %d after %d calls", rec.Code, calls.Load())
+	}
+
+	// Once the TTL has passed the checks run again
+	s.readinessTTL = 0
+	rec = serve(s, http.MethodGet, "/readyz", "", "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status 503, got %d", rec.Code)
+	}
+
+	var report healthReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Invalid readiness body: %v", err)
+	}
+	if report.Status != "unavailable" {
+		t.Errorf("Expected status unavailable, got %q", report.Status)
+	}
+	if report.Checks["aws-credentials"].Status != "ok" {
+		t.Errorf("Expected aws-credentials to pass, got %+v", report.Checks["aws-credentials"])
+	}
+	if got := report.Checks["route53domains"]; got.Status != "fail" || got.Error != "connection refused" {
+		t.Errorf("Expected route53domains to fail, got %+v", got)
+	}
+}
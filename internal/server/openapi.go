@@ -0,0 +1,9 @@
+package server
+
+import _ "embed"
+
+// openAPISpec is the OpenAPI 3 document served at /openapi.json. Keep it in
+// step with the routes registered by NewServer.
+//
+//go:embed openapi.json
+var openAPISpec []byte
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	"github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/output"
+)
+
+// DefaultMaxBulkDomains caps how many domains one bulk request may check
+const DefaultMaxBulkDomains = 100
+
+// maxBodyBytes caps the size of request bodies
+const maxBodyBytes = 1 << 20
+
+// Server serves the r53check REST API
+type Server struct {
+	checker        *domain.DomainChecker
+	mux            *http.ServeMux
+	maxBulkDomains int
+	apiKeys        []APIKey
+	limiter        *rateLimiter
+
+	readinessChecks []readinessCheck
+	readinessTTL    time.Duration
+	readyMu         sync.Mutex
+	readyAt         time.Time
+	readyReport     healthReport
+}
+
+// NewServer creates a server that checks domains with checker
+func NewServer(checker *domain.DomainChecker) *Server {
+	s := &Server{
+		checker:        checker,
+		mux:            http.NewServeMux(),
+		maxBulkDomains: DefaultMaxBulkDomains,
+		readinessTTL:   defaultReadinessTTL,
+	}
+
+	s.mux.HandleFunc("GET /v1/domains/{domain}", s.protect(s.handleCheck))
+	s.mux.HandleFunc("POST /v1/bulk", s.protect(s.handleBulk))
+	s.mux.HandleFunc("GET /openapi.json", s.handleOpenAPI)
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("GET /readyz", s.handleReady)
+
+	return s
+}
+
+// Handler returns the HTTP handler for the API
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// Handle registers an additional handler, such as a metrics endpoint, on the
+// server's mux using http.ServeMux patterns
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// SetMaxBulkDomains sets how many domains one bulk request may check.
+// Values below one restore the default.
+func (s *Server) SetMaxBulkDomains(n int) {
+	if n < 1 {
+		n = DefaultMaxBulkDomains
+	}
+	s.maxBulkDomains = n
+}
+
+// bulkRequest is the body of POST /v1/bulk
+type bulkRequest struct {
+	Domains []string `json:"domains"`
+	Price   bool     `json:"price"`
+}
+
+// handleCheck checks a single domain
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("domain")
+
+	withPrice, err := parseBoolParam(r, "price")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.allow(r, 1); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var result *domain.AvailabilityResult
+	if withPrice {
+		result, err = s.checker.CheckAvailabilityWithPricing(r.Context(), name)
+	} else {
+		result, err = s.checker.CheckAvailability(r.Context(), name)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, output.NewJSONResult(result))
+}
+
+// handleBulk checks every domain in the request body. Clients that accept
+// text/event-stream receive each result as it completes instead.
+func (s *Server) handleBulk(w http.ResponseWriter, r *http.Request) {
+	req, err := s.decodeBulkRequest(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.allow(r, len(req.Domains)); err != nil {
+		writeError(w, err)
+		return
+	}
+	if wantsEventStream(r) {
+		s.streamBulk(w, r, req)
+		return
+	}
+
+	var results []*domain.AvailabilityResult
+	if req.Price {
+		results, err = s.checker.CheckAvailabilityBulkWithPricing(r.Context(), req.Domains)
+	} else {
+		results, err = s.checker.CheckAvailabilityBulk(r.Context(), req.Domains)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, output.NewJSONBulkResults(results))
+}
+
+// decodeBulkRequest reads and validates the body of a bulk request
+func (s *Server) decodeBulkRequest(w http.ResponseWriter, r *http.Request) (*bulkRequest, error) {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		return nil, errUnsupportedMediaType
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+
+	var req bulkRequest
+	if err := decoder.Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if stderrors.As(err, &tooLarge) {
+			return nil, errRequestTooLarge
+		}
+		return nil, errors.NewValidationError("", "body", "invalid JSON request body", err)
+	}
+	if decoder.More() {
+		return nil, errors.NewValidationError("", "body", "request body must contain a single JSON object", nil)
+	}
+
+	if len(req.Domains) == 0 {
+		return nil, errors.NewValidationError("", "domains", "domains must contain at least one domain", nil)
+	}
+	if len(req.Domains) > s.maxBulkDomains {
+		return nil, errors.NewValidationError("", "domains",
+			fmt.Sprintf("too many domains: %d given, maximum %d per request", len(req.Domains), s.maxBulkDomains), nil)
+	}
+	for i, name := range req.Domains {
+		if strings.TrimSpace(name) == "" {
+			return nil, errors.NewValidationError("", fmt.Sprintf("domains[%d]", i), "domain cannot be empty", nil)
+		}
+	}
+
+	return &req, nil
+}
+
+// handleOpenAPI serves the OpenAPI document describing the API
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+// parseBoolParam reads an optional boolean query parameter
+func parseBoolParam(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.NewValidationError("", name, fmt.Sprintf("invalid value %q for %s: must be true or false", value, name), nil)
+	}
+	return b, nil
+}
+
+// writeJSON writes v as the JSON response body with status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/abakermi/r53check/internal/domain"
+	"github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/output"
+	"github.com/abakermi/r53check/pkg/r53checktest"
+)
+
+// newTestServer serves the API backed by a fake Route 53 client
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		Failing("denied.com", r53checktest.AccessDenied()).
+		Failing("busy.com", r53checktest.Throttled()).
+		Client()
+
+	checker := domain.NewDomainChecker(domain.NewDomainValidatorWithTLDs([]string{"com"}), client)
+	checker.SetRetryDelay(-1)
+	return NewServer(checker)
+}
+
+// serve sends one request to the server and returns the recorded response
+func serve(s *Server, method, target, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Check(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name           string
+		target         string
+		expectedStatus int
+		category       string
+	}{
+		{"available", "/v1/domains/free.com", http.StatusOK, ""},
+		{"with pricing", "/v1/domains/free.com?price=true", http.StatusOK, ""},
+		{"invalid price parameter", "/v1/domains/free.com?price=maybe", http.StatusBadRequest, "VALIDATION"},
+		{"invalid domain", "/v1/domains/bad-.com", http.StatusBadRequest, "VALIDATION"},
+		{"access denied", "/v1/domains/denied.com", http.StatusBadGateway, "AUTHORIZATION"},
+		{"throttled", "/v1/domains/busy.com", http.StatusTooManyRequests, "API"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodGet, tt.target, "", "")
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Expected JSON content type, got %q", ct)
+			}
+
+			if tt.category == "" {
+				var result output.JSONResult
+				if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+					t.Fatalf("Invalid result body: %v", err)
+				}
+				if result.Domain != "free.com" || !result.Available {
+					t.Errorf("Expected free.com to be available, got %+v", result)
+				}
+				return
+			}
+
+			var body errorBody
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Invalid error body: %v", err)
+			}
+			if body.Error.Category != tt.category || body.Error.Message == "" {
+				t.Errorf("Expected %s error, got %+v", tt.category, body.Error)
+			}
+		})
+	}
+}
+
+func TestServer_Bulk(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := serve(s, http.MethodPost, "/v1/bulk", "application/json", `{"domains": ["free.com", "taken.com", "denied.com"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var out output.JSONBulkResults
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("Invalid bulk body: %v", err)
+	}
+	expected := output.JSONSummary{Total: 3, Available: 1, Unavailable: 1, Errors: 1}
+	if !reflect.DeepEqual(out.Summary, expected) {
+		t.Errorf("Expected summary %+v, got %+v", expected, out.Summary)
+	}
+}
+
+func TestServer_BulkRequestValidation(t *testing.T) {
+	s := newTestServer(t)
+	s.SetMaxBulkDomains(2)
+
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+		field          string
+	}{
+		{"wrong content type", "text/plain", `{"domains": ["free.com"]}`, http.StatusUnsupportedMediaType, ""},
+		{"malformed JSON", "application/json", `{"domains": [`, http.StatusBadRequest, "body"},
+		{"unknown field", "application/json", `{"domains": ["free.com"], "colour": "blue"}`, http.StatusBadRequest, "body"},
+		{"trailing data", "application/json", `{"domains": ["free.com"]} {}`, http.StatusBadRequest, "body"},
+		{"no domains", "application/json", `{"domains": []}`, http.StatusBadRequest, "domains"},
+		{"too many domains", "application/json", `{"domains": ["a.com", "b.com", "c.com"]}`, http.StatusBadRequest, "domains"},
+		{"empty domain", "application/json", `{"domains": ["free.com", " "]}`, http.StatusBadRequest, "domains[1]"},
+		{"body too large", "application/json", `{"domains": ["` + strings.Repeat("a", maxBodyBytes) + `"]}`, http.StatusRequestEntityTooLarge, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodPost, "/v1/bulk", tt.contentType, tt.body)
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body)
+			}
+
+			var body errorBody
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Invalid error body: %v", err)
+			}
+			if body.Error.Category != "VALIDATION" || body.Error.Field != tt.field {
+				t.Errorf("Expected VALIDATION error for field %q, got %+v", tt.field, body.Error)
+			}
+		})
+	}
+}
+
+func TestServer_MethodNotAllowed(t *testing.T) {
+	s := newTestServer(t)
+
+	if rec := serve(s, http.MethodGet, "/v1/bulk", "", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", rec.Code)
+	}
+}
+
+func TestServer_OpenAPI(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := serve(s, http.MethodGet, "/openapi.json", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var spec struct {
+		OpenAPI string                            `json:"openapi"`
+		Paths   map[string]map[string]interface{} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("Invalid OpenAPI document: %v", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		t.Errorf("Expected an OpenAPI 3 document, got version %q", spec.OpenAPI)
+	}
+
+	// Every route the server registers must be documented
+	routes := map[string]string{
+		"/v1/domains/{domain}": "get",
+		"/v1/bulk":             "post",
+		"/openapi.json":        "get",
+		"/healthz":             "get",
+		"/readyz":              "get",
+	}
+	for path, method := range routes {
+		if _, ok := spec.Paths[path][method]; !ok {
+			t.Errorf("Expected %s %s in the OpenAPI document", strings.ToUpper(method), path)
+		}
+	}
+}
+
+func TestStatusForError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"validation", errors.NewValidationError("x.com", "format", "bad", nil), http.StatusBadRequest},
+		{"authentication", errors.NewAuthenticationError("aws-sdk", "no credentials", nil), http.StatusBadGateway},
+		{"authorization", errors.NewAuthorizationError("CheckDomainAvailability", "route53domains", "denied", nil), http.StatusBadGateway},
+		{"api", errors.NewAPIError("route53domains", "CheckDomainAvailability", "failed", nil), http.StatusBadGateway},
+		{"throttled", r53checktest.Throttled(), http.StatusTooManyRequests},
+		{"timeout", errors.NewAPIError("route53domains", "CheckDomainAvailability", "timed out", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"system", errors.NewSystemError("bulk-check", "failed", nil), http.StatusInternalServerError},
+		{"uncategorized", context.Canceled, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := statusForError(tt.err); status != tt.expected {
+				t.Errorf("statusForError(%v) = %d, want %d", tt.err, status, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/domain"
+	"github.com/abakermi/r53check/internal/output"
+)
+
+// eventStreamType is the media type of Server-Sent Events responses
+const eventStreamType = "text/event-stream"
+
+// streamStart is the data of the first event of a streamed bulk check
+type streamStart struct {
+	Total int `json:"total"`
+}
+
+// wantsEventStream reports whether the client asked for Server-Sent Events
+func wantsEventStream(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept)); err == nil && mediaType == eventStreamType {
+			return true
+		}
+	}
+	return false
+}
+
+// streamBulk checks the domains of req and sends each result as a
+// Server-Sent Event as soon as it completes. The stream opens with a
+// "start" event carrying the number of domains, continues with one
+// "result" event per domain in completion order, and ends with a "summary"
+// event, or an "error" event if the checks stop early.
+func (s *Server) streamBulk(w http.ResponseWriter, r *http.Request, req *bulkRequest) {
+	w.Header().Set("Content-Type", eventStreamType)
+	w.Header().Set("Cache-Control", "no-cache")
+	// Stop reverse proxies such as nginx from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	events := &eventWriter{w: w, rc: http.NewResponseController(w)}
+	if err := events.send("start", streamStart{Total: len(req.Domains)}); err != nil {
+		return
+	}
+
+	stream := s.checker.Stream
+	if req.Price {
+		stream = s.checker.StreamWithPricing
+	}
+
+	results := make([]*domain.AvailabilityResult, 0, len(req.Domains))
+	for result, err := range stream(r.Context(), slices.Values(req.Domains)) {
+		if result == nil {
+			if err != nil {
+				events.send("error", errorBody{Error: newErrorDetail(err)})
+			}
+			return
+		}
+
+		results = append(results, result)
+		if err := events.send("result", output.NewJSONResult(result)); err != nil {
+			// The client went away; breaking out cancels the remaining checks
+			return
+		}
+	}
+
+	events.send("summary", output.NewJSONBulkResults(results).Summary)
+}
+
+// eventWriter writes Server-Sent Events, flushing after each one
+type eventWriter struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+	id int
+}
+
+// send writes v as the JSON data of a named event
+func (e *eventWriter) send(event string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	e.id++
+	if _, err := fmt.Fprintf(e.w, "id: %d\nevent: %s\ndata: %s\n\n", e.id, event, data); err != nil {
+		return err
+	}
+	return e.rc.Flush()
+}
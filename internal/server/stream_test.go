@@ -0,0 +1,168 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/abakermi/r53check/internal/output"
+)
+
+// sseEvent is one parsed Server-Sent Event
+type sseEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+// parseEvents splits a Server-Sent Events body into its events
+func parseEvents(t *testing.T, body string) []sseEvent {
+	t.Helper()
+
+	var events []sseEvent
+	var current sseEvent
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			events = append(events, current)
+			current = sseEvent{}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			current.id = value
+		case "event":
+			current.event = value
+		case "data":
+			current.data = value
+		default:
+			t.Fatalf("Unexpected event line %q", line)
+		}
+	}
+	return events
+}
+
+// streamRequest sends a bulk request asking for an event stream
+func streamRequest(ctx context.Context, s *Server, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/v1/bulk", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_BulkEventStream(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := streamRequest(context.Background(), s, `{"domains": ["free.com", "taken.com", "denied.com"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected an event stream, got %q", ct)
+	}
+	if !rec.Flushed {
+		t.Error("Expected events to be flushed as they are written")
+	}
+
+	events := parseEvents(t, rec.Body.String())
+	if len(events) != 5 {
+		t.Fatalf("Expected start, 3 results and a summary, got %+v", events)
+	}
+
+	if events[0].event != "start" || events[0].data != `{"total":3}` {
+		t.Errorf("Expected a start event with the total, got %+v", events[0])
+	}
+
+	seen := make(map[string]bool)
+	for i, event := range events[1:4] {
+		if event.event != "result" {
+			t.Fatalf("Expected event %d to be a result, got %+v", i+1, event)
+		}
+		var result output.JSONResult
+		if err := json.Unmarshal([]byte(event.data), &result); err != nil {
+			t.Fatalf("Invalid result event: %v", err)
+		}
+		seen[result.Domain] = true
+	}
+	for _, name := range []string{"free.com", "taken.com", "denied.com"} {
+		if !seen[name] {
+			t.Errorf("Expected a result for %s", name)
+		}
+	}
+
+	last := events[4]
+	if last.event != "summary" || last.id != "5" {
+		t.Fatalf("Expected a final summary event with id 5, got %+v", last)
+	}
+	var summary output.JSONSummary
+	if err := json.Unmarshal([]byte(last.data), &summary); err != nil {
+		t.Fatalf("Invalid summary event: %v", err)
+	}
+	expected := output.JSONSummary{Total: 3, Available: 1, Unavailable: 1, Errors: 1}
+	if !reflect.DeepEqual(summary, expected) {
+		t.Errorf("Expected summary %+v, got %+v", expected, summary)
+	}
+}
+
+func TestServer_BulkEventStream_Cancelled(t *testing.T) {
+	s := newTestServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	events := parseEvents(t, streamRequest(ctx, s, `{"domains": ["free.com"]}`).Body.String())
+	last := events[len(events)-1]
+	if last.event != "error" {
+		t.Fatalf("Expected the stream to end with an error event, got %+v", events)
+	}
+
+	var body errorBody
+	if err := json.Unmarshal([]byte(last.data), &body); err != nil {
+		t.Fatalf("Invalid error event: %v", err)
+	}
+	if body.Error.Category != "SYSTEM" {
+		t.Errorf("Expected SYSTEM error, got %+v", body.Error)
+	}
+}
+
+func TestServer_BulkEventStream_ValidationErrorsAreJSON(t *testing.T) {
+	s := newTestServer(t)
+
+	// Request errors are reported before the stream starts
+	rec := streamRequest(context.Background(), s, `{"domains": []}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected a JSON error, got %q", ct)
+	}
+}
+
+func TestWantsEventStream(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{"text/event-stream", true},
+		{"application/json, text/event-stream;q=0.9", true},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/v1/bulk", nil)
+		req.Header.Set("Accept", tt.accept)
+		if got := wantsEventStream(req); got != tt.expected {
+			t.Errorf("wantsEventStream(%q) = %v, want %v", tt.accept, got, tt.expected)
+		}
+	}
+}
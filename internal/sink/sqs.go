@@ -0,0 +1,197 @@
+package sink
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/output"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const (
+	// maxBatch is the most messages SQS accepts in one SendMessageBatch call
+	maxBatch = 10
+
+	// queueSize is how many results may wait to be sent before checks block
+	queueSize = 100
+)
+
+// SQSAPI is the part of the SQS client used to publish results
+type SQSAPI interface {
+	SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error)
+}
+
+// Message is the body of each SQS message: the JSON result shape used by
+// --output json, with the tags of the run
+type Message struct {
+	output.JSONResult
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// SQSSink publishes every completed check to an SQS queue as a JSON
+// Message. It implements domain.Hooks, so attaching it to a checker
+// publishes results while a run is still in progress. Messages are sent in
+// batches from a background goroutine; call Flush to wait for them and Close
+// when done.
+type SQSSink struct {
+	domain.NopHooks
+
+	api      SQSAPI
+	queueURL string
+	fifo     bool
+	tags     map[string]string
+
+	queue   chan types.SendMessageBatchRequestEntry
+	pending sync.WaitGroup
+	done    chan struct{}
+
+	mu     sync.Mutex
+	sent   int
+	failed int
+	err    error
+}
+
+// NewSQSSink creates a sink publishing to the queue at queueURL with api,
+// usually sqs.NewFromConfig(cfg). FIFO queues, whose URLs end in ".fifo",
+// get one message group per domain.
+func NewSQSSink(api SQSAPI, queueURL string) *SQSSink {
+	s := &SQSSink{
+		api:      api,
+		queueURL: queueURL,
+		fifo:     strings.HasSuffix(queueURL, ".fifo"),
+		queue:    make(chan types.SendMessageBatchRequestEntry, queueSize),
+		done:     make(chan struct{}),
+	}
+	go s.send()
+	return s
+}
+
+// SetTags adds tags to every message. It must be called before checks start.
+func (s *SQSSink) SetTags(tags map[string]string) {
+	s.tags = tags
+}
+
+// OnCheckComplete queues result for publishing. A check that fails with a
+// retryable error is published too, and followed by the retried result.
+func (s *SQSSink) OnCheckComplete(ctx context.Context, result *domain.AvailabilityResult, err error) {
+	if result == nil {
+		return
+	}
+	body, marshalErr := json.Marshal(Message{JSONResult: output.NewJSONResult(result), Tags: s.tags})
+	if marshalErr != nil {
+		s.fail(1, marshalErr)
+		return
+	}
+
+	entry := types.SendMessageBatchRequestEntry{
+		MessageBody: aws.String(string(body)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"domain": {DataType: aws.String("String"), StringValue: aws.String(result.Domain)},
+			"status": {DataType: aws.String("String"), StringValue: aws.String(string(result.Status))},
+		},
+	}
+	if s.fifo {
+		sum := sha256.Sum256(body)
+		entry.MessageGroupId = aws.String(result.Domain)
+		entry.MessageDeduplicationId = aws.String(hex.EncodeToString(sum[:]))
+	}
+
+	s.pending.Add(1)
+	s.queue <- entry
+}
+
+// send publishes queued messages, batching whatever is waiting
+func (s *SQSSink) send() {
+	defer close(s.done)
+
+	for entry := range s.queue {
+		batch := []types.SendMessageBatchRequestEntry{entry}
+	collect:
+		for len(batch) < maxBatch {
+			select {
+			case next, ok := <-s.queue:
+				if !ok {
+					break collect
+				}
+				batch = append(batch, next)
+			default:
+				break collect
+			}
+		}
+
+		s.sendBatch(batch)
+		s.pending.Add(-len(batch))
+	}
+}
+
+// sendBatch publishes one batch and records its outcome. Publishing is not
+// tied to the run's context, so results still reach the queue when a run
+// is cancelled.
+func (s *SQSSink) sendBatch(batch []types.SendMessageBatchRequestEntry) {
+	for i := range batch {
+		batch[i].Id = aws.String(strconv.Itoa(i))
+	}
+
+	out, err := s.api.SendMessageBatch(context.Background(), &sqs.SendMessageBatchInput{
+		QueueUrl: &s.queueURL,
+		Entries:  batch,
+	})
+	if err != nil {
+		s.fail(len(batch), customErrors.WrapAWSError(err, "sqs", "SendMessageBatch"))
+		return
+	}
+
+	s.mu.Lock()
+	s.sent += len(out.Successful)
+	s.mu.Unlock()
+	for _, failure := range out.Failed {
+		s.fail(1, fmt.Errorf("%s: %s", aws.ToString(failure.Code), aws.ToString(failure.Message)))
+	}
+}
+
+// fail records n messages that could not be published
+func (s *SQSSink) fail(n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed += n
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// Flush waits until every queued result has been sent, and reports any
+// failures since the previous Flush
+func (s *SQSSink) Flush() error {
+	s.pending.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	failed, sent, err := s.failed, s.sent, s.err
+	s.failed, s.sent, s.err = 0, 0, nil
+
+	if failed == 0 {
+		return nil
+	}
+	return customErrors.NewSystemError("sqs",
+		fmt.Sprintf("%d of %d results could not be sent to %s", failed, failed+sent, s.queueURL), err)
+}
+
+// Close flushes the sink and stops its background goroutine. No checks may
+// complete after Close is called.
+func (s *SQSSink) Close() error {
+	err := s.Flush()
+	close(s.queue)
+	<-s.done
+	return err
+}
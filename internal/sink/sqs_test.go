@@ -0,0 +1,144 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/abakermi/r53check/internal/domain"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// fakeSQS records every batch it is sent. Messages for domains in reject
+// are reported as failed entries, and err fails whole calls.
+type fakeSQS struct {
+	mu      sync.Mutex
+	batches []*sqs.SendMessageBatchInput
+	reject  map[string]bool
+	err     error
+}
+
+func (f *fakeSQS) SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, params)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	out := &sqs.SendMessageBatchOutput{}
+	for _, entry := range params.Entries {
+		if f.reject[aws.ToString(entry.MessageAttributes["domain"].StringValue)] {
+			out.Failed = append(out.Failed, types.BatchResultErrorEntry{Id: entry.Id, Code: aws.String("InvalidMessageContents")})
+		} else {
+			out.Successful = append(out.Successful, types.SendMessageBatchResultEntry{Id: entry.Id})
+		}
+	}
+	return out, nil
+}
+
+// messages returns every message body sent, in order
+func (f *fakeSQS) messages() []types.SendMessageBatchRequestEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var entries []types.SendMessageBatchRequestEntry
+	for _, batch := range f.batches {
+		entries = append(entries, batch.Entries...)
+	}
+	return entries
+}
+
+func complete(s *SQSSink, names ...string) {
+	for _, name := range names {
+		s.OnCheckComplete(context.Background(), &domain.AvailabilityResult{Domain: name, Available: true, Status: domain.StatusAvailable}, nil)
+	}
+}
+
+func TestSQSSink_Publishes(t *testing.T) {
+	api := &fakeSQS{}
+	s := NewSQSSink(api, "https://sqs.us-east-1.amazonaws.com/123456789012/results")
+	s.SetTags(map[string]string{"client": "acme"})
+
+	var names []string
+	for i := 0; i < 25; i++ {
+		names = append(names, fmt.Sprintf("domain%d.com", i))
+	}
+	complete(s, names...)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	entries := api.messages()
+	if len(entries) != len(names) {
+		t.Fatalf("Expected %d messages, got %d", len(names), len(entries))
+	}
+	for _, batch := range api.batches {
+		if len(batch.Entries) > maxBatch {
+			t.Errorf("Expected at most %d messages per batch, got %d", maxBatch, len(batch.Entries))
+		}
+		if aws.ToString(batch.QueueUrl) != "https://sqs.us-east-1.amazonaws.com/123456789012/results" {
+			t.Errorf("Unexpected queue %s", aws.ToString(batch.QueueUrl))
+		}
+	}
+
+	var result Message
+	if err := json.Unmarshal([]byte(aws.ToString(entries[0].MessageBody)), &result); err != nil {
+		t.Fatalf("Invalid message body: %v", err)
+	}
+	if result.Domain != "domain0.com" || result.Status != "AVAILABLE" || result.Tags["client"] != "acme" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if aws.ToString(entries[0].MessageAttributes["status"].StringValue) != "AVAILABLE" {
+		t.Errorf("Expected a status attribute, got %v", entries[0].MessageAttributes)
+	}
+	if entries[0].MessageGroupId != nil {
+		t.Error("Expected no message group for a standard queue")
+	}
+}
+
+func TestSQSSink_FIFO(t *testing.T) {
+	api := &fakeSQS{}
+	s := NewSQSSink(api, "https://sqs.us-east-1.amazonaws.com/123456789012/results.fifo")
+	complete(s, "a.com", "b.com")
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := api.messages()
+	if aws.ToString(entries[0].MessageGroupId) != "a.com" || aws.ToString(entries[0].MessageDeduplicationId) == "" {
+		t.Errorf("Expected a message group and deduplication ID, got %+v", entries[0])
+	}
+	if aws.ToString(entries[0].MessageDeduplicationId) == aws.ToString(entries[1].MessageDeduplicationId) {
+		t.Error("Expected different results to have different deduplication IDs")
+	}
+}
+
+func TestSQSSink_Failures(t *testing.T) {
+	api := &fakeSQS{reject: map[string]bool{"bad.com": true}}
+	s := NewSQSSink(api, "https://sqs.us-east-1.amazonaws.com/123456789012/results")
+
+	complete(s, "good.com", "bad.com")
+	err := s.Flush()
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 results") {
+		t.Errorf("Expected one failed result, got %v", err)
+	}
+
+	// Failures are reported once, by the Flush that follows them
+	complete(s, "good.com")
+	if err := s.Flush(); err != nil {
+		t.Errorf("Expected no failures in the second run, got %v", err)
+	}
+
+	api.err = errors.New("connection reset")
+	complete(s, "good.com")
+	if err := s.Close(); err == nil || !strings.Contains(err.Error(), "1 of 1 results") {
+		t.Errorf("Expected the failed call to be reported, got %v", err)
+	}
+}
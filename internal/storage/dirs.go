@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// appName is the directory created under each XDG base directory
+const appName = "r53check"
+
+// Dirs is the on-disk layout used by r53check, following the XDG Base
+// Directory specification on every platform:
+//
+//	$XDG_CONFIG_HOME/r53check   config.yaml
+//	$XDG_CACHE_HOME/r53check    tlds.json, results/
+//	$XDG_DATA_HOME/r53check     history.jsonl, history.db, checkpoints/
+//
+// Caches can be deleted at any time; data is kept until the user removes it.
+type Dirs struct {
+	Config string
+	Cache  string
+	Data   string
+}
+
+// DefaultDirs returns the layout for the current user. Unset XDG variables
+// fall back to ~/.config, ~/.cache and ~/.local/share.
+func DefaultDirs() Dirs {
+	home, _ := os.UserHomeDir()
+	return Dirs{
+		Config: filepath.Join(xdgDir("XDG_CONFIG_HOME", home, ".config"), appName),
+		Cache:  filepath.Join(xdgDir("XDG_CACHE_HOME", home, ".cache"), appName),
+		Data:   filepath.Join(xdgDir("XDG_DATA_HOME", home, ".local", "share"), appName),
+	}
+}
+
+// xdgDir returns the directory named by env, or the fallback under home.
+// The specification requires relative paths in env to be ignored.
+func xdgDir(env, home string, fallback ...string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(append([]string{home}, fallback...)...)
+}
+
+// Mock returns the layout used with --mock: the same config, with caches
+// and data in mock subdirectories, so that fake prices and results never
+// mix with those of Route 53
+func (d Dirs) Mock() Dirs {
+	return Dirs{Config: d.Config, Cache: filepath.Join(d.Cache, "mock"), Data: filepath.Join(d.Data, "mock")}
+}
+
+// ConfigFile is the default configuration file
+func (d Dirs) ConfigFile() string {
+	return filepath.Join(d.Config, "config.yaml")
+}
+
+// TLDCatalog is the cached Route 53 TLD catalog
+func (d Dirs) TLDCatalog() string {
+	return filepath.Join(d.Cache, "tlds.json")
+}
+
+// PriceCatalog is the cached Route 53 price list
+func (d Dirs) PriceCatalog() string {
+	return filepath.Join(d.Cache, "prices.json")
+}
+
+// ResultCache is the directory of cached availability results
+func (d Dirs) ResultCache() string {
+	return filepath.Join(d.Cache, "results")
+}
+
+// Checkpoints is the directory of saved bulk run progress
+func (d Dirs) Checkpoints() string {
+	return filepath.Join(d.Data, "checkpoints")
+}
+
+// HistoryDB is the check history database
+func (d Dirs) HistoryDB() string {
+	return filepath.Join(d.Data, "history.db")
+}
+
+// Purchases is the ledger of domain purchases checked against the budget
+func (d Dirs) Purchases() string {
+	return filepath.Join(d.Data, "purchases.jsonl")
+}
+
+// Usage reports the number of files under path and their total size in
+// bytes. A missing path has no usage.
+func Usage(path string) (files int, size int64, err error) {
+	err = filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files++
+		size += info.Size()
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	return files, size, err
+}
+
+// ClearCache deletes everything in the cache directory. Config and data are
+// left untouched.
+func (d Dirs) ClearCache() error {
+	return os.RemoveAll(d.Cache)
+}
+
+// CheckCacheWritable verifies that files can be created in the cache
+// directory, creating it if needed
+func (d Dirs) CheckCacheWritable() error {
+	if err := os.MkdirAll(d.Cache, 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(d.Cache, ".probe-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Dirs
+	}{
+		{
+			name: "fallbacks under home",
+			env:  map[string]string{"XDG_CONFIG_HOME": "", "XDG_CACHE_HOME": "", "XDG_DATA_HOME": ""},
+			expected: Dirs{
+				Config: filepath.Join(home, ".config", "r53check"),
+				Cache:  filepath.Join(home, ".cache", "r53check"),
+				Data:   filepath.Join(home, ".local", "share", "r53check"),
+			},
+		},
+		{
+			name: "XDG variables",
+			env:  map[string]string{"XDG_CONFIG_HOME": "/xdg/config", "XDG_CACHE_HOME": "/xdg/cache", "XDG_DATA_HOME": "/xdg/data"},
+			expected: Dirs{
+				Config: filepath.Join("/xdg/config", "r53check"),
+				Cache:  filepath.Join("/xdg/cache", "r53check"),
+				Data:   filepath.Join("/xdg/data", "r53check"),
+			},
+		},
+		{
+			name: "relative XDG variables are ignored",
+			env:  map[string]string{"XDG_CONFIG_HOME": "relative", "XDG_CACHE_HOME": "", "XDG_DATA_HOME": ""},
+			expected: Dirs{
+				Config: filepath.Join(home, ".config", "r53check"),
+				Cache:  filepath.Join(home, ".cache", "r53check"),
+				Data:   filepath.Join(home, ".local", "share", "r53check"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			if dirs := DefaultDirs(); dirs != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, dirs)
+			}
+		})
+	}
+}
+
+func TestDirs_Paths(t *testing.T) {
+	dirs := Dirs{Config: "/c", Cache: "/k", Data: "/d"}
+
+	tests := []struct {
+		got      string
+		expected string
+	}{
+		{dirs.ConfigFile(), filepath.Join("/c", "config.yaml")},
+		{dirs.TLDCatalog(), filepath.Join("/k", "tlds.json")},
+		{dirs.PriceCatalog(), filepath.Join("/k", "prices.json")},
+		{dirs.ResultCache(), filepath.Join("/k", "results")},
+		{dirs.Checkpoints(), filepath.Join("/d", "checkpoints")},
+		{dirs.HistoryDB(), filepath.Join("/d", "history.db")},
+		{dirs.Purchases(), filepath.Join("/d", "purchases.jsonl")},
+		{dirs.Mock().ConfigFile(), filepath.Join("/c", "config.yaml")},
+		{dirs.Mock().PriceCatalog(), filepath.Join("/k", "mock", "prices.json")},
+		{dirs.Mock().HistoryDB(), filepath.Join("/d", "mock", "history.db")},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("expected %s, got %s", tt.expected, tt.got)
+		}
+	}
+}
+
+func TestUsageAndClearCache(t *testing.T) {
+	root := t.TempDir()
+	dirs := Dirs{Config: filepath.Join(root, "config"), Cache: filepath.Join(root, "cache"), Data: filepath.Join(root, "data")}
+
+	files, size, err := Usage(dirs.Cache)
+	if err != nil || files != 0 || size != 0 {
+		t.Errorf("expected no usage for a missing directory, got %d files, %d bytes, %v", files, size, err)
+	}
+
+	if err := os.MkdirAll(dirs.ResultCache(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dirs.Data, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dirs.TLDCatalog(), "12345")
+	writeFile(t, filepath.Join(dirs.ResultCache(), "example.com.json"), "123")
+	writeFile(t, dirs.HistoryDB(), "1")
+
+	files, size, err = Usage(dirs.Cache)
+	if err != nil || files != 2 || size != 8 {
+		t.Errorf("expected 2 files and 8 bytes, got %d files, %d bytes, %v", files, size, err)
+	}
+
+	if err := dirs.ClearCache(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(dirs.Cache); !os.IsNotExist(err) {
+		t.Errorf("expected cache directory to be removed, got %v", err)
+	}
+	if _, err := os.Stat(dirs.HistoryDB()); err != nil {
+		t.Errorf("expected data to be kept, got %v", err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckCacheWritable(t *testing.T) {
+	root := t.TempDir()
+	dirs := Dirs{Cache: filepath.Join(root, "cache")}
+
+	if err := dirs.CheckCacheWritable(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if files, _, _ := Usage(dirs.Cache); files != 0 {
+		t.Errorf("expected the probe file to be removed, found %d files", files)
+	}
+
+	// A file where the cache directory should be makes it unwritable
+	blocked := Dirs{Cache: filepath.Join(root, "file")}
+	writeFile(t, blocked.Cache, "x")
+	if err := blocked.CheckCacheWritable(); err == nil {
+		t.Error("expected an error when the cache path is not a directory")
+	}
+}
@@ -0,0 +1,81 @@
+package update
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// PublicKey is the minisign key release checksums are signed with
+type PublicKey struct {
+	id  [8]byte
+	key ed25519.PublicKey
+}
+
+// ParsePublicKey parses a minisign public key: the contents of its .pub file,
+// or only the base64 line
+func ParsePublicKey(text string) (*PublicKey, error) {
+	line := lastLine(text)
+	data, err := base64.StdEncoding.DecodeString(line)
+	if err != nil || len(data) != 2+8+ed25519.PublicKeySize || string(data[:2]) != "Ed" {
+		return nil, customErrors.NewSystemError("update", "invalid minisign public key", err)
+	}
+
+	key := &PublicKey{key: ed25519.PublicKey(data[10:])}
+	copy(key.id[:], data[2:10])
+	return key, nil
+}
+
+// Verify checks that signature, the contents of a minisign .minisig file,
+// signs message with key. Both the legacy and the prehashed (BLAKE2b)
+// signature algorithms are accepted, and the trusted comment must be signed
+// too.
+func (k *PublicKey) Verify(message, signature []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(signature)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "untrusted comment:") || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return customErrors.NewSystemError("update", "invalid minisign signature file", nil)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return customErrors.NewSystemError("update", "invalid minisign signature", err)
+	}
+	if !bytes.Equal(sig[2:10], k.id[:]) {
+		return customErrors.NewSystemError("update", "the checksums are signed with another key than this build trusts", nil)
+	}
+
+	signed := message
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED":
+		digest := blake2b.Sum512(message)
+		signed = digest[:]
+	default:
+		return customErrors.NewSystemError("update", "unsupported minisign signature algorithm "+string(sig[:2]), nil)
+	}
+	if !ed25519.Verify(k.key, signed, sig[10:]) {
+		return customErrors.NewSystemError("update", "the checksums signature does not match; the release may have been tampered with", nil)
+	}
+
+	// The global signature covers the signature and the trusted comment
+	global, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return customErrors.NewSystemError("update", "invalid minisign trusted comment signature", err)
+	}
+	comment := strings.TrimPrefix(strings.TrimRight(lines[2], "\r"), "trusted comment: ")
+	if !ed25519.Verify(k.key, append(sig[10:], comment...), global) {
+		return customErrors.NewSystemError("update", "the trusted comment of the checksums signature does not match", nil)
+	}
+	return nil
+}
+
+// lastLine returns the last non-empty line of text, trimmed
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,404 @@
+// Package update finds the latest r53check release on GitHub, downloads the
+// archive for the running platform, checks it against the release's
+// checksums, whose minisign signature proves they come from the maintainers,
+// and replaces the running binary with it.
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+const (
+	// DefaultAPIURL is the GitHub API the releases are read from
+	DefaultAPIURL = "https://api.github.com"
+
+	// Repository is the GitHub repository r53check is released from
+	Repository = "abakermi/r53check"
+
+	// binaryName is the executable inside each release archive
+	binaryName = "r53check"
+
+	// maxDownloadSize bounds an archive download, well above a release's size
+	maxDownloadSize = 200 << 20
+)
+
+// Release is a published GitHub release
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	URL    string  `json:"html_url"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Version returns the release's version without its leading v
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.Tag, "v")
+}
+
+// asset returns the asset called name, or nil
+func (r *Release) asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// Updater reads releases from the GitHub API
+type Updater struct {
+	client    *http.Client
+	apiURL    string
+	goos      string
+	goarch    string
+	publicKey *PublicKey
+}
+
+// NewUpdater creates an Updater using client, or http.DefaultClient when
+// client is nil, for the platform the binary runs on
+func NewUpdater(client *http.Client) *Updater {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Updater{client: client, apiURL: DefaultAPIURL, goos: runtime.GOOS, goarch: runtime.GOARCH}
+}
+
+// SetAPIURL sets the base URL of the GitHub API, for GitHub Enterprise
+// mirrors and tests
+func (u *Updater) SetAPIURL(apiURL string) {
+	u.apiURL = strings.TrimSuffix(apiURL, "/")
+}
+
+// SetPlatform sets the operating system and architecture whose archive is
+// downloaded, in GOOS and GOARCH terms
+func (u *Updater) SetPlatform(goos, goarch string) {
+	u.goos, u.goarch = goos, goarch
+}
+
+// SetPublicKey sets the minisign key the checksums of a release must be
+// signed with. Download refuses every release until one is set.
+func (u *Updater) SetPublicKey(key *PublicKey) {
+	u.publicKey = key
+}
+
+// Latest returns the latest published release
+func (u *Updater) Latest(ctx context.Context) (*Release, error) {
+	body, err := u.get(ctx, u.apiURL+"/repos/"+Repository+"/releases/latest", "application/vnd.github+json")
+	if err != nil {
+		return nil, err
+	}
+
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, customErrors.NewSystemError("update", "invalid release information from GitHub", err)
+	}
+	if release.Tag == "" {
+		return nil, customErrors.NewSystemError("update", "GitHub returned a release without a tag", nil)
+	}
+	return &release, nil
+}
+
+// Download fetches the release's archive for the platform, checks the
+// signature of the release's checksums file with the public key, then the
+// archive's SHA-256 checksum against it, and returns the r53check binary
+// inside it
+func (u *Updater) Download(ctx context.Context, release *Release) ([]byte, error) {
+	if u.publicKey == nil {
+		return nil, customErrors.NewSystemError("update",
+			"this build has no release signing key, so it cannot verify releases; download the release manually", nil)
+	}
+
+	archiveName := ArchiveName(release.Version(), u.goos, u.goarch)
+	archive := release.asset(archiveName)
+	if archive == nil {
+		return nil, customErrors.NewSystemError("update",
+			fmt.Sprintf("release %s has no archive for %s/%s", release.Tag, u.goos, u.goarch), nil)
+	}
+	checksums := release.asset(ChecksumsName(release.Version()))
+	if checksums == nil {
+		return nil, customErrors.NewSystemError("update",
+			fmt.Sprintf("release %s publishes no checksums; refusing to install it unchecked", release.Tag), nil)
+	}
+
+	signature := release.asset(SignatureName(release.Version()))
+	if signature == nil {
+		return nil, customErrors.NewSystemError("update",
+			fmt.Sprintf("release %s publishes no checksums signature; refusing to install it unverified", release.Tag), nil)
+	}
+
+	sums, err := u.get(ctx, checksums.URL, "")
+	if err != nil {
+		return nil, err
+	}
+	sig, err := u.get(ctx, signature.URL, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := u.publicKey.Verify(sums, sig); err != nil {
+		return nil, err
+	}
+	expected, err := findChecksum(sums, archiveName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := u.get(ctx, archive.URL, "")
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != expected {
+		return nil, customErrors.NewSystemError("update",
+			fmt.Sprintf("checksum mismatch for %s: expected %s, got %s", archiveName, expected, got), nil)
+	}
+
+	binary, err := extractBinary(data, archiveName, u.goos)
+	if err != nil {
+		return nil, customErrors.NewSystemError("update", "unable to extract "+archiveName, err)
+	}
+	return binary, nil
+}
+
+// get downloads url, failing on any status but 200
+func (u *Updater) get(ctx context.Context, url, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, customErrors.NewSystemError("update", "invalid download URL", err)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, customErrors.NewSystemError("update", "unable to reach GitHub", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, customErrors.NewSystemError("update",
+			fmt.Sprintf("GET %s returned HTTP %d", url, resp.StatusCode), nil)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, customErrors.NewSystemError("update", "download interrupted", err)
+	}
+	if len(body) > maxDownloadSize {
+		return nil, customErrors.NewSystemError("update", "download of "+url+" is too large", nil)
+	}
+	return body, nil
+}
+
+// ArchiveName returns the name of the release archive for a platform, as
+// named by the GoReleaser configuration, such as r53check_1.2.3_Linux_64bit.tar.gz
+func ArchiveName(version, goos, goarch string) string {
+	osName := strings.ToUpper(goos[:1]) + goos[1:]
+	if goos == "freebsd" {
+		osName = "FreeBSD"
+	}
+
+	archName := goarch
+	switch goarch {
+	case "amd64":
+		archName = "64bit"
+	case "386":
+		archName = "32bit"
+	case "arm64":
+		archName = "ARM64"
+	case "riscv64":
+		archName = "RISCV"
+	}
+
+	extension := ".tar.gz"
+	if goos == "windows" {
+		extension = ".zip"
+	}
+	return fmt.Sprintf("%s_%s_%s_%s%s", binaryName, version, osName, archName, extension)
+}
+
+// ChecksumsName returns the name of a release's SHA-256 checksums file
+func ChecksumsName(version string) string {
+	return fmt.Sprintf("%s_%s_checksums.txt", binaryName, version)
+}
+
+// SignatureName returns the name of the minisign signature of a release's
+// checksums file
+func SignatureName(version string) string {
+	return ChecksumsName(version) + ".minisig"
+}
+
+// findChecksum returns the hex SHA-256 listed for name in a checksums file
+// of "<sha256>  <name>" lines
+func findChecksum(sums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", customErrors.NewSystemError("update", "the checksums file does not list "+name, nil)
+}
+
+// extractBinary returns the r53check executable from a .tar.gz or .zip archive
+func extractBinary(data []byte, archiveName, goos string) ([]byte, error) {
+	want := binaryName
+	if goos == "windows" {
+		want += ".exe"
+	}
+
+	if strings.HasSuffix(archiveName, ".zip") {
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range reader.File {
+			if path.Base(file.Name) != want {
+				continue
+			}
+			rc, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(io.LimitReader(rc, maxDownloadSize))
+		}
+		return nil, fmt.Errorf("no %s in the archive", want)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no %s in the archive", want)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && path.Base(header.Name) == want {
+			return io.ReadAll(io.LimitReader(reader, maxDownloadSize))
+		}
+	}
+}
+
+// Replace atomically replaces the executable at target with binary, keeping
+// its permissions. The new binary is written next to target and renamed over
+// it; on Windows, where a running executable cannot be overwritten, target
+// is first moved aside to target.old.
+func Replace(target string, binary []byte) error {
+	info, err := os.Stat(target)
+	if err != nil {
+		return customErrors.NewSystemError("update", "unable to find the running binary", err)
+	}
+
+	dir := filepath.Dir(target)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(target)+".new-*")
+	if err != nil {
+		return customErrors.NewSystemError("update", "unable to write to "+dir+"; rerun with permission to replace the binary", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return customErrors.NewSystemError("update", "unable to write the new binary", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return customErrors.NewSystemError("update", "unable to write the new binary", err)
+	}
+	if err := os.Chmod(tmpName, info.Mode().Perm()); err != nil {
+		return customErrors.NewSystemError("update", "unable to make the new binary executable", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		old := target + ".old"
+		os.Remove(old)
+		if err := os.Rename(target, old); err != nil {
+			return customErrors.NewSystemError("update", "unable to move the running binary aside", err)
+		}
+	}
+	if err := os.Rename(tmpName, target); err != nil {
+		return customErrors.NewSystemError("update", "unable to replace the binary", err)
+	}
+	return nil
+}
+
+// Compare compares two versions such as v1.2.3 and 1.3.0-rc.1 by their
+// numeric major, minor and patch parts, with a pre-release ordered before
+// its release. It returns -1, 0 or 1 as a is older than, equal to or newer
+// than b.
+func Compare(a, b string) int {
+	aCore, aPre, _ := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	bCore, bPre, _ := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+
+	aParts, bParts := strings.Split(aCore, "."), strings.Split(bCore, ".")
+	for i := 0; i < 3; i++ {
+		if c := cmp.Compare(part(aParts, i), part(bParts, i)); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre < bPre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// IsRelease reports whether version looks like a released version, rather
+// than a development build such as "devel" or "(devel)"
+func IsRelease(version string) bool {
+	core, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), "-")
+	for _, p := range strings.Split(core, ".") {
+		if _, err := strconv.Atoi(p); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// part returns the i-th numeric part of a version, 0 when missing
+func part(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	n, _ := strconv.Atoi(parts[i])
+	return n
+}
@@ -0,0 +1,379 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// tarGz returns a .tar.gz archive holding files
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+// zipArchive returns a .zip archive holding files
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+// testKey is a minisign key pair generated for the tests
+type testKey struct {
+	id      [8]byte
+	private ed25519.PrivateKey
+	public  *PublicKey
+}
+
+// newTestKey generates a minisign key pair with id
+func newTestKey(id string) *testKey {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		panic(err)
+	}
+	key := &testKey{private: private}
+	copy(key.id[:], id)
+
+	encoded := base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), key.id[:]...), public...))
+	key.public, err = ParsePublicKey("untrusted comment: minisign public key\n" + encoded + "\n")
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// sign returns a .minisig file signing message with algorithm, "ED" for
+// prehashed or "Ed" for legacy signatures
+func (k *testKey) sign(message []byte, algorithm string) []byte {
+	signed := message
+	if algorithm == "ED" {
+		digest := blake2b.Sum512(message)
+		signed = digest[:]
+	}
+	sig := ed25519.Sign(k.private, signed)
+	comment := "timestamp:1700000000\tfile:checksums.txt"
+	global := ed25519.Sign(k.private, append(append([]byte{}, sig...), comment...))
+
+	return []byte(fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(append(append([]byte(algorithm), k.id[:]...), sig...)),
+		comment,
+		base64.StdEncoding.EncodeToString(global)))
+}
+
+// releaseKey signs the checksums served by releaseServer
+var releaseKey = newTestKey("testkey1")
+
+// releaseServer serves a latest release with the given archives and a
+// checksums file listing checksums, which defaults to the real ones, signed
+// with releaseKey
+func releaseServer(t *testing.T, tag string, archives map[string][]byte, checksums map[string]string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	version := strings.TrimPrefix(tag, "v")
+	release := Release{Tag: tag}
+	var sums strings.Builder
+	for name, data := range archives {
+		data := data
+		mux.HandleFunc("/download/"+name, func(w http.ResponseWriter, r *http.Request) { w.Write(data) })
+		release.Assets = append(release.Assets, Asset{Name: name, URL: server.URL + "/download/" + name})
+
+		sum, ok := checksums[name]
+		if !ok {
+			digest := sha256.Sum256(data)
+			sum = hex.EncodeToString(digest[:])
+		}
+		fmt.Fprintf(&sums, "%s  %s\n", sum, name)
+	}
+	checksumsName := ChecksumsName(version)
+	mux.HandleFunc("/download/"+checksumsName, func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(sums.String())) })
+	release.Assets = append(release.Assets, Asset{Name: checksumsName, URL: server.URL + "/download/" + checksumsName})
+	signature := releaseKey.sign([]byte(sums.String()), "ED")
+	signatureName := SignatureName(version)
+	mux.HandleFunc("/download/"+signatureName, func(w http.ResponseWriter, r *http.Request) { w.Write(signature) })
+	release.Assets = append(release.Assets, Asset{Name: signatureName, URL: server.URL + "/download/" + signatureName})
+
+	mux.HandleFunc("/repos/"+Repository+"/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	return server
+}
+
+func TestUpdater_Download(t *testing.T) {
+	linux := ArchiveName("1.3.0", "linux", "amd64")
+	windows := ArchiveName("1.3.0", "windows", "arm64")
+	server := releaseServer(t, "v1.3.0", map[string][]byte{
+		linux:   tarGz(t, map[string]string{"README.md": "docs", "r53check": "linux binary"}),
+		windows: zipArchive(t, map[string]string{"LICENSE": "licence", "r53check.exe": "windows binary"}),
+	}, nil)
+
+	updater := NewUpdater(server.Client())
+	updater.SetAPIURL(server.URL)
+	updater.SetPublicKey(releaseKey.public)
+
+	release, err := updater.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if release.Tag != "v1.3.0" || release.Version() != "1.3.0" {
+		t.Errorf("Unexpected release %+v", release)
+	}
+
+	tests := []struct {
+		goos, goarch string
+		expected     string
+	}{
+		{"linux", "amd64", "linux binary"},
+		{"windows", "arm64", "windows binary"},
+	}
+	for _, tt := range tests {
+		updater.SetPlatform(tt.goos, tt.goarch)
+		binary, err := updater.Download(context.Background(), release)
+		if err != nil {
+			t.Errorf("%s/%s: unexpected error: %v", tt.goos, tt.goarch, err)
+			continue
+		}
+		if string(binary) != tt.expected {
+			t.Errorf("%s/%s: expected %q, got %q", tt.goos, tt.goarch, tt.expected, binary)
+		}
+	}
+
+	updater.SetPlatform("darwin", "riscv64")
+	if _, err := updater.Download(context.Background(), release); err == nil || !strings.Contains(err.Error(), "no archive for darwin/riscv64") {
+		t.Errorf("Expected an error for a platform without an archive, got %v", err)
+	}
+}
+
+func TestUpdater_DownloadVerifiesChecksum(t *testing.T) {
+	name := ArchiveName("1.3.0", "linux", "amd64")
+	server := releaseServer(t, "v1.3.0", map[string][]byte{
+		name: tarGz(t, map[string]string{"r53check": "tampered"}),
+	}, map[string]string{name: strings.Repeat("0", 64)})
+
+	updater := NewUpdater(server.Client())
+	updater.SetAPIURL(server.URL)
+	updater.SetPublicKey(releaseKey.public)
+	updater.SetPlatform("linux", "amd64")
+
+	release, err := updater.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := updater.Download(context.Background(), release); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+
+	release.Assets = release.Assets[:1]
+	if _, err := updater.Download(context.Background(), release); err == nil || !strings.Contains(err.Error(), "publishes no checksums") {
+		t.Errorf("Expected an error for a release without checksums, got %v", err)
+	}
+}
+
+func TestUpdater_DownloadVerifiesSignature(t *testing.T) {
+	name := ArchiveName("1.3.0", "linux", "amd64")
+	server := releaseServer(t, "v1.3.0", map[string][]byte{
+		name: tarGz(t, map[string]string{"r53check": "new binary"}),
+	}, nil)
+
+	tests := []struct {
+		name     string
+		key      *PublicKey
+		assets   func([]Asset) []Asset
+		expected string
+	}{
+		{"signed", releaseKey.public, nil, ""},
+		{"no key in this build", nil, nil, "no release signing key"},
+		{"signed with another key", newTestKey("otherkey").public, nil, "signed with another key"},
+		{"forged with the same key id", newTestKey("testkey1").public, nil, "signature does not match"},
+		{"unsigned release", releaseKey.public, func(assets []Asset) []Asset { return assets[:2] }, "publishes no checksums signature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updater := NewUpdater(server.Client())
+			updater.SetAPIURL(server.URL)
+			updater.SetPlatform("linux", "amd64")
+			updater.SetPublicKey(tt.key)
+
+			release, err := updater.Latest(context.Background())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.assets != nil {
+				release.Assets = tt.assets(release.Assets)
+			}
+
+			binary, err := updater.Download(context.Background(), release)
+			switch {
+			case tt.expected == "" && err != nil:
+				t.Errorf("Unexpected error: %v", err)
+			case tt.expected == "" && string(binary) != "new binary":
+				t.Errorf("Expected the new binary, got %q", binary)
+			case tt.expected != "" && (err == nil || !strings.Contains(err.Error(), tt.expected)):
+				t.Errorf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestPublicKey_Verify(t *testing.T) {
+	message := []byte("checksums")
+
+	tests := []struct {
+		name      string
+		signature []byte
+		expected  string
+	}{
+		{"prehashed", releaseKey.sign(message, "ED"), ""},
+		{"legacy", releaseKey.sign(message, "Ed"), ""},
+		{"other message", releaseKey.sign([]byte("tampered"), "ED"), "does not match"},
+		{"tampered trusted comment", bytes.Replace(releaseKey.sign(message, "ED"), []byte("timestamp"), []byte("timestomp"), 1), "trusted comment"},
+		{"unknown algorithm", releaseKey.sign(message, "XX"), "unsupported"},
+		{"not a signature", []byte("hello"), "invalid minisign signature file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := releaseKey.public.Verify(message, tt.signature)
+			if tt.expected == "" && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.expected != "" && (err == nil || !strings.Contains(err.Error(), tt.expected)) {
+				t.Errorf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	if _, err := ParsePublicKey("not a key"); err == nil {
+		t.Error("Expected an invalid public key to be rejected")
+	}
+}
+
+func TestUpdater_LatestErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	updater := NewUpdater(server.Client())
+	updater.SetAPIURL(server.URL)
+	updater.SetPublicKey(releaseKey.public)
+	if _, err := updater.Latest(context.Background()); err == nil || !strings.Contains(err.Error(), "HTTP 403") {
+		t.Errorf("Expected the HTTP status in the error, got %v", err)
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	tests := []struct {
+		goos, goarch string
+		expected     string
+	}{
+		{"linux", "amd64", "r53check_1.2.3_Linux_64bit.tar.gz"},
+		{"darwin", "arm64", "r53check_1.2.3_Darwin_ARM64.tar.gz"},
+		{"freebsd", "riscv64", "r53check_1.2.3_FreeBSD_RISCV.tar.gz"},
+		{"windows", "amd64", "r53check_1.2.3_Windows_64bit.zip"},
+	}
+	for _, tt := range tests {
+		if got := ArchiveName("1.2.3", tt.goos, tt.goarch); got != tt.expected {
+			t.Errorf("ArchiveName(%s, %s) = %q, expected %q", tt.goos, tt.goarch, got, tt.expected)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"v1.2.3", "1.2.3", 0},
+		{"v1.2.3", "v1.10.0", -1},
+		{"v2.0.0", "v1.99.99", 1},
+		{"v1.3.0-rc.1", "v1.3.0", -1},
+		{"v1.3.0", "v1.3.0-rc.1", 1},
+		{"v1.3.0-rc.1", "v1.3.0-rc.2", -1},
+		{"v1.3", "v1.3.0", 0},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.expected {
+			t.Errorf("Compare(%s, %s) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+
+	for version, expected := range map[string]bool{"v1.2.3": true, "1.0.0-rc.1": true, "devel": false, "(devel)": false} {
+		if IsRelease(version) != expected {
+			t.Errorf("IsRelease(%q) = %v, expected %v", version, !expected, expected)
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("replacing moves the old binary aside on Windows")
+	}
+
+	target := filepath.Join(t.TempDir(), "r53check")
+	if err := os.WriteFile(target, []byte("old"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := Replace(target, []byte("new")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(target)
+	if err != nil || string(data) != "new" {
+		t.Errorf("Expected the new binary, got %q, %v", data, err)
+	}
+	info, _ := os.Stat(target)
+	if info.Mode().Perm() != 0o700 {
+		t.Errorf("Expected the permissions to be kept, got %v", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(target))
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary files left behind, got %d entries", len(entries))
+	}
+
+	if err := Replace(filepath.Join(t.TempDir(), "missing"), []byte("new")); err == nil {
+		t.Error("Expected an error for a missing binary")
+	}
+}
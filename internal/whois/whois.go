@@ -0,0 +1,202 @@
+// Package whois checks domains with the WHOIS protocol (RFC 3912), as a
+// fallback for TLDs that Route 53 Domains cannot check. WHOIS answers are
+// free text in a format that differs between registries, so the status it
+// reports is a best guess: registered when the registry returns a record,
+// and likely available when it reports that none exists.
+package whois
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+const (
+	// ianaServer refers each TLD to its registry's WHOIS server
+	ianaServer = "whois.iana.org"
+
+	// port is the WHOIS port
+	port = "43"
+
+	// maxResponseSize bounds how much of a response is read
+	maxResponseSize = 1 << 20
+)
+
+// notFoundPatterns are what registries answer, in lower case, when no
+// domain matches the query
+var notFoundPatterns = []string{
+	"no match",
+	"not found",
+	"no entries found",
+	"no data found",
+	"no object found",
+	"nothing found",
+	"no such domain",
+	"status: free",
+	"status: available",
+	"is free",
+}
+
+// registeredPatterns are fields, in lower case, of a registered domain's record
+var registeredPatterns = []string{
+	"domain name:",
+	"domain:",
+	"registrar:",
+	"creation date:",
+	"created:",
+	"registered:",
+}
+
+// rateLimitPatterns are what registries answer, in lower case, when they
+// refuse a query for exceeding their rate limit
+var rateLimitPatterns = []string{
+	"limit exceeded",
+	"quota exceeded",
+	"too many requests",
+	"try again later",
+}
+
+// Client looks up domains on the WHOIS server of their TLD, found through
+// IANA. It implements domain.Fallback and is safe for concurrent use.
+type Client struct {
+	// dial opens connections to WHOIS servers; tests replace it
+	dial func(ctx context.Context, network, address string) (net.Conn, error)
+
+	mu      sync.Mutex
+	servers map[string]string // WHOIS server of each TLD
+}
+
+// NewClient creates a WHOIS client
+func NewClient() *Client {
+	return &Client{
+		dial:    (&net.Dialer{}).DialContext,
+		servers: make(map[string]string),
+	}
+}
+
+// Name identifies WHOIS as the source of a result
+func (c *Client) Name() string {
+	return "whois"
+}
+
+// Lookup queries the WHOIS server of name's TLD and returns
+// StatusUnavailable when it holds a record of the domain, StatusAvailable
+// when it reports no match, and StatusUnknown when its answer is not
+// recognized
+func (c *Client) Lookup(ctx context.Context, name string) (domain.AvailabilityStatus, string, error) {
+	tld := name[strings.LastIndex(name, ".")+1:]
+	server, err := c.lookupServer(ctx, tld)
+	if err != nil {
+		return domain.StatusUnknown, "", err
+	}
+
+	response, err := c.query(ctx, server, name)
+	if err != nil {
+		return domain.StatusUnknown, "", err
+	}
+	return interpret(server, response)
+}
+
+// lookupServer returns the WHOIS server of tld, asking IANA the first time
+func (c *Client) lookupServer(ctx context.Context, tld string) (string, error) {
+	c.mu.Lock()
+	server, ok := c.servers[tld]
+	c.mu.Unlock()
+	if ok {
+		return server, nil
+	}
+
+	response, err := c.query(ctx, ianaServer, tld)
+	if err != nil {
+		return "", err
+	}
+	server = referral(response)
+	if server == "" {
+		return "", customErrors.NewValidationError("", "tld",
+			fmt.Sprintf("unsupported TLD: .%s, and IANA lists no WHOIS server for it", tld), nil)
+	}
+
+	c.mu.Lock()
+	c.servers[tld] = server
+	c.mu.Unlock()
+	return server, nil
+}
+
+// query sends q to server and returns its response
+func (c *Client) query(ctx context.Context, server, q string) (string, error) {
+	conn, err := c.dial(ctx, "tcp", net.JoinHostPort(server, port))
+	if err != nil {
+		return "", queryError(ctx, server, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := io.WriteString(conn, q+"\r\n"); err != nil {
+		return "", queryError(ctx, server, err)
+	}
+	response, err := io.ReadAll(io.LimitReader(conn, maxResponseSize))
+	if err != nil {
+		return "", queryError(ctx, server, err)
+	}
+	return string(response), nil
+}
+
+// queryError reports a failed query, as the context's error when the
+// query was cancelled or timed out
+func queryError(ctx context.Context, server string, err error) error {
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return customErrors.NewAPIError("whois", server, "WHOIS query to "+server+" failed", err)
+}
+
+// referral returns the WHOIS server IANA refers a TLD to, or "" when it
+// lists none
+func referral(response string) string {
+	var whois string
+	scanner := bufio.NewScanner(strings.NewReader(response))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "refer":
+			return strings.TrimSpace(value)
+		case "whois":
+			whois = strings.TrimSpace(value)
+		}
+	}
+	return whois
+}
+
+// interpret reads the status of a domain from server's response
+func interpret(server, response string) (domain.AvailabilityStatus, string, error) {
+	text := strings.ToLower(response)
+	for _, pattern := range rateLimitPatterns {
+		if strings.Contains(text, pattern) {
+			return domain.StatusUnknown, "", customErrors.NewAPIError("whois", server,
+				server+" refused the query for exceeding its rate limit", nil).WithStatusCode(429)
+		}
+	}
+	for _, pattern := range notFoundPatterns {
+		if strings.Contains(text, pattern) {
+			return domain.StatusAvailable, "likely available: " + server + " has no record of the domain (unverified)", nil
+		}
+	}
+	for _, pattern := range registeredPatterns {
+		if strings.Contains(text, pattern) {
+			return domain.StatusUnavailable, "registered according to " + server + " (unverified)", nil
+		}
+	}
+	return domain.StatusUnknown, "unrecognized answer from " + server + " (unverified)", nil
+}
@@ -0,0 +1,151 @@
+package whois
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+// fakeServers answers WHOIS queries from canned responses, keyed by server
+// and query
+type fakeServers struct {
+	mu        sync.Mutex
+	responses map[string]string
+	queries   []string
+}
+
+func (f *fakeServers) dial(ctx context.Context, network, address string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	client, server := net.Pipe()
+	go func() {
+		defer server.Close()
+		query, _ := bufio.NewReader(server).ReadString('\n')
+		key := host + " " + strings.TrimSpace(query)
+
+		f.mu.Lock()
+		f.queries = append(f.queries, key)
+		response := f.responses[key]
+		f.mu.Unlock()
+		server.Write([]byte(response))
+	}()
+	return client, nil
+}
+
+func newTestClient(responses map[string]string) (*Client, *fakeServers) {
+	servers := &fakeServers{responses: responses}
+	client := NewClient()
+	client.dial = servers.dial
+	return client, servers
+}
+
+func TestClient_Lookup(t *testing.T) {
+	client, servers := newTestClient(map[string]string{
+		"whois.iana.org ly":     "% IANA WHOIS server\n\ndomain:       LY\nrefer:        whois.nic.ly\n",
+		"whois.iana.org xx":     "% IANA WHOIS server\n\ndomain:       XX\nstatus:       ACTIVE\n",
+		"whois.nic.ly taken.ly": "Domain Name: taken.ly\nRegistrar: Example Registrar\nCreation Date: 2001-01-01\n",
+		"whois.nic.ly free.ly":  "No match for \"FREE.LY\".\n",
+		"whois.nic.ly odd.ly":   "Unexpected answer\n",
+		"whois.nic.ly busy.ly":  "Query limit exceeded, try again later\n",
+	})
+
+	tests := []struct {
+		domain   string
+		expected domain.AvailabilityStatus
+		message  string
+		err      string
+	}{
+		{"taken.ly", domain.StatusUnavailable, "registered according to whois.nic.ly (unverified)", ""},
+		{"free.ly", domain.StatusAvailable, "likely available: whois.nic.ly has no record of the domain (unverified)", ""},
+		{"odd.ly", domain.StatusUnknown, "unrecognized answer from whois.nic.ly (unverified)", ""},
+		{"busy.ly", domain.StatusUnknown, "", "rate limit"},
+		{"example.xx", domain.StatusUnknown, "", "IANA lists no WHOIS server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			status, message, err := client.Lookup(context.Background(), tt.domain)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Expected an error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Lookup() error: %v", err)
+			}
+			if status != tt.expected || message != tt.message {
+				t.Errorf("Lookup() = %s, %q, want %s, %q", status, message, tt.expected, tt.message)
+			}
+		})
+	}
+
+	// IANA is asked once per TLD
+	referrals := 0
+	for _, query := range servers.queries {
+		if query == "whois.iana.org ly" {
+			referrals++
+		}
+	}
+	if referrals != 1 {
+		t.Errorf("Expected one referral query for .ly, got %d in %v", referrals, servers.queries)
+	}
+}
+
+func TestClient_LookupRateLimitIsRetryable(t *testing.T) {
+	client, _ := newTestClient(map[string]string{
+		"whois.iana.org ly":    "refer: whois.nic.ly\n",
+		"whois.nic.ly busy.ly": "Too many requests\n",
+	})
+
+	_, _, err := client.Lookup(context.Background(), "busy.ly")
+	if key := customErrors.RetryKey(err); key != "429" {
+		t.Errorf("Expected the rate limit to be retried as a 429, got %q for %v", key, err)
+	}
+}
+
+func TestClient_LookupTimeout(t *testing.T) {
+	client := NewClient()
+	client.dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		<-ctx.Done()
+		return nil, errors.New("dial tcp: i/o timeout")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err := client.Lookup(ctx, "slow.ly")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to be reported, got %v", err)
+	}
+}
+
+func TestReferral(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected string
+	}{
+		{"refer", "domain: IO\nrefer:  whois.nic.io\n", "whois.nic.io"},
+		{"whois field", "domain: XY\nwhois:  whois.nic.xy\n", "whois.nic.xy"},
+		{"refer wins", "whois: whois.other.xy\nrefer: whois.nic.xy\n", "whois.nic.xy"},
+		{"none", "domain: XX\nstatus: ACTIVE\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := referral(tt.response); got != tt.expected {
+				t.Errorf("referral() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
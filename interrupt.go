@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+// interruptGracePeriod is how long after the first interrupt a second one
+// aborts the run at once, instead of waiting for the checks in flight
+const interruptGracePeriod = 10 * time.Second
+
+// exitProcess ends the process when a run is aborted; tests replace it
+var exitProcess = os.Exit
+
+// notifyInterrupts relays SIGINT and SIGTERM to signals until release is
+// called. Tests replace it to interrupt runs.
+var notifyInterrupts = func() (signals <-chan os.Signal, release func()) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	return c, func() { signal.Stop(c) }
+}
+
+// handleInterrupts cancels a run through cancel on the first SIGINT or
+// SIGTERM, and calls abort on a second one within interruptGracePeriod. The
+// returned stop function stops listening for the signals, waiting for an
+// abort in progress to finish.
+func handleInterrupts(cancel context.CancelFunc, abort func()) (stop func()) {
+	signals, release := notifyInterrupts()
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		watchInterrupts(signals, release, done, cancel, abort)
+	}()
+
+	return func() {
+		release()
+		close(done)
+		<-finished
+	}
+}
+
+// watchInterrupts implements handleInterrupts until done is closed. Once
+// the grace period has passed, signals are released, so that another
+// interrupt ends the process as it normally would.
+func watchInterrupts(signals <-chan os.Signal, release func(), done <-chan struct{}, cancel context.CancelFunc, abort func()) {
+	select {
+	case <-signals:
+	case <-done:
+		return
+	}
+	fmt.Fprintf(stderr, "\nInterrupted, finishing the checks in flight (interrupt again within %v to exit immediately)...\n", interruptGracePeriod)
+	cancel()
+
+	grace := time.NewTimer(interruptGracePeriod)
+	defer grace.Stop()
+	select {
+	case <-signals:
+		abort()
+	case <-grace.C:
+		release()
+	case <-done:
+	}
+}
+
+// forceExit ends the process after a second interrupt, reporting how many
+// of total checks had completed
+func forceExit(completed, total int) {
+	fmt.Fprintf(stderr, "Aborted: %d of %s completed\n", completed, pluralize(total, "check"))
+	runExitHooks()
+	exitProcess(int(customErrors.ExitSystemError))
+}
+
+// checkProgress records the latest result of every check of a bulk run, so
+// that an aborted run can still report the checks that completed
+type checkProgress struct {
+	domain.NopHooks
+
+	mu      sync.Mutex
+	results map[string]*domain.AvailabilityResult
+}
+
+func (p *checkProgress) OnCheckComplete(ctx context.Context, result *domain.AvailabilityResult, err error) {
+	// Checks cut short by the cancellation did not complete
+	if result == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.results == nil {
+		p.results = make(map[string]*domain.AvailabilityResult)
+	}
+	// Keep a copy, as the checker goes on to update the result
+	completed := *result
+	completed.CompletedAt = time.Now()
+	p.results[historyKey(result.Domain)] = &completed
+}
+
+// snapshot returns the completed result of each of domains, in order, with
+// the domains not checked yet reported as skipped
+func (p *checkProgress) snapshot(domains []string) (results []*domain.AvailabilityResult, completed int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	results = make([]*domain.AvailabilityResult, len(domains))
+	for i, name := range domains {
+		if result := p.results[historyKey(name)]; result != nil {
+			results[i] = result
+			completed++
+			continue
+		}
+		results[i] = domain.SkippedResult(name, "check skipped after the run was aborted")
+	}
+	return results, completed
+}
@@ -1,31 +1,72 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"os"
-	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
-	"syscall"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/abakermi/r53check/internal/aws"
+	"github.com/abakermi/r53check/internal/compress"
+	"github.com/abakermi/r53check/internal/config"
 	"github.com/abakermi/r53check/internal/domain"
 	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/history"
+	"github.com/abakermi/r53check/internal/i18n"
+	"github.com/abakermi/r53check/internal/input"
+	"github.com/abakermi/r53check/internal/monitor"
 	"github.com/abakermi/r53check/internal/output"
+	"github.com/abakermi/r53check/internal/storage"
+	"github.com/abakermi/r53check/pkg/r53checktest"
 
 	awsSDK "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
 	// Global flags
-	timeout time.Duration
-	region  string
-	verbose bool
-	price   bool
+	timeout           time.Duration
+	region            string
+	verbose           bool
+	price             bool
+	years             int
+	refreshTLDs       bool
+	tldsFlag          []string
+	tldsFile          string
+	confusables       bool
+	reservedWordsFile string
+	configFile        string
+	awsProfile        string
+	outputFlag        string
+	langFlag          string
+	mockMode          bool
+
+	// Settings resolved from flags, environment and config file
+	outputFormat = output.FormatText
+	tldsSource   = "--tlds"
+	localizer    *i18n.Localizer
+
+	// Output streams, replaced by execute so tests can capture command output
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+
+	// stdin is read for domains piped to bulk; tests replace it
+	stdin io.Reader = os.Stdin
+
+	// HTTP connection pool flags
+	maxIdleConns        int
+	idleConnTimeout     time.Duration
+	tlsHandshakeTimeout time.Duration
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -38,10 +79,31 @@ way to query Route 53 for domain registration status without navigating
 the AWS console.
 
 This tool is designed for developers, AWS administrators, and website
-planners who need to verify domain availability for their projects.`,
+planners who need to verify domain availability for their projects.
+
+Domains can be given without a command: a single domain is checked like
+"r53check check example.com", and several like "r53check bulk".`,
+	// Errors are formatted by execute; usage is only shown for argument errors
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		setupTimings = nil
+		start := time.Now()
+		if err := applyConfig(cmd); err != nil {
+			return err
+		}
+		timePhase("config load", start)
+		return startProfiling()
+	},
 	Example: `  # Check if example.com is available
   r53check check example.com
 
+  # The same, without the command
+  r53check example.com
+
+  # Check several domains, like r53check bulk
+  r53check example.com example.io example.dev
+
   # Check with pricing information
   r53check --price check example.com
 
@@ -60,30 +122,71 @@ var checkCmd = &cobra.Command{
 	
 The command validates the domain format and queries the Route 53 Domains API
 to determine availability status. It returns clear messages indicating whether
-the domain is available, registered, or if an error occurred.`,
+the domain is available, registered, or if an error occurred.
+
+The command fails when the check fails. --fail-on lists the outcomes that fail
+it instead: error, available, unavailable, reserved or unknown. A failing
+status exits with code 6.
+
+Without a domain, check prompts for one when run in a terminal, and asks
+again until the domain entered is valid.`,
 	Example: `  # Check a single domain
   r53check check example.com
 
+  # Fail unless the domain can be registered
+  r53check check example.com --fail-on unavailable,reserved,unknown,error
+
+  # Verify a domain is still registered, e.g. one you own
+  r53check check example.com --fail-on available,error
+
   # Check with pricing information
   r53check --price check example.com
 
   # Check with .io TLD
   r53check check myapp.io
 
+  # Post the result to a webhook
+  r53check check example.com --webhook-url https://hooks.example.com/r53check
+
+  # Open the Route 53 registration page if the domain is available
+  r53check check example.com --open
+
   # Check with custom timeout
   r53check --timeout 30s check example.com`,
-	Args: cobra.ExactArgs(1),
+	Args: checkArgs,
 	RunE: runCheckCommand,
 }
 
+// checkOutcomes are the --fail-on values: the statuses a check can report,
+// and "error" for a check that failed
+var checkOutcomes = []string{"error", "available", "unavailable", "reserved", "unknown"}
+
+var (
+	// failOnValues lists the check outcomes that fail the check command,
+	// parsed into failOn
+	failOnValues []string
+	failOn       map[string]bool
+)
+
 // bulkCmd represents the bulk command
 var bulkCmd = &cobra.Command{
 	Use:   "bulk [domains...]",
 	Short: "Check availability for multiple domains",
 	Long: `Check if multiple domains are available for registration in AWS Route 53.
 	
-You can provide domains as arguments or read from a file. The command will check
-all domains concurrently and provide a summary of results.`,
+You can provide domains as arguments, read them from a file, or pipe them in on
+stdin, one per line. Stdin is read when it is given as "-", as the only argument or
+as --file, and when no domains are given and stdin is not a terminal. The command
+will check all domains concurrently and provide a summary of results.
+
+--from-hosted-zones checks the domains of the account's public hosted zones
+instead, leaving out those registered in the account with Route 53 Domains. An
+available domain means a dangling zone, for a domain that was never registered
+or has expired.
+
+By default a failure for one domain does not stop the others from being checked.
+Use --fail-fast to abort the run on the first non-retryable error, such as an
+authentication or authorization failure. An invalid domain does not abort it.`,
 	Example: `  # Check multiple domains
   r53check bulk example.com test.org myapp.io
 
@@ -93,6 +196,48 @@ all domains concurrently and provide a summary of results.`,
   # Check domains from a file (one domain per line)
   r53check bulk --file domains.txt
 
+  # Check every list in a directory plus one more file, skipping duplicates
+  r53check bulk --file 'lists/*.txt' --file extra.txt
+
+  # Check the "Domain" column of a spreadsheet
+  r53check bulk --file candidates.xlsx --column domain
+
+  # Check domains generated by another command
+  grep -v staging names.txt | sed 's/$/.com/' | r53check bulk -
+
+  # Check a large list 100 domains at a time, pausing a minute between batches
+  r53check bulk --file domains.txt --batch-size 100 --batch-delay 1m
+
+  # List results alphabetically instead of in input order
+  r53check bulk --order alpha --file domains.txt
+
+  # List the cheapest available domains first, leaving out those over $20
+  r53check bulk --sort price --max-price 20 --file domains.txt
+
+  # Stop at the first non-retryable error
+  r53check bulk --fail-fast --file domains.txt
+
+  # Find hosted zones whose domain is not registered to anyone
+  r53check bulk --from-hosted-zones --list-available
+
+  # Only check domains that were not checked in the last day
+  r53check bulk --file domains.txt --skip-if-checked 24h
+
+  # Fail a CI job when any domain is taken or could not be checked
+  r53check bulk --file domains.txt --fail-on-unavailable --fail-on-error
+
+  # Email the results, using the email settings from the config file
+  r53check bulk --file domains.txt --notify email --email-to me@example.com
+
+  # Publish each result to an SQS queue as it completes
+  r53check bulk --file domains.txt --sqs-queue-url https://sqs.us-east-1.amazonaws.com/123456789012/domains
+
+  # Show a desktop notification when a long run finishes
+  r53check bulk --file domains.txt --notify desktop
+
+  # Post the results and their summary to a webhook as JSON
+  r53check bulk --file domains.txt --webhook-url https://hooks.example.com/r53check
+
   # Check with verbose output
   r53check --verbose bulk example.com test.org`,
 	RunE: runBulkCommand,
@@ -100,18 +245,113 @@ all domains concurrently and provide a summary of results.`,
 
 var (
 	// Bulk command flags
-	domainsFile string
+	domainsFiles  []string
+	domainsColumn string
+	inputFormat   string
+	noRetry       bool
+	failFast      bool
+	batchSize     int
+	batchDelay    time.Duration
+	orderFlag     string
+	concurrency   int
+
+	// extractDomains reads the input as free text and checks the
+	// registrable domains mentioned in it
+	extractDomains bool
+
+	// alternativeTLDs are checked for unavailable domains, to suggest the
+	// same name under another TLD
+	alternativeTLDs []string
+
+	// outFile receives the bulk results instead of stdout
+	outFile string
+
+	// summaryOnly and listAvailable replace the per-domain results with
+	// the summary and, optionally, the list of available domains
+	summaryOnly   bool
+	listAvailable bool
+
+	// limitDomains and sampleDomains check only the first or a random
+	// subset of the input, for spot checks of large lists
+	limitDomains  int
+	sampleDomains int
+	sampleSeed    int64
+
+	// failOnError and failOnUnavailable make a bulk run that completed
+	// exit with ExitPartialFailure when some domains failed or are taken
+	failOnError       bool
+	failOnUnavailable bool
+
+	// skipIfChecked reuses history for domains checked more recently
+	skipIfChecked time.Duration
+
+	// retryPolicy sets the retries per kind of failure, from the config file
+	retryPolicy *customErrors.RetryPolicy
 )
 
 func init() {
+	rootCmd.SetFlagErrorFunc(suggestFlag)
+
 	// Global flags
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Config file (default ~/.config/r53check/config.yaml)")
 	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 10*time.Second, "Timeout for API requests")
 	rootCmd.PersistentFlags().StringVar(&region, "region", "", "AWS region (defaults to AWS SDK default)")
+	rootCmd.PersistentFlags().StringVar(&awsProfile, "profile", "", "AWS shared config profile to use for credentials")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", string(output.FormatText), "Output format: text or json")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
-	rootCmd.PersistentFlags().BoolVar(&price, "price", false, "Include domain pricing information")
+	rootCmd.PersistentFlags().StringVar(&langFlag, "lang", "", "Language of results and error guidance: en, es, fr or ja (default from LANG)")
+	rootCmd.PersistentFlags().BoolVar(&price, "price", false, "Include registration, renewal and transfer prices (also --pricing)")
+	rootCmd.PersistentFlags().IntVar(&years, "years", 0, "Add the cost of registering and renewing each domain for N years, and their total, to prices (implies --price)")
+	rootCmd.SetGlobalNormalizationFunc(normalizeFlagName)
+	rootCmd.PersistentFlags().BoolVar(&confusables, "warn-confusables", false, "Warn about domains that mix scripts or look like popular domains")
+	rootCmd.PersistentFlags().StringVar(&reservedWordsFile, "reserved-words", "", "Warn about domains containing words from this file (one per line)")
+
+	rootCmd.PersistentFlags().StringSliceVar(&tldsFlag, "tlds", nil, "TLD policy entries: allow (io), extend (+dev) or deny (-xyz)")
+	rootCmd.PersistentFlags().StringVar(&tldsFile, "tlds-file", "", "Read TLD policy entries from file (one per line)")
+	rootCmd.PersistentFlags().DurationVar(&priceCacheTTL, "price-cache-ttl", domain.DefaultPriceCatalogTTL, "How long the cached Route 53 price list is used before it is fetched again")
+	rootCmd.PersistentFlags().BoolVar(&refreshTLDs, "refresh-tlds", false, "Refresh the cached Route 53 TLD catalog before checking")
+	rootCmd.PersistentFlags().StringVar(&fallbackFlag, "fallback", "", "Check TLDs Route 53 Domains does not support with: whois (results are marked unverified)")
+	rootCmd.PersistentFlags().BoolVar(&mockMode, "mock", false, "Answer checks from a deterministic fake instead of Route 53, without AWS credentials")
+
+	// HTTP connection pool flags, shared by every AWS client
+	httpDefaults := aws.DefaultHTTPOptions()
+	rootCmd.PersistentFlags().IntVar(&maxIdleConns, "max-idle-conns", httpDefaults.MaxIdleConns, "Maximum idle HTTP connections kept open to AWS")
+	rootCmd.PersistentFlags().DurationVar(&idleConnTimeout, "idle-conn-timeout", httpDefaults.IdleConnTimeout, "How long idle HTTP connections are kept open")
+	rootCmd.PersistentFlags().DurationVar(&tlsHandshakeTimeout, "tls-handshake-timeout", httpDefaults.TLSHandshakeTimeout, "Maximum time to wait for a TLS handshake")
 
 	// Add bulk command flags
-	bulkCmd.Flags().StringVarP(&domainsFile, "file", "f", "", "Read domains from file (one domain per line)")
+	bulkCmd.Flags().StringArrayVarP(&domainsFiles, "file", "f", nil, "Read domains from file (one domain per line, or a CSV or Excel file); repeatable and accepts glob patterns")
+	bulkCmd.Flags().StringVar(&domainsColumn, "column", "", "Column of a CSV or Excel file holding the domains, by header name or number")
+	bulkCmd.Flags().StringVar(&inputFormat, "input-format", "", "Read --file and stdin as text, csv, xlsx or zonefile (default from the file extension, else text)")
+	bulkCmd.Flags().BoolVar(&fromHostedZones, "from-hosted-zones", false, "Check the domains of the account's public hosted zones that are not registered in the account, to find dangling zones")
+	bulkCmd.Flags().BoolVar(&extractDomains, "extract-domains", false, "Treat the input as free text, such as logs or HTML, and check the registrable domains mentioned in it")
+	bulkCmd.Flags().IntVar(&limitDomains, "limit", 0, "Check only the first N domains of the input")
+	bulkCmd.Flags().IntVar(&sampleDomains, "sample", 0, "Check only N domains picked at random from the input")
+	bulkCmd.Flags().Int64Var(&sampleSeed, "seed", 0, "Seed for --sample, to pick the same domains again (default random)")
+	bulkCmd.Flags().StringSliceVar(&alternativeTLDs, "alternatives", nil, "For unavailable domains, check the same name under these TLDs, e.g. dev,io,app, and list the available ones")
+	bulkCmd.Flags().StringVar(&outFile, "out-file", "", "Write the results to this file instead of stdout, compressed when it ends in .gz or .zst")
+	bulkCmd.Flags().BoolVar(&summaryOnly, "summary-only", false, "Print only the summary counts and error groups, not a line per domain")
+	bulkCmd.Flags().BoolVar(&listAvailable, "list-available", false, "List the available domains after the summary")
+	bulkCmd.Flags().BoolVar(&openRegistration, "open", false, "Open the Route 53 registration page of the available domains (prints the URLs when stdout is not a terminal)")
+	bulkCmd.Flags().StringVar(&orderFlag, "order", string(output.OrderInput), "Order of bulk results: input, completed, alpha, or price (cheapest available first; implies --price) (also --sort)")
+	addMaxPriceFlag(bulkCmd)
+	bulkCmd.Flags().BoolVar(&failFast, "fail-fast", false, "Abort the run on the first non-retryable error instead of continuing")
+	bulkCmd.Flags().IntVar(&batchSize, "batch-size", 0, "Check domains in batches of this size (0 checks all at once)")
+	bulkCmd.Flags().DurationVar(&batchDelay, "batch-delay", 0, "Pause between batches when --batch-size is set")
+	bulkCmd.Flags().BoolVar(&failOnError, "fail-on-error", false, "Exit with code 6 when any domain could not be checked")
+	bulkCmd.Flags().BoolVar(&failOnUnavailable, "fail-on-unavailable", false, "Exit with code 6 when any domain is not available")
+	bulkCmd.Flags().StringVar(&prescreenFlag, "prescreen", "", "Look up domains in DNS first: dns reports domains with name servers UNAVAILABLE without calling Route 53")
+	bulkCmd.Flags().BoolVar(&noRetry, "no-retry", false, "Disable the second pass that re-checks throttled or timed out domains")
+	bulkCmd.Flags().IntVar(&concurrency, "concurrency", 5, "Maximum number of concurrent requests")
+	bulkCmd.Flags().DurationVar(&skipIfChecked, "skip-if-checked", 0, "Reuse the recorded result of domains checked within this duration, e.g. 24h, instead of checking them again")
+	addHistoryFlags(bulkCmd)
+	addNotifyFlags(bulkCmd)
+	addSinkFlags(bulkCmd)
+	checkCmd.Flags().StringSliceVar(&alternativeTLDs, "alternatives", nil, "If the domain is unavailable, check the same name under these TLDs, e.g. dev,io,app, and list the available ones")
+	checkCmd.Flags().BoolVar(&openRegistration, "open", false, "Open the Route 53 registration page if the domain is available (prints the URL when stdout is not a terminal)")
+	checkCmd.Flags().StringSliceVar(&failOnValues, "fail-on", nil, "Outcomes that fail the check: error, available, unavailable, reserved, unknown (default error)")
+	addHistoryFlags(checkCmd)
+	addNotifyFlags(checkCmd)
 
 	// Add commands to root
 	rootCmd.AddCommand(checkCmd)
@@ -119,330 +359,1189 @@ func init() {
 }
 
 func runCheckCommand(cmd *cobra.Command, args []string) error {
-	domainName := args[0]
+	if err := parseRunTags(); err != nil {
+		return err
+	}
+	if err := parseFailOn(); err != nil {
+		return err
+	}
+
+	// Prompt before handling interrupts, so Ctrl+C at the prompt just exits
+	var domainName string
+	if len(args) > 0 {
+		domainName = args[0]
+	} else {
+		var err error
+		if domainName, err = promptDomain(context.Background()); err != nil {
+			return err
+		}
+	}
 
 	// Set up signal handling for graceful cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Handle interrupt signals for graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigChan
-		if verbose {
-			fmt.Fprintln(os.Stderr, "\nReceived interrupt signal, cancelling request...")
-		}
-		cancel()
-	}()
+	stopInterrupts := handleInterrupts(cancel, func() { forceExit(0, 1) })
+	defer stopInterrupts()
 
 	// Create context with timeout
 	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, timeout)
 	defer timeoutCancel()
 
 	// Initialize components and run the check workflow
-	exitCode, err := runDomainCheck(timeoutCtx, domainName)
+	return runDomainCheck(timeoutCtx, domainName)
+}
 
-	if err != nil {
-		// Error has already been formatted and printed to stderr
-		os.Exit(exitCode)
+// parseFailOn validates --fail-on into failOn; without values only errors
+// fail the check
+func parseFailOn() error {
+	values := failOnValues
+	if len(values) == 0 {
+		values = []string{"error"}
 	}
 
-	// Success case
-	os.Exit(int(customErrors.ExitSuccess))
-	return nil // This line should never be reached due to os.Exit above
+	failOn = make(map[string]bool, len(values))
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if !slices.Contains(checkOutcomes, value) {
+			return customErrors.NewValidationError("", "fail-on",
+				fmt.Sprintf("invalid --fail-on value %q: must be one of %s%s", value, strings.Join(checkOutcomes, ", "),
+					customErrors.DidYouMean(value, checkOutcomes)), nil)
+		}
+		failOn[value] = true
+	}
+	return nil
 }
 
-// runDomainCheck encapsulates the complete domain checking workflow
-func runDomainCheck(ctx context.Context, domainName string) (int, error) {
-	// Initialize AWS configuration
-	if verbose {
-		fmt.Fprintf(os.Stderr, "Initializing AWS configuration...\n")
+// checkFailed reports a failed check, which fails the command unless
+// --fail-on leaves out error
+func checkFailed(err error) error {
+	if failOn["error"] {
+		return err
 	}
+	fmt.Fprintln(stderr, createFormatter().FormatError(err))
+	return nil
+}
 
-	var awsConfig *awsSDK.Config
-	var err error
+// runDomainCheck encapsulates the complete domain checking workflow
+func runDomainCheck(ctx context.Context, domainName string) error {
+	domainName = normalizeDomains([]string{domainName})[0]
 
-	if region != "" {
-		awsConfig, err = aws.NewConfigWithRegion(ctx, region)
-		if verbose && err == nil {
-			fmt.Fprintf(os.Stderr, "Using AWS region: %s\n", region)
-		}
-	} else {
-		awsConfig, err = aws.NewConfig(ctx)
-		if verbose && err == nil {
-			fmt.Fprintf(os.Stderr, "Using default AWS region from configuration\n")
-		}
+	// Initialize AWS configuration and client
+	if verbose {
+		fmt.Fprintf(stderr, "Initializing AWS configuration...\n")
 	}
 
+	awsClient, err := newTimedRoute53Client(ctx)
 	if err != nil {
-		exitCode := int(customErrors.GetExitCode(err))
-		formatter := createFormatter()
-		fmt.Fprintln(os.Stderr, formatter.FormatError(err))
-		return exitCode, err
-	}
-
-	// Create AWS client
-	if verbose {
-		fmt.Fprintf(os.Stderr, "Creating AWS Route 53 Domains client...\n")
+		return err
 	}
-	awsClient := aws.NewClient(awsConfig)
 
 	// Create domain validator
 	if verbose {
-		fmt.Fprintf(os.Stderr, "Initializing domain validator...\n")
+		fmt.Fprintf(stderr, "Initializing domain validator...\n")
+	}
+	catalogStart := time.Now()
+	validator, _, err := newValidator(ctx, awsClient)
+	if err != nil {
+		return err
 	}
-	validator := domain.NewDomainValidator()
+	timePhase("TLD catalog", catalogStart)
 
 	// Create domain checker with timeout
 	if verbose {
-		fmt.Fprintf(os.Stderr, "Creating domain checker with %v timeout...\n", timeout)
+		fmt.Fprintf(stderr, "Creating domain checker with %v timeout...\n", timeout)
+	}
+	checker, err := newChecker(validator, awsClient)
+	if err != nil {
+		return err
+	}
+
+	notifiers, err := newNotifiers(ctx)
+	if err != nil {
+		return err
+	}
+	store := openCommandHistory(ctx)
+	if store != nil {
+		defer store.Close()
 	}
-	checker := domain.NewDomainCheckerWithTimeout(validator, awsClient, timeout)
 
 	// Create output formatter
 	formatter := createFormatter()
 
 	// Validate domain before making API call
 	if verbose {
-		fmt.Fprintf(os.Stderr, "Validating domain format: %s\n", domainName)
+		fmt.Fprintf(stderr, "Validating domain format: %s\n", domainName)
 	}
 
-	if err := validator.ValidateDomain(domainName); err != nil {
-		exitCode := int(customErrors.GetExitCode(err))
-		fmt.Fprintln(os.Stderr, formatter.FormatError(err))
-		return exitCode, err
+	if err := checker.ValidateDomain(domainName); err != nil {
+		return checkFailed(err)
 	}
 
 	// Check domain availability
 	if verbose {
 		if price {
-			fmt.Fprintf(os.Stderr, "Checking domain availability and pricing with AWS Route 53...\n")
+			fmt.Fprintf(stderr, "Checking domain availability and pricing with AWS Route 53...\n")
 		} else {
-			fmt.Fprintf(os.Stderr, "Checking domain availability with AWS Route 53...\n")
+			fmt.Fprintf(stderr, "Checking domain availability with AWS Route 53...\n")
 		}
 	}
 
+	startedAt := time.Now()
+	stopSpinner := showSpinner("Checking " + domainName + "...")
 	var result *domain.AvailabilityResult
 	if price {
 		result, err = checker.CheckAvailabilityWithPricing(ctx, domainName)
 	} else {
 		result, err = checker.CheckAvailability(ctx, domainName)
 	}
+	stopSpinner()
 	if err != nil {
-		exitCode := int(customErrors.GetExitCode(err))
-
 		// Handle context cancellation gracefully
 		if errors.Is(err, context.Canceled) {
-			cancelErr := customErrors.NewSystemError("context", "Domain check was cancelled", err)
-			fmt.Fprintln(os.Stderr, formatter.FormatError(cancelErr))
-			return int(customErrors.ExitSystemError), cancelErr
+			return customErrors.NewSystemError("context", "Domain check was cancelled", err)
 		}
 
 		// Handle timeout specifically
 		if errors.Is(err, context.DeadlineExceeded) {
-			timeoutErr := customErrors.NewAPIError("route53domains", "CheckDomainAvailability",
-				fmt.Sprintf("domain check timed out after %v", timeout), err)
-			fmt.Fprintln(os.Stderr, formatter.FormatError(timeoutErr))
-			return int(customErrors.ExitAPIError), timeoutErr
+			return checkFailed(customErrors.NewTimeoutError("domain check", timeout, err))
 		}
 
-		fmt.Fprintln(os.Stderr, formatter.FormatError(err))
-		return exitCode, err
+		return checkFailed(err)
 	}
 
+	findAlternatives(ctx, checker, []*domain.AvailabilityResult{result})
+
 	// Display result to stdout
-	fmt.Println(formatter.FormatResult(result))
+	formatStart := time.Now()
+	fmt.Fprintln(stdout, formatter.FormatResult(result))
+	printCheckTimings(result, time.Since(formatStart))
+	openRegistrations([]*domain.AvailabilityResult{result})
+
+	report := monitor.NewReport(startedAt, []*domain.AvailabilityResult{result})
+	report.Tags = runTags
+	recordHistory(store, report)
+	if err := sendNotifications(ctx, notifiers, report); err != nil {
+		return err
+	}
+
+	if status := strings.ToLower(string(result.Status)); failOn[status] {
+		if outputFormat != output.FormatJSON {
+			fmt.Fprintf(stderr, "Check failed: %s is %s (--fail-on %s)\n", result.Domain, status, status)
+		}
+		return customErrors.NewExitError(customErrors.ExitPartialFailure, nil)
+	}
 
 	if verbose {
-		fmt.Fprintf(os.Stderr, "Domain check completed successfully\n")
+		fmt.Fprintf(stderr, "Domain check completed successfully\n")
 	}
 
-	return int(customErrors.ExitSuccess), nil
+	return nil
 }
 
-// createFormatter creates an output formatter based on global flags
-func createFormatter() output.Formatter {
-	formatter := output.NewConsoleFormatter()
-	formatter.SetVerbose(verbose)
-	formatter.SetShowTimestamp(verbose)
-	return formatter
+// loadAWSConfig loads the AWS configuration for the --region and --profile flags, sending all
+// AWS traffic through one HTTP client tuned by the connection pool flags
+func loadAWSConfig(ctx context.Context) (*awsSDK.Config, error) {
+	httpClient := aws.NewHTTPClient(aws.HTTPOptions{
+		MaxIdleConns:        maxIdleConns,
+		IdleConnTimeout:     idleConnTimeout,
+		TLSHandshakeTimeout: tlsHandshakeTimeout,
+	})
+
+	awsConfig, err := aws.NewConfigWithProfile(ctx, region, awsProfile, httpClient)
+	if verbose && err == nil {
+		fmt.Fprintf(stderr, "Using AWS region: %s\n", awsConfig.Region)
+		fmt.Fprintf(stderr, "HTTP pool: %d idle connections, %v idle timeout, %v TLS handshake timeout\n",
+			maxIdleConns, idleConnTimeout, tlsHandshakeTimeout)
+	}
+	return awsConfig, err
 }
 
-func main() {
-	// Execute the root command
-	// Exit codes are handled within the command functions
-	if err := rootCmd.Execute(); err != nil {
-		// This should not normally be reached since we handle exits in runCheckCommand
-		// But if it is reached, exit with a generic error code
-		os.Exit(int(customErrors.ExitSystemError))
+// route53Client is what the commands need from Route 53 Domains: availability
+// and pricing checks plus the TLD catalog and price list
+type route53Client interface {
+	domain.Route53Client
+	domain.TLDSource
+	domain.PriceSource
+}
+
+// newAWSClient creates the Route 53 Domains client used by the commands.
+// Tests replace it to run commands against a fake client.
+var newAWSClient = func(ctx context.Context) (route53Client, error) {
+	awsConfig, err := loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if verbose {
+		fmt.Fprintf(stderr, "Creating AWS Route 53 Domains client...\n")
 	}
+	return aws.NewClient(awsConfig), nil
 }
-func runBulkCommand(cmd *cobra.Command, args []string) error {
-	var domains []string
 
-	// Get domains from file or arguments
-	if domainsFile != "" {
-		fileDomains, err := readDomainsFromFile(domainsFile)
+// newRoute53Client returns the fake client of --mock, whose answers depend
+// only on the domain names, and otherwise the client from newAWSClient
+func newRoute53Client(ctx context.Context) (route53Client, error) {
+	if !mockMode {
+		return newAWSClient(ctx)
+	}
+
+	if verbose {
+		fmt.Fprintf(stderr, "Using the mock Route 53 Domains client; results are not real\n")
+	}
+	return r53checktest.NewMockClient(), nil
+}
+
+// appDirs returns where caches and data are kept: the user's XDG
+// directories, or their mock subdirectories with --mock
+func appDirs() storage.Dirs {
+	if mockMode {
+		return storage.DefaultDirs().Mock()
+	}
+	return storage.DefaultDirs()
+}
+
+// newValidator builds a validator from the Route 53 TLD catalog, falling back
+// to a cached or built-in TLD list when the catalog cannot be fetched, and
+// applies the TLD policy from --tlds and --tlds-file. It also reports where
+// the catalog came from.
+func newValidator(ctx context.Context, source domain.TLDSource) (*domain.DomainValidator, domain.CatalogOrigin, error) {
+	policy, err := loadTLDPolicy()
+	if err != nil {
+		return nil, "", err
+	}
+
+	catalog := domain.NewTLDCatalog(source, appDirs().TLDCatalog(), domain.DefaultCatalogTTL)
+
+	var tlds []string
+	var origin domain.CatalogOrigin
+	if refreshTLDs {
+		tlds, err = catalog.Refresh(ctx)
+		origin = domain.OriginAPI
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading domains file: %v\n", err)
-			os.Exit(int(customErrors.ExitValidation))
+			tlds, origin, _ = catalog.Load(ctx)
 		}
-		domains = fileDomains
-	} else if len(args) > 0 {
-		domains = args
 	} else {
-		fmt.Fprintf(os.Stderr, "Error: No domains provided. Use arguments or --file flag\n")
-		os.Exit(int(customErrors.ExitValidation))
+		tlds, origin, err = catalog.Load(ctx)
 	}
 
-	if len(domains) == 0 {
-		fmt.Fprintf(os.Stderr, "Error: No valid domains found\n")
-		os.Exit(int(customErrors.ExitValidation))
+	if verbose {
+		if err != nil {
+			fmt.Fprintf(stderr, "Unable to fetch TLD catalog from Route 53 Domains: %v\n", err)
+		}
+		fmt.Fprintf(stderr, "Using %d supported TLDs (%s)\n", len(tlds), origin)
 	}
 
-	// Set up signal handling for graceful cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	validator := domain.NewDomainValidatorWithTLDs(tlds)
+	if policy != nil {
+		if verbose {
+			fmt.Fprintf(stderr, "Applying TLD policy from %s\n", policy.Source)
+		}
+		validator.SetTLDPolicy(policy)
+	}
 
-	// Handle interrupt signals for graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigChan
+	return validator, origin, nil
+}
+
+// newChecker creates a domain checker with the timeout and screening flags applied
+func newChecker(validator domain.Validator, awsClient domain.Route53Client) (*domain.DomainChecker, error) {
+	checker := domain.NewDomainCheckerWithTimeout(validator, awsClient, timeout)
+	checker.SetWarnConfusables(confusables)
+	checker.SetConcurrency(concurrency)
+	if retryPolicy != nil {
+		checker.SetRetryPolicy(retryPolicy)
+	}
+	if reporter != nil {
+		checker.AddHooks(reporter)
+	}
+	for _, hooks := range metricsHooks {
+		checker.AddHooks(hooks)
+	}
+	if err := setupFallback(checker); err != nil {
+		return nil, err
+	}
+	if err := setupPrescreen(checker); err != nil {
+		return nil, err
+	}
+	if price {
+		setupPriceList(checker, awsClient)
+	}
+
+	if reservedWordsFile != "" {
+		reserved, err := domain.LoadReservedWordsFile(reservedWordsFile)
+		if err != nil {
+			return nil, customErrors.NewValidationError("", "reserved-words", "invalid reserved words list", err)
+		}
 		if verbose {
-			fmt.Fprintf(os.Stderr, "\nReceived interrupt signal, cancelling bulk check...\n")
+			fmt.Fprintf(stderr, "Screening against %d reserved words from %s\n", reserved.Len(), reserved.Source)
 		}
-		cancel()
-	}()
+		checker.AddScreener(reserved)
+	}
 
-	// Create context with timeout
-	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, timeout)
-	defer timeoutCancel()
+	return checker, nil
+}
+
+// loadTLDPolicy combines the --tlds-file and --tlds policies, returning nil when neither is set
+func loadTLDPolicy() (*domain.TLDPolicy, error) {
+	policy := domain.NewTLDPolicy("")
 
-	// Run bulk domain check
-	exitCode, err := runBulkDomainCheck(timeoutCtx, domains)
+	if tldsFile != "" {
+		filePolicy, err := domain.LoadTLDPolicyFile(tldsFile)
+		if err != nil {
+			return nil, customErrors.NewValidationError("", "tlds-file", "invalid TLD policy file", err)
+		}
+		policy.Merge(filePolicy)
+	}
 
+	if len(tldsFlag) > 0 {
+		flagPolicy := domain.NewTLDPolicy(tldsSource)
+		for _, entry := range tldsFlag {
+			if err := flagPolicy.Add(entry); err != nil {
+				return nil, customErrors.NewValidationError("", "tlds", "invalid TLD policy entry", err)
+			}
+		}
+		policy.Merge(flagPolicy)
+	}
+
+	if policy.IsEmpty() {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+// applyConfig resolves the configurable settings from flags, environment
+// variables, the config file and defaults, in that order, and stores them in
+// the flag variables
+func applyConfig(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	cfg, err := config.Load(configFile, flags)
 	if err != nil {
-		// Error has already been formatted and printed to stderr
-		os.Exit(exitCode)
+		return customErrors.NewValidationError("", "config", "invalid configuration", err)
 	}
 
-	// Success case
-	os.Exit(int(customErrors.ExitSuccess))
-	return nil // This line should never be reached due to os.Exit above
+	timeout = cfg.Timeout
+	region = cfg.Region
+	awsProfile = cfg.Profile
+	mockMode = cfg.Mock
+	priceCacheTTL = cfg.PriceCacheTTL
+	tldsFlag = cfg.TLDs
+	emailSettings = cfg.Email
+	webhookSettings = cfg.Webhook
+	notifyRules = cfg.Notify.Rules
+	historySettings = cfg.History
+	generateSettings = cfg.Generate
+	aiSettings = cfg.AI
+	budgetSettings = cfg.Budget
+	registerSettings = cfg.Register
+	contactProfiles = cfg.Contacts
+	if retryPolicy, err = customErrors.NewRetryPolicy(cfg.Retry.Budgets); err != nil {
+		return err
+	}
+	if cfg.Concurrency > 0 {
+		concurrency = cfg.Concurrency
+	}
+	if err := setupErrorReporting(cfg.ErrorReporting, cmd.CommandPath()); err != nil {
+		return err
+	}
+	if err := setupMetrics(cfg.Metrics); err != nil {
+		return err
+	}
+
+	tldsSource = "--tlds"
+	switch cfg.Source("tlds", flags) {
+	case "env":
+		tldsSource = config.EnvPrefix + "_TLDS"
+	case "config":
+		tldsSource = cfg.File
+	}
+
+	if outputFormat, err = output.ParseOutputFormat(cfg.Output); err != nil {
+		return err
+	}
+
+	lang := cfg.Lang
+	if lang == "" {
+		lang = i18n.Detect(os.Getenv)
+	}
+	if localizer, err = i18n.New(lang); err != nil {
+		return err
+	}
+
+	if years < 0 {
+		return customErrors.NewValidationError("", "years", fmt.Sprintf("--years must be at least 1, got %d", years), nil)
+	}
+	if years > 0 {
+		price = true
+	}
+
+	if verbose && cfg.File != "" {
+		fmt.Fprintf(stderr, "Using config file: %s\n", cfg.File)
+	}
+	return nil
 }
 
-func runBulkDomainCheck(ctx context.Context, domains []string) (int, error) {
-	// Initialize AWS configuration
-	if verbose {
-		fmt.Fprintf(os.Stderr, "Initializing AWS configuration...\n")
+// createFormatter creates an output formatter based on global flags
+func createFormatter() output.Formatter {
+	if outputFormat == output.FormatJSON {
+		formatter := output.NewJSONFormatter()
+		formatter.SetSummaryOnly(summaryOnly)
+		formatter.SetListAvailable(listAvailable)
+		formatter.SetYears(years)
+		return formatter
 	}
 
-	var awsConfig *awsSDK.Config
-	var err error
+	formatter := output.NewConsoleFormatter()
+	formatter.SetLocalizer(localizer)
+	formatter.SetVerbose(verbose)
+	formatter.SetShowTimestamp(verbose)
+	formatter.SetSummaryOnly(summaryOnly)
+	formatter.SetListAvailable(listAvailable)
+	formatter.SetYears(years)
+	return formatter
+}
 
-	if region != "" {
-		awsConfig, err = aws.NewConfigWithRegion(ctx, region)
-		if verbose && err == nil {
-			fmt.Fprintf(os.Stderr, "Using AWS region: %s\n", region)
-		}
-	} else {
-		awsConfig, err = aws.NewConfig(ctx)
-		if verbose && err == nil {
-			fmt.Fprintf(os.Stderr, "Using default AWS region (us-east-1)\n")
+func main() {
+	os.Exit(execute(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// execute runs the command line in args, writing to out and errOut, and
+// returns the process exit code. Commands return their errors rather than
+// exiting, so this is the only place that reports them.
+func execute(args []string, out, errOut io.Writer) int {
+	stdout, stderr = out, errOut
+	localizer = nil
+	rootCmd.SetArgs(defaultCommand(args))
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(errOut)
+
+	defer reportPanic()
+	err := rootCmd.Execute()
+	runExitHooks()
+	reporter.ReportError(err)
+	reporter.Close()
+	if err == nil {
+		return int(customErrors.ExitSuccess)
+	}
+
+	// Errors raised before the config was applied, such as invalid
+	// arguments, still follow an explicit --output and --lang
+	if rootCmd.PersistentFlags().Changed("output") {
+		if format, formatErr := output.ParseOutputFormat(outputFlag); formatErr == nil {
+			outputFormat = format
 		}
 	}
+	if localizer == nil && rootCmd.PersistentFlags().Changed("lang") {
+		localizer, _ = i18n.New(langFlag)
+	}
+
+	// An ExitError without a cause has already been reported by its command
+	var exitErr *customErrors.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Err != nil {
+		fmt.Fprintln(errOut, createFormatter().FormatError(err))
+	}
+	return int(customErrors.GetExitCode(err))
+}
+
+// flagAliases maps alternative spellings of flags to their names
+var flagAliases = map[string]string{
+	"pricing": "price",
+	"sort":    "order",
+}
+
+// normalizeFlagName accepts the aliases of flags in flagAliases
+func normalizeFlagName(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	if alias, ok := flagAliases[name]; ok {
+		name = alias
+	}
+	return pflag.NormalizedName(name)
+}
 
+func runBulkCommand(cmd *cobra.Command, args []string) error {
+	var entries []input.Entry
+
+	order, err := output.ParseResultOrder(orderFlag)
 	if err != nil {
-		exitCode := int(customErrors.GetExitCode(err))
-		formatter := createFormatter()
-		fmt.Fprintln(os.Stderr, formatter.FormatError(err))
-		return exitCode, err
+		return err
+	}
+	if maxPrice < 0 {
+		return customErrors.NewValidationError("", "max-price", "--max-price must not be negative", nil)
+	}
+	// Ordering and filtering by price need the prices
+	if order == output.OrderPrice || maxPrice > 0 {
+		price = true
 	}
 
-	// Create AWS client
+	if domainsColumn != "" && len(domainsFiles) == 0 {
+		return customErrors.NewValidationError("", "column", "--column picks a column of the --file file", nil)
+	}
+	if inputFormat != "" {
+		if _, err := input.ParseFormat(inputFormat); err != nil {
+			return err
+		}
+	}
+
+	// Get domains from hosted zones, file, arguments or stdin
+	switch {
+	case fromHostedZones:
+		if len(domainsFiles) > 0 || len(args) > 0 {
+			return customErrors.NewValidationError("", "from-hosted-zones", "--from-hosted-zones checks the domains of the hosted zones; do not also give domains or --file", nil)
+		}
+		if entries, err = hostedZoneEntries(context.Background()); err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Fprintln(stdout, "Every public hosted zone belongs to a domain registered in this account; nothing to check")
+			return nil
+		}
+	case len(domainsFiles) > 0:
+		if entries, err = readDomainEntries(domainsFiles); err != nil {
+			return customErrors.NewValidationError("", "file", "unable to read domains file", err)
+		}
+	case len(args) == 1 && args[0] == "-":
+		if entries, err = readDomains(stdin, stdinFormat()); err != nil {
+			return customErrors.NewValidationError("", "domains", "unable to read domains from stdin", err)
+		}
+	case len(args) > 0 && extractDomains:
+		entries = domainEntries(domain.ExtractDomains(strings.Join(args, "\n")))
+	case len(args) > 0:
+		entries = domainEntries(args)
+	case stdinPiped():
+		if entries, err = readDomains(stdin, stdinFormat()); err != nil {
+			return customErrors.NewValidationError("", "domains", "unable to read domains from stdin", err)
+		}
+		if len(entries) == 0 {
+			return customErrors.NewValidationError("", "domains", "no domains provided; use arguments, --file or stdin", nil)
+		}
+	default:
+		return customErrors.NewValidationError("", "domains", "no domains provided; use arguments, --file or stdin", nil)
+	}
+
+	if len(entries) == 0 {
+		return customErrors.NewValidationError("", "domains", "no valid domains found", nil)
+	}
+	if entries, err = selectDomains(cmd, entries); err != nil {
+		return err
+	}
+	if err := parseRunTags(); err != nil {
+		return err
+	}
+	if skipIfChecked < 0 {
+		return customErrors.NewValidationError("", "skip-if-checked", "--skip-if-checked must not be negative", nil)
+	}
+	if skipIfChecked > 0 && noHistory {
+		return customErrors.NewValidationError("", "skip-if-checked", "--skip-if-checked reads the check history, which --no-history disables", nil)
+	}
+
+	// Run bulk domain check; the timeout applies to each API request, not the whole run
+	return runBulkDomainCheck(context.Background(), entries, order)
+}
+
+func runBulkDomainCheck(ctx context.Context, entries []input.Entry, order output.ResultOrder) error {
+	domains := normalizeDomains(input.Domains(entries))
+
+	// The first interrupt cancels the run, which still reports the checks
+	// that completed; a second one calls abortRun, once the run has one
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var abortRun atomic.Pointer[func()]
+	stopInterrupts := handleInterrupts(cancel, func() {
+		if abort := abortRun.Load(); abort != nil {
+			(*abort)()
+			return
+		}
+		forceExit(0, len(domains))
+	})
+	defer stopInterrupts()
+
+	// Initialize AWS configuration and client
 	if verbose {
-		fmt.Fprintf(os.Stderr, "Creating AWS Route 53 Domains client...\n")
+		fmt.Fprintf(stderr, "Initializing AWS configuration...\n")
+	}
+
+	awsClient, err := newTimedRoute53Client(ctx)
+	if err != nil {
+		return err
 	}
-	awsClient := aws.NewClient(awsConfig)
 
 	// Create domain validator
 	if verbose {
-		fmt.Fprintf(os.Stderr, "Initializing domain validator...\n")
+		fmt.Fprintf(stderr, "Initializing domain validator...\n")
+	}
+	catalogStart := time.Now()
+	validator, _, err := newValidator(ctx, awsClient)
+	if err != nil {
+		return err
 	}
-	validator := domain.NewDomainValidator()
+	timePhase("TLD catalog", catalogStart)
 
 	// Create domain checker with timeout
 	if verbose {
-		fmt.Fprintf(os.Stderr, "Creating domain checker with %v timeout...\n", timeout)
-		fmt.Fprintf(os.Stderr, "Checking %d domains...\n", len(domains))
+		fmt.Fprintf(stderr, "Creating domain checker with %v timeout...\n", timeout)
+		fmt.Fprintf(stderr, "Checking %d domains...\n", len(domains))
+	}
+	checker, err := newChecker(validator, awsClient)
+	if err != nil {
+		return err
+	}
+	if noRetry {
+		checker.SetRetryDelay(-1)
+	}
+	domains = affordableDomains(ctx, checker, domains)
+	checker.SetFailFast(failFast)
+	checker.SetBatching(batchSize, batchDelay)
+	retries := &retryCounter{}
+	checker.AddHooks(retries)
+	notes := newDomainNotes(entries)
+	if notes != nil {
+		checker.AddHooks(notes)
+	}
+	if verbose && batchSize > 0 {
+		fmt.Fprintf(stderr, "Checking in batches of %d with %v between batches...\n", batchSize, batchDelay)
+	}
+
+	notifiers, err := newNotifiers(ctx)
+	if err != nil {
+		return err
+	}
+	sqsSink, err := newSQSSink(ctx, checker)
+	if err != nil {
+		return err
+	}
+	store := openCommandHistory(ctx)
+	closeStore := sync.OnceFunc(func() {
+		if store != nil {
+			store.Close()
+		}
+	})
+	defer closeStore()
+	startedAt := time.Now()
+
+	// Domains checked recently are reported from history instead
+	recent := recentResults(store, domains, skipIfChecked)
+	unchecked := make([]string, 0, len(domains))
+	for _, name := range domains {
+		if recent[historyKey(name)] == nil {
+			unchecked = append(unchecked, name)
+		}
+	}
+	if verbose && len(recent) > 0 {
+		fmt.Fprintf(stderr, "Reusing %d results checked in the last %v\n", len(domains)-len(unchecked), skipIfChecked)
 	}
-	checker := domain.NewDomainCheckerWithTimeout(validator, awsClient, timeout)
 
 	// Create output formatter
 	formatter := createFormatter()
 
+	// An aborted run reports the checks that had completed, unless the
+	// results are already being reported
+	var reported atomic.Bool
+	progress := &checkProgress{}
+	checker.AddHooks(progress)
+	abort := func() {
+		results, completed := progress.snapshot(unchecked)
+		if reported.CompareAndSwap(false, true) {
+			results = mergeRecentResults(domains, recent, results)
+			notes.annotate(results...)
+			reportInterrupted(formatter, store, startedAt, results, order)
+			closeStore()
+		}
+		forceExit(completed, len(unchecked))
+	}
+	abortRun.Store(&abort)
+
 	// Check domain availability in bulk
+	checkStart := time.Now()
 	var results []*domain.AvailabilityResult
-	if price {
-		results, err = checker.CheckAvailabilityBulkWithPricing(ctx, domains)
-	} else {
-		results, err = checker.CheckAvailabilityBulk(ctx, domains)
+	switch {
+	case len(unchecked) == 0:
+	case price:
+		results, err = checker.CheckAvailabilityBulkWithPricing(ctx, unchecked)
+	default:
+		results, err = checker.CheckAvailabilityBulk(ctx, unchecked)
+	}
+	// A cancelled or timed out run still reports the checks that completed
+	interrupted := err != nil && ctx.Err() != nil
+	if (err == nil && len(recent) > 0) || interrupted {
+		results = mergeRecentResults(domains, recent, results)
+		notes.annotate(results...)
 	}
+	if err == nil {
+		findAlternatives(ctx, checker, results)
+	}
+	checking := time.Since(checkStart)
+
+	// Publish whatever completed, even when the run failed
+	var sinkErr error
+	if sqsSink != nil {
+		sinkErr = sqsSink.Close()
+	}
+
 	if err != nil {
-		exitCode := int(customErrors.GetExitCode(err))
+		if interrupted && reported.CompareAndSwap(false, true) {
+			reportInterrupted(formatter, store, startedAt, results, order)
+		}
 
 		// Handle context cancellation gracefully
 		if errors.Is(err, context.Canceled) {
-			cancelErr := customErrors.NewSystemError("context", "Bulk domain check was cancelled", err)
-			fmt.Fprintln(os.Stderr, formatter.FormatError(cancelErr))
-			return int(customErrors.ExitSystemError), cancelErr
+			return customErrors.NewSystemError("context", "Bulk domain check was cancelled", err)
 		}
 
 		// Handle timeout specifically
 		if errors.Is(err, context.DeadlineExceeded) {
-			timeoutErr := customErrors.NewAPIError("route53domains", "CheckDomainAvailability",
-				fmt.Sprintf("bulk domain check timed out after %v", timeout), err)
-			fmt.Fprintln(os.Stderr, formatter.FormatError(timeoutErr))
-			return int(customErrors.ExitAPIError), timeoutErr
+			return customErrors.NewTimeoutError("bulk domain check", timeout, err)
+		}
+
+		return err
+	}
+
+	// Display results to stdout, unless a second interrupt got there first
+	if !reported.CompareAndSwap(false, true) {
+		return customErrors.NewSystemError("context", "Bulk domain check was aborted", nil)
+	}
+	formatStart := time.Now()
+	sorted := output.SortResults(withinMaxPrice(results), order)
+	if err := writeBulkOutput(formatter.FormatBulkResults(sorted)); err != nil {
+		return err
+	}
+	printBulkTimings(results, checking, time.Since(formatStart), retries)
+	openRegistrations(sorted)
+
+	report := monitor.NewReport(startedAt, results)
+	report.Tags = runTags
+	recordHistory(store, report)
+	if err := sendNotifications(ctx, notifiers, report); err != nil {
+		return err
+	}
+	if sinkErr != nil {
+		return sinkErr
+	}
+
+	if verbose {
+		retried := 0
+		for _, result := range results {
+			if result != nil && result.Retried {
+				retried++
+			}
+		}
+		if retried > 0 {
+			fmt.Fprintf(stderr, "Retried %s: %s\n", pluralize(retried, "domain"), retries)
+		}
+	}
+
+	if err := checkBulkGates(results); err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Fprintf(stderr, "Bulk domain check completed successfully\n")
+	}
+
+	return nil
+}
+
+// reportInterrupted formats and records the results of a bulk run that was
+// cancelled or timed out, so the checks it completed are not lost. The
+// output is marked incomplete by the skipped domains. Notifications and
+// --fail-on gates are left to runs that complete.
+func reportInterrupted(formatter output.Formatter, store history.Store, startedAt time.Time, results []*domain.AvailabilityResult, order output.ResultOrder) {
+	if err := writeBulkOutput(formatter.FormatBulkResults(output.SortResults(withinMaxPrice(results), order))); err != nil {
+		fmt.Fprintln(stderr, formatter.FormatError(err))
+	}
+
+	report := monitor.NewReport(startedAt, results)
+	report.Tags = runTags
+	recordHistory(store, report)
+}
+
+// findAlternatives checks the --alternatives TLDs of the unavailable
+// results. Failing to check them only loses the suggestions, so it is
+// reported as a warning.
+func findAlternatives(ctx context.Context, checker *domain.DomainChecker, results []*domain.AvailabilityResult) {
+	if len(alternativeTLDs) == 0 {
+		return
+	}
+	if verbose {
+		fmt.Fprintf(stderr, "Checking alternatives under %s for unavailable domains...\n", strings.Join(alternativeTLDs, ", "))
+	}
+	if err := checker.FindAlternatives(ctx, results, alternativeTLDs); err != nil {
+		fmt.Fprintf(stderr, "Warning: alternatives could not be checked: %v\n", err)
+	}
+}
+
+// writeBulkOutput writes formatted bulk results to stdout, or to --out-file,
+// compressed when its name ends in .gz or .zst
+func writeBulkOutput(text string) error {
+	if outFile == "" {
+		fmt.Fprintln(stdout, text)
+		return nil
+	}
+
+	file, err := os.Create(outFile)
+	if err != nil {
+		return customErrors.NewSystemError("output", "unable to create the output file", err)
+	}
+	w, err := compress.NewWriter(file, compress.Detect(outFile))
+	if err == nil {
+		_, err = fmt.Fprintln(w, text)
+		err = errors.Join(err, w.Close())
+	}
+	if err = errors.Join(err, file.Close()); err != nil {
+		return customErrors.NewSystemError("output", "unable to write the output file", err)
+	}
+
+	if verbose {
+		fmt.Fprintf(stderr, "Wrote results to %s\n", outFile)
+	}
+	return nil
+}
+
+// checkBulkGates fails a completed bulk run with ExitPartialFailure when
+// --fail-on-error or --fail-on-unavailable is set and some results match
+func checkBulkGates(results []*domain.AvailabilityResult) error {
+	summary := output.NewJSONBulkResults(results).Summary
+
+	var reasons []string
+	if failOnError && summary.Errors > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d of %d domains could not be checked", summary.Errors, summary.Total))
+	}
+	if failOnUnavailable && summary.Unavailable > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d of %d domains are not available", summary.Unavailable, summary.Total))
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	// The results, with their summary, have already been printed
+	if outputFormat != output.FormatJSON {
+		fmt.Fprintf(stderr, "Bulk check failed: %s\n", strings.Join(reasons, "; "))
+	}
+	return customErrors.NewExitError(customErrors.ExitPartialFailure, nil)
+}
+
+// retryCounter counts the retries of a run by the kind of failure that
+// caused them, for verbose output
+type retryCounter struct {
+	domain.NopHooks
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (r *retryCounter) OnRetry(ctx context.Context, name string, attempt int, err error) {
+	key := customErrors.RetryKey(err)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counts == nil {
+		r.counts = make(map[string]int)
+	}
+	r.counts[key]++
+}
+
+// total returns the number of retries counted
+func (r *retryCounter) total() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	total := 0
+	for _, count := range r.counts {
+		total += count
+	}
+	return total
+}
+
+// String summarizes the retries, e.g. "7 retries (429: 5, timeout: 2)"
+func (r *retryCounter) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	total := 0
+	keys := make([]string, 0, len(r.counts))
+	for key, count := range r.counts {
+		total += count
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s: %d", key, r.counts[key])
+	}
+	noun := "retries"
+	if total == 1 {
+		noun = "retry"
+	}
+	return fmt.Sprintf("%d %s (%s)", total, noun, strings.Join(parts, ", "))
+}
+
+// normalizeDomains reduces URLs, FQDNs and subdomains to registrable domains,
+// noting each change in verbose mode
+func normalizeDomains(domains []string) []string {
+	normalized := make([]string, len(domains))
+	for i, input := range domains {
+		name, changed := domain.NormalizeDomain(input)
+		if changed && verbose {
+			fmt.Fprintf(stderr, "Normalized '%s' to '%s'\n", input, name)
 		}
+		normalized[i] = name
+	}
+	return normalized
+}
+
+// selectDomains applies --limit or --sample to the input domains. A sample
+// keeps the input order of the domains picked; its seed is shown in verbose
+// output so the same sample can be checked again with --seed.
+func selectDomains(cmd *cobra.Command, domains []input.Entry) ([]input.Entry, error) {
+	seedSet := cmd.Flags().Changed("seed")
+	switch {
+	case limitDomains < 0:
+		return nil, customErrors.NewValidationError("", "limit", "--limit must not be negative", nil)
+	case sampleDomains < 0:
+		return nil, customErrors.NewValidationError("", "sample", "--sample must not be negative", nil)
+	case limitDomains > 0 && sampleDomains > 0:
+		return nil, customErrors.NewValidationError("", "sample", "use --limit or --sample, not both", nil)
+	case seedSet && sampleDomains == 0:
+		return nil, customErrors.NewValidationError("", "seed", "--seed sets the random picks of --sample", nil)
+	}
 
-		fmt.Fprintln(os.Stderr, formatter.FormatError(err))
-		return exitCode, err
+	if limitDomains > 0 && limitDomains < len(domains) {
+		if verbose {
+			fmt.Fprintf(stderr, "Checking the first %d of %d domains\n", limitDomains, len(domains))
+		}
+		return domains[:limitDomains], nil
+	}
+	if sampleDomains == 0 || sampleDomains >= len(domains) {
+		return domains, nil
 	}
 
-	// Display results to stdout
-	fmt.Println(formatter.FormatBulkResults(results))
+	seed := sampleSeed
+	if !seedSet {
+		seed = rand.Int64()
+	}
+	picks := rand.New(rand.NewPCG(uint64(seed), 0)).Perm(len(domains))[:sampleDomains]
+	sort.Ints(picks)
 
+	sample := make([]input.Entry, len(picks))
+	for i, pick := range picks {
+		sample[i] = domains[pick]
+	}
 	if verbose {
-		fmt.Fprintf(os.Stderr, "Bulk domain check completed successfully\n")
+		fmt.Fprintf(stderr, "Checking %d domains sampled from %d (--seed %d)\n", len(sample), len(domains), seed)
 	}
+	return sample, nil
+}
 
-	return int(customErrors.ExitSuccess), nil
+// readDomainFiles reads the domains of every --file in order, expanding
+// glob patterns such as lists/*.txt. Domains listed more than once, in one
+// file or several, are only kept the first time.
+func readDomainFiles(patterns []string) ([]string, error) {
+	entries, err := readDomainEntries(patterns)
+	return input.Domains(entries), err
 }
 
-func readDomainsFromFile(filename string) ([]string, error) {
+// readDomainEntries reads the domains of every --file like readDomainFiles,
+// with the notes given next to them
+func readDomainEntries(patterns []string) ([]input.Entry, error) {
+	files, err := expandFilePatterns(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []input.Entry
+	seen := make(map[string]bool)
+	for _, file := range files {
+		fileEntries, err := readDomainsFromFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		duplicates := 0
+		for _, entry := range fileEntries {
+			key, _ := domain.NormalizeDomain(entry.Domain)
+			if seen[key] {
+				duplicates++
+				continue
+			}
+			seen[key] = true
+			entries = append(entries, entry)
+		}
+
+		if verbose {
+			source := file
+			if file == "-" {
+				source = "stdin"
+			}
+			fmt.Fprintf(stderr, "Read %s from %s", pluralize(len(fileEntries)-duplicates, "domain"), source)
+			if duplicates > 0 {
+				fmt.Fprintf(stderr, " (%d already listed)", duplicates)
+			}
+			fmt.Fprintln(stderr)
+		}
+	}
+	return entries, nil
+}
+
+// expandFilePatterns returns the files named by patterns, each once. A
+// pattern with glob characters must match at least one file.
+func expandFilePatterns(patterns []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches := []string{pattern}
+		if pattern != "-" && strings.ContainsAny(pattern, "*?[") {
+			var err error
+			if matches, err = filepath.Glob(pattern); err != nil {
+				return nil, fmt.Errorf("invalid file pattern %q: %w", pattern, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match %q", pattern)
+			}
+		}
+		for _, file := range matches {
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	return files, nil
+}
+
+// fileFormat returns the --input-format format, or an empty format to detect
+// the format of each file from its extension
+func fileFormat() input.Format {
+	format, _ := input.ParseFormat(inputFormat)
+	return format
+}
+
+// stdinFormat returns the format stdin is read as: --input-format, else CSV
+// when --column is set, else text
+func stdinFormat() input.Format {
+	switch {
+	case fileFormat() != "":
+		return fileFormat()
+	case domainsColumn != "":
+		return input.FormatCSV
+	default:
+		return input.FormatText
+	}
+}
+
+// readDomainsFromFile reads the domains in filename, or on stdin when
+// filename is "-", in the --input-format format or else the one of its
+// extension. CSV and Excel files are read from the --column column; so is
+// stdin, as CSV, when --column is set.
+func readDomainsFromFile(filename string) ([]input.Entry, error) {
+	if filename == "-" {
+		return readDomains(stdin, stdinFormat())
+	}
+
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	var domains []string
-	scanner := bufio.NewScanner(file)
+	format := fileFormat()
+	if format == "" {
+		format = input.DetectFormat(compress.TrimExtension(filename))
+	}
+	return readDomains(file, format)
+}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		// Skip empty lines and comments
-		if line != "" && !strings.HasPrefix(line, "#") {
-			domains = append(domains, line)
+// readDomains reads the domains in r with their notes, decompressing gzip
+// and zstd data. With --extract-domains, the input is free text and the
+// registrable domains mentioned in it are returned instead, without notes;
+// for CSV and Excel files, only the --column column is searched.
+func readDomains(compressed io.Reader, format input.Format) ([]input.Entry, error) {
+	r, err := compress.NewReader(compressed)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	if !extractDomains {
+		return input.ReadEntries(r, format, domainsColumn)
+	}
+	if format == input.FormatText {
+		text, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
 		}
+		return domainEntries(domain.ExtractDomains(string(text))), nil
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
+	values, err := input.Read(r, format, domainsColumn)
+	if err != nil {
+		return nil, err
 	}
+	return domainEntries(domain.ExtractDomains(strings.Join(values, "\n"))), nil
+}
 
-	return domains, nil
+// domainEntries returns entries without notes for domains
+func domainEntries(domains []string) []input.Entry {
+	entries := make([]input.Entry, len(domains))
+	for i, name := range domains {
+		entries[i] = input.Entry{Domain: name}
+	}
+	return entries
+}
+
+// domainNotes attaches the notes given next to domains in the input list to
+// their results. Results are annotated as each check completes, so the ones
+// published while the run is in progress carry their note too.
+type domainNotes struct {
+	domain.NopHooks
+
+	notes map[string]string
+}
+
+// newDomainNotes returns the notes of entries, or nil when none has a note
+func newDomainNotes(entries []input.Entry) *domainNotes {
+	notes := make(map[string]string)
+	for _, entry := range entries {
+		if entry.Note != "" {
+			name, _ := domain.NormalizeDomain(entry.Domain)
+			notes[noteKey(name)] = entry.Note
+		}
+	}
+	if len(notes) == 0 {
+		return nil
+	}
+	return &domainNotes{notes: notes}
+}
+
+func (n *domainNotes) OnCheckComplete(ctx context.Context, result *domain.AvailabilityResult, err error) {
+	n.annotate(result)
+}
+
+// annotate sets the note of each result that has one
+func (n *domainNotes) annotate(results ...*domain.AvailabilityResult) {
+	if n == nil {
+		return
+	}
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		if note, ok := n.notes[noteKey(result.Domain)]; ok {
+			result.Note = note
+		}
+	}
+}
+
+// noteKey returns the key of a domain's note, which ignores case and the
+// form of internationalized names
+func noteKey(name string) string {
+	return strings.ToLower(historyKey(name))
+}
+
+// stdinPiped reports whether stdin is a pipe or file rather than a terminal,
+// so domains can be read from it without waiting for someone to type them
+func stdinPiped() bool {
+	file, ok := stdin.(*os.File)
+	if !ok {
+		return stdin != nil
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
 }
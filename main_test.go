@@ -0,0 +1,3762 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/audit"
+	"github.com/abakermi/r53check/internal/aws"
+	"github.com/abakermi/r53check/internal/budget"
+	"github.com/abakermi/r53check/internal/config"
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/generate"
+	"github.com/abakermi/r53check/internal/history"
+	"github.com/abakermi/r53check/internal/notify"
+	"github.com/abakermi/r53check/internal/pricing"
+	"github.com/abakermi/r53check/internal/register"
+	"github.com/abakermi/r53check/internal/reporting"
+	"github.com/abakermi/r53check/internal/sink"
+	"github.com/abakermi/r53check/internal/storage"
+	"github.com/abakermi/r53check/internal/update"
+	"github.com/abakermi/r53check/pkg/r53checktest"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	bedrocktypes "github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/getsentry/sentry-go"
+	"github.com/klauspost/compress/zstd"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// resetFlags restores every flag of cmd and its subcommands to its default,
+// since flag variables are package globals shared between runs
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			slice.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.PersistentFlags().VisitAll(reset)
+	cmd.Flags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// runCLI executes the command line against client with isolated storage
+// directories and returns the exit code and captured output
+func runCLI(t *testing.T, client *r53checktest.Client, args ...string) (int, string, string) {
+	t.Helper()
+	return runCLIWithInput(t, client, "", args...)
+}
+
+// runCLIWithInput is runCLI with input piped to stdin
+func runCLIWithInput(t *testing.T, client *r53checktest.Client, input string, args ...string) (int, string, string) {
+	t.Helper()
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("LC_ALL", "C")
+	stdin = strings.NewReader(input)
+
+	original := newAWSClient
+	newAWSClient = func(ctx context.Context) (route53Client, error) {
+		return client, nil
+	}
+	t.Cleanup(func() { newAWSClient = original })
+
+	resetFlags(rootCmd)
+
+	var stdout, stderr bytes.Buffer
+	code := execute(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCheckCommand(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		Failing("denied.com", r53checktest.AccessDenied()).
+		TLDs("com", "io").
+		Client()
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCode customErrors.ExitCode
+		stdout       string
+		stderr       string
+	}{
+		{"available", []string{"check", "free.com"}, customErrors.ExitSuccess, "free.com is AVAILABLE", ""},
+		{"unavailable", []string{"check", "taken.com"}, customErrors.ExitSuccess, "taken.com is UNAVAILABLE", ""},
+		{"invalid domain", []string{"check", "bad-.com"}, customErrors.ExitValidation, "", "Domain Validation Error"},
+		{"unsupported TLD", []string{"check", "example.xyz"}, customErrors.ExitValidation, "", "unsupported TLD"},
+		{"mistyped TLD", []string{"check", "example.con"}, customErrors.ExitValidation, "", "unsupported TLD: .con (did you mean .com?)"},
+		{"mistyped flag", []string{"check", "--outptu", "json", "free.com"}, customErrors.ExitSystemError, "", "unknown flag: --outptu (did you mean --output?)"},
+		{"mistyped flag value", []string{"check", "--output", "jsn", "free.com"}, customErrors.ExitValidation, "", `invalid output format "jsn": must be one of text, json (did you mean "json"?)`},
+		{"access denied", []string{"check", "denied.com"}, customErrors.ExitAuthorization, "", "Error"},
+		{"missing argument", []string{"check"}, customErrors.ExitSystemError, "", "accepts 1 arg(s)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, client, tt.args...)
+
+			if code != int(tt.expectedCode) {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.expectedCode, code, stderr)
+			}
+			if !strings.Contains(stdout, tt.stdout) {
+				t.Errorf("Expected stdout to contain %q, got %q", tt.stdout, stdout)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.stderr, stderr)
+			}
+		})
+	}
+}
+
+func TestCheckCommand_Prompt(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		TLDs("com").
+		Client()
+
+	original := stdinTerminal
+	stdinTerminal = func() bool { return true }
+	t.Cleanup(func() { stdinTerminal = original })
+
+	tests := []struct {
+		name         string
+		input        string
+		expectedCode customErrors.ExitCode
+		stdout       string
+		stderr       []string
+	}{
+		{"valid domain", "free.com\n", customErrors.ExitSuccess, "free.com is AVAILABLE", []string{"Domain to check: "}},
+		{"normalized entry", "https://www.free.com/\n", customErrors.ExitSuccess, "free.com is AVAILABLE", nil},
+		{"asks again after invalid entries", "\nbad-.com\nexample.con\nfree.com\n", customErrors.ExitSuccess, "free.com is AVAILABLE",
+			[]string{"✗ label cannot start or end with hyphen", "✗ unsupported TLD: .con (did you mean .com?)"}},
+		{"no domain entered", "example.con\n", customErrors.ExitValidation, "", []string{"no domain entered"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLIWithInput(t, client, tt.input, "check")
+			if code != int(tt.expectedCode) {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.expectedCode, code, stderr)
+			}
+			if !strings.Contains(stdout, tt.stdout) {
+				t.Errorf("Expected stdout to contain %q, got %q", tt.stdout, stdout)
+			}
+			for _, want := range tt.stderr {
+				if !strings.Contains(stderr, want) {
+					t.Errorf("Expected stderr to contain %q, got %q", want, stderr)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenFlag(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com", "a.com", "b.com", "c.com", "d.com", "e.com", "f.com").
+		Unavailable("taken.com").
+		TLDs("com").
+		Client()
+
+	var opened []string
+	var openErr error
+	originalOpen, originalTerminal := openBrowser, isTerminal
+	openBrowser = func(url string) error {
+		if openErr != nil {
+			return openErr
+		}
+		opened = append(opened, url)
+		return nil
+	}
+	terminal := true
+	isTerminal = func(w io.Writer) bool { return terminal && w == stdout }
+	t.Cleanup(func() { openBrowser, isTerminal = originalOpen, originalTerminal })
+
+	freeURL := "https://console.aws.amazon.com/route53/domains/home#/DomainSearch?domainName=free.com"
+
+	tests := []struct {
+		name     string
+		args     []string
+		terminal bool
+		openErr  error
+		opened   int
+		stderr   []string
+	}{
+		{"available", []string{"check", "free.com", "--open"}, true, nil, 1, nil},
+		{"unavailable", []string{"check", "taken.com", "--open"}, true, nil, 0, nil},
+		{"without --open", []string{"check", "free.com"}, true, nil, 0, nil},
+		{"not a terminal", []string{"check", "free.com", "--open"}, false, nil, 0, []string{"Register free.com: " + freeURL}},
+		{"browser fails", []string{"check", "free.com", "--open"}, true, errors.New("xdg-open not found"), 0,
+			[]string{"Unable to open a browser: xdg-open not found", "Register free.com: " + freeURL}},
+		{"bulk opens the first pages", []string{"bulk", "--open", "a.com", "b.com", "taken.com", "c.com", "d.com", "e.com", "f.com", "free.com"}, true, nil, maxOpenPages,
+			[]string{"Register f.com: ", "Register free.com: "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opened, openErr, terminal = nil, tt.openErr, tt.terminal
+			code, stdout, stderr := runCLI(t, client, tt.args...)
+			if code != int(customErrors.ExitSuccess) && code != int(customErrors.ExitPartialFailure) {
+				t.Fatalf("Unexpected exit code %d (stderr: %s)", code, stderr)
+			}
+			if len(opened) != tt.opened {
+				t.Errorf("Expected %d pages opened, got %v", tt.opened, opened)
+			}
+			if tt.opened == 1 && opened[0] != freeURL {
+				t.Errorf("Expected %s to be opened, got %s", freeURL, opened[0])
+			}
+			for _, want := range tt.stderr {
+				if !strings.Contains(stderr, want) {
+					t.Errorf("Expected stderr to contain %q, got %q", want, stderr)
+				}
+			}
+			if len(tt.stderr) == 0 && strings.Contains(stderr, "Register") {
+				t.Errorf("Expected no URLs printed, got %q", stderr)
+			}
+			if strings.Contains(stdout, "Register") {
+				t.Errorf("Expected stdout to hold only the results, got %q", stdout)
+			}
+		})
+	}
+}
+
+func TestCheckCommand_FailOn(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		Reserved("reserved.com").
+		Failing("denied.com", r53checktest.AccessDenied()).
+		TLDs("com").
+		Client()
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCode customErrors.ExitCode
+		stderr       string
+	}{
+		{"unavailable succeeds by default", []string{"check", "taken.com"}, customErrors.ExitSuccess, ""},
+		{"fail on unavailable", []string{"check", "--fail-on", "unavailable,reserved", "taken.com"}, customErrors.ExitPartialFailure, "taken.com is unavailable"},
+		{"fail on reserved", []string{"check", "--fail-on", "unavailable,reserved", "reserved.com"}, customErrors.ExitPartialFailure, "reserved.com is reserved"},
+		{"available passes", []string{"check", "--fail-on", "unavailable,reserved", "free.com"}, customErrors.ExitSuccess, ""},
+		{"fail on available", []string{"check", "--fail-on", "available", "free.com"}, customErrors.ExitPartialFailure, "free.com is available"},
+		{"errors fail by default", []string{"check", "denied.com"}, customErrors.ExitAuthorization, "Error"},
+		{"errors fail when listed", []string{"check", "--fail-on", "unavailable,error", "denied.com"}, customErrors.ExitAuthorization, "Error"},
+		{"errors reported when not listed", []string{"check", "--fail-on", "unavailable", "denied.com"}, customErrors.ExitSuccess, "Error"},
+		{"invalid value", []string{"check", "--fail-on", "taken", "free.com"}, customErrors.ExitValidation, "invalid --fail-on value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(t, client, tt.args...)
+			if code != int(tt.expectedCode) {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.expectedCode, code, stderr)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.stderr, stderr)
+			}
+		})
+	}
+}
+
+func TestDefaultCommand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"example.com"}, []string{"check", "example.com"}},
+		{[]string{"--price", "-o", "json", "example.com"}, []string{"check", "--price", "-o", "json", "example.com"}},
+		{[]string{"example.com", "--fail-on", "available"}, []string{"check", "example.com", "--fail-on", "available"}},
+		{[]string{"--timeout=5s", "a.com", "b.io"}, []string{"bulk", "--timeout=5s", "a.com", "b.io"}},
+		{[]string{"--fail-on-error", "a.com", "b.io"}, []string{"bulk", "--fail-on-error", "a.com", "b.io"}},
+		{[]string{"a.com", "--out-file", "results.json"}, []string{"check", "a.com", "--out-file", "results.json"}},
+		{[]string{"https://www.example.com/path"}, []string{"check", "https://www.example.com/path"}},
+		{[]string{"check", "example.com"}, []string{"check", "example.com"}},
+		{[]string{"--region", "eu.west", "bulk", "a.com"}, []string{"--region", "eu.west", "bulk", "a.com"}},
+		{[]string{"chek", "example.com"}, []string{"chek", "example.com"}},
+		{[]string{"help"}, []string{"help"}},
+		{[]string{"--verbose"}, []string{"--verbose"}},
+		{nil, nil},
+	}
+
+	for _, tt := range tests {
+		if got := defaultCommand(tt.args); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("defaultCommand(%q) = %q, expected %q", tt.args, got, tt.expected)
+		}
+	}
+}
+
+func TestDefaultCommand_Run(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		TLDs("com").
+		Client()
+
+	code, stdout, stderr := runCLI(t, client, "free.com")
+	if code != int(customErrors.ExitSuccess) || !strings.Contains(stdout, "free.com is AVAILABLE") {
+		t.Errorf("Expected a single check, got exit code %d: %s%s", code, stdout, stderr)
+	}
+
+	code, stdout, stderr = runCLI(t, client, "--fail-on-unavailable", "free.com", "taken.com")
+	if code != int(customErrors.ExitPartialFailure) || !strings.Contains(stdout, "Bulk Domain Check Results (2 domains)") {
+		t.Errorf("Expected a bulk check, got exit code %d: %s%s", code, stdout, stderr)
+	}
+
+	code, _, stderr = runCLI(t, client, "chek", "free.com")
+	if code == int(customErrors.ExitSuccess) || !strings.Contains(stderr, `unknown command "chek"`) {
+		t.Errorf("Expected a mistyped command to fail, got exit code %d: %s", code, stderr)
+	}
+}
+
+func TestPricingFlag(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com", "spare.com").
+		Price("com", r53checktest.Price{Registration: 14, Renewal: 16, Transfer: 12}).
+		TLDs("com").
+		Client()
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"check", []string{"check", "--pricing", "free.com"}},
+		{"bulk", []string{"bulk", "--pricing", "free.com", "spare.com"}},
+		{"price spelling", []string{"--price", "bulk", "free.com", "spare.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, client, tt.args...)
+			if code != int(customErrors.ExitSuccess) {
+				t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+			}
+			for _, expected := range []string{"Registration: $14.00 USD", "Renewal: $16.00 USD", "Transfer: $12.00 USD"} {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("Expected output to contain %q, got %q", expected, stdout)
+				}
+			}
+		})
+	}
+}
+
+func TestYearsFlag(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com", "spare.com").
+		Unavailable("taken.com").
+		Price("com", r53checktest.Price{Registration: 14, Renewal: 16, Transfer: 12}).
+		TLDs("com").
+		Client()
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCode customErrors.ExitCode
+		stdout       []string
+		stderr       string
+	}{
+		{"check", []string{"check", "--years", "3", "free.com"}, customErrors.ExitSuccess,
+			[]string{"Registration: $14.00 USD", "Cost over 3 years: $46.00 USD"}, ""},
+		{"bulk", []string{"--years", "2", "bulk", "free.com", "spare.com", "taken.com"}, customErrors.ExitSuccess,
+			[]string{"Cost over 2 years: $30.00 USD", "$ Cost over 2 years: $60.00 USD for 2 available"}, ""},
+		{"json", []string{"--years", "2", "-o", "json", "bulk", "free.com", "taken.com"}, customErrors.ExitSuccess,
+			[]string{`"cost": 30`, `"costs": [`, `"total": 30`}, ""},
+		{"negative", []string{"check", "--years", "-1", "free.com"}, customErrors.ExitValidation,
+			nil, "--years must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, client, tt.args...)
+			if code != int(tt.expectedCode) {
+				t.Fatalf("Expected exit code %d, got %d (stderr: %s)", tt.expectedCode, code, stderr)
+			}
+			for _, expected := range tt.stdout {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("Expected stdout to contain %q, got %q", expected, stdout)
+				}
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.stderr, stderr)
+			}
+		})
+	}
+}
+
+func TestAlternatives(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Default(types.DomainAvailabilityUnavailable).
+		Available("brand.io", "free.com").
+		Price("io", r53checktest.Price{Registration: 39, Renewal: 39, Transfer: 39}).
+		TLDs("com", "io", "dev").
+		Client()
+
+	code, stdout, stderr := runCLI(t, client, "check", "brand.com", "--alternatives", "io,dev")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "↪ Available instead: brand.io ($39.00 USD)") || strings.Contains(stdout, "brand.dev") {
+		t.Errorf("Expected only the available alternative with its price, got %q", stdout)
+	}
+
+	code, stdout, stderr = runCLI(t, client, "-o", "json", "bulk", "--alternatives", "io", "brand.com", "free.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	var result struct {
+		Results []struct {
+			Domain       string `json:"domain"`
+			Alternatives []struct {
+				Domain  string `json:"domain"`
+				Pricing *struct {
+					Registration float64 `json:"registration"`
+				} `json:"pricing"`
+			} `json:"alternatives"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+	if len(result.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(result.Results))
+	}
+	alternatives := result.Results[0].Alternatives
+	if len(alternatives) != 1 || alternatives[0].Domain != "brand.io" || alternatives[0].Pricing == nil || alternatives[0].Pricing.Registration != 39 {
+		t.Errorf("Expected brand.io with its price as the alternative of brand.com, got %+v", alternatives)
+	}
+	if len(result.Results[1].Alternatives) != 0 {
+		t.Errorf("Expected no alternatives for an available domain, got %+v", result.Results[1].Alternatives)
+	}
+}
+
+func TestGenerateCommand(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Default(types.DomainAvailabilityUnavailable).
+		Available("getexample.com", "example-app.io").
+		TLDs("com", "io").
+		Client()
+
+	code, stdout, stderr := runCLI(t, client, "generate", "example.com", "--prefixes", "get,try", "--suffixes", "hq")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "✓ getexample.com") {
+		t.Errorf("Expected the available variant to be listed, got %q", stdout)
+	}
+	for _, name := range []string{"tryexample.com", "examplehq.com"} {
+		if strings.Contains(stdout, name) {
+			t.Errorf("Expected unavailable %s to be left out, got %q", name, stdout)
+		}
+	}
+
+	code, stdout, stderr = runCLI(t, client, "generate", "example.io", "--prefixes", "", "--suffixes", "app", "--hyphens", "--list")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if stdout != "example.io\nexampleapp.io\nexample-app.io\n" {
+		t.Errorf("Expected the generated names, got %q", stdout)
+	}
+
+	code, stdout, stderr = runCLI(t, client, "-o", "json", "generate", "example", "--prefixes", "get", "--suffixes", "", "--all")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	var result struct {
+		Results []struct {
+			Domain string `json:"domain"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+	if len(result.Results) != 2 || result.Results[0].Domain != "example.com" || result.Results[1].Domain != "getexample.com" {
+		t.Errorf("Expected every generated name with --all, got %+v", result.Results)
+	}
+}
+
+func TestMaxPrice(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Default(types.DomainAvailabilityUnavailable).
+		Available("quickcar.com", "quickcar.io", "fastcar.app").
+		Price("com", r53checktest.Price{Registration: 14, Renewal: 14, Transfer: 14}).
+		Price("io", r53checktest.Price{Registration: 39, Renewal: 39, Transfer: 39}).
+		TLDs("com", "io", "app").
+		Client()
+
+	thesaurus := filepath.Join(t.TempDir(), "thesaurus.txt")
+	if err := os.WriteFile(thesaurus, []byte("fast, quick\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := runCLI(t, client, "--verbose", "suggest", "--synonyms", "fast", "car", "--tld", "com,io,app", "--thesaurus", thesaurus, "--max-price", "25")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "✓ quickcar.com") || strings.Contains(stdout, "quickcar.io") || strings.Contains(stdout, "fastcar.app") {
+		t.Errorf("Expected only the available name within the price, got %q", stdout)
+	}
+	if !strings.Contains(stderr, "Skipping 4 domains under TLDs costing more than $25.00") {
+		t.Errorf("Expected the names under expensive and unpriced TLDs to be skipped, got %q", stderr)
+	}
+
+	code, _, _ = runCLI(t, client, "variants", "example.com", "--max-price", "-1")
+	if code != int(customErrors.ExitValidation) {
+		t.Errorf("Expected validation exit code for a negative price, got %d", code)
+	}
+}
+
+func TestSuggestCommand(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Default(types.DomainAvailabilityUnavailable).
+		Available("quickcar.io").
+		TLDs("com", "io").
+		Client()
+
+	thesaurus := filepath.Join(t.TempDir(), "thesaurus.txt")
+	if err := os.WriteFile(thesaurus, []byte("fast, quick\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := runCLI(t, client, "suggest", "--synonyms", "fast", "car", "--tld", "com,io", "--thesaurus", thesaurus, "--list")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if stdout != "fastcar.com\nfastcar.io\nquickcar.com\nquickcar.io\n" {
+		t.Errorf("Expected the combinations, got %q", stdout)
+	}
+
+	code, stdout, stderr = runCLI(t, client, "suggest", "--synonyms", "fast", "car", "--tld", "com,io", "--thesaurus", thesaurus)
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "✓ quickcar.io") || strings.Contains(stdout, "fastcar") {
+		t.Errorf("Expected only the available combination, got %q", stdout)
+	}
+
+	code, _, stderr = runCLI(t, client, "suggest", "car")
+	if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "--synonyms") {
+		t.Errorf("Expected a validation error without keywords, got exit code %d (stderr: %s)", code, stderr)
+	}
+}
+
+func TestGenerateCommand_Constraints(t *testing.T) {
+	client := r53checktest.NewScenario().TLDs("com").Client()
+
+	code, stdout, stderr := runCLI(t, client, "generate", "example", "--prefixes", "get,4,getting", "--suffixes", "xtrk,hq", "--hyphens",
+		"--max-length", "12", "--letter-first", "--no-hyphens", "--pronounceable", "--list")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if stdout != "example.com\ngetexample.com\nexamplehq.com\n" {
+		t.Errorf("Expected only the names meeting the constraints, got %q", stdout)
+	}
+
+	code, _, stderr = runCLI(t, client, "generate", "example", "--max-length", "3")
+	if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "constraints") {
+		t.Errorf("Expected a validation error when no name meets the constraints, got exit code %d (stderr: %s)", code, stderr)
+	}
+}
+
+func TestGenerateCommand_WordLists(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Default(types.DomainAvailabilityUnavailable).
+		Available("swiftfox.io", "swift-fox.io", "boldfox.dev").
+		Price("io", r53checktest.Price{Registration: 39, Renewal: 39, Transfer: 39}).
+		Price("dev", r53checktest.Price{Registration: 12, Renewal: 12, Transfer: 12}).
+		TLDs("io", "dev").
+		Client()
+
+	dir := t.TempDir()
+	adjectives := filepath.Join(dir, "adjectives.txt")
+	nouns := filepath.Join(dir, "nouns.txt")
+	if err := os.WriteFile(adjectives, []byte("# adjectives\nSwift\nbold\nswift\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(nouns, []byte("fox\n\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := runCLI(t, client, "generate", "--list1", adjectives, "--list2", nouns, "--tld", "io", "--separators", "-", "--list")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if stdout != "swiftfox.io\nswift-fox.io\nboldfox.io\nbold-fox.io\n" {
+		t.Errorf("Expected the deduplicated cross product, got %q", stdout)
+	}
+
+	code, stdout, stderr = runCLI(t, client, "generate", "--list1", adjectives, "--list2", nouns, "--tld", "io,dev", "--max-price", "20")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "✓ boldfox.dev") || strings.Contains(stdout, "swiftfox.io") {
+		t.Errorf("Expected only the available name within the price, got %q", stdout)
+	}
+
+	tests := [][]string{
+		{"generate", "example", "--list1", adjectives, "--list2", nouns},
+		{"generate", "--list1", adjectives},
+		{"generate", "example", "--tld", "io"},
+		{"generate"},
+	}
+	for _, args := range tests {
+		if code, _, _ := runCLI(t, client, args...); code != int(customErrors.ExitValidation) {
+			t.Errorf("Expected validation exit code for %v, got %d", args, code)
+		}
+	}
+}
+
+// scriptedBedrock replies to every Converse call with reply
+type scriptedBedrock struct {
+	reply  string
+	region string
+}
+
+func (b *scriptedBedrock) Converse(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+	return &bedrockruntime.ConverseOutput{
+		Output: &bedrocktypes.ConverseOutputMemberMessage{Value: bedrocktypes.Message{
+			Role:    bedrocktypes.ConversationRoleAssistant,
+			Content: []bedrocktypes.ContentBlock{&bedrocktypes.ContentBlockMemberText{Value: b.reply}},
+		}},
+	}, nil
+}
+
+func TestSuggestCommand_AI(t *testing.T) {
+	api := &scriptedBedrock{reply: "1. Pipewise\n2. BuildLens\n"}
+	original := newBedrockAPI
+	newBedrockAPI = func(ctx context.Context, region string) (generate.BedrockAPI, error) {
+		api.region = region
+		return api, nil
+	}
+	t.Cleanup(func() { newBedrockAPI = original })
+
+	client := r53checktest.NewScenario().
+		Default(types.DomainAvailabilityUnavailable).
+		Available("pipewise.dev").
+		TLDs("com", "dev").
+		Client()
+
+	code, _, stderr := runCLI(t, client, "suggest", "--ai", "a CI/CD analytics startup")
+	if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "ai.model") {
+		t.Errorf("Expected AI suggestions to be off without ai.model, got exit code %d (stderr: %s)", code, stderr)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("ai:\n  model: test-model\n  region: us-west-2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := runCLI(t, client, "--config", configPath, "suggest", "--ai", "a CI/CD analytics startup", "--tld", "com,dev")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "✓ pipewise.dev") || strings.Contains(stdout, "buildlens") {
+		t.Errorf("Expected only the available suggestion, got %q", stdout)
+	}
+	if api.region != "us-west-2" {
+		t.Errorf("Expected the configured region, got %q", api.region)
+	}
+
+	code, _, stderr = runCLI(t, client, "--config", configPath, "suggest", "--ai", "startup", "--synonyms", "fast")
+	if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "not both") {
+		t.Errorf("Expected --ai and --synonyms to be exclusive, got exit code %d (stderr: %s)", code, stderr)
+	}
+}
+
+func TestVariantsCommand(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Default(types.DomainAvailabilityUnavailable).
+		Available("examples.com").
+		TLDs("com").
+		Client()
+
+	code, stdout, stderr := runCLI(t, client, "variants", "example.com", "--kinds", "plural", "--list")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if stdout != "examples.com, plural\n" {
+		t.Errorf("Expected the plural variant with its kind, got %q", stdout)
+	}
+
+	code, stdout, stderr = runCLI(t, client, "-o", "json", "variants", "example.com", "--kinds", "plural,transposition", "--all")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	var result struct {
+		Results []struct {
+			Domain    string `json:"domain"`
+			Available bool   `json:"available"`
+			Note      string `json:"note"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+	notes := make(map[string]string)
+	for _, r := range result.Results {
+		notes[r.Domain] = r.Note
+	}
+	if notes["examples.com"] != "plural" || notes["exmaple.com"] != "transposition" {
+		t.Errorf("Expected the variants noted with their kinds, got %+v", result.Results)
+	}
+
+	code, _, stderr = runCLI(t, client, "variants", "example.com", "--kinds", "typo")
+	if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "unknown kind") {
+		t.Errorf("Expected a validation error for an unknown kind, got exit code %d (stderr: %s)", code, stderr)
+	}
+}
+
+func TestCheckCommand_Timeout(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Slow("slow.com", time.Second, types.DomainAvailabilityAvailable).
+		TLDs("com").
+		Client()
+
+	code, _, stderr := runCLI(t, client, "--timeout", "50ms", "-o", "json", "check", "slow.com")
+	if code != int(customErrors.ExitTimeout) {
+		t.Errorf("Expected exit code %d, got %d (stderr: %s)", customErrors.ExitTimeout, code, stderr)
+	}
+	if !strings.Contains(stderr, `"category": "TIMEOUT"`) || !strings.Contains(stderr, "timed out after 50ms") {
+		t.Errorf("Expected a timeout error, got %q", stderr)
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Failing("denied.com", r53checktest.AccessDenied()).
+		TLDs("com").
+		Client()
+
+	tests := []struct {
+		name     string
+		args     []string
+		category string
+		exitCode customErrors.ExitCode
+	}{
+		{"access denied", []string{"--output", "json", "check", "denied.com"}, "AUTHORIZATION", customErrors.ExitAuthorization},
+		{"invalid domain", []string{"-o", "json", "check", "bad-.com"}, "VALIDATION", customErrors.ExitValidation},
+		{"missing argument", []string{"--output", "json", "check"}, "SYSTEM", customErrors.ExitSystemError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(t, client, tt.args...)
+			if code != int(tt.exitCode) {
+				t.Errorf("Expected exit code %d, got %d", tt.exitCode, code)
+			}
+
+			var decoded struct {
+				Error struct {
+					Category string `json:"category"`
+					Message  string `json:"message"`
+					ExitCode int    `json:"exit_code"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal([]byte(stderr), &decoded); err != nil {
+				t.Fatalf("Expected a JSON error on stderr, got %q: %v", stderr, err)
+			}
+			if decoded.Error.Category != tt.category || decoded.Error.ExitCode != int(tt.exitCode) || decoded.Error.Message == "" {
+				t.Errorf("Unexpected error output: %+v", decoded.Error)
+			}
+		})
+	}
+}
+
+func TestBulkCommand(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		TLDs("com").
+		Client()
+
+	code, stdout, stderr := runCLI(t, client, "bulk", "free.com", "taken.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "free.com") || !strings.Contains(stdout, "taken.com") {
+		t.Errorf("Expected both domains in the output, got %q", stdout)
+	}
+
+	code, _, stderr = runCLI(t, client, "bulk")
+	if code != int(customErrors.ExitValidation) {
+		t.Errorf("Expected validation exit code without domains, got %d", code)
+	}
+	if !strings.Contains(stderr, "no domains provided") {
+		t.Errorf("Expected a missing domains error, got %q", stderr)
+	}
+
+	code, _, _ = runCLI(t, client, "bulk", "--order", "random", "free.com")
+	if code != int(customErrors.ExitValidation) {
+		t.Errorf("Expected validation exit code for an invalid order, got %d", code)
+	}
+}
+
+func TestBulkCommand_SortPrice(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com", "free.io", "free.dev").
+		Unavailable("taken.com").
+		Price("com", r53checktest.Price{Registration: 14, Renewal: 16}).
+		Price("io", r53checktest.Price{Registration: 40, Renewal: 40}).
+		Price("dev", r53checktest.Price{Registration: 12, Renewal: 12}).
+		TLDs("com", "io", "dev").
+		Client()
+
+	code, stdout, stderr := runCLI(t, client, "bulk", "--sort", "price", "free.io", "taken.com", "free.com", "free.dev")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	dev, com, io, taken := strings.Index(stdout, "free.dev:"), strings.Index(stdout, "free.com:"), strings.Index(stdout, "free.io:"), strings.Index(stdout, "taken.com:")
+	if dev < 0 || !(dev < com && com < io && io < taken) {
+		t.Errorf("Expected the available domains cheapest first, then the unavailable one, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "Registration: $12.00 USD") {
+		t.Errorf("Expected --sort price to include prices, got %q", stdout)
+	}
+
+	code, stdout, stderr = runCLI(t, client, "bulk", "--order", "price", "--max-price", "20", "free.io", "free.com", "free.dev")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if strings.Contains(stdout, "free.io") || !strings.Contains(stdout, "free.dev") || !strings.Contains(stdout, "free.com") {
+		t.Errorf("Expected only the domains within the price, got %q", stdout)
+	}
+	if client.CallCount("free.io") != 1 {
+		t.Errorf("Expected the domain over --max-price not to be checked again, got %d calls", client.CallCount("free.io"))
+	}
+
+	code, _, stderr = runCLI(t, client, "bulk", "--max-price", "-5", "free.com")
+	if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "must not be negative") {
+		t.Errorf("Expected a negative --max-price to be rejected, got exit code %d: %s", code, stderr)
+	}
+}
+
+func TestBulkCommand_CSVFile(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		TLDs("com").
+		Client()
+
+	path := filepath.Join(t.TempDir(), "candidates.csv")
+	if err := os.WriteFile(path, []byte("Brand,Website\nFree,free.com\nTaken,taken.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := runCLI(t, client, "bulk", "--file", path, "--column", "website")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "free.com: AVAILABLE") || !strings.Contains(stdout, "taken.com: UNAVAILABLE") || strings.Contains(stdout, "Website") {
+		t.Errorf("Expected the domains of the Website column, got %q", stdout)
+	}
+
+	code, _, stderr = runCLI(t, client, "bulk", "--file", path, "--column", "domain")
+	if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, `column "domain" not found`) {
+		t.Errorf("Expected a missing column error, got exit code %d: %s", code, stderr)
+	}
+
+	code, _, stderr = runCLI(t, client, "bulk", "--column", "website", "free.com")
+	if code != int(customErrors.ExitValidation) {
+		t.Errorf("Expected a validation error for --column without --file, got exit code %d: %s", code, stderr)
+	}
+}
+
+func TestBulkCommand_ZoneFile(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("dangling.com").
+		Unavailable("example.com", "dns-host.net").
+		TLDs("com", "net").
+		Client()
+
+	zone := "$ORIGIN example.com.\n@\tIN\tNS\tns1.dns-host.net.\nwww\tIN\tCNAME\tcdn.dangling.com.\nmail\tIN\tA\t192.0.2.1\n"
+	path := filepath.Join(t.TempDir(), "example.db")
+	if err := os.WriteFile(path, []byte(zone), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := runCLI(t, client, "bulk", "--file", path, "--input-format", "zonefile")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "(3 domains)") || !strings.Contains(stdout, "dangling.com: AVAILABLE\n  ✎ CNAME target of www.example.com\n") {
+		t.Errorf("Expected the registrable domains of the zone, got %q", stdout)
+	}
+
+	code, stdout, stderr = runCLIWithInput(t, client, zone, "bulk", "--input-format", "ZoneFile", "-")
+	if code != int(customErrors.ExitSuccess) || !strings.Contains(stdout, "(3 domains)") {
+		t.Errorf("Expected the zone to be read from stdin, got exit code %d: %s%s", code, stdout, stderr)
+	}
+
+	code, _, stderr = runCLI(t, client, "bulk", "--file", path, "--input-format", "bind")
+	if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "invalid input format") {
+		t.Errorf("Expected an invalid input format error, got exit code %d: %s", code, stderr)
+	}
+}
+
+func TestBulkCommand_MultipleFiles(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com", "extra.com").
+		Unavailable("taken.com").
+		TLDs("com").
+		Client()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"lists/a.txt":   "free.com\ntaken.com\n",
+		"lists/b.txt":   "Taken.com\nfree.com\n",
+		"lists/c.csv":   "domain\nfree.com\n",
+		"extra.txt":     "extra.com\nfree.com\n",
+		"unrelated.txt": "other.com\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	code, stdout, stderr := runCLI(t, client, "--verbose", "bulk",
+		"--file", filepath.Join(dir, "lists", "*.txt"), "--file", filepath.Join(dir, "extra.txt"))
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "(3 domains)") || strings.Contains(stdout, "other.com") {
+		t.Errorf("Expected the 3 distinct domains of the matched files, got %q", stdout)
+	}
+	for _, expected := range []string{
+		"Read 2 domains from " + filepath.Join(dir, "lists", "a.txt") + "\n",
+		"Read 0 domains from " + filepath.Join(dir, "lists", "b.txt") + " (2 already listed)\n",
+		"Read 1 domain from " + filepath.Join(dir, "extra.txt") + " (1 already listed)\n",
+	} {
+		if !strings.Contains(stderr, expected) {
+			t.Errorf("Expected verbose output to contain %q, got %q", expected, stderr)
+		}
+	}
+
+	code, _, stderr = runCLI(t, client, "bulk", "--file", filepath.Join(dir, "missing", "*.txt"))
+	if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "no files match") {
+		t.Errorf("Expected an error for a pattern without matches, got exit code %d: %s", code, stderr)
+	}
+}
+
+func TestBulkCommand_Notes(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		TLDs("com").
+		Client()
+
+	file := filepath.Join(t.TempDir(), "shortlist.txt")
+	if err := os.WriteFile(file, []byte("free.com, client preference #1\nTaken.com,backup\nspare.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := runCLI(t, client, "bulk", "--file", file)
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "✎ client preference #1") || !strings.Contains(stdout, "✎ backup") {
+		t.Errorf("Expected the notes in the output, got %q", stdout)
+	}
+
+	code, stdout, stderr = runCLI(t, client, "-o", "json", "bulk", "--file", file)
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	var result struct {
+		Results []struct {
+			Domain string `json:"domain"`
+			Note   string `json:"note"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+	notes := make(map[string]string)
+	for _, r := range result.Results {
+		notes[strings.ToLower(r.Domain)] = r.Note
+	}
+	expected := map[string]string{"free.com": "client preference #1", "taken.com": "backup", "spare.com": ""}
+	if !reflect.DeepEqual(notes, expected) {
+		t.Errorf("Expected notes %v, got %v", expected, notes)
+	}
+}
+
+func TestBulkCommand_ExtractDomains(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		TLDs("com").
+		Client()
+	text := `<p>Try <a href="https://www.free.com/start">free.com</a> or mail hello@taken.com.</p>
+<img src="banner.png"> 127.0.0.1 - GET /index.html https://shop.free.com/`
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "page.html")
+	if err := os.WriteFile(file, []byte(text), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		args  []string
+	}{
+		{"file", "", []string{"bulk", "--extract-domains", "--file", file}},
+		{"stdin", text, []string{"bulk", "--extract-domains", "-"}},
+		{"arguments", "", []string{"bulk", "--extract-domains", "https://www.free.com/start", "hello@taken.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLIWithInput(t, client, tt.input, tt.args...)
+			if code != int(customErrors.ExitSuccess) {
+				t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+			}
+			for _, expected := range []string{"(2 domains)", "free.com", "taken.com"} {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("Expected output to contain %q, got %q", expected, stdout)
+				}
+			}
+		})
+	}
+}
+
+func TestBulkCommand_LimitAndSample(t *testing.T) {
+	names := []string{"a.com", "b.com", "c.com", "d.com", "e.com", "f.com"}
+	client := r53checktest.NewScenario().
+		Available(names...).
+		TLDs("com").
+		Client()
+
+	code, stdout, stderr := runCLI(t, client, "--verbose", "bulk", "--limit", "2", "a.com", "b.com", "c.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "(2 domains)") || strings.Contains(stdout, "c.com") {
+		t.Errorf("Expected only the first 2 domains, got %q", stdout)
+	}
+	if !strings.Contains(stderr, "Checking the first 2 of 3 domains") {
+		t.Errorf("Expected verbose output to report the limit, got %q", stderr)
+	}
+
+	sample := func() string {
+		args := append([]string{"-o", "json", "bulk", "--sample", "3", "--seed", "42"}, names...)
+		code, stdout, stderr := runCLI(t, client, args...)
+		if code != int(customErrors.ExitSuccess) {
+			t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+		}
+		var result struct {
+			Results []struct {
+				Domain string `json:"domain"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("Invalid JSON output: %v", err)
+		}
+		if len(result.Results) != 3 {
+			t.Fatalf("Expected 3 sampled results, got %d", len(result.Results))
+		}
+		var picked []string
+		for _, r := range result.Results {
+			picked = append(picked, r.Domain)
+		}
+		return strings.Join(picked, ",")
+	}
+	if first, second := sample(), sample(); first != second {
+		t.Errorf("Expected the same seed to pick the same domains, got %s and %s", first, second)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"negative limit", []string{"bulk", "--limit", "-1", "a.com"}},
+		{"limit and sample", []string{"bulk", "--limit", "1", "--sample", "1", "a.com"}},
+		{"seed without sample", []string{"bulk", "--seed", "1", "a.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _, stderr := runCLI(t, client, tt.args...); code != int(customErrors.ExitValidation) {
+				t.Errorf("Expected a validation error, got exit code %d: %s", code, stderr)
+			}
+		})
+	}
+}
+
+func TestBulkCommand_SummaryOnly(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		TLDs("com").
+		Client()
+
+	code, stdout, stderr := runCLI(t, client, "bulk", "--summary-only", "--list-available", "free.com", "taken.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if strings.Contains(stdout, "taken.com") || !strings.Contains(stdout, "Unavailable: 1") {
+		t.Errorf("Expected only the summary, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "Available domains:\n  ✓ free.com") {
+		t.Errorf("Expected the available domains to be listed, got %q", stdout)
+	}
+}
+
+func TestBulkCommand_Compressed(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		TLDs("com").
+		Client()
+
+	dir := t.TempDir()
+	inFile := filepath.Join(dir, "domains.csv.gz")
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write([]byte("domain\nfree.com\ntaken.com\n"))
+	gz.Close()
+	if err := os.WriteFile(inFile, compressed.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	outFile := filepath.Join(dir, "results.json.zst")
+	code, stdout, stderr := runCLI(t, client, "-o", "json", "bulk", "--file", inFile, "--out-file", outFile)
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if stdout != "" {
+		t.Errorf("Expected the results in the output file only, got %q", stdout)
+	}
+
+	file, err := os.Open(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	decoder, err := zstd.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer decoder.Close()
+	var result struct {
+		Summary struct {
+			Total     int `json:"total"`
+			Available int `json:"available"`
+		} `json:"summary"`
+	}
+	if err := json.NewDecoder(decoder).Decode(&result); err != nil {
+		t.Fatalf("Expected zstd compressed JSON: %v", err)
+	}
+	if result.Summary.Total != 2 || result.Summary.Available != 1 {
+		t.Errorf("Expected both domains of the gzip input, got %+v", result.Summary)
+	}
+}
+
+func TestBulkCommand_Stdin(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		TLDs("com").
+		Client()
+	input := "free.com\n# candidates\n\ntaken.com\n"
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"dash argument", []string{"bulk", "-"}},
+		{"dash file", []string{"bulk", "--file", "-"}},
+		{"piped without arguments", []string{"bulk"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLIWithInput(t, client, input, tt.args...)
+			if code != int(customErrors.ExitSuccess) {
+				t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+			}
+			if !strings.Contains(stdout, "free.com: AVAILABLE") || !strings.Contains(stdout, "taken.com: UNAVAILABLE") {
+				t.Errorf("Expected the piped domains to be checked, got %q", stdout)
+			}
+		})
+	}
+
+	// Arguments win over piped input
+	code, stdout, _ := runCLIWithInput(t, client, input, "bulk", "free.com")
+	if code != int(customErrors.ExitSuccess) || strings.Contains(stdout, "taken.com") {
+		t.Errorf("Expected only the argument to be checked, got exit code %d: %s", code, stdout)
+	}
+
+	code, _, stderr := runCLIWithInput(t, client, "# nothing\n", "bulk")
+	if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "no domains provided") {
+		t.Errorf("Expected a missing domains error for empty input, got exit code %d: %s", code, stderr)
+	}
+}
+
+func TestCheckCommand_JSONOutput(t *testing.T) {
+	client := r53checktest.NewScenario().Available("free.com").TLDs("com").Client()
+
+	code, stdout, stderr := runCLI(t, client, "--output", "json", "check", "free.com")
+	if code != 0 {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", stdout, err)
+	}
+	if result["domain"] != "free.com" {
+		t.Errorf("Expected domain free.com, got %v", result["domain"])
+	}
+
+	code, _, stderr = runCLI(t, client, "--output", "json", "check", "bad-.com")
+	if code != int(customErrors.ExitValidation) {
+		t.Errorf("Expected validation exit code, got %d", code)
+	}
+	if !json.Valid([]byte(strings.TrimSpace(stderr))) {
+		t.Errorf("Expected a JSON error on stderr, got %q", stderr)
+	}
+}
+
+func TestDaemonCommand(t *testing.T) {
+	dir := t.TempDir()
+	domainsPath := filepath.Join(dir, "domains.txt")
+	historyPath := filepath.Join(dir, "history.db")
+	if err := os.WriteFile(domainsPath, []byte("drop.com\n# comment\ntaken.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := r53checktest.NewScenario().Unavailable("taken.com").TLDs("com").Client()
+	client.Script("drop.com",
+		r53checktest.Respond(types.DomainAvailabilityUnavailable),
+		r53checktest.Respond(types.DomainAvailabilityAvailable))
+
+	args := []string{"daemon", "--once", "--file", domainsPath, "--history", historyPath}
+	code, stdout, stderr := runCLI(t, client, args...)
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "2 checked, 0 available, 0 errors, 0 changed") {
+		t.Errorf("Expected a baseline run summary, got %q", stdout)
+	}
+
+	code, stdout, stderr = runCLI(t, client, args...)
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "drop.com: UNAVAILABLE -> AVAILABLE") || !strings.Contains(stdout, "1 changed") {
+		t.Errorf("Expected the status change to be reported, got %q", stdout)
+	}
+
+	invalid := []struct {
+		name string
+		args []string
+		msg  string
+	}{
+		{"missing file", []string{"daemon", "--schedule", "@daily"}, "domains file is required"},
+		{"missing schedule", []string{"daemon", "--file", domainsPath}, "schedule is required"},
+		{"invalid schedule", []string{"daemon", "--file", domainsPath, "--schedule", "0 */6 * *"}, "invalid schedule"},
+		{"unreadable file", []string{"daemon", "--file", filepath.Join(dir, "missing.txt"), "--schedule", "@daily"}, "unable to read domains file"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(t, client, tt.args...)
+			if code != int(customErrors.ExitValidation) {
+				t.Errorf("Expected validation exit code, got %d", code)
+			}
+			if !strings.Contains(stderr, tt.msg) {
+				t.Errorf("Expected error containing %q, got %q", tt.msg, stderr)
+			}
+		})
+	}
+}
+
+func TestWatchCommand(t *testing.T) {
+	dir := t.TempDir()
+	domainsPath := filepath.Join(dir, "shortlist.txt")
+	if err := os.WriteFile(domainsPath, []byte("drop.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := r53checktest.NewScenario().Unavailable("taken.com").TLDs("com").Client()
+	client.Script("drop.com",
+		r53checktest.Respond(types.DomainAvailabilityUnavailable),
+		r53checktest.Respond(types.DomainAvailabilityAvailable))
+
+	original := watchContext
+	defer func() { watchContext = original }()
+	watchContext = func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), 300*time.Millisecond)
+	}
+
+	code, stdout, stderr := runCLI(t, client, "watch", "taken.com", "--file", domainsPath,
+		"--interval", "20ms", "--jitter", "0", "--history", filepath.Join(dir, "history.db"))
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "2 checked, 0 available, 0 errors, 0 changed") {
+		t.Errorf("Expected a baseline run of both domains, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "drop.com: UNAVAILABLE -> AVAILABLE") {
+		t.Errorf("Expected a later run to report the status change, got %q", stdout)
+	}
+
+	invalid := []struct {
+		name string
+		args []string
+		msg  string
+	}{
+		{"no domains", []string{"watch"}, "no domains to watch"},
+		{"stdin", []string{"watch", "--file", "-"}, "cannot read domains from stdin"},
+		{"zero interval", []string{"watch", "example.com", "--interval", "0"}, "--interval must be positive"},
+		{"negative jitter", []string{"watch", "example.com", "--jitter", "-1m"}, "--jitter must not be negative"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(t, client, tt.args...)
+			if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, tt.msg) {
+				t.Errorf("Expected a validation error containing %q, got exit code %d: %s", tt.msg, code, stderr)
+			}
+		})
+	}
+}
+
+// capturingSender records mail sent by commands under test
+type capturingSender struct {
+	to  []string
+	msg string
+}
+
+func (s *capturingSender) SendMail(ctx context.Context, from string, to []string, msg []byte) error {
+	s.to, s.msg = to, string(msg)
+	return nil
+}
+
+func TestBulkCommand_FailOn(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		Failing("denied.com", r53checktest.AccessDenied()).
+		TLDs("com").
+		Client()
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCode customErrors.ExitCode
+		stderr       string
+	}{
+		{"partial failure by default", []string{"bulk", "free.com", "denied.com"}, customErrors.ExitSuccess, ""},
+		{"fail on error", []string{"bulk", "--fail-on-error", "free.com", "denied.com"}, customErrors.ExitPartialFailure, "1 of 2 domains could not be checked"},
+		{"fail on error without errors", []string{"bulk", "--fail-on-error", "free.com", "taken.com"}, customErrors.ExitSuccess, ""},
+		{"fail on unavailable", []string{"bulk", "--fail-on-unavailable", "free.com", "taken.com"}, customErrors.ExitPartialFailure, "1 of 2 domains are not available"},
+		{"every domain failed", []string{"bulk", "--fail-on-error", "denied.com"}, customErrors.ExitAuthorization, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, client, tt.args...)
+			if code != int(tt.expectedCode) {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.expectedCode, code, stderr)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.stderr, stderr)
+			}
+			if code == int(customErrors.ExitPartialFailure) && !strings.Contains(stdout, "free.com") {
+				t.Errorf("Expected the results to be printed, got %q", stdout)
+			}
+		})
+	}
+}
+
+func TestBulkCommand_Interrupted(t *testing.T) {
+	tests := []struct {
+		name       string
+		interrupts int
+		aborted    bool
+	}{
+		{"first interrupt cancels the run", 1, false},
+		{"second interrupt aborts it", 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := r53checktest.NewScenario().
+				Available("free.com").
+				Unavailable("taken.com").
+				Slow("slow.com", time.Minute, types.DomainAvailabilityAvailable).
+				TLDs("com").
+				Client()
+
+			signals := make(chan os.Signal, 2)
+			exitCode := -1
+			originalNotify, originalExit := notifyInterrupts, exitProcess
+			notifyInterrupts = func() (<-chan os.Signal, func()) { return signals, func() {} }
+			exitProcess = func(code int) { exitCode = code }
+			t.Cleanup(func() { notifyInterrupts, exitProcess = originalNotify, originalExit })
+
+			// Interrupt the run while slow.com is still being checked
+			go func() {
+				for client.CallCount("free.com") == 0 || client.CallCount("taken.com") == 0 || client.CallCount("slow.com") == 0 {
+					time.Sleep(time.Millisecond)
+				}
+				time.Sleep(50 * time.Millisecond)
+				for i := 0; i < tt.interrupts; i++ {
+					signals <- os.Interrupt
+				}
+			}()
+
+			path := filepath.Join(t.TempDir(), "history.db")
+			code, stdout, stderr := runCLI(t, client, "bulk", "--history", path, "free.com", "taken.com", "slow.com")
+			if code != int(customErrors.ExitSystemError) {
+				t.Errorf("Expected a system error exit code, got %d (stderr: %s)", code, stderr)
+			}
+			if !strings.Contains(stderr, "interrupt again within") {
+				t.Errorf("Expected the interrupt to be acknowledged, got %q", stderr)
+			}
+			if aborted := strings.Contains(stderr, "Aborted: 2 of 3 checks completed"); aborted != tt.aborted {
+				t.Errorf("Expected aborted to be %v, got stderr %q", tt.aborted, stderr)
+			}
+			if tt.aborted && exitCode != int(customErrors.ExitSystemError) {
+				t.Errorf("Expected the process to exit with a system error, got %d", exitCode)
+			}
+			for _, expected := range []string{"free.com", "taken.com", "slow.com: SKIPPED"} {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("Expected the partial results to include %q, got %q", expected, stdout)
+				}
+			}
+
+			db, err := history.OpenDB(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			if records, err := db.Records("free.com"); err != nil || len(records) != 1 {
+				t.Errorf("Expected free.com to be recorded, got %+v, %v", records, err)
+			}
+			if records, err := db.Records("slow.com"); err != nil || len(records) != 0 {
+				t.Errorf("Expected the skipped slow.com not to be recorded, got %+v, %v", records, err)
+			}
+		})
+	}
+}
+
+func TestBulkCommand_RetryBudgets(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("retry:\n  budgets:\n    429: 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		ThrottledThen("busy.com", 2, types.DomainAvailabilityAvailable).
+		TLDs("com").
+		Client()
+
+	code, stdout, stderr := runCLI(t, client, "--config", configPath, "--verbose", "bulk", "free.com", "busy.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.Contains(stdout, "busy.com: AVAILABLE") || client.CallCount("busy.com") != 3 {
+		t.Errorf("Expected busy.com to be available after 2 retries, got %d calls: %s", client.CallCount("busy.com"), stdout)
+	}
+	if !strings.Contains(stderr, "Retried 1 domain: 2 retries (429: 2)") {
+		t.Errorf("Expected the retry counts in verbose output, got %q", stderr)
+	}
+
+	if err := os.WriteFile(configPath, []byte("retry:\n  budgets:\n    throttling: 5\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if code, _, stderr := runCLI(t, client, "--config", configPath, "bulk", "free.com"); code != int(customErrors.ExitValidation) {
+		t.Errorf("Expected a validation error for an unknown retry budget, got exit code %d (stderr: %s)", code, stderr)
+	}
+}
+
+func TestBulkCommand_EmailNotification(t *testing.T) {
+	sender := &capturingSender{}
+	original := newMailSender
+	newMailSender = func(ctx context.Context, settings config.EmailSettings) (notify.MailSender, error) {
+		return sender, nil
+	}
+	t.Cleanup(func() { newMailSender = original })
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("email:\n  from: r53check@example.com\n  to: [team@example.com]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := r53checktest.NewScenario().Available("free.com").TLDs("com").Client()
+
+	code, _, stderr := runCLI(t, client, "--config", configPath, "bulk", "--notify", "email", "free.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if len(sender.to) != 1 || sender.to[0] != "team@example.com" || !strings.Contains(sender.msg, "free.com") {
+		t.Errorf("Expected a report to team@example.com, got %v: %q", sender.to, sender.msg)
+	}
+
+	// --email-to overrides the configured recipients
+	runCLI(t, client, "--config", configPath, "bulk", "--notify", "email", "--email-to", "me@example.com", "free.com")
+	if len(sender.to) != 1 || sender.to[0] != "me@example.com" {
+		t.Errorf("Expected the report to go to me@example.com, got %v", sender.to)
+	}
+
+	invalid := [][]string{
+		{"bulk", "--notify", "pager", "free.com"},
+		{"bulk", "--email-to", "me@example.com", "free.com"},
+		{"bulk", "--notify", "email", "free.com"},
+	}
+	for _, args := range invalid {
+		if code, _, _ := runCLI(t, client, args...); code != int(customErrors.ExitValidation) {
+			t.Errorf("Expected validation exit code for %v, got %d", args, code)
+		}
+	}
+}
+
+func TestWebhookNotification(t *testing.T) {
+	bodies := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+	}))
+	defer server.Close()
+
+	client := r53checktest.NewScenario().Available("free.com").Unavailable("taken.com").TLDs("com").Client()
+
+	code, _, stderr := runCLI(t, client, "check", "free.com", "--webhook-url", server.URL)
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if body := <-bodies; !strings.Contains(body, `"domain":"free.com"`) || !strings.Contains(body, `"total":1`) {
+		t.Errorf("Expected the single result to be posted, got %s", body)
+	}
+
+	// The URL can also come from the config file
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("webhook:\n  url: "+server.URL+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	code, _, stderr = runCLI(t, client, "--config", configPath, "bulk", "--notify", "webhook", "free.com", "taken.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if body := <-bodies; !strings.Contains(body, `"available":1,"unavailable":1`) {
+		t.Errorf("Expected the bulk summary to be posted, got %s", body)
+	}
+
+	if code, _, _ := runCLI(t, client, "check", "free.com", "--webhook-url", "not a url"); code != int(customErrors.ExitValidation) {
+		t.Errorf("Expected validation exit code for an invalid URL, got %d", code)
+	}
+}
+
+// capturingSQS records the results published by commands under test
+type capturingSQS struct {
+	bodies []string
+}
+
+func (c *capturingSQS) SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	out := &sqs.SendMessageBatchOutput{}
+	for _, entry := range params.Entries {
+		c.bodies = append(c.bodies, *entry.MessageBody)
+		out.Successful = append(out.Successful, sqstypes.SendMessageBatchResultEntry{Id: entry.Id})
+	}
+	return out, nil
+}
+
+func TestBulkCommand_SQSSink(t *testing.T) {
+	api := &capturingSQS{}
+	original := newSQSAPI
+	newSQSAPI = func(ctx context.Context) (sink.SQSAPI, error) {
+		return api, nil
+	}
+	t.Cleanup(func() { newSQSAPI = original })
+
+	client := r53checktest.NewScenario().Available("free.com").Unavailable("taken.com").TLDs("com").Client()
+
+	queueURL := "https://sqs.us-east-1.amazonaws.com/123456789012/domains"
+	code, _, stderr := runCLI(t, client, "bulk", "--sqs-queue-url", queueURL, "free.com", "taken.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	published := strings.Join(api.bodies, "\n")
+	if len(api.bodies) != 2 || !strings.Contains(published, `"domain":"free.com"`) || !strings.Contains(published, `"domain":"taken.com"`) {
+		t.Errorf("Expected both results to be published, got %q", api.bodies)
+	}
+
+	if code, _, _ := runCLI(t, client, "bulk", "--sqs-queue-url", "domains", "free.com"); code != int(customErrors.ExitValidation) {
+		t.Errorf("Expected validation exit code for a queue name instead of a URL, got %d", code)
+	}
+}
+
+func TestNotificationRouting(t *testing.T) {
+	bodies := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+	}))
+	defer server.Close()
+
+	writeRules := func(rules string) string {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte("notify:\n  rules:\n"+rules), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	client := r53checktest.NewScenario().Available("free.com", "free.io").Unavailable("taken.com").TLDs("com", "io").Client()
+
+	configPath := writeRules("    - status: AVAILABLE\n      tlds: [com]\n      targets: [webhook]\n")
+	code, _, stderr := runCLI(t, client, "--config", configPath, "bulk", "--webhook-url", server.URL, "free.com", "free.io", "taken.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	body := <-bodies
+	if !strings.Contains(body, `"domain":"free.com"`) || strings.Contains(body, "free.io") || strings.Contains(body, "taken.com") {
+		t.Errorf("Expected only free.com to be posted, got %s", body)
+	}
+
+	// Nothing matching sends nothing
+	code, _, _ = runCLI(t, client, "--config", configPath, "bulk", "--webhook-url", server.URL, "taken.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d", code)
+	}
+	select {
+	case body := <-bodies:
+		t.Errorf("Expected no webhook call, got %s", body)
+	default:
+	}
+
+	configPath = writeRules("    - status: AVAILABLE\n      targets: [pager]\n")
+	code, _, stderr = runCLI(t, client, "--config", configPath, "bulk", "free.com")
+	if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "pager") {
+		t.Errorf("Expected a validation error for an unknown rule target, got %d: %s", code, stderr)
+	}
+}
+
+func TestHistoryRecording(t *testing.T) {
+	client := r53checktest.NewScenario().Available("free.com").Unavailable("taken.com").TLDs("com").Client()
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	if code, _, stderr := runCLI(t, client, "bulk", "--history", path, "free.com", "taken.com"); code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if code, _, stderr := runCLI(t, client, "check", "--history", path, "free.com"); code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+
+	db, err := history.OpenDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	records, err := db.Records("free.com")
+	if err != nil || len(records) != 2 || records[0].RunID == records[1].RunID {
+		t.Errorf("Expected free.com to be recorded by two runs, got %+v, %v", records, err)
+	}
+	if _, ok, _ := db.LastSeen("taken.com", "UNAVAILABLE"); !ok {
+		t.Error("Expected taken.com to be recorded as unavailable")
+	}
+
+	unrecorded := filepath.Join(t.TempDir(), "unrecorded.db")
+	runCLI(t, client, "check", "--history", unrecorded, "--no-history", "free.com")
+	if _, err := os.Stat(unrecorded); !os.IsNotExist(err) {
+		t.Errorf("Expected no history database with --no-history, got %v", err)
+	}
+}
+
+// capturingDynamoDB records the checks written to DynamoDB tables
+type capturingDynamoDB struct {
+	tables map[string]int
+}
+
+func (c *capturingDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (c *capturingDynamoDB) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	for table, requests := range params.RequestItems {
+		c.tables[table] += len(requests)
+	}
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
+func (c *capturingDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return &dynamodb.QueryOutput{}, nil
+}
+
+func TestHistoryBackends(t *testing.T) {
+	api := &capturingDynamoDB{tables: make(map[string]int)}
+	original := newDynamoDBAPI
+	newDynamoDBAPI = func(ctx context.Context) (history.DynamoDBAPI, error) {
+		return api, nil
+	}
+	t.Cleanup(func() { newDynamoDBAPI = original })
+
+	writeConfig := func(content string) string {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	domainsPath := filepath.Join(t.TempDir(), "domains.txt")
+	if err := os.WriteFile(domainsPath, []byte("free.com\ntaken.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	client := r53checktest.NewScenario().Available("free.com").Unavailable("taken.com").TLDs("com").Client()
+
+	configPath := writeConfig("history:\n  backend: dynamodb\n  dynamodb:\n    table: shared-history\n")
+	code, _, stderr := runCLI(t, client, "--config", configPath, "daemon", "--once", "--file", domainsPath)
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if api.tables["shared-history"] != 2 {
+		t.Errorf("Expected both checks in the shared-history table, got %v", api.tables)
+	}
+
+	// The daemon needs its history, while a one-off check only warns
+	configPath = writeConfig("history:\n  backend: dynamodb\n")
+	if code, _, _ := runCLI(t, client, "--config", configPath, "daemon", "--once", "--file", domainsPath); code != int(customErrors.ExitValidation) {
+		t.Errorf("Expected a validation error without a table, got exit code %d", code)
+	}
+	configPath = writeConfig("history:\n  backend: mongodb\n")
+	code, _, stderr = runCLI(t, client, "--config", configPath, "check", "free.com")
+	if code != int(customErrors.ExitSuccess) || !strings.Contains(stderr, "unknown history backend") {
+		t.Errorf("Expected the check to succeed with a warning, got exit code %d (stderr: %s)", code, stderr)
+	}
+}
+
+func TestTrendsCommand(t *testing.T) {
+	client := r53checktest.NewScenario().Unavailable("taken.com").TLDs("com").Client()
+	client.Script("drop.com",
+		r53checktest.Respond(types.DomainAvailabilityUnavailable),
+		r53checktest.Respond(types.DomainAvailabilityUnavailable),
+		r53checktest.Respond(types.DomainAvailabilityAvailable))
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	for i := 0; i < 3; i++ {
+		if code, _, stderr := runCLI(t, client, "bulk", "--history", path, "drop.com", "taken.com"); code != int(customErrors.ExitSuccess) {
+			t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+		}
+	}
+
+	code, stdout, stderr := runCLI(t, client, "trends", "--history", path, "drop.com", "never.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	for _, want := range []string{"drop.com: 1 status change in 3 checks", "UNAVAILABLE", "2 checks", "never.com: no checks recorded"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, stdout)
+		}
+	}
+
+	domainsPath := filepath.Join(t.TempDir(), "domains.txt")
+	if err := os.WriteFile(domainsPath, []byte("drop.com\ntaken.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	code, stdout, _ = runCLI(t, client, "--output", "json", "trends", "--history", path, "--file", domainsPath)
+	var out struct {
+		Trends []history.Trend `json:"trends"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil || code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected JSON trends, got exit code %d: %v\n%s", code, err, stdout)
+	}
+	if len(out.Trends) != 2 || out.Trends[0].Changes != 1 || out.Trends[1].Changes != 0 || len(out.Trends[1].Periods) != 1 {
+		t.Errorf("Unexpected trends %+v", out.Trends)
+	}
+
+	if code, _, _ := runCLI(t, client, "trends"); code != int(customErrors.ExitValidation) {
+		t.Errorf("Expected a validation error without domains, got exit code %d", code)
+	}
+}
+
+func TestBulkCommand_SkipIfChecked(t *testing.T) {
+	client := r53checktest.NewScenario().Available("free.com").Unavailable("taken.com").TLDs("com").Client()
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	if code, _, stderr := runCLI(t, client, "bulk", "--history", path, "taken.com"); code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+
+	code, stdout, stderr := runCLI(t, client, "--output", "json", "bulk", "--history", path, "--skip-if-checked", "24h", "free.com", "taken.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if client.CallCount("taken.com") != 1 || client.CallCount("free.com") != 1 {
+		t.Errorf("Expected only free.com to be checked again, got calls %v", client.Calls())
+	}
+	var out struct {
+		Results []struct {
+			Domain      string `json:"domain"`
+			Status      string `json:"status"`
+			FromHistory bool   `json:"from_history"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("Invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(out.Results) != 2 || out.Results[0].Domain != "free.com" || out.Results[0].FromHistory ||
+		out.Results[1].Domain != "taken.com" || out.Results[1].Status != "UNAVAILABLE" || !out.Results[1].FromHistory {
+		t.Errorf("Expected both results in input order with taken.com from history, got %+v", out.Results)
+	}
+
+	// Reused results are not recorded again, so everything was recent now
+	runCLI(t, client, "bulk", "--history", path, "--skip-if-checked", "24h", "free.com", "taken.com")
+	if client.CallCount("taken.com") != 1 || client.CallCount("free.com") != 1 {
+		t.Errorf("Expected no further checks, got calls %v", client.Calls())
+	}
+	store, err := history.OpenDB(path)
+	if err == nil {
+		defer store.Close()
+		if records, _ := store.Records("taken.com"); len(records) != 1 {
+			t.Errorf("Expected taken.com to be recorded once, got %d records", len(records))
+		}
+	}
+
+	if code, _, _ := runCLI(t, client, "bulk", "--no-history", "--skip-if-checked", "24h", "free.com"); code != int(customErrors.ExitValidation) {
+		t.Errorf("Expected a validation error with --no-history, got exit code %d", code)
+	}
+}
+
+func TestRunTags(t *testing.T) {
+	client := r53checktest.NewScenario().Available("free.com").TLDs("com").Client()
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	runs := [][]string{
+		{"bulk", "--history", path, "--tag", "client=acme", "--tag", "project=rename", "free.com"},
+		{"check", "--history", path, "--tag", "client=globex", "free.com"},
+		{"check", "--history", path, "free.com"},
+	}
+	for _, args := range runs {
+		if code, _, stderr := runCLI(t, client, args...); code != int(customErrors.ExitSuccess) {
+			t.Fatalf("Expected success for %v, got exit code %d (stderr: %s)", args, code, stderr)
+		}
+	}
+
+	tests := []struct {
+		tags   []string
+		checks int
+	}{
+		{nil, 3},
+		{[]string{"client=acme"}, 1},
+		{[]string{"client=acme", "project=rename"}, 1},
+		{[]string{"client=globex", "project=rename"}, 0},
+	}
+	for _, tt := range tests {
+		args := []string{"--output", "json", "trends", "--history", path, "free.com"}
+		for _, tag := range tt.tags {
+			args = append(args, "--tag", tag)
+		}
+		_, stdout, stderr := runCLI(t, client, args...)
+		var out struct {
+			Trends []history.Trend `json:"trends"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &out); err != nil || len(out.Trends) != 1 {
+			t.Fatalf("Expected JSON trends for %v, got %v (stderr: %s)", tt.tags, err, stderr)
+		}
+		if out.Trends[0].Checks != tt.checks {
+			t.Errorf("Expected %d checks tagged %v, got %d", tt.checks, tt.tags, out.Trends[0].Checks)
+		}
+	}
+
+	if code, _, _ := runCLI(t, client, "check", "--no-history", "--tag", "client", "free.com"); code != int(customErrors.ExitValidation) {
+		t.Errorf("Expected a validation error for a tag without a value, got exit code %d", code)
+	}
+}
+
+// capturingTransport records Sentry events instead of sending them
+type capturingTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *capturingTransport) Configure(sentry.ClientOptions)        {}
+func (t *capturingTransport) Flush(time.Duration) bool              { return true }
+func (t *capturingTransport) FlushWithContext(context.Context) bool { return true }
+func (t *capturingTransport) Close()                                {}
+func (t *capturingTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func TestErrorReporting(t *testing.T) {
+	transport := &capturingTransport{}
+	original := newReporter
+	newReporter = func(target, release string) (*reporting.Reporter, error) {
+		return reporting.NewWithTransport(target, release, transport)
+	}
+	t.Cleanup(func() { newReporter = original })
+
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Failing("denied.com", r53checktest.AccessDenied()).
+		Failing("broken.com", customErrors.NewSystemError("route53domains", "unexpected response", nil)).
+		TLDs("com").
+		Client()
+
+	code, _, stderr := runCLI(t, client, "--error-reporting", "sentry://key@sentry.example.com/42", "bulk", "free.com", "denied.com", "broken.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if len(transport.events) != 1 {
+		t.Fatalf("Expected only the unexpected error to be reported, got %d events", len(transport.events))
+	}
+	event := transport.events[0]
+	if event.Tags["domain"] != "broken.com" || event.Tags["command"] != "r53check bulk" {
+		t.Errorf("Expected the domain and command tags, got %v", event.Tags)
+	}
+	if event.Release == "" {
+		t.Error("Expected the event to carry the release")
+	}
+
+	code, _, stderr = runCLI(t, client, "--error-reporting", "https://key@sentry.example.com/42", "check", "free.com")
+	if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "sentry://") {
+		t.Errorf("Expected a validation error for an unsupported target, got exit code %d (stderr: %s)", code, stderr)
+	}
+}
+
+func TestSpinner(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Slow("slow.com", 500*time.Millisecond, types.DomainAvailabilityAvailable).
+		Available("free.com").
+		TLDs("com").
+		Client()
+	t.Setenv("TERM", "xterm")
+
+	original := isTerminal
+	isTerminal = func(w io.Writer) bool { return true }
+	t.Cleanup(func() { isTerminal = original })
+
+	tests := []struct {
+		name    string
+		args    []string
+		spinner bool
+	}{
+		{"slow check", []string{"check", "slow.com"}, true},
+		{"fast check", []string{"check", "free.com"}, false},
+		{"verbose", []string{"--verbose", "check", "slow.com"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, client, tt.args...)
+			if code != int(customErrors.ExitSuccess) {
+				t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+			}
+			if spinner := strings.Contains(stderr, "⠋ Checking slow.com... "); spinner != tt.spinner {
+				t.Errorf("Expected spinner to be %v, got stderr %q", tt.spinner, stderr)
+			}
+			if tt.spinner && !strings.HasSuffix(stderr, "\r\033[K") {
+				t.Errorf("Expected the spinner to be cleared, got stderr %q", stderr)
+			}
+			if !strings.Contains(stdout, "AVAILABLE") {
+				t.Errorf("Expected the result on stdout, got %q", stdout)
+			}
+		})
+	}
+
+	// Output to anything but a terminal never gets a spinner
+	isTerminal = original
+	if _, _, stderr := runCLI(t, client, "check", "slow.com"); stderr != "" {
+		t.Errorf("Expected no spinner without a terminal, got %q", stderr)
+	}
+}
+
+func TestTimings(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		ThrottledThen("busy.com", 1, types.DomainAvailabilityAvailable).
+		TLDs("com").
+		Client()
+
+	code, stdout, stderr := runCLI(t, client, "--timings", "--price", "check", "free.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if strings.Contains(stdout, "Timings:") {
+		t.Errorf("Expected timings on stderr only, got stdout %q", stdout)
+	}
+	for _, phase := range []string{"config load", "credentials", "TLD catalog", "validation", "API call", "pricing", "formatting", "total"} {
+		if !strings.Contains(stderr, "  "+phase+" ") {
+			t.Errorf("Expected the %s phase in %q", phase, stderr)
+		}
+	}
+
+	code, _, stderr = runCLI(t, client, "--timings", "bulk", "free.com", "taken.com", "busy.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	for _, want := range []string{"  checks ", "Per check (3 checks):", "p50", "p99", "  API call ", "Retries: 1 retry (429: 1)"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("Expected %q in %q", want, stderr)
+		}
+	}
+	if strings.Contains(stderr, "  pricing ") {
+		t.Errorf("Expected no pricing percentiles without --price, got %q", stderr)
+	}
+
+	code, _, stderr = runCLI(t, client, "bulk", "free.com")
+	if code != int(customErrors.ExitSuccess) || strings.Contains(stderr, "Timings:") {
+		t.Errorf("Expected no timings without --timings, got exit code %d (stderr: %s)", code, stderr)
+	}
+}
+
+func TestMetricsEmission(t *testing.T) {
+	dir := t.TempDir()
+	emfPath := filepath.Join(dir, "metrics.log")
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("metrics:\n  emf: "+emfPath+"\n  namespace: Domains\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com").
+		Failing("denied.com", r53checktest.AccessDenied()).
+		TLDs("com").
+		Client()
+
+	code, _, stderr := runCLI(t, client, "--config", configPath, "bulk", "free.com", "taken.com", "denied.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+
+	data, err := os.ReadFile(emfPath)
+	if err != nil {
+		t.Fatalf("Expected the EMF file to be written: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var document map[string]any
+		if err := json.Unmarshal([]byte(line), &document); err != nil {
+			t.Fatalf("Invalid EMF document %q: %v", line, err)
+		}
+		switch {
+		case document["checks"] != nil:
+			counts["checks "+document["status"].(string)]++
+		case document["errors"] != nil:
+			counts["errors "+document["category"].(string)]++
+		case document["check_duration"] != nil:
+			counts["timings"]++
+		}
+	}
+	expected := map[string]int{
+		"checks AVAILABLE":     1,
+		"checks UNAVAILABLE":   1,
+		"checks UNKNOWN":       1,
+		"errors AUTHORIZATION": 1,
+		"timings":              3,
+	}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected samples %v, got %v", expected, counts)
+	}
+	if !strings.Contains(string(data), `"Namespace":"Domains"`) {
+		t.Errorf("Expected the configured namespace, got %s", data)
+	}
+
+	if err := os.WriteFile(configPath, []byte("metrics:\n  emf: "+filepath.Join(dir, "missing", "metrics.log")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	code, _, stderr = runCLI(t, client, "--config", configPath, "check", "free.com")
+	if code != int(customErrors.ExitSystemError) || !strings.Contains(stderr, "EMF") {
+		t.Errorf("Expected a system error for an unusable EMF file, got exit code %d (stderr: %s)", code, stderr)
+	}
+}
+
+func TestLocalizedOutput(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Failing("denied.com", r53checktest.AccessDenied()).
+		TLDs("com").
+		Client()
+
+	code, stdout, _ := runCLI(t, client, "--lang", "es", "check", "free.com")
+	if code != int(customErrors.ExitSuccess) || !strings.Contains(stdout, "free.com está DISPONIBLE para registrar") {
+		t.Errorf("Expected the result in Spanish, got exit code %d: %s", code, stdout)
+	}
+
+	t.Setenv("R53CHECK_LANG", "ja")
+	code, _, stderr := runCLI(t, client, "check", "denied.com")
+	if code != int(customErrors.ExitAuthorization) || !strings.Contains(stderr, "認可エラー") {
+		t.Errorf("Expected the error guidance in Japanese, got exit code %d: %s", code, stderr)
+	}
+
+	// JSON output is for machines, so it is never translated
+	code, stdout, _ = runCLI(t, client, "--output", "json", "check", "free.com")
+	if code != int(customErrors.ExitSuccess) || !strings.Contains(stdout, `"status": "AVAILABLE"`) {
+		t.Errorf("Expected untranslated JSON, got exit code %d: %s", code, stdout)
+	}
+
+	code, _, stderr = runCLI(t, client, "--lang", "de", "check", "free.com")
+	if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "unsupported language") {
+		t.Errorf("Expected a validation error for an unsupported language, got exit code %d: %s", code, stderr)
+	}
+}
+
+func TestMockMode(t *testing.T) {
+	// The client given to runCLI fails every check, so only the mock can answer
+	client := r53checktest.NewScenario().Default(types.DomainAvailabilityDontKnow).Client()
+	names := []string{"alpha.com", "bravo.com", "charlie.com", "delta.com"}
+
+	for _, enable := range []string{"flag", "env"} {
+		t.Run(enable, func(t *testing.T) {
+			args := append([]string{"-o", "json", "--price", "bulk"}, names...)
+			if enable == "flag" {
+				args = append([]string{"--mock"}, args...)
+			} else {
+				t.Setenv("R53CHECK_MOCK", "1")
+			}
+
+			code, stdout, stderr := runCLI(t, client, args...)
+			if code != int(customErrors.ExitSuccess) {
+				t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+			}
+			var result struct {
+				Results []struct {
+					Domain    string `json:"domain"`
+					Available bool   `json:"available"`
+					Pricing   *struct {
+						Registration *float64 `json:"registration"`
+					} `json:"pricing"`
+				} `json:"results"`
+			}
+			if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+				t.Fatalf("Invalid JSON output: %v", err)
+			}
+			if len(result.Results) != len(names) {
+				t.Fatalf("Expected %d results, got %d", len(names), len(result.Results))
+			}
+			for _, r := range result.Results {
+				expected := r53checktest.HashedAvailability(r.Domain) == types.DomainAvailabilityAvailable
+				if r.Available != expected {
+					t.Errorf("Expected %s available=%v from the mock, got %v", r.Domain, expected, r.Available)
+				}
+				if r.Available && (r.Pricing == nil || r.Pricing.Registration == nil || *r.Pricing.Registration != 15) {
+					t.Errorf("Expected the canned .com price for %s, got %+v", r.Domain, r.Pricing)
+				}
+			}
+		})
+	}
+
+	if calls := client.Calls(); len(calls) != 0 {
+		t.Errorf("Expected no checks against the real client, got %v", calls)
+	}
+}
+
+func TestMockMode_SeparateCaches(t *testing.T) {
+	client := r53checktest.NewScenario().Client()
+
+	code, _, stderr := runCLI(t, client, "--mock", "--price", "bulk", "alpha.com", "bravo.com")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+
+	// runCLI points the XDG directories at the run's own
+	real := storage.DefaultDirs()
+	for _, path := range []string{real.TLDCatalog(), real.PriceCatalog(), real.HistoryDB()} {
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected --mock not to create %s, got %v", path, err)
+		}
+	}
+	if _, err := os.Stat(real.Mock().PriceCatalog()); err != nil {
+		t.Errorf("Expected the mock prices to be cached apart: %v", err)
+	}
+}
+
+// releaseSigningKey signs the checksums served by serveRelease, with
+// minisign key id "testkey1"
+var releaseSigningKey = func() ed25519.PrivateKey {
+	_, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		panic(err)
+	}
+	return private
+}()
+
+// releasePublicKey returns the minisign public key of releaseSigningKey
+func releasePublicKey(t *testing.T) *update.PublicKey {
+	t.Helper()
+	public := releaseSigningKey.Public().(ed25519.PublicKey)
+	key, err := update.ParsePublicKey(base64.StdEncoding.EncodeToString(append([]byte("Edtestkey1"), public...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// minisign returns a legacy minisign signature of message by releaseSigningKey
+func minisign(message []byte) string {
+	sig := ed25519.Sign(releaseSigningKey, message)
+	comment := "file:checksums.txt"
+	global := ed25519.Sign(releaseSigningKey, append(append([]byte{}, sig...), comment...))
+	return fmt.Sprintf("untrusted comment: test\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(append([]byte("Edtestkey1"), sig...)), comment, base64.StdEncoding.EncodeToString(global))
+}
+
+// serveRelease serves tag as the latest release, with a linux/amd64 archive
+// holding binary, sent after delay, and its checksums signed with
+// releaseSigningKey
+func serveRelease(t *testing.T, tag, binary string, delay time.Duration) *httptest.Server {
+	t.Helper()
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "r53check", Mode: 0o755, Size: int64(len(binary)), Typeflag: tar.TypeReg})
+	tw.Write([]byte(binary))
+	tw.Close()
+	gz.Close()
+	sum := sha256.Sum256(archive.Bytes())
+
+	version := strings.TrimPrefix(tag, "v")
+	archiveName := update.ArchiveName(version, "linux", "amd64")
+	checksumsName := update.ChecksumsName(version)
+	checksums := fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), archiveName)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	mux.HandleFunc("/repos/"+update.Repository+"/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(update.Release{Tag: tag, Assets: []update.Asset{
+			{Name: archiveName, URL: server.URL + "/archive"},
+			{Name: checksumsName, URL: server.URL + "/checksums"},
+			{Name: update.SignatureName(version), URL: server.URL + "/checksums.minisig"},
+		}})
+	})
+	mux.HandleFunc("/archive", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Write(archive.Bytes())
+	})
+	mux.HandleFunc("/checksums", func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, checksums) })
+	mux.HandleFunc("/checksums.minisig", func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, minisign([]byte(checksums))) })
+	return server
+}
+
+func TestUpdateCommand(t *testing.T) {
+	server := serveRelease(t, "v1.3.0", "new binary", 0)
+	target := filepath.Join(t.TempDir(), "r53check")
+
+	originalUpdater, originalPath, originalVersion := newUpdater, executablePath, version
+	newUpdater = func() *update.Updater {
+		updater := update.NewUpdater(server.Client())
+		updater.SetAPIURL(server.URL)
+		updater.SetPlatform("linux", "amd64")
+		updater.SetPublicKey(releasePublicKey(t))
+		return updater
+	}
+	executablePath = func() (string, error) { return target, nil }
+	t.Cleanup(func() { newUpdater, executablePath, version = originalUpdater, originalPath, originalVersion })
+
+	tests := []struct {
+		name    string
+		version string
+		args    []string
+		code    customErrors.ExitCode
+		stdout  string
+		binary  string
+	}{
+		{"check only, newer release", "v1.2.0", []string{"update", "--check-only"}, customErrors.ExitPartialFailure, "r53check v1.3.0 is available (running v1.2.0)", "old binary"},
+		{"check only, up to date", "v1.3.0", []string{"update", "--check-only"}, customErrors.ExitSuccess, "r53check v1.3.0 is up to date", "old binary"},
+		{"up to date", "v1.3.0", []string{"update"}, customErrors.ExitSuccess, "is up to date", "old binary"},
+		{"development build", "devel", []string{"update"}, customErrors.ExitValidation, "", "old binary"},
+		{"update", "v1.2.0", []string{"update"}, customErrors.ExitSuccess, "Updated r53check from v1.2.0 to v1.3.0", "new binary"},
+		{"forced", "devel", []string{"update", "--force"}, customErrors.ExitSuccess, "Updated r53check from devel to v1.3.0", "new binary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(target, []byte("old binary"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			version = tt.version
+
+			code, stdout, stderr := runCLI(t, nil, tt.args...)
+			if code != int(tt.code) {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.code, code, stderr)
+			}
+			if !strings.Contains(stdout, tt.stdout) {
+				t.Errorf("Expected stdout to contain %q, got %q", tt.stdout, stdout)
+			}
+			if data, _ := os.ReadFile(target); string(data) != tt.binary {
+				t.Errorf("Expected the binary to hold %q, got %q", tt.binary, data)
+			}
+		})
+	}
+
+	version = "v1.2.0"
+	code, stdout, stderr := runCLI(t, nil, "-o", "json", "update", "--check-only")
+	var status struct {
+		Current   string `json:"current"`
+		Latest    string `json:"latest"`
+		Available bool   `json:"update_available"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &status); err != nil {
+		t.Fatalf("Invalid JSON output: %v (stderr: %s)", err, stderr)
+	}
+	if code != int(customErrors.ExitPartialFailure) || status.Current != "v1.2.0" || status.Latest != "v1.3.0" || !status.Available {
+		t.Errorf("Unexpected JSON status %+v with exit code %d", status, code)
+	}
+}
+
+func TestUpdateCommand_DownloadTimeout(t *testing.T) {
+	server := serveRelease(t, "v1.3.0", "new binary", 300*time.Millisecond)
+	target := filepath.Join(t.TempDir(), "r53check")
+
+	originalUpdater, originalPath, originalVersion := newUpdater, executablePath, version
+	newUpdater = func() *update.Updater {
+		updater := update.NewUpdater(server.Client())
+		updater.SetAPIURL(server.URL)
+		updater.SetPlatform("linux", "amd64")
+		updater.SetPublicKey(releasePublicKey(t))
+		return updater
+	}
+	executablePath = func() (string, error) { return target, nil }
+	version = "v1.2.0"
+	t.Cleanup(func() { newUpdater, executablePath, version = originalUpdater, originalPath, originalVersion })
+
+	tests := []struct {
+		name   string
+		args   []string
+		ok     bool
+		binary string
+	}{
+		{"slower than --timeout", []string{"--timeout", "100ms", "update"}, true, "new binary"},
+		{"no download timeout", []string{"--timeout", "100ms", "update", "--download-timeout", "0"}, true, "new binary"},
+		{"slower than --download-timeout", []string{"update", "--download-timeout", "100ms"}, false, "old binary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(target, []byte("old binary"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			code, _, stderr := runCLI(t, nil, tt.args...)
+			if (code == int(customErrors.ExitSuccess)) != tt.ok {
+				t.Errorf("Expected success %v, got exit code %d (stderr: %s)", tt.ok, code, stderr)
+			}
+			if data, _ := os.ReadFile(target); string(data) != tt.binary {
+				t.Errorf("Expected the binary to hold %q, got %q", tt.binary, data)
+			}
+		})
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	originalTerminal, originalStdin, originalStderr := stdinTerminal, stdin, stderr
+	t.Cleanup(func() {
+		stdinTerminal, stdin, stderr = originalTerminal, originalStdin, originalStderr
+		assumeYes = false
+	})
+	cost := &spend{Amount: 12, Currency: "USD"}
+
+	tests := []struct {
+		name      string
+		yes       bool
+		terminal  bool
+		input     string
+		confirmed bool
+		stderr    string
+	}{
+		{"yes", false, true, "y\n", true, "Register example.com for $12.00 USD? [y/N] "},
+		{"yes in full", false, true, "YES\n", true, ""},
+		{"no", false, true, "n\n", false, ""},
+		{"default is no", false, true, "\n", false, ""},
+		{"closed stdin", false, true, "", false, ""},
+		{"--yes", true, false, "", true, ""},
+		{"no terminal", false, false, "y\n", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errOut bytes.Buffer
+			assumeYes = tt.yes
+			stdinTerminal = func() bool { return tt.terminal }
+			stdin, stderr = strings.NewReader(tt.input), &errOut
+
+			err := confirm("Register example.com", cost)
+			if confirmed := err == nil; confirmed != tt.confirmed {
+				t.Fatalf("Expected confirmed %v, got %v", tt.confirmed, err)
+			}
+			if !strings.Contains(errOut.String(), tt.stderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.stderr, errOut.String())
+			}
+			if !tt.terminal && !tt.yes && (err == nil || !strings.Contains(err.Error(), "use --yes")) {
+				t.Errorf("Expected to be told about --yes, got %v", err)
+			}
+			if (tt.yes || !tt.terminal) && errOut.Len() > 0 {
+				t.Errorf("Expected no prompt, got %q", errOut.String())
+			}
+		})
+	}
+
+	// Each prompt reads only its own answer
+	assumeYes = false
+	stdinTerminal = func() bool { return true }
+	stdin, stderr = strings.NewReader("y\nn\n"), io.Discard
+	if err := confirm("Renew example.com", nil); err != nil {
+		t.Errorf("Expected the first action to be confirmed, got %v", err)
+	}
+	if err := confirm("Lock example.com", nil); err == nil || !strings.Contains(err.Error(), "cancelled: Lock example.com was not confirmed") {
+		t.Errorf("Expected the second action to be declined, got %v", err)
+	}
+
+	// Commands that confirm take -y for --yes
+	cmd := &cobra.Command{}
+	addConfirmFlags(cmd)
+	if err := cmd.Flags().Parse([]string{"-y"}); err != nil || !assumeYes {
+		t.Errorf("Expected -y to set --yes, got %v", err)
+	}
+}
+
+// fixedFallback answers every fallback lookup with status
+type fixedFallback struct {
+	status domain.AvailabilityStatus
+}
+
+func (f fixedFallback) Name() string {
+	return "whois"
+}
+
+func (f fixedFallback) Lookup(ctx context.Context, name string) (domain.AvailabilityStatus, string, error) {
+	return f.status, "answered by WHOIS", nil
+}
+
+func TestFallbackFlag(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		TLDs("com").
+		Client()
+
+	original := newFallback
+	newFallback = func(name string) domain.Fallback { return fixedFallback{status: domain.StatusAvailable} }
+	t.Cleanup(func() { newFallback = original })
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCode customErrors.ExitCode
+		stdout       string
+		stderr       string
+	}{
+		{"unsupported TLD", []string{"check", "--fallback", "whois", "example.ly"}, customErrors.ExitSuccess,
+			"example.ly is AVAILABLE for registration\n⚠ Unverified: answered by whois, not Route 53", ""},
+		{"supported TLD", []string{"check", "--fallback", "whois", "free.com"}, customErrors.ExitSuccess, "free.com is AVAILABLE for registration\n", ""},
+		{"without --fallback", []string{"check", "example.ly"}, customErrors.ExitValidation, "", "unsupported TLD: .ly"},
+		{"mistyped value", []string{"check", "--fallback", "whoiz", "example.ly"}, customErrors.ExitValidation, "", `(did you mean "whois"?)`},
+		{"bulk JSON", []string{"-o", "json", "bulk", "--fallback", "whois", "example.ly", "free.com"}, customErrors.ExitSuccess,
+			`"source": "whois",`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, client, tt.args...)
+			if code != int(tt.expectedCode) {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.expectedCode, code, stderr)
+			}
+			if !strings.Contains(stdout, tt.stdout) {
+				t.Errorf("Expected stdout to contain %q, got %q", tt.stdout, stdout)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.stderr, stderr)
+			}
+			if tt.name == "supported TLD" && strings.Contains(stdout, "Unverified") {
+				t.Errorf("Expected Route 53 results not to be marked unverified, got %q", stdout)
+			}
+		})
+	}
+
+	if client.CallCount("example.ly") != 0 {
+		t.Errorf("Expected Route 53 not to be asked about example.ly, got %d calls", client.CallCount("example.ly"))
+	}
+}
+
+// registeredPrescreener reports the domains in it as registered
+type registeredPrescreener map[string]bool
+
+func (p registeredPrescreener) Registered(ctx context.Context, name string) (bool, error) {
+	return p[name], nil
+}
+
+func TestPrescreenFlag(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Unavailable("taken.com", "parked.com").
+		TLDs("com").
+		Client()
+
+	original := newPrescreener
+	newPrescreener = func(name string) domain.Prescreener { return registeredPrescreener{"taken.com": true} }
+	t.Cleanup(func() { newPrescreener = original })
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCode customErrors.ExitCode
+		stdout       string
+		stderr       string
+	}{
+		{"console", []string{"bulk", "--prescreen", "dns", "taken.com", "free.com", "parked.com"}, customErrors.ExitSuccess,
+			"taken.com: UNAVAILABLE (already registered)\n  ≈ Prescreened: registered according to DNS, Route 53 was not asked\n", ""},
+		{"summary", []string{"bulk", "--prescreen", "dns", "taken.com", "free.com", "parked.com"}, customErrors.ExitSuccess,
+			"  ✗ Unavailable: 2\n  ≈ Prescreened: 1\n", ""},
+		{"JSON", []string{"-o", "json", "bulk", "--prescreen", "dns", "taken.com", "free.com"}, customErrors.ExitSuccess,
+			`"prescreened": true`, ""},
+		{"mistyped value", []string{"bulk", "--prescreen", "dnss", "taken.com"}, customErrors.ExitValidation, "", `(did you mean "dns"?)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, client, tt.args...)
+			if code != int(tt.expectedCode) {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.expectedCode, code, stderr)
+			}
+			if !strings.Contains(stdout, tt.stdout) {
+				t.Errorf("Expected stdout to contain %q, got %q", tt.stdout, stdout)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.stderr, stderr)
+			}
+		})
+	}
+
+	if client.CallCount("taken.com") != 0 {
+		t.Errorf("Expected Route 53 not to be asked about taken.com, got %d calls", client.CallCount("taken.com"))
+	}
+	if client.CallCount("parked.com") == 0 {
+		t.Error("Expected Route 53 to be asked about parked.com, which DNS did not show registered")
+	}
+}
+
+// tablePrices quotes registration prices in USD from a map
+type tablePrices map[string]float64
+
+func (p tablePrices) Name() string {
+	return "porkbun"
+}
+
+func (p tablePrices) Price(ctx context.Context, tld string) (*domain.PricingInfo, error) {
+	price, ok := p[tld]
+	if !ok {
+		return nil, nil
+	}
+	return &domain.PricingInfo{RegistrationPrice: &price, Currency: "USD"}, nil
+}
+
+func TestComparePriceCommand(t *testing.T) {
+	client := r53checktest.NewScenario().TLDs("com", "io").Client()
+
+	original := newPriceProvider
+	newPriceProvider = func(name string) pricing.Provider { return tablePrices{"com": 11.08} }
+	t.Cleanup(func() { newPriceProvider = original })
+
+	table := filepath.Join(t.TempDir(), "registrars.csv")
+	if err := os.WriteFile(table, []byte("registrar,tld,registration,currency\ngandi,com,8.50,EUR\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCode customErrors.ExitCode
+		stdout       string
+		stderr       string
+	}{
+		{"Route 53 only", []string{"compare-price", "example.com"}, customErrors.ExitSuccess,
+			"Prices for .com (example.com):\n  route53  $15.00 USD, renewal $15.00 USD, transfer $15.00 USD\nCheapest: route53 at $15.00 USD\n", ""},
+		{"cheaper elsewhere", []string{"compare-price", "example.com", "--provider", "route53,porkbun"}, customErrors.ExitSuccess,
+			"  porkbun  $11.08 USD\n  route53  $15.00 USD, renewal $15.00 USD, transfer $15.00 USD\nCheapest: porkbun at $11.08 USD, $3.92 USD less than Route 53\n", ""},
+		{"not offered", []string{"compare-price", "example.io", "--provider", "porkbun,route53"}, customErrors.ExitSuccess,
+			"  porkbun  does not offer .io\n", ""},
+		{"price table", []string{"compare-price", "example.com", "--prices", table}, customErrors.ExitSuccess,
+			"Cheapest: gandi at $8.50 EUR\nCheapest: route53 at $15.00 USD\n", ""},
+		{"JSON", []string{"-o", "json", "compare-price", "example.com", "--provider", "route53,porkbun"}, customErrors.ExitSuccess,
+			`"USD": "porkbun"`, ""},
+		{"mistyped provider", []string{"compare-price", "example.com", "--provider", "porkbn"}, customErrors.ExitValidation,
+			"", `(did you mean "porkbun"?)`},
+		{"missing table", []string{"compare-price", "example.com", "--prices", "missing.csv"}, customErrors.ExitValidation,
+			"", "invalid price table missing.csv"},
+		{"not a domain", []string{"compare-price", "localhost"}, customErrors.ExitValidation,
+			"", "expected a domain such as example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, client, tt.args...)
+			if code != int(tt.expectedCode) {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.expectedCode, code, stderr)
+			}
+			if !strings.Contains(stdout, tt.stdout) {
+				t.Errorf("Expected stdout to contain %q, got %q", tt.stdout, stdout)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.stderr, stderr)
+			}
+		})
+	}
+}
+
+func TestExportCommand(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com", "1st.com").
+		Unavailable("taken.com").
+		TLDs("com").
+		Client()
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCode customErrors.ExitCode
+		stdout       []string
+		stderr       string
+	}{
+		{"available only", []string{"export", "--format", "terraform", "free.com", "taken.com"}, customErrors.ExitSuccess,
+			[]string{"resource \"aws_route53domains_registered_domain\" \"free_com\" {\n  domain_name = \"free.com\"\n  auto_renew  = true\n}\n"},
+			"Not exported: taken.com is UNAVAILABLE"},
+		{"hosted zone", []string{"export", "--hosted-zone", "--auto-renew=false", "1st.com"}, customErrors.ExitSuccess,
+			[]string{
+				"resource \"aws_route53_zone\" \"_1st_com\" {\n  name = \"1st.com\"\n}\n",
+				"  auto_renew  = false\n",
+				"    for_each = aws_route53_zone._1st_com.name_servers\n",
+			}, ""},
+		{"pricing", []string{"--price", "export", "free.com"}, customErrors.ExitSuccess,
+			[]string{"; registration $15.00 USD\n"}, ""},
+		{"nothing available", []string{"export", "taken.com"}, customErrors.ExitValidation,
+			nil, "none of the domains is available"},
+		{"mistyped format", []string{"export", "--format", "terraform2", "free.com"}, customErrors.ExitValidation,
+			nil, `(did you mean "terraform"?)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, client, tt.args...)
+			if code != int(tt.expectedCode) {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.expectedCode, code, stderr)
+			}
+			for _, expected := range tt.stdout {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("Expected stdout to contain %q, got %q", expected, stdout)
+				}
+			}
+			if tt.expectedCode != customErrors.ExitSuccess && stdout != "" {
+				t.Errorf("Expected no configuration on failure, got %q", stdout)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.stderr, stderr)
+			}
+		})
+	}
+}
+
+func TestTerraformName(t *testing.T) {
+	tests := map[string]string{
+		"example.com":       "example_com",
+		"my-site.co.uk":     "my-site_co_uk",
+		"1password.com":     "_1password_com",
+		"xn--mnchen-3ya.de": "xn--mnchen-3ya_de",
+	}
+	for name, expected := range tests {
+		if got := terraformName(name); got != expected {
+			t.Errorf("terraformName(%q) = %q, want %q", name, got, expected)
+		}
+	}
+}
+
+// fakeZoneSource lists fixed hosted zones and registered domains
+type fakeZoneSource struct {
+	zones      []string
+	registered []string
+	err        error
+}
+
+func (f fakeZoneSource) HostedZones(ctx context.Context) ([]string, error) {
+	return f.zones, f.err
+}
+
+func (f fakeZoneSource) RegisteredDomains(ctx context.Context) ([]string, error) {
+	return f.registered, nil
+}
+
+func TestFromHostedZonesFlag(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("dangling.com").
+		Unavailable("elsewhere.io").
+		TLDs("com", "io").
+		Client()
+
+	tests := []struct {
+		name         string
+		source       fakeZoneSource
+		args         []string
+		expectedCode customErrors.ExitCode
+		stdout       []string
+		stderr       string
+	}{
+		{"dangling zones", fakeZoneSource{
+			zones:      []string{"owned.com", "dangling.com", "api.dangling.com", "elsewhere.io"},
+			registered: []string{"OWNED.com"},
+		}, []string{"bulk", "--from-hosted-zones"}, customErrors.ExitSuccess,
+			[]string{"dangling.com: AVAILABLE\n  ✎ hosted zone dangling.com, api.dangling.com\n", "elsewhere.io: UNAVAILABLE", "Bulk Domain Check Results (2 domains)"}, ""},
+		{"all registered", fakeZoneSource{zones: []string{"owned.com"}, registered: []string{"owned.com"}},
+			[]string{"bulk", "--from-hosted-zones"}, customErrors.ExitSuccess,
+			[]string{"Every public hosted zone belongs to a domain registered in this account; nothing to check\n"}, ""},
+		{"with domains", fakeZoneSource{}, []string{"bulk", "--from-hosted-zones", "example.com"}, customErrors.ExitValidation,
+			nil, "do not also give domains or --file"},
+		{"listing fails", fakeZoneSource{err: customErrors.NewAuthorizationError("ListHostedZones", "route53", "access denied", nil)},
+			[]string{"bulk", "--from-hosted-zones"}, customErrors.ExitAuthorization, nil, "Authorization Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := newZoneSource
+			newZoneSource = func(ctx context.Context) (zoneSource, error) { return tt.source, nil }
+			t.Cleanup(func() { newZoneSource = original })
+
+			code, stdout, stderr := runCLI(t, client, tt.args...)
+			if code != int(tt.expectedCode) {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.expectedCode, code, stderr)
+			}
+			for _, expected := range tt.stdout {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("Expected stdout to contain %q, got %q", expected, stdout)
+				}
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.stderr, stderr)
+			}
+		})
+	}
+
+	if client.CallCount("owned.com") != 0 {
+		t.Errorf("Expected domains registered in the account not to be checked, got %d calls", client.CallCount("owned.com"))
+	}
+}
+
+// fakeAuditSource is an account with the given domains, their name
+// servers and hosted zones
+type fakeAuditSource struct {
+	domains     []string
+	nameServers map[string][]string
+	zones       []aws.HostedZone
+	err         error
+}
+
+func (s fakeAuditSource) RegisteredDomains(ctx context.Context) ([]string, error) {
+	return s.domains, s.err
+}
+
+func (s fakeAuditSource) DomainNameServers(ctx context.Context, domain string) ([]string, error) {
+	return s.nameServers[domain], nil
+}
+
+func (s fakeAuditSource) HostedZoneDelegations(ctx context.Context) ([]aws.HostedZone, error) {
+	return s.zones, nil
+}
+
+func TestAuditDNSCommand(t *testing.T) {
+	account := fakeAuditSource{
+		domains: []string{"owned.com", "deleted.com", "elsewhere.io"},
+		nameServers: map[string][]string{
+			"owned.com":    {"ns-1.awsdns-01.com", "ns-2.awsdns-02.net"},
+			"deleted.com":  {"ns-3.awsdns-03.com"},
+			"elsewhere.io": {"ns1.other-dns.net"},
+		},
+		zones: []aws.HostedZone{{ID: "Z1", Name: "owned.com", NameServers: []string{"ns-1.awsdns-01.com", "ns-2.awsdns-02.net"}}},
+	}
+
+	tests := []struct {
+		name         string
+		source       fakeAuditSource
+		args         []string
+		expectedCode customErrors.ExitCode
+		stdout       []string
+		stderr       string
+	}{
+		{"console", account, []string{"audit-dns"}, customErrors.ExitSuccess,
+			[]string{"DNS delegation audit (3 domains):", "✓ owned.com: OK", "✗ deleted.com: DANGLING_ZONE", "⚠ elsewhere.io: THIRD_PARTY_NS",
+				"Summary: 1 dangling, 1 third-party, 1 OK\n"}, ""},
+		{"fail on risk", account, []string{"audit-dns", "--fail-on-risk"}, customErrors.ExitPartialFailure,
+			[]string{"✗ deleted.com: DANGLING_ZONE"}, "Audit failed: 1 domain with a dangling zone"},
+		{"json", account, []string{"audit-dns", "--output", "json"}, customErrors.ExitSuccess,
+			[]string{`"status": "DANGLING_ZONE"`, `"hosted_zones": [`, `"DANGLING_ZONE": 1`}, ""},
+		{"no domains", fakeAuditSource{}, []string{"audit-dns", "--fail-on-risk"}, customErrors.ExitSuccess,
+			[]string{"No domains are registered to this account\n"}, ""},
+		{"listing fails", fakeAuditSource{err: customErrors.NewAuthorizationError("ListDomains", "route53domains", "access denied", nil)},
+			[]string{"audit-dns"}, customErrors.ExitAuthorization, nil, "Authorization Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := newAuditSource
+			newAuditSource = func(ctx context.Context) (audit.Source, error) { return tt.source, nil }
+			t.Cleanup(func() { newAuditSource = original })
+
+			code, stdout, stderr := runCLI(t, r53checktest.NewScenario().Client(), tt.args...)
+			if code != int(tt.expectedCode) {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.expectedCode, code, stderr)
+			}
+			for _, expected := range tt.stdout {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("Expected stdout to contain %q, got %q", expected, stdout)
+				}
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.stderr, stderr)
+			}
+		})
+	}
+}
+
+// fakePortfolio is an account with the given domains, their detail and
+// hosted zones
+type fakePortfolio struct {
+	summaries []aws.DomainSummary
+	details   map[string]aws.DomainDetail
+	zones     []aws.HostedZone
+}
+
+func (p fakePortfolio) DomainSummaries(ctx context.Context) ([]aws.DomainSummary, error) {
+	return p.summaries, nil
+}
+
+func (p fakePortfolio) DomainDetail(ctx context.Context, domain string) (aws.DomainDetail, error) {
+	return p.details[domain], nil
+}
+
+func (p fakePortfolio) HostedZoneDelegations(ctx context.Context) ([]aws.HostedZone, error) {
+	return p.zones, nil
+}
+
+func TestAuditCommand(t *testing.T) {
+	servers := []string{"ns-1.awsdns-01.com"}
+	soon := time.Now().AddDate(0, 0, 30)
+	account := fakePortfolio{
+		summaries: []aws.DomainSummary{
+			{Name: "owned.com", AutoRenew: true, TransferLock: true, Expiry: time.Now().AddDate(1, 0, 0)},
+			{Name: "lapsing.io", Expiry: soon},
+		},
+		details: map[string]aws.DomainDetail{
+			"owned.com":  {NameServers: servers, DNSSEC: true, ContactEmails: map[string]string{"registrant": "ops@corp.net"}},
+			"lapsing.io": {NameServers: servers, ContactEmails: map[string]string{"registrant": "no-reply@corp.net"}},
+		},
+		zones: []aws.HostedZone{
+			{ID: "Z1", Name: "owned.com", NameServers: servers},
+			{ID: "Z2", Name: "lapsing.io", NameServers: servers},
+		},
+	}
+	healthy := fakePortfolio{summaries: account.summaries[:1], details: account.details, zones: account.zones}
+
+	tests := []struct {
+		name   string
+		source fakePortfolio
+		args   []string
+		code   customErrors.ExitCode
+		stdout []string
+		stderr string
+	}{
+		{"console", account, nil, customErrors.ExitPartialFailure,
+			[]string{"Portfolio audit (2 domains):", "✗ lapsing.io (expires " + soon.Format("2006-01-02") + ")", "auto_renew: auto-renewal is off",
+				"transfer_lock: the transfer lock is off", "dnssec: DNSSEC is not enabled", "contact_email: the registrant email no-reply@corp.net is a no-reply address",
+				"✓ owned.com", "Summary: 1 of 2 domains flagged; 1 expiry, 1 auto_renew, 1 transfer_lock, 1 dnssec, 1 contact_email"}, "Audit failed: 1 domain flagged"},
+		{"expiry window", account, []string{"--expiring-within", "7"}, customErrors.ExitPartialFailure,
+			[]string{"Summary: 1 of 2 domains flagged; 1 auto_renew"}, ""},
+		{"skipped checks", account, []string{"--skip", "auto_renew,transfer_lock,dnssec,contact_email,expiry"}, customErrors.ExitSuccess,
+			[]string{"✓ lapsing.io", "Summary: 0 of 2 domains flagged; no issues"}, ""},
+		{"unknown check", account, []string{"--skip", "whois"}, customErrors.ExitValidation, nil, `unknown check "whois"`},
+		{"json", account, []string{"--output", "json"}, customErrors.ExitPartialFailure,
+			[]string{`"check": "auto_renew"`, `"flagged": 1`, `"transfer_lock": 1`}, ""},
+		{"healthy", healthy, nil, customErrors.ExitSuccess, []string{"✓ owned.com"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := newPortfolioSource
+			newPortfolioSource = func(ctx context.Context) (audit.PortfolioSource, error) { return tt.source, nil }
+			t.Cleanup(func() { newPortfolioSource = original })
+
+			code, stdout, stderr := runCLI(t, r53checktest.NewScenario().Client(), append([]string{"audit"}, tt.args...)...)
+			if code != int(tt.code) {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.code, code, stderr)
+			}
+			for _, expected := range tt.stdout {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("Expected stdout to contain %q, got %q", expected, stdout)
+				}
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.stderr, stderr)
+			}
+		})
+	}
+}
+
+func TestPricesRefreshCommand(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Price("example", r53checktest.Price{Registration: 14, Renewal: 16}).
+		Client()
+	tlds := len(r53checktest.DefaultPrices()) + 1
+
+	code, stdout, stderr := runCLI(t, client, "prices", "refresh")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	path := filepath.Join(os.Getenv("XDG_CACHE_HOME"), "r53check", "prices.json")
+	if expected := fmt.Sprintf("Cached the prices of %d TLDs in %s\n", tlds, path); stdout != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout)
+	}
+	if data, err := os.ReadFile(path); err != nil || !strings.Contains(string(data), `"example":{"registration":14,"renewal":16`) {
+		t.Errorf("Expected the price list to be cached, got %s (%v)", data, err)
+	}
+
+	code, stdout, _ = runCLI(t, client, "-o", "json", "prices", "refresh")
+	if code != int(customErrors.ExitSuccess) || !strings.Contains(stdout, fmt.Sprintf(`"tlds": %d`, tlds)) {
+		t.Errorf("Expected the number of TLDs cached in JSON, got exit code %d: %s", code, stdout)
+	}
+}
+
+func TestPricesHistoryCommand(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Available("free.com").
+		Price("example", r53checktest.Price{Registration: 14, Renewal: 16}).
+		TLDs("com").
+		Client()
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	if code, _, stderr := runCLI(t, client, "prices", "refresh", "--history", path); code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+
+	// A changed price is recorded and listed by the next refresh
+	client.SetPrice("example", r53checktest.Price{Registration: 12, Renewal: 16})
+	code, stdout, stderr := runCLI(t, client, "prices", "refresh", "--history", path)
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if want := "  .example: 14.00 USD (renewal 16.00 USD) -> 12.00 USD (renewal 16.00 USD)\n"; !strings.HasSuffix(stdout, want) {
+		t.Errorf("Expected the price change to be listed, got %q", stdout)
+	}
+
+	code, stdout, _ = runCLI(t, client, "prices", "history", ".Example", "--history", path)
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d", code)
+	}
+	for _, want := range []string{".example: 2 prices recorded", "14.00 USD (renewal 16.00 USD)", "12.00 USD (renewal 16.00 USD)  -2.00"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, stdout)
+		}
+	}
+
+	// Pricing-enabled checks record the prices they see
+	client.SetPrice("com", r53checktest.Price{Registration: 9})
+	if code, _, stderr := runCLI(t, client, "bulk", "--price", "--history", path, "free.com"); code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	code, stdout, _ = runCLI(t, client, "-o", "json", "prices", "history", "com", "--history", path)
+	var out struct {
+		TLD    string                `json:"tld"`
+		Prices []history.PriceRecord `json:"prices"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil || code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected the JSON price history, got exit code %d: %v\n%s", code, err, stdout)
+	}
+	if out.TLD != "com" || len(out.Prices) != 2 || *out.Prices[1].Registration != 9 {
+		t.Errorf("Unexpected price history %+v", out)
+	}
+
+	if code, stdout, _ := runCLI(t, client, "prices", "history", "zz", "--history", path); code != int(customErrors.ExitSuccess) || stdout != ".zz: no prices recorded\n" {
+		t.Errorf("Expected no prices for .zz, got exit code %d: %q", code, stdout)
+	}
+}
+
+func TestBudgetCommand(t *testing.T) {
+	client := r53checktest.NewClient()
+
+	code, stdout, stderr := runCLI(t, client, "budget")
+	if code != int(customErrors.ExitSuccess) {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", code, stderr)
+	}
+	if !strings.HasPrefix(stdout, "No budget is set") {
+		t.Errorf("Expected no budget, got %q", stdout)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("budget:\n  per_run: 100\n  per_month: 500\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	code, stdout, _ = runCLI(t, client, "--config", configPath, "budget")
+	for _, want := range []string{"Per run:   $100.00", "Per month: $500.00, $500.00 left", "Spent in " + time.Now().Format("2006-01") + ": $0.00"} {
+		if code != int(customErrors.ExitSuccess) || !strings.Contains(stdout, want) {
+			t.Errorf("Expected output to contain %q, got exit code %d:\n%s", want, code, stdout)
+		}
+	}
+
+	code, stdout, _ = runCLI(t, client, "--config", configPath, "-o", "json", "budget")
+	if code != int(customErrors.ExitSuccess) || !strings.Contains(stdout, `"remaining": 500`) {
+		t.Errorf("Expected the remaining budget in JSON, got exit code %d: %s", code, stdout)
+	}
+}
+
+func TestCheckBudget(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	budgetSettings = config.BudgetSettings{PerRun: 50}
+	defer func() { budgetSettings, overrideBudget = config.BudgetSettings{}, false }()
+	var errOut bytes.Buffer
+	stderr = &errOut
+
+	purchases := []budget.Purchase{{Domain: "example.com", Kind: "register", Amount: 60, Currency: "USD", At: time.Now()}}
+	if _, err := checkBudget(purchases); !errors.Is(err, budget.ErrExceeded) {
+		t.Fatalf("Expected the purchase to be refused, got %v", err)
+	}
+
+	overrideBudget = true
+	guard, err := checkBudget(purchases)
+	if err != nil || !strings.Contains(errOut.String(), "going over the budget") {
+		t.Fatalf("Expected --override-budget to allow the purchase with a warning, got %v (stderr: %s)", err, errOut.String())
+	}
+	if err := guard.Record(purchases); err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+	if status, _ := guard.Status(); status.Spent != 60 {
+		t.Errorf("Expected the purchase to be recorded, got %+v", status)
+	}
+}
+
+// fakeRegistrar registers every domain but those in refused, each
+// operation finishing as soon as it is polled
+type fakeRegistrar struct {
+	mu        sync.Mutex
+	refused   map[string]bool
+	err       error
+	attempts  int
+	submitted []string
+	years     int
+	options   aws.RegistrationOptions
+	// before, when set, is called with each domain before it is submitted
+	before func(domain string)
+}
+
+func (f *fakeRegistrar) Register(ctx context.Context, domain string, years int) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts++
+	if f.before != nil {
+		f.before(domain)
+	}
+	// Like the AWS SDK, a cancelled request is not sent
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if f.refused[domain] {
+		if f.err != nil {
+			return "", f.err
+		}
+		return "", errors.New("DomainLimitExceeded: too many domains")
+	}
+	f.submitted = append(f.submitted, domain)
+	f.years = years
+	return "op-" + domain, nil
+}
+
+func (f *fakeRegistrar) OperationStatus(ctx context.Context, operationID string) (string, string, error) {
+	return "SUCCESSFUL", "", nil
+}
+
+// registerContactConfig is a config file section with the registration
+// contact
+const registerContactConfig = `register:
+  contact:
+    first_name: Ada
+    last_name: Lovelace
+    email: ada@example.com
+    phone: "+44.2071234567"
+    address_line_1: 12 St James's Square
+    city: London
+    country_code: GB
+    zip_code: SW1Y 4JH
+`
+
+func TestRegisterCommand(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Price("com", r53checktest.Price{Registration: 14, Renewal: 16, Transfer: 12}).
+		Price("io", r53checktest.Price{Registration: 39, Renewal: 39, Transfer: 39}).
+		TLDs("com", "io", "dev").
+		Client()
+
+	dir := t.TempDir()
+	resultsPath := filepath.Join(dir, "results.json")
+	results := `{"results": [
+  {"domain": "a.com", "available": true, "status": "AVAILABLE"},
+  {"domain": "taken.com", "available": false, "status": "UNAVAILABLE"},
+  {"domain": "bee.io", "available": true, "status": "AVAILABLE"}
+], "summary": {"total": 3}}`
+	if err := os.WriteFile(resultsPath, []byte(results), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte(registerContactConfig+"budget:\n  per_run: 100\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	noContactPath := filepath.Join(dir, "empty.yaml")
+	if err := os.WriteFile(noContactPath, []byte("budget:\n  per_run: 100\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		refused   map[string]bool
+		code      customErrors.ExitCode
+		submitted []string
+		stdout    []string
+		stderr    string
+	}{
+		{"available domains", []string{"--from", resultsPath, "--only-available", "-y"}, nil, customErrors.ExitSuccess,
+			[]string{"a.com", "bee.io"}, []string{"Registering 2 domains for 1 year:", "a.com   $   14.00", "Total   $   53.00 USD", "… a.com submitted (operation op-a.com)", "2 of 2 registrations submitted"}, ""},
+		{"selected domain for 2 years", []string{"--from", resultsPath, "--only-available", "--years", "2", "--wait", "1m", "-y", "a.com"}, nil, customErrors.ExitSuccess,
+			[]string{"a.com"}, []string{"Total  $   30.00 USD", "✓ a.com registered (operation op-a.com)"}, ""},
+		{"domain not in the results", []string{"--from", resultsPath, "--only-available", "-y", "taken.com"}, nil, customErrors.ExitValidation,
+			nil, nil, "taken.com is not an available domain"},
+		{"unavailable domains in the results", []string{"--from", resultsPath, "-y"}, nil, customErrors.ExitValidation,
+			nil, nil, "use --only-available"},
+		{"partial failure", []string{"--from", resultsPath, "--only-available", "-y"}, map[string]bool{"a.com": true}, customErrors.ExitPartialFailure,
+			[]string{"bee.io"}, []string{"✗ a.com: DomainLimitExceeded", "1 of 2 registrations submitted, 1 failed"}, ""},
+		{"over --max-total", []string{"--from", resultsPath, "--only-available", "--max-total", "50", "-y"}, nil, customErrors.ExitValidation,
+			nil, []string{"Total   $   53.00 USD"}, "over --max-total $50.00"},
+		{"over the budget", []string{"--from", resultsPath, "--only-available", "--years", "2", "-y"}, nil, customErrors.ExitValidation,
+			nil, nil, "over the per-run budget"},
+		{"budget overridden", []string{"--from", resultsPath, "--only-available", "--years", "2", "--override-budget", "-y"}, nil, customErrors.ExitSuccess,
+			[]string{"a.com", "bee.io"}, []string{"Total   $  108.00 USD"}, "going over the budget"},
+		{"dry run", []string{"--from", resultsPath, "--only-available", "--dry-run"}, nil, customErrors.ExitSuccess,
+			nil, []string{"Registering 2 domains for 1 year:"}, ""},
+		{"not confirmed", []string{"a.com"}, nil, customErrors.ExitValidation,
+			nil, nil, "needs confirmation; use --yes"},
+		{"no domains", nil, nil, customErrors.ExitValidation, nil, nil, "give the domains to register"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registrar := &fakeRegistrar{refused: tt.refused}
+			original, originalInterval := newRegistrar, registerPollInterval
+			newRegistrar = func(ctx context.Context, options aws.RegistrationOptions) (register.Registrar, error) {
+				registrar.options = options
+				return registrar, nil
+			}
+			registerPollInterval = time.Millisecond
+			t.Cleanup(func() { newRegistrar, registerPollInterval = original, originalInterval })
+
+			args := append([]string{"--config", configPath, "register"}, tt.args...)
+			code, stdout, stderr := runCLI(t, client, args...)
+			if code != int(tt.code) {
+				t.Fatalf("Expected exit code %d, got %d (stdout: %s, stderr: %s)", tt.code, code, stdout, stderr)
+			}
+			if !reflect.DeepEqual(registrar.submitted, tt.submitted) {
+				t.Errorf("Expected %v to be submitted, got %v", tt.submitted, registrar.submitted)
+			}
+			for _, want := range tt.stdout {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("Expected stdout to contain %q, got:\n%s", want, stdout)
+				}
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.stderr, stderr)
+			}
+		})
+	}
+
+	t.Run("missing contact", func(t *testing.T) {
+		code, _, stderr := runCLI(t, client, "--config", noContactPath, "register", "-y", "a.com")
+		if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "missing first_name") {
+			t.Errorf("Expected the missing contact to be reported, got exit code %d: %s", code, stderr)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		registrar := &fakeRegistrar{}
+		original := newRegistrar
+		newRegistrar = func(ctx context.Context, options aws.RegistrationOptions) (register.Registrar, error) {
+			registrar.options = options
+			return registrar, nil
+		}
+		t.Cleanup(func() { newRegistrar = original })
+
+		code, stdout, _ := runCLI(t, client, "--config", configPath, "-o", "json", "register", "--privacy=false", "-y", "bee.io")
+		var result registrationJSON
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil || code != int(customErrors.ExitSuccess) {
+			t.Fatalf("Expected JSON output, got exit code %d, %v: %s", code, err, stdout)
+		}
+		if result.Total != 39 || result.Submitted != 1 || result.Domains[0].OperationID != "op-bee.io" {
+			t.Errorf("Unexpected result %+v", result)
+		}
+		if registrar.options.Privacy.Registrant || !registrar.options.AutoRenew || *registrar.options.Registrant.FirstName != "Ada" || *registrar.options.Tech.FirstName != "Ada" {
+			t.Errorf("Unexpected registration options %+v", registrar.options)
+		}
+	})
+
+	t.Run("no answer recorded as unconfirmed", func(t *testing.T) {
+		ledger := useFakeTagger(t, &fakeTagger{})
+		registrar := &fakeRegistrar{refused: map[string]bool{"a.com": true},
+			err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}}
+		original := newRegistrar
+		newRegistrar = func(ctx context.Context, options aws.RegistrationOptions) (register.Registrar, error) {
+			return registrar, nil
+		}
+		t.Cleanup(func() { newRegistrar = original })
+
+		code, stdout, stderr := runCLI(t, client, "--config", configPath, "register", "--from", resultsPath, "--only-available", "-y")
+		if code != int(customErrors.ExitPartialFailure) || !strings.Contains(stderr, "a.com; it may have been registered") {
+			t.Errorf("Expected a warning and exit code %d, got %d (stdout: %s, stderr: %s)", customErrors.ExitPartialFailure, code, stdout, stderr)
+		}
+		purchases, err := ledger.Purchases()
+		if err != nil || len(purchases) != 2 {
+			t.Fatalf("Expected both domains in the ledger, got %+v, %v", purchases, err)
+		}
+		if !purchases[0].Unconfirmed || purchases[0].Domain != "a.com" || purchases[1].Unconfirmed {
+			t.Errorf("Expected only a.com to be unconfirmed, got %+v", purchases)
+		}
+	})
+
+	t.Run("each registration recorded once accepted", func(t *testing.T) {
+		ledger := useFakeTagger(t, &fakeTagger{})
+		var recorded []string
+		registrar := &fakeRegistrar{before: func(domain string) {
+			purchases, err := ledger.Purchases()
+			if err != nil {
+				t.Errorf("Purchases() error: %v", err)
+			}
+			recorded = append(recorded, fmt.Sprintf("%s:%d", domain, len(purchases)))
+		}}
+		original := newRegistrar
+		newRegistrar = func(ctx context.Context, options aws.RegistrationOptions) (register.Registrar, error) {
+			return registrar, nil
+		}
+		t.Cleanup(func() { newRegistrar = original })
+
+		code, stdout, stderr := runCLI(t, client, "--config", configPath, "register", "--from", resultsPath, "--only-available", "-y")
+		if code != int(customErrors.ExitSuccess) {
+			t.Fatalf("Expected success, got exit code %d (stdout: %s, stderr: %s)", code, stdout, stderr)
+		}
+		// bee.io is submitted after a.com is in the ledger
+		if want := []string{"a.com:0", "bee.io:1"}; !reflect.DeepEqual(recorded, want) {
+			t.Errorf("Expected the ledger to hold %v before each submission, got %v", want, recorded)
+		}
+	})
+}
+
+func TestWatchCommand_RegisterOnAvailable(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte(registerContactConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	newClient := func() *r53checktest.Client {
+		client := r53checktest.NewScenario().
+			Price("com", r53checktest.Price{Registration: 14, Renewal: 16, Transfer: 12}).
+			TLDs("com").
+			Client()
+		client.Script("drop.com",
+			r53checktest.Respond(types.DomainAvailabilityUnavailable),
+			r53checktest.Respond(types.DomainAvailabilityAvailable))
+		return client
+	}
+
+	originalContext, originalRegistrar := watchContext, newRegistrar
+	t.Cleanup(func() { watchContext, newRegistrar = originalContext, originalRegistrar })
+	watchContext = func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), 5*time.Second)
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		refused   map[string]bool
+		code      customErrors.ExitCode
+		submitted []string
+		output    string
+	}{
+		{"registers once available", []string{"drop.com", "--register-max-price", "20", "--confirm-register", "drop.com"}, nil,
+			customErrors.ExitSuccess, []string{"drop.com"}, "✓ drop.com: registration submitted for $14.00 USD (operation op-drop.com)"},
+		{"over the price cap", []string{"drop.com", "--register-max-price", "10", "--confirm-register", "drop.com"}, nil,
+			customErrors.ExitValidation, nil, "costs $14.00, over --register-max-price $10.00"},
+		{"without a price cap", []string{"drop.com", "--confirm-register", "drop.com"}, nil,
+			customErrors.ExitValidation, nil, "needs --register-max-price"},
+		{"without confirmation", []string{"drop.com", "--register-max-price", "20", "--confirm-register", "other.com"}, nil,
+			customErrors.ExitValidation, nil, "confirm it with --confirm-register drop.com"},
+		{"more than one domain", []string{"drop.com", "taken.com", "--register-max-price", "20", "--confirm-register", "drop.com"}, nil,
+			customErrors.ExitValidation, nil, "watches a single domain, got 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registrar := &fakeRegistrar{refused: tt.refused}
+			newRegistrar = func(ctx context.Context, options aws.RegistrationOptions) (register.Registrar, error) {
+				return registrar, nil
+			}
+
+			args := append([]string{"--config", configPath, "watch", "--interval", "20ms", "--jitter", "0",
+				"--history", filepath.Join(t.TempDir(), "history.db"), "--register-on-available"}, tt.args...)
+			code, stdout, stderr := runCLI(t, newClient(), args...)
+			if code != int(tt.code) {
+				t.Fatalf("Expected exit code %d, got %d (stdout: %s, stderr: %s)", tt.code, code, stdout, stderr)
+			}
+			if !reflect.DeepEqual(registrar.submitted, tt.submitted) {
+				t.Errorf("Expected %v to be registered, got %v", tt.submitted, registrar.submitted)
+			}
+			if !strings.Contains(stdout+stderr, tt.output) {
+				t.Errorf("Expected the output to contain %q, got stdout %q, stderr %q", tt.output, stdout, stderr)
+			}
+		})
+	}
+
+	refusals := []struct {
+		name     string
+		err      error
+		code     customErrors.ExitCode
+		attempts func(int) bool
+	}{
+		{"refused registration stops the watch", customErrors.NewValidationError("drop.com", "domain", "The domain name is invalid or uses an unsupported TLD", nil),
+			customErrors.ExitValidation, func(n int) bool { return n == 1 }},
+		{"throttled registration is tried again", customErrors.NewAPIError("route53domains", "RegisterDomain", "Request rate limit exceeded", nil).WithStatusCode(429),
+			customErrors.ExitSuccess, func(n int) bool { return n >= 2 }},
+		{"server error is tried again", customErrors.NewAPIError("route53domains", "RegisterDomain", "internal error", nil).WithStatusCode(500),
+			customErrors.ExitSuccess, func(n int) bool { return n >= 2 }},
+	}
+	for _, tt := range refusals {
+		t.Run(tt.name, func(t *testing.T) {
+			registrar := &fakeRegistrar{refused: map[string]bool{"drop.com": true}, err: tt.err}
+			newRegistrar = func(ctx context.Context, options aws.RegistrationOptions) (register.Registrar, error) {
+				return registrar, nil
+			}
+			watchContext = func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 200*time.Millisecond)
+			}
+
+			code, stdout, stderr := runCLI(t, newClient(), "--config", configPath, "watch", "drop.com", "--interval", "20ms", "--jitter", "0",
+				"--history", filepath.Join(t.TempDir(), "history.db"), "--register-on-available", "--register-max-price", "20", "--confirm-register", "drop.com")
+			if code != int(tt.code) || !tt.attempts(registrar.attempts) {
+				t.Errorf("Expected exit code %d, got %d after %d attempts: %s (stderr: %s)", tt.code, code, registrar.attempts, stdout, stderr)
+			}
+			if !strings.Contains(stdout, "could not be registered") {
+				t.Errorf("Expected the refusal to be reported, got %q", stdout)
+			}
+		})
+	}
+
+	t.Run("registration without an answer stops the watch", func(t *testing.T) {
+		ledger := useFakeTagger(t, &fakeTagger{})
+		registrar := &fakeRegistrar{refused: map[string]bool{"drop.com": true},
+			err: customErrors.NewTimeoutError("RegisterDomain", 0, context.DeadlineExceeded)}
+		newRegistrar = func(ctx context.Context, options aws.RegistrationOptions) (register.Registrar, error) {
+			return registrar, nil
+		}
+		watchContext = func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 5*time.Second)
+		}
+
+		code, stdout, stderr := runCLI(t, newClient(), "--config", configPath, "watch", "drop.com", "--interval", "20ms", "--jitter", "0",
+			"--history", filepath.Join(t.TempDir(), "history.db"), "--register-on-available", "--register-max-price", "20", "--confirm-register", "drop.com")
+		if code != int(customErrors.ExitSystemError) || registrar.attempts != 1 {
+			t.Errorf("Expected one attempt and exit code %d, got %d after %d attempts: %s (stderr: %s)", customErrors.ExitSystemError, code, registrar.attempts, stdout, stderr)
+		}
+		if !strings.Contains(stderr, "drop.com may have been registered") || !strings.Contains(stderr, "recorded as unconfirmed spend") {
+			t.Errorf("Expected the unknown outcome to be reported, got %q", stderr)
+		}
+		purchases, err := ledger.Purchases()
+		if err != nil || len(purchases) != 1 || !purchases[0].Unconfirmed || purchases[0].Amount != 14 {
+			t.Errorf("Expected an unconfirmed purchase in the ledger, got %+v, %v", purchases, err)
+		}
+	})
+
+	t.Run("interrupt finishes the registration in flight", func(t *testing.T) {
+		ledger := useFakeTagger(t, &fakeTagger{})
+		watchCtx, interrupt := context.WithTimeout(context.Background(), 5*time.Second)
+		watchContext = func() (context.Context, context.CancelFunc) { return watchCtx, interrupt }
+		registrar := &fakeRegistrar{before: func(domain string) {
+			interrupt()
+			time.Sleep(10 * time.Millisecond)
+		}}
+		newRegistrar = func(ctx context.Context, options aws.RegistrationOptions) (register.Registrar, error) {
+			return registrar, nil
+		}
+
+		code, stdout, stderr := runCLI(t, newClient(), "--config", configPath, "watch", "drop.com", "--interval", "20ms", "--jitter", "0",
+			"--history", filepath.Join(t.TempDir(), "history.db"), "--register-on-available", "--register-max-price", "20", "--confirm-register", "drop.com")
+		if code != int(customErrors.ExitSuccess) || !strings.Contains(stdout, "✓ drop.com: registration submitted") {
+			t.Errorf("Expected the registration to be submitted, got exit code %d: %s (stderr: %s)", code, stdout, stderr)
+		}
+		purchases, err := ledger.Purchases()
+		if err != nil || len(purchases) != 1 || purchases[0].OperationID != "op-drop.com" {
+			t.Errorf("Expected the registration in the ledger, got %+v, %v", purchases, err)
+		}
+	})
+
+	t.Run("flags without --register-on-available", func(t *testing.T) {
+		code, _, stderr := runCLI(t, newClient(), "watch", "drop.com", "--register-max-price", "20")
+		if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "go with --register-on-available") {
+			t.Errorf("Expected a validation error, got exit code %d: %s", code, stderr)
+		}
+	})
+}
+
+// stubParameters serves SSM parameters from a map
+type stubParameters map[string]string
+
+func (s stubParameters) GetParameter(ctx context.Context, name string) (string, error) {
+	if value, ok := s[name]; ok {
+		return value, nil
+	}
+	return "", errors.New("ParameterNotFound")
+}
+
+// contactProfilesConfig is a config file with a local contact profile and
+// one kept in SSM Parameter Store
+const contactProfilesConfig = `register:
+  profile: work
+contacts:
+  work:
+    registrant:
+      first_name: Ada
+      last_name: Lovelace
+      organization: Example Ltd
+      type: company
+      email: ada@example.com
+      phone: "+44.2071234567"
+      address_line_1: 12 St James's Square
+      city: London
+      country_code: GB
+      zip_code: SW1Y 4JH
+    tech:
+      first_name: Grace
+      last_name: Hopper
+      email: ops@example.com
+      phone: "+1.2025550123"
+      address_line_1: 1 Navy Way
+      city: Arlington
+      state: VA
+      country_code: US
+      zip_code: "22202"
+    privacy:
+      tech: false
+  shared:
+    ssm_parameter: /r53check/contacts/shared
+`
+
+func TestContactsCommand(t *testing.T) {
+	client := r53checktest.NewClient()
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(contactProfilesConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	original := newParameterReader
+	newParameterReader = func(ctx context.Context) (register.ParameterReader, error) {
+		// The profile is the contact of register.contact as the registrant
+		profile := strings.Replace(registerContactConfig, "register:\n  contact:", "registrant:", 1)
+		return stubParameters{"/r53check/contacts/shared": profile}, nil
+	}
+	t.Cleanup(func() { newParameterReader = original })
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   customErrors.ExitCode
+		output []string
+	}{
+		{"list", []string{"contacts", "list"}, customErrors.ExitSuccess,
+			[]string{"  shared  (SSM parameter /r53check/contacts/shared)", "* work  Ada Lovelace, Example Ltd <ada@example.com>"}},
+		{"show", []string{"contacts", "show", "work"}, customErrors.ExitSuccess, []string{
+			"Contact profile work (config):",
+			"Registrant  Ada Lovelace, Example Ltd <ada@example.com>, private",
+			"Admin       Ada Lovelace, Example Ltd <ada@example.com>, private",
+			"Tech        Grace Hopper <ops@example.com>, public in WHOIS",
+			"1 Navy Way, Arlington, VA, 22202, US, +1.2025550123",
+		}},
+		{"show from SSM", []string{"contacts", "show", "shared"}, customErrors.ExitSuccess,
+			[]string{"Contact profile shared (ssm:/r53check/contacts/shared):"}},
+		{"show unknown", []string{"contacts", "show", "home"}, customErrors.ExitValidation,
+			[]string{`no contact profile "home"`}},
+		{"json", []string{"-o", "json", "contacts", "show", "work"}, customErrors.ExitSuccess,
+			[]string{`"first_name": "Grace"`, `"tech": false`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, client, append([]string{"--config", configPath}, tt.args...)...)
+			if code != int(tt.code) {
+				t.Fatalf("Expected exit code %d, got %d (stderr: %s)", tt.code, code, stderr)
+			}
+			for _, want := range tt.output {
+				if !strings.Contains(stdout+stderr, want) {
+					t.Errorf("Expected the output to contain %q, got:\n%s%s", want, stdout, stderr)
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterCommand_ContactProfile(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Price("com", r53checktest.Price{Registration: 14, Renewal: 16, Transfer: 12}).
+		TLDs("com").
+		Client()
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(contactProfilesConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	originalRegistrar, originalParameters := newRegistrar, newParameterReader
+	t.Cleanup(func() { newRegistrar, newParameterReader = originalRegistrar, originalParameters })
+	newParameterReader = func(ctx context.Context) (register.ParameterReader, error) {
+		return stubParameters{"/r53check/contacts/shared": "registrant:\n  first_name: Ada\n"}, nil
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		tech    string
+		privacy aws.ContactPrivacy
+		err     string
+	}{
+		{"default profile", nil, "Grace", aws.ContactPrivacy{Registrant: true, Admin: true, Tech: false}, ""},
+		{"--privacy overrides the toggles", []string{"--privacy=true"}, "Grace", aws.ContactPrivacy{Registrant: true, Admin: true, Tech: true}, ""},
+		{"invalid profile from SSM", []string{"--contact", "shared"}, "", aws.ContactPrivacy{}, "contacts.shared.registrant is missing last_name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options aws.RegistrationOptions
+			newRegistrar = func(ctx context.Context, o aws.RegistrationOptions) (register.Registrar, error) {
+				options = o
+				return &fakeRegistrar{}, nil
+			}
+
+			args := append([]string{"--config", configPath, "register", "-y", "example.com"}, tt.args...)
+			code, _, stderr := runCLI(t, client, args...)
+			if tt.err != "" {
+				if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, tt.err) {
+					t.Errorf("Expected an error containing %q, got exit code %d: %s", tt.err, code, stderr)
+				}
+				return
+			}
+			if code != int(customErrors.ExitSuccess) {
+				t.Fatalf("Expected success, got exit code %d: %s", code, stderr)
+			}
+			if *options.Tech.FirstName != tt.tech || *options.Admin.FirstName != "Ada" || options.Registrant.ContactType != "COMPANY" {
+				t.Errorf("Unexpected contacts %+v", options)
+			}
+			if options.Privacy != tt.privacy {
+				t.Errorf("Expected privacy %+v, got %+v", tt.privacy, options.Privacy)
+			}
+		})
+	}
+}
+
+// fakeTagger is a Route 53 Domains account with registered domains and
+// their tags
+type fakeTagger struct {
+	mu   sync.Mutex
+	tags map[string]map[string]string
+}
+
+func (f *fakeTagger) RegisteredDomains(ctx context.Context) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	domains := make([]string, 0, len(f.tags))
+	for domain := range f.tags {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	return domains, nil
+}
+
+func (f *fakeTagger) Tags(ctx context.Context, domain string) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return maps.Clone(f.tags[domain]), nil
+}
+
+func (f *fakeTagger) TagDomain(ctx context.Context, domain string, tags map[string]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.tags[domain] == nil {
+		f.tags[domain] = make(map[string]string)
+	}
+	maps.Copy(f.tags[domain], tags)
+	return nil
+}
+
+// useFakeTagger replaces newDomainTagger and purchaseLedger with tagger and
+// a ledger in a directory of the test
+func useFakeTagger(t *testing.T, tagger *fakeTagger) *budget.Ledger {
+	t.Helper()
+	ledger := budget.NewLedger(filepath.Join(t.TempDir(), "purchases.jsonl"))
+	originalTagger, originalLedger := newDomainTagger, purchaseLedger
+	newDomainTagger = func(ctx context.Context) (register.Tagger, error) { return tagger, nil }
+	purchaseLedger = func() *budget.Ledger { return ledger }
+	t.Cleanup(func() { newDomainTagger, purchaseLedger = originalTagger, originalLedger })
+	return ledger
+}
+
+func TestRegisterCommand_Tags(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Price("com", r53checktest.Price{Registration: 14, Renewal: 16, Transfer: 12}).
+		TLDs("com").
+		Client()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	contents := strings.Replace(registerContactConfig, "register:\n", "register:\n  required_tags: [team]\n", 1)
+	if err := os.WriteFile(configPath, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	original, originalInterval := newRegistrar, registerPollInterval
+	newRegistrar = func(ctx context.Context, options aws.RegistrationOptions) (register.Registrar, error) {
+		return &fakeRegistrar{}, nil
+	}
+	registerPollInterval = time.Millisecond
+	t.Cleanup(func() { newRegistrar, registerPollInterval = original, originalInterval })
+
+	t.Run("tagged once registered", func(t *testing.T) {
+		tagger := &fakeTagger{tags: map[string]map[string]string{}}
+		ledger := useFakeTagger(t, tagger)
+		code, stdout, stderr := runCLI(t, client, "--config", configPath, "register", "--tags", "team=platform,env=prod", "--wait", "1m", "-y", "a.com")
+		if code != 0 {
+			t.Fatalf("Expected success, got exit code %d: %s", code, stderr)
+		}
+		if !strings.Contains(stdout, "✓ a.com registered (operation op-a.com, tagged)") {
+			t.Errorf("Expected a.com to be reported tagged, got:\n%s", stdout)
+		}
+		want := map[string]string{"team": "platform", "env": "prod"}
+		if !reflect.DeepEqual(tagger.tags["a.com"], want) {
+			t.Errorf("Expected a.com to be tagged %v, got %v", want, tagger.tags["a.com"])
+		}
+		purchases, err := ledger.Purchases()
+		if err != nil || len(purchases) != 1 || !reflect.DeepEqual(purchases[0].Tags, want) {
+			t.Errorf("Expected the purchase to record the tags, got %+v (%v)", purchases, err)
+		}
+	})
+
+	t.Run("pending registrations", func(t *testing.T) {
+		tagger := &fakeTagger{tags: map[string]map[string]string{}}
+		useFakeTagger(t, tagger)
+		code, _, stderr := runCLI(t, client, "--config", configPath, "register", "--tags", "team=platform", "-y", "a.com")
+		if code != 0 {
+			t.Fatalf("Expected success, got exit code %d: %s", code, stderr)
+		}
+		if len(tagger.tags) != 0 {
+			t.Errorf("Expected no domain to be tagged before it is registered, got %v", tagger.tags)
+		}
+		if !strings.Contains(stderr, "tags audit --apply") {
+			t.Errorf("Expected a note to apply the tags later, got %q", stderr)
+		}
+	})
+
+	t.Run("required tag missing", func(t *testing.T) {
+		useFakeTagger(t, &fakeTagger{tags: map[string]map[string]string{}})
+		code, _, stderr := runCLI(t, client, "--config", configPath, "register", "--tags", "env=prod", "-y", "a.com")
+		if code != int(customErrors.ExitValidation) || !strings.Contains(stderr, "must be tagged with team") {
+			t.Errorf("Expected the missing team tag to be refused, got exit code %d: %s", code, stderr)
+		}
+	})
+}
+
+func TestTagsAuditCommand(t *testing.T) {
+	client := r53checktest.NewScenario().TLDs("com").Client()
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   customErrors.ExitCode
+		tagged map[string]string
+		stdout []string
+	}{
+		{"report", nil, customErrors.ExitSuccess, nil,
+			[]string{"✓ owned.com  team=web", "✗ fresh.com  (no tags)", "missing team", "not yet tagged env=prod, team=platform (run with --apply)", "✗ stray.com  env=dev", "Summary: 1 OK, 2 missing tags"}},
+		{"fail on missing", []string{"--fail-on-missing"}, customErrors.ExitPartialFailure, nil,
+			[]string{"Summary: 1 OK, 2 missing tags"}},
+		{"apply", []string{"--apply"}, customErrors.ExitSuccess, map[string]string{"team": "platform", "env": "prod"},
+			[]string{"✓ fresh.com  env=prod, team=platform", "Summary: 2 OK, 1 missing tags"}},
+		{"required keys from the flag", []string{"--require", "env"}, customErrors.ExitSuccess, nil,
+			[]string{"✗ owned.com  team=web", "missing env", "✓ stray.com  env=dev"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tagger := &fakeTagger{tags: map[string]map[string]string{
+				"fresh.com": nil,
+				"owned.com": {"team": "web"},
+				"stray.com": {"env": "dev"},
+			}}
+			ledger := useFakeTagger(t, tagger)
+			if err := ledger.Record([]budget.Purchase{
+				{Domain: "fresh.com", Kind: "register", Amount: 14, Currency: "USD", At: time.Now(), Tags: map[string]string{"team": "platform", "env": "prod"}},
+				{Domain: "owned.com", Kind: "register", Amount: 14, Currency: "USD", At: time.Now(), Tags: map[string]string{"team": "platform"}},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte("register:\n  required_tags: [team]\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			args := append([]string{"--config", configPath, "tags", "audit"}, tt.args...)
+			code, stdout, stderr := runCLI(t, client, args...)
+			if code != int(tt.code) {
+				t.Fatalf("Expected exit code %d, got %d (stdout: %s, stderr: %s)", tt.code, code, stdout, stderr)
+			}
+			for _, want := range tt.stdout {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("Expected stdout to contain %q, got:\n%s", want, stdout)
+				}
+			}
+			if !reflect.DeepEqual(tagger.tags["fresh.com"], tt.tagged) {
+				t.Errorf("Expected fresh.com to be tagged %v, got %v", tt.tagged, tagger.tags["fresh.com"])
+			}
+			if tagger.tags["owned.com"]["team"] != "web" {
+				t.Errorf("Expected the team tag given since to be kept, got %v", tagger.tags["owned.com"])
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/abakermi/r53check/internal/config"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/metrics"
+)
+
+// metricsHooks emit every check to the destinations of the metrics section
+// of the config file; empty when none is set
+var metricsHooks []*metrics.EmitHooks
+
+// setupMetrics opens the configured StatsD and CloudWatch EMF destinations,
+// which are closed when the command finishes
+func setupMetrics(settings config.MetricsSettings) error {
+	metricsHooks = nil
+
+	var emitters []metrics.Emitter
+	if settings.StatsD != "" {
+		statsd, err := metrics.NewStatsD(settings.StatsD, settings.Namespace)
+		if err != nil {
+			return customErrors.NewSystemError("metrics", "unable to set up StatsD metrics", err)
+		}
+		emitters = append(emitters, statsd)
+	}
+	if settings.EMF != "" {
+		emf, err := metrics.OpenEMF(settings.EMF, settings.Namespace)
+		if err != nil {
+			for _, emitter := range emitters {
+				emitter.Close()
+			}
+			return customErrors.NewSystemError("metrics", "unable to set up CloudWatch EMF metrics", err)
+		}
+		emitters = append(emitters, emf)
+	}
+
+	for _, emitter := range emitters {
+		metricsHooks = append(metricsHooks, metrics.NewEmitHooks(emitter))
+		exitHooks = append(exitHooks, func() { emitter.Close() })
+	}
+	if verbose && len(emitters) > 0 {
+		fmt.Fprintf(stderr, "Emitting check metrics to %s\n", pluralize(len(emitters), "destination"))
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/config"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/monitor"
+	"github.com/abakermi/r53check/internal/notify"
+
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/spf13/cobra"
+)
+
+// notifyChoices lists the values accepted by --notify
+var notifyChoices = []string{"desktop", "email", "webhook"}
+
+var (
+	// Notification flags, shared by the check, bulk and daemon commands
+	notifyTargets []string
+	emailTo       []string
+	webhookURL    string
+
+	// Notifier settings and routing rules from the config file
+	emailSettings   config.EmailSettings
+	webhookSettings config.WebhookSettings
+	notifyRules     []config.NotifyRule
+)
+
+// addNotifyFlags registers the notification flags on cmd
+func addNotifyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&notifyTargets, "notify", nil, "Send notifications to: "+strings.Join(notifyChoices, ", "))
+	cmd.Flags().StringSliceVar(&emailTo, "email-to", nil, "Email notification recipients, overriding email.to in the config file")
+	cmd.Flags().StringVar(&webhookURL, "webhook-url", "", "POST results to this URL, overriding webhook.url in the config file (implies --notify webhook)")
+}
+
+// newMailSender creates the transport for email notifications.
+// Tests replace it to capture sent mail.
+var newMailSender = func(ctx context.Context, settings config.EmailSettings) (notify.MailSender, error) {
+	switch strings.ToLower(settings.Transport) {
+	case "", "smtp":
+		return notify.NewSMTPSender(settings.SMTP)
+	case "ses":
+		awsConfig, err := loadAWSConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return notify.NewSESSender(sesv2.NewFromConfig(*awsConfig)), nil
+	default:
+		return nil, customErrors.NewValidationError("", "email.transport",
+			fmt.Sprintf("unknown email transport %q: must be smtp or ses", settings.Transport), nil)
+	}
+}
+
+// newNotifiers creates the notifiers selected with --notify. Targets named
+// by routing rules in the config file are only told about matching results.
+func newNotifiers(ctx context.Context) ([]monitor.Notifier, error) {
+	for i, target := range notifyTargets {
+		notifyTargets[i] = strings.ToLower(strings.TrimSpace(target))
+	}
+	if webhookURL != "" && !slices.Contains(notifyTargets, "webhook") {
+		notifyTargets = append(notifyTargets, "webhook")
+	}
+
+	rules, err := notify.ParseRules(notifyRules)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		for _, target := range rule.Targets() {
+			if !slices.Contains(notifyChoices, target) {
+				return nil, customErrors.NewValidationError("", "notify.rules",
+					fmt.Sprintf("%s routes to unknown target %q: must be one of %s%s", rule.Name(), target, strings.Join(notifyChoices, ", "),
+						customErrors.DidYouMean(target, notifyChoices)), nil)
+			}
+		}
+	}
+
+	var notifiers []monitor.Notifier
+	for _, target := range notifyTargets {
+		notifier, err := newNotifier(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		if routing := notify.RulesFor(rules, target); len(routing) > 0 {
+			if verbose {
+				names := make([]string, len(routing))
+				for i, rule := range routing {
+					names[i] = rule.Name()
+				}
+				fmt.Fprintf(stderr, "Routing %s notifications through: %s\n", target, strings.Join(names, ", "))
+			}
+			notifier = notify.Route(notifier, routing)
+		}
+		notifiers = append(notifiers, notifier)
+	}
+
+	if len(emailTo) > 0 && !slices.Contains(notifyTargets, "email") {
+		return nil, customErrors.NewValidationError("", "email-to", "--email-to requires --notify email", nil)
+	}
+	return notifiers, nil
+}
+
+// newNotifier creates the notifier for one --notify target
+func newNotifier(ctx context.Context, target string) (monitor.Notifier, error) {
+	switch target {
+	case "desktop":
+		return notify.NewDesktopNotifier()
+	case "email":
+		settings := emailSettings
+		if len(emailTo) > 0 {
+			settings.To = emailTo
+		}
+		sender, err := newMailSender(ctx, settings)
+		if err != nil {
+			return nil, err
+		}
+		return notify.NewEmailNotifier(settings, sender)
+	case "webhook":
+		settings := webhookSettings
+		if webhookURL != "" {
+			settings.URL = webhookURL
+		}
+		return notify.NewWebhookNotifier(settings, &http.Client{Timeout: timeout})
+	default:
+		return nil, customErrors.NewValidationError("", "notify",
+			fmt.Sprintf("unknown notification target %q: must be one of %s%s", target, strings.Join(notifyChoices, ", "),
+				customErrors.DidYouMean(target, notifyChoices)), nil)
+	}
+}
+
+// sendNotifications tells every notifier about report, returning the
+// first failure after trying them all
+func sendNotifications(ctx context.Context, notifiers []monitor.Notifier, report *monitor.Report) error {
+	var firstErr error
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, report); err != nil && firstErr == nil {
+			firstErr = customErrors.NewSystemError("notify", "notification failed", err)
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+// maxOpenPages is how many registration pages --open opens in the browser;
+// the URLs of further available domains are printed instead
+const maxOpenPages = 5
+
+// openRegistration is --open: show the Route 53 registration page of the
+// available domains
+var openRegistration bool
+
+// openBrowser opens url in the default browser; tests replace it
+var openBrowser = func(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Run()
+}
+
+// registrationURL returns the Route 53 console page that registers name
+func registrationURL(name string) string {
+	return "https://console.aws.amazon.com/route53/domains/home#/DomainSearch?domainName=" + url.QueryEscape(name)
+}
+
+// openRegistrations handles --open for results: the registration pages of
+// the available domains are opened in the browser when stdout is a
+// terminal, and otherwise their URLs are printed on stderr, keeping stdout
+// for the results. Only the first maxOpenPages are opened, and once the
+// browser cannot be opened the remaining URLs are printed instead.
+func openRegistrations(results []*domain.AvailabilityResult) {
+	if !openRegistration {
+		return
+	}
+
+	browser := isTerminal(stdout)
+	opened := 0
+	for _, result := range results {
+		if result == nil || result.Status != domain.StatusAvailable {
+			continue
+		}
+		link := registrationURL(result.Domain)
+		if browser && opened < maxOpenPages {
+			err := openBrowser(link)
+			if err == nil {
+				opened++
+				if verbose {
+					fmt.Fprintf(stderr, "Opened the registration page of %s\n", result.Domain)
+				}
+				continue
+			}
+			fmt.Fprintf(stderr, "Unable to open a browser: %v\n", err)
+			browser = false
+		}
+		fmt.Fprintf(stderr, "Register %s: %s\n", result.Domain, link)
+	}
+}
@@ -0,0 +1,233 @@
+package r53check
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+// AvailabilityStatus is what Route 53 reported about a domain
+type AvailabilityStatus string
+
+// Availability statuses
+const (
+	StatusAvailable   AvailabilityStatus = "AVAILABLE"
+	StatusUnavailable AvailabilityStatus = "UNAVAILABLE"
+	StatusReserved    AvailabilityStatus = "RESERVED"
+	StatusUnknown     AvailabilityStatus = "UNKNOWN"
+)
+
+// PricingInfo is what Route 53 charges for a TLD. A price Route 53 does not
+// list is nil.
+type PricingInfo struct {
+	RegistrationPrice *float64
+	RenewalPrice      *float64
+	TransferPrice     *float64
+	Currency          string
+}
+
+// AvailabilityResult is the result of checking one domain
+type AvailabilityResult struct {
+	Domain        string
+	UnicodeDomain string // Unicode form of an internationalized domain, empty otherwise
+	Available     bool
+	Status        AvailabilityStatus
+	Message       string
+	CheckedAt     time.Time
+	CompletedAt   time.Time    // Set when a bulk check finishes, used for completion ordering
+	Error         error        // Why a bulk check of the domain failed
+	Pricing       *PricingInfo // Set by the pricing checks for available domains
+	Retried       bool         // Set when the result comes from a retry
+	Retries       int          // Number of times the domain was checked again after failing
+	Skipped       bool         // Set when a bulk check was aborted or cancelled before checking the domain
+
+	// Timings records how long each phase of the last attempt took
+	Timings CheckTimings
+}
+
+// CheckTimings splits the duration of a check into its phases
+type CheckTimings struct {
+	Validation time.Duration // Validating and converting the name
+	API        time.Duration // Waiting for CheckDomainAvailability
+	Pricing    time.Duration // Looking up the TLD price; zero without pricing
+}
+
+// Hooks observes check events; embed NopHooks to implement only some of them.
+// Hooks run on the checking goroutine, so they must be safe for concurrent use.
+type Hooks interface {
+	// OnCheckStart is called before a domain is validated and checked
+	OnCheckStart(ctx context.Context, domain string)
+	// OnCheckComplete is called with the outcome of every check, including failures
+	OnCheckComplete(ctx context.Context, result *AvailabilityResult, err error)
+	// OnRetry is called before a failed domain is checked again; attempt is
+	// the number of the upcoming attempt and err the failure that caused it
+	OnRetry(ctx context.Context, domain string, attempt int, err error)
+	// OnThrottle is called when AWS rejects a check for exceeding its rate limit
+	OnThrottle(ctx context.Context, domain string, err error)
+}
+
+// NopHooks implements Hooks with methods that do nothing
+type NopHooks struct{}
+
+func (NopHooks) OnCheckStart(ctx context.Context, domain string)                            {}
+func (NopHooks) OnCheckComplete(ctx context.Context, result *AvailabilityResult, err error) {}
+func (NopHooks) OnRetry(ctx context.Context, domain string, attempt int, err error)         {}
+func (NopHooks) OnThrottle(ctx context.Context, domain string, err error)                   {}
+
+// hooksAdapter passes the events of the internal checker to Hooks, with
+// their results and errors converted
+type hooksAdapter struct {
+	hooks Hooks
+}
+
+func (a hooksAdapter) OnCheckStart(ctx context.Context, domain string) {
+	a.hooks.OnCheckStart(ctx, domain)
+}
+
+func (a hooksAdapter) OnCheckComplete(ctx context.Context, result *domain.AvailabilityResult, err error) {
+	a.hooks.OnCheckComplete(ctx, newResult(result), convertError(err))
+}
+
+func (a hooksAdapter) OnRetry(ctx context.Context, domain string, attempt int, err error) {
+	a.hooks.OnRetry(ctx, domain, attempt, convertError(err))
+}
+
+func (a hooksAdapter) OnThrottle(ctx context.Context, domain string, err error) {
+	a.hooks.OnThrottle(ctx, domain, convertError(err))
+}
+
+// Checker checks domain availability; create one with New
+type Checker struct {
+	checker *domain.DomainChecker
+}
+
+// CheckAvailability checks if a domain is available for registration
+func (c *Checker) CheckAvailability(ctx context.Context, domain string) (*AvailabilityResult, error) {
+	result, err := c.checker.CheckAvailability(ctx, domain)
+	return newResult(result), convertError(err)
+}
+
+// CheckAvailabilityWithPricing is CheckAvailability with the TLD's prices
+// for an available domain
+func (c *Checker) CheckAvailabilityWithPricing(ctx context.Context, domain string) (*AvailabilityResult, error) {
+	result, err := c.checker.CheckAvailabilityWithPricing(ctx, domain)
+	return newResult(result), convertError(err)
+}
+
+// CheckAvailabilityBulk checks domains with bounded concurrency, checking
+// those that failed with a retryable error again in a slower second pass.
+// The results are in input order, with each failure in its result's Error.
+// When ctx is cancelled or times out, the results that completed are still
+// returned with the error, and the other domains are marked Skipped.
+func (c *Checker) CheckAvailabilityBulk(ctx context.Context, domains []string) ([]*AvailabilityResult, error) {
+	results, err := c.checker.CheckAvailabilityBulk(ctx, domains)
+	return newResults(results), convertError(err)
+}
+
+// CheckAvailabilityBulkWithPricing is CheckAvailabilityBulk with prices for
+// the available domains
+func (c *Checker) CheckAvailabilityBulkWithPricing(ctx context.Context, domains []string) ([]*AvailabilityResult, error) {
+	results, err := c.checker.CheckAvailabilityBulkWithPricing(ctx, domains)
+	return newResults(results), convertError(err)
+}
+
+// Stream checks every domain produced by domains with bounded concurrency and
+// yields each result as soon as it completes, in completion order. Breaking
+// out of the loop cancels the checks still in flight, and waits for domains
+// to yield its next domain or end. If ctx is cancelled, the final pair
+// yielded is a nil result with the cancellation error.
+func (c *Checker) Stream(ctx context.Context, domains iter.Seq[string]) iter.Seq2[*AvailabilityResult, error] {
+	return convertStream(c.checker.Stream(ctx, domains))
+}
+
+// StreamWithPricing is Stream with prices for the available domains
+func (c *Checker) StreamWithPricing(ctx context.Context, domains iter.Seq[string]) iter.Seq2[*AvailabilityResult, error] {
+	return convertStream(c.checker.StreamWithPricing(ctx, domains))
+}
+
+// TLDPricing returns the prices Route 53 lists for tld, or nil when it lists
+// none. Prices are fetched once per Checker.
+func (c *Checker) TLDPricing(ctx context.Context, tld string) (*PricingInfo, error) {
+	pricing, err := c.checker.TLDPricing(ctx, tld)
+	return newPricingInfo(pricing), convertError(err)
+}
+
+// ValidateDomain validates domain as a check does, without calling AWS
+func (c *Checker) ValidateDomain(domain string) error {
+	return convertError(c.checker.ValidateDomain(domain))
+}
+
+// GetTimeout returns the timeout of each AWS request
+func (c *Checker) GetTimeout() time.Duration {
+	return c.checker.GetTimeout()
+}
+
+// AddHooks registers hooks that are notified of every check, in the order added
+func (c *Checker) AddHooks(hooks Hooks) {
+	c.checker.AddHooks(hooksAdapter{hooks})
+}
+
+// convertStream converts the pairs yielded by an internal stream
+func convertStream(stream iter.Seq2[*domain.AvailabilityResult, error]) iter.Seq2[*AvailabilityResult, error] {
+	return func(yield func(*AvailabilityResult, error) bool) {
+		for result, err := range stream {
+			if !yield(newResult(result), convertError(err)) {
+				return
+			}
+		}
+	}
+}
+
+// newResults converts the results of a bulk check
+func newResults(results []*domain.AvailabilityResult) []*AvailabilityResult {
+	if results == nil {
+		return nil
+	}
+	converted := make([]*AvailabilityResult, len(results))
+	for i, result := range results {
+		converted[i] = newResult(result)
+	}
+	return converted
+}
+
+// newResult converts a result of the internal checker, keeping nil as nil
+func newResult(result *domain.AvailabilityResult) *AvailabilityResult {
+	if result == nil {
+		return nil
+	}
+	return &AvailabilityResult{
+		Domain:        result.Domain,
+		UnicodeDomain: result.UnicodeDomain,
+		Available:     result.Available,
+		Status:        AvailabilityStatus(result.Status),
+		Message:       result.Message,
+		CheckedAt:     result.CheckedAt,
+		CompletedAt:   result.CompletedAt,
+		Error:         convertError(result.Error),
+		Pricing:       newPricingInfo(result.Pricing),
+		Retried:       result.Retried,
+		Retries:       result.Retries,
+		Skipped:       result.Skipped,
+		Timings: CheckTimings{
+			Validation: result.Timings.Validation,
+			API:        result.Timings.API,
+			Pricing:    result.Timings.Pricing,
+		},
+	}
+}
+
+// newPricingInfo converts the prices of the internal checker, keeping nil
+// as nil
+func newPricingInfo(pricing *domain.PricingInfo) *PricingInfo {
+	if pricing == nil {
+		return nil
+	}
+	return &PricingInfo{
+		RegistrationPrice: pricing.RegistrationPrice,
+		RenewalPrice:      pricing.RenewalPrice,
+		TransferPrice:     pricing.TransferPrice,
+		Currency:          pricing.Currency,
+	}
+}
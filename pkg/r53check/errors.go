@@ -0,0 +1,185 @@
+package r53check
+
+import (
+	"errors"
+	"time"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+// ErrorCategory classifies an error; see Category
+type ErrorCategory string
+
+// Error categories
+const (
+	CategoryValidation     ErrorCategory = "VALIDATION"
+	CategoryAuthentication ErrorCategory = "AUTHENTICATION"
+	CategoryAuthorization  ErrorCategory = "AUTHORIZATION"
+	CategoryAPI            ErrorCategory = "API"
+	CategorySystem         ErrorCategory = "SYSTEM"
+	CategoryTimeout        ErrorCategory = "TIMEOUT"
+)
+
+// ValidationError reports a domain rejected before AWS was called
+type ValidationError struct {
+	Domain  string
+	Field   string // What was invalid, such as "format" or "tld"
+	Message string
+	Cause   error
+
+	// err is the error this one was converted from
+	err error
+}
+
+func (e *ValidationError) Error() string { return e.err.Error() }
+func (e *ValidationError) Unwrap() error { return e.Cause }
+func (e *ValidationError) source() error { return e.err }
+
+// ValidationErrors collects every problem found with a domain; errors.As
+// finds the first of them as a *ValidationError
+type ValidationErrors struct {
+	Domain string
+	Errors []*ValidationError
+
+	err error
+}
+
+func (e *ValidationErrors) Error() string { return e.err.Error() }
+func (e *ValidationErrors) source() error { return e.err }
+
+func (e *ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// AuthenticationError reports missing or invalid AWS credentials
+type AuthenticationError struct {
+	Provider string
+	Message  string
+	Cause    error
+
+	err error
+}
+
+func (e *AuthenticationError) Error() string { return e.err.Error() }
+func (e *AuthenticationError) Unwrap() error { return e.Cause }
+func (e *AuthenticationError) source() error { return e.err }
+
+// AuthorizationError reports credentials lacking a permission
+type AuthorizationError struct {
+	Operation string
+	Resource  string
+	Message   string
+	Cause     error
+
+	err error
+}
+
+func (e *AuthorizationError) Error() string { return e.err.Error() }
+func (e *AuthorizationError) Unwrap() error { return e.Cause }
+func (e *AuthorizationError) source() error { return e.err }
+
+// APIError reports a failed Route 53 Domains call, with its status code and
+// request ID when AWS gave them
+type APIError struct {
+	Service    string
+	Operation  string
+	RequestID  string
+	StatusCode int
+	Message    string
+	Cause      error
+
+	err error
+}
+
+func (e *APIError) Error() string { return e.err.Error() }
+func (e *APIError) Unwrap() error { return e.Cause }
+func (e *APIError) source() error { return e.err }
+
+// TimeoutError reports a check abandoned for taking too long. Timeout is
+// zero when the deadline was not the Checker's own.
+type TimeoutError struct {
+	Operation string
+	Timeout   time.Duration
+	Cause     error
+
+	err error
+}
+
+func (e *TimeoutError) Error() string { return e.err.Error() }
+func (e *TimeoutError) Unwrap() error { return e.Cause }
+func (e *TimeoutError) source() error { return e.err }
+
+// SystemError reports a local failure, such as an unreadable file or a
+// cancelled stream
+type SystemError struct {
+	Component string
+	Message   string
+	Cause     error
+
+	err error
+}
+
+func (e *SystemError) Error() string { return e.err.Error() }
+func (e *SystemError) Unwrap() error { return e.Cause }
+func (e *SystemError) source() error { return e.err }
+
+// Category returns the category of err, such as CategoryValidation for a
+// malformed domain. Timeouts take precedence over the error they wrap.
+func Category(err error) ErrorCategory {
+	return ErrorCategory(customErrors.GetCategory(sourceError(err)))
+}
+
+// IsRetryable reports whether err is worth retrying, such as throttling or
+// a Route 53 outage
+func IsRetryable(err error) bool {
+	return customErrors.IsRetryable(sourceError(err))
+}
+
+// sourceError returns the error the first error of this package in err's
+// chain was converted from, or err itself when there is none
+func sourceError(err error) error {
+	var converted interface{ source() error }
+	if errors.As(err, &converted) {
+		return converted.source()
+	}
+	return err
+}
+
+// convertError converts an error of the checker to the error types of this
+// package, along with the errors it wraps. Other errors are returned as they
+// are.
+func convertError(err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *customErrors.ValidationError:
+		return newValidationError(e)
+	case *customErrors.ValidationErrors:
+		converted := &ValidationErrors{Domain: e.Domain, err: e}
+		for _, validationErr := range e.Errors {
+			converted.Errors = append(converted.Errors, newValidationError(validationErr))
+		}
+		return converted
+	case *customErrors.AuthenticationError:
+		return &AuthenticationError{Provider: e.Provider, Message: e.Message, Cause: convertError(e.Cause), err: e}
+	case *customErrors.AuthorizationError:
+		return &AuthorizationError{Operation: e.Operation, Resource: e.Resource, Message: e.Message, Cause: convertError(e.Cause), err: e}
+	case *customErrors.APIError:
+		return &APIError{Service: e.Service, Operation: e.Operation, RequestID: e.RequestID, StatusCode: e.StatusCode,
+			Message: e.Message, Cause: convertError(e.Cause), err: e}
+	case *customErrors.TimeoutError:
+		return &TimeoutError{Operation: e.Operation, Timeout: e.Timeout, Cause: convertError(e.Cause), err: e}
+	case *customErrors.SystemError:
+		return &SystemError{Component: e.Component, Message: e.Message, Cause: convertError(e.Cause), err: e}
+	default:
+		return err
+	}
+}
+
+func newValidationError(e *customErrors.ValidationError) *ValidationError {
+	return &ValidationError{Domain: e.Domain, Field: e.Field, Message: e.Message, Cause: convertError(e.Cause), err: e}
+}
@@ -0,0 +1,56 @@
+package r53check_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/abakermi/r53check/pkg/r53check"
+	"github.com/abakermi/r53check/pkg/r53checktest"
+)
+
+func TestErrors_Classify(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Failing("denied.com", r53checktest.AccessDenied()).
+		Failing("busy.com", r53checktest.Throttled()).
+		Client()
+	checker, err := r53check.New(context.Background(), r53check.WithRoute53Client(client), r53check.WithTLDs("com"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := checker.CheckAvailability(context.Background(), "bad-.com")
+		var validationErr *r53check.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected a *r53check.ValidationError, got %T: %v", err, err)
+		}
+		if category := r53check.Category(err); category != r53check.CategoryValidation {
+			t.Errorf("expected category %s, got %s", r53check.CategoryValidation, category)
+		}
+		if len(client.Calls()) != 0 {
+			t.Errorf("expected no AWS calls, got %v", client.Calls())
+		}
+	})
+
+	t.Run("authorization", func(t *testing.T) {
+		_, err := checker.CheckAvailability(context.Background(), "denied.com")
+		if category := r53check.Category(err); category != r53check.CategoryAuthorization {
+			t.Errorf("expected category %s, got %s (%v)", r53check.CategoryAuthorization, category, err)
+		}
+		if r53check.IsRetryable(err) {
+			t.Error("expected access denied not to be retryable")
+		}
+	})
+
+	t.Run("throttled", func(t *testing.T) {
+		_, err := checker.CheckAvailability(context.Background(), "busy.com")
+		var apiErr *r53check.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("expected a *r53check.APIError, got %T: %v", err, err)
+		}
+		if apiErr.StatusCode != 429 || !r53check.IsRetryable(err) {
+			t.Errorf("expected a retryable 429, got status %d", apiErr.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,272 @@
+// Package r53check checks domain name availability and pricing with Amazon
+// Route 53 Domains. It is the library behind the r53check CLI:
+//
+//	checker, err := r53check.New(ctx, r53check.WithProfile("domains"))
+//	if err != nil {
+//		return err
+//	}
+//	result, err := checker.CheckAvailability(ctx, "example.com")
+//
+// Checkers validate each domain before calling AWS, check bulk lists with
+// bounded concurrency and a retry pass, and can be given a custom Route53Client
+// for testing.
+package r53check
+
+import (
+	"context"
+	"time"
+
+	"github.com/abakermi/r53check/internal/aws"
+	"github.com/abakermi/r53check/internal/domain"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+)
+
+// Validator validates a domain name before it is checked
+type Validator interface {
+	ValidateDomain(domain string) error
+}
+
+// Route53Client is the subset of Route 53 Domains a Checker needs
+type Route53Client interface {
+	CheckDomainAvailability(ctx context.Context, domain string) (*route53domains.CheckDomainAvailabilityOutput, error)
+	ListPrices(ctx context.Context, tld string) (*route53domains.ListPricesOutput, error)
+}
+
+// Route53DomainsAPI is the AWS SDK surface a Client calls
+type Route53DomainsAPI interface {
+	CheckDomainAvailability(ctx context.Context, params *route53domains.CheckDomainAvailabilityInput, optFns ...func(*route53domains.Options)) (*route53domains.CheckDomainAvailabilityOutput, error)
+	ListPrices(ctx context.Context, params *route53domains.ListPricesInput, optFns ...func(*route53domains.Options)) (*route53domains.ListPricesOutput, error)
+}
+
+// HTTPOptions tunes the connection pool used for AWS requests
+type HTTPOptions struct {
+	MaxIdleConns        int           // Idle connections kept open, in total and per host
+	IdleConnTimeout     time.Duration // How long an idle connection stays in the pool
+	TLSHandshakeTimeout time.Duration // Maximum time to wait for a TLS handshake
+}
+
+// Client is the AWS-backed Route53Client; create one with NewClient
+type Client struct {
+	client *aws.Client
+}
+
+// CheckDomainAvailability asks Route 53 Domains whether domain is available
+func (c *Client) CheckDomainAvailability(ctx context.Context, domain string) (*route53domains.CheckDomainAvailabilityOutput, error) {
+	out, err := c.client.CheckDomainAvailability(ctx, domain)
+	return out, convertError(err)
+}
+
+// ListPrices returns the prices Route 53 Domains lists for tld
+func (c *Client) ListPrices(ctx context.Context, tld string) (*route53domains.ListPricesOutput, error) {
+	out, err := c.client.ListPrices(ctx, tld)
+	return out, convertError(err)
+}
+
+// ListTLDs returns every TLD Route 53 Domains can register
+func (c *Client) ListTLDs(ctx context.Context) ([]string, error) {
+	tlds, err := c.client.ListTLDs(ctx)
+	return tlds, convertError(err)
+}
+
+// DomainValidator is the built-in Validator; create one with NewValidator
+type DomainValidator struct {
+	validator *domain.DomainValidator
+}
+
+// ValidateDomain checks the format of domain and that its TLD is supported
+func (v *DomainValidator) ValidateDomain(domain string) error {
+	return convertError(v.validator.ValidateDomain(domain))
+}
+
+// SetTLDPolicy restricts or extends the TLDs the validator accepts. A nil
+// policy removes it.
+func (v *DomainValidator) SetTLDPolicy(policy *TLDPolicy) {
+	if policy == nil {
+		v.validator.SetTLDPolicy(nil)
+		return
+	}
+	v.validator.SetTLDPolicy(policy.policy)
+}
+
+// TLDPolicy restricts or extends the TLDs a DomainValidator accepts; create
+// one with ParseTLDPolicy
+type TLDPolicy struct {
+	policy *domain.TLDPolicy
+}
+
+// Add adds an entry such as "io" (allow), "+dev" (extend) or "-xyz" (deny)
+func (p *TLDPolicy) Add(entry string) error {
+	return p.policy.Add(entry)
+}
+
+// options collects the settings applied by Option functions
+type options struct {
+	region      string
+	profile     string
+	timeout     time.Duration
+	concurrency int
+	retryDelay  time.Duration
+	httpOptions HTTPOptions
+	client      Route53Client
+	validator   Validator
+	tlds        []string
+	policy      *TLDPolicy
+	hooks       []Hooks
+}
+
+// Option configures a Checker created by New
+type Option func(*options)
+
+// WithRegion sets the AWS region. The default is us-east-1, the only region
+// that serves Route 53 Domains.
+func WithRegion(region string) Option {
+	return func(o *options) { o.region = region }
+}
+
+// WithProfile loads AWS credentials from a named shared config profile
+func WithProfile(profile string) Option {
+	return func(o *options) { o.profile = profile }
+}
+
+// WithTimeout sets the timeout of each AWS request. The default is 10 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.timeout = timeout }
+}
+
+// WithConcurrency sets how many bulk requests run at once. The default is 5.
+func WithConcurrency(n int) Option {
+	return func(o *options) { o.concurrency = n }
+}
+
+// WithRetryDelay sets the pause before a failed domain is checked again. The
+// default is one second; a negative delay disables the retries.
+func WithRetryDelay(delay time.Duration) Option {
+	return func(o *options) { o.retryDelay = delay }
+}
+
+// WithHTTPOptions tunes the HTTP connection pool used for AWS requests
+func WithHTTPOptions(httpOptions HTTPOptions) Option {
+	return func(o *options) { o.httpOptions = httpOptions }
+}
+
+// WithRoute53Client uses client instead of creating an AWS-backed one, for
+// tests or instrumented clients. No AWS configuration is loaded.
+func WithRoute53Client(client Route53Client) Option {
+	return func(o *options) { o.client = client }
+}
+
+// WithValidator replaces the built-in domain validator
+func WithValidator(validator Validator) Option {
+	return func(o *options) { o.validator = validator }
+}
+
+// WithTLDs limits the built-in validator to a fixed list of TLDs instead of
+// the Route 53 TLD catalog
+func WithTLDs(tlds ...string) Option {
+	return func(o *options) { o.tlds = tlds }
+}
+
+// WithTLDPolicy applies a TLD policy to the built-in validator
+func WithTLDPolicy(policy *TLDPolicy) Option {
+	return func(o *options) { o.policy = policy }
+}
+
+// WithHooks registers hooks notified of every check the Checker performs
+func WithHooks(hooks ...Hooks) Option {
+	return func(o *options) { o.hooks = append(o.hooks, hooks...) }
+}
+
+// NewClient wraps an AWS SDK Route 53 Domains client, or any mock or instrumented
+// implementation of its API, for use with WithRoute53Client
+func NewClient(api Route53DomainsAPI) *Client {
+	return &Client{aws.NewClientWithAPI(api)}
+}
+
+// New creates a Checker. Unless WithRoute53Client is given, AWS credentials
+// are loaded from the default chain (or the WithProfile profile). Unless
+// WithTLDs or WithValidator is given, supported TLDs come from the cached
+// Route 53 TLD catalog, falling back to a built-in list.
+func New(ctx context.Context, opts ...Option) (*Checker, error) {
+	o := options{
+		timeout:     10 * time.Second,
+		retryDelay:  time.Second,
+		httpOptions: HTTPOptions(aws.DefaultHTTPOptions()),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// The checker is given the internal Client and DomainValidator, so it
+	// classifies and retries their errors before they are converted
+	var client domain.Route53Client = o.client
+	if c, ok := o.client.(*Client); ok {
+		client = c.client
+	}
+	if client == nil {
+		cfg, err := aws.NewConfigWithProfile(ctx, o.region, o.profile, aws.NewHTTPClient(aws.HTTPOptions(o.httpOptions)))
+		if err != nil {
+			return nil, convertError(err)
+		}
+		client = aws.NewClient(cfg)
+	}
+
+	var validator domain.Validator = o.validator
+	switch v := o.validator.(type) {
+	case nil:
+		validator = newValidator(ctx, client, o)
+	case *DomainValidator:
+		validator = v.validator
+	}
+
+	checker := domain.NewDomainCheckerWithTimeout(validator, client, o.timeout)
+	checker.SetConcurrency(o.concurrency)
+	checker.SetRetryDelay(o.retryDelay)
+	for _, h := range o.hooks {
+		checker.AddHooks(hooksAdapter{h})
+	}
+	return &Checker{checker}, nil
+}
+
+// newValidator builds the built-in validator from the options
+func newValidator(ctx context.Context, client domain.Route53Client, o options) *domain.DomainValidator {
+	tlds := o.tlds
+	if len(tlds) == 0 {
+		// A client that cannot list TLDs falls back to the cache or built-in list
+		source, _ := client.(domain.TLDSource)
+		tlds, _, _ = domain.NewTLDCatalog(source, domain.DefaultCatalogPath(), domain.DefaultCatalogTTL).Load(ctx)
+	}
+
+	validator := domain.NewDomainValidatorWithTLDs(tlds)
+	if o.policy != nil {
+		validator.SetTLDPolicy(o.policy.policy)
+	}
+	return validator
+}
+
+// NewValidator creates a domain validator that accepts the given TLDs, or the
+// built-in list when none are given
+func NewValidator(tlds ...string) *DomainValidator {
+	if len(tlds) == 0 {
+		return &DomainValidator{domain.NewDomainValidator()}
+	}
+	return &DomainValidator{domain.NewDomainValidatorWithTLDs(tlds)}
+}
+
+// ParseTLDPolicy builds a TLD policy from entries such as "io" (allow),
+// "+dev" (extend) and "-xyz" (deny)
+func ParseTLDPolicy(entries ...string) (*TLDPolicy, error) {
+	policy := &TLDPolicy{domain.NewTLDPolicy("r53check.ParseTLDPolicy")}
+	for _, entry := range entries {
+		if err := policy.Add(entry); err != nil {
+			return nil, err
+		}
+	}
+	return policy, nil
+}
+
+// NormalizeDomain reduces URLs, fully qualified names and subdomains to the
+// registrable domain, reporting whether the input changed
+func NormalizeDomain(input string) (string, bool) {
+	return domain.NormalizeDomain(input)
+}
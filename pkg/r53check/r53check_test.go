@@ -0,0 +1,225 @@
+package r53check
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/pkg/r53checktest"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+	"github.com/aws/smithy-go"
+)
+
+func TestNew_WithRoute53Client(t *testing.T) {
+	client := r53checktest.NewClient()
+
+	checker, err := New(context.Background(), WithRoute53Client(client), WithTLDs("com"), WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checker.GetTimeout() != time.Second {
+		t.Errorf("expected timeout 1s, got %v", checker.GetTimeout())
+	}
+
+	result, err := checker.CheckAvailability(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusAvailable || !result.Available {
+		t.Errorf("expected available result, got %+v", result)
+	}
+
+	// The TLD list limits what the validator accepts
+	_, err = checker.CheckAvailability(context.Background(), "example.io")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("expected validation error for an unlisted TLD, got %v", err)
+	}
+	if calls := client.Calls(); len(calls) != 1 {
+		t.Errorf("expected only the valid domain to reach the client, got %v", calls)
+	}
+}
+
+func TestNew_CatalogFromClient(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	client := r53checktest.NewScenario().Default(types.DomainAvailabilityUnavailable).TLDs("dev").Client()
+
+	checker, err := New(context.Background(), WithRoute53Client(client))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := checker.CheckAvailability(context.Background(), "example.dev")
+	if err != nil {
+		t.Fatalf("expected a TLD from the client's catalog to be accepted, got %v", err)
+	}
+	if result.Status != StatusUnavailable {
+		t.Errorf("expected unavailable, got %s", result.Status)
+	}
+	if _, err := checker.CheckAvailability(context.Background(), "example.com"); err == nil {
+		t.Error("expected .com to be rejected by a catalog that only lists .dev")
+	}
+}
+
+func TestNew_WithTLDPolicy(t *testing.T) {
+	policy, err := ParseTLDPolicy("-io")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := r53checktest.NewClient()
+
+	checker, err := New(context.Background(), WithRoute53Client(client), WithTLDs("com", "io"), WithTLDPolicy(policy))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := checker.CheckAvailability(context.Background(), "example.io"); err == nil {
+		t.Error("expected the policy to deny .io")
+	}
+}
+
+func TestNew_WithValidator(t *testing.T) {
+	client := r53checktest.NewClient()
+
+	checker, err := New(context.Background(), WithRoute53Client(client), WithValidator(NewValidator("org")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := checker.CheckAvailability(context.Background(), "example.org"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+type countingHooks struct {
+	NopHooks
+	started   []string
+	completed []*AvailabilityResult
+}
+
+func (h *countingHooks) OnCheckStart(ctx context.Context, domain string) {
+	h.started = append(h.started, domain)
+}
+
+func (h *countingHooks) OnCheckComplete(ctx context.Context, result *AvailabilityResult, err error) {
+	h.completed = append(h.completed, result)
+}
+
+func TestNew_WithHooks(t *testing.T) {
+	client := r53checktest.NewClient()
+	hooks := &countingHooks{}
+
+	checker, err := New(context.Background(), WithRoute53Client(client), WithTLDs("com"), WithHooks(hooks))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := checker.CheckAvailability(context.Background(), "example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hooks.started) != 1 || hooks.started[0] != "example.com" {
+		t.Errorf("expected one OnCheckStart for example.com, got %v", hooks.started)
+	}
+	if len(hooks.completed) != 1 || hooks.completed[0].Status != StatusAvailable {
+		t.Errorf("expected one OnCheckComplete with the result, got %+v", hooks.completed)
+	}
+}
+
+// deniedAPI is a Route53DomainsAPI refusing every call
+type deniedAPI struct{}
+
+func (deniedAPI) CheckDomainAvailability(ctx context.Context, params *route53domains.CheckDomainAvailabilityInput, optFns ...func(*route53domains.Options)) (*route53domains.CheckDomainAvailabilityOutput, error) {
+	return nil, &smithy.GenericAPIError{Code: "AccessDenied", Message: "not allowed"}
+}
+
+func (deniedAPI) ListPrices(ctx context.Context, params *route53domains.ListPricesInput, optFns ...func(*route53domains.Options)) (*route53domains.ListPricesOutput, error) {
+	return nil, &smithy.GenericAPIError{Code: "AccessDenied", Message: "not allowed"}
+}
+
+func TestNewClient_Errors(t *testing.T) {
+	client := NewClient(deniedAPI{})
+	checker, err := New(context.Background(), WithRoute53Client(client), WithTLDs("com"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, checkErr := checker.CheckAvailability(context.Background(), "example.com")
+	_, clientErr := client.CheckDomainAvailability(context.Background(), "example.com")
+	for _, err := range []error{checkErr, clientErr} {
+		var authErr *AuthorizationError
+		if !errors.As(err, &authErr) || Category(err) != CategoryAuthorization {
+			t.Errorf("expected a *AuthorizationError, got %T: %v", err, err)
+		}
+	}
+}
+
+func TestStatuses_MatchChecker(t *testing.T) {
+	statuses := map[AvailabilityStatus]domain.AvailabilityStatus{
+		StatusAvailable:   domain.StatusAvailable,
+		StatusUnavailable: domain.StatusUnavailable,
+		StatusReserved:    domain.StatusReserved,
+		StatusUnknown:     domain.StatusUnknown,
+	}
+	for public, internal := range statuses {
+		if string(public) != string(internal) {
+			t.Errorf("expected %s to match the checker's %s", public, internal)
+		}
+	}
+}
+
+func TestConvertError(t *testing.T) {
+	throttled := customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "rate exceeded", nil).WithStatusCode(429)
+	timeout := customErrors.NewTimeoutError("CheckDomainAvailability", time.Second, throttled)
+	err := convertError(timeout)
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Timeout != time.Second || err.Error() != timeout.Error() {
+		t.Fatalf("expected a *TimeoutError with the same message, got %T: %v", err, err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 429 {
+		t.Errorf("expected the wrapped *APIError to be converted too, got %v", timeoutErr.Cause)
+	}
+	var internalErr *customErrors.APIError
+	if errors.As(err, &internalErr) {
+		t.Error("expected no internal error in the chain")
+	}
+	if Category(err) != CategoryTimeout || Category(apiErr) != CategoryAPI {
+		t.Errorf("unexpected categories %s, %s", Category(err), Category(apiErr))
+	}
+	if !IsRetryable(apiErr) {
+		t.Error("expected the throttled error to be retryable")
+	}
+
+	plain := errors.New("plain")
+	if convertError(plain) != plain || convertError(nil) != nil {
+		t.Error("expected other errors to be returned as they are")
+	}
+}
+
+func TestStream_Cancelled(t *testing.T) {
+	checker, err := New(context.Background(), WithRoute53Client(r53checktest.NewClient()), WithTLDs("com"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var last error
+	for _, err := range checker.Stream(ctx, slices.Values([]string{"a.com", "b.com"})) {
+		last = err
+	}
+	var systemErr *SystemError
+	if !errors.As(last, &systemErr) || !errors.Is(last, context.Canceled) {
+		t.Errorf("expected a *SystemError wrapping the cancellation, got %T: %v", last, last)
+	}
+}
+
+func TestParseTLDPolicy_Invalid(t *testing.T) {
+	if _, err := ParseTLDPolicy("+"); err == nil {
+		t.Error("expected an error for an empty entry")
+	}
+}
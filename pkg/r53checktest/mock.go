@@ -0,0 +1,29 @@
+package r53checktest
+
+import (
+	"hash/fnv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// NewMockClient creates a fake client for running r53check without AWS
+// access, as its --mock flag does. Each domain is available or not
+// depending on a hash of its name, so it gets the same answer on every run,
+// and the DefaultPrices are known.
+func NewMockClient() *Client {
+	client := NewClient()
+	client.SetHashed(true)
+	return client
+}
+
+// HashedAvailability reports domain available for about half of all names,
+// decided by an FNV hash of the lowercased name
+func HashedAvailability(domain string) types.DomainAvailability {
+	hash := fnv.New32a()
+	hash.Write([]byte(strings.ToLower(domain)))
+	if hash.Sum32()%2 == 0 {
+		return types.DomainAvailabilityAvailable
+	}
+	return types.DomainAvailabilityUnavailable
+}
@@ -0,0 +1,245 @@
+// Package r53checktest provides test doubles for code built on the r53check
+// package: a configurable fake Route 53 client, canned availability and
+// pricing fixtures, and a Scenario builder for scripting per-domain responses.
+//
+//	client := r53checktest.NewScenario().
+//		Available("free.com").
+//		Unavailable("taken.com").
+//		ThrottledThen("busy.com", 1, types.DomainAvailabilityAvailable).
+//		Client()
+//	checker, err := r53check.New(ctx, r53check.WithRoute53Client(client), r53check.WithTLDs("com"))
+package r53checktest
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains"
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// Response is one scripted answer to an availability check. A non-nil Err
+// takes precedence over Availability; Delay is waited before answering and
+// is cut short if the request context ends.
+type Response struct {
+	Availability types.DomainAvailability
+	Err          error
+	Delay        time.Duration
+}
+
+// Respond returns a Response reporting availability
+func Respond(availability types.DomainAvailability) Response {
+	return Response{Availability: availability}
+}
+
+// Fail returns a Response failing with err
+func Fail(err error) Response {
+	return Response{Err: err}
+}
+
+// Price is the registration, renewal and transfer price of a TLD in USD
+type Price struct {
+	Registration float64
+	Renewal      float64
+	Transfer     float64
+}
+
+// DefaultPrices returns canned prices for a few common TLDs
+func DefaultPrices() map[string]Price {
+	return map[string]Price{
+		"com":   {Registration: 15, Renewal: 15, Transfer: 15},
+		"net":   {Registration: 17, Renewal: 17, Transfer: 17},
+		"org":   {Registration: 15, Renewal: 15, Transfer: 15},
+		"io":    {Registration: 71, Renewal: 71, Transfer: 71},
+		"dev":   {Registration: 16, Renewal: 16, Transfer: 16},
+		"co.uk": {Registration: 9, Renewal: 9, Transfer: 9},
+	}
+}
+
+// Throttled returns the error r53check reports when AWS rate limits a request
+func Throttled() error {
+	return customErrors.NewAPIError("route53domains", "CheckDomainAvailability", "rate limited", nil).WithStatusCode(429)
+}
+
+// AccessDenied returns the error r53check reports when the caller lacks permission
+func AccessDenied() error {
+	return customErrors.NewAuthorizationError("CheckDomainAvailability", "route53domains", "access denied", nil)
+}
+
+// Client is a fake Route 53 client safe for concurrent use. Domains without a
+// script are answered with the default availability; TLDs without a price
+// get an empty price list.
+type Client struct {
+	mu           sync.Mutex
+	availability types.DomainAvailability
+	hashed       bool
+	scripts      map[string][]Response
+	prices       map[string]Price
+	tlds         []string
+	calls        []string
+	attempts     map[string]int
+}
+
+// NewClient creates a fake client that reports every domain available and
+// knows the DefaultPrices
+func NewClient() *Client {
+	return &Client{
+		availability: types.DomainAvailabilityAvailable,
+		scripts:      make(map[string][]Response),
+		prices:       DefaultPrices(),
+		attempts:     make(map[string]int),
+	}
+}
+
+// SetDefault sets the availability reported for domains without a script
+func (c *Client) SetDefault(availability types.DomainAvailability) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.availability = availability
+}
+
+// SetHashed makes domains without a script answer with HashedAvailability
+// instead of the default availability
+func (c *Client) SetHashed(hashed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hashed = hashed
+}
+
+// Script sets the responses for domain, one per attempt. Once they run out
+// the last response is repeated.
+func (c *Client) Script(domain string, responses ...Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.scripts[strings.ToLower(domain)] = responses
+}
+
+// SetPrice sets the price list returned for tld
+func (c *Client) SetPrice(tld string, price Price) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prices[strings.ToLower(tld)] = price
+}
+
+// SetTLDs sets the catalog returned by ListTLDs. Without it ListTLDs fails,
+// so checkers fall back to their cached or built-in TLD list.
+func (c *Client) SetTLDs(tlds ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tlds = tlds
+}
+
+// CheckDomainAvailability answers with the domain's next scripted response
+func (c *Client) CheckDomainAvailability(ctx context.Context, domain string) (*route53domains.CheckDomainAvailabilityOutput, error) {
+	key := strings.ToLower(domain)
+
+	c.mu.Lock()
+	c.calls = append(c.calls, domain)
+	c.attempts[key]++
+	response := Response{Availability: c.availability}
+	if c.hashed {
+		response.Availability = HashedAvailability(key)
+	}
+	if script := c.scripts[key]; len(script) > 0 {
+		response = script[min(c.attempts[key], len(script))-1]
+	}
+	c.mu.Unlock()
+
+	if response.Delay > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(response.Delay):
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if response.Err != nil {
+		return nil, response.Err
+	}
+	return &route53domains.CheckDomainAvailabilityOutput{Availability: response.Availability}, nil
+}
+
+// ListPrices returns the price set for tld, if any
+func (c *Client) ListPrices(ctx context.Context, tld string) (*route53domains.ListPricesOutput, error) {
+	c.mu.Lock()
+	price, ok := c.prices[strings.ToLower(tld)]
+	c.mu.Unlock()
+
+	if !ok {
+		return &route53domains.ListPricesOutput{}, nil
+	}
+	return &route53domains.ListPricesOutput{Prices: []types.DomainPrice{domainPrice(tld, price)}}, nil
+}
+
+// ListTLDPrices returns every price set with SetPrice, by TLD. Without any
+// it fails, so checkers fall back to asking ListPrices for each TLD.
+func (c *Client) ListTLDPrices(ctx context.Context) ([]types.DomainPrice, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.prices) == 0 {
+		return nil, customErrors.NewAPIError("route53domains", "ListPrices", "no price list configured", nil)
+	}
+	prices := make([]types.DomainPrice, 0, len(c.prices))
+	for tld, price := range c.prices {
+		prices = append(prices, domainPrice(tld, price))
+	}
+	sort.Slice(prices, func(i, j int) bool { return *prices[i].Name < *prices[j].Name })
+	return prices, nil
+}
+
+// domainPrice is price as Route 53 Domains lists it for tld
+func domainPrice(tld string, price Price) types.DomainPrice {
+	return types.DomainPrice{
+		Name:              aws.String(tld),
+		RegistrationPrice: &types.PriceWithCurrency{Price: price.Registration, Currency: aws.String("USD")},
+		RenewalPrice:      &types.PriceWithCurrency{Price: price.Renewal, Currency: aws.String("USD")},
+		TransferPrice:     &types.PriceWithCurrency{Price: price.Transfer, Currency: aws.String("USD")},
+	}
+}
+
+// ListTLDs returns the catalog set with SetTLDs
+func (c *Client) ListTLDs(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.tlds) == 0 {
+		return nil, customErrors.NewAPIError("route53domains", "ListPrices", "no TLD catalog configured", nil)
+	}
+	return append([]string(nil), c.tlds...), nil
+}
+
+// Calls returns every domain checked so far, in call order
+func (c *Client) Calls() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.calls...)
+}
+
+// CallCount returns how many times domain has been checked
+func (c *Client) CallCount(domain string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.attempts[strings.ToLower(domain)]
+}
+
+// CheckedDomains returns the distinct domains checked so far, sorted
+func (c *Client) CheckedDomains() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	domains := make([]string, 0, len(c.attempts))
+	for domain := range c.attempts {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	return domains
+}
@@ -0,0 +1,152 @@
+package r53checktest_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/pkg/r53check"
+	"github.com/abakermi/r53check/pkg/r53checktest"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+func TestClient_Script(t *testing.T) {
+	client := r53checktest.NewClient()
+	client.Script("example.com",
+		r53checktest.Fail(r53checktest.Throttled()),
+		r53checktest.Respond(types.DomainAvailabilityUnavailable),
+	)
+
+	ctx := context.Background()
+	if _, err := client.CheckDomainAvailability(ctx, "example.com"); !customErrors.IsThrottling(err) {
+		t.Errorf("expected a throttling error on the first attempt, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		out, err := client.CheckDomainAvailability(ctx, "EXAMPLE.com")
+		if err != nil || out.Availability != types.DomainAvailabilityUnavailable {
+			t.Errorf("expected the last response to repeat, got %v, %v", out, err)
+		}
+	}
+
+	out, err := client.CheckDomainAvailability(ctx, "other.com")
+	if err != nil || out.Availability != types.DomainAvailabilityAvailable {
+		t.Errorf("expected unscripted domains to be available, got %v, %v", out, err)
+	}
+
+	if n := client.CallCount("example.com"); n != 3 {
+		t.Errorf("expected 3 calls for example.com, got %d", n)
+	}
+	if calls := client.Calls(); len(calls) != 4 || calls[3] != "other.com" {
+		t.Errorf("unexpected call log %v", calls)
+	}
+}
+
+func TestClient_DelayHonoursContext(t *testing.T) {
+	client := r53checktest.NewScenario().Slow("slow.com", time.Minute, types.DomainAvailabilityAvailable).Client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.CheckDomainAvailability(ctx, "slow.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestClient_ListPrices(t *testing.T) {
+	client := r53checktest.NewClient()
+	client.SetPrice("app", r53checktest.Price{Registration: 20, Renewal: 22, Transfer: 20})
+
+	out, err := client.ListPrices(context.Background(), "app")
+	if err != nil || len(out.Prices) != 1 || out.Prices[0].RenewalPrice.Price != 22 {
+		t.Errorf("expected the configured price, got %+v, %v", out, err)
+	}
+
+	out, err = client.ListPrices(context.Background(), "unknown")
+	if err != nil || len(out.Prices) != 0 {
+		t.Errorf("expected no prices for an unknown TLD, got %+v, %v", out, err)
+	}
+
+	if _, err := client.ListTLDs(context.Background()); err == nil {
+		t.Error("expected ListTLDs to fail without a configured catalog")
+	}
+
+	prices, err := client.ListTLDPrices(context.Background())
+	if err != nil || len(prices) != len(r53checktest.DefaultPrices())+1 {
+		t.Errorf("expected the default prices and the configured one, got %d prices, %v", len(prices), err)
+	}
+}
+
+func TestNewMockClient(t *testing.T) {
+	client := r53checktest.NewMockClient()
+	client.Script("scripted.com", r53checktest.Respond(types.DomainAvailabilityReserved))
+
+	ctx := context.Background()
+	counts := make(map[types.DomainAvailability]int)
+	for _, name := range []string{"alpha.com", "bravo.com", "charlie.com", "delta.com", "echo.com", "foxtrot.com", "golf.com", "hotel.com"} {
+		first, err := client.CheckDomainAvailability(ctx, name)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", name, err)
+		}
+		again, _ := client.CheckDomainAvailability(ctx, strings.ToUpper(name))
+		if first.Availability != again.Availability || first.Availability != r53checktest.HashedAvailability(name) {
+			t.Errorf("expected %s to get the same hashed answer every time, got %s and %s", name, first.Availability, again.Availability)
+		}
+		counts[first.Availability]++
+	}
+	if counts[types.DomainAvailabilityAvailable] == 0 || counts[types.DomainAvailabilityUnavailable] == 0 {
+		t.Errorf("expected a mix of available and unavailable domains, got %v", counts)
+	}
+
+	if out, _ := client.CheckDomainAvailability(ctx, "scripted.com"); out.Availability != types.DomainAvailabilityReserved {
+		t.Errorf("expected scripts to take precedence over the hash, got %s", out.Availability)
+	}
+	if out, err := client.ListPrices(ctx, "com"); err != nil || len(out.Prices) != 1 {
+		t.Errorf("expected the default prices, got %+v, %v", out, err)
+	}
+}
+
+func TestScenario_WithChecker(t *testing.T) {
+	client := r53checktest.NewScenario().
+		Default(types.DomainAvailabilityUnavailable).
+		Available("free.com").
+		Reserved("held.com").
+		ThrottledThen("busy.com", 1, types.DomainAvailabilityAvailable).
+		Failing("denied.com", r53checktest.AccessDenied()).
+		Client()
+
+	checker, err := r53check.New(context.Background(), r53check.WithRoute53Client(client), r53check.WithTLDs("com"), r53check.WithRetryDelay(0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results, err := checker.CheckAvailabilityBulkWithPricing(context.Background(),
+		[]string{"free.com", "held.com", "busy.com", "denied.com", "taken.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []r53check.AvailabilityStatus{
+		r53check.StatusAvailable, r53check.StatusReserved, r53check.StatusAvailable,
+		r53check.StatusUnknown, r53check.StatusUnavailable,
+	}
+	for i, result := range results {
+		if result.Status != expected[i] {
+			t.Errorf("%s: expected %s, got %s", result.Domain, expected[i], result.Status)
+		}
+	}
+	if results[0].Pricing == nil || *results[0].Pricing.RegistrationPrice != 15 {
+		t.Errorf("expected canned .com pricing, got %+v", results[0].Pricing)
+	}
+	if !results[2].Retried {
+		t.Error("expected busy.com to succeed on retry")
+	}
+
+	want := []string{"busy.com", "denied.com", "free.com", "held.com", "taken.com"}
+	if got := client.CheckedDomains(); !slices.Equal(got, want) {
+		t.Errorf("expected checked domains %v, got %v", want, got)
+	}
+}
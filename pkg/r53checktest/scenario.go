@@ -0,0 +1,98 @@
+package r53checktest
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53domains/types"
+)
+
+// Scenario builds a Client by describing how each domain behaves. Every
+// method returns the Scenario so calls can be chained.
+type Scenario struct {
+	client *Client
+}
+
+// NewScenario starts a scenario on a fresh NewClient
+func NewScenario() *Scenario {
+	return &Scenario{client: NewClient()}
+}
+
+// Default sets the availability of domains the scenario does not mention
+func (s *Scenario) Default(availability types.DomainAvailability) *Scenario {
+	s.client.SetDefault(availability)
+	return s
+}
+
+// Available makes each domain available for registration
+func (s *Scenario) Available(domains ...string) *Scenario {
+	return s.respond(types.DomainAvailabilityAvailable, domains)
+}
+
+// Unavailable makes each domain already registered
+func (s *Scenario) Unavailable(domains ...string) *Scenario {
+	return s.respond(types.DomainAvailabilityUnavailable, domains)
+}
+
+// Reserved makes each domain reserved by its registry
+func (s *Scenario) Reserved(domains ...string) *Scenario {
+	return s.respond(types.DomainAvailabilityReserved, domains)
+}
+
+// Unknown makes AWS unable to determine each domain's availability
+func (s *Scenario) Unknown(domains ...string) *Scenario {
+	return s.respond(types.DomainAvailabilityDontKnow, domains)
+}
+
+// Failing makes every check of domain fail with err
+func (s *Scenario) Failing(domain string, err error) *Scenario {
+	s.client.Script(domain, Fail(err))
+	return s
+}
+
+// ThrottledThen makes the first n checks of domain fail with Throttled and
+// later checks report availability
+func (s *Scenario) ThrottledThen(domain string, n int, availability types.DomainAvailability) *Scenario {
+	responses := make([]Response, 0, n+1)
+	for i := 0; i < n; i++ {
+		responses = append(responses, Fail(Throttled()))
+	}
+	s.client.Script(domain, append(responses, Respond(availability))...)
+	return s
+}
+
+// Slow makes domain answer with availability only after delay
+func (s *Scenario) Slow(domain string, delay time.Duration, availability types.DomainAvailability) *Scenario {
+	s.client.Script(domain, Response{Availability: availability, Delay: delay})
+	return s
+}
+
+// Script sets the responses for domain, one per attempt, as Client.Script does
+func (s *Scenario) Script(domain string, responses ...Response) *Scenario {
+	s.client.Script(domain, responses...)
+	return s
+}
+
+// Price sets the price list returned for tld
+func (s *Scenario) Price(tld string, price Price) *Scenario {
+	s.client.SetPrice(tld, price)
+	return s
+}
+
+// TLDs sets the catalog returned by ListTLDs
+func (s *Scenario) TLDs(tlds ...string) *Scenario {
+	s.client.SetTLDs(tlds...)
+	return s
+}
+
+// Client returns the configured fake client
+func (s *Scenario) Client() *Client {
+	return s.client
+}
+
+// respond scripts a single availability for each domain
+func (s *Scenario) respond(availability types.DomainAvailability, domains []string) *Scenario {
+	for _, domain := range domains {
+		s.client.Script(domain, Respond(availability))
+	}
+	return s
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+)
+
+// prescreenChoices are the --prescreen values
+var prescreenChoices = []string{"dns"}
+
+// prescreenFlag is --prescreen: how bulk finds registered domains before
+// asking Route 53
+var prescreenFlag string
+
+// newPrescreener creates the prescreener of a --prescreen value; tests
+// replace it
+var newPrescreener = func(name string) domain.Prescreener {
+	return domain.NewDNSPrescreener()
+}
+
+// setupPrescreen gives checker the --prescreen prescreener, if one was asked for
+func setupPrescreen(checker *domain.DomainChecker) error {
+	name := strings.ToLower(strings.TrimSpace(prescreenFlag))
+	switch name {
+	case "":
+		return nil
+	case "dns":
+		if verbose {
+			fmt.Fprintln(stderr, "Prescreening with DNS; domains with name servers are reported UNAVAILABLE without calling Route 53")
+		}
+		checker.SetPrescreener(newPrescreener(name))
+		return nil
+	default:
+		return customErrors.NewValidationError("", "prescreen",
+			fmt.Sprintf("invalid --prescreen value %q: must be one of %s%s", prescreenFlag, strings.Join(prescreenChoices, ", "),
+				customErrors.DidYouMean(prescreenFlag, prescreenChoices)), nil)
+	}
+}
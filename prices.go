@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/history"
+	"github.com/abakermi/r53check/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+// priceCacheTTL is --price-cache-ttl: how long the cached price list is
+// used before it is fetched again
+var priceCacheTTL time.Duration
+
+// pricesCmd groups the commands that manage the cached Route 53 price list
+// and the price history
+var pricesCmd = &cobra.Command{
+	Use:   "prices",
+	Short: "Manage the cached Route 53 price list and show how prices changed",
+	Long: `Manage the cached Route 53 price list and show how prices changed.
+
+Pricing-enabled runs read the price of every TLD from a list cached under
+$XDG_CACHE_HOME/r53check, fetching it with ListPrices once it is older than
+--price-cache-ttl (default 24h) instead of asking for each TLD. When Route 53
+Domains cannot be reached an older list is used, so prices are still shown,
+with a warning that they may be out of date.
+
+The prices seen by pricing-enabled runs and by prices refresh are recorded in
+the check history whenever they change, so prices history shows when a TLD
+became cheaper or dearer.`,
+}
+
+var pricesRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Fetch the Route 53 price list and cache it, however recent the cached one is",
+	Long: `Fetch the Route 53 price list and cache it, however recent the cached one is.
+
+The prices that changed since they were last recorded in the check history are
+recorded and listed.`,
+	Args: cobra.NoArgs,
+	RunE: runPricesRefreshCommand,
+}
+
+var pricesHistoryCmd = &cobra.Command{
+	Use:   "history <tld>",
+	Short: "Show how the price of a TLD changed over time",
+	Long: `Show every price of a TLD recorded in the check history, oldest first, with
+the change in registration price from the previous one.
+
+Prices are recorded when they change, by pricing-enabled runs and by prices
+refresh, so each price was charged from the time it was observed until the
+next. Nothing is fetched from Route 53.`,
+	Example: `  # Show when .io became cheaper or dearer
+  r53check prices history io
+
+  # Export the price history as JSON
+  r53check prices history io --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPricesHistoryCommand,
+}
+
+func init() {
+	pricesRefreshCmd.Flags().StringVar(&historyFile, "history", "", "History database (default $XDG_DATA_HOME/r53check/history.db)")
+	pricesRefreshCmd.Flags().BoolVar(&noHistory, "no-history", false, "Do not record prices in the history database")
+	pricesHistoryCmd.Flags().StringVar(&historyFile, "history", "", "History database (default $XDG_DATA_HOME/r53check/history.db)")
+
+	pricesCmd.AddCommand(pricesRefreshCmd)
+	pricesCmd.AddCommand(pricesHistoryCmd)
+	rootCmd.AddCommand(pricesCmd)
+}
+
+func runPricesRefreshCommand(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	client, err := newTimedRoute53Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	path := appDirs().PriceCatalog()
+	list, err := domain.NewPriceCatalog(client, path, priceCacheTTL).Refresh(ctx)
+	if err != nil {
+		if list == nil {
+			return err
+		}
+		fmt.Fprintf(stderr, "Unable to cache the price list: %v\n", err)
+	}
+	changes := recordPriceList(ctx, list)
+
+	if outputFormat == output.FormatJSON {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"path": path, "tlds": len(list.Prices), "fetched_at": list.FetchedAt, "price_changes": changes,
+		}, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+	fmt.Fprintf(stdout, "Cached the prices of %s in %s\n", pluralize(len(list.Prices), "TLD"), path)
+	for _, change := range changes {
+		fmt.Fprintf(stdout, "  .%s: %s -> %s\n", change.TLD, change.Previous.Price(), change.Current.Price())
+	}
+	return nil
+}
+
+// recordPriceList records the prices of list in the history and returns
+// the prices that changed. History is a convenience here, so a failure
+// only produces a warning.
+func recordPriceList(ctx context.Context, list *domain.PriceList) []history.PriceChange {
+	store := openCommandHistory(ctx)
+	if store == nil {
+		return []history.PriceChange{}
+	}
+	defer store.Close()
+	prices, ok := store.(history.PriceStore)
+	if !ok {
+		fmt.Fprintf(stderr, "Warning: prices were not recorded: %v\n", errNoPriceHistory())
+		return []history.PriceChange{}
+	}
+
+	tlds := make([]string, 0, len(list.Prices))
+	for tld := range list.Prices {
+		tlds = append(tlds, tld)
+	}
+	sort.Strings(tlds)
+	records := make([]history.PriceRecord, len(tlds))
+	for i, tld := range tlds {
+		records[i] = history.NewPriceRecord(tld, list.Prices[tld], list.FetchedAt)
+	}
+
+	changes, err := history.RecordPrices(prices, records)
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: prices were not recorded: %v\n", err)
+	}
+	if changes == nil {
+		changes = []history.PriceChange{}
+	}
+	return changes
+}
+
+// errNoPriceHistory is the error for a history that keeps no prices
+func errNoPriceHistory() error {
+	return customErrors.NewValidationError("", "history", "this history does not record prices; prices history needs the SQLite, DynamoDB or Postgres history", nil)
+}
+
+func runPricesHistoryCommand(cmd *cobra.Command, args []string) error {
+	tld := strings.Trim(strings.ToLower(strings.TrimSpace(args[0])), ".")
+	if tld == "" {
+		return customErrors.NewValidationError("", "tld", "a TLD such as com or co.uk is required", nil)
+	}
+
+	store, err := openHistory(context.Background())
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	prices, ok := store.(history.PriceStore)
+	if !ok {
+		return errNoPriceHistory()
+	}
+
+	records, err := prices.Prices(tld)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		// Machines sharing a history may record prices out of order
+		return records[i].ObservedAt.Before(records[j].ObservedAt)
+	})
+
+	if outputFormat == output.FormatJSON {
+		if records == nil {
+			records = []history.PriceRecord{}
+		}
+		data, _ := json.MarshalIndent(map[string]interface{}{"tld": tld, "prices": records}, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+	fmt.Fprint(stdout, formatPriceHistory(tld, records))
+	return nil
+}
+
+// formatPriceHistory renders the recorded prices of tld, one per line, with
+// the change in registration price from the previous one
+func formatPriceHistory(tld string, records []history.PriceRecord) string {
+	var b strings.Builder
+	if len(records) == 0 {
+		fmt.Fprintf(&b, ".%s: no prices recorded\n", tld)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, ".%s: %s recorded\n", tld, pluralize(len(records), "price"))
+	const layout = "2006-01-02 15:04"
+	for i, record := range records {
+		fmt.Fprintf(&b, "  %s  %s", record.ObservedAt.Local().Format(layout), record.Price())
+		if i > 0 && records[i-1].Registration != nil && record.Registration != nil {
+			if delta := *record.Registration - *records[i-1].Registration; delta != 0 {
+				fmt.Fprintf(&b, "  %+.2f", delta)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// setupPriceList prices checker's checks from the cached price list, when
+// the client can list every price at once. Without a fresh list or a stale
+// one to fall back on, each TLD is still asked for separately.
+func setupPriceList(checker *domain.DomainChecker, awsClient domain.Route53Client) {
+	source, ok := awsClient.(domain.PriceSource)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	start := time.Now()
+	list, origin, err := domain.NewPriceCatalog(source, appDirs().PriceCatalog(), priceCacheTTL).Load(ctx)
+	timePhase("price list", start)
+
+	if err != nil && verbose {
+		fmt.Fprintf(stderr, "Unable to fetch the price list from Route 53 Domains: %v\n", err)
+	}
+	if list == nil {
+		return
+	}
+	if origin == domain.OriginStale {
+		fmt.Fprintf(stderr, "Warning: using prices cached on %s, which may be out of date\n", list.FetchedAt.Format("2006-01-02 15:04 MST"))
+	} else if verbose {
+		fmt.Fprintf(stderr, "Using the prices of %d TLDs (%s)\n", len(list.Prices), origin)
+	}
+	checker.SetPriceList(list.Prices)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+var (
+	// Hidden profiling flags
+	cpuProfile  string
+	heapProfile string
+
+	// exitHooks run once the command has finished, before the process exits
+	exitHooks []func()
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&cpuProfile, "pprof-cpu", "", "Write a CPU profile of the run to this file")
+	rootCmd.PersistentFlags().StringVar(&heapProfile, "pprof-heap", "", "Write a heap profile at the end of the run to this file")
+	rootCmd.PersistentFlags().MarkHidden("pprof-cpu")
+	rootCmd.PersistentFlags().MarkHidden("pprof-heap")
+}
+
+// runExitHooks runs the registered exit hooks in reverse order and clears them
+func runExitHooks() {
+	for i := len(exitHooks) - 1; i >= 0; i-- {
+		exitHooks[i]()
+	}
+	exitHooks = nil
+}
+
+// startProfiling starts the CPU profile and arranges for both profiles to be
+// written when the process exits
+func startProfiling() error {
+	if cpuProfile != "" {
+		file, err := os.Create(cpuProfile)
+		if err != nil {
+			return fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		exitHooks = append(exitHooks, func() {
+			pprof.StopCPUProfile()
+			file.Close()
+		})
+	}
+
+	if heapProfile != "" {
+		exitHooks = append(exitHooks, writeHeapProfile)
+	}
+
+	return nil
+}
+
+// writeHeapProfile writes the heap profile after a GC so it reflects live memory
+func writeHeapProfile() {
+	file, err := os.Create(heapProfile)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error creating heap profile: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		fmt.Fprintf(stderr, "Error writing heap profile: %v\n", err)
+	}
+}
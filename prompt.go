@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/spf13/cobra"
+)
+
+// stdinTerminal reports whether stdin is a terminal someone can type a
+// domain into; tests replace it
+var stdinTerminal = func() bool {
+	_, ok := stdin.(*os.File)
+	return ok && !stdinPiped()
+}
+
+// checkArgs accepts a single domain, or none when stdin is a terminal, in
+// which case check prompts for it
+func checkArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && stdinTerminal() {
+		return nil
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
+// promptDomain asks for a domain on stderr until one passes validation,
+// explaining what is wrong with each rejected entry, and returns it
+// normalized. Validation uses the same TLD catalog, policy and --fallback as
+// the check.
+func promptDomain(ctx context.Context) (string, error) {
+	setupCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	awsClient, err := newTimedRoute53Client(setupCtx)
+	if err != nil {
+		return "", err
+	}
+	validator, _, err := newValidator(setupCtx, awsClient)
+	if err != nil {
+		return "", err
+	}
+	checker, err := newChecker(validator, awsClient)
+	if err != nil {
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(stdin)
+	for {
+		fmt.Fprint(stderr, "Domain to check: ")
+		if !scanner.Scan() {
+			fmt.Fprintln(stderr)
+			if err := scanner.Err(); err != nil {
+				return "", customErrors.NewSystemError("prompt", "unable to read the domain", err)
+			}
+			return "", customErrors.NewValidationError("", "domain", "no domain entered", nil)
+		}
+
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" {
+			continue
+		}
+		name := normalizeDomains([]string{entry})[0]
+		if err := checker.ValidateDomain(name); err != nil {
+			for _, message := range validationMessages(err) {
+				fmt.Fprintf(stderr, "  ✗ %s\n", message)
+			}
+			continue
+		}
+		return name, nil
+	}
+}
+
+// validationMessages returns the problems a validation error reports, one
+// per message
+func validationMessages(err error) []string {
+	var validationErrs *customErrors.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		messages := make([]string, len(validationErrs.Errors))
+		for i, validationErr := range validationErrs.Errors {
+			messages[i] = validationErr.Message
+		}
+		return messages
+	}
+	var validationErr *customErrors.ValidationError
+	if errors.As(err, &validationErr) {
+		return []string{validationErr.Message}
+	}
+	return []string{err.Error()}
+}
@@ -0,0 +1,484 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abakermi/r53check/internal/aws"
+	"github.com/abakermi/r53check/internal/budget"
+	"github.com/abakermi/r53check/internal/config"
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/output"
+	"github.com/abakermi/r53check/internal/register"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// registerSettings holds the registration contact, from the config file
+	registerSettings config.RegisterSettings
+
+	// registerFrom is --from: the JSON results to pick the domains from
+	registerFrom string
+
+	// onlyAvailable is --only-available: skip the results that are not
+	// available instead of refusing the file
+	onlyAvailable bool
+
+	// maxTotal is --max-total: refuse registrations costing more together
+	maxTotal float64
+
+	// registerDryRun is --dry-run: show the cost without registering
+	registerDryRun bool
+
+	// registerWait is --wait: how long to follow the operations
+	registerWait time.Duration
+
+	// autoRenew and privacy are --auto-renew and --privacy
+	autoRenew bool
+	privacy   bool
+
+	// domainTagValues is --tags, and domainTags the tags it gives the
+	// registered domains
+	domainTagValues []string
+	domainTags      map[string]string
+)
+
+// registerPollInterval is how often --wait polls the operations; tests
+// shorten it
+var registerPollInterval = 15 * time.Second
+
+// newRegistrar creates what register submits registrations to; tests
+// replace it
+var newRegistrar = func(ctx context.Context, options aws.RegistrationOptions) (register.Registrar, error) {
+	awsConfig, err := loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return aws.NewRegistrar(awsConfig, options), nil
+}
+
+// registerCmd represents the register command
+var registerCmd = &cobra.Command{
+	Use:   "register [domain...]",
+	Short: "Register domains, such as the available ones from an earlier bulk check",
+	Long: `Register domains with Route 53 Domains, charging the AWS account.
+
+The domains are given as arguments, or picked from the JSON output of an
+earlier bulk or generate run with --from. A file with domains that are not
+available is refused unless --only-available skips them; domains given as
+arguments along with --from narrow the selection to those. The results are
+not checked again, so a domain taken since fails to register.
+
+Each domain is registered for --years years, 1 by default, to the contact
+profile picked with --contact or register.profile (see r53check contacts), or
+else to register.contact as the registrant, administrative and technical
+contact. The cost of each domain and the total
+are shown before anything is registered, and must be confirmed unless --yes is
+given; --dry-run stops after showing them. --max-total refuses a run costing
+more, as does the budget in the config file unless --override-budget is
+given.
+
+Registrations are submitted one at a time. Route 53 finishes each
+asynchronously and reports it by operation ID; --wait follows the operations
+until they finish. A domain that fails to register does not stop the others,
+and the run then exits with code 6. --tags tags the domains once they are
+registered; those still registering are tagged by r53check tags audit --apply.`,
+	Example: `  # Register the available domains from a bulk run, up to $200 in total
+  r53check bulk domains.txt --output json > results.json
+  r53check register --from results.json --only-available --max-total 200
+
+  # Register two of them for 2 years and wait for the registrations
+  r53check register --from results.json --only-available --years 2 --wait 10m a.com b.io
+
+  # The contact in ~/.config/r53check/config.yaml
+  register:
+    contact:
+      first_name: Ada
+      last_name: Lovelace
+      email: ada@example.com
+      phone: "+44.2071234567"
+      address_line_1: 12 St James's Square
+      city: London
+      country_code: GB
+      zip_code: SW1Y 4JH`,
+	RunE: runRegisterCommand,
+}
+
+func init() {
+	registerCmd.Flags().StringVar(&registerFrom, "from", "", "Pick the domains from the JSON output of bulk or generate")
+	registerCmd.Flags().BoolVar(&onlyAvailable, "only-available", false, "Skip the domains in --from that are not available")
+	registerCmd.Flags().Float64Var(&maxTotal, "max-total", 0, "Refuse the run when the registrations cost more than this together, in USD")
+	registerCmd.Flags().BoolVar(&registerDryRun, "dry-run", false, "Show what the registrations would cost without registering")
+	registerCmd.Flags().DurationVar(&registerWait, "wait", 0, "Follow the registrations for up to this long until they finish")
+	addConfirmFlags(registerCmd)
+	addRegistrationFlags(registerCmd)
+
+	rootCmd.AddCommand(registerCmd)
+}
+
+// addRegistrationFlags registers the flags of a command that registers
+// domains: --contact, --tags, --auto-renew, --privacy and --override-budget
+func addRegistrationFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&domainTagValues, "tags", nil, "Tag the registered domains with comma-separated key=value pairs, such as team=platform,env=prod")
+	cmd.Flags().StringVar(&contactProfile, "contact", "", "Register to this contact profile of the config file (default register.profile)")
+	cmd.Flags().BoolVar(&autoRenew, "auto-renew", true, "Renew the domains automatically before they expire")
+	cmd.Flags().BoolVar(&privacy, "privacy", true, "Hide the contacts from WHOIS where the TLD allows it; given, it overrides the toggles of the contact profile")
+	addOverrideBudgetFlag(cmd)
+}
+
+// registrationOptions returns the options registrations are made with: the
+// contact profile of --contact or register.profile, or else register.contact
+// for every contact, and the --auto-renew and --privacy flags
+func registrationOptions(ctx context.Context, cmd *cobra.Command) (aws.RegistrationOptions, error) {
+	name := contactProfile
+	if name == "" {
+		name = registerSettings.Profile
+	}
+	if name == "" {
+		if registerSettings.Contact == (config.ContactSettings{}) && len(contactProfiles) > 0 {
+			return aws.RegistrationOptions{}, customErrors.NewValidationError("", "contact",
+				"pick the contact profile to register to with --contact or register.profile", nil)
+		}
+		contact, err := register.NewContact(registerSettings.Contact)
+		if err != nil {
+			return aws.RegistrationOptions{}, err
+		}
+		return aws.RegistrationOptions{
+			Registrant: contact, Admin: contact, Tech: contact,
+			AutoRenew: autoRenew,
+			Privacy:   aws.ContactPrivacy{Registrant: privacy, Admin: privacy, Tech: privacy},
+		}, nil
+	}
+
+	profile, err := resolveContactProfile(ctx, name)
+	if err != nil {
+		return aws.RegistrationOptions{}, err
+	}
+	if cmd.Flags().Changed("privacy") {
+		profile.Privacy = aws.ContactPrivacy{Registrant: privacy, Admin: privacy, Tech: privacy}
+	}
+	return profile.Options(autoRenew)
+}
+
+// registrationJSON is the JSON output of register
+type registrationJSON struct {
+	Years     int                `json:"years"`
+	DryRun    bool               `json:"dry_run,omitempty"`
+	Domains   []register.Outcome `json:"domains"`
+	Total     float64            `json:"total"`
+	Currency  string             `json:"currency"`
+	Submitted int                `json:"submitted"`
+	Failed    int                `json:"failed"`
+}
+
+func runRegisterCommand(cmd *cobra.Command, args []string) error {
+	if mockMode && !registerDryRun {
+		return customErrors.NewValidationError("", "mock", "register buys domains from Route 53 Domains, which --mock cannot; use --dry-run to see the cost", nil)
+	}
+	if registerFrom == "" && len(args) == 0 {
+		return customErrors.NewValidationError("", "from", "give the domains to register, or --from with the results to pick them from", nil)
+	}
+	if maxTotal < 0 {
+		return customErrors.NewValidationError("", "max-total", "--max-total cannot be negative", nil)
+	}
+	if err := parseDomainTags(); err != nil {
+		return err
+	}
+	names, err := registerCandidates(normalizeDomains(args))
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Fprintln(stderr, "No domains to register")
+		return nil
+	}
+	registerYears := max(years, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The first interrupt stops submitting and waiting once the registration
+	// in flight returns; registrations already submitted go ahead regardless
+	stopInterrupts := handleInterrupts(cancel, func() {
+		fmt.Fprintln(stderr, "Aborted: the registrations already submitted still go ahead")
+		runExitHooks()
+		exitProcess(int(customErrors.ExitSystemError))
+	})
+	defer stopInterrupts()
+
+	options, err := registrationOptions(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	orders, err := priceOrders(ctx, names, registerYears)
+	if err != nil {
+		return err
+	}
+	result := registrationJSON{Years: registerYears, DryRun: registerDryRun, Currency: orders[0].Currency}
+	for _, order := range orders {
+		result.Total += order.Cost
+		result.Domains = append(result.Domains, register.Outcome{Order: order})
+	}
+
+	if outputFormat != output.FormatJSON {
+		fmt.Fprint(stdout, formatRegistrationCost(orders, registerYears, result.Total))
+	}
+	if maxTotal > 0 && result.Total > maxTotal {
+		return customErrors.NewValidationError("", "max-total",
+			fmt.Sprintf("the registrations cost $%.2f, over --max-total $%.2f", result.Total, maxTotal), nil)
+	}
+	purchases := registrationPurchases(result.Domains, time.Now())
+	guard, err := checkBudget(purchases)
+	if err != nil {
+		return err
+	}
+	if registerDryRun {
+		if outputFormat == output.FormatJSON {
+			printRegistrationJSON(result)
+		}
+		return nil
+	}
+	action := fmt.Sprintf("Register %s for %s", pluralize(len(orders), "domain"), pluralize(registerYears, "year"))
+	if err := confirm(action, &spend{Amount: result.Total, Currency: result.Currency}); err != nil {
+		return err
+	}
+
+	registrar, err := newRegistrar(ctx, options)
+	if err != nil {
+		return err
+	}
+	// Record each registration as soon as Route 53 accepts it, so it counts
+	// against the budget even when a second interrupt exits before the other
+	// orders are submitted. One that failed without an answer may have been
+	// accepted, and is recorded as unconfirmed.
+	submitted := 0
+	result.Domains = register.Submit(ctx, registrar, orders, registerYears, func(outcome register.Outcome) {
+		switch {
+		case outcome.Ambiguous():
+			warnUnconfirmed(outcome)
+		case outcome.Failed():
+			return
+		default:
+			submitted++
+		}
+		if err := guard.Record(registrationPurchases([]register.Outcome{outcome}, time.Now())); err != nil {
+			fmt.Fprintf(stderr, "Warning: unable to record %s in the purchase ledger: %v\n", outcome.Domain, err)
+		}
+	})
+
+	if registerWait > 0 && submitted > 0 {
+		waitCtx, cancelWait := context.WithTimeout(ctx, registerWait)
+		err := register.Wait(waitCtx, registrar, result.Domains, registerPollInterval)
+		cancelWait()
+		if err != nil {
+			fmt.Fprintf(stderr, "Stopped waiting after %s; the registrations still pending continue\n", registerWait)
+		}
+	}
+	tagRegistrations(ctx, result.Domains)
+
+	for _, outcome := range result.Domains {
+		if outcome.Failed() {
+			result.Failed++
+		} else {
+			result.Submitted++
+		}
+	}
+	if outputFormat == output.FormatJSON {
+		printRegistrationJSON(result)
+	} else {
+		fmt.Fprint(stdout, formatRegistrations(result))
+	}
+
+	if result.Failed > 0 {
+		return customErrors.NewExitError(customErrors.ExitPartialFailure, nil)
+	}
+	return nil
+}
+
+// parseDomainTags parses --tags into domainTags, requiring the keys of
+// register.required_tags
+func parseDomainTags() error {
+	tags, err := register.ParseTags(domainTagValues, registerSettings.RequiredTags)
+	if err != nil {
+		return err
+	}
+	domainTags = tags
+	return nil
+}
+
+// tagRegistrations tags the domains that finished registering with --tags.
+// Route 53 only tags registered domains, so the tags of those still pending
+// are left in the purchase ledger for tags audit --apply.
+func tagRegistrations(ctx context.Context, outcomes []register.Outcome) {
+	if len(domainTags) == 0 {
+		return
+	}
+	registered, pending := false, false
+	for _, outcome := range outcomes {
+		registered = registered || outcome.Status == register.StatusSuccessful
+		pending = pending || outcome.Pending()
+	}
+	if registered {
+		tagger, err := newDomainTagger(ctx)
+		if err == nil {
+			err = register.ApplyTags(ctx, tagger, outcomes, domainTags)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Warning: unable to tag the registered domains, r53check tags audit --apply retries: %v\n", err)
+		}
+	}
+	if pending {
+		fmt.Fprintln(stderr, "The domains still registering are tagged by r53check tags audit --apply once they are registered")
+	}
+}
+
+// registerCandidates returns the domains to register: those in --from, or
+// those of them in names when both are given, or else names
+func registerCandidates(names []string) ([]string, error) {
+	var candidates []string
+	if registerFrom != "" {
+		var err error
+		if candidates, err = register.ReadCandidates(registerFrom, onlyAvailable); err != nil || len(names) == 0 {
+			return candidates, err
+		}
+	}
+
+	var selected []string
+	for _, name := range names {
+		if registerFrom != "" && !slices.Contains(candidates, name) {
+			return nil, customErrors.NewValidationError(name, "domain",
+				fmt.Sprintf("%s is not an available domain in %s", name, registerFrom), nil)
+		}
+		if !slices.Contains(selected, name) {
+			selected = append(selected, name)
+		}
+	}
+	return selected, nil
+}
+
+// priceOrders validates names and prices registering each for years from
+// the Route 53 price list. A domain without a listed price is refused, since
+// its cost could not be confirmed.
+func priceOrders(ctx context.Context, names []string, years int) ([]register.Order, error) {
+	setupCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	awsClient, err := newTimedRoute53Client(setupCtx)
+	if err != nil {
+		return nil, err
+	}
+	validator, _, err := newValidator(setupCtx, awsClient)
+	if err != nil {
+		return nil, err
+	}
+	checker, err := newChecker(validator, awsClient)
+	if err != nil {
+		return nil, err
+	}
+	setupPriceList(checker, awsClient)
+
+	orders := make([]register.Order, 0, len(names))
+	for _, name := range names {
+		if err := checker.ValidateDomain(name); err != nil {
+			return nil, err
+		}
+		tld := domain.EffectiveTLD(name)
+		pricing, err := checker.TLDPricing(ctx, tld)
+		if err != nil {
+			return nil, err
+		}
+		cost, ok := pricing.Cost(years)
+		if !ok {
+			return nil, customErrors.NewValidationError(name, "price",
+				fmt.Sprintf("Route 53 lists no price for registering .%s for %s", tld, pluralize(years, "year")), nil)
+		}
+		orders = append(orders, register.Order{Domain: name, Cost: cost, Currency: pricing.Currency})
+	}
+	return orders, nil
+}
+
+// registrationPurchases returns the purchases the registrations in outcomes
+// make, at at
+func registrationPurchases(outcomes []register.Outcome, at time.Time) []budget.Purchase {
+	purchases := make([]budget.Purchase, 0, len(outcomes))
+	for _, outcome := range outcomes {
+		purchases = append(purchases, budget.Purchase{
+			Domain:      outcome.Domain,
+			Kind:        "register",
+			Amount:      outcome.Cost,
+			Currency:    outcome.Currency,
+			At:          at,
+			OperationID: outcome.OperationID,
+			Tags:        domainTags,
+			Unconfirmed: outcome.Ambiguous(),
+		})
+	}
+	return purchases
+}
+
+// warnUnconfirmed warns that the registration in outcome failed without an
+// answer from Route 53, so the domain may have been registered all the same
+func warnUnconfirmed(outcome register.Outcome) {
+	fmt.Fprintf(stderr, "Warning: no answer from Route 53 for %s; it may have been registered, so its $%.2f is recorded as unconfirmed spend in the purchase ledger\n",
+		outcome.Domain, outcome.Cost)
+}
+
+// formatRegistrationCost renders the cost of registering each domain for
+// years and the total
+func formatRegistrationCost(orders []register.Order, years int, total float64) string {
+	width := len("Total")
+	for _, order := range orders {
+		width = max(width, len(order.Domain))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Registering %s for %s:\n", pluralize(len(orders), "domain"), pluralize(years, "year"))
+	for _, order := range orders {
+		fmt.Fprintf(&b, "  %-*s  $%8.2f\n", width, order.Domain, order.Cost)
+	}
+	fmt.Fprintf(&b, "  %-*s  $%8.2f %s\n", width, "Total", total, orders[0].Currency)
+	return b.String()
+}
+
+// formatRegistrations renders the outcome of each registration, with its
+// operation ID or why it failed, and a summary
+func formatRegistrations(result registrationJSON) string {
+	var b strings.Builder
+	for _, outcome := range result.Domains {
+		switch {
+		case outcome.Failed():
+			fmt.Fprintf(&b, "✗ %s: %s\n", outcome.Domain, registrationMessage(outcome))
+		case outcome.Status == register.StatusSuccessful:
+			tagged := ""
+			if outcome.Tagged {
+				tagged = ", tagged"
+			}
+			fmt.Fprintf(&b, "✓ %s registered (operation %s%s)\n", outcome.Domain, outcome.OperationID, tagged)
+		default:
+			fmt.Fprintf(&b, "… %s %s (operation %s)\n", outcome.Domain, strings.ToLower(strings.ReplaceAll(outcome.Status, "_", " ")), outcome.OperationID)
+		}
+	}
+	fmt.Fprintf(&b, "%d of %d registrations submitted", result.Submitted, len(result.Domains))
+	if result.Failed > 0 {
+		fmt.Fprintf(&b, ", %d failed", result.Failed)
+	}
+	fmt.Fprintln(&b)
+	return b.String()
+}
+
+// registrationMessage explains a failed registration
+func registrationMessage(outcome register.Outcome) string {
+	if outcome.Message == "" {
+		return strings.ToLower(outcome.Status)
+	}
+	return outcome.Message
+}
+
+func printRegistrationJSON(result registrationJSON) {
+	data, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Fprintln(stdout, string(data))
+}
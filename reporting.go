@@ -0,0 +1,70 @@
+package main
+
+import (
+	"runtime/debug"
+
+	"github.com/abakermi/r53check/internal/reporting"
+)
+
+var (
+	// errorReporting is the --error-reporting target, such as
+	// sentry://key@host/project
+	errorReporting string
+
+	// reporter reports unexpected errors and panics; nil when error
+	// reporting is off
+	reporter *reporting.Reporter
+
+	// newReporter creates the reporter for a target; tests replace it to
+	// capture events
+	newReporter = reporting.New
+
+	// version is the release of the binary, set at build time with
+	// -ldflags "-X main.version=v1.2.3"
+	version string
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&errorReporting, "error-reporting", "", "Report unexpected errors and panics to this target (sentry://key@host/project)")
+}
+
+// buildVersion returns the release of the binary: the version set at build
+// time, else the module version recorded by go install
+func buildVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "devel"
+}
+
+// setupErrorReporting creates the reporter for the --error-reporting target,
+// tagging its events with the command and region being run
+func setupErrorReporting(target, command string) error {
+	reporter = nil
+	if target == "" {
+		return nil
+	}
+
+	r, err := newReporter(target, buildVersion())
+	if err != nil {
+		return err
+	}
+	r.SetTag("command", command)
+	if region != "" {
+		r.SetTag("region", region)
+	}
+	reporter = r
+	return nil
+}
+
+// reportPanic reports a panic of the command before letting it crash the
+// process; it must be deferred directly
+func reportPanic() {
+	if value := recover(); value != nil {
+		reporter.ReportPanic(value)
+		panic(value)
+	}
+}
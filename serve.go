@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/metrics"
+	"github.com/abakermi/r53check/internal/server"
+	"github.com/abakermi/r53check/internal/storage"
+
+	"github.com/spf13/cobra"
+)
+
+// shutdownTimeout bounds how long in-flight requests may take to finish on shutdown
+const shutdownTimeout = 30 * time.Second
+
+var (
+	// Serve command flags
+	serveAddr      string
+	maxBulkDomains int
+	apiKeysFile    string
+	rateLimit      int
+	rateBurst      int
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an HTTP API for domain checks",
+	Long: `Run an HTTP server exposing domain checks as a REST API.
+
+The API is described by an OpenAPI 3 document served at /openapi.json:
+
+  GET  /v1/domains/{domain}   Check one domain (?price=true adds pricing)
+  POST /v1/bulk               Check several domains: {"domains": [...], "price": false}
+                              (Accept: text/event-stream streams results as they complete)
+  GET  /metrics               Prometheus metrics
+  GET  /healthz               Liveness
+  GET  /readyz                Readiness: AWS credentials, Route 53 Domains, cache
+
+Errors are returned as {"error": {"category": ..., "message": ...}} with a
+status code matching the error category.
+
+With --api-keys-file, the /v1 routes require a key from the file, sent as
+"Authorization: Bearer <key>" or "X-API-Key: <key>". The file holds one
+"name:key" entry per line. --rate-limit caps the domain checks each key (or
+client IP, without keys) may run per minute; a bulk request counts once per
+domain, and one with more domains than --rate-burst is rejected.`,
+	Example: `  # Serve on the default address
+  r53check serve
+
+  # Serve on a specific address with a smaller bulk limit
+  r53check serve --addr 127.0.0.1:9000 --max-bulk-domains 25
+
+  # Require API keys and allow each key 120 checks per minute
+  r53check serve --api-keys-file /etc/r53check/keys --rate-limit 120`,
+	Args: cobra.NoArgs,
+	RunE: runServeCommand,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", ":8080", "Address to listen on")
+	serveCmd.Flags().IntVar(&maxBulkDomains, "max-bulk-domains", server.DefaultMaxBulkDomains, "Maximum number of domains per bulk request")
+	serveCmd.Flags().IntVar(&concurrency, "concurrency", 5, "Maximum number of concurrent requests per bulk check")
+	serveCmd.Flags().StringVar(&apiKeysFile, "api-keys-file", "", "File of name:key API keys required by the /v1 routes")
+	serveCmd.Flags().IntVar(&rateLimit, "rate-limit", 0, "Domain checks allowed per minute for each API key or client (0 for no limit)")
+	serveCmd.Flags().IntVar(&rateBurst, "rate-burst", 0, "Domain checks allowed in a burst (defaults to --rate-limit)")
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServeCommand(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var apiKeys []server.APIKey
+	if apiKeysFile != "" {
+		keys, err := server.LoadAPIKeys(apiKeysFile)
+		if err != nil {
+			return err
+		}
+		apiKeys = keys
+	}
+
+	awsClient, err := newRoute53Client(ctx)
+	if err != nil {
+		return err
+	}
+	validator, origin, err := newValidator(ctx, awsClient)
+	if err != nil {
+		return err
+	}
+	checker, err := newChecker(validator, awsClient)
+	if err != nil {
+		return err
+	}
+
+	// Every check the API performs is recorded for /metrics
+	recorder := metrics.New()
+	recorder.RecordCacheLookup("tld-catalog", origin == domain.OriginCache)
+	checker.AddHooks(recorder)
+
+	api := server.NewServer(checker)
+	api.SetMaxBulkDomains(maxBulkDomains)
+	api.SetAPIKeys(apiKeys)
+	api.SetRateLimit(rateLimit, rateBurst)
+	api.Handle("GET /metrics", recorder.Handler())
+	addReadinessChecks(api, awsClient)
+
+	listener, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return customErrors.NewSystemError("server", "unable to listen on "+serveAddr, err)
+	}
+
+	httpServer := &http.Server{
+		Handler:           reporter.Middleware(api.Handler()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.Serve(listener)
+	}()
+	fmt.Fprintf(stderr, "Serving r53check API on %s\n", listener.Addr())
+
+	select {
+	case err := <-serveErr:
+		return customErrors.NewSystemError("server", "server stopped unexpectedly", err)
+	case <-ctx.Done():
+	}
+
+	if verbose {
+		fmt.Fprintln(stderr, "Shutting down, waiting for in-flight requests...")
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return customErrors.NewSystemError("server", "shutdown did not complete", err)
+	}
+	return nil
+}
+
+// addReadinessChecks registers the dependencies /readyz verifies: AWS
+// credentials and Route 53 Domains when the client supports checking them,
+// and a writable cache directory
+func addReadinessChecks(api *server.Server, client route53Client) {
+	if c, ok := client.(interface{ CheckCredentials(context.Context) error }); ok {
+		api.AddReadinessCheck("aws-credentials", c.CheckCredentials)
+	}
+	if c, ok := client.(interface{ Ping(context.Context) error }); ok {
+		api.AddReadinessCheck("route53domains", c.Ping)
+	}
+	api.AddReadinessCheck("cache", func(ctx context.Context) error {
+		return storage.DefaultDirs().CheckCacheWritable()
+	})
+}
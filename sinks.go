@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/abakermi/r53check/internal/domain"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/sink"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/spf13/cobra"
+)
+
+// Output sink flags, shared by the bulk and daemon commands
+var sqsQueueURL string
+
+// addSinkFlags registers the output sink flags on cmd
+func addSinkFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&sqsQueueURL, "sqs-queue-url", "", "Publish each result as a JSON message to this SQS queue as it completes")
+}
+
+// newSQSAPI creates the SQS client used by the sink.
+// Tests replace it to capture published results.
+var newSQSAPI = func(ctx context.Context) (sink.SQSAPI, error) {
+	awsConfig, err := loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sqs.NewFromConfig(*awsConfig), nil
+}
+
+// newSQSSink attaches an SQS sink to checker when --sqs-queue-url is set,
+// and returns nil otherwise. The caller must close the sink.
+func newSQSSink(ctx context.Context, checker *domain.DomainChecker) (*sink.SQSSink, error) {
+	if sqsQueueURL == "" {
+		return nil, nil
+	}
+	if u, err := url.Parse(sqsQueueURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, customErrors.NewValidationError("", "sqs-queue-url", "the SQS queue URL must be an http or https URL", err)
+	}
+
+	api, err := newSQSAPI(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s := sink.NewSQSSink(api, sqsQueueURL)
+	s.SetTags(runTags)
+	checker.AddHooks(s)
+	return s, nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abakermi/r53check/internal/aws"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/monitor"
+	"github.com/abakermi/r53check/internal/register"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// registerOnAvailable is --register-on-available: register the watched
+	// domain as soon as it is available
+	registerOnAvailable bool
+
+	// registerMaxPrice is --register-max-price: the most the registration
+	// may cost
+	registerMaxPrice float64
+
+	// confirmRegister is --confirm-register: the watched domain, typed out
+	// again to confirm that it is meant to be registered unattended
+	confirmRegister string
+)
+
+// sniper registers a watched domain the first time a run finds it
+// available. It submits one registration at most, then stops the watch.
+type sniper struct {
+	domain   string
+	years    int
+	maxPrice float64
+	options  aws.RegistrationOptions
+
+	// stop ends the watch once the registration is submitted, or cannot be
+	// made within the limits
+	stop context.CancelFunc
+
+	// err is why the watch was stopped without registering
+	err error
+}
+
+// newSniper checks the --register-on-available safety flags for a watch of
+// list, and returns the sniper registering its domain. The watch must be of
+// a single domain, named again by --confirm-register, whose current price is
+// within --register-max-price.
+func newSniper(ctx context.Context, cmd *cobra.Command, list func() ([]string, error), stop context.CancelFunc) (*sniper, error) {
+	if mockMode {
+		return nil, customErrors.NewValidationError("", "mock", "--register-on-available buys domains from Route 53 Domains, which --mock cannot", nil)
+	}
+	if registerMaxPrice <= 0 {
+		return nil, customErrors.NewValidationError("", "register-max-price", "--register-on-available needs --register-max-price, the most the registration may cost", nil)
+	}
+	domains, err := list()
+	if err != nil {
+		return nil, err
+	}
+	if len(domains) != 1 {
+		return nil, customErrors.NewValidationError("", "register-on-available",
+			fmt.Sprintf("--register-on-available watches a single domain, got %d", len(domains)), nil)
+	}
+	if normalizeDomains([]string{confirmRegister})[0] != domains[0] {
+		return nil, customErrors.NewValidationError(domains[0], "confirm-register",
+			fmt.Sprintf("--register-on-available registers %s unattended; confirm it with --confirm-register %s", domains[0], domains[0]), nil)
+	}
+	if err := parseDomainTags(); err != nil {
+		return nil, err
+	}
+	options, err := registrationOptions(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &sniper{domain: domains[0], years: max(years, 1), maxPrice: registerMaxPrice, options: options, stop: stop}
+	if _, err := s.order(ctx); err != nil {
+		return nil, err
+	}
+	if verbose {
+		fmt.Fprintf(stderr, "Registering %s for %s once it is available\n", s.domain, pluralize(s.years, "year"))
+	}
+	return s, nil
+}
+
+// order prices the registration, refusing it when it costs more than the
+// price cap
+func (s *sniper) order(ctx context.Context) (register.Order, error) {
+	orders, err := priceOrders(ctx, []string{s.domain}, s.years)
+	if err != nil {
+		return register.Order{}, err
+	}
+	if orders[0].Cost > s.maxPrice {
+		return register.Order{}, customErrors.NewValidationError(s.domain, "register-max-price",
+			fmt.Sprintf("registering %s for %s costs $%.2f, over --register-max-price $%.2f",
+				s.domain, pluralize(s.years, "year"), orders[0].Cost, s.maxPrice), nil)
+	}
+	return orders[0], nil
+}
+
+// afterRun registers the domain when the run found it available. A
+// registration refused for a reason that may pass, such as throttling or a
+// server error, is reported and the watch goes on to try again; one that is
+// submitted, refused outright, or would go over the price cap or budget,
+// stops the watch. So does one that got no answer, which may have been
+// accepted; it is recorded as unconfirmed spend. An interrupt does not
+// abandon a registration in flight: it is finished, reported and recorded
+// before the watch stops.
+func (s *sniper) afterRun(ctx context.Context, report *monitor.Report) error {
+	available := false
+	for _, result := range report.Results {
+		if result.Domain == s.domain && result.Error == nil && result.Available {
+			available = true
+		}
+	}
+	if !available || ctx.Err() != nil {
+		return nil
+	}
+
+	order, err := s.order(ctx)
+	if err != nil {
+		return s.abort(err)
+	}
+	outcome := register.Outcome{Order: order}
+	guard, err := checkBudget(registrationPurchases([]register.Outcome{outcome}, time.Now()))
+	if err != nil {
+		return s.abort(err)
+	}
+	registrar, err := newRegistrar(ctx, s.options)
+	if err != nil {
+		return err
+	}
+
+	// Submit finishes a registration in flight when the watch is
+	// interrupted, and the outcome is recorded before the watch stops
+	outcome = register.Submit(ctx, registrar, []register.Order{order}, s.years, func(outcome register.Outcome) {
+		if outcome.Failed() && !outcome.Ambiguous() {
+			return
+		}
+		if err := guard.Record(registrationPurchases([]register.Outcome{outcome}, time.Now())); err != nil {
+			fmt.Fprintf(stderr, "Warning: unable to record the registration in the purchase ledger: %v\n", err)
+		}
+	})[0]
+	switch {
+	case outcome.Ambiguous():
+		// Submitting again could register the domain twice over
+		fmt.Fprintf(stdout, "? %s: no answer to the registration: %s\n", s.domain, registrationMessage(outcome))
+		warnUnconfirmed(outcome)
+		return s.abort(customErrors.NewSystemError("register",
+			fmt.Sprintf("%s may have been registered; check the account before watching it again", s.domain), nil))
+	case outcome.Failed() && outcome.Err == nil:
+		// The watch was interrupted before the order was submitted
+		return nil
+	case outcome.Failed():
+		fmt.Fprintf(stdout, "✗ %s is available but could not be registered: %s\n", s.domain, registrationMessage(outcome))
+		if outcome.Transient() {
+			return nil
+		}
+		// Route 53 would refuse the same order on every run
+		return s.abort(outcome.Err)
+	}
+	fmt.Fprintf(stdout, "✓ %s: registration submitted for $%.2f %s (operation %s)\n", s.domain, order.Cost, order.Currency, outcome.OperationID)
+	tagRegistrations(ctx, []register.Outcome{outcome})
+	s.stop()
+	return nil
+}
+
+// abort stops the watch because the domain cannot be registered within the
+// limits
+func (s *sniper) abort(err error) error {
+	s.err = err
+	s.stop()
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// spinnerDelay is how long a check runs before the spinner appears, so
+	// fast checks do not flicker
+	spinnerDelay = 300 * time.Millisecond
+
+	// spinnerInterval is the time between frames of the spinner
+	spinnerInterval = 100 * time.Millisecond
+)
+
+// spinnerFrames are drawn in turn in front of the status line
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// isTerminal reports whether w writes to a terminal; tests replace it
+var isTerminal = func(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// showSpinner draws a spinner with label and the elapsed time on stderr
+// until the returned function is called, which clears it. The spinner is
+// only shown on a terminal, and not with --verbose, whose messages would
+// break up the line.
+func showSpinner(label string) (stop func()) {
+	if verbose || os.Getenv("TERM") == "dumb" || !isTerminal(stderr) {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		runSpinner(stderr, label, done)
+	}()
+
+	return sync.OnceFunc(func() {
+		close(done)
+		<-finished
+	})
+}
+
+// runSpinner animates the status line on w after spinnerDelay, until done
+// is closed
+func runSpinner(w io.Writer, label string, done <-chan struct{}) {
+	start := time.Now()
+	delay := time.NewTimer(spinnerDelay)
+	defer delay.Stop()
+	select {
+	case <-done:
+		return
+	case <-delay.C:
+	}
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+	for frame := 0; ; frame++ {
+		fmt.Fprintf(w, "\r%s %s %.1fs", spinnerFrames[frame%len(spinnerFrames)], label, time.Since(start).Seconds())
+		select {
+		case <-done:
+			// Clear the line for the output that follows
+			fmt.Fprint(w, "\r\033[K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
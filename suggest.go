@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/config"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/generate"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/spf13/cobra"
+)
+
+// maxSuggestions bounds the names a suggest run checks, since every
+// keyword multiplies them
+const maxSuggestions = 500
+
+var (
+	// Suggest command flags
+	suggestSynonyms  []string
+	suggestTLDs      []string
+	suggestThesaurus string
+	suggestAI        string
+	suggestCount     int
+
+	// aiSettings holds the Bedrock model from the config file
+	aiSettings config.AISettings
+)
+
+// newBedrockAPI creates the Bedrock client used for --ai suggestions, in
+// region when it is set. Tests replace it to script the model's replies.
+var newBedrockAPI = func(ctx context.Context, region string) (generate.BedrockAPI, error) {
+	awsConfig, err := loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cfg := *awsConfig
+	if region != "" {
+		cfg.Region = region
+	}
+	return bedrockruntime.NewFromConfig(cfg), nil
+}
+
+// suggestCmd represents the suggest command
+var suggestCmd = &cobra.Command{
+	Use:   "suggest [words...] --synonyms <keyword>... | --ai <description>",
+	Short: "Check names made from synonyms of keywords, or suggested by AI",
+	Long: `Suggest names by expanding each --synonyms keyword into its synonyms and
+checking every combination, such as quickcar.com and swiftcar.com for
+--synonyms fast car. It works offline from a built-in thesaurus, as a local
+alternative to Route 53's domain suggestions.
+
+Names are the synonyms of the keywords, in the order given, followed by the
+words given as arguments, which are used as they are. Only the available names
+are listed, after the summary.
+
+--thesaurus reads synonyms from your own file instead, with one group of
+synonyms per line, separated by commas.
+
+--ai asks a model on Amazon Bedrock for --count names matching a description
+instead. It is off until the ai.model setting of the config file names the
+model to call, since each run is billed to your AWS account.`,
+	Example: `  # Check synonyms of "fast" under .com
+  r53check suggest --synonyms fast
+
+  # Combine synonyms of two keywords with a fixed word, under .com and .io
+  r53check suggest --synonyms fast,smart lab --tld com,io
+
+  # Print the combinations without checking them
+  r53check suggest --synonyms fast --list
+
+  # Ask Bedrock for names, with ai.model set in the config file
+  r53check suggest --ai "a CI/CD analytics startup" --tld com,dev`,
+	RunE: runSuggestCommand,
+}
+
+func init() {
+	suggestCmd.Flags().StringSliceVar(&suggestSynonyms, "synonyms", nil, "Keywords to expand into their synonyms")
+	suggestCmd.Flags().StringVar(&suggestAI, "ai", "", "Ask Amazon Bedrock for names matching this description (needs ai.model in the config file)")
+	suggestCmd.Flags().IntVar(&suggestCount, "count", 20, "Number of names to ask for with --ai")
+	suggestCmd.Flags().StringSliceVar(&suggestTLDs, "tld", nil, "TLDs to check each combination under (default com)")
+	suggestCmd.Flags().StringVar(&suggestThesaurus, "thesaurus", "", "Read synonyms from this file instead of the built-in thesaurus")
+	suggestCmd.Flags().BoolVar(&generateAll, "all", false, "Print the result of every combination, not only the available ones")
+	suggestCmd.Flags().BoolVar(&generateList, "list", false, "Print the combinations, one per line, without checking them")
+	addMaxPriceFlag(suggestCmd)
+	addConstraintFlags(suggestCmd)
+	suggestCmd.Flags().IntVar(&concurrency, "concurrency", 5, "Maximum number of concurrent requests")
+	addHistoryFlags(suggestCmd)
+
+	rootCmd.AddCommand(suggestCmd)
+}
+
+func runSuggestCommand(cmd *cobra.Command, args []string) error {
+	var groups [][]string
+	var err error
+	switch {
+	case suggestAI != "" && len(suggestSynonyms) > 0:
+		return customErrors.NewValidationError("", "ai", "use --synonyms or --ai, not both", nil)
+	case suggestAI != "":
+		if len(args) > 0 {
+			return customErrors.NewValidationError("", "ai", "--ai suggests whole names; leave out the words", nil)
+		}
+		groups, err = aiGroups(context.Background())
+	case len(suggestSynonyms) > 0:
+		groups, err = synonymGroups(args)
+	default:
+		return customErrors.NewValidationError("", "synonyms", "no keywords given; use --synonyms or --ai", nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	tlds := suggestTLDs
+	if len(tlds) == 0 {
+		tlds = []string{generate.DefaultTLD}
+	}
+	names := generate.Combine(groups, nil, tlds)
+	if len(names) == 0 {
+		return customErrors.NewValidationError("", "synonyms", "no valid names could be made from the keywords", nil)
+	}
+	if len(names) > maxSuggestions {
+		return customErrors.NewValidationError("", "synonyms",
+			fmt.Sprintf("the keywords make %d names, more than the limit of %d; use fewer keywords or TLDs", len(names), maxSuggestions), nil)
+	}
+
+	if verbose {
+		fmt.Fprintf(stderr, "Checking %s\n", pluralize(len(names), "suggested name"))
+	}
+	return checkGeneratedNames(domainEntries(names))
+}
+
+// synonymGroups returns the synonyms of each --synonyms keyword, followed
+// by each word as it is
+func synonymGroups(words []string) ([][]string, error) {
+	thesaurus, err := loadThesaurus()
+	if err != nil {
+		return nil, err
+	}
+
+	var groups [][]string
+	for _, keyword := range suggestSynonyms {
+		if synonyms := thesaurus.Synonyms(keyword); len(synonyms) > 0 {
+			groups = append(groups, synonyms)
+		}
+	}
+	for _, word := range words {
+		groups = append(groups, []string{strings.ToLower(word)})
+	}
+	return groups, nil
+}
+
+// aiGroups asks the configured Bedrock model for names matching --ai
+func aiGroups(ctx context.Context) ([][]string, error) {
+	if aiSettings.Model == "" {
+		return nil, customErrors.NewValidationError("", "ai",
+			"AI suggestions are off; set ai.model in the config file to a Bedrock model ID", nil)
+	}
+	if suggestCount <= 0 {
+		return nil, customErrors.NewValidationError("", "count", "--count must be positive", nil)
+	}
+
+	api, err := newBedrockAPI(ctx, aiSettings.Region)
+	if err != nil {
+		return nil, err
+	}
+	if verbose {
+		fmt.Fprintf(stderr, "Asking %s for %d names...\n", aiSettings.Model, suggestCount)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	names, err := generate.NewBedrockSuggester(api, aiSettings.Model).Suggest(ctx, suggestAI, suggestCount)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, customErrors.NewSystemError("bedrock", "the model suggested no usable names", nil)
+	}
+	return [][]string{names}, nil
+}
+
+// loadThesaurus reads the --thesaurus file, or returns the built-in thesaurus
+func loadThesaurus() (*generate.Thesaurus, error) {
+	if suggestThesaurus == "" {
+		return generate.DefaultThesaurus(), nil
+	}
+
+	file, err := os.Open(suggestThesaurus)
+	if err != nil {
+		return nil, customErrors.NewValidationError("", "thesaurus", "unable to read thesaurus file", err)
+	}
+	defer file.Close()
+
+	thesaurus, err := generate.LoadThesaurus(file)
+	if err != nil {
+		return nil, customErrors.NewValidationError("", "thesaurus", "unable to read thesaurus file", err)
+	}
+	return thesaurus, nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abakermi/r53check/internal/aws"
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/output"
+	"github.com/abakermi/r53check/internal/register"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// requiredTags is --require: the tag keys every domain must carry
+	requiredTags []string
+
+	// applyTags is --apply: add the tags domains were registered with that
+	// they do not carry
+	applyTags bool
+
+	// failOnMissing is --fail-on-missing: exit with code 6 when a domain
+	// lacks a required or pending tag
+	failOnMissing bool
+)
+
+// newDomainTagger creates what registered domains are tagged and audited
+// with; tests replace it
+var newDomainTagger = func(ctx context.Context) (register.Tagger, error) {
+	awsConfig, err := loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return aws.NewDomainTags(awsConfig), nil
+}
+
+// tagsCmd groups the commands about the tags of registered domains
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Audit the tags of the domains registered to the account",
+	Long: `Audit the tags of the domains registered to the account, such as the team
+and environment that own them.
+
+register, and watch with --register-on-available, tag the domains they
+register with --tags once the registrations finish, and register.required_tags
+in the config file lists the keys --tags must give.`,
+}
+
+var tagsAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Find registered domains missing required tags or the tags they were registered with",
+	Long: `Read the tags of every domain registered to the account with Route 53
+Domains and report those missing a required key, from --require or
+register.required_tags, or a tag given with --tags when they were registered.
+
+Route 53 only tags a domain once it is registered, so domains registered
+without --wait, or still registering when it ran out, are tagged later:
+--apply adds the tags from the purchase ledger that a domain does not carry.
+A key given another value since is left alone, and required keys without a
+recorded value can only be added in the Route 53 console or API.
+
+--fail-on-missing exits with code 6 when a domain still lacks a tag, for
+scheduled audits.`,
+	Example: `  # Register with ownership tags, then tag the domains once registered
+  r53check register --from results.json --only-available --tags team=platform,env=prod
+  r53check tags audit --apply
+
+  # Fail a scheduled job when a domain has no owner
+  r53check tags audit --require team --fail-on-missing --output json`,
+	Args: cobra.NoArgs,
+	RunE: runTagsAuditCommand,
+}
+
+func init() {
+	tagsAuditCmd.Flags().StringSliceVar(&requiredTags, "require", nil, "Tag keys every domain must carry (default register.required_tags)")
+	tagsAuditCmd.Flags().BoolVar(&applyTags, "apply", false, "Add the tags domains were registered with that they do not carry")
+	tagsAuditCmd.Flags().BoolVar(&failOnMissing, "fail-on-missing", false, "Exit with code 6 when a domain lacks a required or pending tag")
+
+	tagsCmd.AddCommand(tagsAuditCmd)
+	rootCmd.AddCommand(tagsCmd)
+}
+
+func runTagsAuditCommand(cmd *cobra.Command, args []string) error {
+	if mockMode {
+		return customErrors.NewValidationError("", "mock", "tags audit reads the domains registered to an AWS account, which --mock has none of", nil)
+	}
+	required := requiredTags
+	if !cmd.Flags().Changed("require") {
+		required = registerSettings.RequiredTags
+	}
+
+	registered, err := registrationTags()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	tagger, err := newDomainTagger(ctx)
+	if err != nil {
+		return err
+	}
+	findings, err := register.AuditTags(ctx, tagger, required, registered)
+	if err != nil {
+		return err
+	}
+	if applyTags {
+		if err := register.ApplyPending(ctx, tagger, findings, required); err != nil {
+			fmt.Fprintf(stderr, "Warning: unable to tag some domains: %v\n", err)
+		}
+	}
+
+	failing := 0
+	for _, finding := range findings {
+		if !finding.OK() {
+			failing++
+		}
+	}
+
+	if outputFormat == output.FormatJSON {
+		data, _ := json.MarshalIndent(map[string]interface{}{"required": required, "findings": findings, "failing": failing}, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+	} else {
+		fmt.Fprint(stdout, formatTagAudit(findings, failing))
+	}
+
+	if failOnMissing && failing > 0 {
+		if outputFormat != output.FormatJSON {
+			fmt.Fprintf(stderr, "Audit failed: %s missing tags\n", pluralize(failing, "domain"))
+		}
+		return customErrors.NewExitError(customErrors.ExitPartialFailure, nil)
+	}
+	return nil
+}
+
+// registrationTags returns the tags each domain was last registered with,
+// from the purchase ledger
+func registrationTags() (map[string]map[string]string, error) {
+	purchases, err := purchaseLedger().Purchases()
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]map[string]string)
+	for _, purchase := range purchases {
+		if purchase.Kind == "register" && len(purchase.Tags) > 0 {
+			tags[purchase.Domain] = purchase.Tags
+		}
+	}
+	return tags, nil
+}
+
+// formatTagAudit renders findings, one domain per line with its tags and
+// what it lacks, followed by a summary
+func formatTagAudit(findings []register.TagFinding, failing int) string {
+	if len(findings) == 0 {
+		return "No domains are registered to this account\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Tag audit (%s):\n", pluralize(len(findings), "domain"))
+	for _, finding := range findings {
+		switch {
+		case finding.Error != "":
+			fmt.Fprintf(&b, "  ? %s: %s\n", finding.Domain, finding.Error)
+			continue
+		case finding.OK():
+			fmt.Fprintf(&b, "  ✓ %s  %s\n", finding.Domain, formatTagPairs(finding.Tags))
+			continue
+		}
+		fmt.Fprintf(&b, "  ✗ %s  %s\n", finding.Domain, formatTagPairs(finding.Tags))
+		if len(finding.Missing) > 0 {
+			fmt.Fprintf(&b, "    missing %s\n", strings.Join(finding.Missing, ", "))
+		}
+		if len(finding.Pending) > 0 {
+			fmt.Fprintf(&b, "    not yet tagged %s (run with --apply)\n", formatTagPairs(finding.Pending))
+		}
+	}
+	fmt.Fprintf(&b, "Summary: %d OK, %d missing tags\n", len(findings)-failing, failing)
+	return b.String()
+}
+
+// formatTagPairs renders tags as sorted key=value pairs
+func formatTagPairs(tags map[string]string) string {
+	if len(tags) == 0 {
+		return "(no tags)"
+	}
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/abakermi/r53check/internal/domain"
+)
+
+var (
+	// showTimings prints how long each phase of the run took to stderr
+	showTimings bool
+
+	// setupTimings are the phases before checking, in the order they ran
+	setupTimings []phaseTiming
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&showTimings, "timings", false, "Print how long each phase took, with percentiles per check for bulk runs")
+}
+
+// phaseTiming is the duration of one named phase of the run
+type phaseTiming struct {
+	name     string
+	duration time.Duration
+}
+
+// timePhase records the time since start as a setup phase
+func timePhase(name string, start time.Time) {
+	setupTimings = append(setupTimings, phaseTiming{name, time.Since(start)})
+}
+
+// newTimedRoute53Client creates the Route 53 client as a timed phase. With
+// --timings the AWS credentials are also retrieved up front, so resolving
+// them is not counted as part of the first API call; failures are left for
+// the check to report.
+func newTimedRoute53Client(ctx context.Context) (route53Client, error) {
+	start := time.Now()
+	defer timePhase("credentials", start)
+
+	client, err := newRoute53Client(ctx)
+	if err != nil || !showTimings {
+		return client, err
+	}
+	if credentials, ok := client.(interface{ CheckCredentials(context.Context) error }); ok {
+		credentials.CheckCredentials(ctx)
+	}
+	return client, nil
+}
+
+// printCheckTimings prints the setup phases, the phases of the check and the
+// formatting of its result
+func printCheckTimings(result *domain.AvailabilityResult, formatting time.Duration) {
+	if !showTimings {
+		return
+	}
+
+	phases := append(slices.Clone(setupTimings),
+		phaseTiming{"validation", result.Timings.Validation},
+		phaseTiming{"API call", result.Timings.API},
+	)
+	if price {
+		phases = append(phases, phaseTiming{"pricing", result.Timings.Pricing})
+	}
+	phases = append(phases, phaseTiming{"formatting", formatting})
+
+	fmt.Fprintln(stderr, "Timings:")
+	var total time.Duration
+	for _, phase := range phases {
+		fmt.Fprintf(stderr, "  %-12s %10s\n", phase.name, roundDuration(phase.duration))
+		total += phase.duration
+	}
+	fmt.Fprintf(stderr, "  %-12s %10s\n", "total", roundDuration(total))
+}
+
+// printBulkTimings prints the setup phases, the time spent checking and
+// formatting, and percentiles of each check phase across the checked
+// results. Retries show whether slow runs were throttled.
+func printBulkTimings(results []*domain.AvailabilityResult, checking, formatting time.Duration, retries *retryCounter) {
+	if !showTimings {
+		return
+	}
+
+	var validation, prescreen, api, pricing []time.Duration
+	for _, result := range results {
+		if result == nil || result.FromHistory || result.Skipped {
+			continue
+		}
+		validation = append(validation, result.Timings.Validation)
+		if prescreenFlag != "" {
+			prescreen = append(prescreen, result.Timings.Prescreen)
+		}
+		if result.Prescreened {
+			continue
+		}
+		api = append(api, result.Timings.API)
+		if price && result.Available {
+			pricing = append(pricing, result.Timings.Pricing)
+		}
+	}
+
+	fmt.Fprintln(stderr, "Timings:")
+	phases := append(slices.Clone(setupTimings),
+		phaseTiming{"checks", checking},
+		phaseTiming{"formatting", formatting},
+	)
+	for _, phase := range phases {
+		fmt.Fprintf(stderr, "  %-12s %10s\n", phase.name, roundDuration(phase.duration))
+	}
+	if len(validation) == 0 {
+		return
+	}
+
+	fmt.Fprintf(stderr, "Per check (%s):\n", pluralize(len(validation), "check"))
+	fmt.Fprintf(stderr, "  %-12s %10s %10s %10s %10s\n", "", "p50", "p90", "p99", "max")
+	for _, phase := range []struct {
+		name      string
+		durations []time.Duration
+	}{{"validation", validation}, {"prescreen", prescreen}, {"API call", api}, {"pricing", pricing}} {
+		if len(phase.durations) == 0 {
+			continue
+		}
+		slices.Sort(phase.durations)
+		fmt.Fprintf(stderr, "  %-12s %10s %10s %10s %10s\n", phase.name,
+			roundDuration(percentile(phase.durations, 50)),
+			roundDuration(percentile(phase.durations, 90)),
+			roundDuration(percentile(phase.durations, 99)),
+			roundDuration(phase.durations[len(phase.durations)-1]))
+	}
+	if retries.total() > 0 {
+		fmt.Fprintf(stderr, "Retries: %s\n", retries)
+	}
+}
+
+// percentile returns the nearest-rank percentile p of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// roundDuration rounds d for display, to 10µs below a second and to the
+// millisecond above
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Second {
+		return d.Round(10 * time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
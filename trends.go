@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/history"
+	"github.com/abakermi/r53check/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+// trendsCmd represents the trends command
+var trendsCmd = &cobra.Command{
+	Use:   "trends [domain...]",
+	Short: "Show how the status of domains changed over time",
+	Long: `Show the status timeline of domains from the check history: each period a
+domain kept the same status, and so when it flipped between registered and
+available.
+
+A domain changed status at some point between the last check of one period and
+the first check of the next. Failed checks are counted but do not end a period.
+Nothing is checked with Route 53; domains only have a timeline once check, bulk
+or daemon recorded them. With --tag, only checks from runs with every given tag
+are included.`,
+	Example: `  # Show when a domain was registered or available
+  r53check trends example.com
+
+  # Show the timelines of a watch list
+  r53check trends --file domains.txt
+
+  # Export the timelines as JSON
+  r53check trends --file domains.txt --output json
+
+  # Only include checks made for one engagement
+  r53check trends --file domains.txt --tag client=acme`,
+	RunE: runTrendsCommand,
+}
+
+func init() {
+	trendsCmd.Flags().StringArrayVarP(&domainsFiles, "file", "f", nil, "Read domains from file (one domain per line, or a CSV or Excel file); repeatable and accepts glob patterns")
+	trendsCmd.Flags().StringVar(&domainsColumn, "column", "", "Column of a CSV or Excel file holding the domains, by header name or number")
+	trendsCmd.Flags().StringArrayVar(&tagValues, "tag", nil, "Only include checks from runs with this key=value tag (repeatable)")
+	trendsCmd.Flags().StringVar(&historyFile, "history", "", "History database (default $XDG_DATA_HOME/r53check/history.db)")
+
+	rootCmd.AddCommand(trendsCmd)
+}
+
+func runTrendsCommand(cmd *cobra.Command, args []string) error {
+	if domainsColumn != "" && len(domainsFiles) == 0 {
+		return customErrors.NewValidationError("", "column", "--column picks a column of the --file file", nil)
+	}
+
+	domains := args
+	if len(domainsFiles) > 0 {
+		fileDomains, err := readDomainFiles(domainsFiles)
+		if err != nil {
+			return customErrors.NewValidationError("", "file", "unable to read domains file", err)
+		}
+		domains = fileDomains
+	}
+	if len(domains) == 0 {
+		return customErrors.NewValidationError("", "domains", "no domains provided; use arguments or --file", nil)
+	}
+	if err := parseRunTags(); err != nil {
+		return err
+	}
+
+	store, err := openHistory(context.Background())
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	trends := make([]history.Trend, 0, len(domains))
+	for _, name := range normalizeDomains(domains) {
+		name = historyKey(name)
+		records, err := store.Records(name)
+		if err != nil {
+			return err
+		}
+		if len(runTags) > 0 {
+			tagged := records[:0]
+			for _, record := range records {
+				if record.HasTags(runTags) {
+					tagged = append(tagged, record)
+				}
+			}
+			records = tagged
+		}
+		trends = append(trends, history.NewTrend(name, records))
+	}
+
+	if outputFormat == output.FormatJSON {
+		data, _ := json.MarshalIndent(map[string][]history.Trend{"trends": trends}, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	for i, trend := range trends {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprint(stdout, formatTrend(trend))
+	}
+	return nil
+}
+
+// formatTrend renders a domain's timeline, one period per line
+func formatTrend(trend history.Trend) string {
+	var b strings.Builder
+	if trend.Checks == 0 {
+		fmt.Fprintf(&b, "%s: no checks recorded\n", trend.Domain)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%s: %s in %s", trend.Domain, pluralize(trend.Changes, "status change"), pluralize(trend.Checks, "check"))
+	if trend.Failed > 0 {
+		fmt.Fprintf(&b, " (%d failed)", trend.Failed)
+	}
+	b.WriteString("\n")
+
+	const layout = "2006-01-02 15:04"
+	for _, period := range trend.Periods {
+		span := period.From.Local().Format(layout)
+		if period.Checks > 1 {
+			span += " → " + period.To.Local().Format(layout)
+		}
+		fmt.Fprintf(&b, "  %-11s  %-35s  %s\n", period.Status, span, pluralize(period.Checks, "check"))
+	}
+	return b.String()
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/output"
+	"github.com/abakermi/r53check/internal/update"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Update command flags
+	updateCheckOnly       bool
+	updateForce           bool
+	updateDownloadTimeout time.Duration
+)
+
+// updatePublicKey is the minisign public key release checksums are signed
+// with, set at build time with -ldflags "-X main.updatePublicKey=RWQ..."
+var updatePublicKey string
+
+// newUpdater creates the client for GitHub releases. Tests replace it to
+// serve releases from a local server.
+var newUpdater = func() *update.Updater {
+	updater := update.NewUpdater(nil)
+	// Without a valid key, Download refuses every release
+	if key, err := update.ParsePublicKey(updatePublicKey); err == nil {
+		updater.SetPublicKey(key)
+	}
+	return updater
+}
+
+// executablePath returns the path of the running binary; tests replace it
+var executablePath = func() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(path)
+}
+
+// updateCmd represents the update command
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update r53check to the latest release",
+	Long: `Check GitHub for the latest r53check release and replace the running binary
+with it. Before anything is replaced, the minisign signature of the SHA-256
+checksums published with the release is verified with the public key built
+into this binary, and the release archive for this platform is checked against
+the checksums. A release that is unsigned, signed with another key or does not
+match its checksums is refused; builds without a key, such as those from go
+install, cannot update themselves.
+
+Looking up the release is bounded by --timeout; downloading the archive by
+--download-timeout, 10 minutes by default, or not at all when it is 0.
+
+--check-only reports whether a newer release exists without installing it, and
+exits with code 6 when one does, for scripts.
+
+Binaries installed by a package manager should be updated with it instead, and
+binaries installed with go install with go install ...@latest.`,
+	Example: `  # Install the latest release
+  r53check update
+
+  # Only check whether an update is available
+  r53check update --check-only`,
+	Args: cobra.NoArgs,
+	RunE: runUpdateCommand,
+}
+
+func init() {
+	updateCmd.Flags().BoolVar(&updateCheckOnly, "check-only", false, "Report whether a newer release exists without installing it")
+	updateCmd.Flags().BoolVar(&updateForce, "force", false, "Install the latest release even if it is not newer, or this is a development build")
+	updateCmd.Flags().DurationVar(&updateDownloadTimeout, "download-timeout", 10*time.Minute, "Timeout for downloading the release archive, or 0 for none")
+
+	rootCmd.AddCommand(updateCmd)
+}
+
+// updateStatus is the JSON output of the update command
+type updateStatus struct {
+	Current   string `json:"current"`
+	Latest    string `json:"latest"`
+	Available bool   `json:"update_available"`
+	Updated   bool   `json:"updated"`
+	URL       string `json:"release_url,omitempty"`
+}
+
+func runUpdateCommand(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	updater := newUpdater()
+	release, err := updater.Latest(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := buildVersion()
+	status := updateStatus{
+		Current:   current,
+		Latest:    release.Tag,
+		Available: !update.IsRelease(current) || update.Compare(current, release.Tag) < 0,
+		URL:       release.URL,
+	}
+	if verbose {
+		fmt.Fprintf(stderr, "Running %s, latest release is %s\n", current, release.Tag)
+	}
+
+	switch {
+	case updateCheckOnly:
+		printUpdateStatus(status)
+		if status.Available {
+			return customErrors.NewExitError(customErrors.ExitPartialFailure, nil)
+		}
+		return nil
+	case !update.IsRelease(current) && !updateForce:
+		return customErrors.NewValidationError("", "force",
+			fmt.Sprintf("this is a development build (%s); use --force to replace it with %s", current, release.Tag), nil)
+	case !status.Available && !updateForce:
+		printUpdateStatus(status)
+		return nil
+	}
+
+	target, err := executablePath()
+	if err != nil {
+		return customErrors.NewSystemError("update", "unable to find the running binary", err)
+	}
+	if verbose {
+		fmt.Fprintf(stderr, "Downloading %s...\n", release.Tag)
+	}
+	downloadCtx, cancelDownload := context.Background(), context.CancelFunc(func() {})
+	if updateDownloadTimeout > 0 {
+		downloadCtx, cancelDownload = context.WithTimeout(downloadCtx, updateDownloadTimeout)
+	}
+	defer cancelDownload()
+	binary, err := updater.Download(downloadCtx, release)
+	if err != nil {
+		return err
+	}
+	if err := update.Replace(target, binary); err != nil {
+		return err
+	}
+
+	status.Updated = true
+	printUpdateStatus(status)
+	return nil
+}
+
+// printUpdateStatus reports the outcome of the update command
+func printUpdateStatus(status updateStatus) {
+	if outputFormat == output.FormatJSON {
+		data, _ := json.MarshalIndent(status, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+		return
+	}
+
+	switch {
+	case status.Updated:
+		fmt.Fprintf(stdout, "Updated r53check from %s to %s\n", status.Current, status.Latest)
+	case status.Available:
+		fmt.Fprintf(stdout, "r53check %s is available (running %s)\n", status.Latest, status.Current)
+		if status.URL != "" {
+			fmt.Fprintf(stdout, "Release notes: %s\n", status.URL)
+		}
+		fmt.Fprintln(stdout, `Run "r53check update" to install it`)
+	default:
+		fmt.Fprintf(stdout, "r53check %s is up to date\n", status.Current)
+	}
+}
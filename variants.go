@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+	"github.com/abakermi/r53check/internal/generate"
+	"github.com/abakermi/r53check/internal/input"
+
+	"github.com/spf13/cobra"
+)
+
+// variantKinds limits the kinds of variants checked
+var variantKinds []string
+
+// variantsCmd represents the variants command
+var variantsCmd = &cobra.Command{
+	Use:   "variants <domain>...",
+	Short: "Check typos and lookalikes of a domain for brand protection",
+	Long: `Generate lookalikes of each domain that a typo or a misreading could lead
+to, and check them like the bulk command does, so you can register them
+defensively before someone else does. Only the available lookalikes are listed,
+after the summary; --all also shows the ones already registered.
+
+The kinds of variants are:
+  omission       a character left out: exmple.com
+  repetition     a character typed twice: exaample.com
+  transposition  two characters swapped: exmaple.com
+  replacement    a neighbouring key typed instead: exsmple.com
+  homoglyph      a lookalike character: exampie.com, or a Cyrillic а
+  hyphenation    a hyphen added or removed: ex-ample.com
+  plural         a plural or singular form: examples.com
+  leet           letters written as digits: ex4mple.com, 3xampl3.com
+
+Each result is noted with its kind. Doubled letters are repetitions, and digits
+that pass for letters, such as 0 for o, are homoglyphs unless only leet variants
+are asked for with --kinds.`,
+	Example: `  # Find lookalikes of your domain that are still available
+  r53check variants example.com
+
+  # Show which lookalikes are registered, as JSON
+  r53check -o json variants example.com --all
+
+  # Only check homoglyphs and omissions
+  r53check variants example.com --kinds homoglyph,omission
+
+  # Find available leetspeak spellings of a name
+  r53check variants food.com --kinds leet,repetition`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runVariantsCommand,
+}
+
+func init() {
+	variantsCmd.Flags().StringSliceVar(&variantKinds, "kinds", nil, fmt.Sprintf("Kinds of variants to check: %s (default all)", strings.Join(generate.Kinds, ", ")))
+	variantsCmd.Flags().BoolVar(&generateAll, "all", false, "Print the result of every variant, not only the available ones")
+	variantsCmd.Flags().BoolVar(&generateList, "list", false, "Print the variants and their kinds, one per line, without checking them")
+	addMaxPriceFlag(variantsCmd)
+	variantsCmd.Flags().IntVar(&concurrency, "concurrency", 5, "Maximum number of concurrent requests")
+	addHistoryFlags(variantsCmd)
+
+	rootCmd.AddCommand(variantsCmd)
+}
+
+func runVariantsCommand(cmd *cobra.Command, args []string) error {
+	for _, kind := range variantKinds {
+		if !slices.Contains(generate.Kinds, kind) {
+			return customErrors.NewValidationError("", "kinds",
+				fmt.Sprintf("unknown kind of variant %q; use %s%s", kind, strings.Join(generate.Kinds, ", "),
+					customErrors.DidYouMean(kind, generate.Kinds)), nil)
+		}
+	}
+
+	var entries []input.Entry
+	seen := make(map[string]bool)
+	for _, base := range args {
+		for _, variant := range generate.Variants(base, variantKinds...) {
+			if seen[variant.Name] {
+				continue
+			}
+			seen[variant.Name] = true
+			entries = append(entries, input.Entry{Domain: variant.Name, Note: variant.Kind})
+		}
+	}
+	if len(entries) == 0 {
+		return customErrors.NewValidationError("", "domain", "no variants could be generated", nil)
+	}
+
+	if verbose {
+		fmt.Fprintf(stderr, "Generated %s of %s\n", pluralize(len(entries), "variant"), strings.Join(args, ", "))
+	}
+	return checkGeneratedNames(entries)
+}
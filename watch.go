@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	customErrors "github.com/abakermi/r53check/internal/errors"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Watch command flags
+	watchInterval time.Duration
+	watchJitter   time.Duration
+
+	// watchContext returns the context a watch runs in until it is
+	// interrupted; tests replace it to stop the watch
+	watchContext = func() (context.Context, context.CancelFunc) {
+		return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	}
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch [domains...]",
+	Short: "Check a list of domains at an interval and report status changes",
+	Long: `Watch a list of domains, checking all of them right away and then again
+every --interval until interrupted.
+
+Like the daemon, every run is recorded in the check history and each domain
+whose status changed since its previous check is reported, and sent to the
+--notify notifiers. Domains are given as arguments, with --file, or both; the
+files are read again before every run.
+
+A random delay of up to --jitter is added to each interval, so watches started
+together, or on many machines, do not all call AWS at the same moment. Use the
+daemon instead to check at fixed times of day.
+
+--register-on-available registers the domain, with the contact in the config
+file, the first time a run finds it available, then stops the watch. Since
+nobody confirms the purchase, the watch must be of a single domain, named again
+with --confirm-register, and the registration may not cost more than
+--register-max-price or go over the budget; a domain that costs more is
+refused before watching starts. A registration Route 53 refuses, such as when
+someone else registered the domain first, is reported and the watch goes on.`,
+	Example: `  # Check a shortlist every six hours
+  r53check watch --file shortlist.txt --interval 6h
+
+  # Watch two domains, emailing status changes
+  r53check watch example.com example.io --interval 1h --notify email
+
+  # Check every 30 to 40 minutes
+  r53check watch --file shortlist.txt --interval 30m --jitter 10m
+
+  # Register a dropping domain the moment it is available, for up to $20
+  r53check watch example.com --interval 5m --register-on-available \
+    --register-max-price 20 --confirm-register example.com`,
+	RunE: runWatchCommand,
+}
+
+func init() {
+	watchCmd.Flags().StringArrayVarP(&domainsFiles, "file", "f", nil, "Read domains from file (one domain per line, or a CSV or Excel file); repeatable and accepts glob patterns")
+	watchCmd.Flags().StringVar(&domainsColumn, "column", "", "Column of a CSV or Excel file holding the domains, by header name or number")
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 6*time.Hour, "Time between checks of the list")
+	watchCmd.Flags().DurationVar(&watchJitter, "jitter", 0, "Random delay of up to this long added to each interval (default a tenth of --interval)")
+	watchCmd.Flags().IntVar(&concurrency, "concurrency", 5, "Maximum number of concurrent requests")
+	addHistoryFlags(watchCmd)
+	addNotifyFlags(watchCmd)
+	addSinkFlags(watchCmd)
+	watchCmd.Flags().BoolVar(&registerOnAvailable, "register-on-available", false, "Register the watched domain as soon as it is available, then stop")
+	watchCmd.Flags().Float64Var(&registerMaxPrice, "register-max-price", 0, "The most --register-on-available may spend, in USD")
+	watchCmd.Flags().StringVar(&confirmRegister, "confirm-register", "", "The watched domain again, confirming --register-on-available")
+	addRegistrationFlags(watchCmd)
+
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatchCommand(cmd *cobra.Command, args []string) error {
+	if domainsColumn != "" && len(domainsFiles) == 0 {
+		return customErrors.NewValidationError("", "column", "--column picks a column of the --file file", nil)
+	}
+	if slices.Contains(args, "-") || slices.Contains(domainsFiles, "-") {
+		// The list is read again for every run, which stdin cannot be
+		return customErrors.NewValidationError("", "file", "watch cannot read domains from stdin; use a file", nil)
+	}
+	if watchInterval <= 0 {
+		return customErrors.NewValidationError("", "interval", "--interval must be positive", nil)
+	}
+	if watchJitter < 0 {
+		return customErrors.NewValidationError("", "jitter", "--jitter must not be negative", nil)
+	}
+	jitter := watchJitter
+	if !cmd.Flags().Changed("jitter") {
+		jitter = watchInterval / 10
+	}
+
+	if !registerOnAvailable && (registerMaxPrice != 0 || confirmRegister != "") {
+		return customErrors.NewValidationError("", "register-on-available", "--register-max-price and --confirm-register go with --register-on-available", nil)
+	}
+
+	if err := parseRunTags(); err != nil {
+		return err
+	}
+
+	// Fail on an empty list or unreadable file before the first run
+	list := watchList(args)
+	if _, err := list(); err != nil {
+		return err
+	}
+
+	ctx, stop := watchContext()
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var snipe *sniper
+	if registerOnAvailable {
+		var err error
+		if snipe, err = newSniper(ctx, cmd, list, cancel); err != nil {
+			return err
+		}
+	}
+
+	setup, err := newMonitorSetup(ctx)
+	if err != nil {
+		return err
+	}
+	defer setup.Close()
+	if snipe != nil {
+		setup.afterRun = snipe.afterRun
+	}
+
+	reportRunError(runMonitor(ctx, setup, list))
+
+	err = monitorLoop(ctx, setup, list, func(now time.Time) (time.Time, error) {
+		return now.Add(watchInterval + randomDelay(jitter)), nil
+	})
+	if snipe != nil && snipe.err != nil {
+		return snipe.err
+	}
+	return err
+}
+
+// watchList returns a function reading the watched domains: args followed
+// by the domains of the --file files, read again on every call
+func watchList(args []string) func() ([]string, error) {
+	return func() ([]string, error) {
+		domains := slices.Clone(args)
+		if len(domainsFiles) > 0 {
+			fileDomains, err := readDomainFiles(domainsFiles)
+			if err != nil {
+				return nil, customErrors.NewValidationError("", "file", "unable to read domains file", err)
+			}
+			domains = append(domains, fileDomains...)
+		}
+		if len(domains) == 0 {
+			return nil, customErrors.NewValidationError("", "domains", "no domains to watch; use arguments or --file", nil)
+		}
+		return normalizeDomains(domains), nil
+	}
+}
+
+// randomDelay returns a random duration in [0, limit), or 0 when limit is 0
+func randomDelay(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	return rand.N(limit)
+}